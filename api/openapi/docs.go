@@ -15,25 +15,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/healthz": {
+        "/admin/cluster": {
             "get": {
-                "description": "Health check",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns raft state of this node and known cluster members with their public addresses. Every member is a voter, learners are not supported.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "store"
+                    "admin"
                 ],
-                "summary": "Healthz",
+                "summary": "Cluster membership",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.clusterStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -41,30 +52,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/{key}": {
-            "get": {
-                "description": "Gets a value from the store",
+        "/admin/cluster/members/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Commits a new public address of the node through raft, e.g. after replacing a machine. Voter set is fixed by raft peers configuration, so id must be one of the configured peers. Adding a voter is not supported and rejected with 501.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "store"
+                    "admin"
                 ],
-                "summary": "Gets a value from the store",
+                "summary": "Set member address",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "raft node id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "member",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.memberReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "value",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cluster.Member"
+                            }
                         }
                     },
                     "307": {
@@ -73,50 +104,189 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "501": {
+                        "description": "Adding raft voters is not supported",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Puts a value into the store",
-                "consumes": [
-                    "text/plain"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Commits removal of the node's public address, so clients are no longer redirected to it. The node stays a raft voter until it's removed from raft peers configuration.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "store"
+                    "admin"
                 ],
-                "summary": "Puts a value into the store",
+                "summary": "Remove member address",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "raft node id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cluster.Member"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
+                    "404": {
+                        "description": "Member has no address",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "501": {
+                        "description": "Removing raft voters is not supported",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/namespaces": {
+            "get": {
+                "security": [
                     {
-                        "description": "value",
-                        "name": "value",
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns namespaces known to this node ordered by name, without the default one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List namespaces",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Namespace"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Commits creation of an empty namespace with its own limits. Zero limits fall back to the store config, larger ones are rejected. Keys of the namespace are served under /v1/ns/{namespace}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create namespace",
+                "parameters": [
+                    {
+                        "description": "namespace",
+                        "name": "namespace",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/store.Namespace"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created"
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/store.Namespace"
+                        }
                     },
                     "307": {
                         "description": "Node is not a leader",
@@ -130,25 +300,50 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Namespace already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/namespaces/{name}": {
             "delete": {
-                "description": "Deletes a value from the store",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Commits deletion of the namespace with all its keys.",
                 "tags": [
-                    "store"
+                    "admin"
                 ],
-                "summary": "Deletes a value from the store",
+                "summary": "Delete namespace",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "description": "namespace",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -163,14 +358,3072 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown namespace",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/snapshot": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns outcome of the last snapshot restore attempt of this node. Non-empty error means the node refused a corrupted snapshot and stopped applying commands.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Last snapshot restore",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fsm.RestoreStatus"
+                        }
+                    },
+                    "204": {
+                        "description": "No snapshot was restored yet"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Health check",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Healthz",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Node is draining before shutdown",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
+        },
+        "/leases": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Grants a lease expiring after ttl unless it's kept alive. The deadline is assigned by the leader",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Grants a lease",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lease TTL as a duration, e.g. 10s",
+                        "name": "ttl",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Lease ID",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/leases/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets TTL, deadline and attached keys of a live lease",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Gets a lease",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Lease ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fsm.Lease"
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Lease not found or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes a lease deleting keys attached to it",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Revokes a lease",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Lease ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of deleted keys",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Lease not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/leases/{id}/keepalive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the deadline of a live lease to now plus its TTL",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Keeps a lease alive",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Lease ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New deadline in unix milliseconds",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Lease not found or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Reports whether the node process is able to make progress. It doesn't depend on other nodes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Status"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Status"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports whether the node should receive client traffic: it knows a leader (the leader confirms its quorum), applies committed entries in time, isn't restoring a snapshot and isn't draining.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Status"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Status"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ns/{namespace}/{key}/lease": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leases and locks exist in the default namespace only, so attaching keys of other namespaces\nto leases and locking them is rejected",
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Rejects leases and locks of namespaced keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Leases and locks exist in the default namespace only",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ns/{namespace}/{key}/lock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leases and locks exist in the default namespace only, so attaching keys of other namespaces\nto leases and locking them is rejected",
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Rejects leases and locks of namespaced keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Leases and locks exist in the default namespace only",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leases and locks exist in the default namespace only, so attaching keys of other namespaces\nto leases and locking them is rejected",
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Rejects leases and locks of namespaced keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Leases and locks exist in the default namespace only",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets a value from the store. With path, returns the part of a JSON document at the path",
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Gets a value from the store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON path like $.a.b[0]",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "value",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Index applied by the node, only for reads with X-KV-Index or stale reads"
+                            },
+                            "X-KV-Staleness": {
+                                "type": "string",
+                                "description": "Time since the node applied its last entry, only for stale reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid X-KV-Index, X-KV-Consistency or path",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Key or path not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind or, with path, not a JSON document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Puts a value into the store. Values sent as application/json must be valid JSON",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Puts a value into the store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "value",
+                        "name": "value",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a value from the store",
+                "tags": [
+                    "store"
+                ],
+                "summary": "Deletes a value from the store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies a JSON Patch (RFC 6902) or a JSON Merge Patch (RFC 7386) to the JSON document at key\nas a whole or not at all. A missing key is patched as null. The document is stored with sorted members",
+                "consumes": [
+                    "application/json-patch+json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Patches a JSON document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patched document",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed patch or wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Value isn't a JSON document or the patch can't be applied to it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/fields": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets all fields of a hash as a JSON object",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hashes"
+                ],
+                "summary": "Gets all fields of a hash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/fields/{field}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets a field of a hash",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "hashes"
+                ],
+                "summary": "Gets a field of a hash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "value",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets a field of a hash, creating the hash if needed",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "hashes"
+                ],
+                "summary": "Sets a field of a hash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "value",
+                        "name": "value",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a field of a hash. Hash without fields is deleted",
+                "tags": [
+                    "hashes"
+                ],
+                "summary": "Deletes a field of a hash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/fields/{field}/incr": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a number to the integer value of a hash field, missing field counts as 0",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "hashes"
+                ],
+                "summary": "Increments a field of a hash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Increment, 1 by default",
+                        "name": "by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New value",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind or the field isn't an integer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/lease": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaches an existing key to a live lease, so it's deleted with the lease.\nWriting or deleting the key detaches it",
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Attaches a key to a lease",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lease ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Key or lease not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets values of a list between start and stop inclusive. Negative indexes count from the tail",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Gets values of a list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First index, 0 by default",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last index, -1 by default",
+                        "name": "stop",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Values, empty for a missing list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pushes a value to the tail (right, default) or the head (left) of a list, creating the list if needed",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Pushes a value to a list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "left",
+                            "right"
+                        ],
+                        "type": "string",
+                        "description": "End of the list",
+                        "name": "side",
+                        "in": "query"
+                    },
+                    {
+                        "description": "value",
+                        "name": "value",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New length of the list",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/list/len": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets length of a list, 0 for a missing list",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Gets length of a list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Length",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/list/pop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pops values from the head (left, default) or the tail (right) of a list. With wait\nan empty list is watched until a value is pushed or the wait is over. Wait is capped\nby the store max_pop_wait setting and only the leader waits",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Pops values from a list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "left",
+                            "right"
+                        ],
+                        "type": "string",
+                        "description": "End of the list",
+                        "name": "side",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of values, 1 by default",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How long to wait for a value, e.g. 5s",
+                        "name": "wait",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Popped values, empty if the list stayed empty",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/lock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Acquires the lock at key for a live lease. The lock is released when the lease is revoked or expires.\nReturns a fencing token, a unique id given to the acquiring write, which grows with every new holder.\nAcquiring a lock the lease already holds returns the same token",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Acquires a lock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lease ID",
+                        "name": "lease",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fencing token",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Lease not found or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Lock is held by another lease",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Releases the lock at key held by the lease",
+                "tags": [
+                    "leases"
+                ],
+                "summary": "Releases a lock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lease ID",
+                        "name": "lease",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Lock isn't held",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Lock is held by another lease",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/zset": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets members of a sorted set in ascending order of scores. By default members ranked between start\nand stop inclusive are returned, negative ranks count from the highest score. With min or max,\nup to limit members with scores in that range are returned instead. Infinite scores are written\nas \"inf\" and \"-inf\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sorted sets"
+                ],
+                "summary": "Gets members of a sorted set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First rank, 0 by default",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last rank, -1 by default",
+                        "name": "stop",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lowest score, -inf by default",
+                        "name": "min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highest score, inf by default",
+                        "name": "max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of members in a score range, unlimited by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members, empty for a missing sorted set",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/zsets.Member"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/zset/members/{member}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets score of a sorted set member. Infinite scores are written as \"inf\" and \"-inf\"",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "sorted sets"
+                ],
+                "summary": "Gets score of a sorted set member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "member",
+                        "name": "member",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index returned by a write. Any node answers once it has applied it",
+                        "name": "X-KV-Index",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "stale"
+                        ],
+                        "type": "string",
+                        "description": "Set to stale to read from any node without waiting",
+                        "name": "X-KV-Consistency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Score",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sorted set or member not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets score of a member, adding it and creating the sorted set if needed",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "sorted sets"
+                ],
+                "summary": "Sets score of a sorted set member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "member",
+                        "name": "member",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "score, e.g. 1.5 or -inf",
+                        "name": "score",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a member of a sorted set. Sorted set without members is deleted",
+                "tags": [
+                    "sorted sets"
+                ],
+                "summary": "Removes a sorted set member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "member",
+                        "name": "member",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/zset/members/{member}/incr": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a number to the score of a member, which starts at 0 for a new member",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "sorted sets"
+                ],
+                "summary": "Increments score of a sorted set member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "member",
+                        "name": "member",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Increment, 1 by default",
+                        "name": "by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New score",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind or the score would not be a number",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/{key}/zset/popmin": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes up to count members with the lowest scores from a sorted set and returns them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sorted sets"
+                ],
+                "summary": "Pops members with the lowest scores",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of members, 1 by default",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Popped members, empty for a missing sorted set",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/zsets.Member"
+                            }
+                        },
+                        "headers": {
+                            "X-KV-Index": {
+                                "type": "int",
+                                "description": "Log index of the write to use in subsequent reads"
+                            }
+                        }
+                    },
+                    "307": {
+                        "description": "Node is not a leader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong input data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key holds a value of another kind",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Node is overloaded or request timed out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "cluster.Member": {
+            "type": "object",
+            "properties": {
+                "http_addr": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fsm.Lease": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt is the deadline of the lease assigned by the leader with the last keepalive.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the log index of the command that granted the lease.",
+                    "type": "integer"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ttl_ms": {
+                    "description": "TTLMillis is the time in milliseconds every keepalive extends the lease by.",
+                    "type": "integer"
+                }
+            }
+        },
+        "fsm.RestoreStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "snapshot_index": {
+                    "type": "integer"
+                },
+                "snapshot_size": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Status": {
+            "type": "object",
+            "properties": {
+                "applied_index": {
+                    "type": "integer"
+                },
+                "apply_backlog": {
+                    "description": "ApplyBacklog is a number of committed entries delivered by raft and not applied yet.",
+                    "type": "integer"
+                },
+                "draining": {
+                    "type": "boolean"
+                },
+                "failures": {
+                    "description": "Failures lists reasons the node isn't ready.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "has_leader": {
+                    "type": "boolean"
+                },
+                "is_leader": {
+                    "type": "boolean"
+                },
+                "last_applied_at": {
+                    "description": "LastAppliedAt is time the node applied its last entry. raft-core doesn't report\ntime of the last contact with the leader, so it's the closest available signal.",
+                    "type": "string"
+                },
+                "leader_id": {
+                    "description": "LeaderID is id of the leader known to a follower, -1 otherwise.",
+                    "type": "integer"
+                },
+                "live": {
+                    "type": "boolean"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "restoring": {
+                    "type": "boolean"
+                },
+                "state_available": {
+                    "type": "boolean"
+                },
+                "term": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httphandlers.clusterStatus": {
+            "type": "object",
+            "properties": {
+                "is_leader": {
+                    "type": "boolean"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cluster.Member"
+                    }
+                },
+                "term": {
+                    "type": "integer"
+                },
+                "voters": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httphandlers.memberReq": {
+            "type": "object",
+            "properties": {
+                "http_addr": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.Namespace": {
+            "type": "object",
+            "properties": {
+                "max_key_size": {
+                    "type": "integer"
+                },
+                "max_keys": {
+                    "description": "MaxKeys limits number of keys stored in the namespace, zero means no limit.",
+                    "type": "integer"
+                },
+                "max_val_size": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "zsets.Member": {
+            "type": "object",
+            "properties": {
+                "member": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
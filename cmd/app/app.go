@@ -2,23 +2,55 @@ package main
 
 import (
 	"log/slog"
+	"slices"
 
 	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/cdc"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	"github.com/shrtyk/kv-store/internal/core/expiry"
+	"github.com/shrtyk/kv-store/internal/core/health"
+	"github.com/shrtyk/kv-store/internal/core/ports/audit"
+	cdcport "github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	clusterport "github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
 	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
 	"github.com/shrtyk/kv-store/internal/core/ports/metrics"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	internalRaft "github.com/shrtyk/kv-store/internal/core/raft"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	internalStore "github.com/shrtyk/kv-store/internal/core/store"
 	raftapi "github.com/shrtyk/raft-core/api"
 )
 
 type application struct {
 	cfg                 *cfg.AppConfig
 	store               store.Store
+	namespaces          store.Namespaces
 	logger              *slog.Logger
 	metrics             metrics.Metrics
 	raft                raftapi.Raft
 	fsm                 raftapi.FSM
 	futures             ftr.FuturesStore
+	proposer            proposer.Proposer
+	batcher             *internalRaft.Batcher
 	raftPublicHTTPAddrs []string
+	membership          clusterport.Membership
+	audit               audit.Logger
+	auth                *auth.Service
+	limiter             *ratelimit.Limiter
+	admission           *admission.Controller
+	applied             fsmport.AppliedIndex
+	pushes              fsmport.PushWatcher
+	leases              fsmport.LeaseReader
+	applyBacklog        func() int
+	health              *health.Checker
+	reaper              *expiry.Reaper
+	ticker              *expiry.Ticker
+	cdcSinks            []cdcport.Sink
+	cdcFeed             *cdc.Feed
 }
 
 type opt func(*application)
@@ -31,6 +63,37 @@ func (app *application) Init(opts ...opt) {
 	for _, op := range opts {
 		op(app)
 	}
+	// Without batching every write is submitted as a separate log entry
+	if app.proposer == nil {
+		app.proposer = internalRaft.NewProposer(app.raft, app.futures)
+	}
+	// Stores of namespaces live next to the default store unless provided
+	if app.namespaces == nil {
+		app.namespaces = internalStore.NewNamespaces(app.store, &app.cfg.Store, &app.cfg.ShardsCfg, app.logger)
+	}
+	if app.membership == nil {
+		app.membership = cluster.NewRegistry(len(app.raftPublicHTTPAddrs), app.raftPublicHTTPAddrs)
+	}
+	// Reads with consistency tokens are served only if the fsm tracks applied index
+	app.applied, _ = app.fsm.(fsmport.AppliedIndex)
+	// Blocking pops wait for pushes only if the fsm reports them, otherwise they don't block
+	app.pushes, _ = app.fsm.(fsmport.PushWatcher)
+	// Leases are served and reaped only if the fsm keeps them
+	app.leases, _ = app.fsm.(fsmport.LeaseReader)
+	fsmStatus, _ := app.fsm.(fsmport.StatusReporter)
+	app.health = health.NewChecker(&app.cfg.Health, app.raft, fsmStatus, app.applied, app.admission, app.applyBacklog)
+	// Ticks advance the cluster time only if the fsm keeps it
+	clock, _ := app.fsm.(fsmport.ClusterClock)
+	app.ticker = expiry.NewTicker(&app.cfg.Store.Expiry, clock, app.raft, app.proposer, app.logger)
+	app.reaper = expiry.NewReaper(&app.cfg.Store.Expiry, app.store, app.namespaces, app.leases, app.raft, app.proposer, app.logger)
+	// Changes are delivered to sinks only if the fsm keeps them
+	changes, _ := app.fsm.(fsmport.ChangeFeed)
+	sinks := app.cdcSinks
+	if app.audit != nil {
+		// Mutations are audited once committed, including ones committed without a client request
+		sinks = append(slices.Clip(sinks), cdc.NewAuditSink(app.audit))
+	}
+	app.cdcFeed = cdc.NewFeed(&app.cfg.CDC, changes, app.raft, app.proposer, sinks, app.logger)
 }
 
 func WithCfg(cfg *cfg.AppConfig) opt {
@@ -45,6 +108,12 @@ func WithStore(store store.Store) opt {
 	}
 }
 
+func WithNamespaces(namespaces store.Namespaces) opt {
+	return func(app *application) {
+		app.namespaces = namespaces
+	}
+}
+
 func WithLogger(l *slog.Logger) opt {
 	return func(app *application) {
 		app.logger = l
@@ -75,8 +144,64 @@ func WithFutures(f ftr.FuturesStore) opt {
 	}
 }
 
+func WithProposer(p proposer.Proposer) opt {
+	return func(app *application) {
+		app.proposer = p
+	}
+}
+
+// WithBatcher sets the batcher proposals are collected by. It's run by Serve until raft is stopped.
+func WithBatcher(b *internalRaft.Batcher) opt {
+	return func(app *application) {
+		app.batcher = b
+	}
+}
+
 func WithRaftPublicHTTPAddrs(addrs []string) opt {
 	return func(app *application) {
 		app.raftPublicHTTPAddrs = addrs
 	}
 }
+
+func WithMembership(m clusterport.Membership) opt {
+	return func(app *application) {
+		app.membership = m
+	}
+}
+
+func WithAudit(a audit.Logger) opt {
+	return func(app *application) {
+		app.audit = a
+	}
+}
+
+func WithCDCSinks(sinks []cdcport.Sink) opt {
+	return func(app *application) {
+		app.cdcSinks = sinks
+	}
+}
+
+func WithAuth(a *auth.Service) opt {
+	return func(app *application) {
+		app.auth = a
+	}
+}
+
+func WithRateLimiter(l *ratelimit.Limiter) opt {
+	return func(app *application) {
+		app.limiter = l
+	}
+}
+
+func WithAdmission(c *admission.Controller) opt {
+	return func(app *application) {
+		app.admission = c
+	}
+}
+
+// WithApplyBacklog sets a func reporting number of committed entries waiting to be applied.
+func WithApplyBacklog(backlog func() int) opt {
+	return func(app *application) {
+		app.applyBacklog = backlog
+	}
+}
@@ -2,13 +2,30 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os/signal"
 	"sync"
 	"syscall"
 
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
 	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	corecdc "github.com/shrtyk/kv-store/internal/core/cdc"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/audit"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	"github.com/shrtyk/kv-store/internal/core/ports/encryption"
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
 	internalRaft "github.com/shrtyk/kv-store/internal/core/raft"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
 	"github.com/shrtyk/kv-store/internal/core/store"
+	"github.com/shrtyk/kv-store/internal/infrastructure/auditlog"
+	"github.com/shrtyk/kv-store/internal/infrastructure/cdcsink"
+	"github.com/shrtyk/kv-store/internal/infrastructure/keyring"
 	pmts "github.com/shrtyk/kv-store/internal/infrastructure/prometheus"
 	log "github.com/shrtyk/kv-store/pkg/logger"
 	raftapi "github.com/shrtyk/raft-core/api"
@@ -19,6 +36,10 @@ import (
 // @title           KV-Store API
 // @version         1.0
 // @description     A simple key-value store.
+
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
 func main() {
 	var wg sync.WaitGroup
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -28,6 +49,7 @@ func main() {
 	slogger := log.NewLogger(cfg.Env)
 
 	st := store.NewStore(&wg, &cfg.Store, &cfg.ShardsCfg, slogger)
+	namespaces := store.NewNamespaces(st, &cfg.Store, &cfg.ShardsCfg, slogger)
 	m := pmts.NewPrometheusMetrics()
 
 	parsedPeers, err := cfg.Raft.ParsePeers()
@@ -54,9 +76,56 @@ func main() {
 		return
 	}
 
+	auditLog, closeAudit, err := newAuditLogger(&cfg.Audit, slogger)
+	if err != nil {
+		slogger.Error("failed to create audit logger", log.ErrorAttr(err))
+		return
+	}
+	defer func() {
+		if err := closeAudit(); err != nil {
+			slogger.Error("failed to close audit logger", log.ErrorAttr(err))
+		}
+	}()
+
+	cdcSinks, closeCDCSinks, err := newCDCSinks(&cfg.CDC)
+	if err != nil {
+		slogger.Error("failed to create cdc sinks", log.ErrorAttr(err))
+		return
+	}
+	defer func() {
+		if err := closeCDCSinks(); err != nil {
+			slogger.Error("failed to close cdc sinks", log.ErrorAttr(err))
+		}
+	}()
+
+	authSvc, err := auth.NewService(&cfg.Auth)
+	if err != nil {
+		slogger.Error("failed to create auth service", log.ErrorAttr(err))
+		return
+	}
+
+	compression, err := internalRaft.ParseCompression(cfg.Raft.SnapshotCompression)
+	if err != nil {
+		slogger.Error("invalid snapshot compression", log.ErrorAttr(err))
+		return
+	}
+
+	var cipher encryption.Cipher
+	if cfg.Encryption.Enabled {
+		kr, err := keyring.NewKeyring(&cfg.Encryption)
+		if err != nil {
+			slogger.Error("failed to load encryption keys", log.ErrorAttr(err))
+			return
+		}
+		slogger.Info("encryption at rest enabled", slog.String("active_key", kr.ActiveKey()))
+		cipher = kr
+	}
+
+	membership := cluster.NewRegistry(len(parsedPeers.Addrs), cfg.Raft.PublicHTTPAddrs)
+
 	applyCh := make(chan *raftapi.ApplyMessage, 128)
 	futures := internalRaft.NewApplyFuture()
-	fsm := internalRaft.NewFSM(slogger, st, futures, applyCh)
+	fsm := internalRaft.NewFSM(slogger, st, namespaces, futures, applyCh, compression, cipher, membership)
 
 	raftNode, err := raft.NewNodeBuilder(ctx, parsedPeers.Me, applyCh, fsm, raftTransport).
 		WithConfig(raftCfg).
@@ -66,17 +135,108 @@ func main() {
 		return
 	}
 
+	var prop proposer.Proposer = internalRaft.NewProposer(raftNode, futures)
+	var batcher *internalRaft.Batcher
+	if cfg.Batch.Enabled {
+		batcher = internalRaft.NewBatcher(&cfg.Batch, raftNode, futures, m, slogger)
+		prop = batcher
+	}
+	if cipher != nil {
+		prop = internalRaft.NewSealingProposer(prop, cipher)
+	}
+	// Commands carry the client they were proposed for, so committed changes are audited with it.
+	// It's recorded before sealing, as it holds caller ips.
+	prop = reqinfo.NewOriginProposer(prop)
+	// Commands carry the leader's time, so replicas advance their cluster time identically
+	prop = internalRaft.NewStampingProposer(prop)
+
+	applyBacklog := func() int { return len(applyCh) }
+	// Undelivered changes are kept until every cdc sink acknowledges them, so writes wait for lagging sinks
+	changes := fsm.(fsmport.ChangeFeed)
+	changesFull := func() bool {
+		return cfg.CDC.MaxBacklog > 0 && changes.Backlog() >= cfg.CDC.MaxBacklog
+	}
+
 	app := NewApp()
 	app.Init(
 		WithCfg(cfg),
 		WithStore(st),
+		WithNamespaces(namespaces),
 		WithLogger(slogger),
 		WithMetrics(m),
 		WithRaft(raftNode),
 		WithFSM(fsm),
 		WithFutures(futures),
+		WithProposer(prop),
+		WithBatcher(batcher),
 		WithRaftPublicHTTPAddrs(cfg.Raft.PublicHTTPAddrs),
+		WithMembership(membership),
+		WithAudit(auditLog),
+		WithCDCSinks(cdcSinks),
+		WithAuth(authSvc),
+		WithRateLimiter(ratelimit.NewLimiter(&cfg.RateLimit)),
+		WithAdmission(admission.NewController(&cfg.Store.Admission, futures, applyBacklog, changesFull)),
+		WithApplyBacklog(applyBacklog),
 	)
 
 	app.Serve(ctx, &wg)
 }
+
+// newAuditLogger creates the audit log. It's nil if auditing is disabled.
+func newAuditLogger(c *cfg.AuditCfg, l *slog.Logger) (audit.Logger, func() error, error) {
+	if !c.Enabled {
+		return nil, func() error { return nil }, nil
+	}
+	fl, err := auditlog.NewFileLogger(c, l)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fl, fl.Close, nil
+}
+
+// newCDCSinks creates configured sinks of change data capture. Names identify cursors of sinks, so they must be unique.
+func newCDCSinks(c *cfg.CDCCfg) ([]cdc.Sink, func() error, error) {
+	var sinks []cdc.Sink
+	closeAll := func() error {
+		var errs []error
+		for _, s := range sinks {
+			errs = append(errs, s.Close())
+		}
+		return errors.Join(errs...)
+	}
+	if !c.Enabled {
+		return nil, closeAll, nil
+	}
+
+	names := make(map[string]bool)
+	checkName := func(name string) error {
+		if name == "" {
+			return errors.New("cdc sink name is empty")
+		}
+		if name == corecdc.AuditSinkName {
+			return fmt.Errorf("cdc sink name %q is reserved for the audit log", name)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate cdc sink name %q", name)
+		}
+		names[name] = true
+		return nil
+	}
+	for i := range c.Files {
+		if err := checkName(c.Files[i].Name); err != nil {
+			return nil, nil, errors.Join(err, closeAll())
+		}
+		fs, err := cdcsink.NewFileSink(&c.Files[i])
+		if err != nil {
+			return nil, nil, errors.Join(err, closeAll())
+		}
+		sinks = append(sinks, fs)
+	}
+	for i := range c.Webhooks {
+		if err := checkName(c.Webhooks[i].Name); err != nil {
+			return nil, nil, errors.Join(err, closeAll())
+		}
+		sinks = append(sinks, cdcsink.NewWebhookSink(&c.Webhooks[i]))
+	}
+	return sinks, closeAll, nil
+}
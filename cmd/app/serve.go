@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -16,48 +18,129 @@ import (
 	"github.com/shrtyk/kv-store/internal/api/grpc"
 	appHttp "github.com/shrtyk/kv-store/internal/api/http"
 	mw "github.com/shrtyk/kv-store/internal/api/http/middleware"
+	"github.com/shrtyk/kv-store/internal/api/memcache"
+	"github.com/shrtyk/kv-store/internal/api/resp"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/infrastructure/tlsreload"
 	"github.com/shrtyk/kv-store/pkg/logger"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 func (app *application) Serve(ctx context.Context, wg *sync.WaitGroup) {
+	httpTLS, err := app.tlsConfig(ctx, wg, &app.cfg.HttpCfg.TLS)
+	if err != nil {
+		app.logger.Error("failed to setup http tls", logger.ErrorAttr(err))
+		return
+	}
+	grpcTLS, err := app.tlsConfig(ctx, wg, &app.cfg.GRPCCfg.TLS)
+	if err != nil {
+		app.logger.Error("failed to setup grpc tls", logger.ErrorAttr(err))
+		return
+	}
+
 	httpServ := http.Server{
 		Addr:         ":" + app.cfg.HttpCfg.Port,
 		Handler:      app.NewRouter(),
 		IdleTimeout:  app.cfg.HttpCfg.ServerIdleTimeout,
 		WriteTimeout: app.cfg.HttpCfg.ServerWriteTimeout,
 		ReadTimeout:  app.cfg.HttpCfg.ServerReadTimeout,
+		TLSConfig:    httpTLS,
 	}
 	grpcServ := grpc.NewGRPCServer(
 		wg,
 		&app.cfg.GRPCCfg,
 		&app.cfg.Store,
-		app.store,
+		app.namespaces,
 		app.metrics,
 		app.logger,
 		app.raft,
-		app.futures,
-		app.raftPublicHTTPAddrs,
+		app.proposer,
+		app.membership,
+		grpcTLS,
+		app.auth,
+		app.limiter,
+		app.admission,
+		app.applied,
+		app.pushes,
+		app.leases,
+		app.health,
 	)
+	var respServ *resp.Server
+	if app.cfg.RESP.Enabled {
+		respServ = resp.NewServer(
+			wg,
+			&app.cfg.RESP,
+			&app.cfg.Store,
+			app.store,
+			app.logger,
+			app.raft,
+			app.proposer,
+			app.membership,
+			app.auth,
+			app.limiter,
+			app.admission,
+		)
+	}
+	var memcachedServ *memcache.Server
+	if app.cfg.Memcached.Enabled {
+		memcachedServ = memcache.NewServer(
+			wg,
+			&app.cfg.Memcached,
+			&app.cfg.Store,
+			app.store,
+			app.logger,
+			app.raft,
+			app.proposer,
+			app.membership,
+			app.auth,
+			app.limiter,
+			app.admission,
+		)
+	}
+
+	// Committed writes keep being applied while the node drains, so the apply loop stops only after raft does.
+	applyCtx, stopApply := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopApply()
 
 	errCh := make(chan error, 1)
 	go func() {
 		<-ctx.Done()
 
+		app.logger.Info("got a signal to stop work. draining node")
+		app.drain()
+
 		tCtx, tCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer tCancel()
 
-		app.logger.Info("got a signal to stop work. executing graceful shutdown")
+		app.logger.Info("executing graceful shutdown")
 
-		errCh <- grpcServ.Shutdown(tCtx)
+		errCh <- errors.Join(respServ.Shutdown(tCtx), memcachedServ.Shutdown(tCtx), grpcServ.Shutdown(tCtx))
 		errCh <- httpServ.Shutdown(tCtx)
-		errCh <- app.raft.Stop()
+		if _, isLeader := app.raft.State(); isLeader {
+			// The raft library can't hand leadership over, followers elect a new leader after the election timeout.
+			app.logger.Warn("stopping the leader without leadership transfer, writes are unavailable until a new leader is elected")
+		}
+		stopErr := app.raft.Stop()
+		stopApply()
+		errCh <- stopErr
 		close(errCh)
 	}()
 
 	app.store.StartMapRebuilder(ctx, wg)
+	app.namespaces.StartMapRebuilder(ctx, wg)
+	app.futures.StartGC(ctx)
 	wg.Go(func() { app.readRaftErrors(ctx) })
-	wg.Go(func() { app.fsm.Start(ctx) })
+	wg.Go(func() { app.fsm.Start(applyCtx) })
+	if app.batcher != nil {
+		wg.Go(func() { app.batcher.Start(applyCtx) })
+	}
+	wg.Go(func() { app.limiter.Start(ctx) })
+	wg.Go(func() { grpcServ.WatchHealth(ctx, app.cfg.Health.GRPCInterval) })
+	wg.Go(func() { app.reaper.Start(ctx) })
+	wg.Go(func() { app.ticker.Start(ctx) })
+	wg.Go(func() { app.cdcFeed.Start(applyCtx) })
 
 	if err := app.raft.Start(); err != nil {
 		app.logger.Error("failed to start raft node", logger.ErrorAttr(err))
@@ -67,8 +150,26 @@ func (app *application) Serve(ctx context.Context, wg *sync.WaitGroup) {
 	app.logger.Info("grpc listening", slog.String("port", app.cfg.GRPCCfg.Port))
 	grpcServ.MustStart()
 
-	app.logger.Info("http listening", slog.String("port", app.cfg.HttpCfg.Port))
-	if err := httpServ.ListenAndServe(); err != http.ErrServerClosed {
+	if respServ != nil {
+		app.logger.Info("resp listening", slog.String("port", app.cfg.RESP.Port))
+		respServ.MustStart()
+	}
+
+	if memcachedServ != nil {
+		app.logger.Info("memcached listening", slog.String("port", app.cfg.Memcached.Port))
+		memcachedServ.MustStart()
+	}
+
+	app.logger.Info(
+		"http listening",
+		slog.String("port", app.cfg.HttpCfg.Port),
+		slog.Bool("tls", httpTLS != nil))
+	if httpTLS != nil {
+		err = httpServ.ListenAndServeTLS("", "")
+	} else {
+		err = httpServ.ListenAndServe()
+	}
+	if err != http.ErrServerClosed {
 		app.logger.Error("server failed to start", logger.ErrorAttr(err))
 		return
 	}
@@ -82,6 +183,34 @@ func (app *application) Serve(ctx context.Context, wg *sync.WaitGroup) {
 	app.logger.Info("application stopped")
 }
 
+// tlsConfig returns nil if tls is disabled. Otherwise it loads certificates
+// and keeps reloading them in background until ctx is done.
+func (app *application) tlsConfig(ctx context.Context, wg *sync.WaitGroup, c *cfg.TLSCfg) (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	r, err := tlsreload.NewReloader(c, app.logger)
+	if err != nil {
+		return nil, err
+	}
+	wg.Go(func() { r.Start(ctx) })
+	return r.TLSConfig(), nil
+}
+
+// drain stops accepting new requests and waits for in-flight writes to be applied,
+// so their results are delivered before raft is stopped.
+func (app *application) drain() {
+	app.admission.Drain()
+
+	ctx, cancel := context.WithTimeout(context.Background(), app.cfg.Store.Admission.DrainTimeout)
+	defer cancel()
+	if err := app.admission.WaitIdle(ctx); err != nil {
+		app.logger.Warn("node wasn't drained in time", logger.ErrorAttr(err))
+		return
+	}
+	app.logger.Info("node drained")
+}
+
 func (app *application) readRaftErrors(ctx context.Context) {
 	select {
 	case <-ctx.Done():
@@ -95,13 +224,47 @@ func (app *application) readRaftErrors(ctx context.Context) {
 func (app *application) NewRouter() *chi.Mux {
 	handlers := appHttp.NewHandlersProvider(
 		&app.cfg.Store,
-		app.store,
+		app.namespaces,
 		app.metrics,
 		app.raft,
-		app.futures,
-		app.raftPublicHTTPAddrs,
+		app.proposer,
+		app.membership,
+		app.admission,
+		app.applied,
+		app.pushes,
+		app.leases,
 	)
-	mws := mw.NewMiddlewares(app.logger, app.metrics)
+	mws := mw.NewMiddlewares(app.logger, app.metrics, app.namespaces)
+	authMws := mw.NewAuthMiddlewares(app.auth, app.limiter)
+	rlMws := mw.NewRateLimitMiddlewares(app.limiter)
+	fsmStatus, _ := app.fsm.(fsmport.StatusReporter)
+	healthHandlers := appHttp.NewHealthHandlers(app.health)
+	admin := appHttp.NewAdminHandlers(&app.cfg.Store, fsmStatus, app.membership, app.raft, app.proposer, app.namespaces)
+
+	keyRoutes := func(r chi.Router) {
+		r.With(authMws.Require(auth.Write)).Put("/{key}", handlers.PutHandler)
+		r.With(authMws.Require(auth.Read)).Get("/{key}", handlers.GetHandler)
+		r.With(authMws.Require(auth.Write)).Delete("/{key}", handlers.DeleteHandler)
+		r.With(authMws.Require(auth.Write)).Patch("/{key}", handlers.PatchHandler)
+
+		r.With(authMws.Require(auth.Read)).Get("/{key}/fields", handlers.HGetAllHandler)
+		r.With(authMws.Require(auth.Read)).Get("/{key}/fields/{field}", handlers.HGetHandler)
+		r.With(authMws.Require(auth.Write)).Put("/{key}/fields/{field}", handlers.HSetHandler)
+		r.With(authMws.Require(auth.Write)).Delete("/{key}/fields/{field}", handlers.HDelHandler)
+		r.With(authMws.Require(auth.Write)).Post("/{key}/fields/{field}/incr", handlers.HIncrByHandler)
+
+		r.With(authMws.Require(auth.Read)).Get("/{key}/list", handlers.LRangeHandler)
+		r.With(authMws.Require(auth.Read)).Get("/{key}/list/len", handlers.LLenHandler)
+		r.With(authMws.Require(auth.Write)).Post("/{key}/list", handlers.PushHandler)
+		r.With(authMws.Require(auth.Write)).Post("/{key}/list/pop", handlers.PopHandler)
+
+		r.With(authMws.Require(auth.Read)).Get("/{key}/zset", handlers.ZRangeHandler)
+		r.With(authMws.Require(auth.Write)).Post("/{key}/zset/popmin", handlers.ZPopMinHandler)
+		r.With(authMws.Require(auth.Read)).Get("/{key}/zset/members/{member}", handlers.ZScoreHandler)
+		r.With(authMws.Require(auth.Write)).Put("/{key}/zset/members/{member}", handlers.ZAddHandler)
+		r.With(authMws.Require(auth.Write)).Delete("/{key}/zset/members/{member}", handlers.ZRemHandler)
+		r.With(authMws.Require(auth.Write)).Post("/{key}/zset/members/{member}/incr", handlers.ZIncrByHandler)
+	}
 
 	mux := chi.NewMux()
 
@@ -112,12 +275,43 @@ func (app *application) NewRouter() *chi.Mux {
 	mux.Handle("/metrics", promhttp.Handler())
 	mux.Get("/swagger/*", httpSwagger.WrapHandler)
 	mux.Get("/healthz", handlers.Healthz)
+	mux.Get("/livez", healthHandlers.Livez)
+	mux.Get("/readyz", healthHandlers.Readyz)
 	mux.Route("/v1", func(r chi.Router) {
-		r.Use(chimw.Recoverer, mws.Logging, mws.HttpMetrics)
+		r.Use(chimw.Recoverer, mws.Logging, mws.HttpMetrics, authMws.Authenticate, rlMws.RateLimit)
+
+		keyRoutes(r)
+		// Keys of other namespaces are served with the same routes. Leases and locks exist in the default namespace only.
+		r.Route("/ns/{namespace}", func(r chi.Router) {
+			keyRoutes(r)
+			r.With(authMws.Require(auth.Write)).Put("/{key}/lease", handlers.NamespacedLeaseHandler)
+			r.With(authMws.Require(auth.Write)).Post("/{key}/lock", handlers.NamespacedLeaseHandler)
+			r.With(authMws.Require(auth.Write)).Delete("/{key}/lock", handlers.NamespacedLeaseHandler)
+		})
+
+		r.With(authMws.Require(auth.Write)).Put("/{key}/lease", handlers.LeaseAttachHandler)
+		r.With(authMws.Require(auth.Write)).Post("/{key}/lock", handlers.LockHandler)
+		r.With(authMws.Require(auth.Write)).Delete("/{key}/lock", handlers.UnlockHandler)
+	})
+	// Leases aren't bound to a key, so managing them requires access to the whole key space.
+	mux.Route("/leases", func(r chi.Router) {
+		r.Use(chimw.Recoverer, mws.Logging, mws.HttpMetrics, authMws.Authenticate, rlMws.RateLimit)
+
+		r.With(authMws.Require(auth.Write)).Post("/", handlers.LeaseGrantHandler)
+		r.With(authMws.Require(auth.Read)).Get("/{id}", handlers.LeaseHandler)
+		r.With(authMws.Require(auth.Write)).Post("/{id}/keepalive", handlers.LeaseKeepAliveHandler)
+		r.With(authMws.Require(auth.Write)).Delete("/{id}", handlers.LeaseRevokeHandler)
+	})
+	mux.Route("/admin", func(r chi.Router) {
+		r.Use(chimw.Recoverer, mws.Logging, authMws.Authenticate, authMws.Require(auth.Admin))
 
-		r.Put("/{key}", handlers.PutHandler)
-		r.Get("/{key}", handlers.GetHandler)
-		r.Delete("/{key}", handlers.DeleteHandler)
+		r.Get("/snapshot", admin.SnapshotStatus)
+		r.Get("/cluster", admin.ClusterStatus)
+		r.Put("/cluster/members/{id}", admin.SetMember)
+		r.Delete("/cluster/members/{id}", admin.RemoveMember)
+		r.Post("/namespaces", admin.CreateNamespace)
+		r.Get("/namespaces", admin.ListNamespaces)
+		r.Delete("/namespaces/{name}", admin.DeleteNamespace)
 	})
 
 	return mux
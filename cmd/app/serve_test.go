@@ -1,19 +1,30 @@
 package main
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os/signal"
 	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
 	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
-	"github.com/shrtyk/kv-store/internal/core/store"
+	internalRaft "github.com/shrtyk/kv-store/internal/core/raft"
 	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	"github.com/shrtyk/kv-store/internal/core/store"
 	pmts "github.com/shrtyk/kv-store/internal/infrastructure/prometheus"
 	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	raftapi "github.com/shrtyk/raft-core/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestServe(t *testing.T) {
@@ -46,3 +57,70 @@ func TestServe(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "404 page not found\n", string(b))
 }
+
+func TestServeAppliesPendingWritesOnSIGTERM(t *testing.T) {
+	var wg sync.WaitGroup
+	l, _ := tu.NewMockLogger()
+
+	appCfg := &cfg.AppConfig{
+		Store: cfg.StoreCfg{
+			MaxKeySize: 100,
+			MaxValSize: 100,
+			Admission:  cfg.AdmissionCfg{DrainTimeout: 10 * time.Second},
+		},
+		HttpCfg:   cfg.HttpCfg{Port: "16711"},
+		GRPCCfg:   cfg.GRPCCfg{Port: "16712"},
+		ShardsCfg: cfg.ShardsCfg{CheckFreq: time.Minute, SparseRatio: 0.1, MinOpsUntilRebuild: 1000, MinDeletes: 500},
+	}
+	st := store.NewStore(&wg, &appCfg.Store, &appCfg.ShardsCfg, l)
+	applyCh := make(chan *raftapi.ApplyMessage)
+	futures := internalRaft.NewApplyFuture()
+	fsm := internalRaft.NewFSM(l, st, nil, futures, applyCh, internalRaft.CompressionNone, nil, nil)
+
+	app := NewApp()
+	app.Init(
+		WithCfg(appCfg),
+		WithLogger(l),
+		WithMetrics(pmts.NewMockMetrics()),
+		WithStore(st),
+		WithRaft(rmocks.NewStubRaft(st, true, 0)),
+		WithFSM(fsm),
+		WithFutures(futures),
+		WithRateLimiter(ratelimit.NewLimiter(&appCfg.RateLimit)),
+		WithAdmission(admission.NewController(&appCfg.Store.Admission, futures, nil, nil)),
+	)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		app.Serve(ctx, &wg)
+		close(stopped)
+	}()
+
+	// A write committed by raft but not applied yet when the signal arrives
+	future := futures.NewFuture(1)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+	<-ctx.Done()
+
+	cmd, err := proto.Marshal(&fsm_v1.Command{Command: &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: "k", Value: "v"}}})
+	require.NoError(t, err)
+	select {
+	case applyCh <- &raftapi.ApplyMessage{CommandValid: true, CommandIndex: 1, Command: cmd}:
+	case <-time.After(time.Second):
+		t.Fatal("apply loop stopped before the node was drained")
+	}
+
+	wCtx, wCancel := context.WithTimeout(context.Background(), time.Second)
+	defer wCancel()
+	assert.NoError(t, future.Wait(wCtx))
+	v, err := st.Get("k")
+	assert.NoError(t, err)
+	assert.Equal(t, "v", v)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("node didn't stop once drained")
+	}
+}
@@ -0,0 +1,85 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+)
+
+const (
+	// Header carries log index of a write in responses. Reads carrying it are
+	// answered by any node once it has applied at least that index.
+	Header = "X-KV-Index"
+	// MetadataKey is gRPC counterpart of Header used in trailers and request metadata.
+	MetadataKey = "x-kv-index"
+
+	// ModeHeader selects consistency of a read. Reads in ModeStale are answered by
+	// any node from its local state without waiting for any index.
+	ModeHeader      = "X-KV-Consistency"
+	ModeMetadataKey = "x-kv-consistency"
+	ModeStale       = "stale"
+
+	// StalenessHeader reports time since the node applied its last entry in responses to stale reads.
+	// Returned data misses at most writes committed during that period.
+	StalenessHeader      = "X-KV-Staleness"
+	StalenessMetadataKey = "x-kv-staleness"
+)
+
+var (
+	ErrInvalidIndex = errors.New("consistency: invalid index token")
+	ErrNotApplied   = errors.New("consistency: requested index is not applied on this node yet")
+	ErrInvalidMode  = errors.New("consistency: invalid read consistency mode")
+)
+
+func ParseIndex(token string) (int64, error) {
+	idx, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidIndex, token)
+	}
+	return idx, nil
+}
+
+func FormatIndex(idx int64) string {
+	return strconv.FormatInt(idx, 10)
+}
+
+// IsStale reports whether mode requests a stale read. Empty mode is the default linearizable read.
+func IsStale(mode string) (bool, error) {
+	switch mode {
+	case "":
+		return false, nil
+	case ModeStale:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: %q", ErrInvalidMode, mode)
+	}
+}
+
+// Staleness returns formatted time since the node applied its last entry.
+// It's empty if the node hasn't applied anything since start.
+func Staleness(applied fsm.AppliedIndex) string {
+	at := applied.AppliedAt()
+	if at.IsZero() {
+		return ""
+	}
+	return time.Since(at).Round(time.Millisecond).String()
+}
+
+// Wait blocks until the node has applied idx. Zero timeout means waiting until ctx is done.
+func Wait(ctx context.Context, applied fsm.AppliedIndex, idx int64, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := applied.WaitApplied(ctx, idx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: requested %d, applied %d", ErrNotApplied, idx, applied.AppliedIndex())
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package consistency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndex(t *testing.T) {
+	idx, err := ParseIndex("42")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), idx)
+	assert.Equal(t, "42", FormatIndex(idx))
+
+	for _, token := range []string{"", "abc", "-1", "1.5"} {
+		_, err := ParseIndex(token)
+		assert.ErrorIs(t, err, ErrInvalidIndex, token)
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	stale, err := IsStale("")
+	require.NoError(t, err)
+	assert.False(t, stale)
+
+	stale, err = IsStale(ModeStale)
+	require.NoError(t, err)
+	assert.True(t, stale)
+
+	_, err = IsStale("strong")
+	assert.ErrorIs(t, err, ErrInvalidMode)
+}
+
+func TestStaleness(t *testing.T) {
+	applied := fsmmocks.NewMockAppliedIndex(t)
+	applied.On("AppliedAt").Return(time.Time{}).Once()
+	assert.Empty(t, Staleness(applied))
+
+	applied.On("AppliedAt").Return(time.Now().Add(-2 * time.Second)).Once()
+	d, err := time.ParseDuration(Staleness(applied))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, d, 2*time.Second)
+}
+
+func TestWait(t *testing.T) {
+	t.Run("applied", func(t *testing.T) {
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		applied.On("WaitApplied", mock.Anything, int64(5)).Return(nil).Once()
+
+		assert.NoError(t, Wait(context.Background(), applied, 5, time.Second))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		applied.EXPECT().WaitApplied(mock.Anything, int64(5)).RunAndReturn(func(ctx context.Context, _ int64) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Once()
+		applied.On("AppliedIndex").Return(int64(3)).Once()
+
+		err := Wait(context.Background(), applied, 5, time.Millisecond)
+		assert.ErrorIs(t, err, ErrNotApplied)
+	})
+}
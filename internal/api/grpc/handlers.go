@@ -5,10 +5,18 @@ import (
 	"errors"
 	"time"
 
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
 	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
@@ -17,13 +25,40 @@ func (s *Server) Get(ctx context.Context, in *pb.GetReq) (*pb.GetResp, error) {
 	start := time.Now()
 	key := in.GetKey()
 
-	resp, err := s.raft.ReadOnly(ctx, []byte(key))
+	if err := s.admission.AdmitRead(); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	ctx, cancel := withTimeout(ctx, s.stCfg.ReadTimeout)
+	defer cancel()
+
+	if s.applied != nil {
+		stale, err := consistency.IsStale(metadataValue(ctx, consistency.ModeMetadataKey))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if stale {
+			if staleness := consistency.Staleness(s.applied); staleness != "" {
+				_ = grpc.SetTrailer(ctx, metadata.Pairs(consistency.StalenessMetadataKey, staleness))
+			}
+			return s.getLocal(ctx, in.GetNamespace(), key, start)
+		}
+		if token := metadataValue(ctx, consistency.MetadataKey); token != "" {
+			return s.getAtIndex(ctx, in.GetNamespace(), key, token, start)
+		}
+	}
+
+	resp, err := s.raft.ReadOnly(ctx, fsm.ReadQuery(in.GetNamespace(), key))
 	if err != nil {
 		switch {
-		case errors.Is(err, store.ErrNoSuchKey):
+		case errors.Is(err, store.ErrNoSuchKey), errors.Is(err, store.ErrNoSuchNamespace):
 			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, store.ErrWrongType):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		case errors.Is(err, context.DeadlineExceeded):
 			return nil, status.Error(codes.DeadlineExceeded, "request timed out: raft cluster is busy")
+		case errors.Is(err, fsm.ErrStateUnavailable):
+			return nil, status.Error(codes.Unavailable, err.Error())
 		default:
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -42,6 +77,44 @@ func (s *Server) Get(ctx context.Context, in *pb.GetReq) (*pb.GetResp, error) {
 	}, nil
 }
 
+// getAtIndex answers from the local store once the node has applied the index from the token.
+// Unlike ReadOnly it doesn't require leadership, so followers can serve read-your-writes reads.
+func (s *Server) getAtIndex(ctx context.Context, namespace, key, token string, start time.Time) (*pb.GetResp, error) {
+	idx, err := consistency.ParseIndex(token)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := consistency.Wait(ctx, s.applied, idx, s.stCfg.ReadIndexTimeout); err != nil {
+		switch {
+		case errors.Is(err, consistency.ErrNotApplied), errors.Is(err, fsm.ErrStateUnavailable):
+			return nil, status.Error(codes.Unavailable, err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.Error(codes.DeadlineExceeded, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return s.getLocal(ctx, namespace, key, start)
+}
+
+// getLocal answers from the local store and reports the applied index the answer reflects.
+func (s *Server) getLocal(ctx context.Context, namespace, key string, start time.Time) (*pb.GetResp, error) {
+	setIndexTrailer(ctx, s.applied.AppliedIndex())
+	st, err := s.namespaceStore(namespace)
+	if err != nil {
+		return nil, err
+	}
+	val, err := st.Get(key)
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	s.metrics.GrpcGet(key, time.Since(start).Seconds())
+	return &pb.GetResp{Entry: &pb.Entry{Key: key, Value: val}}, nil
+}
+
 func (s *Server) Put(ctx context.Context, in *pb.PutReq) (*pb.PutResp, error) {
 	start := time.Now()
 	if len(in.GetKey()) > s.stCfg.MaxKeySize {
@@ -52,6 +125,7 @@ func (s *Server) Put(ctx context.Context, in *pb.PutReq) (*pb.PutResp, error) {
 	}
 
 	cmd := &fsm_v1.Command{
+		Namespace: in.GetNamespace(),
 		Command: &fsm_v1.Command_Put{
 			Put: &fsm_v1.PutCommand{
 				Key:   in.GetKey(),
@@ -64,16 +138,31 @@ func (s *Server) Put(ctx context.Context, in *pb.PutReq) (*pb.PutResp, error) {
 		return nil, status.Error(codes.Internal, "failed to marshal command")
 	}
 
-	resp := s.raft.Submit(data)
+	done, err := s.admission.AdmitWrite()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(ctx, s.stCfg.WriteTimeout)
+	defer cancel()
+
+	resp, err := s.proposer.Propose(ctx, data)
+	if err != nil {
+		return nil, applyError(err)
+	}
 	if !resp.IsLeader {
 		return nil, s.redirect(resp.LeaderID)
 	}
 
-	promise := s.futures.NewFuture(resp.LogIndex)
-	if err := promise.Wait(ctx); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	err = resp.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		return nil, applyError(err)
 	}
 
+	setIndexTrailer(ctx, resp.LogIndex)
 	s.metrics.GrpcPut(in.GetKey(), time.Since(start).Seconds())
 	return &pb.PutResp{}, nil
 }
@@ -82,6 +171,7 @@ func (s *Server) Delete(ctx context.Context, in *pb.DeleteReq) (*pb.DeleteResp,
 	start := time.Now()
 
 	cmd := &fsm_v1.Command{
+		Namespace: in.GetNamespace(),
 		Command: &fsm_v1.Command_Delete{
 			Delete: &fsm_v1.DeleteCommand{
 				Key: in.GetKey(),
@@ -93,24 +183,201 @@ func (s *Server) Delete(ctx context.Context, in *pb.DeleteReq) (*pb.DeleteResp,
 		return nil, status.Error(codes.Internal, "failed to marshal command")
 	}
 
-	resp := s.raft.Submit(data)
+	done, err := s.admission.AdmitWrite()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(ctx, s.stCfg.WriteTimeout)
+	defer cancel()
+
+	resp, err := s.proposer.Propose(ctx, data)
+	if err != nil {
+		return nil, applyError(err)
+	}
 	if !resp.IsLeader {
 		return nil, s.redirect(resp.LeaderID)
 	}
 
-	promise := s.futures.NewFuture(resp.LogIndex)
-	if err := promise.Wait(ctx); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	err = resp.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		return nil, applyError(err)
 	}
 
+	setIndexTrailer(ctx, resp.LogIndex)
 	s.metrics.GrpcDelete(in.GetKey(), time.Since(start).Seconds())
 	return &pb.DeleteResp{}, nil
 }
 
 func (s *Server) redirect(liderID int) error {
-	if liderID >= 0 && liderID < len(s.raftPublicHTTPAddrs) {
-		leaderAddr := s.raftPublicHTTPAddrs[liderID]
+	if leaderAddr, ok := s.membership.HTTPAddr(liderID); ok {
 		return status.Errorf(codes.Unavailable, "not a leader, leader is at %s", leaderAddr)
 	}
 	return status.Error(codes.Unavailable, "no leader available")
 }
+
+// applyError maps error of waiting for a command to be applied into grpc status.
+func applyError(err error) error {
+	switch {
+	case errors.Is(err, ftr.ErrPromiseTimeout):
+		return status.Error(codes.DeadlineExceeded, "request timed out: raft cluster is busy")
+	case errors.Is(err, fsm.ErrStateUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, store.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, store.ErrKeyTooLarge), errors.Is(err, store.ErrValueTooLarge):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, store.ErrWrongType), errors.Is(err, store.ErrNotInteger), errors.Is(err, store.ErrNotANumber):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, jsondoc.ErrNotJSON), errors.Is(err, jsondoc.ErrPatchFailed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, jsondoc.ErrInvalidPatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, fsm.ErrLeaseNotFound), errors.Is(err, store.ErrNoSuchKey), errors.Is(err, store.ErrNoSuchNamespace):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, fsm.ErrLockHeld):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, fsm.ErrNamespacedLease), errors.Is(err, store.ErrInvalidNamespace):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, store.ErrNamespaceExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// readError maps error of reading the local store into grpc status.
+func readError(err error) error {
+	switch {
+	case errors.Is(err, store.ErrNoSuchKey), errors.Is(err, store.ErrNoSuchNamespace), errors.Is(err, jsondoc.ErrPathNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, store.ErrWrongType), errors.Is(err, jsondoc.ErrNotJSON):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, fsm.ErrStateUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// metadataValue returns the first value of the key from request metadata if any.
+func metadataValue(ctx context.Context, key string) string {
+	if vals := metadata.ValueFromIncomingContext(ctx, key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+func setIndexTrailer(ctx context.Context, idx int64) {
+	// Fails only outside of a grpc call, e.g. when handlers are called directly.
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(consistency.MetadataKey, consistency.FormatIndex(idx)))
+}
+
+// withTimeout limits ctx with timeout. Zero timeout means no limit.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// propose submits the command and waits until it's applied.
+// The log index of the command is sent in the trailer.
+func (s *Server) propose(ctx context.Context, cmd *fsm_v1.Command) (*proposer.Proposal, error) {
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to marshal command")
+	}
+
+	done, err := s.admission.AdmitWrite()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(ctx, s.stCfg.WriteTimeout)
+	defer cancel()
+
+	res, err := s.proposer.Propose(ctx, data)
+	if err != nil {
+		return nil, applyError(err)
+	}
+	if !res.IsLeader {
+		return nil, s.redirect(res.LeaderID)
+	}
+	err = res.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		return nil, applyError(err)
+	}
+
+	setIndexTrailer(ctx, res.LogIndex)
+	return res, nil
+}
+
+// namespaceStore returns the store of the namespace. Unknown namespace is reported with NOT_FOUND.
+func (s *Server) namespaceStore(namespace string) (store.Store, error) {
+	st, err := s.namespaces.Store(namespace)
+	if err != nil {
+		return nil, readError(err)
+	}
+	return st, nil
+}
+
+// readBarrier waits until the local store can answer the request with the consistency it asks for.
+func (s *Server) readBarrier(ctx context.Context) error {
+	if err := s.admission.AdmitRead(); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	ctx, cancel := withTimeout(ctx, s.stCfg.ReadTimeout)
+	defer cancel()
+
+	if s.applied != nil {
+		stale, err := consistency.IsStale(metadataValue(ctx, consistency.ModeMetadataKey))
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if stale {
+			if staleness := consistency.Staleness(s.applied); staleness != "" {
+				_ = grpc.SetTrailer(ctx, metadata.Pairs(consistency.StalenessMetadataKey, staleness))
+			}
+			setIndexTrailer(ctx, s.applied.AppliedIndex())
+			return nil
+		}
+		if token := metadataValue(ctx, consistency.MetadataKey); token != "" {
+			idx, err := consistency.ParseIndex(token)
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			if err := consistency.Wait(ctx, s.applied, idx, s.stCfg.ReadIndexTimeout); err != nil {
+				switch {
+				case errors.Is(err, consistency.ErrNotApplied), errors.Is(err, fsm.ErrStateUnavailable):
+					return status.Error(codes.Unavailable, err.Error())
+				case errors.Is(err, context.DeadlineExceeded):
+					return status.Error(codes.DeadlineExceeded, err.Error())
+				default:
+					return status.Error(codes.Internal, err.Error())
+				}
+			}
+			setIndexTrailer(ctx, s.applied.AppliedIndex())
+			return nil
+		}
+	}
+
+	resp, err := s.raft.ReadOnly(ctx, nil)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return status.Error(codes.DeadlineExceeded, "request timed out: raft cluster is busy")
+		}
+		return readError(err)
+	}
+	if !resp.IsLeader {
+		return s.redirect(resp.LeaderId)
+	}
+	return nil
+}
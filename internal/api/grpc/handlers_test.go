@@ -4,18 +4,31 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/shrtyk/kv-store/internal/api/consistency"
 	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	"github.com/shrtyk/kv-store/internal/core/health"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
 	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
 	metricsmocks "github.com/shrtyk/kv-store/internal/core/ports/metrics/mocks"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	storemocks "github.com/shrtyk/kv-store/internal/core/ports/store/mocks"
+	internalRaft "github.com/shrtyk/kv-store/internal/core/raft"
 	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	corestore "github.com/shrtyk/kv-store/internal/core/store"
 	"github.com/shrtyk/kv-store/pkg/logger"
 	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -44,12 +57,20 @@ func setup(t *testing.T) serverSetup {
 		&wg,
 		grpcCfg,
 		stCfg,
-		mockStore,
+		corestore.NewNamespaces(mockStore, stCfg, &cfg.ShardsCfg{}, slogger),
 		mockMetrics,
 		slogger,
 		stubRaft,
-		mockFutures,
-		addrs,
+		internalRaft.NewProposer(stubRaft, mockFutures),
+		cluster.NewRegistry(len(addrs), addrs),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	return serverSetup{server, mockStore, stubRaft, mockFutures, mockFuture, mockMetrics}
@@ -118,6 +139,56 @@ func TestGRPCServer_Put(t *testing.T) {
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, st.Code())
 	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		s := setup(t)
+		key, value := "key", "value"
+
+		s.mockStore.On("Put", key, value).Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrQuotaExceeded).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.Put(context.Background(), &pb.PutReq{Key: key, Value: value})
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("overloaded", func(t *testing.T) {
+		s := setup(t)
+		s.server.admission = admission.NewController(&cfg.AdmissionCfg{MaxInFlight: 1}, nil, nil, nil)
+		_, err := s.server.admission.AdmitWrite()
+		require.NoError(t, err)
+
+		_, err = s.server.Put(context.Background(), &pb.PutReq{Key: "key", Value: "value"})
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("state unavailable", func(t *testing.T) {
+		s := setup(t)
+		key, value := "key", "value"
+
+		s.mockStore.On("Put", key, value).Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(fsm.ErrStateUnavailable).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.Put(context.Background(), &pb.PutReq{Key: key, Value: value})
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("promise timeout", func(t *testing.T) {
+		s := setup(t)
+		key, value := "key", "value"
+
+		s.mockStore.On("Put", key, value).Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(ftr.ErrPromiseTimeout).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.Put(context.Background(), &pb.PutReq{Key: key, Value: value})
+
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
 }
 
 func TestGRPCServer_Get(t *testing.T) {
@@ -165,6 +236,89 @@ func TestGRPCServer_Get(t *testing.T) {
 	})
 }
 
+func TestGRPCServer_GetAtIndex(t *testing.T) {
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(consistency.MetadataKey, token))
+	}
+
+	t.Run("follower answers once index is applied", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		s.server.applied = applied
+		key, value := "key", "value"
+
+		applied.On("WaitApplied", mock.Anything, int64(7)).Return(nil).Once()
+		applied.On("AppliedIndex").Return(int64(7)).Once()
+		s.mockStore.On("Get", key).Return(value, nil).Once()
+		s.mockMetrics.On("GrpcGet", key, mock.Anything).Return().Once()
+
+		resp, err := s.server.Get(withToken("7"), &pb.GetReq{Key: key})
+
+		require.NoError(t, err)
+		assert.Equal(t, value, resp.Entry.Value)
+	})
+
+	t.Run("index not applied in time", func(t *testing.T) {
+		s := setup(t)
+		s.server.stCfg.ReadIndexTimeout = time.Millisecond
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		s.server.applied = applied
+
+		applied.EXPECT().WaitApplied(mock.Anything, int64(7)).RunAndReturn(func(ctx context.Context, _ int64) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Once()
+		applied.On("AppliedIndex").Return(int64(3)).Once()
+
+		_, err := s.server.Get(withToken("7"), &pb.GetReq{Key: "key"})
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		s := setup(t)
+		s.server.applied = fsmmocks.NewMockAppliedIndex(t)
+
+		_, err := s.server.Get(withToken("latest"), &pb.GetReq{Key: "key"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGRPCServer_GetStale(t *testing.T) {
+	withMode := func(mode string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(consistency.ModeMetadataKey, mode))
+	}
+
+	t.Run("follower answers from local state", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		s.server.applied = applied
+		key, value := "key", "value"
+
+		applied.On("AppliedAt").Return(time.Now()).Once()
+		applied.On("AppliedIndex").Return(int64(4)).Once()
+		s.mockStore.On("Get", key).Return(value, nil).Once()
+		s.mockMetrics.On("GrpcGet", key, mock.Anything).Return().Once()
+
+		resp, err := s.server.Get(withMode(consistency.ModeStale), &pb.GetReq{Key: key})
+
+		require.NoError(t, err)
+		assert.Equal(t, value, resp.Entry.Value)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		s := setup(t)
+		s.server.applied = fsmmocks.NewMockAppliedIndex(t)
+
+		_, err := s.server.Get(withMode("eventual"), &pb.GetReq{Key: "key"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
 func TestGRPCServer_Delete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		s := setup(t)
@@ -194,3 +348,30 @@ func TestGRPCServer_Delete(t *testing.T) {
 		assert.Equal(t, codes.Unavailable, st.Code())
 	})
 }
+
+func TestGRPCServer_Health(t *testing.T) {
+	stubRaft := rmocks.NewStubRaft(nil, false, -1)
+	stubRaft.SetReadOnlyResult([]byte{}, nil)
+	checker := health.NewChecker(&cfg.HealthCfg{}, stubRaft, nil, nil, nil, nil)
+	server := NewGRPCServer(
+		&sync.WaitGroup{}, &cfg.GRPCCfg{}, &cfg.StoreCfg{}, nil, nil, nil, stubRaft,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, checker,
+	)
+	check := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := server.health.Check(context.Background(), &healthpb.HealthCheckRequest{
+			Service: pb.KVStore_ServiceDesc.ServiceName,
+		})
+		require.NoError(t, err)
+		return resp.Status
+	}
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check())
+
+	stubRaft.SetLeader(true)
+	server.updateHealth(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check())
+
+	stubRaft.SetLeader(false)
+	server.updateHealth(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check())
+}
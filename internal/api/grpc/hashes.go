@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) HSet(ctx context.Context, in *pb.HSetReq) (*pb.HSetResp, error) {
+	if len(in.GetFields()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no fields to set")
+	}
+	if err := s.checkFields(in.GetKey(), in.GetFields()); err != nil {
+		return nil, err
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Hset{Hset: &fsm_v1.HSetCommand{
+		Key:    in.GetKey(),
+		Fields: in.GetFields(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	added, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.HSetResp{Added: added}, nil
+}
+
+func (s *Server) HGet(ctx context.Context, in *pb.HGetReq) (*pb.HGetResp, error) {
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	val, err := st.HGet(in.GetKey(), in.GetField())
+	if err != nil {
+		return nil, readError(err)
+	}
+	return &pb.HGetResp{Value: val}, nil
+}
+
+func (s *Server) HDel(ctx context.Context, in *pb.HDelReq) (*pb.HDelResp, error) {
+	if len(in.GetFields()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no fields to delete")
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Hdel{Hdel: &fsm_v1.HDelCommand{
+		Key:    in.GetKey(),
+		Fields: in.GetFields(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.HDelResp{Deleted: deleted}, nil
+}
+
+func (s *Server) HGetAll(ctx context.Context, in *pb.HGetAllReq) (*pb.HGetAllResp, error) {
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	fields, err := st.HGetAll(in.GetKey())
+	if err != nil {
+		return nil, readError(err)
+	}
+	return &pb.HGetAllResp{Fields: fields}, nil
+}
+
+func (s *Server) HIncrBy(ctx context.Context, in *pb.HIncrByReq) (*pb.HIncrByResp, error) {
+	if err := s.checkFields(in.GetKey(), map[string]string{in.GetField(): ""}); err != nil {
+		return nil, err
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Hincrby{Hincrby: &fsm_v1.HIncrByCommand{
+		Key:   in.GetKey(),
+		Field: in.GetField(),
+		Delta: in.GetDelta(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	data := res.Future.Data()
+	val, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.HIncrByResp{Value: val}, nil
+}
+
+// checkFields checks sizes of the key, field names and values.
+func (s *Server) checkFields(key string, fields map[string]string) error {
+	if len(key) > s.stCfg.MaxKeySize {
+		return status.Error(codes.InvalidArgument, store.ErrKeyTooLarge.Error())
+	}
+	for f, v := range fields {
+		if len(f) > s.stCfg.MaxKeySize {
+			return status.Error(codes.InvalidArgument, store.ErrKeyTooLarge.Error())
+		}
+		if len(v) > s.stCfg.MaxValSize {
+			return status.Error(codes.InvalidArgument, store.ErrValueTooLarge.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer_Hashes(t *testing.T) {
+	t.Run("hset", func(t *testing.T) {
+		s := setup(t)
+		fields := map[string]string{"a": "1", "b": "2"}
+
+		s.mockStore.On("HSet", "h", fields, mock.Anything).Return(2, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("2")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.HSet(context.Background(), &pb.HSetReq{Key: "h", Fields: fields})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.GetAdded())
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("hset wrong type", func(t *testing.T) {
+		s := setup(t)
+		fields := map[string]string{"a": "1"}
+
+		s.mockStore.On("HSet", "s", fields, mock.Anything).Return(0, store.ErrWrongType).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrWrongType).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.HSet(context.Background(), &pb.HSetReq{Key: "s", Fields: fields})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("hget", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("HGet", "h", "a").Return("1", nil).Once()
+		s.mockStore.On("HGet", "h", "b").Return("", store.ErrNoSuchKey).Once()
+
+		resp, err := s.server.HGet(context.Background(), &pb.HGetReq{Key: "h", Field: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, "1", resp.GetValue())
+
+		_, err = s.server.HGet(context.Background(), &pb.HGetReq{Key: "h", Field: "b"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("hgetall not leader", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+
+		_, err := s.server.HGetAll(context.Background(), &pb.HGetAllReq{Key: "h"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Contains(t, err.Error(), "http://leader:8080")
+	})
+
+	t.Run("hincrby", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("HIncrBy", "h", "n", int64(5), mock.Anything).Return(int64(5), nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("5")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.HIncrBy(context.Background(), &pb.HIncrByReq{Key: "h", Field: "n", Delta: 5})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), resp.GetValue())
+	})
+
+	t.Run("hdel without fields", func(t *testing.T) {
+		s := setup(t)
+
+		_, err := s.server.HDel(context.Background(), &pb.HDelReq{Key: "h"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGRPCServer_Namespaces(t *testing.T) {
+	t.Run("reads keys of the namespace", func(t *testing.T) {
+		s := setup(t)
+		require.NoError(t, s.server.namespaces.Create(store.Namespace{Name: "team-a"}))
+		st, err := s.server.namespaces.Store("team-a")
+		require.NoError(t, err)
+		_, err = st.HSet("h", map[string]string{"a": "1"}, 0)
+		require.NoError(t, err)
+
+		resp, err := s.server.HGet(context.Background(), &pb.HGetReq{Namespace: "team-a", Key: "h", Field: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, "1", resp.GetValue())
+	})
+
+	t.Run("unknown namespace", func(t *testing.T) {
+		s := setup(t)
+
+		_, err := s.server.HGetAll(context.Background(), &pb.HGetAllReq{Namespace: "team-a", Key: "h"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// observe reports status and latency of KVStore calls labeled with the namespace of the request.
+func (s *Server) observe(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+	if !ok || service != pb.KVStore_ServiceDesc.ServiceName {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	var namespace string
+	if nr, ok := req.(namespacedRequest); ok {
+		namespace = store.MetricsNamespace(s.namespaces, nr.GetNamespace())
+	}
+	s.metrics.GrpcRequest(status.Code(err), service, method, namespace, time.Since(start).Seconds())
+	return resp, err
+}
+
+// requestInfo attaches request id and client ip to the request context.
+func (s *Server) requestInfo(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ri := &reqinfo.Info{RequestID: uuid.NewString()}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ri.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ri.IP); err == nil {
+			ri.IP = host
+		}
+	}
+	return handler(reqinfo.ToCtx(ctx, ri), req)
+}
+
+// keyedRequest is implemented by every request addressing a single key.
+type keyedRequest interface {
+	GetKey() string
+}
+
+// namespacedRequest is implemented by every request addressing keys of a namespace.
+type namespacedRequest interface {
+	GetNamespace() string
+}
+
+// methodAccess maps KVStore methods to the access level they require.
+// Methods outside of KVStore service (e.g. reflection) are not authorized.
+var methodAccess = map[string]auth.Access{
+	pb.KVStore_Get_FullMethodName:           auth.Read,
+	pb.KVStore_Put_FullMethodName:           auth.Write,
+	pb.KVStore_Delete_FullMethodName:        auth.Write,
+	pb.KVStore_HSet_FullMethodName:          auth.Write,
+	pb.KVStore_HGet_FullMethodName:          auth.Read,
+	pb.KVStore_HDel_FullMethodName:          auth.Write,
+	pb.KVStore_HGetAll_FullMethodName:       auth.Read,
+	pb.KVStore_HIncrBy_FullMethodName:       auth.Write,
+	pb.KVStore_LPush_FullMethodName:         auth.Write,
+	pb.KVStore_RPush_FullMethodName:         auth.Write,
+	pb.KVStore_LPop_FullMethodName:          auth.Write,
+	pb.KVStore_RPop_FullMethodName:          auth.Write,
+	pb.KVStore_BLPop_FullMethodName:         auth.Write,
+	pb.KVStore_LRange_FullMethodName:        auth.Read,
+	pb.KVStore_LLen_FullMethodName:          auth.Read,
+	pb.KVStore_ZAdd_FullMethodName:          auth.Write,
+	pb.KVStore_ZRem_FullMethodName:          auth.Write,
+	pb.KVStore_ZScore_FullMethodName:        auth.Read,
+	pb.KVStore_ZRange_FullMethodName:        auth.Read,
+	pb.KVStore_ZRangeByScore_FullMethodName: auth.Read,
+	pb.KVStore_ZIncrBy_FullMethodName:       auth.Write,
+	pb.KVStore_ZPopMin_FullMethodName:       auth.Write,
+	pb.KVStore_JsonPatch_FullMethodName:     auth.Write,
+	pb.KVStore_JsonGet_FullMethodName:       auth.Read,
+	// Leases aren't bound to a key, so managing them requires access to the whole key space.
+	pb.KVStore_LeaseGrant_FullMethodName:      auth.Write,
+	pb.KVStore_LeaseKeepAlive_FullMethodName:  auth.Write,
+	pb.KVStore_LeaseRevoke_FullMethodName:     auth.Write,
+	pb.KVStore_LeaseTimeToLive_FullMethodName: auth.Read,
+	pb.KVStore_LeaseAttach_FullMethodName:     auth.Write,
+	pb.KVStore_Lock_FullMethodName:            auth.Write,
+	pb.KVStore_Unlock_FullMethodName:          auth.Write,
+}
+
+// authorize authenticates bearer credential from "authorization" metadata
+// and checks the principal has required access to the requested key of the requested namespace.
+func (s *Server) authorize(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if !s.auth.Enabled() || !strings.HasPrefix(info.FullMethod, "/"+pb.KVStore_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	var credential string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("authorization"); len(vals) > 0 {
+			credential = auth.BearerToken(vals[0])
+		}
+	}
+	p, err := s.auth.Authenticate(credential)
+	if err != nil {
+		// Unauthenticated clients are limited by ip as readers, so credentials can't be guessed at full speed.
+		if err := s.limit(ctx, ratelimit.OpRead); err != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unauthenticated, auth.ErrUnauthenticated.Error())
+	}
+	reqinfo.FromCtx(ctx).Identity = p.Subject
+
+	access, ok := methodAccess[info.FullMethod]
+	if !ok {
+		access = auth.Admin
+	}
+	var namespace, key string
+	if nr, ok := req.(namespacedRequest); ok {
+		namespace = nr.GetNamespace()
+	}
+	if kr, ok := req.(keyedRequest); ok {
+		key = kr.GetKey()
+	}
+	if err := s.auth.Authorize(p, access, namespace, key); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return handler(auth.ToCtx(ctx, p), req)
+}
+
+// rateLimit rejects the request with ResourceExhausted if the client exceeded its rate.
+// Suggested backoff in seconds is sent in "retry-after" header.
+func (s *Server) rateLimit(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if !s.limiter.Enabled() || !strings.HasPrefix(info.FullMethod, "/"+pb.KVStore_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	op := ratelimit.OpWrite
+	if methodAccess[info.FullMethod] == auth.Read {
+		op = ratelimit.OpRead
+	}
+	if err := s.limit(ctx, op); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// limit takes a token of the operation from the caller's bucket and returns ResourceExhausted if it's empty.
+func (s *Server) limit(ctx context.Context, op ratelimit.Op) error {
+	if ok, retryAfter := s.limiter.Allow(reqinfo.FromCtx(ctx).Caller(), op); !ok {
+		secs := strconv.Itoa(ratelimit.RetryAfterSeconds(retryAfter))
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", secs))
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ss", secs)
+	}
+	return nil
+}
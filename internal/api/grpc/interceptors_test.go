@@ -0,0 +1,202 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestInfoInterceptor(t *testing.T) {
+	s := setup(t)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 5555},
+	})
+
+	var got *reqinfo.Info
+	_, err := s.server.requestInfo(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		got = reqinfo.FromCtx(ctx)
+		return nil, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "10.1.2.3", got.IP)
+	assert.NotEmpty(t, got.RequestID)
+}
+
+func TestObserveInterceptor(t *testing.T) {
+	s := setup(t)
+	require.NoError(t, s.server.namespaces.Create(store.Namespace{Name: "team-a"}))
+
+	tests := []struct {
+		name      string
+		namespace string
+		wantLabel string
+	}{
+		{name: "default namespace", namespace: "", wantLabel: ""},
+		{name: "existing namespace", namespace: "team-a", wantLabel: "team-a"},
+		{name: "unknown namespace", namespace: "team-b", wantLabel: store.UnknownNamespace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.mockMetrics.On("GrpcRequest", codes.NotFound, pb.KVStore_ServiceDesc.ServiceName, "Get",
+				tt.wantLabel, mock.Anything).Return().Once()
+
+			_, err := s.server.observe(context.Background(), &pb.GetReq{Namespace: tt.namespace, Key: "k"},
+				&grpc.UnaryServerInfo{FullMethod: pb.KVStore_Get_FullMethodName},
+				func(ctx context.Context, req any) (any, error) {
+					return nil, status.Error(codes.NotFound, "not found")
+				})
+
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			s.mockMetrics.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthorizeInterceptor(t *testing.T) {
+	svc, err := auth.NewService(&cfg.AuthCfg{
+		Enabled: true,
+		Tokens: []cfg.APITokenCfg{
+			{Token: "jobs-token", Subject: "batch-job", Roles: []string{"jobs-writer"}},
+		},
+		Roles: []cfg.RoleCfg{
+			{Name: "jobs-writer", Rules: []cfg.RoleRuleCfg{
+				{Prefix: "jobs/", Access: "write"},
+				{Namespace: "team-a", Prefix: "jobs/", Access: "write"},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		req      any
+		wantCode codes.Code
+	}{
+		{
+			name:     "missing credentials",
+			method:   pb.KVStore_Put_FullMethodName,
+			req:      &pb.PutReq{Key: "jobs/1"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "forbidden prefix",
+			token:    "jobs-token",
+			method:   pb.KVStore_Delete_FullMethodName,
+			req:      &pb.DeleteReq{Key: "users/1"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "allowed",
+			token:    "jobs-token",
+			method:   pb.KVStore_Get_FullMethodName,
+			req:      &pb.GetReq{Key: "jobs/1"},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "allowed namespace",
+			token:    "jobs-token",
+			method:   pb.KVStore_Put_FullMethodName,
+			req:      &pb.PutReq{Namespace: "team-a", Key: "jobs/1"},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "forbidden namespace",
+			token:    "jobs-token",
+			method:   pb.KVStore_Put_FullMethodName,
+			req:      &pb.PutReq{Namespace: "team-b", Key: "jobs/1"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "non kv-store service",
+			method:   "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := setup(t)
+			s.server.auth = svc
+
+			ctx := reqinfo.ToCtx(context.Background(), &reqinfo.Info{})
+			if tt.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tt.token))
+			}
+
+			var called bool
+			_, err := s.server.authorize(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					called = true
+					if tt.token != "" {
+						assert.Equal(t, "batch-job", auth.FromCtx(ctx).Subject)
+						assert.Equal(t, "batch-job", reqinfo.FromCtx(ctx).Identity)
+					}
+					return nil, nil
+				})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, called)
+		})
+	}
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	s := setup(t)
+	s.server.limiter = ratelimit.NewLimiter(&cfg.RateLimitCfg{
+		Enabled: true,
+		Write:   cfg.LimitCfg{Rate: 1, Burst: 1},
+	})
+
+	ctx := reqinfo.ToCtx(context.Background(), &reqinfo.Info{IP: "10.0.0.1"})
+	call := func(method string) error {
+		_, err := s.server.rateLimit(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return err
+	}
+
+	assert.NoError(t, call(pb.KVStore_Put_FullMethodName))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call(pb.KVStore_Delete_FullMethodName)))
+	assert.NoError(t, call(pb.KVStore_Get_FullMethodName))
+}
+
+func TestAuthorizeInterceptor_LimitsUnauthenticated(t *testing.T) {
+	svc, err := auth.NewService(&cfg.AuthCfg{Enabled: true})
+	require.NoError(t, err)
+	s := setup(t)
+	s.server.auth = svc
+	s.server.limiter = ratelimit.NewLimiter(&cfg.RateLimitCfg{
+		Enabled: true,
+		Read:    cfg.LimitCfg{Rate: 0.1, Burst: 1},
+	})
+
+	call := func(ip string) error {
+		ctx := reqinfo.ToCtx(context.Background(), &reqinfo.Info{IP: ip})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer guess"))
+		_, err := s.server.authorize(ctx, &pb.GetReq{Key: "k"}, &grpc.UnaryServerInfo{FullMethod: pb.KVStore_Get_FullMethodName},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return err
+	}
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("10.0.0.1")))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call("10.0.0.1")), "guesses are limited by ip")
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("10.0.0.2")))
+}
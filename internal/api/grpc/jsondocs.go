@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) JsonPatch(ctx context.Context, in *pb.JsonPatchReq) (*pb.JsonPatchResp, error) {
+	if len(in.GetKey()) > s.stCfg.MaxKeySize {
+		return nil, status.Error(codes.InvalidArgument, store.ErrKeyTooLarge.Error())
+	}
+	if len(in.GetPatch()) > s.stCfg.MaxValSize {
+		return nil, status.Error(codes.InvalidArgument, store.ErrValueTooLarge.Error())
+	}
+	typ := jsondoc.PatchType(in.GetType())
+	if err := jsondoc.CheckPatch(in.GetPatch(), typ); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_JsonPatch{JsonPatch: &fsm_v1.JsonPatchCommand{
+		Key:   in.GetKey(),
+		Patch: in.GetPatch(),
+		Type:  fsm_v1.PatchType(typ),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.JsonPatchResp{Document: res.Future.Data()}, nil
+}
+
+func (s *Server) JsonGet(ctx context.Context, in *pb.JsonGetReq) (*pb.JsonGetResp, error) {
+	path := in.GetPath()
+	if path == "" {
+		path = "$"
+	}
+	if err := jsondoc.CheckPath(path); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	val, err := st.Get(in.GetKey())
+	if err != nil {
+		return nil, readError(err)
+	}
+	doc, err := jsondoc.Get([]byte(val), path)
+	if err != nil {
+		return nil, readError(err)
+	}
+	return &pb.JsonGetResp{Document: doc}, nil
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer_JSONDocuments(t *testing.T) {
+	t.Run("merge patch", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("Lookup", "doc", mock.Anything).Return(store.Entry{}, store.ErrNoSuchKey).Once()
+		s.mockStore.On("PutEntry", store.Entry{Key: "doc", Value: `{"a":1}`}).Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte(`{"a":1}`)).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.JsonPatch(context.Background(), &pb.JsonPatchReq{
+			Key:   "doc",
+			Patch: []byte(`{"a":1}`),
+			Type:  pb.PatchType_PATCH_TYPE_MERGE_PATCH,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(resp.GetDocument()))
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("patch fails", func(t *testing.T) {
+		s := setup(t)
+		s.server.stCfg.MaxValSize = 100
+
+		s.mockStore.On("Lookup", "doc", mock.Anything).Return(store.Entry{Key: "doc", Value: `1`}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(jsondoc.ErrPatchFailed).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.JsonPatch(context.Background(), &pb.JsonPatchReq{
+			Key:   "doc",
+			Patch: []byte(`[{"op":"remove","path":"/a"}]`),
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("malformed patch", func(t *testing.T) {
+		s := setup(t)
+
+		_, err := s.server.JsonPatch(context.Background(), &pb.JsonPatchReq{Key: "doc", Patch: []byte(`{}`)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Get", "doc").Return(`{"a":[1,{"b":true}]}`, nil).Twice()
+
+		resp, err := s.server.JsonGet(context.Background(), &pb.JsonGetReq{Key: "doc", Path: "$.a[1].b"})
+		require.NoError(t, err)
+		assert.Equal(t, `true`, string(resp.GetDocument()))
+
+		_, err = s.server.JsonGet(context.Background(), &pb.JsonGetReq{Key: "doc", Path: "$.b"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		_, err = s.server.JsonGet(context.Background(), &pb.JsonGetReq{Key: "doc", Path: "b"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
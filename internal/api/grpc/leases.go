@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) LeaseGrant(ctx context.Context, in *pb.LeaseGrantReq) (*pb.LeaseGrantResp, error) {
+	if in.GetTtlMs() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "lease ttl must be positive")
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseGrant{LeaseGrant: &fsm_v1.LeaseGrantCommand{
+		TtlMs: in.GetTtlMs(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.LeaseGrantResp{Id: id}, nil
+}
+
+func (s *Server) LeaseKeepAlive(ctx context.Context, in *pb.LeaseKeepAliveReq) (*pb.LeaseKeepAliveResp, error) {
+	res, err := s.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseKeepAlive{LeaseKeepAlive: &fsm_v1.LeaseKeepAliveCommand{
+		Id: in.GetId(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.LeaseKeepAliveResp{ExpiresAt: expiresAt}, nil
+}
+
+func (s *Server) LeaseRevoke(ctx context.Context, in *pb.LeaseRevokeReq) (*pb.LeaseRevokeResp, error) {
+	res, err := s.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseRevoke{LeaseRevoke: &fsm_v1.LeaseRevokeCommand{
+		Id: in.GetId(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.LeaseRevokeResp{Deleted: deleted}, nil
+}
+
+func (s *Server) LeaseTimeToLive(ctx context.Context, in *pb.LeaseTimeToLiveReq) (*pb.LeaseTimeToLiveResp, error) {
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+	if s.leases == nil {
+		return nil, status.Error(codes.NotFound, fsm.ErrLeaseNotFound.Error())
+	}
+
+	lease, ok := s.leases.Lease(in.GetId(), time.Now().UnixMilli())
+	if !ok {
+		return nil, status.Error(codes.NotFound, fsm.ErrLeaseNotFound.Error())
+	}
+	return &pb.LeaseTimeToLiveResp{
+		TtlMs:     lease.TTLMillis,
+		ExpiresAt: lease.ExpiresAt.UnixMilli(),
+		Keys:      lease.Keys,
+	}, nil
+}
+
+func (s *Server) LeaseAttach(ctx context.Context, in *pb.LeaseAttachReq) (*pb.LeaseAttachResp, error) {
+	_, err := s.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseAttach{LeaseAttach: &fsm_v1.LeaseAttachCommand{
+		Id:   in.GetId(),
+		Keys: []string{in.GetKey()},
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LeaseAttachResp{}, nil
+}
+
+func (s *Server) Lock(ctx context.Context, in *pb.LockReq) (*pb.LockResp, error) {
+	if len(in.GetKey()) > s.stCfg.MaxKeySize {
+		return nil, status.Error(codes.InvalidArgument, store.ErrKeyTooLarge.Error())
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_Lock{Lock: &fsm_v1.LockCommand{
+		Key:   in.GetKey(),
+		Lease: in.GetLease(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	token, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.LockResp{Token: token}, nil
+}
+
+func (s *Server) Unlock(ctx context.Context, in *pb.UnlockReq) (*pb.UnlockResp, error) {
+	_, err := s.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_Unlock{Unlock: &fsm_v1.UnlockCommand{
+		Key:   in.GetKey(),
+		Lease: in.GetLease(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UnlockResp{}, nil
+}
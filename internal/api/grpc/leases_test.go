@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer_Leases(t *testing.T) {
+	t.Run("grant", func(t *testing.T) {
+		s := setup(t)
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("5")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.LeaseGrant(context.Background(), &pb.LeaseGrantReq{TtlMs: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), resp.GetId())
+
+		_, err = s.server.LeaseGrant(context.Background(), &pb.LeaseGrantReq{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("keepalive of unknown lease", func(t *testing.T) {
+		s := setup(t)
+		s.mockFuture.On("Wait", mock.Anything).Return(fsm.ErrLeaseNotFound).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.LeaseKeepAlive(context.Background(), &pb.LeaseKeepAliveReq{Id: 5})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("time to live", func(t *testing.T) {
+		s := setup(t)
+		leases := fsmmocks.NewMockLeaseReader(t)
+		s.server.leases = leases
+		leases.EXPECT().Lease(int64(5), mock.Anything).Return(fsm.Lease{
+			ID:        5,
+			TTLMillis: 1000,
+			ExpiresAt: time.UnixMilli(3000),
+			Keys:      []string{"a"},
+		}, true).Once()
+		leases.EXPECT().Lease(int64(6), mock.Anything).Return(fsm.Lease{}, false).Once()
+
+		resp, err := s.server.LeaseTimeToLive(context.Background(), &pb.LeaseTimeToLiveReq{Id: 5})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), resp.GetTtlMs())
+		assert.Equal(t, int64(3000), resp.GetExpiresAt())
+		assert.Equal(t, []string{"a"}, resp.GetKeys())
+
+		_, err = s.server.LeaseTimeToLive(context.Background(), &pb.LeaseTimeToLiveReq{Id: 6})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestGRPCServer_Locks(t *testing.T) {
+	t.Run("lock", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Put", "lock", "1").Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("1")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.Lock(context.Background(), &pb.LockReq{Key: "lock", Lease: 5})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), resp.GetToken())
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("held by another lease", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Put", "lock", "1").Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(fsm.ErrLockHeld).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.Lock(context.Background(), &pb.LockReq{Key: "lock", Lease: 5})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Delete", "lock").Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.Unlock(context.Background(), &pb.UnlockReq{Key: "lock", Lease: 5})
+		require.NoError(t, err)
+	})
+}
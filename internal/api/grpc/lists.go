@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/api/lists"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) LPush(ctx context.Context, in *pb.PushReq) (*pb.PushResp, error) {
+	return s.push(ctx, in, true)
+}
+
+func (s *Server) RPush(ctx context.Context, in *pb.PushReq) (*pb.PushResp, error) {
+	return s.push(ctx, in, false)
+}
+
+func (s *Server) LPop(ctx context.Context, in *pb.PopReq) (*pb.PopResp, error) {
+	return s.pop(ctx, in.GetNamespace(), in.GetKey(), in.GetCount(), true, 0)
+}
+
+func (s *Server) RPop(ctx context.Context, in *pb.PopReq) (*pb.PopResp, error) {
+	return s.pop(ctx, in.GetNamespace(), in.GetKey(), in.GetCount(), false, 0)
+}
+
+// BLPop pops values from the head of a list. An empty list is watched until a value is pushed
+// or the timeout is over, then an empty response is returned.
+func (s *Server) BLPop(ctx context.Context, in *pb.BLPopReq) (*pb.PopResp, error) {
+	if in.GetTimeoutMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative timeout")
+	}
+	wait := min(time.Duration(in.GetTimeoutMs())*time.Millisecond, s.stCfg.MaxPopWait)
+	return s.pop(ctx, in.GetNamespace(), in.GetKey(), in.GetCount(), true, wait)
+}
+
+func (s *Server) LRange(ctx context.Context, in *pb.LRangeReq) (*pb.LRangeResp, error) {
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	vals, err := st.LRange(in.GetKey(), int(in.GetStart()), int(in.GetStop()))
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return nil, readError(err)
+	}
+	return &pb.LRangeResp{Values: vals}, nil
+}
+
+func (s *Server) LLen(ctx context.Context, in *pb.LLenReq) (*pb.LLenResp, error) {
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	n, err := st.LLen(in.GetKey())
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return nil, readError(err)
+	}
+	return &pb.LLenResp{Length: int64(n)}, nil
+}
+
+func (s *Server) push(ctx context.Context, in *pb.PushReq, left bool) (*pb.PushResp, error) {
+	if len(in.GetValues()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no values to push")
+	}
+	if len(in.GetKey()) > s.stCfg.MaxKeySize {
+		return nil, status.Error(codes.InvalidArgument, store.ErrKeyTooLarge.Error())
+	}
+	for _, v := range in.GetValues() {
+		if len(v) > s.stCfg.MaxValSize {
+			return nil, status.Error(codes.InvalidArgument, store.ErrValueTooLarge.Error())
+		}
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Push{Push: &fsm_v1.PushCommand{
+		Key:    in.GetKey(),
+		Values: in.GetValues(),
+		Left:   left,
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	n, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.PushResp{Length: n}, nil
+}
+
+func (s *Server) pop(ctx context.Context, namespace, key string, count int64, left bool, wait time.Duration) (*pb.PopResp, error) {
+	if count < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative count")
+	}
+	count = max(count, 1)
+	st, err := s.namespaceStore(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	vals, err := lists.BlockingPop(ctx, s.pushes, key, wait,
+		func() bool {
+			n, err := st.LLen(key)
+			return n > 0 || (err != nil && !errors.Is(err, store.ErrNoSuchKey))
+		},
+		func() ([]string, error) {
+			res, err := s.propose(ctx, &fsm_v1.Command{Namespace: namespace, Command: &fsm_v1.Command_Pop{Pop: &fsm_v1.PopCommand{
+				Key:   key,
+				Count: count,
+				Left:  left,
+			}}})
+			if err != nil {
+				return nil, err
+			}
+			vals, err := lists.DecodePop(res.Future.Data())
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			return vals, nil
+		})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.FromContextError(err).Err()
+	}
+
+	return &pb.PopResp{Values: vals}, nil
+}
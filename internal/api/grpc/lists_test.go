@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func popResult(t *testing.T, vals ...string) []byte {
+	data, err := proto.Marshal(&fsm_v1.PopResult{Values: vals})
+	require.NoError(t, err)
+	return data
+}
+
+func TestGRPCServer_Lists(t *testing.T) {
+	t.Run("lpush", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("Push", "l", []string{"a", "b"}, true, mock.Anything).Return(2, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("2")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.LPush(context.Background(), &pb.PushReq{Key: "l", Values: []string{"a", "b"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.GetLength())
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("rpush without values", func(t *testing.T) {
+		s := setup(t)
+
+		_, err := s.server.RPush(context.Background(), &pb.PushReq{Key: "l"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("rpop wrong type", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("Pop", "s", 1, false, mock.Anything).Return(nil, store.ErrWrongType).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrWrongType).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.RPop(context.Background(), &pb.PopReq{Key: "s"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("blpop wakes up on push", func(t *testing.T) {
+		s := setup(t)
+		watcher := fsmmocks.NewMockPushWatcher(t)
+		s.server.pushes = watcher
+		s.server.stCfg.MaxPopWait = time.Second
+
+		pushed := make(chan struct{})
+		close(pushed)
+		watcher.On("WatchPushes", "l").Return((<-chan struct{})(pushed), func() {}).Twice()
+		s.mockStore.On("Pop", "l", 2, true, mock.Anything).Return([]string{}, nil).Once()
+		s.mockStore.On("LLen", "l").Return(1, nil).Once()
+		s.mockStore.On("Pop", "l", 2, true, mock.Anything).Return([]string{"a"}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Twice()
+		s.mockFuture.On("Data").Return(popResult(t)).Once()
+		s.mockFuture.On("Data").Return(popResult(t, "a")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Twice()
+
+		resp, err := s.server.BLPop(context.Background(), &pb.BLPopReq{Key: "l", Count: 2, TimeoutMs: 60_000})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, resp.GetValues())
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("blpop times out", func(t *testing.T) {
+		s := setup(t)
+		watcher := fsmmocks.NewMockPushWatcher(t)
+		s.server.pushes = watcher
+		s.server.stCfg.MaxPopWait = time.Second
+
+		watcher.On("WatchPushes", "l").Return(make(<-chan struct{}), func() {}).Once()
+		s.mockStore.On("Pop", "l", 1, true, mock.Anything).Return([]string{}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return(popResult(t)).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.BLPop(context.Background(), &pb.BLPopReq{Key: "l", TimeoutMs: 20})
+		require.NoError(t, err)
+		assert.Empty(t, resp.GetValues())
+	})
+
+	t.Run("lrange and llen of missing list", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("LRange", "l", 0, -1).Return(nil, store.ErrNoSuchKey).Once()
+		s.mockStore.On("LLen", "l").Return(0, store.ErrNoSuchKey).Once()
+
+		vals, err := s.server.LRange(context.Background(), &pb.LRangeReq{Key: "l", Stop: -1})
+		require.NoError(t, err)
+		assert.Empty(t, vals.GetValues())
+		n, err := s.server.LLen(context.Background(), &pb.LLenReq{Key: "l"})
+		require.NoError(t, err)
+		assert.Zero(t, n.GetLength())
+	})
+}
@@ -2,32 +2,51 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/shrtyk/kv-store/internal/cfg"
-	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/health"
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
 	"github.com/shrtyk/kv-store/internal/core/ports/metrics"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
 	kv_store_v1 "github.com/shrtyk/kv-store/proto/grpc/gen"
 	raftapi "github.com/shrtyk/raft-core/api"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 type Server struct {
-	wg                  *sync.WaitGroup
-	cfg                 *cfg.GRPCCfg
-	stCfg               *cfg.StoreCfg
-	store               store.Store
-	metrics             metrics.Metrics
-	logger              *slog.Logger
-	grpcServ            *grpc.Server
-	raft                raftapi.Raft
-	futures             ftr.FuturesStore
-	raftPublicHTTPAddrs []string
+	wg         *sync.WaitGroup
+	cfg        *cfg.GRPCCfg
+	stCfg      *cfg.StoreCfg
+	namespaces store.Namespaces
+	metrics    metrics.Metrics
+	logger     *slog.Logger
+	grpcServ   *grpc.Server
+	raft       raftapi.Raft
+	proposer   proposer.Proposer
+	membership cluster.Membership
+	auth       *auth.Service
+	limiter    *ratelimit.Limiter
+	admission  *admission.Controller
+	applied    fsm.AppliedIndex
+	pushes     fsm.PushWatcher
+	leases     fsm.LeaseReader
+	checker    *health.Checker
+	health     *grpchealth.Server
 
 	kv_store_v1.UnimplementedKVStoreServer
 }
@@ -36,28 +55,54 @@ func NewGRPCServer(
 	wg *sync.WaitGroup,
 	cfg *cfg.GRPCCfg,
 	stCfg *cfg.StoreCfg,
-	store store.Store,
+	namespaces store.Namespaces,
 	metrics metrics.Metrics,
 	logger *slog.Logger,
 	raft raftapi.Raft,
-	futures ftr.FuturesStore,
-	raftPublicHTTPAddrs []string,
+	prop proposer.Proposer,
+	membership cluster.Membership,
+	tlsConf *tls.Config,
+	authSvc *auth.Service,
+	limiter *ratelimit.Limiter,
+	adm *admission.Controller,
+	applied fsm.AppliedIndex,
+	pushes fsm.PushWatcher,
+	leases fsm.LeaseReader,
+	checker *health.Checker,
 ) *Server {
 	s := &Server{
-		wg:                  wg,
-		cfg:                 cfg,
-		stCfg:               stCfg,
-		store:               store,
-		metrics:             metrics,
-		logger:              logger,
-		grpcServ:            grpc.NewServer(),
-		raft:                raft,
-		futures:             futures,
-		raftPublicHTTPAddrs: raftPublicHTTPAddrs,
+		wg:         wg,
+		cfg:        cfg,
+		stCfg:      stCfg,
+		namespaces: namespaces,
+		metrics:    metrics,
+		logger:     logger,
+		raft:       raft,
+		proposer:   prop,
+		membership: membership,
+		auth:       authSvc,
+		limiter:    limiter,
+		admission:  adm,
+		applied:    applied,
+		pushes:     pushes,
+		leases:     leases,
+		checker:    checker,
 	}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.observe, s.requestInfo, s.authorize, s.rateLimit),
+	}
+	if tlsConf != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+	}
+	s.grpcServ = grpc.NewServer(opts...)
 
 	kv_store_v1.RegisterKVStoreServer(s.grpcServ, s)
 	reflection.Register(s.grpcServ)
+	if checker != nil {
+		s.health = grpchealth.NewServer()
+		s.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		healthpb.RegisterHealthServer(s.grpcServ, s.health)
+	}
 
 	return s
 }
@@ -70,7 +115,6 @@ func (s *Server) MustStart() {
 	}
 
 	s.wg.Go(func() {
-		defer s.wg.Done()
 		if err := s.grpcServ.Serve(l); err != nil {
 			msg := fmt.Sprintf("failed to start grpc server: %s", err)
 			panic(msg)
@@ -78,7 +122,50 @@ func (s *Server) MustStart() {
 	})
 }
 
+// defaultHealthInterval is used if health watch interval isn't configured.
+const defaultHealthInterval = 5 * time.Second
+
+// WatchHealth refreshes serving status of the health service from readiness
+// checks every interval until ctx is done.
+func (s *Server) WatchHealth(ctx context.Context, interval time.Duration) {
+	if s.health == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		s.updateHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *Server) updateHealth(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if s.checker.Ready(ctx).Ready {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.setServingStatus(status)
+}
+
+// setServingStatus sets status of the whole server and of the KVStore service.
+func (s *Server) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(kv_store_v1.KVStore_ServiceDesc.ServiceName, status)
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.health != nil {
+		// Clients watching health are told the node is going away before connections are closed.
+		s.health.Shutdown()
+	}
+
 	done := make(chan struct{})
 	go func() {
 		s.grpcServ.GracefulStop()
@@ -0,0 +1,170 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/shrtyk/kv-store/internal/api/zsets"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ZAdd(ctx context.Context, in *pb.ZAddReq) (*pb.ZAddResp, error) {
+	if len(in.GetMembers()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no members to add")
+	}
+	members := make([]*fsm_v1.ScoredMember, 0, len(in.GetMembers()))
+	for _, m := range in.GetMembers() {
+		if err := s.checkMember(in.GetKey(), m.GetMember(), m.GetScore()); err != nil {
+			return nil, err
+		}
+		members = append(members, &fsm_v1.ScoredMember{Member: m.GetMember(), Score: m.GetScore()})
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Zadd{Zadd: &fsm_v1.ZAddCommand{
+		Key:     in.GetKey(),
+		Members: members,
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	added, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.ZAddResp{Added: added}, nil
+}
+
+func (s *Server) ZRem(ctx context.Context, in *pb.ZRemReq) (*pb.ZRemResp, error) {
+	if len(in.GetMembers()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no members to remove")
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Zrem{Zrem: &fsm_v1.ZRemCommand{
+		Key:     in.GetKey(),
+		Members: in.GetMembers(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	removed, _ := strconv.ParseInt(string(res.Future.Data()), 10, 64)
+	return &pb.ZRemResp{Removed: removed}, nil
+}
+
+func (s *Server) ZScore(ctx context.Context, in *pb.ZScoreReq) (*pb.ZScoreResp, error) {
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	score, err := st.ZScore(in.GetKey(), in.GetMember())
+	if err != nil {
+		return nil, readError(err)
+	}
+	return &pb.ZScoreResp{Score: score}, nil
+}
+
+func (s *Server) ZRange(ctx context.Context, in *pb.ZRangeReq) (*pb.ZRangeResp, error) {
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	members, err := st.ZRange(in.GetKey(), int(in.GetStart()), int(in.GetStop()))
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return nil, readError(err)
+	}
+	return &pb.ZRangeResp{Members: toPBMembers(members)}, nil
+}
+
+func (s *Server) ZRangeByScore(ctx context.Context, in *pb.ZRangeByScoreReq) (*pb.ZRangeResp, error) {
+	if math.IsNaN(in.GetMin()) || math.IsNaN(in.GetMax()) {
+		return nil, status.Error(codes.InvalidArgument, zsets.ErrInvalidScore.Error())
+	}
+	if err := s.readBarrier(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := s.namespaceStore(in.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	members, err := st.ZRangeByScore(in.GetKey(), in.GetMin(), in.GetMax(), int(in.GetLimit()))
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return nil, readError(err)
+	}
+	return &pb.ZRangeResp{Members: toPBMembers(members)}, nil
+}
+
+func (s *Server) ZIncrBy(ctx context.Context, in *pb.ZIncrByReq) (*pb.ZIncrByResp, error) {
+	if err := s.checkMember(in.GetKey(), in.GetMember(), in.GetDelta()); err != nil {
+		return nil, err
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Zincrby{Zincrby: &fsm_v1.ZIncrByCommand{
+		Key:    in.GetKey(),
+		Member: in.GetMember(),
+		Delta:  in.GetDelta(),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := zsets.ParseScore(string(res.Future.Data()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.ZIncrByResp{Score: score}, nil
+}
+
+func (s *Server) ZPopMin(ctx context.Context, in *pb.ZPopMinReq) (*pb.ZRangeResp, error) {
+	if in.GetCount() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative count")
+	}
+
+	res, err := s.propose(ctx, &fsm_v1.Command{Namespace: in.GetNamespace(), Command: &fsm_v1.Command_Zpopmin{Zpopmin: &fsm_v1.ZPopMinCommand{
+		Key:   in.GetKey(),
+		Count: max(in.GetCount(), 1),
+	}}})
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := zsets.DecodePopMin(res.Future.Data())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.ZRangeResp{Members: toPBMembers(members)}, nil
+}
+
+// checkMember checks sizes of the key and a member and its score. Members are limited like values.
+func (s *Server) checkMember(key, member string, score float64) error {
+	if len(key) > s.stCfg.MaxKeySize {
+		return status.Error(codes.InvalidArgument, store.ErrKeyTooLarge.Error())
+	}
+	if len(member) > s.stCfg.MaxValSize {
+		return status.Error(codes.InvalidArgument, store.ErrValueTooLarge.Error())
+	}
+	if math.IsNaN(score) {
+		return status.Error(codes.InvalidArgument, zsets.ErrInvalidScore.Error())
+	}
+	return nil
+}
+
+func toPBMembers(members []store.ScoredMember) []*pb.ScoredMember {
+	out := make([]*pb.ScoredMember, 0, len(members))
+	for _, m := range members {
+		out = append(out, &pb.ScoredMember{Member: m.Member, Score: m.Score})
+	}
+	return out
+}
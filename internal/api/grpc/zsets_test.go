@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	pb "github.com/shrtyk/kv-store/proto/grpc/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCServer_SortedSets(t *testing.T) {
+	t.Run("zadd", func(t *testing.T) {
+		s := setup(t)
+		members := []store.ScoredMember{{Member: "a", Score: 1}, {Member: "b", Score: math.Inf(1)}}
+
+		s.mockStore.On("ZAdd", "z", members, mock.Anything).Return(2, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("2")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.ZAdd(context.Background(), &pb.ZAddReq{Key: "z", Members: []*pb.ScoredMember{
+			{Member: "a", Score: 1},
+			{Member: "b", Score: math.Inf(1)},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.GetAdded())
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("zadd nan", func(t *testing.T) {
+		s := setup(t)
+
+		_, err := s.server.ZAdd(context.Background(), &pb.ZAddReq{Key: "z", Members: []*pb.ScoredMember{
+			{Member: "a", Score: math.NaN()},
+		}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("zincrby wrong type", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("ZIncrBy", "s", "a", 1.0, mock.Anything).Return(0.0, store.ErrWrongType).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrWrongType).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		_, err := s.server.ZIncrBy(context.Background(), &pb.ZIncrByReq{Key: "s", Member: "a", Delta: 1})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("zrange and zrangebyscore", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("ZRange", "z", 0, -1).Return([]store.ScoredMember{{Member: "a", Score: 1}}, nil).Once()
+		s.mockStore.On("ZRangeByScore", "z", 0.0, 5.0, 1).Return(nil, store.ErrNoSuchKey).Once()
+
+		resp, err := s.server.ZRange(context.Background(), &pb.ZRangeReq{Key: "z", Stop: -1})
+		require.NoError(t, err)
+		require.Len(t, resp.GetMembers(), 1)
+		assert.Equal(t, "a", resp.GetMembers()[0].GetMember())
+
+		resp, err = s.server.ZRangeByScore(context.Background(), &pb.ZRangeByScoreReq{Key: "z", Max: 5, Limit: 1})
+		require.NoError(t, err)
+		assert.Empty(t, resp.GetMembers())
+	})
+
+	t.Run("zscore missing member", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("ZScore", "z", "a").Return(0.0, store.ErrNoSuchKey).Once()
+
+		_, err := s.server.ZScore(context.Background(), &pb.ZScoreReq{Key: "z", Member: "a"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("zpopmin", func(t *testing.T) {
+		s := setup(t)
+		popped, err := proto.Marshal(&fsm_v1.ZPopResult{Members: []*fsm_v1.ScoredMember{{Member: "a", Score: 1}}})
+		require.NoError(t, err)
+
+		s.mockStore.On("ZPopMin", "z", 1, mock.Anything).Return([]store.ScoredMember{{Member: "a", Score: 1}}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return(popped).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		resp, err := s.server.ZPopMin(context.Background(), &pb.ZPopMinReq{Key: "z"})
+		require.NoError(t, err)
+		require.Len(t, resp.GetMembers(), 1)
+		assert.Equal(t, 1.0, resp.GetMembers()[0].GetScore())
+	})
+
+	t.Run("zrem not leader", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+
+		_, err := s.server.ZRem(context.Background(), &pb.ZRemReq{Key: "z", Members: []string{"a"}})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
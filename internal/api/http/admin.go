@@ -0,0 +1,226 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"google.golang.org/protobuf/proto"
+)
+
+var errInvalidAddr = errors.New("http_addr must be an absolute http(s) url")
+
+type adminHandlers struct {
+	stCfg      *cfg.StoreCfg
+	fsmStatus  fsm.StatusReporter
+	membership cluster.Membership
+	raft       raftapi.Raft
+	proposer   proposer.Proposer
+	namespaces store.Namespaces
+}
+
+// NewAdminHandlers returns handlers of operator endpoints. fsmStatus may be nil.
+func NewAdminHandlers(
+	stCfg *cfg.StoreCfg,
+	fsmStatus fsm.StatusReporter,
+	membership cluster.Membership,
+	raft raftapi.Raft,
+	prop proposer.Proposer,
+	namespaces store.Namespaces,
+) *adminHandlers {
+	return &adminHandlers{
+		stCfg:      stCfg,
+		fsmStatus:  fsmStatus,
+		membership: membership,
+		raft:       raft,
+		proposer:   prop,
+		namespaces: namespaces,
+	}
+}
+
+// SnapshotStatus godoc
+// @Summary      Last snapshot restore
+// @Description  Returns outcome of the last snapshot restore attempt of this node. Non-empty error means the node refused a corrupted snapshot and stopped applying commands.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} fsm.RestoreStatus
+// @Success      204 "No snapshot was restored yet"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Forbidden"
+// @Security     BearerAuth
+// @Router       /admin/snapshot [get]
+func (h *adminHandlers) SnapshotStatus(w http.ResponseWriter, r *http.Request) {
+	var status *fsm.RestoreStatus
+	if h.fsmStatus != nil {
+		status = h.fsmStatus.LastRestore()
+	}
+	if status == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+type clusterStatus struct {
+	Term     int64            `json:"term"`
+	IsLeader bool             `json:"is_leader"`
+	Voters   int              `json:"voters"`
+	Members  []cluster.Member `json:"members"`
+}
+
+// ClusterStatus godoc
+// @Summary      Cluster membership
+// @Description  Returns raft state of this node and known cluster members with their public addresses. Every member is a voter, learners are not supported.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} clusterStatus
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Forbidden"
+// @Security     BearerAuth
+// @Router       /admin/cluster [get]
+func (h *adminHandlers) ClusterStatus(w http.ResponseWriter, r *http.Request) {
+	term, isLeader := h.raft.State()
+	writeJSON(w, http.StatusOK, clusterStatus{
+		Term:     term,
+		IsLeader: isLeader,
+		Voters:   h.membership.Voters(),
+		Members:  h.membership.Members(),
+	})
+}
+
+type memberReq struct {
+	HTTPAddr string `json:"http_addr"`
+}
+
+// SetMember godoc
+// @Summary      Set member address
+// @Description  Commits a new public address of the node through raft, e.g. after replacing a machine. Voter set is fixed by raft peers configuration, so id must be one of the configured peers. Adding a voter is not supported and rejected with 501.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "raft node id"
+// @Param        member body memberReq true "member"
+// @Success      200 {array} cluster.Member
+// @Failure      307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Forbidden"
+// @Failure      501 {string} string "Adding raft voters is not supported"
+// @Failure      503 {string} string "Request timed out"
+// @Security     BearerAuth
+// @Router       /admin/cluster/members/{id} [put]
+func (h *adminHandlers) SetMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.memberID(w, r)
+	if !ok {
+		return
+	}
+
+	var req memberReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if u, err := url.Parse(req.HTTPAddr); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		http.Error(w, errInvalidAddr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.commitMembership(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_MemberSet{MemberSet: &fsm_v1.MemberSetCommand{
+		Member: &fsm_v1.Member{Id: int32(id), HttpAddr: req.HTTPAddr},
+	}}})
+}
+
+// RemoveMember godoc
+// @Summary      Remove member address
+// @Description  Commits removal of the node's public address, so clients are no longer redirected to it. The node stays a raft voter until it's removed from raft peers configuration.
+// @Tags         admin
+// @Produce      json
+// @Param        id path int true "raft node id"
+// @Success      200 {array} cluster.Member
+// @Failure      307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Forbidden"
+// @Failure      404 {string} string "Member has no address"
+// @Failure      501 {string} string "Removing raft voters is not supported"
+// @Failure      503 {string} string "Request timed out"
+// @Security     BearerAuth
+// @Router       /admin/cluster/members/{id} [delete]
+func (h *adminHandlers) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.memberID(w, r)
+	if !ok {
+		return
+	}
+
+	h.commitMembership(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_MemberRemove{MemberRemove: &fsm_v1.MemberRemoveCommand{
+		Id: int32(id),
+	}}})
+}
+
+// memberID parses id of a configured raft peer. Errors are written into w.
+func (h *adminHandlers) memberID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 0 {
+		http.Error(w, fmt.Sprintf("invalid node id: %q", chi.URLParam(r, "id")), http.StatusBadRequest)
+		return 0, false
+	}
+	if id >= h.membership.Voters() {
+		// Members outside of the voter set would be voters added or removed at runtime.
+		http.Error(w, fmt.Sprintf("%s: %d, configured peers: %d: %s",
+			cluster.ErrUnknownMember, id, h.membership.Voters(), cluster.ErrVoterChange), http.StatusNotImplemented)
+		return 0, false
+	}
+	return id, true
+}
+
+// commitMembership commits a change of a single member, so concurrent changes of other members aren't lost.
+func (h *adminHandlers) commitMembership(w http.ResponseWriter, r *http.Request, cmd *fsm_v1.Command) {
+	if h.commit(w, r, cmd) {
+		writeJSON(w, http.StatusOK, h.membership.Members())
+	}
+}
+
+// commit proposes the command and waits until it's applied. Errors are written into w.
+func (h *adminHandlers) commit(w http.ResponseWriter, r *http.Request, cmd *fsm_v1.Command) bool {
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		http.Error(w, "failed to marshal command", http.StatusInternalServerError)
+		return false
+	}
+
+	ctx, cancel := withTimeout(r.Context(), h.stCfg.WriteTimeout)
+	defer cancel()
+
+	res, err := h.proposer.Propose(ctx, data)
+	if err != nil {
+		writeApplyError(w, err)
+		return false
+	}
+	if !res.IsLeader {
+		redirect(w, h.membership, r.URL.Path, res.LeaderID)
+		return false
+	}
+	if err := res.Future.Wait(ctx); err != nil {
+		writeApplyError(w, err)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,196 @@
+package httphandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	clusterport "github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	proposermocks "github.com/shrtyk/kv-store/internal/core/ports/proposer/mocks"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSnapshotStatus(t *testing.T) {
+	t.Run("nothing restored", func(t *testing.T) {
+		reporter := fsmmocks.NewMockStatusReporter(t)
+		reporter.On("LastRestore").Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		NewAdminHandlers(&cfg.StoreCfg{}, reporter, nil, nil, nil, nil).SnapshotStatus(rr, httptest.NewRequest(http.MethodGet, "/admin/snapshot", nil))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("failed restore", func(t *testing.T) {
+		status := &fsm.RestoreStatus{
+			Time:          time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			SnapshotIndex: 42,
+			SnapshotSize:  1024,
+			Error:         "snapshot: corrupted data: checksum mismatch",
+		}
+		reporter := fsmmocks.NewMockStatusReporter(t)
+		reporter.On("LastRestore").Return(status).Once()
+
+		rr := httptest.NewRecorder()
+		NewAdminHandlers(&cfg.StoreCfg{}, reporter, nil, nil, nil, nil).SnapshotStatus(rr, httptest.NewRequest(http.MethodGet, "/admin/snapshot", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		got := new(fsm.RestoreStatus)
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(got))
+		assert.Equal(t, status, got)
+	})
+}
+
+func newMemberReq(method, id, body string) *http.Request {
+	req := httptest.NewRequest(method, "/admin/cluster/members/"+id, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// committingProposer applies member commands to the registry like the state machine does.
+func committingProposer(t *testing.T, registry *cluster.Registry) *proposermocks.MockProposer {
+	prop := proposermocks.NewMockProposer(t)
+	prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+		var cmd fsm_v1.Command
+		require.NoError(t, proto.Unmarshal(data, &cmd))
+
+		var err error
+		members := registry.Members()
+		switch c := cmd.Command.(type) {
+		case *fsm_v1.Command_MemberSet:
+			m := c.MemberSet.Member
+			members = slices.DeleteFunc(members, func(member clusterport.Member) bool { return member.ID == int(m.Id) })
+			members = append(members, clusterport.Member{ID: int(m.Id), HTTPAddr: m.HttpAddr})
+		case *fsm_v1.Command_MemberRemove:
+			n := len(members)
+			members = slices.DeleteFunc(members, func(member clusterport.Member) bool {
+				return member.ID == int(c.MemberRemove.Id)
+			})
+			if len(members) == n {
+				err = clusterport.ErrNoSuchMember
+			}
+		}
+		registry.Replace(members)
+
+		future := futuresmocks.NewMockFuture(t)
+		future.On("Wait", mock.Anything).Return(err).Once()
+		return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+	}).Maybe()
+	return prop
+}
+
+func TestClusterMembers(t *testing.T) {
+	addrs := []string{"http://a:8080", "http://b:8080", "http://c:8080"}
+
+	t.Run("status", func(t *testing.T) {
+		registry := cluster.NewRegistry(3, addrs)
+		h := NewAdminHandlers(&cfg.StoreCfg{}, nil, registry, rmocks.NewStubRaft(nil, true, 0), nil, nil)
+
+		rr := httptest.NewRecorder()
+		h.ClusterStatus(rr, httptest.NewRequest(http.MethodGet, "/admin/cluster", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var status clusterStatus
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&status))
+		assert.True(t, status.IsLeader)
+		assert.Equal(t, 3, status.Voters)
+		assert.Equal(t, registry.Members(), status.Members)
+	})
+
+	t.Run("replace address", func(t *testing.T) {
+		registry := cluster.NewRegistry(3, addrs)
+		h := NewAdminHandlers(&cfg.StoreCfg{}, nil, registry, nil, committingProposer(t, registry), nil)
+
+		rr := httptest.NewRecorder()
+		h.SetMember(rr, newMemberReq(http.MethodPut, "2", `{"http_addr":"http://new-c:8080"}`))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		addr, _ := registry.HTTPAddr(2)
+		assert.Equal(t, "http://new-c:8080", addr)
+		assert.Len(t, registry.Members(), 3)
+	})
+
+	t.Run("remove address", func(t *testing.T) {
+		registry := cluster.NewRegistry(3, addrs)
+		h := NewAdminHandlers(&cfg.StoreCfg{}, nil, registry, nil, committingProposer(t, registry), nil)
+
+		rr := httptest.NewRecorder()
+		h.RemoveMember(rr, newMemberReq(http.MethodDelete, "1", ""))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		_, ok := registry.HTTPAddr(1)
+		assert.False(t, ok)
+
+		rr = httptest.NewRecorder()
+		h.RemoveMember(rr, newMemberReq(http.MethodDelete, "1", ""))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("rejects ids outside of voter set", func(t *testing.T) {
+		registry := cluster.NewRegistry(3, addrs)
+		h := NewAdminHandlers(&cfg.StoreCfg{}, nil, registry, nil, committingProposer(t, registry), nil)
+
+		rr := httptest.NewRecorder()
+		h.SetMember(rr, newMemberReq(http.MethodPut, "3", `{"http_addr":"http://d:8080"}`))
+
+		assert.Equal(t, http.StatusNotImplemented, rr.Code, "adding voters is not supported")
+		assert.Contains(t, rr.Body.String(), clusterport.ErrUnknownMember.Error())
+		assert.Contains(t, rr.Body.String(), clusterport.ErrVoterChange.Error())
+
+		rr = httptest.NewRecorder()
+		h.RemoveMember(rr, newMemberReq(http.MethodDelete, "3", ""))
+		assert.Equal(t, http.StatusNotImplemented, rr.Code, "removing voters is not supported")
+		assert.Contains(t, rr.Body.String(), clusterport.ErrUnknownMember.Error())
+		assert.Contains(t, rr.Body.String(), clusterport.ErrVoterChange.Error())
+
+		rr = httptest.NewRecorder()
+		h.SetMember(rr, newMemberReq(http.MethodPut, "-1", `{"http_addr":"http://d:8080"}`))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		rr = httptest.NewRecorder()
+		h.RemoveMember(rr, newMemberReq(http.MethodDelete, "x", ""))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("rejects invalid address", func(t *testing.T) {
+		registry := cluster.NewRegistry(3, addrs)
+		h := NewAdminHandlers(&cfg.StoreCfg{}, nil, registry, nil, committingProposer(t, registry), nil)
+
+		rr := httptest.NewRecorder()
+		h.SetMember(rr, newMemberReq(http.MethodPut, "0", `{"http_addr":"a:8080"}`))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("redirects to leader", func(t *testing.T) {
+		registry := cluster.NewRegistry(3, addrs)
+		prop := proposermocks.NewMockProposer(t)
+		prop.On("Propose", mock.Anything, mock.Anything).Return(&proposer.Proposal{LeaderID: 1}, nil).Once()
+		h := NewAdminHandlers(&cfg.StoreCfg{}, nil, registry, nil, prop, nil)
+
+		rr := httptest.NewRecorder()
+		h.RemoveMember(rr, newMemberReq(http.MethodDelete, "2", ""))
+
+		assert.Equal(t, http.StatusTemporaryRedirect, rr.Code)
+		assert.Equal(t, "http://b:8080/admin/cluster/members/2", rr.Header().Get("Location"))
+	})
+}
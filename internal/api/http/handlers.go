@@ -2,6 +2,7 @@ package httphandlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,9 +11,15 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
 	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
 	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
 	"github.com/shrtyk/kv-store/internal/core/ports/metrics"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	"github.com/shrtyk/kv-store/pkg/logger"
 	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
@@ -21,29 +28,41 @@ import (
 )
 
 type handlersProvider struct {
-	stCfg               *cfg.StoreCfg
-	store               store.Store
-	metrics             metrics.Metrics
-	raft                raftapi.Raft
-	futures             ftr.FuturesStore
-	raftPublicHTTPAddrs []string
+	stCfg      *cfg.StoreCfg
+	namespaces store.Namespaces
+	metrics    metrics.Metrics
+	raft       raftapi.Raft
+	proposer   proposer.Proposer
+	membership cluster.Membership
+	admission  *admission.Controller
+	applied    fsm.AppliedIndex
+	pushes     fsm.PushWatcher
+	leases     fsm.LeaseReader
 }
 
 func NewHandlersProvider(
 	stCfg *cfg.StoreCfg,
-	store store.Store,
+	namespaces store.Namespaces,
 	m metrics.Metrics,
 	raft raftapi.Raft,
-	futures ftr.FuturesStore,
-	raftPublicHTTPAddrs []string,
+	prop proposer.Proposer,
+	membership cluster.Membership,
+	adm *admission.Controller,
+	applied fsm.AppliedIndex,
+	pushes fsm.PushWatcher,
+	leases fsm.LeaseReader,
 ) *handlersProvider {
 	return &handlersProvider{
-		stCfg:               stCfg,
-		store:               store,
-		metrics:             m,
-		raft:                raft,
-		futures:             futures,
-		raftPublicHTTPAddrs: raftPublicHTTPAddrs,
+		stCfg:      stCfg,
+		namespaces: namespaces,
+		metrics:    m,
+		raft:       raft,
+		proposer:   prop,
+		membership: membership,
+		admission:  adm,
+		applied:    applied,
+		pushes:     pushes,
+		leases:     leases,
 	}
 }
 
@@ -54,8 +73,13 @@ func NewHandlersProvider(
 // @Produce      text/plain
 // @Success      200 {string} string
 // @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is draining before shutdown"
 // @Router       /healthz [get]
 func (h *handlersProvider) Healthz(w http.ResponseWriter, r *http.Request) {
+	if h.admission.Draining() {
+		http.Error(w, admission.ErrDraining.Error(), http.StatusServiceUnavailable)
+		return
+	}
 	if _, err := fmt.Fprint(w, "kv-store up and healthy"); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
@@ -63,16 +87,23 @@ func (h *handlersProvider) Healthz(w http.ResponseWriter, r *http.Request) {
 
 // PutHandler godoc
 // @Summary      Puts a value into the store
-// @Description  Puts a value into the store
+// @Description  Puts a value into the store. Values sent as application/json must be valid JSON
 // @Tags         store
-// @Accept       text/plain
+// @Accept       text/plain,json
 // @Produce      text/plain
 // @Param        key path string true "key"
 // @Param        value body string true "value"
 // @Success      201
+// @Header       201 {int} X-KV-Index "Log index of the write to use in subsequent reads"
 // @Failure 	 307 {string} string "Node is not a leader"
 // @Failure      400 {string} string "Wrong input data"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
 // @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
 // @Router       /v1/{key} [put]
 func (h *handlersProvider) PutHandler(w http.ResponseWriter, r *http.Request) {
 	l := logger.FromCtx(r.Context())
@@ -98,8 +129,13 @@ func (h *handlersProvider) PutHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, store.ErrValueTooLarge.Error(), http.StatusBadRequest)
 		return
 	}
+	if hasMediaType(r, "application/json") && !json.Valid(val) {
+		http.Error(w, jsondoc.ErrNotJSON.Error(), http.StatusBadRequest)
+		return
+	}
 
 	cmd := &fsm_v1.Command{
+		Namespace: namespaceOf(r),
 		Command: &fsm_v1.Command_Put{
 			Put: &fsm_v1.PutCommand{
 				Key:   key,
@@ -113,24 +149,35 @@ func (h *handlersProvider) PutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res := h.raft.Submit(data)
+	done, err := h.admission.AdmitWrite()
+	if err != nil {
+		writeOverloaded(w, err)
+		return
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(r.Context(), h.stCfg.WriteTimeout)
+	defer cancel()
+
+	res, err := h.proposer.Propose(ctx, data)
+	if err != nil {
+		writeApplyError(w, err)
+		return
+	}
 	if !res.IsLeader {
 		h.redirect(w, r.URL.Path, res.LeaderID)
 		return
 	}
 
-	promise := h.futures.NewFuture(res.LogIndex)
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-	defer cancel()
-	if err := promise.Wait(ctx); err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
-			http.Error(w, "request timed out: raft cluster is busy", http.StatusServiceUnavailable)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	err = res.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		writeApplyError(w, err)
 		return
 	}
 
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
 	w.WriteHeader(http.StatusCreated)
 	h.metrics.HttpPut(key, time.Since(start).Seconds())
 	l.Debug(
@@ -141,31 +188,75 @@ func (h *handlersProvider) PutHandler(w http.ResponseWriter, r *http.Request) {
 
 // GetHandler godoc
 // @Summary      Gets a value from the store
-// @Description  Gets a value from the store
+// @Description  Gets a value from the store. With path, returns the part of a JSON document at the path
 // @Tags         store
-// @Produce      text/plain
+// @Produce      text/plain,json
 // @Param        key path string true "key"
+// @Param        path query string false "JSON path like $.a.b[0]"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
 // @Success      200 {string} string "value"
+// @Header       200 {int} X-KV-Index "Index applied by the node, only for reads with X-KV-Index or stale reads"
+// @Header       200 {string} X-KV-Staleness "Time since the node applied its last entry, only for stale reads"
 // @Failure 	 307 {string} string "Node is not a leader"
-// @Failure      404
+// @Failure      400 {string} string "Invalid X-KV-Index, X-KV-Consistency or path"
+// @Failure      404 {string} string "Key or path not found"
+// @Failure      409 {string} string "Key holds a value of another kind or, with path, not a JSON document"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
 // @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
 // @Router       /v1/{key} [get]
 func (h *handlersProvider) GetHandler(w http.ResponseWriter, r *http.Request) {
 	l := logger.FromCtx(r.Context())
 	start := time.Now()
 
 	key := chi.URLParam(r, "key")
+	if path, ok := r.URL.Query()["path"]; ok {
+		h.getJSONPath(w, r, key, path[0])
+		return
+	}
+
+	if err := h.admission.AdmitRead(); err != nil {
+		writeOverloaded(w, err)
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	ctx, cancel := withTimeout(r.Context(), h.stCfg.ReadTimeout)
 	defer cancel()
 
-	resp, err := h.raft.ReadOnly(ctx, []byte(key))
+	if h.applied != nil {
+		stale, err := consistency.IsStale(r.Header.Get(consistency.ModeHeader))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if stale {
+			if staleness := consistency.Staleness(h.applied); staleness != "" {
+				w.Header().Set(consistency.StalenessHeader, staleness)
+			}
+			h.readLocal(w, r.WithContext(ctx), key, start)
+			return
+		}
+		if token := r.Header.Get(consistency.Header); token != "" {
+			h.readAtIndex(w, r.WithContext(ctx), key, token, start)
+			return
+		}
+	}
+
+	resp, err := h.raft.ReadOnly(ctx, fsm.ReadQuery(namespaceOf(r), key))
 	if err != nil {
 		switch {
-		case errors.Is(err, store.ErrNoSuchKey):
+		case errors.Is(err, store.ErrNoSuchKey), errors.Is(err, store.ErrNoSuchNamespace):
 			http.NotFound(w, r)
+		case errors.Is(err, store.ErrWrongType):
+			http.Error(w, err.Error(), http.StatusConflict)
 		case errors.Is(err, context.DeadlineExceeded):
 			http.Error(w, "request timed out: raft cluster is busy", http.StatusServiceUnavailable)
+		case errors.Is(err, fsm.ErrStateUnavailable):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -189,14 +280,70 @@ func (h *handlersProvider) GetHandler(w http.ResponseWriter, r *http.Request) {
 		slog.String("value", string(resp.Data)))
 }
 
+// readAtIndex answers from the local store once the node has applied the index from the token.
+// Unlike ReadOnly it doesn't require leadership, so followers can serve read-your-writes reads.
+func (h *handlersProvider) readAtIndex(w http.ResponseWriter, r *http.Request, key, token string, start time.Time) {
+	idx, err := consistency.ParseIndex(token)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := consistency.Wait(r.Context(), h.applied, idx, h.stCfg.ReadIndexTimeout); err != nil {
+		switch {
+		case errors.Is(err, consistency.ErrNotApplied), errors.Is(err, context.DeadlineExceeded):
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		case errors.Is(err, fsm.ErrStateUnavailable):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.readLocal(w, r, key, start)
+}
+
+// readLocal answers from the local store and reports the applied index the answer reflects.
+func (h *handlersProvider) readLocal(w http.ResponseWriter, r *http.Request, key string, start time.Time) {
+	w.Header().Set(consistency.Header, consistency.FormatIndex(h.applied.AppliedIndex()))
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+	val, err := st.Get(key)
+	if err != nil {
+		writeReadError(w, r, err)
+		return
+	}
+
+	if _, err := io.WriteString(w, val); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.HttpGet(key, time.Since(start).Seconds())
+	logger.FromCtx(r.Context()).Debug(
+		"Local get operation successfully completed",
+		slog.String("key", key),
+		slog.String("value", val))
+}
+
 // DeleteHandler godoc
 // @Summary      Deletes a value from the store
 // @Description  Deletes a value from the store
 // @Tags         store
 // @Param        key path string true "key"
 // @Success      204
+// @Header       204 {int} X-KV-Index "Log index of the write to use in subsequent reads"
 // @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
 // @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
 // @Router       /v1/{key} [delete]
 func (h *handlersProvider) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 	l := logger.FromCtx(r.Context())
@@ -205,6 +352,7 @@ func (h *handlersProvider) DeleteHandler(w http.ResponseWriter, r *http.Request)
 	key := chi.URLParam(r, "key")
 
 	cmd := &fsm_v1.Command{
+		Namespace: namespaceOf(r),
 		Command: &fsm_v1.Command_Delete{
 			Delete: &fsm_v1.DeleteCommand{
 				Key: key,
@@ -217,32 +365,46 @@ func (h *handlersProvider) DeleteHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	res := h.raft.Submit(data)
+	done, err := h.admission.AdmitWrite()
+	if err != nil {
+		writeOverloaded(w, err)
+		return
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(r.Context(), h.stCfg.WriteTimeout)
+	defer cancel()
+
+	res, err := h.proposer.Propose(ctx, data)
+	if err != nil {
+		writeApplyError(w, err)
+		return
+	}
 	if !res.IsLeader {
 		h.redirect(w, r.URL.Path, res.LeaderID)
 		return
 	}
 
-	promise := h.futures.NewFuture(res.LogIndex)
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-	defer cancel()
-	if err := promise.Wait(ctx); err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
-			http.Error(w, "request timed out: raft cluster is busy", http.StatusServiceUnavailable)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	err = res.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		writeApplyError(w, err)
 		return
 	}
 
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
 	w.WriteHeader(http.StatusNoContent)
 	h.metrics.HttpDelete(key, time.Since(start).Seconds())
 	l.Debug("Delete operation successfully completed", slog.String("key", key))
 }
 
 func (h *handlersProvider) redirect(w http.ResponseWriter, urlPath string, leaderId int) {
-	if leaderId >= 0 && leaderId < len(h.raftPublicHTTPAddrs) {
-		leaderAddr := h.raftPublicHTTPAddrs[leaderId]
+	redirect(w, h.membership, urlPath, leaderId)
+}
+
+func redirect(w http.ResponseWriter, membership cluster.Membership, urlPath string, leaderId int) {
+	if leaderAddr, ok := membership.HTTPAddr(leaderId); ok {
 		redirectURL := fmt.Sprintf("%s%s", leaderAddr, urlPath)
 		w.Header().Set("Location", redirectURL)
 		w.WriteHeader(http.StatusTemporaryRedirect)
@@ -250,3 +412,175 @@ func (h *handlersProvider) redirect(w http.ResponseWriter, urlPath string, leade
 		http.Error(w, "no leader available", http.StatusServiceUnavailable)
 	}
 }
+
+// writeApplyError maps error of waiting for a command to be applied into http response.
+func writeApplyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, ftr.ErrPromiseTimeout):
+		http.Error(w, "request timed out: raft cluster is busy", http.StatusServiceUnavailable)
+	case errors.Is(err, fsm.ErrStateUnavailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, store.ErrQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+	case errors.Is(err, store.ErrKeyTooLarge), errors.Is(err, store.ErrValueTooLarge):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, store.ErrWrongType), errors.Is(err, store.ErrNotInteger), errors.Is(err, store.ErrNotANumber):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, jsondoc.ErrNotJSON), errors.Is(err, jsondoc.ErrPatchFailed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, jsondoc.ErrInvalidPatch):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, fsm.ErrLeaseNotFound), errors.Is(err, store.ErrNoSuchKey), errors.Is(err, store.ErrNoSuchNamespace),
+		errors.Is(err, cluster.ErrNoSuchMember):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, fsm.ErrNamespacedLease), errors.Is(err, store.ErrInvalidNamespace):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, store.ErrNamespaceExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, fsm.ErrLockHeld):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeReadError maps error of reading the local store into http response.
+func writeReadError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, store.ErrNoSuchKey):
+		http.NotFound(w, r)
+	case errors.Is(err, store.ErrNoSuchNamespace):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, store.ErrWrongType), errors.Is(err, jsondoc.ErrNotJSON):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, jsondoc.ErrPathNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, fsm.ErrStateUnavailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeOverloaded(w http.ResponseWriter, err error) {
+	w.Header().Set("Retry-After", "1")
+	http.Error(w, err.Error(), http.StatusServiceUnavailable)
+}
+
+// withTimeout limits ctx with timeout. Zero timeout means no limit.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// propose submits the command for keys of the request namespace and waits until it's applied.
+// Errors are written into w.
+func (h *handlersProvider) propose(w http.ResponseWriter, r *http.Request, cmd *fsm_v1.Command) (*proposer.Proposal, bool) {
+	cmd.Namespace = namespaceOf(r)
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		http.Error(w, "failed to marshal command", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	done, err := h.admission.AdmitWrite()
+	if err != nil {
+		writeOverloaded(w, err)
+		return nil, false
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(r.Context(), h.stCfg.WriteTimeout)
+	defer cancel()
+
+	res, err := h.proposer.Propose(ctx, data)
+	if err != nil {
+		writeApplyError(w, err)
+		return nil, false
+	}
+	if !res.IsLeader {
+		h.redirect(w, r.URL.RequestURI(), res.LeaderID)
+		return nil, false
+	}
+	err = res.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		writeApplyError(w, err)
+		return nil, false
+	}
+	return res, true
+}
+
+// namespaceOf returns the namespace of keys the request addresses, empty for the default namespace.
+func namespaceOf(r *http.Request) string {
+	return chi.URLParam(r, "namespace")
+}
+
+// namespaceStore returns the store of the request namespace. Unknown namespace is reported with 404.
+func (h *handlersProvider) namespaceStore(w http.ResponseWriter, r *http.Request) (store.Store, bool) {
+	st, err := h.namespaces.Store(namespaceOf(r))
+	if err != nil {
+		writeReadError(w, r, err)
+		return nil, false
+	}
+	return st, true
+}
+
+// readBarrier waits until the local store can answer the request with the consistency it asks for.
+// Errors are written into w.
+func (h *handlersProvider) readBarrier(w http.ResponseWriter, r *http.Request) bool {
+	if err := h.admission.AdmitRead(); err != nil {
+		writeOverloaded(w, err)
+		return false
+	}
+
+	ctx, cancel := withTimeout(r.Context(), h.stCfg.ReadTimeout)
+	defer cancel()
+
+	if h.applied != nil {
+		stale, err := consistency.IsStale(r.Header.Get(consistency.ModeHeader))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return false
+		}
+		if stale {
+			if staleness := consistency.Staleness(h.applied); staleness != "" {
+				w.Header().Set(consistency.StalenessHeader, staleness)
+			}
+			w.Header().Set(consistency.Header, consistency.FormatIndex(h.applied.AppliedIndex()))
+			return true
+		}
+		if token := r.Header.Get(consistency.Header); token != "" {
+			idx, err := consistency.ParseIndex(token)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return false
+			}
+			if err := consistency.Wait(ctx, h.applied, idx, h.stCfg.ReadIndexTimeout); err != nil {
+				if errors.Is(err, consistency.ErrNotApplied) || errors.Is(err, context.DeadlineExceeded) {
+					w.Header().Set("Retry-After", "1")
+					http.Error(w, err.Error(), http.StatusServiceUnavailable)
+					return false
+				}
+				writeApplyError(w, err)
+				return false
+			}
+			w.Header().Set(consistency.Header, consistency.FormatIndex(h.applied.AppliedIndex()))
+			return true
+		}
+	}
+
+	resp, err := h.raft.ReadOnly(ctx, nil)
+	if err != nil {
+		writeApplyError(w, err)
+		return false
+	}
+	if !resp.IsLeader {
+		h.redirect(w, r.URL.RequestURI(), resp.LeaderId)
+		return false
+	}
+	return true
+}
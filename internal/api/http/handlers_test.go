@@ -6,17 +6,26 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
 	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
 	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
 	metricsmocks "github.com/shrtyk/kv-store/internal/core/ports/metrics/mocks"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	storemocks "github.com/shrtyk/kv-store/internal/core/ports/store/mocks"
+	internalRaft "github.com/shrtyk/kv-store/internal/core/raft"
 	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	corestore "github.com/shrtyk/kv-store/internal/core/store"
 	"github.com/shrtyk/kv-store/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type handlerSetup struct {
@@ -39,11 +48,15 @@ func setup(t *testing.T) handlerSetup {
 
 	hp := NewHandlersProvider(
 		stCfg,
-		mockStore,
+		corestore.NewNamespaces(mockStore, stCfg, &cfg.ShardsCfg{}, logger.NewLogger("dev")),
 		mockMetrics,
 		stubRaft,
-		mockFutures,
-		addrs,
+		internalRaft.NewProposer(stubRaft, mockFutures),
+		cluster.NewRegistry(len(addrs), addrs),
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	return handlerSetup{hp, mockStore, stubRaft, mockFutures, mockMetrics, mockFuture}
@@ -66,10 +79,12 @@ func TestPutHandler(t *testing.T) {
 		rctx.URLParams.Add("key", key)
 		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 		req = req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+		req = req.WithContext(reqinfo.ToCtx(req.Context(), &reqinfo.Info{RequestID: "req-1", IP: "10.0.0.1"}))
 
 		s.hp.PutHandler(rr, req)
 
 		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get(consistency.Header))
 		s.mockStore.AssertExpectations(t)
 		s.mockMetrics.AssertExpectations(t)
 		s.mockFutures.AssertExpectations(t)
@@ -144,6 +159,68 @@ func TestPutHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
 	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		s := setup(t)
+		key, value := "testkey", "testvalue"
+
+		s.mockStore.On("Put", key, value).Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrQuotaExceeded).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		req := httptest.NewRequest(http.MethodPut, "/v1/"+key, strings.NewReader(value))
+		rr := httptest.NewRecorder()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("key", key)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+
+		s.hp.PutHandler(rr, req)
+
+		assert.Equal(t, http.StatusInsufficientStorage, rr.Code)
+	})
+
+	t.Run("overloaded", func(t *testing.T) {
+		s := setup(t)
+		s.hp.admission = admission.NewController(&cfg.AdmissionCfg{MaxInFlight: 1}, nil, nil, nil)
+		_, err := s.hp.admission.AdmitWrite()
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPut, "/v1/key", strings.NewReader("value"))
+		rr := httptest.NewRecorder()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("key", "key")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+
+		s.hp.PutHandler(rr, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	})
+}
+
+func TestHealthz_Draining(t *testing.T) {
+	s := setup(t)
+	s.hp.admission = admission.NewController(&cfg.AdmissionCfg{}, nil, nil, nil)
+
+	rr := httptest.NewRecorder()
+	s.hp.Healthz(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	s.hp.admission.Drain()
+	rr = httptest.NewRecorder()
+	s.hp.Healthz(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/key", strings.NewReader("value"))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("key", "key")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+	rr = httptest.NewRecorder()
+	s.hp.PutHandler(rr, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
 }
 
 func TestGetHandler(t *testing.T) {
@@ -206,6 +283,110 @@ func TestGetHandler(t *testing.T) {
 	})
 }
 
+func TestGetHandler_AtIndex(t *testing.T) {
+	newReq := func(key, token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/v1/"+key, nil)
+		req.Header.Set(consistency.Header, token)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("key", key)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		return req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+	}
+
+	t.Run("follower answers once index is applied", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		s.hp.applied = applied
+		key, value := "testkey", "testvalue"
+
+		applied.On("WaitApplied", mock.Anything, int64(7)).Return(nil).Once()
+		applied.On("AppliedIndex").Return(int64(9)).Once()
+		s.mockStore.On("Get", key).Return(value, nil).Once()
+		s.mockMetrics.On("HttpGet", key, mock.Anything).Return().Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newReq(key, "7"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, value, rr.Body.String())
+		assert.Equal(t, "9", rr.Header().Get(consistency.Header))
+	})
+
+	t.Run("index not applied in time", func(t *testing.T) {
+		s := setup(t)
+		s.hp.stCfg.ReadIndexTimeout = time.Millisecond
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		s.hp.applied = applied
+
+		applied.EXPECT().WaitApplied(mock.Anything, int64(7)).RunAndReturn(func(ctx context.Context, _ int64) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Once()
+		applied.On("AppliedIndex").Return(int64(3)).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newReq("testkey", "7"))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		s := setup(t)
+		s.hp.applied = fsmmocks.NewMockAppliedIndex(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newReq("testkey", "latest"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetHandler_Stale(t *testing.T) {
+	newReq := func(key, mode string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/v1/"+key, nil)
+		req.Header.Set(consistency.ModeHeader, mode)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("key", key)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		return req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+	}
+
+	t.Run("follower answers from local state", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+		applied := fsmmocks.NewMockAppliedIndex(t)
+		s.hp.applied = applied
+		key, value := "testkey", "testvalue"
+
+		applied.On("AppliedAt").Return(time.Now().Add(-time.Second)).Once()
+		applied.On("AppliedIndex").Return(int64(9)).Once()
+		s.mockStore.On("Get", key).Return(value, nil).Once()
+		s.mockMetrics.On("HttpGet", key, mock.Anything).Return().Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newReq(key, consistency.ModeStale))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, value, rr.Body.String())
+		assert.Equal(t, "9", rr.Header().Get(consistency.Header))
+		staleness, err := time.ParseDuration(rr.Header().Get(consistency.StalenessHeader))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, staleness, time.Second)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		s := setup(t)
+		s.hp.applied = fsmmocks.NewMockAppliedIndex(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newReq("testkey", "eventual"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
 func TestDeleteHandler(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		s := setup(t)
@@ -0,0 +1,229 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// HGetAllHandler godoc
+// @Summary      Gets all fields of a hash
+// @Description  Gets all fields of a hash as a JSON object
+// @Tags         hashes
+// @Produce      json
+// @Param        key path string true "key"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
+// @Success      200 {object} map[string]string
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      404
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/fields [get]
+func (h *handlersProvider) HGetAllHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	if !h.readBarrier(w, r) {
+		return
+	}
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	fields, err := st.HGetAll(key)
+	if err != nil {
+		writeReadError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(fields); err != nil {
+		logger.FromCtx(r.Context()).Error("failed to write hash", logger.ErrorAttr(err))
+	}
+}
+
+// HGetHandler godoc
+// @Summary      Gets a field of a hash
+// @Description  Gets a field of a hash
+// @Tags         hashes
+// @Produce      text/plain
+// @Param        key path string true "key"
+// @Param        field path string true "field"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
+// @Success      200 {string} string "value"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      404
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/fields/{field} [get]
+func (h *handlersProvider) HGetHandler(w http.ResponseWriter, r *http.Request) {
+	key, field := chi.URLParam(r, "key"), chi.URLParam(r, "field")
+	if !h.readBarrier(w, r) {
+		return
+	}
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	val, err := st.HGet(key, field)
+	if err != nil {
+		writeReadError(w, r, err)
+		return
+	}
+	if _, err := io.WriteString(w, val); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HSetHandler godoc
+// @Summary      Sets a field of a hash
+// @Description  Sets a field of a hash, creating the hash if needed
+// @Tags         hashes
+// @Accept       text/plain
+// @Param        key path string true "key"
+// @Param        field path string true "field"
+// @Param        value body string true "value"
+// @Success      201
+// @Header       201 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/fields/{field} [put]
+func (h *handlersProvider) HSetHandler(w http.ResponseWriter, r *http.Request) {
+	key, field := chi.URLParam(r, "key"), chi.URLParam(r, "field")
+	val, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(key) > h.stCfg.MaxKeySize || len(field) > h.stCfg.MaxKeySize {
+		http.Error(w, store.ErrKeyTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(val) > h.stCfg.MaxValSize {
+		http.Error(w, store.ErrValueTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Hset{Hset: &fsm_v1.HSetCommand{
+		Key:    key,
+		Fields: map[string]string{field: string(val)},
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.WriteHeader(http.StatusCreated)
+	logger.FromCtx(r.Context()).Debug("HSet operation successfully completed", slog.String("key", key))
+}
+
+// HDelHandler godoc
+// @Summary      Deletes a field of a hash
+// @Description  Deletes a field of a hash. Hash without fields is deleted
+// @Tags         hashes
+// @Param        key path string true "key"
+// @Param        field path string true "field"
+// @Success      204
+// @Header       204 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/fields/{field} [delete]
+func (h *handlersProvider) HDelHandler(w http.ResponseWriter, r *http.Request) {
+	key, field := chi.URLParam(r, "key"), chi.URLParam(r, "field")
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Hdel{Hdel: &fsm_v1.HDelCommand{
+		Key:    key,
+		Fields: []string{field},
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HIncrByHandler godoc
+// @Summary      Increments a field of a hash
+// @Description  Adds a number to the integer value of a hash field, missing field counts as 0
+// @Tags         hashes
+// @Produce      text/plain
+// @Param        key path string true "key"
+// @Param        field path string true "field"
+// @Param        by query int false "Increment, 1 by default"
+// @Success      200 {string} string "New value"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind or the field isn't an integer"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/fields/{field}/incr [post]
+func (h *handlersProvider) HIncrByHandler(w http.ResponseWriter, r *http.Request) {
+	key, field := chi.URLParam(r, "key"), chi.URLParam(r, "field")
+	delta := int64(1)
+	if by := r.URL.Query().Get("by"); by != "" {
+		var err error
+		if delta, err = strconv.ParseInt(by, 10, 64); err != nil {
+			http.Error(w, "invalid increment", http.StatusBadRequest)
+			return
+		}
+	}
+	if len(key) > h.stCfg.MaxKeySize || len(field) > h.stCfg.MaxKeySize {
+		http.Error(w, store.ErrKeyTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Hincrby{Hincrby: &fsm_v1.HIncrByCommand{
+		Key:   key,
+		Field: field,
+		Delta: delta,
+	}}})
+	if !ok {
+		return
+	}
+
+	data := res.Future.Data()
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	if _, err := w.Write(data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,175 @@
+package httphandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newFieldRequest(method, key, field, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("key", key)
+	if field != "" {
+		rctx.URLParams.Add("field", field)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+}
+
+func TestHSetHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("HSet", "h", map[string]string{"f": "v"}, mock.Anything).Return(1, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.HSetHandler(rr, newFieldRequest(http.MethodPut, "h", "f", "/v1/h/fields/f", "v"))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get(consistency.Header))
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("HSet", "h", map[string]string{"f": "v"}, mock.Anything).Return(0, store.ErrWrongType).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrWrongType).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.HSetHandler(rr, newFieldRequest(http.MethodPut, "h", "f", "/v1/h/fields/f", "v"))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("field too large", func(t *testing.T) {
+		s := setup(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.HSetHandler(rr, newFieldRequest(http.MethodPut, "h", "thisfieldistoolarge", "/v1/h/fields/x", "v"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestHGetHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("HGet", "h", "f").Return("v", nil).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.HGetHandler(rr, newFieldRequest(http.MethodGet, "h", "f", "/v1/h/fields/f", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "v", rr.Body.String())
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("HGet", "h", "f").Return("", store.ErrNoSuchKey).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.HGetHandler(rr, newFieldRequest(http.MethodGet, "h", "f", "/v1/h/fields/f", ""))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("HGet", "s", "f").Return("", store.ErrWrongType).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.HGetHandler(rr, newFieldRequest(http.MethodGet, "s", "f", "/v1/s/fields/f", ""))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("not leader", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+
+		rr := httptest.NewRecorder()
+		s.hp.HGetHandler(rr, newFieldRequest(http.MethodGet, "h", "f", "/v1/h/fields/f", ""))
+
+		assert.Equal(t, http.StatusTemporaryRedirect, rr.Code)
+		assert.Equal(t, "http://leader:8080/v1/h/fields/f", rr.Header().Get("Location"))
+	})
+}
+
+func TestHGetAllHandler(t *testing.T) {
+	s := setup(t)
+	s.mockStore.On("HGetAll", "h").Return(map[string]string{"a": "1", "b": "2"}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.HGetAllHandler(rr, newFieldRequest(http.MethodGet, "h", "", "/v1/h/fields", ""))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"a":"1","b":"2"}`, rr.Body.String())
+}
+
+func TestHDelHandler(t *testing.T) {
+	s := setup(t)
+
+	s.mockStore.On("HDel", "h", []string{"f"}, mock.Anything).Return(1, nil).Once()
+	s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+	s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.HDelHandler(rr, newFieldRequest(http.MethodDelete, "h", "f", "/v1/h/fields/f", ""))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	s.mockStore.AssertExpectations(t)
+}
+
+func TestHIncrByHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("HIncrBy", "h", "n", int64(-3), mock.Anything).Return(int64(4), nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("4")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.HIncrByHandler(rr, newFieldRequest(http.MethodPost, "h", "n", "/v1/h/fields/n/incr?by=-3", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "4", rr.Body.String())
+	})
+
+	t.Run("invalid increment", func(t *testing.T) {
+		s := setup(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.HIncrByHandler(rr, newFieldRequest(http.MethodPost, "h", "n", "/v1/h/fields/n/incr?by=x", ""))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("not an integer", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("HIncrBy", "h", "n", int64(1), mock.Anything).Return(int64(0), store.ErrNotInteger).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrNotInteger).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.HIncrByHandler(rr, newFieldRequest(http.MethodPost, "h", "n", "/v1/h/fields/n/incr", ""))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
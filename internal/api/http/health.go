@@ -0,0 +1,49 @@
+package httphandlers
+
+import (
+	"net/http"
+
+	"github.com/shrtyk/kv-store/internal/core/health"
+)
+
+type healthHandlers struct {
+	checker *health.Checker
+}
+
+// NewHealthHandlers returns handlers of liveness and readiness probes.
+func NewHealthHandlers(checker *health.Checker) *healthHandlers {
+	return &healthHandlers{checker: checker}
+}
+
+// Livez godoc
+// @Summary      Liveness probe
+// @Description  Reports whether the node process is able to make progress. It doesn't depend on other nodes.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} health.Status
+// @Failure      503 {object} health.Status
+// @Router       /livez [get]
+func (h *healthHandlers) Livez(w http.ResponseWriter, r *http.Request) {
+	st := h.checker.Live()
+	writeJSON(w, statusCode(st.Live), st)
+}
+
+// Readyz godoc
+// @Summary      Readiness probe
+// @Description  Reports whether the node should receive client traffic: it knows a leader (the leader confirms its quorum), applies committed entries in time, isn't restoring a snapshot and isn't draining.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} health.Status
+// @Failure      503 {object} health.Status
+// @Router       /readyz [get]
+func (h *healthHandlers) Readyz(w http.ResponseWriter, r *http.Request) {
+	st := h.checker.Ready(r.Context())
+	writeJSON(w, statusCode(st.Ready), st)
+}
+
+func statusCode(ok bool) int {
+	if ok {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
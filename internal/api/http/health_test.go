@@ -0,0 +1,35 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/health"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	h := NewHealthHandlers(health.NewChecker(&cfg.HealthCfg{}, rmocks.NewStubRaft(nil, false, 1), nil, nil, nil, nil))
+
+	rr := httptest.NewRecorder()
+	h.Readyz(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var st health.Status
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&st))
+	assert.True(t, st.Ready)
+	assert.Equal(t, 1, st.LeaderID)
+
+	h = NewHealthHandlers(health.NewChecker(&cfg.HealthCfg{}, rmocks.NewStubRaft(nil, false, -1), nil, nil, nil, nil))
+	rr = httptest.NewRecorder()
+	h.Readyz(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.Livez(rr, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,133 @@
+package httphandlers
+
+import (
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// Media types of patches accepted by PatchHandler.
+const (
+	jsonPatchType  = "application/json-patch+json"
+	mergePatchType = "application/merge-patch+json"
+)
+
+// PatchHandler godoc
+// @Summary      Patches a JSON document
+// @Description  Applies a JSON Patch (RFC 6902) or a JSON Merge Patch (RFC 7386) to the JSON document at key
+// @Description  as a whole or not at all. A missing key is patched as null. The document is stored with sorted members
+// @Tags         store
+// @Accept       application/json-patch+json,application/merge-patch+json
+// @Produce      json
+// @Param        key path string true "key"
+// @Param        patch body string true "patch"
+// @Success      200 {string} string "Patched document"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Malformed patch or wrong input data"
+// @Failure      409 {string} string "Value isn't a JSON document or the patch can't be applied to it"
+// @Failure      415 {string} string "Unsupported patch media type"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key} [patch]
+func (h *handlersProvider) PatchHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	var typ jsondoc.PatchType
+	switch {
+	case hasMediaType(r, jsonPatchType):
+		typ = jsondoc.PatchJSON
+	case hasMediaType(r, mergePatchType):
+		typ = jsondoc.PatchMerge
+	default:
+		w.Header().Set("Accept-Patch", jsonPatchType+", "+mergePatchType)
+		http.Error(w, "unsupported patch media type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(key) > h.stCfg.MaxKeySize {
+		http.Error(w, store.ErrKeyTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(patch) > h.stCfg.MaxValSize {
+		http.Error(w, store.ErrValueTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := jsondoc.CheckPatch(patch, typ); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_JsonPatch{JsonPatch: &fsm_v1.JsonPatchCommand{
+		Key:   key,
+		Patch: patch,
+		Type:  fsm_v1.PatchType(typ),
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(res.Future.Data()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	logger.FromCtx(r.Context()).Debug(
+		"Patch operation successfully completed",
+		slog.String("key", key),
+		slog.String("type", typ.String()))
+}
+
+// getJSONPath answers GetHandler requests with a path.
+func (h *handlersProvider) getJSONPath(w http.ResponseWriter, r *http.Request, key, path string) {
+	if err := jsondoc.CheckPath(path); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.readBarrier(w, r) {
+		return
+	}
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	val, err := st.Get(key)
+	if err != nil {
+		writeReadError(w, r, err)
+		return
+	}
+	doc, err := jsondoc.Get([]byte(val), path)
+	if err != nil {
+		writeReadError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(doc); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// hasMediaType reports whether the request body has the media type, ignoring its parameters.
+func hasMediaType(r *http.Request, mediaType string) bool {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mt == mediaType
+}
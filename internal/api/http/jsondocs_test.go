@@ -0,0 +1,140 @@
+package httphandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPutHandler_JSON(t *testing.T) {
+	s := setup(t)
+
+	req := newFieldRequest(http.MethodPut, "doc", "", "/v1/doc", `{"a":`)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rr := httptest.NewRecorder()
+	s.hp.PutHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), jsondoc.ErrNotJSON.Error())
+}
+
+func TestGetHandler_JSONPath(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Get", "doc").Return(`{"a":{"b":[1,2]}}`, nil).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newFieldRequest(http.MethodGet, "doc", "", "/v1/doc?path=$.a.b[1]", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "2", rr.Body.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for path, code := range map[string]int{"$.x": http.StatusNotFound, "a": http.StatusBadRequest} {
+			s := setup(t)
+			s.mockStore.On("Get", "doc").Return(`{"a":1}`, nil).Maybe()
+
+			rr := httptest.NewRecorder()
+			s.hp.GetHandler(rr, newFieldRequest(http.MethodGet, "doc", "", "/v1/doc?path="+path, ""))
+
+			assert.Equal(t, code, rr.Code, path)
+		}
+	})
+
+	t.Run("not a document", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Get", "doc").Return("text", nil).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newFieldRequest(http.MethodGet, "doc", "", "/v1/doc?path=$", ""))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("not leader keeps the path", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+
+		rr := httptest.NewRecorder()
+		s.hp.GetHandler(rr, newFieldRequest(http.MethodGet, "doc", "", "/v1/doc?path=$.a", ""))
+
+		assert.Equal(t, http.StatusTemporaryRedirect, rr.Code)
+		assert.Equal(t, "http://leader:8080/v1/doc?path=$.a", rr.Header().Get("Location"))
+	})
+}
+
+func TestPatchHandler(t *testing.T) {
+	patches := map[string]string{
+		jsonPatchType:  `[{"op":"add","path":"/b","value":2}]`,
+		mergePatchType: `{"b":2}`,
+	}
+	for mediaType, patch := range patches {
+		t.Run(mediaType, func(t *testing.T) {
+			s := setup(t)
+			s.hp.stCfg.MaxValSize = 100
+
+			s.mockStore.On("Lookup", "doc", mock.Anything).Return(store.Entry{Key: "doc", Value: `{"a":1}`, Flags: 3}, nil).Once()
+			s.mockStore.On("PutEntry", store.Entry{Key: "doc", Value: `{"a":1,"b":2}`, Flags: 3}).Return(nil).Once()
+			s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+			s.mockFuture.On("Data").Return([]byte(`{"a":1,"b":2}`)).Once()
+			s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+			req := newFieldRequest(http.MethodPatch, "doc", "", "/v1/doc", patch)
+			req.Header.Set("Content-Type", mediaType)
+			rr := httptest.NewRecorder()
+			s.hp.PatchHandler(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "1", rr.Header().Get(consistency.Header))
+			assert.Equal(t, `{"a":1,"b":2}`, rr.Body.String())
+			s.mockStore.AssertExpectations(t)
+		})
+	}
+
+	t.Run("patch fails", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("Lookup", "doc", mock.Anything).Return(store.Entry{Key: "doc", Value: `{}`}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(jsondoc.ErrPatchFailed).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		req := newFieldRequest(http.MethodPatch, "doc", "", "/v1/doc", `[{"op":"remove","path":"/a"}]`)
+		req.Header.Set("Content-Type", jsonPatchType)
+		s.hp.stCfg.MaxValSize = 100
+		rr := httptest.NewRecorder()
+		s.hp.PatchHandler(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("malformed patch", func(t *testing.T) {
+		s := setup(t)
+
+		req := newFieldRequest(http.MethodPatch, "doc", "", "/v1/doc", `{"op":"add"}`)
+		req.Header.Set("Content-Type", jsonPatchType)
+		rr := httptest.NewRecorder()
+		s.hp.PatchHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		s := setup(t)
+
+		req := newFieldRequest(http.MethodPatch, "doc", "", "/v1/doc", `{"b":2}`)
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		s.hp.PatchHandler(rr, req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+		assert.Contains(t, rr.Header().Get("Accept-Patch"), mergePatchType)
+	})
+}
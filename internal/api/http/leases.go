@@ -0,0 +1,315 @@
+package httphandlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// LeaseGrantHandler godoc
+// @Summary      Grants a lease
+// @Description  Grants a lease expiring after ttl unless it's kept alive. The deadline is assigned by the leader
+// @Tags         leases
+// @Produce      text/plain
+// @Param        ttl query string true "Lease TTL as a duration, e.g. 10s"
+// @Success      201 {string} string "Lease ID"
+// @Header       201 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /leases [post]
+func (h *handlersProvider) LeaseGrantHandler(w http.ResponseWriter, r *http.Request) {
+	ttl, err := time.ParseDuration(r.URL.Query().Get("ttl"))
+	if err != nil || ttl < time.Millisecond {
+		http.Error(w, "invalid lease ttl", http.StatusBadRequest)
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseGrant{LeaseGrant: &fsm_v1.LeaseGrantCommand{
+		TtlMs: ttl.Milliseconds(),
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write(res.Future.Data()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// LeaseHandler godoc
+// @Summary      Gets a lease
+// @Description  Gets TTL, deadline and attached keys of a live lease
+// @Tags         leases
+// @Produce      json
+// @Param        id path int true "Lease ID"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
+// @Success      200 {object} fsm.Lease
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      404 {string} string "Lease not found or expired"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /leases/{id} [get]
+func (h *handlersProvider) LeaseHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseLeaseID(w, chi.URLParam(r, "id"))
+	if !ok || !h.readBarrier(w, r) {
+		return
+	}
+
+	if h.leases == nil {
+		http.Error(w, fsm.ErrLeaseNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	lease, ok := h.leases.Lease(id, time.Now().UnixMilli())
+	if !ok {
+		http.Error(w, fsm.ErrLeaseNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, lease)
+}
+
+// LeaseKeepAliveHandler godoc
+// @Summary      Keeps a lease alive
+// @Description  Moves the deadline of a live lease to now plus its TTL
+// @Tags         leases
+// @Produce      text/plain
+// @Param        id path int true "Lease ID"
+// @Success      200 {string} string "New deadline in unix milliseconds"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      404 {string} string "Lease not found or expired"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /leases/{id}/keepalive [post]
+func (h *handlersProvider) LeaseKeepAliveHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseLeaseID(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseKeepAlive{LeaseKeepAlive: &fsm_v1.LeaseKeepAliveCommand{
+		Id: id,
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	if _, err := w.Write(res.Future.Data()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// LeaseRevokeHandler godoc
+// @Summary      Revokes a lease
+// @Description  Revokes a lease deleting keys attached to it
+// @Tags         leases
+// @Produce      text/plain
+// @Param        id path int true "Lease ID"
+// @Success      200 {string} string "Number of deleted keys"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      404 {string} string "Lease not found"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /leases/{id} [delete]
+func (h *handlersProvider) LeaseRevokeHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseLeaseID(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseRevoke{LeaseRevoke: &fsm_v1.LeaseRevokeCommand{
+		Id: id,
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	if _, err := w.Write(res.Future.Data()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// LeaseAttachHandler godoc
+// @Summary      Attaches a key to a lease
+// @Description  Attaches an existing key to a live lease, so it's deleted with the lease.
+// @Description  Writing or deleting the key detaches it
+// @Tags         leases
+// @Param        key path string true "key"
+// @Param        id query int true "Lease ID"
+// @Success      204
+// @Header       204 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      404 {string} string "Key or lease not found"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/lease [put]
+func (h *handlersProvider) LeaseAttachHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	id, ok := parseLeaseID(w, r.URL.Query().Get("id"))
+	if !ok {
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseAttach{LeaseAttach: &fsm_v1.LeaseAttachCommand{
+		Id:   id,
+		Keys: []string{key},
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LockHandler godoc
+// @Summary      Acquires a lock
+// @Description  Acquires the lock at key for a live lease. The lock is released when the lease is revoked or expires.
+// @Description  Returns a fencing token, a unique id given to the acquiring write, which grows with every new holder.
+// @Description  Acquiring a lock the lease already holds returns the same token
+// @Tags         leases
+// @Produce      text/plain
+// @Param        key path string true "key"
+// @Param        lease query int true "Lease ID"
+// @Success      200 {string} string "Fencing token"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      404 {string} string "Lease not found or expired"
+// @Failure      409 {string} string "Lock is held by another lease"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/lock [post]
+func (h *handlersProvider) LockHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	id, ok := parseLeaseID(w, r.URL.Query().Get("lease"))
+	if !ok {
+		return
+	}
+	if len(key) > h.stCfg.MaxKeySize {
+		http.Error(w, store.ErrKeyTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Lock{Lock: &fsm_v1.LockCommand{
+		Key:   key,
+		Lease: id,
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	if _, err := w.Write(res.Future.Data()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// UnlockHandler godoc
+// @Summary      Releases a lock
+// @Description  Releases the lock at key held by the lease
+// @Tags         leases
+// @Param        key path string true "key"
+// @Param        lease query int true "Lease ID"
+// @Success      204
+// @Header       204 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      404 {string} string "Lock isn't held"
+// @Failure      409 {string} string "Lock is held by another lease"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/lock [delete]
+func (h *handlersProvider) UnlockHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	id, ok := parseLeaseID(w, r.URL.Query().Get("lease"))
+	if !ok {
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Unlock{Unlock: &fsm_v1.UnlockCommand{
+		Key:   key,
+		Lease: id,
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NamespacedLeaseHandler godoc
+// @Summary      Rejects leases and locks of namespaced keys
+// @Description  Leases and locks exist in the default namespace only, so attaching keys of other namespaces
+// @Description  to leases and locking them is rejected
+// @Tags         leases
+// @Param        namespace path string true "namespace"
+// @Param        key path string true "key"
+// @Failure      400 {string} string "Leases and locks exist in the default namespace only"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Security     BearerAuth
+// @Router       /v1/ns/{namespace}/{key}/lease [put]
+// @Router       /v1/ns/{namespace}/{key}/lock [post]
+// @Router       /v1/ns/{namespace}/{key}/lock [delete]
+func (h *handlersProvider) NamespacedLeaseHandler(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, fsm.ErrNamespacedLease.Error(), http.StatusBadRequest)
+}
+
+// parseLeaseID parses a lease ID. Errors are written into w.
+func parseLeaseID(w http.ResponseWriter, s string) (int64, bool) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid lease id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,134 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newLeaseRequest builds a request to a /leases/{id} route.
+func newLeaseRequest(method, id, path string) *http.Request {
+	req := newFieldRequest(method, "", "", path, "")
+	chi.RouteContext(req.Context()).URLParams.Add("id", id)
+	return req
+}
+
+func TestLeaseGrantHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("1")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.LeaseGrantHandler(rr, newFieldRequest(http.MethodPost, "", "", "/leases?ttl=10s", ""))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "1", rr.Body.String())
+		assert.Equal(t, "1", rr.Header().Get(consistency.Header))
+	})
+
+	t.Run("invalid ttl", func(t *testing.T) {
+		for _, ttl := range []string{"", "abc", "0s", "-1s", "10us"} {
+			s := setup(t)
+			rr := httptest.NewRecorder()
+			s.hp.LeaseGrantHandler(rr, newFieldRequest(http.MethodPost, "", "", "/leases?ttl="+ttl, ""))
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, ttl)
+		}
+	})
+}
+
+func TestLeaseHandler(t *testing.T) {
+	s := setup(t)
+	leases := fsmmocks.NewMockLeaseReader(t)
+	s.hp.leases = leases
+	lease := fsm.Lease{ID: 3, TTLMillis: 1000, ExpiresAt: time.UnixMilli(5000).UTC(), Keys: []string{"a"}}
+	leases.EXPECT().Lease(int64(3), mock.Anything).Return(lease, true).Once()
+	leases.EXPECT().Lease(int64(4), mock.Anything).Return(fsm.Lease{}, false).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.LeaseHandler(rr, newLeaseRequest(http.MethodGet, "3", "/leases/3"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got fsm.Lease
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, lease, got)
+
+	rr = httptest.NewRecorder()
+	s.hp.LeaseHandler(rr, newLeaseRequest(http.MethodGet, "4", "/leases/4"))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	s.hp.LeaseHandler(rr, newLeaseRequest(http.MethodGet, "x", "/leases/x"))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestLeaseKeepAliveHandler_NotFound(t *testing.T) {
+	s := setup(t)
+	s.mockFuture.On("Wait", mock.Anything).Return(fsm.ErrLeaseNotFound).Once()
+	s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.LeaseKeepAliveHandler(rr, newLeaseRequest(http.MethodPost, "3", "/leases/3/keepalive"))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestLockHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Put", "lock", "1").Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("1")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.LockHandler(rr, newFieldRequest(http.MethodPost, "lock", "", "/v1/lock/lock?lease=3", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "1", rr.Body.String())
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("held by another lease", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("Put", "lock", "1").Return(nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(fsm.ErrLockHeld).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.LockHandler(rr, newFieldRequest(http.MethodPost, "lock", "", "/v1/lock/lock?lease=3", ""))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("missing lease", func(t *testing.T) {
+		s := setup(t)
+		rr := httptest.NewRecorder()
+		s.hp.LockHandler(rr, newFieldRequest(http.MethodPost, "lock", "", "/v1/lock/lock", ""))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestUnlockHandler(t *testing.T) {
+	s := setup(t)
+	s.mockStore.On("Delete", "lock").Return(nil).Once()
+	s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+	s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.UnlockHandler(rr, newFieldRequest(http.MethodDelete, "lock", "", "/v1/lock/lock?lease=3", ""))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
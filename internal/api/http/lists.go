@@ -0,0 +1,250 @@
+package httphandlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/api/lists"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// errPopFailed stops a blocking pop after a failed attempt already wrote its response.
+var errPopFailed = errors.New("pop failed")
+
+// PushHandler godoc
+// @Summary      Pushes a value to a list
+// @Description  Pushes a value to the tail (right, default) or the head (left) of a list, creating the list if needed
+// @Tags         lists
+// @Accept       text/plain
+// @Produce      text/plain
+// @Param        key path string true "key"
+// @Param        side query string false "End of the list" Enums(left, right)
+// @Param        value body string true "value"
+// @Success      200 {string} string "New length of the list"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/list [post]
+func (h *handlersProvider) PushHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	left, err := lists.ParseSide(r.URL.Query().Get("side"), false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	val, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(key) > h.stCfg.MaxKeySize {
+		http.Error(w, store.ErrKeyTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(val) > h.stCfg.MaxValSize {
+		http.Error(w, store.ErrValueTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Push{Push: &fsm_v1.PushCommand{
+		Key:    key,
+		Values: []string{string(val)},
+		Left:   left,
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	if _, err := w.Write(res.Future.Data()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// PopHandler godoc
+// @Summary      Pops values from a list
+// @Description  Pops values from the head (left, default) or the tail (right) of a list. With wait
+// @Description  an empty list is watched until a value is pushed or the wait is over. Wait is capped
+// @Description  by the store max_pop_wait setting and only the leader waits
+// @Tags         lists
+// @Produce      json
+// @Param        key path string true "key"
+// @Param        side query string false "End of the list" Enums(left, right)
+// @Param        count query int false "Maximum number of values, 1 by default"
+// @Param        wait query string false "How long to wait for a value, e.g. 5s"
+// @Success      200 {array} string "Popped values, empty if the list stayed empty"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/list/pop [post]
+func (h *handlersProvider) PopHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	q := r.URL.Query()
+	left, err := lists.ParseSide(q.Get("side"), true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	count := int64(1)
+	if c := q.Get("count"); c != "" {
+		if count, err = strconv.ParseInt(c, 10, 64); err != nil || count < 1 {
+			http.Error(w, "invalid count", http.StatusBadRequest)
+			return
+		}
+	}
+	var wait time.Duration
+	if d := q.Get("wait"); d != "" {
+		if wait, err = time.ParseDuration(d); err != nil || wait < 0 {
+			http.Error(w, "invalid wait", http.StatusBadRequest)
+			return
+		}
+		wait = min(wait, h.stCfg.MaxPopWait)
+	}
+
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	var logIdx int64
+	failed := false
+	vals, err := lists.BlockingPop(r.Context(), h.pushes, key, wait,
+		func() bool {
+			n, err := st.LLen(key)
+			return n > 0 || (err != nil && err != store.ErrNoSuchKey)
+		},
+		func() ([]string, error) {
+			res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Pop{Pop: &fsm_v1.PopCommand{
+				Key:   key,
+				Count: count,
+				Left:  left,
+			}}})
+			if !ok {
+				failed = true
+				return nil, errPopFailed
+			}
+			logIdx = res.LogIndex
+			return lists.DecodePop(res.Future.Data())
+		})
+	if failed {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if logIdx > 0 {
+		w.Header().Set(consistency.Header, consistency.FormatIndex(logIdx))
+	}
+	writeJSON(w, http.StatusOK, append([]string{}, vals...))
+}
+
+// LRangeHandler godoc
+// @Summary      Gets values of a list
+// @Description  Gets values of a list between start and stop inclusive. Negative indexes count from the tail
+// @Tags         lists
+// @Produce      json
+// @Param        key path string true "key"
+// @Param        start query int false "First index, 0 by default"
+// @Param        stop query int false "Last index, -1 by default"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
+// @Success      200 {array} string "Values, empty for a missing list"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/list [get]
+func (h *handlersProvider) LRangeHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	start, stop := 0, -1
+	for name, dst := range map[string]*int{"start": &start, "stop": &stop} {
+		if v := r.URL.Query().Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, "invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+	if !h.readBarrier(w, r) {
+		return
+	}
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	vals, err := st.LRange(key, start, stop)
+	if err != nil && err != store.ErrNoSuchKey {
+		writeReadError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, append([]string{}, vals...))
+}
+
+// LLenHandler godoc
+// @Summary      Gets length of a list
+// @Description  Gets length of a list, 0 for a missing list
+// @Tags         lists
+// @Produce      text/plain
+// @Param        key path string true "key"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
+// @Success      200 {string} string "Length"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/list/len [get]
+func (h *handlersProvider) LLenHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	if !h.readBarrier(w, r) {
+		return
+	}
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := st.LLen(key)
+	if err != nil && err != store.ErrNoSuchKey {
+		writeReadError(w, r, err)
+		return
+	}
+	if _, err := io.WriteString(w, strconv.Itoa(n)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
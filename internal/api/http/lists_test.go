@@ -0,0 +1,194 @@
+package httphandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func popResult(t *testing.T, vals ...string) []byte {
+	data, err := proto.Marshal(&fsm_v1.PopResult{Values: vals})
+	require.NoError(t, err)
+	return data
+}
+
+func TestPushHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("Push", "l", []string{"v"}, true, mock.Anything).Return(3, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("3")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.PushHandler(rr, newFieldRequest(http.MethodPost, "l", "", "/v1/l/list?side=left", "v"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "3", rr.Body.String())
+		assert.Equal(t, "1", rr.Header().Get(consistency.Header))
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid side", func(t *testing.T) {
+		s := setup(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.PushHandler(rr, newFieldRequest(http.MethodPost, "l", "", "/v1/l/list?side=up", "v"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("Push", "s", []string{"v"}, false, mock.Anything).Return(0, store.ErrWrongType).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrWrongType).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.PushHandler(rr, newFieldRequest(http.MethodPost, "s", "", "/v1/s/list", "v"))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
+
+func TestPopHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("Pop", "l", 2, false, mock.Anything).Return([]string{"b", "a"}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return(popResult(t, "b", "a")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.PopHandler(rr, newFieldRequest(http.MethodPost, "l", "", "/v1/l/list/pop?side=right&count=2", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `["b","a"]`, rr.Body.String())
+		assert.Equal(t, "1", rr.Header().Get(consistency.Header))
+	})
+
+	t.Run("waits for a push", func(t *testing.T) {
+		s := setup(t)
+		watcher := fsmmocks.NewMockPushWatcher(t)
+		s.hp.pushes = watcher
+		s.hp.stCfg.MaxPopWait = time.Second
+
+		pushed := make(chan struct{})
+		close(pushed)
+		watcher.On("WatchPushes", "l").Return((<-chan struct{})(pushed), func() {}).Twice()
+		s.mockStore.On("Pop", "l", 1, true, mock.Anything).Return([]string{}, nil).Once()
+		s.mockStore.On("LLen", "l").Return(1, nil).Once()
+		s.mockStore.On("Pop", "l", 1, true, mock.Anything).Return([]string{"a"}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Twice()
+		s.mockFuture.On("Data").Return(popResult(t)).Once()
+		s.mockFuture.On("Data").Return(popResult(t, "a")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Twice()
+
+		rr := httptest.NewRecorder()
+		s.hp.PopHandler(rr, newFieldRequest(http.MethodPost, "l", "", "/v1/l/list/pop?wait=1m", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `["a"]`, rr.Body.String())
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("times out on empty list", func(t *testing.T) {
+		s := setup(t)
+		watcher := fsmmocks.NewMockPushWatcher(t)
+		s.hp.pushes = watcher
+		s.hp.stCfg.MaxPopWait = 20 * time.Millisecond
+
+		watcher.On("WatchPushes", "l").Return(make(<-chan struct{}), func() {}).Once()
+		s.mockStore.On("Pop", "l", 1, true, mock.Anything).Return([]string{}, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return(popResult(t)).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		start := time.Now()
+		s.hp.PopHandler(rr, newFieldRequest(http.MethodPost, "l", "", "/v1/l/list/pop?wait=1h", ""))
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[]`, rr.Body.String())
+	})
+
+	t.Run("not leader", func(t *testing.T) {
+		s := setup(t)
+		s.stubRaft.SetLeader(false)
+
+		rr := httptest.NewRecorder()
+		s.hp.PopHandler(rr, newFieldRequest(http.MethodPost, "l", "", "/v1/l/list/pop", ""))
+
+		assert.Equal(t, http.StatusTemporaryRedirect, rr.Code)
+	})
+
+	t.Run("invalid count", func(t *testing.T) {
+		s := setup(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.PopHandler(rr, newFieldRequest(http.MethodPost, "l", "", "/v1/l/list/pop?count=0", ""))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestLRangeHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("LRange", "l", 1, -2).Return([]string{"b", "c"}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.LRangeHandler(rr, newFieldRequest(http.MethodGet, "l", "", "/v1/l/list?start=1&stop=-2", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `["b","c"]`, rr.Body.String())
+	})
+
+	t.Run("missing list", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("LRange", "l", 0, -1).Return(nil, store.ErrNoSuchKey).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.LRangeHandler(rr, newFieldRequest(http.MethodGet, "l", "", "/v1/l/list", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[]`, rr.Body.String())
+	})
+}
+
+func TestLLenHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("LLen", "l").Return(4, nil).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.LLenHandler(rr, newFieldRequest(http.MethodGet, "l", "", "/v1/l/list/len", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "4", rr.Body.String())
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("LLen", "s").Return(0, store.ErrWrongType).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.LLenHandler(rr, newFieldRequest(http.MethodGet, "s", "", "/v1/s/list/len", ""))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
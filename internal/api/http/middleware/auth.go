@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	"github.com/shrtyk/kv-store/pkg/logger"
+)
+
+type authMws struct {
+	auth    *auth.Service
+	limiter *ratelimit.Limiter
+}
+
+// NewAuthMiddlewares creates auth middlewares. Failed authentications are limited by the limiter, which may be nil.
+func NewAuthMiddlewares(a *auth.Service, l *ratelimit.Limiter) *authMws {
+	return &authMws{auth: a, limiter: l}
+}
+
+// Authenticate resolves the bearer credential into a principal
+// and rejects the request with 401 if it is missing or invalid. Unauthenticated clients
+// are limited by ip as readers, so credentials can't be guessed at full speed.
+func (m *authMws) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.auth.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p, err := m.auth.Authenticate(auth.BearerToken(r.Header.Get("Authorization")))
+		if err != nil {
+			logger.FromCtx(r.Context()).Debug("request unauthenticated", logger.ErrorAttr(err))
+			if ok, retryAfter := m.limiter.Allow(reqinfo.FromCtx(r.Context()).Caller(), ratelimit.OpRead); !ok {
+				tooManyRequests(w, retryAfter)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="kv-store"`)
+			http.Error(w, auth.ErrUnauthenticated.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		reqinfo.FromCtx(r.Context()).Identity = p.Subject
+		ctx := auth.ToCtx(r.Context(), p)
+		ctx = logger.ToCtx(ctx, logger.FromCtx(ctx).With(slog.String("subject", p.Subject)))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Require rejects the request with 403 if the principal has no given access to the "key"
+// url parameter in the "namespace" one. Must be used after routing so parameters are resolved.
+func (m *authMws) Require(access auth.Access) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.auth.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err := m.auth.Authorize(auth.FromCtx(r.Context()), access, chi.URLParam(r, "namespace"), chi.URLParam(r, "key"))
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r)
+			case errors.Is(err, auth.ErrUnauthenticated):
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+			default:
+				http.Error(w, err.Error(), http.StatusForbidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	tutils "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthRouter(t *testing.T, enabled bool, limiter *ratelimit.Limiter) (*chi.Mux, *string) {
+	t.Helper()
+	svc, err := auth.NewService(&cfg.AuthCfg{
+		Enabled: enabled,
+		Tokens: []cfg.APITokenCfg{
+			{Token: "jobs-token", Subject: "batch-job", Roles: []string{"jobs-writer"}},
+		},
+		Roles: []cfg.RoleCfg{
+			{Name: "jobs-writer", Rules: []cfg.RoleRuleCfg{
+				{Prefix: "jobs-", Access: "write"},
+				{Namespace: "team-a", Prefix: "jobs-", Access: "write"},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	l, _ := tutils.NewMockLogger()
+	mws := NewMiddlewares(l, &mockMetrics{}, nil)
+	authMws := NewAuthMiddlewares(svc, limiter)
+
+	var identity string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = reqinfo.FromCtx(r.Context()).Identity
+	})
+
+	router := chi.NewRouter()
+	router.Use(mws.Logging, authMws.Authenticate)
+	router.With(authMws.Require(auth.Write)).Put("/{key}", handler)
+	router.With(authMws.Require(auth.Write)).Put("/ns/{namespace}/{key}", handler)
+	return router, &identity
+}
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		header   string
+		path     string
+		wantCode int
+	}{
+		{name: "disabled", enabled: false, path: "/users-1", wantCode: http.StatusOK},
+		{name: "missing credentials", enabled: true, path: "/jobs-1", wantCode: http.StatusUnauthorized},
+		{name: "invalid token", enabled: true, header: "Bearer nope", path: "/jobs-1", wantCode: http.StatusUnauthorized},
+		{name: "forbidden prefix", enabled: true, header: "Bearer jobs-token", path: "/users-1", wantCode: http.StatusForbidden},
+		{name: "allowed", enabled: true, header: "Bearer jobs-token", path: "/jobs-1", wantCode: http.StatusOK},
+		{name: "allowed namespace", enabled: true, header: "Bearer jobs-token", path: "/ns/team-a/jobs-1", wantCode: http.StatusOK},
+		{name: "forbidden namespace", enabled: true, header: "Bearer jobs-token", path: "/ns/team-b/jobs-1", wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, identity := newAuthRouter(t, tt.enabled, nil)
+
+			req := httptest.NewRequest(http.MethodPut, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+			}
+			if tt.enabled && tt.wantCode == http.StatusOK {
+				assert.Equal(t, "batch-job", *identity)
+			}
+		})
+	}
+}
+
+func TestAuth_LimitsUnauthenticated(t *testing.T) {
+	limiter := ratelimit.NewLimiter(&cfg.RateLimitCfg{
+		Enabled: true,
+		Read:    cfg.LimitCfg{Rate: 0.1, Burst: 1},
+	})
+	router, _ := newAuthRouter(t, true, limiter)
+
+	do := func(header, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/jobs-1", nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("Authorization", header)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, do("Bearer guess-1", "10.0.0.1").Code)
+	rr := do("Bearer guess-2", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "guesses are limited by ip")
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusUnauthorized, do("Bearer guess-3", "10.0.0.2").Code)
+	assert.Equal(t, http.StatusOK, do("Bearer jobs-token", "10.0.0.1").Code, "authenticated clients aren't limited by ip")
+}
@@ -8,20 +8,24 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
 	"github.com/shrtyk/kv-store/internal/core/ports/metrics"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	"github.com/shrtyk/kv-store/pkg/logger"
 	"github.com/tomasen/realip"
 )
 
 type mws struct {
-	log     *slog.Logger
-	metrics metrics.Metrics
+	log        *slog.Logger
+	metrics    metrics.Metrics
+	namespaces store.Namespaces
 }
 
-func NewMiddlewares(l *slog.Logger, m metrics.Metrics) *mws {
+func NewMiddlewares(l *slog.Logger, m metrics.Metrics, namespaces store.Namespaces) *mws {
 	return &mws{
-		log:     l,
-		metrics: m,
+		log:        l,
+		metrics:    m,
+		namespaces: namespaces,
 	}
 }
 
@@ -59,21 +63,27 @@ func (m *mws) HttpMetrics(next http.Handler) http.Handler {
 			cw.statusCode,
 			r.Method,
 			chi.RouteContext(r.Context()).RoutePattern(),
+			// Resolved by routing once the request is served.
+			store.MetricsNamespace(m.namespaces, chi.URLParam(r, "namespace")),
 			time.Since(start).Seconds())
 	})
 }
 
 func (m *mws) Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := &reqinfo.Info{
+			RequestID: uuid.NewString(),
+			IP:        realip.FromRequest(r),
+		}
 		ctxWithLog := logger.ToCtx(r.Context(), m.log.With(
-			slog.String("ip", realip.FromRequest(r)),
+			slog.String("ip", info.IP),
 			slog.String("user-agent", r.UserAgent()),
-			slog.String("request_id", uuid.NewString()),
+			slog.String("request_id", info.RequestID),
 			slog.String("method", r.Method),
 			slog.String("url", r.URL.RequestURI()),
 		))
 
-		newReq := r.WithContext(ctxWithLog)
+		newReq := r.WithContext(reqinfo.ToCtx(ctxWithLog, info))
 		next.ServeHTTP(w, newReq)
 	})
 }
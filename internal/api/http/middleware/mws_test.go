@@ -6,6 +6,9 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	storemocks "github.com/shrtyk/kv-store/internal/core/ports/store/mocks"
 	tutils "github.com/shrtyk/kv-store/internal/tests/testutils"
 	"github.com/shrtyk/kv-store/pkg/logger"
 	"github.com/stretchr/testify/assert"
@@ -14,33 +17,37 @@ import (
 )
 
 type mockMetrics struct {
-	called  bool
-	code    int
-	method  string
-	path    string
-	latency float64
+	called    bool
+	code      int
+	method    string
+	path      string
+	namespace string
+	latency   float64
 }
 
 func (m *mockMetrics) GrpcPut(key string, duration float64)    {}
 func (m *mockMetrics) GrpcDelete(key string, duration float64) {}
 func (m *mockMetrics) GrpcGet(key string, duration float64)    {}
+func (m *mockMetrics) ProposalBatch(size int)                  {}
 
-func (m *mockMetrics) HttpRequest(code int, method, path string, latency float64) {
+func (m *mockMetrics) HttpRequest(code int, method, path, namespace string, latency float64) {
 	m.called = true
 	m.code = code
 	m.method = method
 	m.path = path
+	m.namespace = namespace
 	m.latency = latency
 }
-func (m *mockMetrics) GrpcRequest(code codes.Code, service, method string, latency float64) {}
-func (m *mockMetrics) HttpPut(key string, duration float64)                                 {}
-func (m *mockMetrics) HttpDelete(key string, duration float64)                              {}
-func (m *mockMetrics) HttpGet(key string, duration float64)                                 {}
+func (m *mockMetrics) GrpcRequest(code codes.Code, service, method, namespace string, latency float64) {
+}
+func (m *mockMetrics) HttpPut(key string, duration float64)    {}
+func (m *mockMetrics) HttpDelete(key string, duration float64) {}
+func (m *mockMetrics) HttpGet(key string, duration float64)    {}
 
 func TestHttpMetrics(t *testing.T) {
 	l, _ := tutils.NewMockLogger()
 	mockMetrics := &mockMetrics{}
-	mws := NewMiddlewares(l, mockMetrics)
+	mws := NewMiddlewares(l, mockMetrics, nil)
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusCreated)
@@ -63,13 +70,52 @@ func TestHttpMetrics(t *testing.T) {
 	assert.Greater(t, mockMetrics.latency, 0.0)
 }
 
+func TestHttpMetrics_Namespace(t *testing.T) {
+	l, _ := tutils.NewMockLogger()
+	namespaces := storemocks.NewMockNamespaces(t)
+	namespaces.EXPECT().Store("team-a").Return(nil, nil).Maybe()
+	namespaces.EXPECT().Store("team-b").Return(nil, store.ErrNoSuchNamespace).Maybe()
+
+	tests := []struct {
+		name      string
+		url       string
+		wantLabel string
+	}{
+		{name: "existing namespace", url: "/v1/ns/team-a/k", wantLabel: "team-a"},
+		{name: "unknown namespace", url: "/v1/ns/team-b/k", wantLabel: store.UnknownNamespace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetrics := &mockMetrics{}
+			mws := NewMiddlewares(l, mockMetrics, namespaces)
+
+			router := chi.NewRouter()
+			router.Route("/v1", func(r chi.Router) {
+				r.Use(mws.HttpMetrics)
+				r.Route("/ns/{namespace}", func(r chi.Router) {
+					r.Get("/{key}", func(w http.ResponseWriter, r *http.Request) {})
+				})
+			})
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			assert.True(t, mockMetrics.called)
+			assert.Equal(t, tt.wantLabel, mockMetrics.namespace)
+			assert.Equal(t, "/v1/ns/{namespace}/{key}", mockMetrics.path)
+		})
+	}
+}
+
 func TestLogging(t *testing.T) {
 	l, buf := tutils.NewMockLogger()
-	mws := NewMiddlewares(l, &mockMetrics{})
+	mws := NewMiddlewares(l, &mockMetrics{}, nil)
+	var info *reqinfo.Info
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		logger := logger.FromCtx(r.Context())
 		logger.Info("test message")
+		info = reqinfo.FromCtx(r.Context())
 	})
 
 	router := chi.NewRouter()
@@ -87,4 +133,7 @@ func TestLogging(t *testing.T) {
 	assert.Contains(t, buf.String(), "request_id")
 	assert.Contains(t, buf.String(), "method")
 	assert.Contains(t, buf.String(), "url")
+	assert.NotEmpty(t, info.RequestID)
+	assert.Contains(t, buf.String(), info.RequestID)
+	assert.NotEmpty(t, info.IP)
 }
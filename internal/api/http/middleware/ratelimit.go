@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+)
+
+type rateLimitMws struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimitMiddlewares(l *ratelimit.Limiter) *rateLimitMws {
+	return &rateLimitMws{limiter: l}
+}
+
+// RateLimit rejects the request with 429 if the client exceeded its rate.
+// Must be used after Authenticate so authenticated clients are limited by subject.
+func (m *rateLimitMws) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.limiter.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		op := ratelimit.OpWrite
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			op = ratelimit.OpRead
+		}
+
+		if ok, retryAfter := m.limiter.Allow(reqinfo.FromCtx(r.Context()).Caller(), op); !ok {
+			tooManyRequests(w, retryAfter)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tooManyRequests rejects the request with 429 suggesting a backoff in Retry-After header.
+func tooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(retryAfter)))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
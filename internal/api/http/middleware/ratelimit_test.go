@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	tutils "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter := ratelimit.NewLimiter(&cfg.RateLimitCfg{
+		Enabled: true,
+		Write:   cfg.LimitCfg{Rate: 0.1, Burst: 1},
+	})
+
+	l, _ := tutils.NewMockLogger()
+	mws := NewMiddlewares(l, &mockMetrics{}, nil)
+	rlMws := NewRateLimitMiddlewares(limiter)
+
+	router := chi.NewRouter()
+	router.Use(mws.Logging, rlMws.RateLimit)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	router.Get("/{key}", handler)
+	router.Put("/{key}", handler)
+
+	do := func(method, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/k", nil)
+		req.RemoteAddr = ip + ":1234"
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "10.0.0.1").Code)
+
+	rr := do(http.MethodPut, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "10.0.0.2").Code)
+}
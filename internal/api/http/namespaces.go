@@ -0,0 +1,86 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// CreateNamespace godoc
+// @Summary      Create namespace
+// @Description  Commits creation of an empty namespace with its own limits. Zero limits fall back to the store config, larger ones are rejected. Keys of the namespace are served under /v1/ns/{namespace}.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        namespace body store.Namespace true "namespace"
+// @Success      201 {object} store.Namespace
+// @Failure      307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Forbidden"
+// @Failure      409 {string} string "Namespace already exists"
+// @Failure      503 {string} string "Request timed out"
+// @Security     BearerAuth
+// @Router       /admin/namespaces [post]
+func (h *adminHandlers) CreateNamespace(w http.ResponseWriter, r *http.Request) {
+	var ns store.Namespace
+	if err := json.NewDecoder(r.Body).Decode(&ns); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := store.CheckNamespace(ns, h.stCfg.MaxKeySize, h.stCfg.MaxValSize); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cmd := &fsm_v1.Command{Command: &fsm_v1.Command_NamespaceCreate{NamespaceCreate: &fsm_v1.NamespaceCreateCommand{
+		Namespace: &fsm_v1.Namespace{
+			Name:       ns.Name,
+			MaxKeySize: int32(ns.MaxKeySize),
+			MaxValSize: int32(ns.MaxValSize),
+			MaxKeys:    int32(ns.MaxKeys),
+		},
+	}}}
+	if h.commit(w, r, cmd) {
+		writeJSON(w, http.StatusCreated, ns)
+	}
+}
+
+// ListNamespaces godoc
+// @Summary      List namespaces
+// @Description  Returns namespaces known to this node ordered by name, without the default one.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {array} store.Namespace
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Forbidden"
+// @Security     BearerAuth
+// @Router       /admin/namespaces [get]
+func (h *adminHandlers) ListNamespaces(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.namespaces.List())
+}
+
+// DeleteNamespace godoc
+// @Summary      Delete namespace
+// @Description  Commits deletion of the namespace with all its keys.
+// @Tags         admin
+// @Param        name path string true "namespace"
+// @Success      204
+// @Failure      307 {string} string "Node is not a leader"
+// @Failure      401 {string} string "Unauthorized"
+// @Failure      403 {string} string "Forbidden"
+// @Failure      404 {string} string "Unknown namespace"
+// @Failure      503 {string} string "Request timed out"
+// @Security     BearerAuth
+// @Router       /admin/namespaces/{name} [delete]
+func (h *adminHandlers) DeleteNamespace(w http.ResponseWriter, r *http.Request) {
+	cmd := &fsm_v1.Command{Command: &fsm_v1.Command_NamespaceDelete{NamespaceDelete: &fsm_v1.NamespaceDeleteCommand{
+		Name: chi.URLParam(r, "name"),
+	}}}
+	if h.commit(w, r, cmd) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,155 @@
+package httphandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	proposermocks "github.com/shrtyk/kv-store/internal/core/ports/proposer/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	corestore "github.com/shrtyk/kv-store/internal/core/store"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newNamespaceReq(method, target, body string, params map[string]string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(logger.ToCtx(req.Context(), logger.NewLogger("dev")))
+}
+
+// namespacesProposer applies namespace commands to the namespaces like the state machine does.
+func namespacesProposer(t *testing.T, spaces store.Namespaces) *proposermocks.MockProposer {
+	prop := proposermocks.NewMockProposer(t)
+	prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+		var cmd fsm_v1.Command
+		require.NoError(t, proto.Unmarshal(data, &cmd))
+		var err error
+		switch c := cmd.Command.(type) {
+		case *fsm_v1.Command_NamespaceCreate:
+			ns := c.NamespaceCreate.Namespace
+			err = spaces.Create(store.Namespace{Name: ns.Name, MaxKeys: int(ns.MaxKeys)})
+		case *fsm_v1.Command_NamespaceDelete:
+			err = spaces.Delete(c.NamespaceDelete.Name)
+		}
+		future := futuresmocks.NewMockFuture(t)
+		future.On("Wait", mock.Anything).Return(err).Once()
+		return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+	}).Maybe()
+	return prop
+}
+
+func TestAdminNamespaces(t *testing.T) {
+	stCfg := &cfg.StoreCfg{MaxKeySize: 10, MaxValSize: 20}
+	spaces := corestore.NewNamespaces(nil, stCfg, &cfg.ShardsCfg{}, logger.NewLogger("dev"))
+	h := NewAdminHandlers(stCfg, nil, nil, nil, namespacesProposer(t, spaces), spaces)
+
+	rr := httptest.NewRecorder()
+	h.CreateNamespace(rr, newNamespaceReq(http.MethodPost, "/admin/namespaces", `{"name":"team-a","max_keys":5}`, nil))
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.CreateNamespace(rr, newNamespaceReq(http.MethodPost, "/admin/namespaces", `{"name":"team-a"}`, nil))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.CreateNamespace(rr, newNamespaceReq(http.MethodPost, "/admin/namespaces", `{"name":"team-b","max_val_size":100}`, nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.ListNamespaces(rr, newNamespaceReq(http.MethodGet, "/admin/namespaces", "", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list []store.Namespace
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&list))
+	assert.Equal(t, []store.Namespace{{Name: "team-a", MaxKeys: 5}}, list)
+
+	params := map[string]string{"name": "team-a"}
+	rr = httptest.NewRecorder()
+	h.DeleteNamespace(rr, newNamespaceReq(http.MethodDelete, "/admin/namespaces/team-a", "", params))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.DeleteNamespace(rr, newNamespaceReq(http.MethodDelete, "/admin/namespaces/team-a", "", params))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestNamespaceRoutes(t *testing.T) {
+	t.Run("reads keys of the namespace", func(t *testing.T) {
+		s := setup(t)
+		require.NoError(t, s.hp.namespaces.Create(store.Namespace{Name: "team-a"}))
+		st, err := s.hp.namespaces.Store("team-a")
+		require.NoError(t, err)
+		_, err = st.HSet("user", map[string]string{"name": "alice"}, 0)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		s.hp.HGetHandler(rr, newNamespaceReq(http.MethodGet, "/v1/ns/team-a/user/fields/name", "", map[string]string{
+			"namespace": "team-a", "key": "user", "field": "name",
+		}))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "alice", rr.Body.String())
+	})
+
+	t.Run("unknown namespace", func(t *testing.T) {
+		s := setup(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.HGetHandler(rr, newNamespaceReq(http.MethodGet, "/v1/ns/team-a/user/fields/name", "", map[string]string{
+			"namespace": "team-a", "key": "user", "field": "name",
+		}))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("writes carry the namespace", func(t *testing.T) {
+		s := setup(t)
+		prop := proposermocks.NewMockProposer(t)
+		s.hp.proposer = prop
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.Equal(t, "team-a", cmd.GetNamespace())
+			assert.Equal(t, "k", cmd.GetPut().GetKey())
+			return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: s.mockFuture}, nil
+		}).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockMetrics.On("HttpPut", "k", mock.Anything).Return().Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.PutHandler(rr, newNamespaceReq(http.MethodPut, "/v1/ns/team-a/k", "v", map[string]string{
+			"namespace": "team-a", "key": "k",
+		}))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+	})
+
+	t.Run("leases and locks are rejected", func(t *testing.T) {
+		s := setup(t)
+		s.hp.proposer = proposermocks.NewMockProposer(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.NamespacedLeaseHandler(rr, newNamespaceReq(http.MethodPost, "/v1/ns/team-a/k/lock?lease=1", "", map[string]string{
+			"namespace": "team-a", "key": "k",
+		}))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), fsm.ErrNamespacedLease.Error())
+	})
+}
@@ -0,0 +1,329 @@
+package httphandlers
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/api/zsets"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// ZRangeHandler godoc
+// @Summary      Gets members of a sorted set
+// @Description  Gets members of a sorted set in ascending order of scores. By default members ranked between start
+// @Description  and stop inclusive are returned, negative ranks count from the highest score. With min or max,
+// @Description  up to limit members with scores in that range are returned instead. Infinite scores are written
+// @Description  as "inf" and "-inf"
+// @Tags         sorted sets
+// @Produce      json
+// @Param        key path string true "key"
+// @Param        start query int false "First rank, 0 by default"
+// @Param        stop query int false "Last rank, -1 by default"
+// @Param        min query string false "Lowest score, -inf by default"
+// @Param        max query string false "Highest score, inf by default"
+// @Param        limit query int false "Maximum number of members in a score range, unlimited by default"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
+// @Success      200 {array} zsets.Member "Members, empty for a missing sorted set"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/zset [get]
+func (h *handlersProvider) ZRangeHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	q := r.URL.Query()
+	byScore := q.Has("min") || q.Has("max")
+
+	start, stop, limit := 0, -1, 0
+	for name, dst := range map[string]*int{"start": &start, "stop": &stop, "limit": &limit} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, "invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+	lo, hi := math.Inf(-1), math.Inf(1)
+	for name, dst := range map[string]*float64{"min": &lo, "max": &hi} {
+		if v := q.Get(name); v != "" {
+			score, err := zsets.ParseScore(v)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			*dst = score
+		}
+	}
+	if !h.readBarrier(w, r) {
+		return
+	}
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	var (
+		members []store.ScoredMember
+		err     error
+	)
+	if byScore {
+		members, err = st.ZRangeByScore(key, lo, hi, limit)
+	} else {
+		members, err = st.ZRange(key, start, stop)
+	}
+	if err != nil && err != store.ErrNoSuchKey {
+		writeReadError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, zsets.Members(members))
+}
+
+// ZScoreHandler godoc
+// @Summary      Gets score of a sorted set member
+// @Description  Gets score of a sorted set member. Infinite scores are written as "inf" and "-inf"
+// @Tags         sorted sets
+// @Produce      text/plain
+// @Param        key path string true "key"
+// @Param        member path string true "member"
+// @Param        X-KV-Index header int false "Index returned by a write. Any node answers once it has applied it"
+// @Param        X-KV-Consistency header string false "Set to stale to read from any node without waiting" Enums(stale)
+// @Success      200 {string} string "Score"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      404 {string} string "Sorted set or member not found"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/zset/members/{member} [get]
+func (h *handlersProvider) ZScoreHandler(w http.ResponseWriter, r *http.Request) {
+	key, member := chi.URLParam(r, "key"), chi.URLParam(r, "member")
+	if !h.readBarrier(w, r) {
+		return
+	}
+	st, ok := h.namespaceStore(w, r)
+	if !ok {
+		return
+	}
+
+	score, err := st.ZScore(key, member)
+	if err != nil {
+		writeReadError(w, r, err)
+		return
+	}
+	if _, err := io.WriteString(w, zsets.FormatScore(score)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ZAddHandler godoc
+// @Summary      Sets score of a sorted set member
+// @Description  Sets score of a member, adding it and creating the sorted set if needed
+// @Tags         sorted sets
+// @Accept       text/plain
+// @Param        key path string true "key"
+// @Param        member path string true "member"
+// @Param        score body string true "score, e.g. 1.5 or -inf"
+// @Success      201 "Created"
+// @Header       201 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/zset/members/{member} [put]
+func (h *handlersProvider) ZAddHandler(w http.ResponseWriter, r *http.Request) {
+	key, member := chi.URLParam(r, "key"), chi.URLParam(r, "member")
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	score, err := zsets.ParseScore(strings.TrimSpace(string(body)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkMember(w, key, member) {
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Zadd{Zadd: &fsm_v1.ZAddCommand{
+		Key:     key,
+		Members: []*fsm_v1.ScoredMember{{Member: member, Score: score}},
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// ZRemHandler godoc
+// @Summary      Removes a sorted set member
+// @Description  Removes a member of a sorted set. Sorted set without members is deleted
+// @Tags         sorted sets
+// @Param        key path string true "key"
+// @Param        member path string true "member"
+// @Success      204 "No Content"
+// @Header       204 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/zset/members/{member} [delete]
+func (h *handlersProvider) ZRemHandler(w http.ResponseWriter, r *http.Request) {
+	key, member := chi.URLParam(r, "key"), chi.URLParam(r, "member")
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Zrem{Zrem: &fsm_v1.ZRemCommand{
+		Key:     key,
+		Members: []string{member},
+	}}})
+	if !ok {
+		return
+	}
+
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ZIncrByHandler godoc
+// @Summary      Increments score of a sorted set member
+// @Description  Adds a number to the score of a member, which starts at 0 for a new member
+// @Tags         sorted sets
+// @Produce      text/plain
+// @Param        key path string true "key"
+// @Param        member path string true "member"
+// @Param        by query string false "Increment, 1 by default"
+// @Success      200 {string} string "New score"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind or the score would not be a number"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      507 {string} string "Storage quota exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/zset/members/{member}/incr [post]
+func (h *handlersProvider) ZIncrByHandler(w http.ResponseWriter, r *http.Request) {
+	key, member := chi.URLParam(r, "key"), chi.URLParam(r, "member")
+	delta := 1.0
+	if by := r.URL.Query().Get("by"); by != "" {
+		var err error
+		if delta, err = zsets.ParseScore(by); err != nil {
+			http.Error(w, "invalid increment", http.StatusBadRequest)
+			return
+		}
+	}
+	if !h.checkMember(w, key, member) {
+		return
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Zincrby{Zincrby: &fsm_v1.ZIncrByCommand{
+		Key:    key,
+		Member: member,
+		Delta:  delta,
+	}}})
+	if !ok {
+		return
+	}
+
+	score, err := zsets.ParseScore(string(res.Future.Data()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	if _, err := io.WriteString(w, zsets.FormatScore(score)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ZPopMinHandler godoc
+// @Summary      Pops members with the lowest scores
+// @Description  Removes up to count members with the lowest scores from a sorted set and returns them
+// @Tags         sorted sets
+// @Produce      json
+// @Param        key path string true "key"
+// @Param        count query int false "Maximum number of members, 1 by default"
+// @Success      200 {array} zsets.Member "Popped members, empty for a missing sorted set"
+// @Header       200 {int} X-KV-Index "Log index of the write to use in subsequent reads"
+// @Failure 	 307 {string} string "Node is not a leader"
+// @Failure      400 {string} string "Wrong input data"
+// @Failure      409 {string} string "Key holds a value of another kind"
+// @Failure      401 {string} string "Missing or invalid credentials"
+// @Failure      403 {string} string "Permission denied"
+// @Failure      429 {string} string "Rate limit exceeded"
+// @Failure      500 {string} string "Internal Server Error"
+// @Failure      503 {string} string "Node is overloaded or request timed out"
+// @Security     BearerAuth
+// @Router       /v1/{key}/zset/popmin [post]
+func (h *handlersProvider) ZPopMinHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	count := int64(1)
+	if c := r.URL.Query().Get("count"); c != "" {
+		var err error
+		if count, err = strconv.ParseInt(c, 10, 64); err != nil || count < 1 {
+			http.Error(w, "invalid count", http.StatusBadRequest)
+			return
+		}
+	}
+
+	res, ok := h.propose(w, r, &fsm_v1.Command{Command: &fsm_v1.Command_Zpopmin{Zpopmin: &fsm_v1.ZPopMinCommand{
+		Key:   key,
+		Count: count,
+	}}})
+	if !ok {
+		return
+	}
+
+	members, err := zsets.DecodePopMin(res.Future.Data())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(consistency.Header, consistency.FormatIndex(res.LogIndex))
+	writeJSON(w, http.StatusOK, zsets.Members(members))
+}
+
+// checkMember checks sizes of the key and a member. Members are limited like values.
+func (h *handlersProvider) checkMember(w http.ResponseWriter, key, member string) bool {
+	if len(key) > h.stCfg.MaxKeySize {
+		http.Error(w, store.ErrKeyTooLarge.Error(), http.StatusBadRequest)
+		return false
+	}
+	if len(member) > h.stCfg.MaxValSize {
+		http.Error(w, store.ErrValueTooLarge.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package httphandlers
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shrtyk/kv-store/internal/api/consistency"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newMemberRequest(method, key, member, path, body string) *http.Request {
+	req := newFieldRequest(method, key, "", path, body)
+	if member != "" {
+		chi.RouteContext(req.Context()).URLParams.Add("member", member)
+	}
+	return req
+}
+
+func TestZAddHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("ZAdd", "z", []store.ScoredMember{{Member: "m", Score: -1.5}}, mock.Anything).Return(1, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.ZAddHandler(rr, newMemberRequest(http.MethodPut, "z", "m", "/v1/z/zset/members/m", "-1.5\n"))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get(consistency.Header))
+		s.mockStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid score", func(t *testing.T) {
+		s := setup(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.ZAddHandler(rr, newMemberRequest(http.MethodPut, "z", "m", "/v1/z/zset/members/m", "NaN"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestZRangeHandler(t *testing.T) {
+	t.Run("by rank", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("ZRange", "z", 0, 1).Return([]store.ScoredMember{
+			{Member: "a", Score: math.Inf(-1)},
+			{Member: "b", Score: 2},
+		}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.ZRangeHandler(rr, newMemberRequest(http.MethodGet, "z", "", "/v1/z/zset?stop=1", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[{"member":"a","score":"-inf"},{"member":"b","score":2}]`, rr.Body.String())
+	})
+
+	t.Run("by score", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("ZRangeByScore", "z", 1.0, math.Inf(1), 10).Return([]store.ScoredMember{}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.ZRangeHandler(rr, newMemberRequest(http.MethodGet, "z", "", "/v1/z/zset?min=1&limit=10", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[]`, rr.Body.String())
+	})
+
+	t.Run("missing sorted set", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.On("ZRange", "z", 0, -1).Return(nil, store.ErrNoSuchKey).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.ZRangeHandler(rr, newMemberRequest(http.MethodGet, "z", "", "/v1/z/zset", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[]`, rr.Body.String())
+	})
+
+	t.Run("invalid min", func(t *testing.T) {
+		s := setup(t)
+
+		rr := httptest.NewRecorder()
+		s.hp.ZRangeHandler(rr, newMemberRequest(http.MethodGet, "z", "", "/v1/z/zset?min=low", ""))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestZScoreHandler(t *testing.T) {
+	s := setup(t)
+	s.mockStore.On("ZScore", "z", "a").Return(2.5, nil).Once()
+	s.mockStore.On("ZScore", "z", "b").Return(0.0, store.ErrNoSuchKey).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.ZScoreHandler(rr, newMemberRequest(http.MethodGet, "z", "a", "/v1/z/zset/members/a", ""))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2.5", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	s.hp.ZScoreHandler(rr, newMemberRequest(http.MethodGet, "z", "b", "/v1/z/zset/members/b", ""))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestZIncrByHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("ZIncrBy", "z", "a", 0.5, mock.Anything).Return(3.0, nil).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+		s.mockFuture.On("Data").Return([]byte("3")).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.ZIncrByHandler(rr, newMemberRequest(http.MethodPost, "z", "a", "/v1/z/zset/members/a/incr?by=0.5", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "3", rr.Body.String())
+	})
+
+	t.Run("not a number", func(t *testing.T) {
+		s := setup(t)
+
+		s.mockStore.On("ZIncrBy", "z", "a", math.Inf(-1), mock.Anything).Return(0.0, store.ErrNotANumber).Once()
+		s.mockFuture.On("Wait", mock.Anything).Return(store.ErrNotANumber).Once()
+		s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+		rr := httptest.NewRecorder()
+		s.hp.ZIncrByHandler(rr, newMemberRequest(http.MethodPost, "z", "a", "/v1/z/zset/members/a/incr?by=-inf", ""))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
+
+func TestZRemHandler(t *testing.T) {
+	s := setup(t)
+
+	s.mockStore.On("ZRem", "z", []string{"a"}, mock.Anything).Return(1, nil).Once()
+	s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+	s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.ZRemHandler(rr, newMemberRequest(http.MethodDelete, "z", "a", "/v1/z/zset/members/a", ""))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	s.mockStore.AssertExpectations(t)
+}
+
+func TestZPopMinHandler(t *testing.T) {
+	s := setup(t)
+	popped, err := proto.Marshal(&fsm_v1.ZPopResult{Members: []*fsm_v1.ScoredMember{{Member: "a", Score: 1}}})
+	require.NoError(t, err)
+
+	s.mockStore.On("ZPopMin", "z", 3, mock.Anything).Return([]store.ScoredMember{{Member: "a", Score: 1}}, nil).Once()
+	s.mockFuture.On("Wait", mock.Anything).Return(nil).Once()
+	s.mockFuture.On("Data").Return(popped).Once()
+	s.mockFutures.On("NewFuture", mock.Anything).Return(s.mockFuture).Once()
+
+	rr := httptest.NewRecorder()
+	s.hp.ZPopMinHandler(rr, newMemberRequest(http.MethodPost, "z", "", "/v1/z/zset/popmin?count=3", ""))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"member":"a","score":1}]`, rr.Body.String())
+}
@@ -0,0 +1,78 @@
+// Package lists holds helpers shared by list handlers of every api.
+package lists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrInvalidSide = errors.New("lists: side must be left or right")
+
+// ParseSide reports whether side selects the head of a list. Empty side gives def.
+func ParseSide(side string, def bool) (bool, error) {
+	switch side {
+	case "":
+		return def, nil
+	case "left":
+		return true, nil
+	case "right":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %q", ErrInvalidSide, side)
+	}
+}
+
+// DecodePop returns values from the result data of an applied pop command.
+func DecodePop(data []byte) ([]string, error) {
+	var res fsm_v1.PopResult
+	if err := proto.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("lists: unexpected pop result: %w", err)
+	}
+	return res.Values, nil
+}
+
+// BlockingPop calls pop and, while it gives no values, waits up to timeout for a push to the key
+// applied by the local state machine to try again. It returns no values and no error on timeout.
+// The first attempt is always made, so followers answer with a redirect. Later ones are made only
+// if hasValues reports the local list isn't empty, so waiting doesn't replicate empty pops.
+// Nil watcher or zero timeout make a single attempt.
+func BlockingPop(
+	ctx context.Context,
+	watcher fsm.PushWatcher,
+	key string,
+	timeout time.Duration,
+	hasValues func() bool,
+	pop func() ([]string, error),
+) ([]string, error) {
+	if watcher == nil || timeout <= 0 {
+		return pop()
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for first := true; ; first = false {
+		pushed, stop := watcher.WatchPushes(key)
+		if first || hasValues() {
+			vals, err := pop()
+			if err != nil || len(vals) > 0 {
+				stop()
+				return vals, err
+			}
+		}
+
+		select {
+		case <-pushed:
+			stop()
+		case <-waitCtx.Done():
+			stop()
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseSide(t *testing.T) {
+	left, err := ParseSide("", true)
+	require.NoError(t, err)
+	assert.True(t, left)
+	left, err = ParseSide("right", true)
+	require.NoError(t, err)
+	assert.False(t, left)
+	_, err = ParseSide("middle", true)
+	assert.ErrorIs(t, err, ErrInvalidSide)
+}
+
+func TestDecodePop(t *testing.T) {
+	data, err := proto.Marshal(&fsm_v1.PopResult{Values: []string{"a", "b"}})
+	require.NoError(t, err)
+	vals, err := DecodePop(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, vals)
+}
+
+func TestBlockingPop(t *testing.T) {
+	t.Run("wakes up on push", func(t *testing.T) {
+		watcher := fsmmocks.NewMockPushWatcher(t)
+		pushed := make(chan struct{})
+		watcher.On("WatchPushes", "q").Return((<-chan struct{})(pushed), func() {}).Twice()
+
+		attempts := 0
+		vals, err := BlockingPop(context.Background(), watcher, "q", time.Second,
+			func() bool { return true },
+			func() ([]string, error) {
+				attempts++
+				if attempts == 1 {
+					close(pushed)
+					return nil, nil
+				}
+				return []string{"v"}, nil
+			})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v"}, vals)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		watcher := fsmmocks.NewMockPushWatcher(t)
+		watcher.On("WatchPushes", "q").Return((<-chan struct{})(make(chan struct{})), func() {}).Once()
+
+		vals, err := BlockingPop(context.Background(), watcher, "q", 10*time.Millisecond,
+			func() bool { return false },
+			func() ([]string, error) { return nil, nil })
+		require.NoError(t, err)
+		assert.Empty(t, vals)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		watcher := fsmmocks.NewMockPushWatcher(t)
+		watcher.On("WatchPushes", "q").Return((<-chan struct{})(make(chan struct{})), func() {}).Once()
+		boom := errors.New("boom")
+
+		_, err := BlockingPop(context.Background(), watcher, "q", time.Second,
+			func() bool { return true },
+			func() ([]string, error) { return nil, boom })
+		assert.ErrorIs(t, err, boom)
+	})
+}
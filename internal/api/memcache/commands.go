@@ -0,0 +1,523 @@
+package memcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"google.golang.org/protobuf/proto"
+)
+
+const version = "1.0.0"
+
+// relativeExptimeLimit is the largest exptime memcached treats as relative, larger ones are unix time.
+const relativeExptimeLimit = 60 * 60 * 24 * 30
+
+// clientError is a reply to a malformed or forbidden command.
+type clientError string
+
+func (e clientError) Error() string {
+	return "CLIENT_ERROR " + string(e)
+}
+
+// serverError is a reply to a command the server failed to execute.
+type serverError string
+
+func (e serverError) Error() string {
+	return "SERVER_ERROR " + string(e)
+}
+
+// readError is a failure to read a data block. Client and server are out of sync after it,
+// so the connection is closed.
+type readError struct {
+	err error
+}
+
+func (e *readError) Error() string {
+	return e.err.Error()
+}
+
+func (e *readError) Unwrap() error {
+	return e.err
+}
+
+var (
+	// errUnknownCommand is replied to unknown commands and commands with a wrong number of arguments.
+	errUnknownCommand  = errors.New("ERROR")
+	errBadFormat       = clientError("bad command line format")
+	errBadDataChunk    = clientError("bad data chunk")
+	errUnauthenticated = clientError("unauthenticated")
+	errAuthFailure     = clientError("authentication failure")
+	errBadDelta        = clientError("invalid numeric delta argument")
+	errNonNumeric      = clientError("cannot increment or decrement non-numeric value")
+)
+
+var commands = map[string]func(s *Server, c *conn, args []string) error{
+	"get":       (*Server).get,
+	"gets":      (*Server).get,
+	"set":       (*Server).set,
+	"add":       (*Server).set,
+	"replace":   (*Server).set,
+	"cas":       (*Server).set,
+	"delete":    (*Server).delete,
+	"incr":      (*Server).counter,
+	"decr":      (*Server).counter,
+	"touch":     (*Server).touch,
+	"flush_all": (*Server).flushAll,
+	"version":   (*Server).version,
+	"stats":     (*Server).statsCmd,
+	"quit":      (*Server).quit,
+}
+
+// exec runs a command line and writes its reply. Returned error means the connection has to be closed.
+func (s *Server) exec(c *conn, line string) error {
+	args := strings.Fields(line)
+	c.noreply = false
+	if len(args) == 0 {
+		c.reply(errUnknownCommand.Error())
+		return nil
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		c.reply(errUnknownCommand.Error())
+		return nil
+	}
+	if args[len(args)-1] == "noreply" {
+		c.noreply = true
+		args = args[:len(args)-1]
+	}
+
+	err := cmd(s, c, args)
+	var rerr *readError
+	if errors.As(err, &rerr) {
+		return rerr.err
+	}
+	if err != nil {
+		// Errors are sent even if the command asked for no reply, like memcached does.
+		c.noreply = false
+		c.reply(s.errorReply(err))
+	}
+	return nil
+}
+
+// errorReply maps an error into a reply line.
+func (s *Server) errorReply(err error) string {
+	var cerr clientError
+	var serr serverError
+	var nl *notLeaderError
+	switch {
+	case errors.Is(err, errUnknownCommand):
+		return errUnknownCommand.Error()
+	case errors.As(err, &cerr):
+		return cerr.Error()
+	case errors.As(err, &serr):
+		return serr.Error()
+	case errors.As(err, &nl):
+		return s.redirect(nl)
+	case errors.Is(err, admission.ErrOverloaded), errors.Is(err, admission.ErrDraining):
+		return "SERVER_ERROR busy, retry later: " + err.Error()
+	case errors.Is(err, ftr.ErrPromiseTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "SERVER_ERROR request timed out: raft cluster is busy"
+	case errors.Is(err, store.ErrQuotaExceeded):
+		return "SERVER_ERROR out of memory storing object"
+	case errors.Is(err, store.ErrValueTooLarge):
+		return "SERVER_ERROR object too large for cache"
+	case errors.Is(err, store.ErrKeyTooLarge):
+		return errBadFormat.Error()
+	default:
+		return "SERVER_ERROR " + err.Error()
+	}
+}
+
+// get serves get and gets, the latter also returns cas tokens.
+func (s *Server) get(c *conn, args []string) error {
+	if len(args) < 2 {
+		return errUnknownCommand
+	}
+	keys := args[1:]
+	for _, key := range keys {
+		if len(key) > s.stCfg.MaxKeySize {
+			return errBadFormat
+		}
+	}
+	if err := s.authorize(c, auth.Read, keys...); err != nil {
+		return err
+	}
+	if err := s.readBarrier(); err != nil {
+		return err
+	}
+
+	withCas := args[0] == "gets"
+	now := time.Now().UnixMilli()
+	s.stats.cmdGet.Add(uint64(len(keys)))
+	for _, key := range keys {
+		e, err := s.store.Lookup(key, now)
+		if err != nil || e.Kind != store.KindString {
+			s.stats.getMisses.Add(1)
+			continue
+		}
+		s.stats.getHits.Add(1)
+		if withCas {
+			fmt.Fprintf(c.w, "VALUE %s %d %d %d\r\n", e.Key, e.Flags, len(e.Value), e.Version)
+		} else {
+			fmt.Fprintf(c.w, "VALUE %s %d %d\r\n", e.Key, e.Flags, len(e.Value))
+		}
+		_, _ = c.w.WriteString(e.Value)
+		_, _ = c.w.WriteString("\r\n")
+	}
+	c.reply("END")
+	return nil
+}
+
+// set serves set, add, replace and cas:
+//
+//	<command> <key> <flags> <exptime> <bytes> [<cas unique>] [noreply]
+func (s *Server) set(c *conn, args []string) error {
+	name := args[0]
+	want := 5
+	if name == "cas" {
+		want = 6
+	}
+	if len(args) != want {
+		return errBadFormat
+	}
+	key := args[1]
+	flags, err1 := strconv.ParseUint(args[2], 10, 32)
+	exptime, err2 := strconv.ParseInt(args[3], 10, 32)
+	size, err3 := strconv.Atoi(args[4])
+	if err := errors.Join(err1, err2, err3); err != nil || size < 0 {
+		return errBadFormat
+	}
+	var casUnique uint64
+	if name == "cas" {
+		var err error
+		// Versions start at one, so zero token can't match.
+		if casUnique, err = strconv.ParseUint(args[5], 10, 64); err != nil || casUnique == 0 {
+			return errBadFormat
+		}
+	}
+
+	if len(key) > s.stCfg.MaxKeySize || size > s.stCfg.MaxValSize {
+		if err := c.discardData(size); err != nil {
+			return &readError{err: err}
+		}
+		if len(key) > s.stCfg.MaxKeySize {
+			return errBadFormat
+		}
+		return store.ErrValueTooLarge
+	}
+	data, err := c.readData(size)
+	if errors.Is(err, errBadDataChunk) {
+		return err
+	}
+	if err != nil {
+		return &readError{err: err}
+	}
+
+	if s.auth.Enabled() && c.principal == nil {
+		if name != "set" {
+			return errUnauthenticated
+		}
+		// Authentication attempts are limited as reads of the remote ip, so tokens can't be brute forced.
+		if err := s.limit(c, auth.Read); err != nil {
+			return err
+		}
+		if err := s.authenticate(c, data); err != nil {
+			return err
+		}
+		c.reply("STORED")
+		return nil
+	}
+	if err := s.authorize(c, auth.Write, key); err != nil {
+		return err
+	}
+
+	cond := fsm_v1.SetCondition_SET_CONDITION_NONE
+	switch name {
+	case "add":
+		cond = fsm_v1.SetCondition_SET_CONDITION_NOT_EXISTS
+	case "replace":
+		cond = fsm_v1.SetCondition_SET_CONDITION_EXISTS
+	}
+	now := time.Now().UnixMilli()
+	s.stats.cmdSet.Add(1)
+	prop, err := s.propose(c, &fsm_v1.Command{Command: &fsm_v1.Command_Set{Set: &fsm_v1.SetCommand{
+		Key:       key,
+		Value:     data,
+		Condition: cond,
+		ExpiresAt: expiresAt(exptime, now),
+		Flags:     uint32(flags),
+		Version:   casUnique,
+	}}})
+	switch {
+	case errors.Is(err, store.ErrNoSuchKey):
+		c.reply("NOT_FOUND")
+		return nil
+	case errors.Is(err, store.ErrVersionMismatch):
+		c.reply("EXISTS")
+		return nil
+	case err != nil:
+		return err
+	}
+	if string(prop.Future.Data()) != "1" {
+		c.reply("NOT_STORED")
+		return nil
+	}
+	c.reply("STORED")
+	return nil
+}
+
+// delete accepts "delete <key> [0]", the zero is left from old clients.
+func (s *Server) delete(c *conn, args []string) error {
+	if len(args) != 2 && (len(args) != 3 || args[2] != "0") {
+		return clientError("bad command line format.  Usage: delete <key> [noreply]")
+	}
+	key := args[1]
+	if len(key) > s.stCfg.MaxKeySize {
+		return errBadFormat
+	}
+	if err := s.authorize(c, auth.Write, key); err != nil {
+		return err
+	}
+
+	prop, err := s.propose(c, &fsm_v1.Command{Command: &fsm_v1.Command_Del{Del: &fsm_v1.DelCommand{
+		Keys: []string{key},
+	}}})
+	if err != nil {
+		return err
+	}
+	if string(prop.Future.Data()) == "0" {
+		c.reply("NOT_FOUND")
+		return nil
+	}
+	c.reply("DELETED")
+	return nil
+}
+
+// counter serves incr and decr of unsigned 64-bit values.
+func (s *Server) counter(c *conn, args []string) error {
+	if len(args) != 3 {
+		return errUnknownCommand
+	}
+	key := args[1]
+	if len(key) > s.stCfg.MaxKeySize {
+		return errBadFormat
+	}
+	delta, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return errBadDelta
+	}
+	if err := s.authorize(c, auth.Write, key); err != nil {
+		return err
+	}
+
+	prop, err := s.propose(c, &fsm_v1.Command{Command: &fsm_v1.Command_Counter{Counter: &fsm_v1.CounterCommand{
+		Key:   key,
+		Delta: delta,
+		Decr:  args[0] == "decr",
+	}}})
+	switch {
+	case errors.Is(err, store.ErrNoSuchKey):
+		c.reply("NOT_FOUND")
+		return nil
+	case errors.Is(err, store.ErrNotInteger):
+		return errNonNumeric
+	case err != nil:
+		return err
+	}
+	data := prop.Future.Data()
+	c.reply(string(data))
+	return nil
+}
+
+// touch changes expiry of a key without reading it.
+func (s *Server) touch(c *conn, args []string) error {
+	if len(args) != 3 {
+		return errUnknownCommand
+	}
+	key := args[1]
+	exptime, err := strconv.ParseInt(args[2], 10, 32)
+	if err != nil || len(key) > s.stCfg.MaxKeySize {
+		return errBadFormat
+	}
+	if err := s.authorize(c, auth.Write, key); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	s.stats.cmdTouch.Add(1)
+	prop, err := s.propose(c, &fsm_v1.Command{Command: &fsm_v1.Command_Expire{Expire: &fsm_v1.ExpireCommand{
+		Key:       key,
+		ExpiresAt: expiresAt(exptime, now),
+	}}})
+	if err != nil {
+		return err
+	}
+	if string(prop.Future.Data()) != "1" {
+		c.reply("NOT_FOUND")
+		return nil
+	}
+	c.reply("TOUCHED")
+	return nil
+}
+
+// flushAll invalidates every key now or after a delay. It needs admin access,
+// because unlike memcached it wipes the whole replicated store.
+func (s *Server) flushAll(c *conn, args []string) error {
+	if len(args) > 2 {
+		return errUnknownCommand
+	}
+	var delay int64
+	if len(args) == 2 {
+		var err error
+		if delay, err = strconv.ParseInt(args[1], 10, 32); err != nil {
+			return errBadFormat
+		}
+	}
+	if err := s.authorize(c, auth.Admin, ""); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	var at int64
+	if delay > 0 {
+		at = expiresAt(delay, now)
+	}
+	s.stats.cmdFlush.Add(1)
+	if _, err := s.propose(c, &fsm_v1.Command{Command: &fsm_v1.Command_Flush{Flush: &fsm_v1.FlushCommand{
+		ExpiresAt: at,
+	}}}); err != nil {
+		return err
+	}
+	c.reply("OK")
+	return nil
+}
+
+func (s *Server) version(c *conn, _ []string) error {
+	c.reply("VERSION " + version)
+	return nil
+}
+
+// statsCmd reports general statistics of this node. Other stats groups aren't supported.
+func (s *Server) statsCmd(c *conn, args []string) error {
+	if len(args) > 1 {
+		return errUnknownCommand
+	}
+	if err := s.authorize(c, auth.Read); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	currConns := len(s.conns)
+	s.mu.Unlock()
+	now := time.Now()
+	stat := func(name string, value any) {
+		fmt.Fprintf(c.w, "STAT %s %v\r\n", name, value)
+	}
+	stat("pid", os.Getpid())
+	stat("uptime", int64(now.Sub(s.started).Seconds()))
+	stat("time", now.Unix())
+	stat("version", version)
+	stat("curr_connections", currConns)
+	stat("total_connections", s.stats.totalConns.Load())
+	stat("cmd_get", s.stats.cmdGet.Load())
+	stat("cmd_set", s.stats.cmdSet.Load())
+	stat("cmd_flush", s.stats.cmdFlush.Load())
+	stat("cmd_touch", s.stats.cmdTouch.Load())
+	stat("get_hits", s.stats.getHits.Load())
+	stat("get_misses", s.stats.getMisses.Load())
+	_, isLeader := s.raft.State()
+	stat("leader", isLeader)
+	c.reply("END")
+	return nil
+}
+
+func (s *Server) quit(c *conn, _ []string) error {
+	c.quit = true
+	return nil
+}
+
+// expiresAt converts memcached exptime into a deadline in unix milliseconds:
+// zero never expires, up to 30 days is relative, larger values are unix time
+// and negative ones expire immediately.
+func expiresAt(exptime, now int64) int64 {
+	switch {
+	case exptime == 0:
+		return 0
+	case exptime < 0:
+		return now
+	case exptime <= relativeExptimeLimit:
+		return now + exptime*1000
+	default:
+		return max(exptime*1000, now)
+	}
+}
+
+// readBarrier makes sure local state reflects every write committed before the read.
+func (s *Server) readBarrier() error {
+	if err := s.admission.AdmitRead(); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(context.Background(), s.stCfg.ReadTimeout)
+	defer cancel()
+	res, err := s.raft.ReadOnly(ctx, nil)
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return err
+	}
+	if res != nil && !res.IsLeader {
+		return &notLeaderError{leaderID: res.LeaderId}
+	}
+	return nil
+}
+
+// propose submits the command and waits until it's applied.
+func (s *Server) propose(c *conn, cmd *fsm_v1.Command) (*proposer.Proposal, error) {
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	done, err := s.admission.AdmitWrite()
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(reqinfo.ToCtx(context.Background(), c.info), s.stCfg.WriteTimeout)
+	defer cancel()
+
+	prop, err := s.proposer.Propose(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if !prop.IsLeader {
+		return nil, &notLeaderError{leaderID: prop.LeaderID}
+	}
+	err = prop.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		return nil, err
+	}
+	return prop, nil
+}
+
+// withTimeout limits ctx with timeout. Zero timeout means no limit.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,372 @@
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	raftapi "github.com/shrtyk/raft-core/api"
+)
+
+// maxLineSize limits a command line. Memcached keys are at most 250 bytes,
+// so lines of multi-key gets are the longest ones.
+const maxLineSize = 64 * 1024
+
+// Server serves the memcached ASCII protocol.
+type Server struct {
+	wg         *sync.WaitGroup
+	cfg        *cfg.MemcachedCfg
+	stCfg      *cfg.StoreCfg
+	store      store.Store
+	logger     *slog.Logger
+	raft       raftapi.Raft
+	proposer   proposer.Proposer
+	membership cluster.Membership
+	auth       *auth.Service
+	limiter    *ratelimit.Limiter
+	admission  *admission.Controller
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[*conn]struct{}
+	connsWG  sync.WaitGroup
+	closing  atomic.Bool
+	started  time.Time
+	stats    stats
+}
+
+// stats are counters reported by the stats command.
+type stats struct {
+	totalConns atomic.Uint64
+	cmdGet     atomic.Uint64
+	cmdSet     atomic.Uint64
+	cmdTouch   atomic.Uint64
+	cmdFlush   atomic.Uint64
+	getHits    atomic.Uint64
+	getMisses  atomic.Uint64
+}
+
+func NewServer(
+	wg *sync.WaitGroup,
+	c *cfg.MemcachedCfg,
+	stCfg *cfg.StoreCfg,
+	st store.Store,
+	l *slog.Logger,
+	raft raftapi.Raft,
+	prop proposer.Proposer,
+	membership cluster.Membership,
+	authSvc *auth.Service,
+	limiter *ratelimit.Limiter,
+	adm *admission.Controller,
+) *Server {
+	return &Server{
+		wg:         wg,
+		cfg:        c,
+		stCfg:      stCfg,
+		store:      st,
+		logger:     l,
+		raft:       raft,
+		proposer:   prop,
+		membership: membership,
+		auth:       authSvc,
+		limiter:    limiter,
+		admission:  adm,
+		conns:      make(map[*conn]struct{}),
+		started:    time.Now(),
+	}
+}
+
+func (s *Server) MustStart() {
+	l, err := net.Listen("tcp", ":"+s.cfg.Port)
+	if err != nil {
+		msg := fmt.Sprintf("failed create net.Listener: %s", err)
+		panic(msg)
+	}
+
+	s.wg.Go(func() {
+		if err := s.Serve(l); err != nil {
+			msg := fmt.Sprintf("failed to start memcached server: %s", err)
+			panic(msg)
+		}
+	})
+}
+
+// Serve accepts connections on l until the server is shut down.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			if s.closing.Load() {
+				return nil
+			}
+			return err
+		}
+
+		c := s.newConn(nc)
+		s.mu.Lock()
+		if s.closing.Load() {
+			s.mu.Unlock()
+			_ = nc.Close()
+			continue
+		}
+		s.conns[c] = struct{}{}
+		s.connsWG.Add(1)
+		s.mu.Unlock()
+		s.stats.totalConns.Add(1)
+
+		go func() {
+			defer s.connsWG.Done()
+			s.handle(c)
+		}()
+	}
+}
+
+// Shutdown stops accepting connections and lets connections finish commands they are
+// executing. Connections still open when ctx is done are closed forcibly. Nil server is a no-op.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.closing.Store(true)
+
+	s.mu.Lock()
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for c := range s.conns {
+		// Unblocks connections waiting for the next command.
+		_ = c.nc.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.connsWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		s.logger.Warn("memcached server graceful shutdown time out; closing connections")
+		s.mu.Lock()
+		for c := range s.conns {
+			_ = c.nc.Close()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	case <-done:
+		s.logger.Info("memcached server graceful shutdown complete")
+		return nil
+	}
+}
+
+// conn is a state of a single client connection.
+type conn struct {
+	nc   net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+	info *reqinfo.Info
+
+	principal *auth.Principal
+	// noreply is set while a command asked not to be answered.
+	noreply bool
+	quit    bool
+}
+
+func (s *Server) newConn(nc net.Conn) *conn {
+	info := &reqinfo.Info{}
+	if host, _, err := net.SplitHostPort(nc.RemoteAddr().String()); err == nil {
+		info.IP = host
+	}
+	return &conn{
+		nc:   nc,
+		r:    bufio.NewReaderSize(nc, maxLineSize),
+		w:    bufio.NewWriter(nc),
+		info: info,
+	}
+}
+
+func (s *Server) handle(c *conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		_ = c.nc.Close()
+	}()
+
+	for !c.quit && !s.closing.Load() {
+		if s.cfg.IdleTimeout > 0 {
+			_ = c.nc.SetReadDeadline(time.Now().Add(s.cfg.IdleTimeout))
+		}
+		line, err := c.readLine()
+		if err != nil {
+			if errors.Is(err, bufio.ErrBufferFull) {
+				c.reply("CLIENT_ERROR line too long")
+				_ = c.w.Flush()
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) && !isTimeout(err) {
+				s.logger.Debug("failed to read memcached command", logger.ErrorAttr(err))
+			}
+			return
+		}
+
+		if err := s.exec(c, line); err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) && !isTimeout(err) {
+				s.logger.Debug("failed to read memcached data block", logger.ErrorAttr(err))
+			}
+			return
+		}
+		if c.r.Buffered() > 0 {
+			continue
+		}
+		if err := c.w.Flush(); err != nil {
+			s.logger.Debug("failed to write memcached reply", logger.ErrorAttr(err))
+			return
+		}
+	}
+	_ = c.w.Flush()
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// readLine reads a command line without the trailing "\r\n" or "\n".
+func (c *conn) readLine() (string, error) {
+	line, err := c.r.ReadSlice('\n')
+	if err != nil {
+		return "", err
+	}
+	line = line[:len(line)-1]
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return string(line), nil
+}
+
+// readData reads a data block of n bytes followed by "\r\n".
+func (c *conn) readData(n int) (string, error) {
+	buf := make([]byte, n+2)
+	if _, err := io.ReadFull(c.r, buf); err != nil {
+		return "", err
+	}
+	if buf[n] != '\r' || buf[n+1] != '\n' {
+		// Like memcached, skip the rest of the line, so it isn't taken for a command.
+		if buf[n+1] != '\n' {
+			if _, err := c.r.ReadSlice('\n'); err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+				return "", err
+			}
+		}
+		return "", errBadDataChunk
+	}
+	return string(buf[:n]), nil
+}
+
+// discardData skips a data block of n bytes followed by "\r\n".
+func (c *conn) discardData(n int) error {
+	_, err := c.r.Discard(n + 2)
+	return err
+}
+
+// reply writes a line unless the command asked for no reply.
+func (c *conn) reply(line string) {
+	if c.noreply {
+		return
+	}
+	_, _ = c.w.WriteString(line)
+	_, _ = c.w.WriteString("\r\n")
+}
+
+// authorize checks credentials, permissions on the keys and the rate limit.
+func (s *Server) authorize(c *conn, access auth.Access, keys ...string) error {
+	if s.auth.Enabled() {
+		if c.principal == nil {
+			return errUnauthenticated
+		}
+		for _, key := range keys {
+			if err := s.auth.Authorize(c.principal, access, "", key); err != nil {
+				return clientError(err.Error())
+			}
+		}
+	}
+
+	return s.limit(c, access)
+}
+
+// limit takes a token of the client, the authenticated subject or the remote ip.
+func (s *Server) limit(c *conn, access auth.Access) error {
+	if !s.limiter.Enabled() {
+		return nil
+	}
+	op := ratelimit.OpWrite
+	if access == auth.Read {
+		op = ratelimit.OpRead
+	}
+	if ok, retryAfter := s.limiter.Allow(c.info.Caller(), op); !ok {
+		return serverError(fmt.Sprintf("rate limit exceeded, retry after %ds", ratelimit.RetryAfterSeconds(retryAfter)))
+	}
+	return nil
+}
+
+// authenticate handles the ASCII authentication of memcached: until the connection
+// is authenticated, a set carries "<username> <token>" as data. Username is ignored,
+// the token is checked like a bearer token of the http api.
+func (s *Server) authenticate(c *conn, data string) error {
+	var token string
+	if _, err := fmt.Sscan(data, new(string), &token); err != nil {
+		return errAuthFailure
+	}
+	p, err := s.auth.Authenticate(token)
+	if err != nil {
+		return errAuthFailure
+	}
+	c.principal = p
+	c.info.Identity = p.Subject
+	return nil
+}
+
+// notLeaderError is returned when a command has to be served by the leader.
+type notLeaderError struct {
+	leaderID int
+}
+
+func (e *notLeaderError) Error() string {
+	return "node is not a leader"
+}
+
+// redirect builds the error a client gets from a follower. It points to the leader's
+// memcached address, which is its public http host with the port of this node.
+// Memcached clients don't follow redirects, so the client library has to be
+// configured with the leader or retry other servers on this error.
+func (s *Server) redirect(e *notLeaderError) string {
+	addr, ok := s.membership.HTTPAddr(e.leaderID)
+	if !ok {
+		return "SERVER_ERROR no leader available"
+	}
+	host := addr
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return "SERVER_ERROR not a leader, leader is at " + net.JoinHostPort(host, s.cfg.Port)
+}
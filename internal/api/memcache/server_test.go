@@ -0,0 +1,302 @@
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	internalRaft "github.com/shrtyk/kv-store/internal/core/raft"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	"github.com/shrtyk/kv-store/internal/core/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// localProposer commits every command right away and applies it with a real state machine.
+type localProposer struct {
+	raft    *rmocks.StubRaft
+	applyCh chan *raftapi.ApplyMessage
+	futures ftr.FuturesStore
+	index   atomic.Int64
+}
+
+func (p *localProposer) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	if _, isLeader := p.raft.State(); !isLeader {
+		return &proposer.Proposal{LeaderID: 1}, nil
+	}
+	idx := p.index.Add(1)
+	future := p.futures.NewFuture(idx)
+	p.applyCh <- &raftapi.ApplyMessage{CommandValid: true, Command: cmd, CommandIndex: idx}
+	return &proposer.Proposal{IsLeader: true, LogIndex: idx, Future: future}, nil
+}
+
+type testNode struct {
+	srv  *Server
+	raft *rmocks.StubRaft
+	addr string
+}
+
+func newTestNode(t *testing.T, authSvc *auth.Service, limiter *ratelimit.Limiter) *testNode {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	l, _ := tu.NewMockLogger()
+	stCfg := tu.NewMockStoreCfg()
+	shCfg := tu.NewMockShardsCfg()
+	shCfg.ShardsCount = 4
+	st := store.NewStore(&sync.WaitGroup{}, stCfg, shCfg, l)
+
+	raft := rmocks.NewStubRaft(st, true, 1)
+	raft.SetReadOnlyResult([]byte{}, nil)
+	futures := internalRaft.NewApplyFuture()
+	applyCh := make(chan *raftapi.ApplyMessage, 1)
+	fsm := internalRaft.NewFSM(l, st, nil, futures, applyCh, internalRaft.CompressionNone, nil, nil)
+	go fsm.Start(ctx)
+
+	prop := &localProposer{raft: raft, applyCh: applyCh, futures: futures}
+	membership := cluster.NewRegistry(3, []string{"http://a:8080", "http://b:8080", "http://c:8080"})
+	srv := NewServer(&sync.WaitGroup{}, &cfg.MemcachedCfg{Port: "11211"}, stCfg, st, l, raft, prop, membership,
+		authSvc, limiter, nil)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() {
+		sCtx, sCancel := context.WithTimeout(context.Background(), time.Second)
+		defer sCancel()
+		_ = srv.Shutdown(sCtx)
+	})
+
+	return &testNode{srv: srv, raft: raft, addr: ln.Addr().String()}
+}
+
+// client is a minimal memcached client working with reply lines.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) *client {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// do sends lines terminated with "\r\n" and reads a single reply line.
+func (c *client) do(lines ...string) string {
+	c.send(lines...)
+	return c.readLine()
+}
+
+func (c *client) send(lines ...string) {
+	_, err := c.conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n"))
+	require.NoError(c.t, err)
+}
+
+func (c *client) readLine() string {
+	line, err := c.r.ReadString('\n')
+	require.NoError(c.t, err)
+	return strings.TrimSuffix(line, "\r\n")
+}
+
+// readUntilEnd reads reply lines up to "END".
+func (c *client) readUntilEnd() []string {
+	var lines []string
+	for {
+		line := c.readLine()
+		if line == "END" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestServer_Storage(t *testing.T) {
+	n := newTestNode(t, nil, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, "STORED", c.do("set foo 5 0 3", "bar"))
+	c.send("get foo missing")
+	assert.Equal(t, []string{"VALUE foo 5 3", "bar"}, c.readUntilEnd())
+
+	assert.Equal(t, "NOT_STORED", c.do("add foo 0 0 1", "x"))
+	assert.Equal(t, "NOT_STORED", c.do("replace missing 0 0 1", "x"))
+	assert.Equal(t, "STORED", c.do("replace foo 7 0 3", "baz"))
+
+	c.send("gets foo")
+	lines := c.readUntilEnd()
+	require.Len(t, lines, 2)
+	fields := strings.Fields(lines[0])
+	require.Len(t, fields, 5)
+	assert.Equal(t, []string{"VALUE", "foo", "7", "3"}, fields[:4])
+	token := fields[4]
+
+	assert.Equal(t, "STORED", c.do("cas foo 1 0 3 "+token, "new"))
+	assert.Equal(t, "EXISTS", c.do("cas foo 1 0 3 "+token, "old"))
+	assert.Equal(t, "NOT_FOUND", c.do("cas missing 1 0 3 "+token, "old"))
+
+	assert.Equal(t, "DELETED", c.do("delete foo"))
+	assert.Equal(t, "NOT_FOUND", c.do("delete foo"))
+
+	// Values over the limit are skipped without breaking the stream.
+	assert.Equal(t, "SERVER_ERROR object too large for cache", c.do("set big 0 0 101", strings.Repeat("x", 101)))
+	assert.Equal(t, "CLIENT_ERROR bad data chunk", c.do("set foo 0 0 1", "xyz"))
+	assert.Equal(t, "ERROR", c.do("bogus"))
+	assert.Equal(t, "VERSION "+version, c.do("version"))
+}
+
+func TestServer_Counters(t *testing.T) {
+	n := newTestNode(t, nil, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, "NOT_FOUND", c.do("incr n 1"))
+	assert.Equal(t, "STORED", c.do("set n 3 0 2", "10"))
+	assert.Equal(t, "15", c.do("incr n 5"))
+	assert.Equal(t, "0", c.do("decr n 20"))
+	assert.Equal(t, "CLIENT_ERROR invalid numeric delta argument", c.do("incr n -1"))
+
+	// Counters keep flags of the value.
+	c.send("get n")
+	assert.Equal(t, []string{"VALUE n 3 1", "0"}, c.readUntilEnd())
+
+	assert.Equal(t, "STORED", c.do("set s 0 0 1", "a"))
+	assert.Equal(t, "CLIENT_ERROR cannot increment or decrement non-numeric value", c.do("incr s 1"))
+}
+
+func TestServer_Expiry(t *testing.T) {
+	n := newTestNode(t, nil, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, "STORED", c.do("set gone 0 -1 1", "a"))
+	c.send("get gone")
+	assert.Empty(t, c.readUntilEnd())
+
+	assert.Equal(t, "STORED", c.do("set k 0 100 1", "a"))
+	assert.Equal(t, "TOUCHED", c.do("touch k 0"))
+	assert.Equal(t, "NOT_FOUND", c.do("touch missing 10"))
+
+	// noreply commands are answered only by the next command.
+	c.send("set a 0 0 1 noreply", "a", "flush_all noreply")
+	assert.Equal(t, "VERSION "+version, c.do("version"))
+	c.send("get a k")
+	assert.Empty(t, c.readUntilEnd())
+
+	assert.Equal(t, "STORED", c.do("set later 0 0 1", "a"))
+	assert.Equal(t, "OK", c.do("flush_all 100"))
+	c.send("get later")
+	assert.Equal(t, []string{"VALUE later 0 1", "a"}, c.readUntilEnd())
+}
+
+func TestExpiresAt(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	assert.Zero(t, expiresAt(0, now))
+	assert.Equal(t, now, expiresAt(-1, now))
+	assert.Equal(t, now+10_000, expiresAt(10, now))
+	assert.Equal(t, int64(1_800_000_000_000), expiresAt(1_800_000_000, now))
+	assert.Equal(t, now, expiresAt(relativeExptimeLimit+1, now))
+}
+
+func TestServer_NotLeader(t *testing.T) {
+	n := newTestNode(t, nil, nil)
+	c := dial(t, n.addr)
+	n.raft.SetLeader(false)
+
+	assert.Equal(t, "SERVER_ERROR not a leader, leader is at b:11211", c.do("set foo 0 0 1", "a"))
+	c.send("get foo")
+	assert.Equal(t, "SERVER_ERROR not a leader, leader is at b:11211", c.readLine())
+}
+
+func TestServer_Auth(t *testing.T) {
+	authSvc, err := auth.NewService(&cfg.AuthCfg{
+		Enabled: true,
+		Tokens: []cfg.APITokenCfg{
+			{Token: "secret", Subject: "svc", Roles: []string{"jobs"}},
+		},
+		Roles: []cfg.RoleCfg{
+			{Name: "jobs", Rules: []cfg.RoleRuleCfg{{Prefix: "jobs:", Access: "write"}}},
+		},
+	})
+	require.NoError(t, err)
+	n := newTestNode(t, authSvc, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, "CLIENT_ERROR unauthenticated", c.do("get jobs:1"))
+	assert.Equal(t, "CLIENT_ERROR authentication failure", c.do("set auth 0 0 10", "user wrong"))
+	assert.Equal(t, "STORED", c.do("set auth 0 0 11", "user secret"))
+
+	assert.Equal(t, "STORED", c.do("set jobs:1 0 0 1", "v"))
+	assert.Contains(t, c.do("set other 0 0 1", "v"), "CLIENT_ERROR auth: permission denied")
+	assert.Contains(t, c.do("flush_all"), "CLIENT_ERROR auth: permission denied")
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	authSvc, err := auth.NewService(&cfg.AuthCfg{
+		Enabled: true,
+		Tokens: []cfg.APITokenCfg{
+			{Token: "a-secret", Subject: "a", Roles: []string{"all"}},
+			{Token: "b-secret", Subject: "b", Roles: []string{"all"}},
+		},
+		Roles: []cfg.RoleCfg{
+			{Name: "all", Rules: []cfg.RoleRuleCfg{{Prefix: "", Access: "write"}}},
+		},
+	})
+	require.NoError(t, err)
+	limiter := ratelimit.NewLimiter(&cfg.RateLimitCfg{
+		Enabled: true,
+		Read:    cfg.LimitCfg{Rate: 0.001, Burst: 3},
+		Write:   cfg.LimitCfg{Rate: 0.001, Burst: 1},
+	})
+	n := newTestNode(t, authSvc, limiter)
+
+	a := dial(t, n.addr)
+	assert.Equal(t, "STORED", a.do("set auth 0 0 10", "a a-secret"))
+	assert.Equal(t, "STORED", a.do("set k 0 0 1", "v"))
+	assert.Contains(t, a.do("set k 0 0 1", "v"), "SERVER_ERROR rate limit exceeded")
+	assert.Equal(t, "VALUE k 0 1", a.do("get k"), "reads are limited separately")
+	assert.Equal(t, []string{"v"}, a.readUntilEnd())
+
+	// Clients are limited by subject, not by connection.
+	other := dial(t, n.addr)
+	assert.Equal(t, "STORED", other.do("set auth 0 0 10", "a a-secret"))
+	assert.Contains(t, other.do("delete k"), "SERVER_ERROR rate limit exceeded")
+	other.send("get k", "get k")
+	assert.Equal(t, []string{"VALUE k 0 1", "v"}, other.readUntilEnd())
+	assert.Equal(t, []string{"VALUE k 0 1", "v"}, other.readUntilEnd())
+	assert.Contains(t, other.do("get k"), "SERVER_ERROR rate limit exceeded")
+
+	b := dial(t, n.addr)
+	assert.Equal(t, "STORED", b.do("set auth 0 0 10", "b b-secret"))
+	assert.Equal(t, "STORED", b.do("set k 0 0 1", "v"))
+	// Authentication attempts are limited by remote ip.
+	assert.Contains(t, dial(t, n.addr).do("set auth 0 0 10", "b b-secret"), "SERVER_ERROR rate limit exceeded")
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	n := newTestNode(t, nil, nil)
+	c := dial(t, n.addr)
+	require.Equal(t, "VERSION "+version, c.do("version"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, n.srv.Shutdown(ctx))
+
+	_, err := c.r.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+}
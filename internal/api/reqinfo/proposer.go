@@ -0,0 +1,42 @@
+package reqinfo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ proposer.Proposer = (*originProposer)(nil)
+
+// commandOriginField is the number of the Command field holding the origin of the command.
+var commandOriginField = (&fsm_v1.Command{}).ProtoReflect().Descriptor().Fields().ByName("origin").Number()
+
+// originProposer records the request info of ctx in commands before passing them to the next proposer,
+// so changes are audited with the caller once they are committed.
+type originProposer struct {
+	next proposer.Proposer
+}
+
+func NewOriginProposer(next proposer.Proposer) *originProposer {
+	return &originProposer{next: next}
+}
+
+func (p *originProposer) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	info := FromCtx(ctx)
+	if *info == (Info{}) {
+		return p.next.Propose(ctx, cmd)
+	}
+	origin, err := proto.Marshal(&fsm_v1.Origin{Caller: info.Caller(), Ip: info.IP, RequestId: info.RequestID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal command origin: %w", err)
+	}
+	// Parsing concatenated messages merges them, so the field is appended without unmarshaling the command.
+	data := protowire.AppendTag(slices.Clip(cmd), commandOriginField, protowire.BytesType)
+	data = protowire.AppendBytes(data, origin)
+	return p.next.Propose(ctx, data)
+}
@@ -0,0 +1,40 @@
+package reqinfo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	proposermocks "github.com/shrtyk/kv-store/internal/core/ports/proposer/mocks"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOriginProposer(t *testing.T) {
+	cmd, err := proto.Marshal(&fsm_v1.Command{Namespace: "team-a", Command: &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: "k", Value: "v"}}})
+	require.NoError(t, err)
+
+	next := proposermocks.NewMockProposer(t)
+	p := NewOriginProposer(next)
+
+	var got fsm_v1.Command
+	next.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+		got.Reset()
+		require.NoError(t, proto.Unmarshal(data, &got))
+		return &proposer.Proposal{IsLeader: true}, nil
+	}).Twice()
+
+	ctx := ToCtx(context.Background(), &Info{RequestID: "req-1", IP: "10.0.0.1", Identity: "alice"})
+	_, err = p.Propose(ctx, cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", got.GetNamespace())
+	assert.Equal(t, "k", got.GetPut().GetKey())
+	assert.True(t, proto.Equal(&fsm_v1.Origin{Caller: "alice", Ip: "10.0.0.1", RequestId: "req-1"}, got.GetOrigin()))
+
+	_, err = p.Propose(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Nil(t, got.GetOrigin(), "commands without a request have no origin")
+}
@@ -0,0 +1,35 @@
+package reqinfo
+
+import "context"
+
+// Info holds per-request metadata shared between transports and handlers.
+type Info struct {
+	RequestID string
+	IP        string
+	Identity  string
+}
+
+// Caller returns authenticated identity if known and client ip otherwise.
+func (i *Info) Caller() string {
+	if i.Identity != "" {
+		return i.Identity
+	}
+	return i.IP
+}
+
+type ctxKeyType string
+
+const (
+	ctxKey ctxKeyType = "request_info"
+)
+
+func ToCtx(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, ctxKey, info)
+}
+
+func FromCtx(ctx context.Context) *Info {
+	if info, ok := ctx.Value(ctxKey).(*Info); ok {
+		return info
+	}
+	return &Info{}
+}
@@ -0,0 +1,620 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"google.golang.org/protobuf/proto"
+)
+
+// redisError is a reply error. It starts with an error code like "ERR" or "NOAUTH".
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+var (
+	errSyntax     = redisError("ERR syntax error")
+	errNotInteger = redisError("ERR value is not an integer or out of range")
+	errNoAuth     = redisError("NOAUTH Authentication required.")
+	errWrongPass  = redisError("WRONGPASS invalid username-password pair or user is disabled.")
+)
+
+// noAuth marks commands which can be sent before authentication.
+const noAuth auth.Access = 0
+
+type command struct {
+	// arity is an exact number of arguments including the name or, if negative, a minimum one.
+	arity  int
+	access auth.Access
+	// Positions of keys like in Redis command table. Negative lastKey counts from the end.
+	firstKey, lastKey, step int
+	run                     func(s *Server, c *conn, args []string) error
+}
+
+// keys returns key arguments of the command.
+func (cmd *command) keys(args []string) []string {
+	if cmd.firstKey == 0 {
+		return nil
+	}
+	last := cmd.lastKey
+	if last < 0 {
+		last += len(args)
+	}
+	var keys []string
+	for i := cmd.firstKey; i <= last && i < len(args); i += max(cmd.step, 1) {
+		keys = append(keys, args[i])
+	}
+	return keys
+}
+
+var commands = map[string]*command{
+	"hello":     {arity: -1, access: noAuth, run: (*Server).hello},
+	"auth":      {arity: -2, access: noAuth, run: (*Server).authCmd},
+	"quit":      {arity: -1, access: noAuth, run: (*Server).quit},
+	"ping":      {arity: -1, access: auth.Read, run: (*Server).ping},
+	"echo":      {arity: 2, access: auth.Read, run: (*Server).echo},
+	"readonly":  {arity: 1, access: auth.Read, run: (*Server).readOnly},
+	"readwrite": {arity: 1, access: auth.Read, run: (*Server).readWrite},
+
+	"get":    {arity: 2, access: auth.Read, firstKey: 1, lastKey: 1, step: 1, run: (*Server).get},
+	"mget":   {arity: -2, access: auth.Read, firstKey: 1, lastKey: -1, step: 1, run: (*Server).mget},
+	"exists": {arity: -2, access: auth.Read, firstKey: 1, lastKey: -1, step: 1, run: (*Server).exists},
+	"ttl":    {arity: 2, access: auth.Read, firstKey: 1, lastKey: 1, step: 1, run: (*Server).ttl},
+	"pttl":   {arity: 2, access: auth.Read, firstKey: 1, lastKey: 1, step: 1, run: (*Server).ttl},
+	"keys":   {arity: 2, access: auth.Read, run: (*Server).keys},
+	"scan":   {arity: -2, access: auth.Read, run: (*Server).scan},
+
+	"set":    {arity: -3, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).set},
+	"mset":   {arity: -3, access: auth.Write, firstKey: 1, lastKey: -1, step: 2, run: (*Server).mset},
+	"del":    {arity: -2, access: auth.Write, firstKey: 1, lastKey: -1, step: 1, run: (*Server).del},
+	"incr":   {arity: 2, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).incr},
+	"decr":   {arity: 2, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).incr},
+	"incrby": {arity: 3, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).incr},
+	"decrby": {arity: 3, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).incr},
+	"expire": {arity: 3, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).expire},
+
+	"hget":    {arity: 3, access: auth.Read, firstKey: 1, lastKey: 1, step: 1, run: (*Server).hget},
+	"hgetall": {arity: 2, access: auth.Read, firstKey: 1, lastKey: 1, step: 1, run: (*Server).hgetall},
+	"hset":    {arity: -4, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).hset},
+	"hdel":    {arity: -3, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).hdel},
+	"hincrby": {arity: 4, access: auth.Write, firstKey: 1, lastKey: 1, step: 1, run: (*Server).hincrby},
+}
+
+// errorReply maps an error into a reply error.
+func (s *Server) errorReply(err error) string {
+	var rerr redisError
+	var nl *notLeaderError
+	switch {
+	case errors.As(err, &rerr):
+		return string(rerr)
+	case errors.As(err, &nl):
+		return s.redirect(nl)
+	case errors.Is(err, admission.ErrOverloaded), errors.Is(err, admission.ErrDraining):
+		return "TRYAGAIN " + err.Error()
+	case errors.Is(err, ftr.ErrPromiseTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "ERR request timed out: raft cluster is busy"
+	case errors.Is(err, store.ErrQuotaExceeded):
+		return "OOM " + err.Error()
+	case errors.Is(err, store.ErrNotInteger):
+		return string(errNotInteger)
+	case errors.Is(err, store.ErrWrongType):
+		return string(errWrongType)
+	default:
+		return "ERR " + err.Error()
+	}
+}
+
+func (s *Server) hello(c *conn, args []string) error {
+	protover := c.w.proto
+	rest := args[1:]
+	if len(rest) > 0 {
+		v, err := strconv.Atoi(rest[0])
+		if err != nil {
+			return redisError("ERR Protocol version is not an integer or out of range")
+		}
+		if v != 2 && v != 3 {
+			return redisError("NOPROTO unsupported protocol version")
+		}
+		protover, rest = v, rest[1:]
+	}
+	for len(rest) > 0 {
+		switch strings.ToLower(rest[0]) {
+		case "auth":
+			if len(rest) < 3 {
+				return errSyntax
+			}
+			if err := s.authenticate(c, rest[2]); err != nil {
+				return err
+			}
+			rest = rest[3:]
+		case "setname":
+			if len(rest) < 2 {
+				return errSyntax
+			}
+			rest = rest[2:]
+		default:
+			return errSyntax
+		}
+	}
+	if s.auth.Enabled() && c.principal == nil {
+		return errNoAuth
+	}
+
+	c.w.proto = protover
+	role := "replica"
+	if _, isLeader := s.raft.State(); isLeader {
+		role = "master"
+	}
+	c.w.mapHeader(7)
+	c.w.bulk("server")
+	c.w.bulk("kv-store")
+	c.w.bulk("version")
+	c.w.bulk("1.0.0")
+	c.w.bulk("proto")
+	c.w.integer(int64(protover))
+	c.w.bulk("id")
+	c.w.integer(c.id)
+	c.w.bulk("mode")
+	c.w.bulk("standalone")
+	c.w.bulk("role")
+	c.w.bulk(role)
+	c.w.bulk("modules")
+	c.w.array(0)
+	return nil
+}
+
+// authCmd accepts "AUTH <token>" and "AUTH <username> <token>". Username is ignored,
+// the token is checked like a bearer token of the http api.
+func (s *Server) authCmd(c *conn, args []string) error {
+	if len(args) > 3 {
+		return errSyntax
+	}
+	if err := s.authenticate(c, args[len(args)-1]); err != nil {
+		return err
+	}
+	c.w.simple("OK")
+	return nil
+}
+
+func (s *Server) authenticate(c *conn, credential string) error {
+	if !s.auth.Enabled() {
+		return redisError("ERR AUTH called without any password configured for the default user. Are you sure your configuration is correct?")
+	}
+	p, err := s.auth.Authenticate(credential)
+	if err != nil {
+		return errWrongPass
+	}
+	c.principal = p
+	c.info.Identity = p.Subject
+	return nil
+}
+
+func (s *Server) quit(c *conn, _ []string) error {
+	c.quit = true
+	c.w.simple("OK")
+	return nil
+}
+
+func (s *Server) ping(c *conn, args []string) error {
+	switch len(args) {
+	case 1:
+		c.w.simple("PONG")
+	case 2:
+		c.w.bulk(args[1])
+	default:
+		return redisError("ERR wrong number of arguments for 'ping' command")
+	}
+	return nil
+}
+
+func (s *Server) echo(c *conn, args []string) error {
+	c.w.bulk(args[1])
+	return nil
+}
+
+// readOnly lets the connection read from a follower. Such reads may be stale.
+func (s *Server) readOnly(c *conn, _ []string) error {
+	c.replicaReads = true
+	c.w.simple("OK")
+	return nil
+}
+
+func (s *Server) readWrite(c *conn, _ []string) error {
+	c.replicaReads = false
+	c.w.simple("OK")
+	return nil
+}
+
+func (s *Server) get(c *conn, args []string) error {
+	if err := s.readBarrier(c, args[1]); err != nil {
+		return err
+	}
+	val, err := s.store.Get(args[1])
+	if errors.Is(err, store.ErrNoSuchKey) {
+		c.w.null()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.w.bulk(val)
+	return nil
+}
+
+func (s *Server) mget(c *conn, args []string) error {
+	if err := s.readBarrier(c, args[1]); err != nil {
+		return err
+	}
+	c.w.array(len(args) - 1)
+	for _, key := range args[1:] {
+		if val, err := s.store.Get(key); err == nil {
+			c.w.bulk(val)
+		} else {
+			c.w.null()
+		}
+	}
+	return nil
+}
+
+func (s *Server) exists(c *conn, args []string) error {
+	if err := s.readBarrier(c, args[1]); err != nil {
+		return err
+	}
+	var n int64
+	now := time.Now().UnixMilli()
+	for _, key := range args[1:] {
+		if _, err := s.store.Lookup(key, now); err == nil {
+			n++
+		}
+	}
+	c.w.integer(n)
+	return nil
+}
+
+// ttl serves TTL and PTTL: -2 if the key doesn't exist and -1 if it doesn't expire.
+func (s *Server) ttl(c *conn, args []string) error {
+	if err := s.readBarrier(c, args[1]); err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	e, err := s.store.Lookup(args[1], now)
+	switch {
+	case errors.Is(err, store.ErrNoSuchKey):
+		c.w.integer(-2)
+	case err != nil:
+		return err
+	case e.ExpiresAt == 0:
+		c.w.integer(-1)
+	case strings.EqualFold(args[0], "pttl"):
+		c.w.integer(e.ExpiresAt - now)
+	default:
+		c.w.integer((e.ExpiresAt - now + 500) / 1000)
+	}
+	return nil
+}
+
+func (s *Server) keys(c *conn, args []string) error {
+	if err := s.readBarrier(c, ""); err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	var keys []string
+	for cursor := 0; ; {
+		var batch []string
+		batch, cursor = s.store.ScanShard(cursor, now)
+		keys = s.appendMatching(c, keys, batch, args[1])
+		if cursor == 0 {
+			break
+		}
+	}
+	c.w.bulks(keys)
+	return nil
+}
+
+// scan iterates over keys shard by shard, the cursor is an index of the next shard.
+// Keys written or deleted during iteration may be missed or returned, like in Redis.
+func (s *Server) scan(c *conn, args []string) error {
+	cursor, err := strconv.Atoi(args[1])
+	if err != nil || cursor < 0 {
+		return redisError("ERR invalid cursor")
+	}
+	pattern, count := "*", 10
+	for i := 2; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return errSyntax
+		}
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			if count, err = strconv.Atoi(args[i+1]); err != nil {
+				return errNotInteger
+			}
+			if count < 1 {
+				return errSyntax
+			}
+		default:
+			return errSyntax
+		}
+	}
+
+	if err := s.readBarrier(c, ""); err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	keys := []string{}
+	for {
+		var batch []string
+		batch, cursor = s.store.ScanShard(cursor, now)
+		keys = s.appendMatching(c, keys, batch, pattern)
+		if cursor == 0 || len(keys) >= count {
+			break
+		}
+	}
+	c.w.array(2)
+	c.w.bulk(strconv.Itoa(cursor))
+	c.w.bulks(keys)
+	return nil
+}
+
+// appendMatching appends keys matching the pattern the connection is allowed to read.
+func (s *Server) appendMatching(c *conn, dst, keys []string, pattern string) []string {
+	for _, key := range keys {
+		if !match(pattern, key) {
+			continue
+		}
+		if s.auth.Enabled() && s.auth.Authorize(c.principal, auth.Read, "", key) != nil {
+			continue
+		}
+		dst = append(dst, key)
+	}
+	return dst
+}
+
+func (s *Server) set(c *conn, args []string) error {
+	key, value := args[1], args[2]
+	cond := fsm_v1.SetCondition_SET_CONDITION_NONE
+	var ttl int64
+	for i := 3; i < len(args); i++ {
+		switch opt := strings.ToUpper(args[i]); opt {
+		case "NX", "XX":
+			if cond != fsm_v1.SetCondition_SET_CONDITION_NONE {
+				return errSyntax
+			}
+			cond = fsm_v1.SetCondition_SET_CONDITION_NOT_EXISTS
+			if opt == "XX" {
+				cond = fsm_v1.SetCondition_SET_CONDITION_EXISTS
+			}
+		case "EX", "PX":
+			if ttl != 0 || i+1 >= len(args) {
+				return errSyntax
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return errNotInteger
+			}
+			unit := int64(1)
+			if opt == "EX" {
+				unit = 1000
+			}
+			if n <= 0 || n > math.MaxInt64/2/unit {
+				return redisError("ERR invalid expire time in 'set' command")
+			}
+			ttl = n * unit
+			i++
+		default:
+			return errSyntax
+		}
+	}
+	if err := s.checkSize(key, value); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = now + ttl
+	}
+	prop, err := s.propose(c, key, &fsm_v1.Command{Command: &fsm_v1.Command_Set{Set: &fsm_v1.SetCommand{
+		Key:       key,
+		Value:     value,
+		Condition: cond,
+		ExpiresAt: expiresAt,
+	}}})
+	if err != nil {
+		return err
+	}
+	if string(prop.Future.Data()) != "1" {
+		c.w.null()
+		return nil
+	}
+	c.w.simple("OK")
+	return nil
+}
+
+func (s *Server) mset(c *conn, args []string) error {
+	if len(args)%2 == 0 {
+		return redisError("ERR wrong number of arguments for 'mset' command")
+	}
+	puts := make([]*fsm_v1.PutCommand, 0, len(args)/2)
+	for i := 1; i < len(args); i += 2 {
+		if err := s.checkSize(args[i], args[i+1]); err != nil {
+			return err
+		}
+		puts = append(puts, &fsm_v1.PutCommand{Key: args[i], Value: args[i+1]})
+	}
+
+	_, err := s.propose(c, args[1], &fsm_v1.Command{Command: &fsm_v1.Command_Mset{Mset: &fsm_v1.MSetCommand{Puts: puts}}})
+	if err != nil {
+		return err
+	}
+	c.w.simple("OK")
+	return nil
+}
+
+func (s *Server) del(c *conn, args []string) error {
+	keys := args[1:]
+	prop, err := s.propose(c, keys[0], &fsm_v1.Command{Command: &fsm_v1.Command_Del{Del: &fsm_v1.DelCommand{
+		Keys: keys,
+	}}})
+	if err != nil {
+		return err
+	}
+	deleted, err := strconv.ParseInt(string(prop.Future.Data()), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected del result: %w", err)
+	}
+	c.w.integer(deleted)
+	return nil
+}
+
+// incr serves INCR, DECR, INCRBY and DECRBY.
+func (s *Server) incr(c *conn, args []string) error {
+	name, key := strings.ToLower(args[0]), args[1]
+	delta := int64(1)
+	if len(args) == 3 {
+		n, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return errNotInteger
+		}
+		delta = n
+	}
+	if strings.HasPrefix(name, "decr") {
+		if delta == math.MinInt64 {
+			return redisError("ERR decrement would overflow")
+		}
+		delta = -delta
+	}
+	if err := s.checkSize(key, ""); err != nil {
+		return err
+	}
+
+	prop, err := s.propose(c, key, &fsm_v1.Command{Command: &fsm_v1.Command_Incr{Incr: &fsm_v1.IncrCommand{
+		Key:   key,
+		Delta: delta,
+	}}})
+	if err != nil {
+		return err
+	}
+	data := prop.Future.Data()
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected incr result: %w", err)
+	}
+	c.w.integer(n)
+	return nil
+}
+
+// expire sets expiry in seconds. Non-positive timeout deletes the key.
+func (s *Server) expire(c *conn, args []string) error {
+	key := args[1]
+	secs, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return errNotInteger
+	}
+	if secs > math.MaxInt64/2/1000 || secs < math.MinInt64/2/1000 {
+		return redisError("ERR invalid expire time in 'expire' command")
+	}
+
+	now := time.Now().UnixMilli()
+	expiresAt := max(now+secs*1000, now)
+	prop, err := s.propose(c, key, &fsm_v1.Command{Command: &fsm_v1.Command_Expire{Expire: &fsm_v1.ExpireCommand{
+		Key:       key,
+		ExpiresAt: expiresAt,
+	}}})
+	if err != nil {
+		return err
+	}
+	if string(prop.Future.Data()) != "1" {
+		c.w.integer(0)
+		return nil
+	}
+	c.w.integer(1)
+	return nil
+}
+
+func (s *Server) checkSize(key, value string) error {
+	if len(key) > s.stCfg.MaxKeySize {
+		return store.ErrKeyTooLarge
+	}
+	if len(value) > s.stCfg.MaxValSize {
+		return store.ErrValueTooLarge
+	}
+	return nil
+}
+
+// readBarrier makes sure local state reflects every write committed before the read,
+// unless the connection opted in for stale reads from followers.
+func (s *Server) readBarrier(c *conn, key string) error {
+	if err := s.admission.AdmitRead(); err != nil {
+		return err
+	}
+	if c.replicaReads {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(context.Background(), s.stCfg.ReadTimeout)
+	defer cancel()
+	res, err := s.raft.ReadOnly(ctx, nil)
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return err
+	}
+	if res != nil && !res.IsLeader {
+		return &notLeaderError{leaderID: res.LeaderId, key: key}
+	}
+	return nil
+}
+
+// propose submits the command and waits until it's applied.
+func (s *Server) propose(c *conn, key string, cmd *fsm_v1.Command) (*proposer.Proposal, error) {
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	done, err := s.admission.AdmitWrite()
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() { done(committed) }()
+
+	ctx, cancel := withTimeout(reqinfo.ToCtx(context.Background(), c.info), s.stCfg.WriteTimeout)
+	defer cancel()
+
+	prop, err := s.proposer.Propose(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if !prop.IsLeader {
+		return nil, &notLeaderError{leaderID: prop.LeaderID, key: key, write: true}
+	}
+	err = prop.Future.Wait(ctx)
+	committed = admission.Committed(err)
+	if err != nil {
+		return nil, err
+	}
+	return prop, nil
+}
+
+// withTimeout limits ctx with timeout. Zero timeout means no limit.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,125 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+var errWrongType = redisError("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+// hset serves HSET with one or more field value pairs.
+func (s *Server) hset(c *conn, args []string) error {
+	if len(args)%2 != 0 {
+		return redisError("ERR wrong number of arguments for 'hset' command")
+	}
+	key := args[1]
+	fields := make(map[string]string, (len(args)-2)/2)
+	for i := 2; i < len(args); i += 2 {
+		if err := s.checkSize(args[i], args[i+1]); err != nil {
+			return err
+		}
+		fields[args[i]] = args[i+1]
+	}
+	if err := s.checkSize(key, ""); err != nil {
+		return err
+	}
+
+	prop, err := s.propose(c, key, &fsm_v1.Command{Command: &fsm_v1.Command_Hset{Hset: &fsm_v1.HSetCommand{
+		Key:    key,
+		Fields: fields,
+	}}})
+	if err != nil {
+		return err
+	}
+	added, err := strconv.ParseInt(string(prop.Future.Data()), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected hset result: %w", err)
+	}
+	c.w.integer(added)
+	return nil
+}
+
+func (s *Server) hget(c *conn, args []string) error {
+	if err := s.readBarrier(c, args[1]); err != nil {
+		return err
+	}
+	val, err := s.store.HGet(args[1], args[2])
+	if errors.Is(err, store.ErrNoSuchKey) {
+		c.w.null()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.w.bulk(val)
+	return nil
+}
+
+func (s *Server) hgetall(c *conn, args []string) error {
+	if err := s.readBarrier(c, args[1]); err != nil {
+		return err
+	}
+	fields, err := s.store.HGetAll(args[1])
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return err
+	}
+	c.w.mapHeader(len(fields))
+	for _, f := range slices.Sorted(maps.Keys(fields)) {
+		c.w.bulk(f)
+		c.w.bulk(fields[f])
+	}
+	return nil
+}
+
+func (s *Server) hdel(c *conn, args []string) error {
+	key := args[1]
+	prop, err := s.propose(c, key, &fsm_v1.Command{Command: &fsm_v1.Command_Hdel{Hdel: &fsm_v1.HDelCommand{
+		Key:    key,
+		Fields: args[2:],
+	}}})
+	if err != nil {
+		return err
+	}
+	deleted, err := strconv.ParseInt(string(prop.Future.Data()), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected hdel result: %w", err)
+	}
+	c.w.integer(deleted)
+	return nil
+}
+
+func (s *Server) hincrby(c *conn, args []string) error {
+	key, field := args[1], args[2]
+	delta, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil {
+		return errNotInteger
+	}
+	if err := s.checkSize(key, ""); err != nil {
+		return err
+	}
+	if err := s.checkSize(field, ""); err != nil {
+		return err
+	}
+
+	prop, err := s.propose(c, key, &fsm_v1.Command{Command: &fsm_v1.Command_Hincrby{Hincrby: &fsm_v1.HIncrByCommand{
+		Key:   key,
+		Field: field,
+		Delta: delta,
+	}}})
+	if err != nil {
+		return err
+	}
+	data := prop.Future.Data()
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected hincrby result: %w", err)
+	}
+	c.w.integer(n)
+	return nil
+}
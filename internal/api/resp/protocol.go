@@ -0,0 +1,181 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxArgs limits number of arguments of a single command.
+	maxArgs = 1024 * 1024
+	// maxInlineSize limits length of an inline command and of a line with a length prefix.
+	maxInlineSize = 64 * 1024
+)
+
+// protocolError is a malformed request. The connection is closed after it's reported.
+type protocolError string
+
+func (e protocolError) Error() string {
+	return "ERR Protocol error: " + string(e)
+}
+
+// reader parses commands sent as RESP arrays of bulk strings or as inline commands.
+type reader struct {
+	r *bufio.Reader
+	// maxBulk limits size of a single argument.
+	maxBulk int
+}
+
+func newReader(r io.Reader, maxBulk int) *reader {
+	return &reader{r: bufio.NewReaderSize(r, maxInlineSize), maxBulk: maxBulk}
+}
+
+// buffered reports whether the client already sent more data, so replies
+// to pipelined commands are flushed together.
+func (r *reader) buffered() bool {
+	return r.r.Buffered() > 0
+}
+
+// readCommand returns arguments of the next command. Empty inline lines are skipped.
+func (r *reader) readCommand() ([]string, error) {
+	for {
+		b, err := r.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] == '*' {
+			return r.readArray()
+		}
+
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if args := strings.Fields(line); len(args) > 0 {
+			return args, nil
+		}
+	}
+}
+
+func (r *reader) readArray() ([]string, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > maxArgs {
+		return nil, protocolError("invalid multibulk length")
+	}
+
+	args := make([]string, 0, max(n, 0))
+	for range n {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if line == "" || line[0] != '$' {
+			return nil, protocolError(fmt.Sprintf("expected '$', got '%.1s'", line))
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > r.maxBulk {
+			return nil, protocolError("invalid bulk length")
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r.r, buf); err != nil {
+			return nil, err
+		}
+		if buf[size] != '\r' || buf[size+1] != '\n' {
+			return nil, protocolError("bulk string is not terminated by CRLF")
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// readLine returns a line without its terminator. Both CRLF and LF are accepted.
+func (r *reader) readLine() (string, error) {
+	line, err := r.r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return "", protocolError("too big inline request")
+	}
+	if err != nil {
+		return "", err
+	}
+	line = line[:len(line)-1]
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return string(line), nil
+}
+
+// writer encodes replies in the protocol version negotiated with HELLO.
+type writer struct {
+	w     *bufio.Writer
+	proto int
+}
+
+func newWriter(w io.Writer) *writer {
+	return &writer{w: bufio.NewWriter(w), proto: 2}
+}
+
+func (w *writer) flush() error {
+	return w.w.Flush()
+}
+
+func (w *writer) simple(s string) {
+	w.line('+', s)
+}
+
+func (w *writer) error(s string) {
+	w.line('-', s)
+}
+
+func (w *writer) integer(n int64) {
+	w.line(':', strconv.FormatInt(n, 10))
+}
+
+func (w *writer) bulk(s string) {
+	w.line('$', strconv.Itoa(len(s)))
+	_, _ = w.w.WriteString(s)
+	_, _ = w.w.WriteString("\r\n")
+}
+
+// null writes a missing value: RESP3 null or RESP2 null bulk string.
+func (w *writer) null() {
+	if w.proto >= 3 {
+		_, _ = w.w.WriteString("_\r\n")
+		return
+	}
+	w.line('$', "-1")
+}
+
+func (w *writer) array(n int) {
+	w.line('*', strconv.Itoa(n))
+}
+
+// mapHeader starts a map of n pairs. RESP2 has no maps, so it's sent as a flat array.
+func (w *writer) mapHeader(n int) {
+	if w.proto >= 3 {
+		w.line('%', strconv.Itoa(n))
+		return
+	}
+	w.array(2 * n)
+}
+
+func (w *writer) bulks(vals []string) {
+	w.array(len(vals))
+	for _, v := range vals {
+		w.bulk(v)
+	}
+}
+
+func (w *writer) line(prefix byte, s string) {
+	_ = w.w.WriteByte(prefix)
+	_, _ = w.w.WriteString(s)
+	_, _ = w.w.WriteString("\r\n")
+}
@@ -0,0 +1,72 @@
+package resp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	r := newReader(strings.NewReader("*2\r\n$3\r\nGET\r\n$1\r\nk\r\n\r\nSET  k v\nPING\r\n"), 16)
+
+	args, err := r.readCommand()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET", "k"}, args)
+
+	args, err = r.readCommand()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SET", "k", "v"}, args)
+
+	args, err = r.readCommand()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PING"}, args)
+
+	_, err = newReader(strings.NewReader("*1\r\n$17\r\n"), 16).readCommand()
+	assert.Equal(t, protocolError("invalid bulk length"), err)
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	w.null()
+	w.mapHeader(1)
+	w.proto = 3
+	w.null()
+	w.mapHeader(1)
+	require.NoError(t, w.flush())
+
+	assert.Equal(t, "$-1\r\n*2\r\n_\r\n%1\r\n", buf.String())
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"*", "", true},
+		{"user:*", "user:1", true},
+		{"user:*", "order:1", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h*llo", "heeello", true},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-b]llo", "hbllo", true},
+		{`h\*llo`, "h*llo", true},
+		{`h\*llo`, "hello", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, match(tt.pattern, tt.key), "%q %q", tt.pattern, tt.key)
+	}
+}
+
+func TestKeySlot(t *testing.T) {
+	assert.Equal(t, 12182, keySlot("foo"))
+	assert.Equal(t, keySlot("bar"), keySlot("{bar}.baz"))
+	assert.Equal(t, keySlot("{}x"), int(crc16("{}x"))%slotsCount)
+}
@@ -0,0 +1,336 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/api/reqinfo"
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	raftapi "github.com/shrtyk/raft-core/api"
+)
+
+const (
+	RedirectMoved    = "moved"
+	RedirectReadOnly = "readonly"
+)
+
+// Server serves a subset of Redis commands over RESP2 and RESP3.
+type Server struct {
+	wg         *sync.WaitGroup
+	cfg        *cfg.RESPCfg
+	stCfg      *cfg.StoreCfg
+	store      store.Store
+	logger     *slog.Logger
+	raft       raftapi.Raft
+	proposer   proposer.Proposer
+	membership cluster.Membership
+	auth       *auth.Service
+	limiter    *ratelimit.Limiter
+	admission  *admission.Controller
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[*conn]struct{}
+	connsWG  sync.WaitGroup
+	closing  atomic.Bool
+	lastID   atomic.Int64
+}
+
+func NewServer(
+	wg *sync.WaitGroup,
+	c *cfg.RESPCfg,
+	stCfg *cfg.StoreCfg,
+	st store.Store,
+	l *slog.Logger,
+	raft raftapi.Raft,
+	prop proposer.Proposer,
+	membership cluster.Membership,
+	authSvc *auth.Service,
+	limiter *ratelimit.Limiter,
+	adm *admission.Controller,
+) *Server {
+	return &Server{
+		wg:         wg,
+		cfg:        c,
+		stCfg:      stCfg,
+		store:      st,
+		logger:     l,
+		raft:       raft,
+		proposer:   prop,
+		membership: membership,
+		auth:       authSvc,
+		limiter:    limiter,
+		admission:  adm,
+		conns:      make(map[*conn]struct{}),
+	}
+}
+
+func (s *Server) MustStart() {
+	l, err := net.Listen("tcp", ":"+s.cfg.Port)
+	if err != nil {
+		msg := fmt.Sprintf("failed create net.Listener: %s", err)
+		panic(msg)
+	}
+
+	s.wg.Go(func() {
+		if err := s.Serve(l); err != nil {
+			msg := fmt.Sprintf("failed to start resp server: %s", err)
+			panic(msg)
+		}
+	})
+}
+
+// Serve accepts connections on l until the server is shut down.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			if s.closing.Load() {
+				return nil
+			}
+			return err
+		}
+
+		c := s.newConn(nc)
+		s.mu.Lock()
+		if s.closing.Load() {
+			s.mu.Unlock()
+			_ = nc.Close()
+			continue
+		}
+		s.conns[c] = struct{}{}
+		s.connsWG.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.connsWG.Done()
+			s.handle(c)
+		}()
+	}
+}
+
+// Shutdown stops accepting connections and lets connections finish commands they are
+// executing. Connections still open when ctx is done are closed forcibly. Nil server is a no-op.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.closing.Store(true)
+
+	s.mu.Lock()
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for c := range s.conns {
+		// Unblocks connections waiting for the next command.
+		_ = c.nc.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.connsWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		s.logger.Warn("resp server graceful shutdown time out; closing connections")
+		s.mu.Lock()
+		for c := range s.conns {
+			_ = c.nc.Close()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	case <-done:
+		s.logger.Info("resp server graceful shutdown complete")
+		return nil
+	}
+}
+
+// conn is a state of a single client connection.
+type conn struct {
+	id   int64
+	nc   net.Conn
+	r    *reader
+	w    *writer
+	info *reqinfo.Info
+
+	principal *auth.Principal
+	// replicaReads is set by READONLY command. Reads are served from local state
+	// even on followers and may be stale.
+	replicaReads bool
+	quit         bool
+}
+
+func (s *Server) newConn(nc net.Conn) *conn {
+	info := &reqinfo.Info{}
+	if host, _, err := net.SplitHostPort(nc.RemoteAddr().String()); err == nil {
+		info.IP = host
+	}
+	return &conn{
+		id:   s.lastID.Add(1),
+		nc:   nc,
+		r:    newReader(nc, max(s.stCfg.MaxKeySize, s.stCfg.MaxValSize)),
+		w:    newWriter(nc),
+		info: info,
+	}
+}
+
+func (s *Server) handle(c *conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		_ = c.nc.Close()
+	}()
+
+	for !c.quit && !s.closing.Load() {
+		if s.cfg.IdleTimeout > 0 {
+			_ = c.nc.SetReadDeadline(time.Now().Add(s.cfg.IdleTimeout))
+		}
+		args, err := c.r.readCommand()
+		if err != nil {
+			var perr protocolError
+			if errors.As(err, &perr) {
+				c.w.error(perr.Error())
+				_ = c.w.flush()
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) && !isTimeout(err) {
+				s.logger.Debug("failed to read resp command", logger.ErrorAttr(err))
+			}
+			return
+		}
+
+		s.exec(c, args)
+		if c.r.buffered() {
+			continue
+		}
+		if err := c.w.flush(); err != nil {
+			s.logger.Debug("failed to write resp reply", logger.ErrorAttr(err))
+			return
+		}
+	}
+	_ = c.w.flush()
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// exec runs a command and writes its reply.
+func (s *Server) exec(c *conn, args []string) {
+	name := strings.ToLower(args[0])
+	cmd, ok := commands[name]
+	if !ok {
+		c.w.error(fmt.Sprintf("ERR unknown command '%s', with args beginning with: %s", args[0], quoteArgs(args[1:])))
+		return
+	}
+	if (cmd.arity > 0 && len(args) != cmd.arity) || (cmd.arity < 0 && len(args) < -cmd.arity) {
+		c.w.error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", name))
+		return
+	}
+	if cmd.access != noAuth {
+		if err := s.authorize(c, cmd, args); err != nil {
+			c.w.error(s.errorReply(err))
+			return
+		}
+	}
+	if err := s.limit(c, cmd); err != nil {
+		c.w.error(s.errorReply(err))
+		return
+	}
+
+	if err := cmd.run(s, c, args); err != nil {
+		c.w.error(s.errorReply(err))
+	}
+}
+
+func quoteArgs(args []string) string {
+	var b strings.Builder
+	for _, a := range args {
+		fmt.Fprintf(&b, "'%s' ", a)
+	}
+	return b.String()
+}
+
+// authorize checks credentials and permissions on every key of the command.
+func (s *Server) authorize(c *conn, cmd *command, args []string) error {
+	if !s.auth.Enabled() {
+		return nil
+	}
+	if c.principal == nil {
+		return errNoAuth
+	}
+	for _, key := range cmd.keys(args) {
+		if err := s.auth.Authorize(c.principal, cmd.access, "", key); err != nil {
+			return redisError("NOPERM " + err.Error())
+		}
+	}
+	return nil
+}
+
+// limit takes a token of the client, the authenticated subject or the remote ip.
+// Commands allowed before authentication are limited as reads, so AUTH can't be brute forced.
+func (s *Server) limit(c *conn, cmd *command) error {
+	if !s.limiter.Enabled() {
+		return nil
+	}
+	op := ratelimit.OpWrite
+	if cmd.access == auth.Read || cmd.access == noAuth {
+		op = ratelimit.OpRead
+	}
+	if ok, retryAfter := s.limiter.Allow(c.info.Caller(), op); !ok {
+		return redisError(fmt.Sprintf("ERR rate limit exceeded, retry after %ds", ratelimit.RetryAfterSeconds(retryAfter)))
+	}
+	return nil
+}
+
+// notLeaderError is returned when a command has to be served by the leader.
+type notLeaderError struct {
+	leaderID int
+	key      string
+	write    bool
+}
+
+func (e *notLeaderError) Error() string {
+	return "node is not a leader"
+}
+
+// redirect builds the error a client gets from a follower. MOVED points to the
+// leader's RESP address, which is its public http host with the port of this node.
+func (s *Server) redirect(e *notLeaderError) string {
+	if e.write && s.cfg.Redirect == RedirectReadOnly {
+		return "READONLY You can't write against a read only replica."
+	}
+
+	addr, ok := s.membership.HTTPAddr(e.leaderID)
+	if !ok {
+		return "CLUSTERDOWN no leader available"
+	}
+	host := addr
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return fmt.Sprintf("MOVED %d %s", keySlot(e.key), net.JoinHostPort(host, s.cfg.Port))
+}
@@ -0,0 +1,428 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/auth"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	internalRaft "github.com/shrtyk/kv-store/internal/core/raft"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ratelimit"
+	"github.com/shrtyk/kv-store/internal/core/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// localProposer commits every command right away and applies it with a real state machine.
+type localProposer struct {
+	raft    *rmocks.StubRaft
+	applyCh chan *raftapi.ApplyMessage
+	futures ftr.FuturesStore
+	index   atomic.Int64
+}
+
+func (p *localProposer) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	if _, isLeader := p.raft.State(); !isLeader {
+		return &proposer.Proposal{LeaderID: 1}, nil
+	}
+	idx := p.index.Add(1)
+	future := p.futures.NewFuture(idx)
+	p.applyCh <- &raftapi.ApplyMessage{CommandValid: true, Command: cmd, CommandIndex: idx}
+	return &proposer.Proposal{IsLeader: true, LogIndex: idx, Future: future}, nil
+}
+
+type testNode struct {
+	srv  *Server
+	raft *rmocks.StubRaft
+	addr string
+}
+
+func newTestNode(t *testing.T, respCfg *cfg.RESPCfg, authSvc *auth.Service, limiter *ratelimit.Limiter) *testNode {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	l, _ := tu.NewMockLogger()
+	stCfg := tu.NewMockStoreCfg()
+	shCfg := tu.NewMockShardsCfg()
+	shCfg.ShardsCount = 4
+	st := store.NewStore(&sync.WaitGroup{}, stCfg, shCfg, l)
+
+	raft := rmocks.NewStubRaft(st, true, 1)
+	raft.SetReadOnlyResult([]byte{}, nil)
+	futures := internalRaft.NewApplyFuture()
+	applyCh := make(chan *raftapi.ApplyMessage, 1)
+	fsm := internalRaft.NewFSM(l, st, nil, futures, applyCh, internalRaft.CompressionNone, nil, nil)
+	go fsm.Start(ctx)
+
+	prop := &localProposer{raft: raft, applyCh: applyCh, futures: futures}
+	membership := cluster.NewRegistry(3, []string{"http://a:8080", "http://b:8080", "http://c:8080"})
+	srv := NewServer(&sync.WaitGroup{}, respCfg, stCfg, st, l, raft, prop, membership,
+		authSvc, limiter, nil)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() {
+		sCtx, sCancel := context.WithTimeout(context.Background(), time.Second)
+		defer sCancel()
+		_ = srv.Shutdown(sCtx)
+	})
+
+	return &testNode{srv: srv, raft: raft, addr: ln.Addr().String()}
+}
+
+// replyError is an error reply read by the test client.
+type replyError string
+
+// client is a minimal RESP client. Replies are decoded into string, int64,
+// replyError, nil, []any and map[string]any.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) *client {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *client) send(args ...string) {
+	buf := fmt.Appendf(nil, "*%d\r\n", len(args))
+	for _, a := range args {
+		buf = fmt.Appendf(buf, "$%d\r\n%s\r\n", len(a), a)
+	}
+	_, err := c.conn.Write(buf)
+	require.NoError(c.t, err)
+}
+
+func (c *client) do(args ...string) any {
+	c.send(args...)
+	return c.read()
+}
+
+func (c *client) read() any {
+	line, err := c.r.ReadString('\n')
+	require.NoError(c.t, err)
+	line = line[:len(line)-2]
+	body := line[1:]
+
+	switch line[0] {
+	case '+':
+		return body
+	case '-':
+		return replyError(body)
+	case ':':
+		n, err := strconv.ParseInt(body, 10, 64)
+		require.NoError(c.t, err)
+		return n
+	case '_':
+		return nil
+	case '$':
+		n, err := strconv.Atoi(body)
+		require.NoError(c.t, err)
+		if n < 0 {
+			return nil
+		}
+		buf := make([]byte, n+2)
+		_, err = io.ReadFull(c.r, buf)
+		require.NoError(c.t, err)
+		return string(buf[:n])
+	case '*':
+		n, err := strconv.Atoi(body)
+		require.NoError(c.t, err)
+		arr := make([]any, n)
+		for i := range arr {
+			arr[i] = c.read()
+		}
+		return arr
+	case '%':
+		n, err := strconv.Atoi(body)
+		require.NoError(c.t, err)
+		m := make(map[string]any, n)
+		for range n {
+			k := c.read()
+			m[k.(string)] = c.read()
+		}
+		return m
+	default:
+		c.t.Fatalf("unexpected reply %q", line)
+		return nil
+	}
+}
+
+func TestServer_Strings(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, "PONG", c.do("PING"))
+	assert.Nil(t, c.do("GET", "k"))
+	assert.Equal(t, "OK", c.do("SET", "k", "v"))
+	assert.Equal(t, "v", c.do("get", "k"))
+
+	assert.Nil(t, c.do("SET", "k", "other", "NX"))
+	assert.Equal(t, "OK", c.do("SET", "k", "new", "XX"))
+	assert.Nil(t, c.do("SET", "missing", "v", "XX"))
+	assert.Equal(t, replyError("ERR syntax error"), c.do("SET", "k", "v", "NX", "XX"))
+
+	assert.Equal(t, "OK", c.do("MSET", "a", "1", "b", "2"))
+	assert.Equal(t, []any{"1", nil, "2"}, c.do("MGET", "a", "nope", "b"))
+	assert.Equal(t, int64(3), c.do("EXISTS", "a", "b", "a", "nope"))
+
+	assert.Equal(t, int64(2), c.do("INCR", "a"))
+	assert.Equal(t, int64(-8), c.do("DECRBY", "a", "10"))
+	assert.Equal(t, int64(1), c.do("INCR", "counter"))
+	assert.Equal(t, replyError("ERR value is not an integer or out of range"), c.do("INCR", "k"))
+
+	assert.Equal(t, int64(2), c.do("DEL", "a", "b", "nope"))
+	assert.Equal(t, int64(0), c.do("EXISTS", "a"))
+
+	assert.Equal(t, replyError("ERR wrong number of arguments for 'get' command"), c.do("GET"))
+	assert.Equal(t, replyError("ERR unknown command 'FLUSHALL', with args beginning with: "), c.do("FLUSHALL"))
+}
+
+func TestServer_Hashes(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, int64(2), c.do("HSET", "h", "a", "1", "b", "2"))
+	assert.Equal(t, int64(1), c.do("HSET", "h", "b", "3", "c", "4"))
+	assert.Equal(t, "3", c.do("HGET", "h", "b"))
+	assert.Nil(t, c.do("HGET", "h", "nope"))
+	assert.Nil(t, c.do("HGET", "nope", "a"))
+	assert.Equal(t, []any{"a", "1", "b", "3", "c", "4"}, c.do("HGETALL", "h"))
+	assert.Equal(t, []any{}, c.do("HGETALL", "nope"))
+
+	assert.Equal(t, int64(11), c.do("HINCRBY", "h", "a", "10"))
+	assert.Equal(t, replyError("ERR value is not an integer or out of range"), c.do("HINCRBY", "h", "a", "x"))
+	assert.Equal(t, int64(2), c.do("HDEL", "h", "a", "b", "nope"))
+	assert.Equal(t, int64(1), c.do("EXISTS", "h"))
+	assert.Equal(t, int64(1), c.do("HDEL", "h", "c"))
+	assert.Equal(t, int64(0), c.do("EXISTS", "h"))
+
+	wrongType := replyError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	assert.Equal(t, "OK", c.do("SET", "s", "v"))
+	assert.Equal(t, wrongType, c.do("HSET", "s", "a", "1"))
+	assert.Equal(t, wrongType, c.do("HGET", "s", "a"))
+	assert.Equal(t, int64(1), c.do("HSET", "h", "a", "1"))
+	assert.Equal(t, wrongType, c.do("GET", "h"))
+	assert.Equal(t, wrongType, c.do("INCR", "h"))
+	assert.Equal(t, []any{nil, "v"}, c.do("MGET", "h", "s"))
+
+	assert.Equal(t, replyError("ERR wrong number of arguments for 'hset' command"), c.do("HSET", "h", "a", "1", "b"))
+}
+
+func TestServer_Expiry(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, int64(-2), c.do("TTL", "k"))
+	assert.Equal(t, "OK", c.do("SET", "k", "v"))
+	assert.Equal(t, int64(-1), c.do("TTL", "k"))
+
+	assert.Equal(t, int64(1), c.do("EXPIRE", "k", "100"))
+	assert.Equal(t, int64(100), c.do("TTL", "k"))
+	assert.Equal(t, int64(0), c.do("EXPIRE", "nope", "100"))
+
+	assert.Equal(t, "OK", c.do("SET", "short", "v", "PX", "50"))
+	pttl := c.do("PTTL", "short").(int64)
+	assert.True(t, pttl > 0 && pttl <= 50)
+	assert.Eventually(t, func() bool { return c.do("GET", "short") == nil }, time.Second, 10*time.Millisecond)
+
+	// Overwriting a key clears its expiry.
+	assert.Equal(t, "OK", c.do("SET", "k", "v2"))
+	assert.Equal(t, int64(-1), c.do("TTL", "k"))
+
+	assert.Equal(t, int64(1), c.do("EXPIRE", "k", "0"))
+	assert.Equal(t, int64(0), c.do("EXISTS", "k"))
+	assert.Equal(t, replyError("ERR invalid expire time in 'set' command"), c.do("SET", "k", "v", "EX", "0"))
+}
+
+func TestServer_KeysAndScan(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+
+	for i := range 20 {
+		require.Equal(t, "OK", c.do("SET", fmt.Sprintf("user:%d", i), "v"))
+	}
+	require.Equal(t, "OK", c.do("SET", "order:1", "v"))
+
+	assert.ElementsMatch(t, []any{"order:1"}, c.do("KEYS", "order:*"))
+	assert.Len(t, c.do("KEYS", "user:?"), 10)
+
+	var keys []any
+	cursor := "0"
+	for {
+		reply := c.do("SCAN", cursor, "MATCH", "user:*", "COUNT", "3").([]any)
+		keys = append(keys, reply[1].([]any)...)
+		cursor = reply[0].(string)
+		if cursor == "0" {
+			break
+		}
+	}
+	assert.Len(t, keys, 20)
+}
+
+func TestServer_Pipelining(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+
+	c.send("SET", "k", "v")
+	c.send("GET", "k")
+	_, err := c.conn.Write([]byte("PING\r\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "OK", c.read())
+	assert.Equal(t, "v", c.read())
+	assert.Equal(t, "PONG", c.read())
+}
+
+func TestServer_Hello(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+
+	hello := c.do("HELLO", "3").(map[string]any)
+	assert.Equal(t, int64(3), hello["proto"])
+	assert.Equal(t, "master", hello["role"])
+	// RESP3 null.
+	assert.Nil(t, c.do("GET", "missing"))
+
+	assert.Equal(t, replyError("NOPROTO unsupported protocol version"), c.do("HELLO", "4"))
+	hello2 := c.do("HELLO", "2").([]any)
+	assert.Equal(t, "server", hello2[0])
+}
+
+func TestServer_NotLeader(t *testing.T) {
+	t.Run("moved", func(t *testing.T) {
+		n := newTestNode(t, &cfg.RESPCfg{Port: "6379", Redirect: RedirectMoved}, nil, nil)
+		c := dial(t, n.addr)
+		require.Equal(t, "OK", c.do("SET", "foo", "bar"))
+		n.raft.SetLeader(false)
+
+		// "foo" hashes into slot 12182.
+		assert.Equal(t, replyError("MOVED 12182 b:6379"), c.do("SET", "foo", "v"))
+		assert.Equal(t, replyError("MOVED 12182 b:6379"), c.do("GET", "foo"))
+
+		// Replica reads are served locally.
+		assert.Equal(t, "OK", c.do("READONLY"))
+		assert.Equal(t, "bar", c.do("GET", "foo"))
+	})
+
+	t.Run("readonly", func(t *testing.T) {
+		n := newTestNode(t, &cfg.RESPCfg{Port: "6379", Redirect: RedirectReadOnly}, nil, nil)
+		c := dial(t, n.addr)
+		n.raft.SetLeader(false)
+
+		assert.Equal(t, replyError("READONLY You can't write against a read only replica."), c.do("DEL", "foo"))
+	})
+}
+
+func TestServer_Auth(t *testing.T) {
+	authSvc, err := auth.NewService(&cfg.AuthCfg{
+		Enabled: true,
+		Tokens: []cfg.APITokenCfg{
+			{Token: "secret", Subject: "svc", Roles: []string{"jobs"}},
+		},
+		Roles: []cfg.RoleCfg{
+			{Name: "jobs", Rules: []cfg.RoleRuleCfg{{Prefix: "jobs:", Access: "write"}}},
+		},
+	})
+	require.NoError(t, err)
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, authSvc, nil)
+	c := dial(t, n.addr)
+
+	assert.Equal(t, replyError("NOAUTH Authentication required."), c.do("GET", "jobs:1"))
+	assert.Equal(t, replyError(errWrongPass), c.do("AUTH", "wrong"))
+	assert.Equal(t, "OK", c.do("AUTH", "default", "secret"))
+
+	assert.Equal(t, "OK", c.do("SET", "jobs:1", "v"))
+	reply := c.do("SET", "other", "v")
+	require.IsType(t, replyError(""), reply)
+	assert.Contains(t, string(reply.(replyError)), "NOPERM")
+	reply = c.do("MSET", "jobs:2", "v", "other", "v")
+	require.IsType(t, replyError(""), reply)
+	assert.Equal(t, []any{"jobs:1"}, c.do("KEYS", "*"))
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	authSvc, err := auth.NewService(&cfg.AuthCfg{
+		Enabled: true,
+		Tokens: []cfg.APITokenCfg{
+			{Token: "a-secret", Subject: "a", Roles: []string{"all"}},
+			{Token: "b-secret", Subject: "b", Roles: []string{"all"}},
+		},
+		Roles: []cfg.RoleCfg{
+			{Name: "all", Rules: []cfg.RoleRuleCfg{{Prefix: "", Access: "write"}}},
+		},
+	})
+	require.NoError(t, err)
+	limiter := ratelimit.NewLimiter(&cfg.RateLimitCfg{
+		Enabled: true,
+		Read:    cfg.LimitCfg{Rate: 0.001, Burst: 3},
+		Write:   cfg.LimitCfg{Rate: 0.001, Burst: 1},
+	})
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, authSvc, limiter)
+
+	a := dial(t, n.addr)
+	assert.Equal(t, "OK", a.do("AUTH", "a-secret"))
+	assert.Equal(t, "OK", a.do("SET", "k", "v"))
+	reply := a.do("SET", "k", "v")
+	require.IsType(t, replyError(""), reply)
+	assert.Contains(t, string(reply.(replyError)), "ERR rate limit exceeded")
+	assert.Equal(t, "v", a.do("GET", "k"), "reads are limited separately")
+
+	// Clients are limited by subject, not by connection.
+	other := dial(t, n.addr)
+	assert.Equal(t, "OK", other.do("AUTH", "a-secret"))
+	assert.Equal(t, "v", other.do("GET", "k"))
+	assert.Equal(t, "v", other.do("GET", "k"))
+	assert.IsType(t, replyError(""), other.do("GET", "k"))
+
+	b := dial(t, n.addr)
+	assert.Equal(t, "OK", b.do("AUTH", "b-secret"))
+	assert.Equal(t, "OK", b.do("SET", "k", "v"))
+	// Unauthenticated commands are limited by remote ip.
+	assert.IsType(t, replyError(""), dial(t, n.addr).do("AUTH", "b-secret"))
+}
+
+func TestServer_ProtocolError(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+
+	_, err := c.conn.Write([]byte("*1\r\n+PING\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, replyError("ERR Protocol error: expected '$', got '+'"), c.read())
+
+	_, err = c.r.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	n := newTestNode(t, &cfg.RESPCfg{Port: "6379"}, nil, nil)
+	c := dial(t, n.addr)
+	require.Equal(t, "PONG", c.do("PING"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, n.srv.Shutdown(ctx))
+
+	_, err := c.r.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+}
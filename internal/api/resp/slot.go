@@ -0,0 +1,33 @@
+package resp
+
+import "strings"
+
+// slotsCount is a number of hash slots of Redis Cluster.
+const slotsCount = 16384
+
+// keySlot returns Redis Cluster hash slot of the key, so cluster-aware clients
+// following MOVED redirects cache a valid slot. Only "{tag}" part is hashed if present.
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key)) % slotsCount
+}
+
+// crc16 is CRC16-CCITT (XMODEM) used by Redis Cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,77 @@
+// Package zsets holds helpers shared by sorted set handlers of every api.
+package zsets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrInvalidScore = errors.New("zsets: score must be a number")
+
+// ParseScore parses a score. Infinities are written as inf, +inf or -inf, NaN is rejected.
+func ParseScore(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidScore, s)
+	}
+	return f, nil
+}
+
+// FormatScore formats a score in the shortest form ParseScore reads back.
+func FormatScore(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+// DecodePopMin returns members from the result data of an applied pop min command.
+func DecodePopMin(data []byte) ([]store.ScoredMember, error) {
+	var res fsm_v1.ZPopResult
+	if err := proto.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("zsets: unexpected pop result: %w", err)
+	}
+	members := make([]store.ScoredMember, 0, len(res.Members))
+	for _, m := range res.Members {
+		members = append(members, store.ScoredMember{Member: m.GetMember(), Score: m.GetScore()})
+	}
+	return members, nil
+}
+
+// Member is a JSON view of a sorted set member.
+type Member struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+// MarshalJSON writes infinite scores, which JSON numbers can't hold, as "inf" and "-inf" strings.
+func (m Member) MarshalJSON() ([]byte, error) {
+	type plain Member
+	if !math.IsInf(m.Score, 0) {
+		return json.Marshal(plain(m))
+	}
+	return json.Marshal(struct {
+		Member string `json:"member"`
+		Score  string `json:"score"`
+	}{m.Member, FormatScore(m.Score)})
+}
+
+// Members converts members of a sorted set to their JSON view.
+func Members(members []store.ScoredMember) []Member {
+	out := make([]Member, 0, len(members))
+	for _, m := range members {
+		out = append(out, Member{Member: m.Member, Score: m.Score})
+	}
+	return out
+}
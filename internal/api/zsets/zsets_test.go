@@ -0,0 +1,45 @@
+package zsets
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseScore(t *testing.T) {
+	for in, want := range map[string]float64{"1.5": 1.5, "-3": -3, "inf": math.Inf(1), "-inf": math.Inf(-1), "+Inf": math.Inf(1)} {
+		got, err := ParseScore(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+		back, err := ParseScore(FormatScore(got))
+		require.NoError(t, err, in)
+		assert.Equal(t, want, back, in)
+	}
+	for _, in := range []string{"", "nan", "1x"} {
+		_, err := ParseScore(in)
+		assert.ErrorIs(t, err, ErrInvalidScore, in)
+	}
+}
+
+func TestDecodePopMin(t *testing.T) {
+	data, err := proto.Marshal(&fsm_v1.ZPopResult{Members: []*fsm_v1.ScoredMember{{Member: "a", Score: 1}}})
+	require.NoError(t, err)
+	members, err := DecodePopMin(data)
+	require.NoError(t, err)
+	assert.Equal(t, []store.ScoredMember{{Member: "a", Score: 1}}, members)
+}
+
+func TestMembersJSON(t *testing.T) {
+	data, err := json.Marshal(Members([]store.ScoredMember{
+		{Member: "low", Score: math.Inf(-1)},
+		{Member: "a", Score: 2.5},
+	}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"member":"low","score":"-inf"},{"member":"a","score":2.5}]`, string(data))
+}
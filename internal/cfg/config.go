@@ -16,17 +16,61 @@ func init() {
 }
 
 type AppConfig struct {
-	Env       string    `yaml:"env" env:"ENV" env-default:"production"`
-	Store     StoreCfg  `yaml:"store"`
-	ShardsCfg ShardsCfg `yaml:"shards"`
-	HttpCfg   HttpCfg   `yaml:"http"`
-	GRPCCfg   GRPCCfg   `yaml:"grpc"`
-	Raft      RaftCfg   `yaml:"raft"`
+	Env        string        `yaml:"env" env:"ENV" env-default:"production"`
+	Store      StoreCfg      `yaml:"store"`
+	ShardsCfg  ShardsCfg     `yaml:"shards"`
+	HttpCfg    HttpCfg       `yaml:"http"`
+	GRPCCfg    GRPCCfg       `yaml:"grpc"`
+	Raft       RaftCfg       `yaml:"raft"`
+	Audit      AuditCfg      `yaml:"audit"`
+	Auth       AuthCfg       `yaml:"auth"`
+	RateLimit  RateLimitCfg  `yaml:"rate_limit"`
+	Batch      BatchCfg      `yaml:"batch"`
+	Encryption EncryptionCfg `yaml:"encryption"`
+	Health     HealthCfg     `yaml:"health"`
+	RESP       RESPCfg       `yaml:"resp"`
+	Memcached  MemcachedCfg  `yaml:"memcached"`
+	CDC        CDCCfg        `yaml:"cdc"`
 }
 
 type StoreCfg struct {
-	MaxKeySize int `yaml:"max_key" env:"MAX_KEY_SIZE_BYTES" env-default:"1024"`
-	MaxValSize int `yaml:"max_val" env:"MAX_VAL_SIZE_BYTES" env-default:"1024"`
+	MaxKeySize int        `yaml:"max_key" env:"MAX_KEY_SIZE_BYTES" env-default:"1024"`
+	MaxValSize int        `yaml:"max_val" env:"MAX_VAL_SIZE_BYTES" env-default:"1024"`
+	Quotas     []QuotaCfg `yaml:"quotas"`
+
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"STORE_WRITE_TIMEOUT" env-default:"10s"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"STORE_READ_TIMEOUT" env-default:"10s"`
+	// ReadIndexTimeout limits how long a read carrying a consistency token waits for the node to catch up.
+	ReadIndexTimeout time.Duration `yaml:"read_index_timeout" env:"STORE_READ_INDEX_TIMEOUT" env-default:"1s"`
+	// MaxPopWait limits how long a blocking pop waits for a value pushed to an empty list.
+	MaxPopWait time.Duration `yaml:"max_pop_wait" env:"STORE_MAX_POP_WAIT" env-default:"5s"`
+	Admission  AdmissionCfg  `yaml:"admission"`
+	Expiry     ExpiryCfg     `yaml:"expiry"`
+}
+
+type ExpiryCfg struct {
+	// Interval is how often the leader looks for expired keys to delete.
+	Interval time.Duration `yaml:"interval" env:"EXPIRY_INTERVAL" env-default:"1s"`
+	// BatchSize is a maximum number of expired keys deleted by a single command.
+	BatchSize int `yaml:"batch_size" env:"EXPIRY_BATCH_SIZE" env-default:"512"`
+	// TickInterval is how long the leader waits for a write before it proposes a tick advancing
+	// the cluster time of replicas. Zero disables ticks.
+	TickInterval time.Duration `yaml:"tick_interval" env:"EXPIRY_TICK_INTERVAL" env-default:"1s"`
+}
+
+type AdmissionCfg struct {
+	MaxInFlight       int           `yaml:"max_in_flight" env:"ADMISSION_MAX_IN_FLIGHT" env-default:"0"`
+	MaxPendingFutures int           `yaml:"max_pending_futures" env:"ADMISSION_MAX_PENDING_FUTURES" env-default:"0"`
+	MaxApplyBacklog   int           `yaml:"max_apply_backlog" env:"ADMISSION_MAX_APPLY_BACKLOG" env-default:"0"`
+	MaxCommitLatency  time.Duration `yaml:"max_commit_latency" env:"ADMISSION_MAX_COMMIT_LATENCY" env-default:"0s"`
+	// DrainTimeout limits how long shutdown waits for in-flight writes after the node stopped accepting requests.
+	DrainTimeout time.Duration `yaml:"drain_timeout" env:"ADMISSION_DRAIN_TIMEOUT" env-default:"5s"`
+}
+
+type QuotaCfg struct {
+	Prefix   string `yaml:"prefix"`
+	MaxKeys  int    `yaml:"max_keys"`
+	MaxBytes int64  `yaml:"max_bytes"`
 }
 
 type ShardsCfg struct {
@@ -44,10 +88,142 @@ type HttpCfg struct {
 	ServerIdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"5s"`
 	ServerWriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" env-default:"10s"`
 	ServerReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" env-default:"10s"`
+	TLS                TLSCfg        `yaml:"tls" env-prefix:"HTTP_"`
 }
 
 type GRPCCfg struct {
 	Port string `yaml:"port" env:"GRPC_PORT" env-default:"3000"`
+	TLS  TLSCfg `yaml:"tls" env-prefix:"GRPC_"`
+}
+
+type TLSCfg struct {
+	Enabled        bool          `yaml:"enabled" env:"TLS_ENABLED" env-default:"false"`
+	CertFile       string        `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile        string        `yaml:"key_file" env:"TLS_KEY_FILE"`
+	ClientCAFile   string        `yaml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	ClientAuth     string        `yaml:"client_auth" env:"TLS_CLIENT_AUTH" env-default:"none"`
+	MinVersion     string        `yaml:"min_version" env:"TLS_MIN_VERSION" env-default:"1.2"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL" env-default:"30s"`
+}
+
+type AuditCfg struct {
+	Enabled    bool          `yaml:"enabled" env:"AUDIT_ENABLED" env-default:"false"`
+	Path       string        `yaml:"path" env:"AUDIT_PATH" env-default:"./data/audit/audit.jsonl"`
+	MaxSize    int64         `yaml:"max_size_bytes" env:"AUDIT_MAX_SIZE_BYTES" env-default:"104857600"`
+	MaxAge     time.Duration `yaml:"max_age" env:"AUDIT_MAX_AGE" env-default:"24h"`
+	MaxBackups int           `yaml:"max_backups" env:"AUDIT_MAX_BACKUPS" env-default:"10"`
+}
+
+type AuthCfg struct {
+	Enabled bool          `yaml:"enabled" env:"AUTH_ENABLED" env-default:"false"`
+	Tokens  []APITokenCfg `yaml:"tokens"`
+	JWT     JWTCfg        `yaml:"jwt"`
+	Roles   []RoleCfg     `yaml:"roles"`
+}
+
+type APITokenCfg struct {
+	Token   string   `yaml:"token"`
+	Subject string   `yaml:"subject"`
+	Roles   []string `yaml:"roles"`
+}
+
+type JWTCfg struct {
+	Secret     string        `yaml:"secret" env:"AUTH_JWT_SECRET"`
+	Issuer     string        `yaml:"issuer" env:"AUTH_JWT_ISSUER"`
+	Audience   string        `yaml:"audience" env:"AUTH_JWT_AUDIENCE"`
+	RolesClaim string        `yaml:"roles_claim" env:"AUTH_JWT_ROLES_CLAIM" env-default:"roles"`
+	Leeway     time.Duration `yaml:"leeway" env:"AUTH_JWT_LEEWAY" env-default:"30s"`
+}
+
+type RoleCfg struct {
+	Name  string        `yaml:"name"`
+	Rules []RoleRuleCfg `yaml:"rules"`
+}
+
+type RoleRuleCfg struct {
+	// Namespace of keys the rule grants access to. Empty means the default namespace, "*" every namespace.
+	Namespace string `yaml:"namespace"`
+	Prefix    string `yaml:"prefix"`
+	Access    string `yaml:"access"`
+}
+
+type RateLimitCfg struct {
+	Enabled     bool          `yaml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"false"`
+	Read        LimitCfg      `yaml:"read" env-prefix:"RATE_LIMIT_READ_"`
+	Write       LimitCfg      `yaml:"write" env-prefix:"RATE_LIMIT_WRITE_"`
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"RATE_LIMIT_IDLE_TIMEOUT" env-default:"10m"`
+}
+
+type LimitCfg struct {
+	Rate  float64 `yaml:"rate" env:"RATE" env-default:"0"`
+	Burst int     `yaml:"burst" env:"BURST" env-default:"0"`
+}
+
+type BatchCfg struct {
+	Enabled  bool          `yaml:"enabled" env:"BATCH_ENABLED" env-default:"false"`
+	Window   time.Duration `yaml:"window" env:"BATCH_WINDOW" env-default:"1ms"`
+	MaxSize  int           `yaml:"max_size" env:"BATCH_MAX_SIZE" env-default:"128"`
+	MaxBytes int           `yaml:"max_bytes" env:"BATCH_MAX_BYTES" env-default:"1048576"`
+}
+
+type EncryptionCfg struct {
+	Enabled   bool   `yaml:"enabled" env:"ENCRYPTION_ENABLED" env-default:"false"`
+	KeyFile   string `yaml:"key_file" env:"ENCRYPTION_KEY_FILE"`
+	ActiveKey string `yaml:"active_key" env:"ENCRYPTION_ACTIVE_KEY"`
+}
+
+type HealthCfg struct {
+	// ProbeTimeout limits the quorum read a leader does to confirm it's ready.
+	ProbeTimeout time.Duration `yaml:"probe_timeout" env:"HEALTH_PROBE_TIMEOUT" env-default:"1s"`
+	// MaxApplyBacklog is a number of committed entries waiting to be applied above which the node isn't ready.
+	MaxApplyBacklog int `yaml:"max_apply_backlog" env:"HEALTH_MAX_APPLY_BACKLOG" env-default:"1000"`
+	// GRPCInterval is how often serving status of the grpc health service is refreshed.
+	GRPCInterval time.Duration `yaml:"grpc_interval" env:"HEALTH_GRPC_INTERVAL" env-default:"5s"`
+}
+
+type RESPCfg struct {
+	Enabled bool   `yaml:"enabled" env:"RESP_ENABLED" env-default:"false"`
+	Port    string `yaml:"port" env:"RESP_PORT" env-default:"6379"`
+	// Redirect is an error writes get on a follower: "moved" or "readonly".
+	Redirect string `yaml:"redirect" env:"RESP_REDIRECT" env-default:"moved"`
+	// IdleTimeout closes connections which sent no commands for this long. 0 disables it.
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"RESP_IDLE_TIMEOUT" env-default:"0s"`
+}
+
+type MemcachedCfg struct {
+	Enabled bool   `yaml:"enabled" env:"MEMCACHED_ENABLED" env-default:"false"`
+	Port    string `yaml:"port" env:"MEMCACHED_PORT" env-default:"11211"`
+	// IdleTimeout closes connections which sent no commands for this long. 0 disables it.
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"MEMCACHED_IDLE_TIMEOUT" env-default:"0s"`
+}
+
+type CDCCfg struct {
+	Enabled bool `yaml:"enabled" env:"CDC_ENABLED" env-default:"false"`
+	// MaxBacklog is a number of changes not yet delivered to every sink at which writes are rejected.
+	// Every node keeps them in memory and in snapshots. 0 disables the limit.
+	MaxBacklog int `yaml:"max_backlog" env:"CDC_MAX_BACKLOG" env-default:"100000"`
+	// BatchSize is a number of changes delivered to a sink at once.
+	BatchSize int `yaml:"batch_size" env:"CDC_BATCH_SIZE" env-default:"500"`
+	// RetryMin and RetryMax bound the backoff between failed deliveries.
+	RetryMin time.Duration   `yaml:"retry_min" env:"CDC_RETRY_MIN" env-default:"100ms"`
+	RetryMax time.Duration   `yaml:"retry_max" env:"CDC_RETRY_MAX" env-default:"30s"`
+	Files    []CDCFileCfg    `yaml:"files"`
+	Webhooks []CDCWebhookCfg `yaml:"webhooks"`
+}
+
+type CDCFileCfg struct {
+	Name       string        `yaml:"name"`
+	Path       string        `yaml:"path"`
+	MaxSize    int64         `yaml:"max_size_bytes"`
+	MaxAge     time.Duration `yaml:"max_age"`
+	MaxBackups int           `yaml:"max_backups"`
+}
+
+type CDCWebhookCfg struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout time.Duration     `yaml:"timeout"`
 }
 
 func ReadConfig() *AppConfig {
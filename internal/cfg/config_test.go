@@ -19,6 +19,8 @@ func TestReadConfig_Env(t *testing.T) {
 	t.Setenv("HTTP_PORT", "9999")
 	t.Setenv("GRPC_PORT", "9998")
 	t.Setenv("RAFT_NODE_ID", "node-env")
+	t.Setenv("HTTP_TLS_ENABLED", "true")
+	t.Setenv("GRPC_TLS_CERT_FILE", "/certs/grpc.crt")
 
 	cfg := ReadConfig()
 
@@ -29,4 +31,8 @@ func TestReadConfig_Env(t *testing.T) {
 	assert.Equal(t, "9999", cfg.HttpCfg.Port)
 	assert.Equal(t, "9998", cfg.GRPCCfg.Port)
 	assert.Equal(t, "node-env", cfg.Raft.NodeID)
+	assert.True(t, cfg.HttpCfg.TLS.Enabled)
+	assert.False(t, cfg.GRPCCfg.TLS.Enabled)
+	assert.Equal(t, "/certs/grpc.crt", cfg.GRPCCfg.TLS.CertFile)
+	assert.Equal(t, "1.2", cfg.HttpCfg.TLS.MinVersion)
 }
@@ -24,6 +24,7 @@ type RaftCfg struct {
 	ShutdownTimeout            time.Duration `yaml:"shutdown_timeout" env:"RAFT_SHUTDOWN_TIMEOUT" env-default:"5s"`
 	SnapshotThreshold          int           `yaml:"snapshot_threshold_bytes" env:"RAFT_SNAPSHOT_THRESHOLD_BYTES" env-default:"134217728"`
 	SnapshotCheckIn            time.Duration `yaml:"snapshot_check_in" env:"RAFT_SNAPSHOT_CHECK_IN" env-default:"1s"`
+	SnapshotCompression        string        `yaml:"snapshot_compression" env:"RAFT_SNAPSHOT_COMPRESSION" env-default:"none"`
 	LinearizableReadIn         time.Duration `yaml:"linearizable_read_in" env:"RAFT_LINEARIZABLE_READ_IN" env-default:"100ms"`
 	CBFailureThreshold         int           `yaml:"cb_failure_threshold" env:"RAFT_CB_FAILURE_THRESHOLD" env-default:"6"`
 	CBSuccessThreshold         int           `yaml:"cb_success_threshold" env:"RAFT_CB_SUCCESS_THRESHOLD" env-default:"3"`
@@ -0,0 +1,173 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+)
+
+var (
+	ErrOverloaded = errors.New("admission: node is overloaded, try again later")
+	ErrDraining   = errors.New("admission: node is draining before shutdown, try another node")
+)
+
+const (
+	// latencyWeight is a weight of a new sample in the commit latency moving average.
+	latencyWeight = 0.2
+	// idlePollInterval is how often WaitIdle checks for in-flight requests.
+	idlePollInterval = 10 * time.Millisecond
+)
+
+// Controller sheds requests instead of letting them pile up
+// when raft can't keep up with the incoming load.
+type Controller struct {
+	cfg          *cfg.AdmissionCfg
+	futures      ftr.FuturesStore
+	applyBacklog func() int
+	changesFull  func() bool
+
+	inFlight atomic.Int64
+	draining atomic.Bool
+	// latency is a moving average of commit latency in nanoseconds
+	latency atomic.Int64
+}
+
+// NewController creates admission controller. applyBacklog reports
+// number of committed entries waiting to be applied by the state machine.
+// changesFull reports whether too many changes wait for delivery to change data capture sinks, may be nil.
+func NewController(c *cfg.AdmissionCfg, futures ftr.FuturesStore, applyBacklog func() int, changesFull func() bool) *Controller {
+	return &Controller{
+		cfg:          c,
+		futures:      futures,
+		applyBacklog: applyBacklog,
+		changesFull:  changesFull,
+	}
+}
+
+// AdmitWrite reserves a slot for a new proposal. Returned done func must be called
+// once the proposal is applied or abandoned. Commit latency is observed only if committed is true,
+// so redirects and timed out proposals don't skew it. Nil controller admits everything.
+func (c *Controller) AdmitWrite() (done func(committed bool), err error) {
+	if c == nil {
+		return func(bool) {}, nil
+	}
+	if c.draining.Load() {
+		return nil, ErrDraining
+	}
+
+	// Changes of admitted writes would be kept on top of the backlog until sinks catch up.
+	if c.changesFull != nil && c.changesFull() {
+		return nil, ErrOverloaded
+	}
+
+	n := c.inFlight.Add(1)
+	if c.cfg.MaxInFlight > 0 && n > int64(c.cfg.MaxInFlight) {
+		c.inFlight.Add(-1)
+		return nil, ErrOverloaded
+	}
+	// Requests are shed on high latency only while there are other proposals in flight,
+	// so a single one still gets through and refreshes the average once the cluster recovers.
+	if err := c.check(n > 1); err != nil {
+		c.inFlight.Add(-1)
+		return nil, err
+	}
+
+	start := time.Now()
+	return func(committed bool) {
+		c.inFlight.Add(-1)
+		if committed {
+			c.observe(time.Since(start))
+		}
+	}, nil
+}
+
+// Committed reports whether a proposal was committed given the error of waiting for it to be applied.
+// Commands rejected by the state machine are committed as well.
+func Committed(err error) bool {
+	return !errors.Is(err, ftr.ErrPromiseTimeout) && !errors.Is(err, ftr.ErrCompacted) &&
+		!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// AdmitRead reports whether the node is able to serve reads in time.
+// Linearizable reads wait for committed entries to be applied, so they share thresholds with writes.
+func (c *Controller) AdmitRead() error {
+	if c == nil {
+		return nil
+	}
+	if c.draining.Load() {
+		return ErrDraining
+	}
+	return c.check(c.inFlight.Load() > 0)
+}
+
+// Drain makes the controller reject all new requests. It can't be undone.
+func (c *Controller) Drain() {
+	if c != nil {
+		c.draining.Store(true)
+	}
+}
+
+// Draining reports whether the node stopped accepting new requests.
+func (c *Controller) Draining() bool {
+	return c != nil && c.draining.Load()
+}
+
+// WaitIdle blocks until all admitted writes are done and no futures are pending or ctx is done.
+func (c *Controller) WaitIdle(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	t := time.NewTicker(idlePollInterval)
+	defer t.Stop()
+	for {
+		if c.InFlight() == 0 && (c.futures == nil || c.futures.Pending() == 0) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d writes still in flight: %w", c.InFlight(), ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// InFlight returns number of admitted writes that are not done yet.
+func (c *Controller) InFlight() int {
+	return int(c.inFlight.Load())
+}
+
+// CommitLatency returns moving average of commit latency.
+func (c *Controller) CommitLatency() time.Duration {
+	return time.Duration(c.latency.Load())
+}
+
+func (c *Controller) check(checkLatency bool) error {
+	if c.cfg.MaxPendingFutures > 0 && c.futures.Pending() >= c.cfg.MaxPendingFutures {
+		return ErrOverloaded
+	}
+	if c.cfg.MaxApplyBacklog > 0 && c.applyBacklog() >= c.cfg.MaxApplyBacklog {
+		return ErrOverloaded
+	}
+	if checkLatency && c.cfg.MaxCommitLatency > 0 && c.CommitLatency() > c.cfg.MaxCommitLatency {
+		return ErrOverloaded
+	}
+	return nil
+}
+
+func (c *Controller) observe(d time.Duration) {
+	for {
+		old := c.latency.Load()
+		next := int64(d)
+		if old != 0 {
+			next = int64(latencyWeight*float64(d) + (1-latencyWeight)*float64(old))
+		}
+		if c.latency.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
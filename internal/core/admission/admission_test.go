@@ -0,0 +1,137 @@
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_MaxInFlight(t *testing.T) {
+	c := NewController(&cfg.AdmissionCfg{MaxInFlight: 2}, nil, nil, nil)
+
+	done1, err := c.AdmitWrite()
+	require.NoError(t, err)
+	_, err = c.AdmitWrite()
+	require.NoError(t, err)
+
+	_, err = c.AdmitWrite()
+	assert.ErrorIs(t, err, ErrOverloaded)
+	assert.Equal(t, 2, c.InFlight())
+
+	done1(true)
+	_, err = c.AdmitWrite()
+	assert.NoError(t, err)
+}
+
+func TestController_Backlog(t *testing.T) {
+	futures := futuresmocks.NewMockFuturesStore(t)
+	futures.On("Pending").Return(10)
+	backlog := 0
+	c := NewController(
+		&cfg.AdmissionCfg{MaxPendingFutures: 100, MaxApplyBacklog: 5},
+		futures,
+		func() int { return backlog },
+		nil,
+	)
+
+	assert.NoError(t, c.AdmitRead())
+	backlog = 5
+	assert.ErrorIs(t, c.AdmitRead(), ErrOverloaded)
+	_, err := c.AdmitWrite()
+	assert.ErrorIs(t, err, ErrOverloaded)
+	assert.Equal(t, 0, c.InFlight())
+}
+
+func TestController_ChangeBacklog(t *testing.T) {
+	full := true
+	c := NewController(&cfg.AdmissionCfg{}, nil, nil, func() bool { return full })
+
+	_, err := c.AdmitWrite()
+	assert.ErrorIs(t, err, ErrOverloaded)
+	assert.NoError(t, c.AdmitRead(), "reads don't add changes")
+	assert.Equal(t, 0, c.InFlight())
+
+	full = false
+	done, err := c.AdmitWrite()
+	require.NoError(t, err)
+	done(true)
+}
+
+func TestController_PendingFutures(t *testing.T) {
+	futures := futuresmocks.NewMockFuturesStore(t)
+	futures.On("Pending").Return(100)
+	c := NewController(&cfg.AdmissionCfg{MaxPendingFutures: 100}, futures, nil, nil)
+
+	_, err := c.AdmitWrite()
+	assert.ErrorIs(t, err, ErrOverloaded)
+}
+
+func TestController_CommitLatency(t *testing.T) {
+	c := NewController(&cfg.AdmissionCfg{MaxCommitLatency: time.Second}, nil, nil, nil)
+	c.observe(3 * time.Second)
+	assert.Equal(t, 3*time.Second, c.CommitLatency())
+
+	// Single proposal is still admitted to probe the cluster.
+	done, err := c.AdmitWrite()
+	require.NoError(t, err)
+
+	_, err = c.AdmitWrite()
+	assert.ErrorIs(t, err, ErrOverloaded)
+	assert.ErrorIs(t, c.AdmitRead(), ErrOverloaded)
+
+	done(false)
+	assert.Equal(t, 3*time.Second, c.CommitLatency(), "abandoned proposals are not observed")
+
+	done, err = c.AdmitWrite()
+	require.NoError(t, err)
+	done(true)
+	assert.Less(t, c.CommitLatency(), 3*time.Second)
+}
+
+func TestCommitted(t *testing.T) {
+	assert.True(t, Committed(nil))
+	assert.True(t, Committed(fsm.ErrLockHeld), "commands rejected by the state machine are committed")
+	assert.False(t, Committed(ftr.ErrPromiseTimeout))
+	assert.False(t, Committed(ftr.ErrCompacted))
+	assert.False(t, Committed(context.Canceled))
+}
+
+func TestController_Nil(t *testing.T) {
+	var c *Controller
+	done, err := c.AdmitWrite()
+	require.NoError(t, err)
+	done(true)
+	assert.NoError(t, c.AdmitRead())
+}
+
+func TestController_Drain(t *testing.T) {
+	futures := futuresmocks.NewMockFuturesStore(t)
+	futures.On("Pending").Return(0)
+	c := NewController(&cfg.AdmissionCfg{}, futures, nil, nil)
+
+	done, err := c.AdmitWrite()
+	require.NoError(t, err)
+
+	c.Drain()
+	assert.True(t, c.Draining())
+	_, err = c.AdmitWrite()
+	assert.ErrorIs(t, err, ErrDraining)
+	assert.ErrorIs(t, c.AdmitRead(), ErrDraining)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, c.WaitIdle(ctx), context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		done(false)
+	}()
+	assert.NoError(t, c.WaitIdle(context.Background()))
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("auth: missing or invalid credentials")
+	ErrPermissionDenied = errors.New("auth: permission denied")
+	ErrUnknownAccess    = errors.New("auth: unknown access level")
+	ErrUnknownRole      = errors.New("auth: unknown role")
+)
+
+// Access is a permission level. Every level includes the previous ones.
+type Access int
+
+const (
+	Read Access = iota + 1
+	Write
+	Admin
+)
+
+func (a Access) String() string {
+	switch a {
+	case Read:
+		return "read"
+	case Write:
+		return "write"
+	case Admin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseAccess(s string) (Access, error) {
+	switch strings.ToLower(s) {
+	case "read":
+		return Read, nil
+	case "write":
+		return Write, nil
+	case "admin":
+		return Admin, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownAccess, s)
+	}
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+// AllNamespaces is the namespace of rules granting access to keys of every namespace.
+const AllNamespaces = "*"
+
+type rule struct {
+	namespace string
+	prefix    string
+	access    Access
+}
+
+type staticToken struct {
+	token     []byte
+	principal *Principal
+}
+
+// Service authenticates callers by static API tokens or HMAC signed JWTs
+// and authorizes their access to key prefixes based on configured roles.
+type Service struct {
+	enabled bool
+	tokens  []staticToken
+	jwt     *cfg.JWTCfg
+	roles   map[string][]rule
+	now     func() time.Time
+}
+
+func NewService(c *cfg.AuthCfg) (*Service, error) {
+	s := &Service{
+		enabled: c.Enabled,
+		jwt:     &c.JWT,
+		roles:   make(map[string][]rule, len(c.Roles)),
+		now:     time.Now,
+	}
+
+	for _, r := range c.Roles {
+		rules := make([]rule, 0, len(r.Rules))
+		for _, rr := range r.Rules {
+			access, err := ParseAccess(rr.Access)
+			if err != nil {
+				return nil, fmt.Errorf("role %s: %w", r.Name, err)
+			}
+			rules = append(rules, rule{namespace: rr.Namespace, prefix: rr.Prefix, access: access})
+		}
+		s.roles[r.Name] = rules
+	}
+
+	for _, t := range c.Tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("api token for %s is empty", t.Subject)
+		}
+		for _, role := range t.Roles {
+			if _, ok := s.roles[role]; !ok {
+				return nil, fmt.Errorf("api token for %s: %w: %s", t.Subject, ErrUnknownRole, role)
+			}
+		}
+		s.tokens = append(s.tokens, staticToken{
+			token:     []byte(t.Token),
+			principal: &Principal{Subject: t.Subject, Roles: t.Roles},
+		})
+	}
+
+	return s, nil
+}
+
+// Enabled reports whether requests have to be authenticated.
+// Nil service is treated as disabled.
+func (s *Service) Enabled() bool {
+	return s != nil && s.enabled
+}
+
+// Authenticate resolves a bearer credential into a principal.
+func (s *Service) Authenticate(credential string) (*Principal, error) {
+	if credential == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare(t.token, []byte(credential)) == 1 {
+			return t.principal, nil
+		}
+	}
+
+	if s.jwt.Secret != "" && strings.Count(credential, ".") == 2 {
+		p, err := s.verifyJWT(credential)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+		}
+		return p, nil
+	}
+
+	return nil, ErrUnauthenticated
+}
+
+// Authorize checks that principal has at least the given access to the key of the namespace.
+// Empty namespace is the default one.
+func (s *Service) Authorize(p *Principal, access Access, namespace, key string) error {
+	if p == nil {
+		return ErrUnauthenticated
+	}
+	for _, role := range p.Roles {
+		for _, r := range s.roles[role] {
+			if r.access >= access && (r.namespace == AllNamespaces || r.namespace == namespace) && strings.HasPrefix(key, r.prefix) {
+				return nil
+			}
+		}
+	}
+	if namespace != "" {
+		return fmt.Errorf("%w: %s has no %s access to %q in namespace %q", ErrPermissionDenied, p.Subject, access, key, namespace)
+	}
+	return fmt.Errorf("%w: %s has no %s access to %q", ErrPermissionDenied, p.Subject, access, key)
+}
+
+// BearerToken extracts credential from "Bearer <token>" authorization value.
+func BearerToken(header string) string {
+	const prefix = "bearer "
+	if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return ""
+}
+
+type ctxKeyType string
+
+const (
+	ctxKey ctxKeyType = "principal"
+)
+
+func ToCtx(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey, p)
+}
+
+func FromCtx(ctx context.Context) *Principal {
+	if p, ok := ctx.Value(ctxKey).(*Principal); ok {
+		return p
+	}
+	return nil
+}
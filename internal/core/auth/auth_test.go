@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+func newTestCfg() *cfg.AuthCfg {
+	return &cfg.AuthCfg{
+		Enabled: true,
+		Tokens: []cfg.APITokenCfg{
+			{Token: "reader-token", Subject: "reader-svc", Roles: []string{"reader"}},
+			{Token: "jobs-token", Subject: "batch-job", Roles: []string{"jobs-writer"}},
+		},
+		JWT: cfg.JWTCfg{
+			Secret:     testSecret,
+			Issuer:     "issuer",
+			Audience:   "kv-store",
+			RolesClaim: "roles",
+		},
+		Roles: []cfg.RoleCfg{
+			{Name: "reader", Rules: []cfg.RoleRuleCfg{{Prefix: "", Access: "read"}}},
+			{Name: "jobs-writer", Rules: []cfg.RoleRuleCfg{{Prefix: "jobs/", Access: "write"}}},
+			{Name: "team-a", Rules: []cfg.RoleRuleCfg{{Namespace: "team-a", Prefix: "", Access: "write"}}},
+			{Name: "admin", Rules: []cfg.RoleRuleCfg{{Namespace: AllNamespaces, Prefix: "", Access: "admin"}}},
+		},
+	}
+}
+
+func signJWT(t *testing.T, secret string, alg string, claims map[string]any) string {
+	t.Helper()
+	h, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signHS256(secret, input))
+}
+
+func TestNewService(t *testing.T) {
+	s, err := NewService(newTestCfg())
+	require.NoError(t, err)
+	assert.True(t, s.Enabled())
+
+	var nilSvc *Service
+	assert.False(t, nilSvc.Enabled())
+
+	c := newTestCfg()
+	c.Roles[0].Rules[0].Access = "everything"
+	_, err = NewService(c)
+	assert.ErrorIs(t, err, ErrUnknownAccess)
+
+	c = newTestCfg()
+	c.Tokens[0].Roles = []string{"ghost"}
+	_, err = NewService(c)
+	assert.ErrorIs(t, err, ErrUnknownRole)
+}
+
+func TestService_AuthenticateToken(t *testing.T) {
+	s, err := NewService(newTestCfg())
+	require.NoError(t, err)
+
+	p, err := s.Authenticate("jobs-token")
+	require.NoError(t, err)
+	assert.Equal(t, "batch-job", p.Subject)
+	assert.Equal(t, []string{"jobs-writer"}, p.Roles)
+
+	_, err = s.Authenticate("")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = s.Authenticate("wrong-token")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestService_AuthenticateJWT(t *testing.T) {
+	s, err := NewService(newTestCfg())
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	valid := func() map[string]any {
+		return map[string]any{
+			"sub":   "alice",
+			"iss":   "issuer",
+			"aud":   []string{"other", "kv-store"},
+			"exp":   now.Add(time.Hour).Unix(),
+			"nbf":   now.Add(-time.Minute).Unix(),
+			"roles": []string{"reader", "admin"},
+		}
+	}
+
+	p, err := s.Authenticate(signJWT(t, testSecret, "HS256", valid()))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p.Subject)
+	assert.Equal(t, []string{"reader", "admin"}, p.Roles)
+
+	tests := []struct {
+		name   string
+		secret string
+		alg    string
+		mutate func(map[string]any)
+	}{
+		{name: "bad signature", secret: "other", alg: "HS256", mutate: func(map[string]any) {}},
+		{name: "alg none", secret: testSecret, alg: "none", mutate: func(map[string]any) {}},
+		{name: "expired", secret: testSecret, alg: "HS256", mutate: func(c map[string]any) {
+			c["exp"] = now.Add(-time.Hour).Unix()
+		}},
+		{name: "not yet valid", secret: testSecret, alg: "HS256", mutate: func(c map[string]any) {
+			c["nbf"] = now.Add(time.Hour).Unix()
+		}},
+		{name: "wrong issuer", secret: testSecret, alg: "HS256", mutate: func(c map[string]any) {
+			c["iss"] = "someone"
+		}},
+		{name: "wrong audience", secret: testSecret, alg: "HS256", mutate: func(c map[string]any) {
+			c["aud"] = "other"
+		}},
+		{name: "no subject", secret: testSecret, alg: "HS256", mutate: func(c map[string]any) {
+			delete(c, "sub")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(claims)
+			_, err := s.Authenticate(signJWT(t, tt.secret, tt.alg, claims))
+			assert.ErrorIs(t, err, ErrUnauthenticated)
+		})
+	}
+}
+
+func TestService_Authorize(t *testing.T) {
+	s, err := NewService(newTestCfg())
+	require.NoError(t, err)
+
+	reader := &Principal{Subject: "r", Roles: []string{"reader"}}
+	jobs := &Principal{Subject: "j", Roles: []string{"jobs-writer"}}
+	admin := &Principal{Subject: "a", Roles: []string{"admin"}}
+
+	assert.NoError(t, s.Authorize(reader, Read, "", "any/key"))
+	assert.ErrorIs(t, s.Authorize(reader, Write, "", "any/key"), ErrPermissionDenied)
+
+	assert.NoError(t, s.Authorize(jobs, Write, "", "jobs/1"))
+	assert.NoError(t, s.Authorize(jobs, Read, "", "jobs/1"))
+	assert.ErrorIs(t, s.Authorize(jobs, Write, "", "users/1"), ErrPermissionDenied)
+	assert.ErrorIs(t, s.Authorize(jobs, Admin, "", "jobs/1"), ErrPermissionDenied)
+
+	assert.NoError(t, s.Authorize(admin, Admin, "", ""))
+	assert.NoError(t, s.Authorize(admin, Write, "", "users/1"))
+
+	assert.ErrorIs(t, s.Authorize(nil, Read, "", "k"), ErrUnauthenticated)
+}
+
+func TestService_AuthorizeNamespaces(t *testing.T) {
+	s, err := NewService(newTestCfg())
+	require.NoError(t, err)
+
+	reader := &Principal{Subject: "r", Roles: []string{"reader"}}
+	team := &Principal{Subject: "t", Roles: []string{"team-a"}}
+	admin := &Principal{Subject: "a", Roles: []string{"admin"}}
+
+	// Rules without a namespace grant access to the default namespace only.
+	err = s.Authorize(reader, Read, "team-a", "any/key")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.Contains(t, err.Error(), `namespace "team-a"`)
+
+	assert.NoError(t, s.Authorize(team, Write, "team-a", "any/key"))
+	assert.ErrorIs(t, s.Authorize(team, Read, "team-b", "any/key"), ErrPermissionDenied)
+	assert.ErrorIs(t, s.Authorize(team, Read, "", "any/key"), ErrPermissionDenied)
+
+	assert.NoError(t, s.Authorize(admin, Write, "team-b", "any/key"))
+	assert.NoError(t, s.Authorize(admin, Admin, "", ""))
+}
+
+func TestBearerToken(t *testing.T) {
+	assert.Equal(t, "abc", BearerToken("Bearer abc"))
+	assert.Equal(t, "abc", BearerToken("bearer  abc"))
+	assert.Empty(t, BearerToken("Basic abc"))
+	assert.Empty(t, BearerToken(""))
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	errMalformedToken   = errors.New("malformed token")
+	errUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	errInvalidSignature = errors.New("invalid signature")
+	errTokenExpired     = errors.New("token expired")
+	errTokenNotYetValid = errors.New("token not valid yet")
+	errInvalidIssuer    = errors.New("invalid issuer")
+	errInvalidAudience  = errors.New("invalid audience")
+	errNoSubject        = errors.New("missing subject")
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+// verifyJWT validates HS256 signed token and registered claims.
+func (s *Service) verifyJWT(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformedToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedAlg, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	if !hmac.Equal(sig, signHS256(s.jwt.Secret, parts[0]+"."+parts[1])) {
+		return nil, errInvalidSignature
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := s.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, errNoSubject
+	}
+	return &Principal{
+		Subject: sub,
+		Roles:   stringList(claims[s.jwt.RolesClaim]),
+	}, nil
+}
+
+func (s *Service) validateClaims(claims map[string]any) error {
+	now := s.now()
+	if exp, ok := claims["exp"].(float64); ok {
+		if now.After(time.Unix(int64(exp), 0).Add(s.jwt.Leeway)) {
+			return errTokenExpired
+		}
+	}
+	if nbf, ok := claims["nbf"].(float64); ok {
+		if now.Add(s.jwt.Leeway).Before(time.Unix(int64(nbf), 0)) {
+			return errTokenNotYetValid
+		}
+	}
+	if s.jwt.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != s.jwt.Issuer {
+			return errInvalidIssuer
+		}
+	}
+	if s.jwt.Audience != "" {
+		var found bool
+		for _, aud := range stringList(claims["aud"]) {
+			if aud == s.jwt.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errInvalidAudience
+		}
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return errMalformedToken
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return errMalformedToken
+	}
+	return nil
+}
+
+func signHS256(secret, signingInput string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// stringList accepts both a single string and a list of strings claim.
+func stringList(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
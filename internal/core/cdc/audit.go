@@ -0,0 +1,152 @@
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/audit"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// AuditSinkName is the reserved name of the audit sink's delivery cursor.
+const AuditSinkName = "audit"
+
+var _ cdc.Sink = (*AuditSink)(nil)
+
+// AuditSink writes audit records of committed changes. It's fed by the change stream rather than
+// by request handlers, so mutations committed without a request, e.g. deletes of keys of revoked
+// leases, are audited too and every mutation is audited at least once.
+type AuditSink struct {
+	log audit.Logger
+}
+
+func NewAuditSink(l audit.Logger) *AuditSink {
+	return &AuditSink{log: l}
+}
+
+func (s *AuditSink) Name() string {
+	return AuditSinkName
+}
+
+func (s *AuditSink) Deliver(ctx context.Context, events []cdc.Event) error {
+	for _, e := range events {
+		for _, rec := range AuditRecords(e.Change) {
+			if err := s.log.Record(&rec); err != nil {
+				return fmt.Errorf("failed to record change at index %d: %w", e.Index, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Close doesn't close the audit logger, it's owned by the caller.
+func (s *AuditSink) Close() error {
+	return nil
+}
+
+// AuditRecords describes the change as audit records, one per changed key.
+// Changes which don't mutate keys or namespaces, e.g. lease grants, have no records.
+func AuditRecords(ch fsm.Change) []audit.Record {
+	cmd := ch.Command
+	base := audit.Record{
+		Namespace: cmd.GetNamespace(),
+		Caller:    audit.SystemCaller,
+		LogIndex:  ch.Index,
+	}
+	if ch.Time > 0 {
+		base.Time = time.UnixMilli(ch.Time).UTC()
+	}
+	if o := cmd.GetOrigin(); o != nil {
+		base.Caller = o.GetCaller()
+		base.IP = o.GetIp()
+		base.RequestID = o.GetRequestId()
+	}
+
+	var recs []audit.Record
+	add := func(op audit.Op, key string, value []byte) {
+		rec := base
+		rec.Op = op
+		rec.Key = key
+		if op.HasValue() {
+			rec.ValueSize = len(value)
+			rec.ValueHash = audit.HashValue(value)
+		}
+		recs = append(recs, rec)
+	}
+
+	// Side effects are applied before the command itself, e.g. a lock of an expired lease is taken over
+	// after keys of the lease are deleted.
+	for _, key := range ch.Deleted {
+		add(audit.OpDelete, key, nil)
+	}
+	switch c := cmd.Command.(type) {
+	case *fsm_v1.Command_Put:
+		add(audit.OpPut, c.Put.Key, []byte(c.Put.Value))
+	case *fsm_v1.Command_Set:
+		add(audit.OpPut, c.Set.Key, []byte(c.Set.Value))
+	case *fsm_v1.Command_Mset:
+		for _, put := range c.Mset.Puts {
+			add(audit.OpPut, put.Key, []byte(put.Value))
+		}
+	case *fsm_v1.Command_Incr:
+		add(audit.OpPut, c.Incr.Key, ch.Result)
+	case *fsm_v1.Command_Counter:
+		add(audit.OpPut, c.Counter.Key, ch.Result)
+	case *fsm_v1.Command_Delete:
+		add(audit.OpDelete, c.Delete.Key, nil)
+	case *fsm_v1.Command_Del:
+		for _, key := range c.Del.Keys {
+			add(audit.OpDelete, key, nil)
+		}
+	case *fsm_v1.Command_Expire:
+		add(audit.OpExpire, c.Expire.Key, nil)
+	case *fsm_v1.Command_Flush:
+		add(audit.OpFlush, "", nil)
+	case *fsm_v1.Command_Hset:
+		for _, field := range slices.Sorted(maps.Keys(c.Hset.Fields)) {
+			add(audit.OpHSet, c.Hset.Key, []byte(c.Hset.Fields[field]))
+		}
+	case *fsm_v1.Command_Hincrby:
+		add(audit.OpHSet, c.Hincrby.Key, ch.Result)
+	case *fsm_v1.Command_Hdel:
+		add(audit.OpHDel, c.Hdel.Key, nil)
+	case *fsm_v1.Command_Push:
+		for _, v := range c.Push.Values {
+			add(audit.OpPush, c.Push.Key, []byte(v))
+		}
+	case *fsm_v1.Command_Pop:
+		add(audit.OpPop, c.Pop.Key, nil)
+	case *fsm_v1.Command_Zadd:
+		for _, m := range c.Zadd.Members {
+			add(audit.OpZAdd, c.Zadd.Key, []byte(m.Member))
+		}
+	case *fsm_v1.Command_Zincrby:
+		add(audit.OpZAdd, c.Zincrby.Key, []byte(c.Zincrby.Member))
+	case *fsm_v1.Command_Zrem:
+		add(audit.OpZRem, c.Zrem.Key, nil)
+	case *fsm_v1.Command_Zpopmin:
+		add(audit.OpZPop, c.Zpopmin.Key, nil)
+	case *fsm_v1.Command_JsonPatch:
+		add(audit.OpPatch, c.JsonPatch.Key, c.JsonPatch.Patch)
+	case *fsm_v1.Command_LeaseAttach:
+		for _, key := range c.LeaseAttach.Keys {
+			add(audit.OpLeaseAttach, key, nil)
+		}
+	case *fsm_v1.Command_Lock:
+		add(audit.OpLock, c.Lock.Key, nil)
+	case *fsm_v1.Command_Unlock:
+		add(audit.OpUnlock, c.Unlock.Key, nil)
+	case *fsm_v1.Command_NamespaceCreate:
+		base.Namespace = c.NamespaceCreate.GetNamespace().GetName()
+		add(audit.OpNamespaceCreate, "", nil)
+	case *fsm_v1.Command_NamespaceDelete:
+		base.Namespace = c.NamespaceDelete.Name
+		add(audit.OpNamespaceDelete, "", nil)
+	}
+	return recs
+}
@@ -0,0 +1,119 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/audit"
+	auditmocks "github.com/shrtyk/kv-store/internal/core/ports/audit/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRecords(t *testing.T) {
+	origin := &fsm_v1.Origin{Caller: "alice", Ip: "10.0.0.1", RequestId: "req-1"}
+
+	t.Run("put", func(t *testing.T) {
+		recs := AuditRecords(fsm.Change{Index: 7, Time: 1700000000000, Command: &fsm_v1.Command{
+			Namespace: "team-a",
+			Origin:    origin,
+			Command:   &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: "k", Value: "val"}},
+		}})
+		require.Len(t, recs, 1)
+		assert.Equal(t, audit.Record{
+			Time:      time.UnixMilli(1700000000000).UTC(),
+			Op:        audit.OpPut,
+			Namespace: "team-a",
+			Key:       "k",
+			ValueSize: 3,
+			ValueHash: audit.HashValue([]byte("val")),
+			Caller:    "alice",
+			IP:        "10.0.0.1",
+			RequestID: "req-1",
+			LogIndex:  7,
+		}, recs[0])
+	})
+
+	t.Run("one record per key", func(t *testing.T) {
+		recs := AuditRecords(fsm.Change{Index: 1, Command: &fsm_v1.Command{Origin: origin, Command: &fsm_v1.Command_Hset{
+			Hset: &fsm_v1.HSetCommand{Key: "h", Fields: map[string]string{"b": "2", "a": "1"}},
+		}}})
+		require.Len(t, recs, 2)
+		assert.Equal(t, audit.HashValue([]byte("1")), recs[0].ValueHash, "fields are ordered")
+		assert.Equal(t, audit.OpHSet, recs[1].Op)
+
+		recs = AuditRecords(fsm.Change{Index: 2, Command: &fsm_v1.Command{Command: &fsm_v1.Command_Del{
+			Del: &fsm_v1.DelCommand{Keys: []string{"a", "b"}},
+		}}})
+		require.Len(t, recs, 2)
+		assert.Equal(t, "b", recs[1].Key)
+		assert.Equal(t, audit.OpDelete, recs[1].Op)
+		assert.Empty(t, recs[1].ValueHash)
+	})
+
+	t.Run("new values of counters", func(t *testing.T) {
+		recs := AuditRecords(fsm.Change{Index: 3, Result: []byte("42"), Command: &fsm_v1.Command{Command: &fsm_v1.Command_Incr{
+			Incr: &fsm_v1.IncrCommand{Key: "n", Delta: 1},
+		}}})
+		require.Len(t, recs, 1)
+		assert.Equal(t, audit.OpPut, recs[0].Op)
+		assert.Equal(t, audit.HashValue([]byte("42")), recs[0].ValueHash)
+	})
+
+	t.Run("side effect deletes", func(t *testing.T) {
+		recs := AuditRecords(fsm.Change{Index: 4, Deleted: []string{"a", "b"}, Command: &fsm_v1.Command{Command: &fsm_v1.Command_Reap{
+			Reap: &fsm_v1.ReapCommand{Keys: []string{"a", "b", "c"}},
+		}}})
+		require.Len(t, recs, 2)
+		for _, rec := range recs {
+			assert.Equal(t, audit.OpDelete, rec.Op)
+			assert.Equal(t, audit.SystemCaller, rec.Caller)
+		}
+
+		recs = AuditRecords(fsm.Change{Index: 5, Deleted: []string{"lock"}, Command: &fsm_v1.Command{Origin: origin, Command: &fsm_v1.Command_Lock{
+			Lock: &fsm_v1.LockCommand{Key: "lock", Lease: 2},
+		}}})
+		require.Len(t, recs, 2)
+		assert.Equal(t, audit.OpDelete, recs[0].Op, "lock of the expired lease is deleted first")
+		assert.Equal(t, audit.OpLock, recs[1].Op)
+		assert.Equal(t, "alice", recs[1].Caller)
+	})
+
+	t.Run("namespaces and flush", func(t *testing.T) {
+		recs := AuditRecords(fsm.Change{Index: 6, Command: &fsm_v1.Command{Origin: origin, Command: &fsm_v1.Command_NamespaceDelete{
+			NamespaceDelete: &fsm_v1.NamespaceDeleteCommand{Name: "team-a"},
+		}}})
+		require.Len(t, recs, 1)
+		assert.Equal(t, audit.OpNamespaceDelete, recs[0].Op)
+		assert.Equal(t, "team-a", recs[0].Namespace)
+
+		recs = AuditRecords(fsm.Change{Index: 7, Command: &fsm_v1.Command{Command: &fsm_v1.Command_Flush{Flush: &fsm_v1.FlushCommand{}}}})
+		require.Len(t, recs, 1)
+		assert.Equal(t, audit.OpFlush, recs[0].Op)
+	})
+
+	t.Run("lease grants are not audited", func(t *testing.T) {
+		grant := &fsm_v1.Command{Command: &fsm_v1.Command_LeaseGrant{LeaseGrant: &fsm_v1.LeaseGrantCommand{TtlMs: 1000}}}
+		assert.Empty(t, AuditRecords(fsm.Change{Index: 8, Command: grant}))
+	})
+}
+
+func TestAuditSink_Deliver(t *testing.T) {
+	log := auditmocks.NewMockLogger(t)
+	s := NewAuditSink(log)
+	assert.Equal(t, AuditSinkName, s.Name())
+
+	events := []cdc.Event{
+		{Index: 1, Change: putChange(1, 0, "a")},
+		{Index: 2, Change: putChange(2, 0, "b")},
+	}
+	log.EXPECT().Record(mock.MatchedBy(func(rec *audit.Record) bool { return rec.Key == "a" })).Return(nil).Once()
+	log.EXPECT().Record(mock.MatchedBy(func(rec *audit.Record) bool { return rec.Key == "b" })).Return(errors.New("disk full")).Once()
+	assert.Error(t, s.Deliver(context.Background(), events), "failed records are delivered again")
+}
@@ -0,0 +1,257 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var errNotLeader = errors.New("cdc: node is not the leader")
+
+const (
+	// leaderCheckInterval is how often an idle sink checks whether the node is still the leader.
+	leaderCheckInterval = time.Second
+	// ackTimeout limits committing the cursor of a sink or the set of sinks.
+	ackTimeout = 5 * time.Second
+)
+
+// Feed delivers changes applied by the state machine to sinks while the node is the leader.
+// Sinks are registered through raft, so every replica keeps changes until all sinks acknowledge them.
+// Cursors of sinks are committed through raft after every delivery, so a restarted node or
+// a new leader resumes from the last committed cursor and delivers changes at least once.
+type Feed struct {
+	cfg *cfg.CDCCfg
+	// changes is nil if the fsm doesn't keep applied changes.
+	changes  fsm.ChangeFeed
+	raft     raftapi.Raft
+	proposer proposer.Proposer
+	sinks    []cdc.Sink
+	logger   *slog.Logger
+}
+
+func NewFeed(
+	c *cfg.CDCCfg,
+	changes fsm.ChangeFeed,
+	raft raftapi.Raft,
+	prop proposer.Proposer,
+	sinks []cdc.Sink,
+	l *slog.Logger,
+) *Feed {
+	return &Feed{
+		cfg:      c,
+		changes:  changes,
+		raft:     raft,
+		proposer: prop,
+		sinks:    sinks,
+		logger:   l,
+	}
+}
+
+// Start registers sinks and delivers changes to every sink until ctx is done. It runs without
+// sinks as well, so sinks removed from the configuration are unregistered and their changes dropped.
+func (f *Feed) Start(ctx context.Context) {
+	if f.changes == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Go(func() { f.register(ctx) })
+	for _, sink := range f.sinks {
+		wg.Go(func() { f.run(ctx, sink) })
+	}
+	wg.Wait()
+}
+
+// register proposes the configured set of sinks while the node is the leader and the registered set differs.
+func (f *Feed) register(ctx context.Context) {
+	names := make([]string, 0, len(f.sinks))
+	for _, sink := range f.sinks {
+		names = append(names, sink.Name())
+	}
+	slices.Sort(names)
+
+	for {
+		if _, isLeader := f.raft.State(); isLeader && !slices.Equal(f.changes.Sinks(), names) {
+			if err := f.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_CdcSinks{
+				CdcSinks: &fsm_v1.CdcSinksCommand{Sinks: names},
+			}}); err != nil {
+				f.logger.Warn("failed to register cdc sinks", slog.Any("sinks", names), logger.ErrorAttr(err))
+			} else {
+				f.logger.Info("registered cdc sinks", slog.Any("sinks", names))
+			}
+		}
+		if !sleep(ctx, leaderCheckInterval) {
+			return
+		}
+	}
+}
+
+// run delivers changes to the sink batch by batch while the node is the leader.
+func (f *Feed) run(ctx context.Context, sink cdc.Sink) {
+	l := f.logger.With(slog.String("sink", sink.Name()))
+	// cursor is the last index delivered by this node. It's ahead of the committed one if an ack failed.
+	var cursor int64
+	backoff := f.cfg.RetryMin
+	for ctx.Err() == nil {
+		if _, isLeader := f.raft.State(); !isLeader {
+			// Another leader could deliver more in the meantime.
+			cursor = 0
+			sleep(ctx, leaderCheckInterval)
+			continue
+		}
+
+		committed, ok := f.changes.Cursor(sink.Name())
+		if !ok {
+			// Changes aren't kept for the sink until it's registered.
+			cursor = 0
+			sleep(ctx, leaderCheckInterval)
+			continue
+		}
+		cursor = max(cursor, committed)
+		changes, next, err := f.changes.Changes(cursor, f.cfg.BatchSize)
+		if err != nil {
+			// Nothing is skipped, the sink stays behind until it's removed from the configuration.
+			l.Error("changes after the cursor are no longer kept", slog.Int64("cursor", cursor), logger.ErrorAttr(err))
+			sleep(ctx, leaderCheckInterval)
+			continue
+		}
+		if len(changes) == 0 {
+			select {
+			case <-ctx.Done():
+			case <-next:
+			case <-time.After(leaderCheckInterval):
+			}
+			continue
+		}
+
+		events, err := toEvents(changes)
+		if err != nil {
+			// The cursor isn't moved past a change which wasn't delivered.
+			l.Error("failed to encode changes", slog.Duration("retry_in", backoff), logger.ErrorAttr(err))
+			sleep(ctx, backoff)
+			backoff = min(max(backoff*2, time.Millisecond), f.cfg.RetryMax)
+			continue
+		}
+		backoff = f.cfg.RetryMin
+		if err := f.deliver(ctx, sink, events); err != nil {
+			continue
+		}
+		cursor = changes[len(changes)-1].Index
+		if err := f.ack(ctx, sink.Name(), cursor); err != nil {
+			// Changes since the committed cursor are delivered again after a leader change.
+			l.Warn("failed to commit cdc cursor", slog.Int64("cursor", cursor), logger.ErrorAttr(err))
+		}
+	}
+}
+
+// deliver retries delivering the events with exponential backoff until the sink accepts them,
+// ctx is done or the node is no longer the leader.
+func (f *Feed) deliver(ctx context.Context, sink cdc.Sink, events []cdc.Event) error {
+	backoff := f.cfg.RetryMin
+	for {
+		err := sink.Deliver(ctx, events)
+		if err == nil {
+			return nil
+		}
+		f.logger.Warn("failed to deliver changes",
+			slog.String("sink", sink.Name()),
+			slog.Int64("from_index", events[0].Index),
+			slog.Duration("retry_in", backoff),
+			logger.ErrorAttr(err))
+
+		if !sleep(ctx, backoff) {
+			return ctx.Err()
+		}
+		if _, isLeader := f.raft.State(); !isLeader {
+			return errNotLeader
+		}
+		backoff = min(max(backoff*2, time.Millisecond), f.cfg.RetryMax)
+	}
+}
+
+// ack commits the cursor of the sink.
+func (f *Feed) ack(ctx context.Context, sink string, index int64) error {
+	return f.propose(ctx, &fsm_v1.Command{Command: &fsm_v1.Command_CdcAck{CdcAck: &fsm_v1.CdcAckCommand{
+		Cursor: &fsm_v1.CdcCursor{Sink: sink, Index: index},
+	}}})
+}
+
+// propose commits the command through raft.
+func (f *Feed) propose(ctx context.Context, cmd *fsm_v1.Command) error {
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cdc command: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ackTimeout)
+	defer cancel()
+	prop, err := f.proposer.Propose(ctx, data)
+	if err != nil {
+		return err
+	}
+	if !prop.IsLeader {
+		return errNotLeader
+	}
+	return prop.Future.Wait(ctx)
+}
+
+// toEvents converts changes into events. Changes are never skipped, so a change which can't be
+// encoded fails the whole batch.
+func toEvents(changes []fsm.Change) ([]cdc.Event, error) {
+	events := make([]cdc.Event, 0, len(changes))
+	for _, c := range changes {
+		e, err := toEvent(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode change at index %d: %w", c.Index, err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func toEvent(c fsm.Change) (cdc.Event, error) {
+	msg := c.Command.ProtoReflect()
+	field := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("command"))
+	if field == nil {
+		return cdc.Event{}, errors.New("cdc: empty command")
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg.Get(field).Message().Interface())
+	if err != nil {
+		return cdc.Event{}, err
+	}
+	return cdc.Event{
+		Index:       c.Index,
+		Seq:         c.Seq,
+		Time:        time.UnixMilli(c.Time).UTC(),
+		Namespace:   c.Command.GetNamespace(),
+		Op:          string(field.Name()),
+		Command:     data,
+		DeletedKeys: c.Deleted,
+		Change:      c,
+	}, nil
+}
+
+// sleep waits for d or until ctx is done. Reports whether the whole duration passed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
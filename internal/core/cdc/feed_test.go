@@ -0,0 +1,196 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	cdcmocks "github.com/shrtyk/kv-store/internal/core/ports/cdc/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	proposermocks "github.com/shrtyk/kv-store/internal/core/ports/proposer/mocks"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func putChange(index int64, seq int, key string) fsm.Change {
+	return fsm.Change{
+		Index: index,
+		Seq:   seq,
+		Time:  1700000000000,
+		Command: &fsm_v1.Command{
+			Namespace: "team-a",
+			Command:   &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: key, Value: "v"}},
+		},
+	}
+}
+
+func TestFeed(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	c := &cfg.CDCCfg{Enabled: true, BatchSize: 10, RetryMin: time.Millisecond, RetryMax: 2 * time.Millisecond}
+
+	t.Run("retries delivery and commits the cursor", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		changes := fsmmocks.NewMockChangeFeed(t)
+		sink := cdcmocks.NewMockSink(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		f := NewFeed(c, changes, rmocks.NewStubRaft(nil, true, 0), prop, []cdc.Sink{sink}, l)
+
+		sink.EXPECT().Name().Return("analytics").Maybe()
+		changes.EXPECT().Sinks().Return([]string{"analytics"}).Maybe()
+		changes.EXPECT().Cursor("analytics").Return(3, true).Once()
+		changes.EXPECT().Changes(int64(3), 10).Return([]fsm.Change{putChange(4, 0, "a"), putChange(4, 1, "b")}, nil, nil).Once()
+		sink.EXPECT().Deliver(mock.Anything, mock.Anything).Return(errors.New("unavailable")).Twice()
+		sink.EXPECT().Deliver(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, events []cdc.Event) error {
+			require.Len(t, events, 2)
+			assert.Equal(t, int64(4), events[1].Index)
+			assert.Equal(t, 1, events[1].Seq)
+			return nil
+		}).Once()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.Equal(t, "analytics", cmd.GetCdcAck().GetCursor().GetSink())
+			assert.Equal(t, int64(4), cmd.GetCdcAck().GetCursor().GetIndex())
+			cancel()
+			return &proposer.Proposal{IsLeader: true, LogIndex: 5, Future: future}, nil
+		}).Once()
+
+		f.Start(ctx)
+	})
+
+	t.Run("waits for new changes", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		changes := fsmmocks.NewMockChangeFeed(t)
+		sink := cdcmocks.NewMockSink(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		f := NewFeed(c, changes, rmocks.NewStubRaft(nil, true, 0), prop, []cdc.Sink{sink}, l)
+
+		next := make(chan struct{})
+		close(next)
+		sink.EXPECT().Name().Return("files").Maybe()
+		changes.EXPECT().Sinks().Return([]string{"files"}).Maybe()
+		changes.EXPECT().Cursor("files").Return(0, true).Twice()
+		changes.EXPECT().Changes(int64(0), 10).Return(nil, next, nil).Once()
+		changes.EXPECT().Changes(int64(0), 10).Return([]fsm.Change{putChange(1, 0, "a")}, nil, nil).Once()
+		sink.EXPECT().Deliver(mock.Anything, mock.Anything).Return(nil).Once()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(context.Context, []byte) (*proposer.Proposal, error) {
+			cancel()
+			return &proposer.Proposal{IsLeader: true, LogIndex: 2, Future: future}, nil
+		}).Once()
+
+		f.Start(ctx)
+	})
+
+	t.Run("registers sinks before delivery", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		changes := fsmmocks.NewMockChangeFeed(t)
+		files := cdcmocks.NewMockSink(t)
+		analytics := cdcmocks.NewMockSink(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		f := NewFeed(c, changes, rmocks.NewStubRaft(nil, true, 0), prop, []cdc.Sink{files, analytics}, l)
+
+		files.EXPECT().Name().Return("files").Maybe()
+		analytics.EXPECT().Name().Return("analytics").Maybe()
+		changes.EXPECT().Sinks().Return([]string{"files", "old"}).Once()
+		changes.EXPECT().Cursor(mock.Anything).Return(0, false).Maybe()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.Equal(t, []string{"analytics", "files"}, cmd.GetCdcSinks().GetSinks())
+			cancel()
+			return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+		}).Once()
+
+		f.Start(ctx)
+	})
+
+	t.Run("unregisters removed sinks", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		changes := fsmmocks.NewMockChangeFeed(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		f := NewFeed(&cfg.CDCCfg{}, changes, rmocks.NewStubRaft(nil, true, 0), prop, nil, l)
+
+		changes.EXPECT().Sinks().Return([]string{"old"}).Once()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.NotNil(t, cmd.GetCdcSinks())
+			assert.Empty(t, cmd.GetCdcSinks().GetSinks())
+			cancel()
+			return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+		}).Once()
+
+		f.Start(ctx)
+	})
+
+	t.Run("never skips changes", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		changes := fsmmocks.NewMockChangeFeed(t)
+		sink := cdcmocks.NewMockSink(t)
+		f := NewFeed(c, changes, rmocks.NewStubRaft(nil, true, 0), nil, []cdc.Sink{sink}, l)
+
+		sink.EXPECT().Name().Return("files").Maybe()
+		changes.EXPECT().Sinks().Return([]string{"files"}).Maybe()
+		changes.EXPECT().Cursor("files").Return(2, true).Times(3)
+		changes.EXPECT().Changes(int64(2), 10).Return(nil, nil, fsm.ErrChangesCompacted).Once()
+		// A change which can't be encoded is neither delivered nor acknowledged.
+		changes.EXPECT().Changes(int64(2), 10).Return([]fsm.Change{{Index: 3, Command: &fsm_v1.Command{}}}, nil, nil).Once()
+		changes.EXPECT().Changes(int64(2), 10).RunAndReturn(func(int64, int) ([]fsm.Change, <-chan struct{}, error) {
+			cancel()
+			return nil, nil, nil
+		}).Once()
+
+		f.Start(ctx)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		sink := cdcmocks.NewMockSink(t)
+		f := NewFeed(&cfg.CDCCfg{}, nil, rmocks.NewStubRaft(nil, true, 0), nil, []cdc.Sink{sink}, l)
+
+		f.Start(context.Background())
+	})
+}
+
+func TestToEvent(t *testing.T) {
+	e, err := toEvent(putChange(7, 2, "a"))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(7), e.Index)
+	assert.Equal(t, 2, e.Seq)
+	assert.Equal(t, "team-a", e.Namespace)
+	assert.Equal(t, "put", e.Op)
+	assert.Equal(t, time.UnixMilli(1700000000000).UTC(), e.Time)
+	assert.JSONEq(t, `{"key":"a","value":"v"}`, string(e.Command))
+
+	_, err = toEvent(fsm.Change{Index: 1, Command: &fsm_v1.Command{}})
+	assert.Error(t, err)
+}
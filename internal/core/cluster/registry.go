@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+)
+
+var _ cluster.Membership = (*Registry)(nil)
+
+// Registry resolves node ids into public addresses. It starts with addresses from
+// configuration and switches to the replicated membership once one is committed.
+//
+// Voter set itself is fixed by raft peers configuration, so membership only
+// changes addresses of the configured slots.
+type Registry struct {
+	voters int
+
+	mu      sync.RWMutex
+	members map[int]string
+}
+
+func NewRegistry(voters int, addrs []string) *Registry {
+	r := &Registry{
+		voters:  voters,
+		members: make(map[int]string, len(addrs)),
+	}
+	for id, addr := range addrs {
+		r.members[id] = addr
+	}
+	return r
+}
+
+func (r *Registry) HTTPAddr(id int) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	addr, ok := r.members[id]
+	return addr, ok
+}
+
+func (r *Registry) Members() []cluster.Member {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members := make([]cluster.Member, 0, len(r.members))
+	for id, addr := range r.members {
+		members = append(members, cluster.Member{ID: id, HTTPAddr: addr})
+	}
+	slices.SortFunc(members, func(a, b cluster.Member) int { return a.ID - b.ID })
+	return members
+}
+
+func (r *Registry) Voters() int {
+	return r.voters
+}
+
+func (r *Registry) Replace(members []cluster.Member) {
+	m := make(map[int]string, len(members))
+	for _, member := range members {
+		m[member.ID] = member.HTTPAddr
+	}
+
+	r.mu.Lock()
+	r.members = m
+	r.mu.Unlock()
+}
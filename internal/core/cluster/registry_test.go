@@ -0,0 +1,29 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(3, []string{"http://a:8080", "http://b:8080", "http://c:8080"})
+	assert.Equal(t, 3, r.Voters())
+
+	addr, ok := r.HTTPAddr(1)
+	assert.True(t, ok)
+	assert.Equal(t, "http://b:8080", addr)
+	_, ok = r.HTTPAddr(3)
+	assert.False(t, ok)
+
+	r.Replace([]cluster.Member{{ID: 2, HTTPAddr: "http://new-c:8080"}, {ID: 0, HTTPAddr: "http://a:8080"}})
+
+	assert.Equal(t, []cluster.Member{
+		{ID: 0, HTTPAddr: "http://a:8080"},
+		{ID: 2, HTTPAddr: "http://new-c:8080"},
+	}, r.Members())
+	_, ok = r.HTTPAddr(1)
+	assert.False(t, ok)
+	assert.Equal(t, 3, r.Voters())
+}
@@ -0,0 +1,131 @@
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"google.golang.org/protobuf/proto"
+)
+
+// Reaper deletes expired keys and revokes expired leases through the raft log, so every
+// replica frees them at the same point of the log. Expired keys are hidden from reads before that anyway.
+type Reaper struct {
+	cfg   *cfg.ExpiryCfg
+	store store.Store
+	// namespaces is nil if stores of namespaces aren't reaped.
+	namespaces store.Namespaces
+	// leases is nil if the fsm doesn't keep leases.
+	leases   fsm.LeaseReader
+	raft     raftapi.Raft
+	proposer proposer.Proposer
+	logger   *slog.Logger
+}
+
+func NewReaper(
+	c *cfg.ExpiryCfg,
+	st store.Store,
+	namespaces store.Namespaces,
+	leases fsm.LeaseReader,
+	raft raftapi.Raft,
+	prop proposer.Proposer,
+	l *slog.Logger,
+) *Reaper {
+	return &Reaper{
+		cfg:        c,
+		store:      st,
+		namespaces: namespaces,
+		leases:     leases,
+		raft:       raft,
+		proposer:   prop,
+		logger:     l,
+	}
+}
+
+// Start periodically deletes expired keys while the node is the leader until ctx is done.
+func (r *Reaper) Start(ctx context.Context) {
+	if r.cfg.Interval <= 0 {
+		return
+	}
+	t := time.NewTicker(r.cfg.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		if _, isLeader := r.raft.State(); !isLeader {
+			continue
+		}
+		if _, err := r.Reap(ctx); err != nil {
+			r.logger.Warn("failed to delete expired keys and leases", logger.ErrorAttr(err))
+		}
+	}
+}
+
+// Reap proposes deletion of up to a batch of keys of every namespace and a batch of leases expired
+// by the local clock and returns number of proposed keys and leases.
+func (r *Reaper) Reap(ctx context.Context) (int, error) {
+	now, limit := time.Now().UnixMilli(), max(r.cfg.BatchSize, 1)
+	var leases []int64
+	if r.leases != nil {
+		leases = r.leases.ExpiredLeases(now, limit)
+	}
+	reaped, err := r.propose(ctx, "", r.store.ExpiredKeys(now, limit), leases, now)
+	if err != nil || r.namespaces == nil {
+		return reaped, err
+	}
+
+	for _, ns := range r.namespaces.List() {
+		st, err := r.namespaces.Store(ns.Name)
+		if err != nil {
+			// Deleted since it was listed.
+			continue
+		}
+		n, err := r.propose(ctx, ns.Name, st.ExpiredKeys(now, limit), nil, now)
+		reaped += n
+		if err != nil {
+			return reaped, err
+		}
+	}
+	return reaped, nil
+}
+
+// propose proposes a reap command for the namespace unless there is nothing to reap.
+func (r *Reaper) propose(ctx context.Context, namespace string, keys []string, leases []int64, now int64) (int, error) {
+	if len(keys) == 0 && len(leases) == 0 {
+		return 0, nil
+	}
+
+	data, err := proto.Marshal(&fsm_v1.Command{
+		Namespace: namespace,
+		Command:   &fsm_v1.Command_Reap{Reap: &fsm_v1.ReapCommand{Keys: keys, Now: now, Leases: leases}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal reap command: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Interval)
+	defer cancel()
+	prop, err := r.proposer.Propose(ctx, data)
+	if err != nil {
+		return 0, err
+	}
+	if !prop.IsLeader {
+		return 0, nil
+	}
+	if err := prop.Future.Wait(ctx); err != nil {
+		return 0, err
+	}
+	return len(keys) + len(leases), nil
+}
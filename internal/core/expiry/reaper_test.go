@@ -0,0 +1,120 @@
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	proposermocks "github.com/shrtyk/kv-store/internal/core/ports/proposer/mocks"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	storemocks "github.com/shrtyk/kv-store/internal/core/ports/store/mocks"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReaper_Reap(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	c := &cfg.ExpiryCfg{Interval: time.Second, BatchSize: 2}
+
+	t.Run("proposes expired keys", func(t *testing.T) {
+		st := storemocks.NewMockStore(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		r := NewReaper(c, st, nil, nil, rmocks.NewStubRaft(nil, true, 0), prop, l)
+
+		st.EXPECT().ExpiredKeys(mock.Anything, 2).Return([]string{"a", "b"}).Once()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.Equal(t, []string{"a", "b"}, cmd.GetReap().GetKeys())
+			assert.NotZero(t, cmd.GetReap().GetNow())
+			return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+		}).Once()
+
+		n, err := r.Reap(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("proposes expired leases", func(t *testing.T) {
+		st := storemocks.NewMockStore(t)
+		leases := fsmmocks.NewMockLeaseReader(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		r := NewReaper(c, st, nil, leases, rmocks.NewStubRaft(nil, true, 0), prop, l)
+
+		st.EXPECT().ExpiredKeys(mock.Anything, 2).Return(nil).Once()
+		leases.EXPECT().ExpiredLeases(mock.Anything, 2).Return([]int64{7}).Once()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.Empty(t, cmd.GetReap().GetKeys())
+			assert.Equal(t, []int64{7}, cmd.GetReap().GetLeases())
+			return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+		}).Once()
+
+		n, err := r.Reap(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("proposes expired keys of namespaces", func(t *testing.T) {
+		st := storemocks.NewMockStore(t)
+		nsStore := storemocks.NewMockStore(t)
+		spaces := storemocks.NewMockNamespaces(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		r := NewReaper(c, st, spaces, nil, rmocks.NewStubRaft(nil, true, 0), prop, l)
+
+		st.EXPECT().ExpiredKeys(mock.Anything, 2).Return(nil).Once()
+		spaces.EXPECT().List().Return([]pstore.Namespace{{Name: "team-a"}}).Once()
+		spaces.EXPECT().Store("team-a").Return(nsStore, nil).Once()
+		nsStore.EXPECT().ExpiredKeys(mock.Anything, 2).Return([]string{"a"}).Once()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.Equal(t, "team-a", cmd.GetNamespace())
+			assert.Equal(t, []string{"a"}, cmd.GetReap().GetKeys())
+			return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+		}).Once()
+
+		n, err := r.Reap(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("nothing expired", func(t *testing.T) {
+		st := storemocks.NewMockStore(t)
+		r := NewReaper(c, st, nil, nil, rmocks.NewStubRaft(nil, true, 0), proposermocks.NewMockProposer(t), l)
+
+		st.EXPECT().ExpiredKeys(mock.Anything, 2).Return(nil).Once()
+
+		n, err := r.Reap(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
+
+func TestReaper_StartOnlyOnLeader(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	st := storemocks.NewMockStore(t)
+	r := NewReaper(&cfg.ExpiryCfg{Interval: time.Millisecond}, st, nil, nil, rmocks.NewStubRaft(nil, false, 1), proposermocks.NewMockProposer(t), l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r.Start(ctx)
+
+	st.AssertNotCalled(t, "ExpiredKeys", mock.Anything, mock.Anything)
+}
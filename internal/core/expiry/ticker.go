@@ -0,0 +1,97 @@
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"google.golang.org/protobuf/proto"
+)
+
+// Ticker proposes tick commands while the leader has no other writes, so the cluster time of replicas
+// keeps up with the leader's clock and time-dependent state advances identically on every replica.
+type Ticker struct {
+	cfg *cfg.ExpiryCfg
+	// clock is nil if the fsm doesn't keep cluster time.
+	clock    fsm.ClusterClock
+	raft     raftapi.Raft
+	proposer proposer.Proposer
+	logger   *slog.Logger
+}
+
+func NewTicker(
+	c *cfg.ExpiryCfg,
+	clock fsm.ClusterClock,
+	raft raftapi.Raft,
+	prop proposer.Proposer,
+	l *slog.Logger,
+) *Ticker {
+	return &Ticker{
+		cfg:      c,
+		clock:    clock,
+		raft:     raft,
+		proposer: prop,
+		logger:   l,
+	}
+}
+
+// Start periodically proposes ticks while the node is the leader until ctx is done.
+func (t *Ticker) Start(ctx context.Context) {
+	if t.cfg.TickInterval <= 0 || t.clock == nil {
+		return
+	}
+	tk := time.NewTicker(t.cfg.TickInterval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+
+		if _, isLeader := t.raft.State(); !isLeader {
+			continue
+		}
+		if _, err := t.Tick(ctx); err != nil {
+			t.logger.Warn("failed to advance cluster time", logger.ErrorAttr(err))
+		}
+	}
+}
+
+// Tick proposes a tick unless the cluster time is within the tick interval from the local clock,
+// i.e. other writes keep it up to date. Reports whether a tick was applied.
+func (t *Ticker) Tick(ctx context.Context) (bool, error) {
+	now := time.Now().UnixMilli()
+	if now-t.clock.ClusterTime() < t.cfg.TickInterval.Milliseconds() {
+		return false, nil
+	}
+
+	data, err := proto.Marshal(&fsm_v1.Command{
+		Now:     now,
+		Command: &fsm_v1.Command_Tick{Tick: &fsm_v1.TickCommand{}},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal tick command: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.TickInterval)
+	defer cancel()
+	prop, err := t.proposer.Propose(ctx, data)
+	if err != nil {
+		return false, err
+	}
+	if !prop.IsLeader {
+		return false, nil
+	}
+	if err := prop.Future.Wait(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
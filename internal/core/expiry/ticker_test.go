@@ -0,0 +1,57 @@
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	proposermocks "github.com/shrtyk/kv-store/internal/core/ports/proposer/mocks"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTicker_Tick(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	c := &cfg.ExpiryCfg{TickInterval: time.Second}
+
+	t.Run("proposes tick while idle", func(t *testing.T) {
+		clock := fsmmocks.NewMockClusterClock(t)
+		prop := proposermocks.NewMockProposer(t)
+		future := futuresmocks.NewMockFuture(t)
+		tk := NewTicker(c, clock, rmocks.NewStubRaft(nil, true, 0), prop, l)
+
+		clock.EXPECT().ClusterTime().Return(time.Now().Add(-2 * time.Second).UnixMilli()).Once()
+		future.EXPECT().Wait(mock.Anything).Return(nil).Once()
+		prop.EXPECT().Propose(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, data []byte) (*proposer.Proposal, error) {
+			var cmd fsm_v1.Command
+			require.NoError(t, proto.Unmarshal(data, &cmd))
+			assert.NotNil(t, cmd.GetTick())
+			assert.NotZero(t, cmd.GetNow())
+			return &proposer.Proposal{IsLeader: true, LogIndex: 1, Future: future}, nil
+		}).Once()
+
+		ticked, err := tk.Tick(context.Background())
+		require.NoError(t, err)
+		assert.True(t, ticked)
+	})
+
+	t.Run("skips tick after recent writes", func(t *testing.T) {
+		clock := fsmmocks.NewMockClusterClock(t)
+		tk := NewTicker(c, clock, rmocks.NewStubRaft(nil, true, 0), proposermocks.NewMockProposer(t), l)
+
+		clock.EXPECT().ClusterTime().Return(time.Now().UnixMilli()).Once()
+
+		ticked, err := tk.Tick(context.Background())
+		require.NoError(t, err)
+		assert.False(t, ticked)
+	})
+}
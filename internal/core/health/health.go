@@ -0,0 +1,155 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	raftapi "github.com/shrtyk/raft-core/api"
+)
+
+// Status describes the node as seen by liveness and readiness probes.
+type Status struct {
+	Live     bool `json:"live"`
+	Ready    bool `json:"ready"`
+	Draining bool `json:"draining"`
+
+	Term      int64 `json:"term"`
+	IsLeader  bool  `json:"is_leader"`
+	HasLeader bool  `json:"has_leader"`
+	// LeaderID is id of the leader known to a follower, -1 otherwise.
+	LeaderID int `json:"leader_id"`
+
+	AppliedIndex int64 `json:"applied_index"`
+	// ApplyBacklog is a number of committed entries delivered by raft and not applied yet.
+	ApplyBacklog int `json:"apply_backlog"`
+	// LastAppliedAt is time the node applied its last entry. raft-core doesn't report
+	// time of the last contact with the leader, so it's the closest available signal.
+	LastAppliedAt  time.Time `json:"last_applied_at,omitzero"`
+	Restoring      bool      `json:"restoring"`
+	StateAvailable bool      `json:"state_available"`
+
+	// Failures lists reasons the node isn't ready.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Checker derives liveness and readiness of the node from raft and state machine state.
+type Checker struct {
+	cfg          *cfg.HealthCfg
+	raft         raftapi.Raft
+	fsm          fsm.StatusReporter
+	applied      fsm.AppliedIndex
+	admission    *admission.Controller
+	applyBacklog func() int
+}
+
+// NewChecker creates a checker. applyBacklog reports number of committed
+// entries waiting to be applied by the state machine.
+func NewChecker(
+	c *cfg.HealthCfg,
+	raft raftapi.Raft,
+	fsmStatus fsm.StatusReporter,
+	applied fsm.AppliedIndex,
+	adm *admission.Controller,
+	applyBacklog func() int,
+) *Checker {
+	return &Checker{
+		cfg:          c,
+		raft:         raft,
+		fsm:          fsmStatus,
+		applied:      applied,
+		admission:    adm,
+		applyBacklog: applyBacklog,
+	}
+}
+
+// Live reports whether the process is able to make progress. It doesn't depend on
+// other nodes, so a partitioned node isn't restarted for nothing.
+func (c *Checker) Live() *Status {
+	st := c.local()
+	st.Live = !c.raft.Killed()
+	if !st.Live {
+		st.Failures = append(st.Failures, "raft node is stopped")
+	}
+	return st
+}
+
+// Ready reports whether the node should receive client traffic. A leader confirms
+// it still has a quorum and has applied all committed entries with a quorum read.
+func (c *Checker) Ready(ctx context.Context) *Status {
+	st := c.Live()
+
+	if st.Draining {
+		st.Failures = append(st.Failures, "node is draining")
+	}
+	if !st.StateAvailable {
+		st.Failures = append(st.Failures, "state machine stopped applying commands")
+	}
+	if st.Restoring {
+		st.Failures = append(st.Failures, "snapshot is being restored")
+	}
+	if c.cfg.MaxApplyBacklog > 0 && st.ApplyBacklog > c.cfg.MaxApplyBacklog {
+		st.Failures = append(st.Failures, "too many committed entries are not applied")
+	}
+	if st.Live {
+		if err := c.probeLeader(ctx, st); err != nil {
+			st.Failures = append(st.Failures, err.Error())
+		}
+	}
+
+	st.Ready = len(st.Failures) == 0
+	return st
+}
+
+func (c *Checker) local() *Status {
+	st := &Status{
+		Draining:       c.admission.Draining(),
+		LeaderID:       -1,
+		StateAvailable: true,
+	}
+	st.Term, st.IsLeader = c.raft.State()
+	if c.applied != nil {
+		st.AppliedIndex = c.applied.AppliedIndex()
+		st.LastAppliedAt = c.applied.AppliedAt()
+	}
+	if c.fsm != nil {
+		st.Restoring = c.fsm.Restoring()
+		st.StateAvailable = c.fsm.Available()
+	}
+	if c.applyBacklog != nil {
+		st.ApplyBacklog = c.applyBacklog()
+	}
+	return st
+}
+
+// probeLeader fills leadership fields. Followers only check they know a leader,
+// while the leader runs a read through raft which requires a quorum.
+func (c *Checker) probeLeader(ctx context.Context, st *Status) error {
+	if c.cfg.ProbeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.ProbeTimeout)
+		defer cancel()
+	}
+
+	res, err := c.raft.ReadOnly(ctx, nil)
+	if err != nil && !errors.Is(err, store.ErrNoSuchKey) {
+		return fmt.Errorf("leader failed to confirm quorum: %w", err)
+	}
+	if res == nil || res.IsLeader {
+		st.IsLeader, st.HasLeader = true, true
+		return nil
+	}
+
+	st.IsLeader = false
+	st.LeaderID = res.LeaderId
+	st.HasLeader = res.LeaderId >= 0
+	if !st.HasLeader {
+		return errors.New("node doesn't know a leader")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/admission"
+	fsmmocks "github.com/shrtyk/kv-store/internal/core/ports/fsm/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	storemocks "github.com/shrtyk/kv-store/internal/core/ports/store/mocks"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSetup struct {
+	raft      *rmocks.StubRaft
+	fsm       *fsmmocks.MockStatusReporter
+	admission *admission.Controller
+	backlog   int
+	checker   *Checker
+}
+
+func setup(t *testing.T, isLeader bool, leaderID int) *testSetup {
+	t.Helper()
+	st := storemocks.NewMockStore(t)
+	st.On("Get", "").Return("", store.ErrNoSuchKey).Maybe()
+
+	applied := fsmmocks.NewMockAppliedIndex(t)
+	applied.On("AppliedIndex").Return(int64(10)).Maybe()
+	applied.On("AppliedAt").Return(time.Now()).Maybe()
+
+	s := &testSetup{
+		raft:      rmocks.NewStubRaft(st, isLeader, leaderID),
+		fsm:       fsmmocks.NewMockStatusReporter(t),
+		admission: admission.NewController(&cfg.AdmissionCfg{}, nil, nil, nil),
+	}
+	s.checker = NewChecker(
+		&cfg.HealthCfg{ProbeTimeout: time.Second, MaxApplyBacklog: 5},
+		s.raft,
+		s.fsm,
+		applied,
+		s.admission,
+		func() int { return s.backlog },
+	)
+	return s
+}
+
+func (s *testSetup) fsmState(restoring, available bool) {
+	s.fsm.On("Restoring").Return(restoring)
+	s.fsm.On("Available").Return(available)
+}
+
+func TestChecker_Ready(t *testing.T) {
+	t.Run("leader", func(t *testing.T) {
+		s := setup(t, true, 0)
+		s.fsmState(false, true)
+
+		st := s.checker.Ready(context.Background())
+
+		assert.True(t, st.Ready)
+		assert.True(t, st.Live)
+		assert.True(t, st.IsLeader)
+		assert.True(t, st.HasLeader)
+		assert.Equal(t, int64(10), st.AppliedIndex)
+		assert.Empty(t, st.Failures)
+	})
+
+	t.Run("follower with leader", func(t *testing.T) {
+		s := setup(t, false, 2)
+		s.fsmState(false, true)
+
+		st := s.checker.Ready(context.Background())
+
+		assert.True(t, st.Ready)
+		assert.False(t, st.IsLeader)
+		assert.True(t, st.HasLeader)
+		assert.Equal(t, 2, st.LeaderID)
+	})
+
+	t.Run("follower without leader", func(t *testing.T) {
+		s := setup(t, false, -1)
+		s.fsmState(false, true)
+
+		st := s.checker.Ready(context.Background())
+
+		assert.False(t, st.Ready)
+		assert.False(t, st.HasLeader)
+		assert.Len(t, st.Failures, 1)
+	})
+
+	t.Run("leader without quorum", func(t *testing.T) {
+		s := setup(t, true, 0)
+		s.fsmState(false, true)
+		s.raft.SetReadOnlyResult(nil, errors.New("timeout waiting for state machine to catch up"))
+
+		st := s.checker.Ready(context.Background())
+
+		assert.False(t, st.Ready)
+		assert.Contains(t, st.Failures[0], "quorum")
+	})
+
+	t.Run("state machine problems", func(t *testing.T) {
+		s := setup(t, true, 0)
+		s.fsmState(true, false)
+		s.backlog = 6
+
+		st := s.checker.Ready(context.Background())
+
+		assert.False(t, st.Ready)
+		assert.True(t, st.Live)
+		assert.True(t, st.Restoring)
+		assert.False(t, st.StateAvailable)
+		assert.Equal(t, 6, st.ApplyBacklog)
+		assert.Len(t, st.Failures, 3)
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		s := setup(t, true, 0)
+		s.fsmState(false, true)
+		s.admission.Drain()
+
+		st := s.checker.Ready(context.Background())
+
+		assert.False(t, st.Ready)
+		assert.True(t, st.Draining)
+	})
+}
+
+func TestChecker_Live(t *testing.T) {
+	s := setup(t, false, -1)
+	s.fsm.On("Restoring").Return(false)
+	s.fsm.On("Available").Return(false)
+
+	// Missing leader and broken state don't fail liveness.
+	assert.True(t, s.checker.Live().Live)
+
+	_ = s.raft.Stop()
+	st := s.checker.Live()
+	assert.False(t, st.Live)
+	assert.NotEmpty(t, st.Failures)
+}
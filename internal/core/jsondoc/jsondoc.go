@@ -0,0 +1,164 @@
+// Package jsondoc reads and patches JSON documents stored as string values.
+//
+// Patched documents are written back with object members sorted by name, so every replica
+// applying the same patch stores the same bytes. Numbers keep their original text.
+package jsondoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrNotJSON is returned when the stored value isn't a JSON document.
+	ErrNotJSON = errors.New("value is not a JSON document")
+	// ErrInvalidPath is returned for a malformed path.
+	ErrInvalidPath = errors.New("invalid JSON path")
+	// ErrPathNotFound is returned when a path points to nothing in the document.
+	ErrPathNotFound = errors.New("JSON path not found")
+	// ErrInvalidPatch is returned for a malformed patch.
+	ErrInvalidPatch = errors.New("invalid JSON patch")
+	// ErrPatchFailed is returned when a well formed patch can't be applied to the document.
+	ErrPatchFailed = errors.New("JSON patch can't be applied")
+)
+
+// PatchType selects the format of a patch.
+type PatchType int
+
+const (
+	// PatchJSON is a JSON Patch, RFC 6902.
+	PatchJSON PatchType = iota
+	// PatchMerge is a JSON Merge Patch, RFC 7386.
+	PatchMerge
+)
+
+func (t PatchType) String() string {
+	switch t {
+	case PatchJSON:
+		return "json-patch"
+	case PatchMerge:
+		return "merge-patch"
+	default:
+		return "unknown"
+	}
+}
+
+// Get returns the part of doc at path. Path is $ followed by member names and array indexes,
+// like $.a.b[0] or $['a b'].
+func Get(doc []byte, path string) ([]byte, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	node, err := decode(doc, ErrNotJSON)
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range segs {
+		if node, err = child(node, seg); err != nil {
+			return nil, ErrPathNotFound
+		}
+	}
+	return encode(node)
+}
+
+// CheckPath returns an error if path is malformed.
+func CheckPath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+// Patch applies the patch to doc and returns the new document. Nil doc is patched as null.
+// The patch is applied as a whole or not at all.
+func Patch(doc, patch []byte, typ PatchType) ([]byte, error) {
+	var target any
+	if doc != nil {
+		var err error
+		if target, err = decode(doc, ErrNotJSON); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	switch typ {
+	case PatchJSON:
+		var ops []operation
+		if ops, err = parseOperations(patch); err != nil {
+			return nil, err
+		}
+		target, err = applyOperations(target, ops)
+	case PatchMerge:
+		var p any
+		if p, err = decode(patch, ErrInvalidPatch); err != nil {
+			return nil, err
+		}
+		target = mergePatch(target, p)
+	default:
+		return nil, fmt.Errorf("%w: unknown patch type %d", ErrInvalidPatch, typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return encode(target)
+}
+
+// CheckPatch returns an error if the patch is malformed. It doesn't check whether the patch applies.
+func CheckPatch(patch []byte, typ PatchType) error {
+	switch typ {
+	case PatchJSON:
+		_, err := parseOperations(patch)
+		return err
+	case PatchMerge:
+		_, err := decode(patch, ErrInvalidPatch)
+		return err
+	default:
+		return fmt.Errorf("%w: unknown patch type %d", ErrInvalidPatch, typ)
+	}
+}
+
+// mergePatch implements the MergePatch function of RFC 7386.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for name, v := range p {
+		if v == nil {
+			delete(t, name)
+		} else {
+			t[name] = mergePatch(t[name], v)
+		}
+	}
+	return t
+}
+
+// decode parses a single JSON value keeping numbers as json.Number. Malformed data gives errKind.
+func decode(data []byte, errKind error) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: %v", errKind, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: trailing data", errKind)
+	}
+	return v, nil
+}
+
+func encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
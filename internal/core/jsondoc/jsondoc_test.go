@@ -0,0 +1,136 @@
+package jsondoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	doc := []byte(`{"a":{"b":[10,{"c d":true}],"n":1.50},"e":null}`)
+	for path, want := range map[string]string{
+		"$":               `{"a":{"b":[10,{"c d":true}],"n":1.50},"e":null}`,
+		"$.a.b":           `[10,{"c d":true}]`,
+		"$.a.b[0]":        `10`,
+		"$.a.b[1]['c d']": `true`,
+		`$["a"].n`:        `1.50`,
+		"$.e":             `null`,
+	} {
+		got, err := Get(doc, path)
+		require.NoError(t, err, path)
+		assert.JSONEq(t, want, string(got), path)
+	}
+
+	for _, path := range []string{"$.x", "$.a.b[2]", "$.a[0]", "$.a.b.c", "$.e.f"} {
+		_, err := Get(doc, path)
+		assert.ErrorIs(t, err, ErrPathNotFound, path)
+	}
+	for _, path := range []string{"", "a.b", "$.", "$..a", "$[", "$[-1]", "$['a]", "$['a'", "$a"} {
+		assert.ErrorIs(t, CheckPath(path), ErrInvalidPath, path)
+	}
+	_, err := Get([]byte("not json"), "$")
+	assert.ErrorIs(t, err, ErrNotJSON)
+	_, err = Get([]byte(`{} {}`), "$")
+	assert.ErrorIs(t, err, ErrNotJSON)
+}
+
+func TestPatch_Merge(t *testing.T) {
+	doc := []byte(`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"text"}`)
+	patch := []byte(`{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`)
+
+	got, err := Patch(doc, patch, PatchMerge)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`{"author":{"givenName":"John"},"content":"text","phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`,
+		string(got))
+
+	got, err = Patch(nil, []byte(`{"a":{"b":null,"c":1}}`), PatchMerge)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"c":1}}`, string(got))
+	got, err = Patch([]byte(`{"a":1}`), []byte(`["x"]`), PatchMerge)
+	require.NoError(t, err)
+	assert.Equal(t, `["x"]`, string(got))
+
+	_, err = Patch([]byte(`{`), []byte(`{}`), PatchMerge)
+	assert.ErrorIs(t, err, ErrNotJSON)
+	assert.ErrorIs(t, CheckPatch([]byte(`{`), PatchMerge), ErrInvalidPatch)
+}
+
+func TestPatch_JSONPatch(t *testing.T) {
+	doc := []byte(`{"a":{"b":1},"list":[1,2,3],"x~y":{"p/q":"v"}}`)
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"add member", `[{"op":"add","path":"/a/c","value":[true]}]`,
+			`{"a":{"b":1,"c":[true]},"list":[1,2,3],"x~y":{"p/q":"v"}}`},
+		{"add inserts into array", `[{"op":"add","path":"/list/1","value":9},{"op":"add","path":"/list/-","value":4}]`,
+			`{"a":{"b":1},"list":[1,9,2,3,4],"x~y":{"p/q":"v"}}`},
+		{"add replaces root", `[{"op":"add","path":"","value":{"new":1}}]`, `{"new":1}`},
+		{"remove", `[{"op":"remove","path":"/list/0"},{"op":"remove","path":"/x~0y/p~1q"}]`,
+			`{"a":{"b":1},"list":[2,3],"x~y":{}}`},
+		{"replace", `[{"op":"replace","path":"/a/b","value":null},{"op":"replace","path":"/list/2","value":"z"}]`,
+			`{"a":{"b":null},"list":[1,2,"z"],"x~y":{"p/q":"v"}}`},
+		{"move", `[{"op":"move","from":"/a/b","path":"/list/0"}]`,
+			`{"a":{},"list":[1,1,2,3],"x~y":{"p/q":"v"}}`},
+		{"copy is deep", `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/d","value":2}]`,
+			`{"a":{"b":1},"c":{"b":1,"d":2},"list":[1,2,3],"x~y":{"p/q":"v"}}`},
+		{"test compares numbers by value", `[{"op":"test","path":"/list","value":[1.0,2,3e0]},{"op":"test","path":"/a","value":{"b":1}}]`,
+			`{"a":{"b":1},"list":[1,2,3],"x~y":{"p/q":"v"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, CheckPatch([]byte(tt.patch), PatchJSON))
+			got, err := Patch(doc, []byte(tt.patch), PatchJSON)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	got, err := Patch(nil, []byte(`[{"op":"add","path":"","value":[]},{"op":"add","path":"/-","value":1}]`), PatchJSON)
+	require.NoError(t, err)
+	assert.Equal(t, `[1]`, string(got))
+}
+
+func TestPatch_JSONPatchFailures(t *testing.T) {
+	doc := []byte(`{"a":{"b":1},"list":[1,2,3]}`)
+	for name, patch := range map[string]string{
+		"remove missing":     `[{"op":"remove","path":"/missing"}]`,
+		"replace missing":    `[{"op":"replace","path":"/a/missing","value":1}]`,
+		"add past end":       `[{"op":"add","path":"/list/4","value":1}]`,
+		"add under scalar":   `[{"op":"add","path":"/a/b/c","value":1}]`,
+		"add missing parent": `[{"op":"add","path":"/x/y","value":1}]`,
+		"leading zero index": `[{"op":"remove","path":"/list/01"}]`,
+		"move into child":    `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+		"failed test":        `[{"op":"test","path":"/a/b","value":"1"}]`,
+		"remove root":        `[{"op":"remove","path":""}]`,
+		"later op fails":     `[{"op":"add","path":"/z","value":1},{"op":"test","path":"/z","value":2}]`,
+	} {
+		_, err := Patch(doc, []byte(patch), PatchJSON)
+		assert.ErrorIs(t, err, ErrPatchFailed, name)
+		assert.NoError(t, CheckPatch([]byte(patch), PatchJSON), name)
+	}
+
+	for name, patch := range map[string]string{
+		"not an array": `{"op":"add","path":"/a","value":1}`,
+		"unknown op":   `[{"op":"merge","path":"/a"}]`,
+		"no path":      `[{"op":"remove"}]`,
+		"no value":     `[{"op":"add","path":"/a"}]`,
+		"no from":      `[{"op":"copy","path":"/a"}]`,
+		"bad pointer":  `[{"op":"remove","path":"a"}]`,
+		"bad escape":   `[{"op":"remove","path":"/a~2"}]`,
+		"malformed":    `[{"op":`,
+	} {
+		assert.ErrorIs(t, CheckPatch([]byte(patch), PatchJSON), ErrInvalidPatch, name)
+		_, err := Patch(doc, []byte(patch), PatchJSON)
+		assert.ErrorIs(t, err, ErrInvalidPatch, name)
+	}
+
+	// A failed patch leaves the input untouched, so the caller can keep the old document.
+	before := string(doc)
+	_, err := Patch(doc, []byte(`[{"op":"remove","path":"/a"},{"op":"remove","path":"/a"}]`), PatchJSON)
+	assert.ErrorIs(t, err, ErrPatchFailed)
+	assert.Equal(t, before, string(doc))
+}
@@ -0,0 +1,317 @@
+package jsondoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// operation is a single operation of a JSON Patch.
+type operation struct {
+	op    string
+	path  []string
+	from  []string
+	value any
+}
+
+// parseOperations parses and checks a JSON Patch document.
+func parseOperations(patch []byte) ([]operation, error) {
+	var raw []struct {
+		Op    string          `json:"op"`
+		Path  *string         `json:"path"`
+		From  *string         `json:"from"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(patch, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	ops := make([]operation, 0, len(raw))
+	for i, r := range raw {
+		op := operation{op: r.Op}
+		if r.Path == nil {
+			return nil, fmt.Errorf("%w: operation %d has no path", ErrInvalidPatch, i)
+		}
+		var err error
+		if op.path, err = parsePointer(*r.Path); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+
+		switch r.Op {
+		case "add", "replace", "test":
+			if r.Value == nil {
+				return nil, fmt.Errorf("%w: operation %d has no value", ErrInvalidPatch, i)
+			}
+			if op.value, err = decode(r.Value, ErrInvalidPatch); err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i, err)
+			}
+		case "move", "copy":
+			if r.From == nil {
+				return nil, fmt.Errorf("%w: operation %d has no from", ErrInvalidPatch, i)
+			}
+			if op.from, err = parsePointer(*r.From); err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i, err)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("%w: operation %d has unknown op %q", ErrInvalidPatch, i, r.Op)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// parsePointer splits a JSON Pointer, RFC 6901, into unescaped reference tokens.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("%w: pointer %q doesn't start with /", ErrInvalidPatch, p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		for j := 0; j < len(t); j++ {
+			if t[j] == '~' && (j+1 == len(t) || (t[j+1] != '0' && t[j+1] != '1')) {
+				return nil, fmt.Errorf("%w: bad escape in pointer %q", ErrInvalidPatch, p)
+			}
+		}
+		// ~1 is unescaped first, so ~01 becomes ~1 rather than /.
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func applyOperations(doc any, ops []operation) (any, error) {
+	var err error
+	for i, op := range ops {
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return doc, nil
+}
+
+func (op operation) apply(doc any) (any, error) {
+	switch op.op {
+	case "add":
+		return add(doc, op.path, op.value)
+	case "remove":
+		doc, _, err := remove(doc, op.path)
+		return doc, err
+	case "replace":
+		if len(op.path) == 0 {
+			return op.value, nil
+		}
+		doc, _, err := remove(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, op.value)
+	case "move":
+		if len(op.from) < len(op.path) && slices.Equal(op.from, op.path[:len(op.from)]) {
+			return nil, fmt.Errorf("%w: can't move a value into itself", ErrPatchFailed)
+		}
+		doc, v, err := remove(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, v)
+	case "copy":
+		v, err := get(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, deepCopy(v))
+	case "test":
+		v, err := get(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(v, op.value) {
+			return nil, fmt.Errorf("%w: test failed", ErrPatchFailed)
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.op)
+	}
+}
+
+func get(doc any, tokens []string) (any, error) {
+	node := doc
+	for _, t := range tokens {
+		var err error
+		if node, err = pointerChild(node, t); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func add(doc any, tokens []string, v any) (any, error) {
+	if len(tokens) == 0 {
+		return v, nil
+	}
+	return modify(doc, tokens, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[token] = v
+			return c, nil
+		case []any:
+			i := len(c)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(c)+1); err != nil {
+					return nil, err
+				}
+			}
+			return slices.Insert(c, i, v), nil
+		default:
+			return nil, fmt.Errorf("%w: parent of /%s isn't a container", ErrPatchFailed, token)
+		}
+	})
+}
+
+// remove deletes the value at tokens and returns the new document and the removed value.
+func remove(doc any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("%w: can't remove the whole document", ErrPatchFailed)
+	}
+	var removed any
+	doc, err := modify(doc, tokens, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q not found", ErrPatchFailed, token)
+			}
+			removed = v
+			delete(c, token)
+			return c, nil
+		case []any:
+			i, err := arrayIndex(token, len(c))
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return slices.Delete(c, i, i+1), nil
+		default:
+			return nil, fmt.Errorf("%w: parent of /%s isn't a container", ErrPatchFailed, token)
+		}
+	})
+	return doc, removed, err
+}
+
+// modify replaces the container holding the last token with the result of fn
+// and returns the document, which is a new value if fn replaced the root.
+func modify(node any, tokens []string, fn func(container any, token string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0])
+	}
+	next, err := pointerChild(node, tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	updated, err := modify(next, tokens[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch c := node.(type) {
+	case map[string]any:
+		c[tokens[0]] = updated
+	case []any:
+		i, _ := arrayIndex(tokens[0], len(c))
+		c[i] = updated
+	}
+	return node, nil
+}
+
+func pointerChild(node any, token string) (any, error) {
+	switch c := node.(type) {
+	case map[string]any:
+		v, ok := c[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q not found", ErrPatchFailed, token)
+		}
+		return v, nil
+	case []any:
+		i, err := arrayIndex(token, len(c))
+		if err != nil {
+			return nil, err
+		}
+		return c[i], nil
+	default:
+		return nil, fmt.Errorf("%w: /%s not found", ErrPatchFailed, token)
+	}
+}
+
+// arrayIndex parses an array index below n. Leading zeros aren't allowed.
+func arrayIndex(token string, n int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || token[0] < '0' || token[0] > '9' || (len(token) > 1 && token[0] == '0') || i >= n {
+		return 0, fmt.Errorf("%w: bad array index %q", ErrPatchFailed, token)
+	}
+	return i, nil
+}
+
+// equal compares JSON values. Numbers are equal if their values are.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fx, errX := x.Float64()
+		fy, errY := y.Float64()
+		if errX == nil && errY == nil {
+			return fx == fy
+		}
+		return x == y
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func deepCopy(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(x))
+		for k, e := range x {
+			c[k] = deepCopy(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(x))
+		for i, e := range x {
+			c[i] = deepCopy(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
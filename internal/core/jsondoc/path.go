@@ -0,0 +1,80 @@
+package jsondoc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segment is a step of a path, either a member name or an array index.
+type segment struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// parsePath splits a path like $.a['b c'][0] into segments. Quoted names can't contain their quote.
+func parsePath(path string) ([]segment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("%w: %q doesn't start with $", ErrInvalidPath, path)
+	}
+
+	var segs []segment
+	for i := 1; i < len(path); {
+		switch path[i] {
+		case '.':
+			j := i + 1
+			for j < len(path) && path[j] != '.' && path[j] != '[' {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("%w: empty member name at %d", ErrInvalidPath, i)
+			}
+			segs = append(segs, segment{name: path[i+1 : j]})
+			i = j
+		case '[':
+			rest := path[i+1:]
+			if rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+				end := strings.IndexByte(rest[1:], rest[0])
+				if end < 0 || !strings.HasPrefix(rest[end+2:], "]") {
+					return nil, fmt.Errorf("%w: unterminated name at %d", ErrInvalidPath, i)
+				}
+				segs = append(segs, segment{name: rest[1 : end+1]})
+				i += end + 4
+				continue
+			}
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated index at %d", ErrInvalidPath, i)
+			}
+			idx, err := strconv.Atoi(rest[:end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("%w: bad index %q", ErrInvalidPath, rest[:end])
+			}
+			segs = append(segs, segment{index: idx, isIndex: true})
+			i += end + 2
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at %d", ErrInvalidPath, path[i], i)
+		}
+	}
+	return segs, nil
+}
+
+func child(node any, seg segment) (any, error) {
+	if seg.isIndex {
+		arr, ok := node.([]any)
+		if !ok || seg.index >= len(arr) {
+			return nil, ErrPathNotFound
+		}
+		return arr[seg.index], nil
+	}
+	obj, ok := node.(map[string]any)
+	if !ok {
+		return nil, ErrPathNotFound
+	}
+	v, ok := obj[seg.name]
+	if !ok {
+		return nil, ErrPathNotFound
+	}
+	return v, nil
+}
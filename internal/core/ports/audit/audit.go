@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+type Op string
+
+const (
+	OpPut    Op = "put"
+	OpDelete Op = "delete"
+	// OpHSet sets fields of a hash, the value is the written field value.
+	OpHSet Op = "hset"
+	// OpHDel deletes fields of a hash.
+	OpHDel Op = "hdel"
+	// OpPush pushes a value to a list, the value is the pushed one.
+	OpPush Op = "push"
+	// OpPop pops values from a list.
+	OpPop Op = "pop"
+	// OpZAdd sets the score of a sorted set member, the value is the member.
+	OpZAdd Op = "zadd"
+	// OpZRem removes members of a sorted set.
+	OpZRem Op = "zrem"
+	// OpZPop pops members with the lowest scores from a sorted set.
+	OpZPop Op = "zpop"
+	// OpPatch patches a JSON document, the value is the patch.
+	OpPatch Op = "patch"
+	// OpLeaseAttach attaches a key to a lease.
+	OpLeaseAttach Op = "lease_attach"
+	// OpLock acquires a lock held by a lease.
+	OpLock Op = "lock"
+	// OpUnlock releases a lock.
+	OpUnlock Op = "unlock"
+	// OpExpire sets or removes the expiry of a key.
+	OpExpire Op = "expire"
+	// OpFlush expires or deletes all keys of a namespace.
+	OpFlush Op = "flush"
+	// OpNamespaceCreate creates a namespace.
+	OpNamespaceCreate Op = "namespace_create"
+	// OpNamespaceDelete deletes a namespace with all its keys.
+	OpNamespaceDelete Op = "namespace_delete"
+)
+
+// SystemCaller is the caller of mutations the cluster commits itself, e.g. deletes of expired keys.
+const SystemCaller = "system"
+
+// HasValue reports whether records of the op describe a written value.
+func (o Op) HasValue() bool {
+	return o == OpPut || o == OpHSet || o == OpPush || o == OpZAdd || o == OpPatch
+}
+
+// Record describes a single committed mutation.
+type Record struct {
+	Time      time.Time `json:"ts"`
+	Op        Op        `json:"op"`
+	Namespace string    `json:"namespace,omitempty"`
+	Key       string    `json:"key"`
+	ValueSize int       `json:"value_size"`
+	ValueHash string    `json:"value_sha256,omitempty"`
+	Caller    string    `json:"caller"`
+	IP        string    `json:"ip,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	LogIndex  int64     `json:"log_index"`
+}
+
+//go:generate mockery
+type Logger interface {
+	Record(rec *Record) error
+	Close() error
+}
+
+// HashValue returns hex encoded sha256 sum of the value
+func HashValue(value []byte) string {
+	sum := sha256.Sum256(value)
+	return hex.EncodeToString(sum[:])
+}
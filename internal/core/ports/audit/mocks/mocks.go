@@ -0,0 +1,132 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package auditmocks
+
+import (
+	"github.com/shrtyk/kv-store/internal/core/ports/audit"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockLogger creates a new instance of MockLogger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLogger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLogger {
+	mock := &MockLogger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLogger is an autogenerated mock type for the Logger type
+type MockLogger struct {
+	mock.Mock
+}
+
+type MockLogger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLogger) EXPECT() *MockLogger_Expecter {
+	return &MockLogger_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function for the type MockLogger
+func (_mock *MockLogger) Close() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLogger_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockLogger_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *MockLogger_Expecter) Close() *MockLogger_Close_Call {
+	return &MockLogger_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *MockLogger_Close_Call) Run(run func()) *MockLogger_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockLogger_Close_Call) Return(err error) *MockLogger_Close_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLogger_Close_Call) RunAndReturn(run func() error) *MockLogger_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function for the type MockLogger
+func (_mock *MockLogger) Record(rec *audit.Record) error {
+	ret := _mock.Called(rec)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*audit.Record) error); ok {
+		r0 = returnFunc(rec)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLogger_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockLogger_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - rec *audit.Record
+func (_e *MockLogger_Expecter) Record(rec interface{}) *MockLogger_Record_Call {
+	return &MockLogger_Record_Call{Call: _e.mock.On("Record", rec)}
+}
+
+func (_c *MockLogger_Record_Call) Run(run func(rec *audit.Record)) *MockLogger_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *audit.Record
+		if args[0] != nil {
+			arg0 = args[0].(*audit.Record)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Record_Call) Return(err error) *MockLogger_Record_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLogger_Record_Call) RunAndReturn(run func(rec *audit.Record) error) *MockLogger_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,38 @@
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+)
+
+// Event describes a single committed change delivered to sinks.
+// Index and Seq identify the event, so consumers can drop duplicates.
+type Event struct {
+	Index int64 `json:"index"`
+	// Seq orders events committed with a single log entry.
+	Seq int `json:"seq"`
+	// Time is the cluster time the change was applied at.
+	Time      time.Time `json:"ts"`
+	Namespace string    `json:"namespace,omitempty"`
+	// Op is the kind of the change, e.g. "put", "hset" or "lease_revoke".
+	Op string `json:"op"`
+	// Command holds arguments of the change.
+	Command json.RawMessage `json:"command"`
+	// DeletedKeys are keys deleted as a side effect of the change, e.g. keys of a revoked lease or expired keys.
+	DeletedKeys []string `json:"deleted_keys,omitempty"`
+	// Change is the applied change the event was built from. It isn't delivered as is.
+	Change fsm.Change `json:"-"`
+}
+
+//go:generate mockery
+type Sink interface {
+	// Name identifies the sink's delivery cursor, so it must not change between restarts
+	Name() string
+	// Deliver delivers events in log order. Events of a failed delivery are delivered again,
+	// so the same event can be delivered more than once
+	Deliver(ctx context.Context, events []Event) error
+	Close() error
+}
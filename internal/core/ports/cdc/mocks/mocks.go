@@ -0,0 +1,184 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package cdcmocks
+
+import (
+	"context"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockSink creates a new instance of MockSink. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSink(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSink {
+	mock := &MockSink{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSink is an autogenerated mock type for the Sink type
+type MockSink struct {
+	mock.Mock
+}
+
+type MockSink_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSink) EXPECT() *MockSink_Expecter {
+	return &MockSink_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function for the type MockSink
+func (_mock *MockSink) Close() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSink_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockSink_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *MockSink_Expecter) Close() *MockSink_Close_Call {
+	return &MockSink_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *MockSink_Close_Call) Run(run func()) *MockSink_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSink_Close_Call) Return(err error) *MockSink_Close_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSink_Close_Call) RunAndReturn(run func() error) *MockSink_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Deliver provides a mock function for the type MockSink
+func (_mock *MockSink) Deliver(ctx context.Context, events []cdc.Event) error {
+	ret := _mock.Called(ctx, events)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deliver")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []cdc.Event) error); ok {
+		r0 = returnFunc(ctx, events)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSink_Deliver_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Deliver'
+type MockSink_Deliver_Call struct {
+	*mock.Call
+}
+
+// Deliver is a helper method to define mock.On call
+//   - ctx context.Context
+//   - events []cdc.Event
+func (_e *MockSink_Expecter) Deliver(ctx interface{}, events interface{}) *MockSink_Deliver_Call {
+	return &MockSink_Deliver_Call{Call: _e.mock.On("Deliver", ctx, events)}
+}
+
+func (_c *MockSink_Deliver_Call) Run(run func(ctx context.Context, events []cdc.Event)) *MockSink_Deliver_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []cdc.Event
+		if args[1] != nil {
+			arg1 = args[1].([]cdc.Event)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSink_Deliver_Call) Return(err error) *MockSink_Deliver_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSink_Deliver_Call) RunAndReturn(run func(ctx context.Context, events []cdc.Event) error) *MockSink_Deliver_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Name provides a mock function for the type MockSink
+func (_mock *MockSink) Name() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockSink_Name_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Name'
+type MockSink_Name_Call struct {
+	*mock.Call
+}
+
+// Name is a helper method to define mock.On call
+func (_e *MockSink_Expecter) Name() *MockSink_Name_Call {
+	return &MockSink_Name_Call{Call: _e.mock.On("Name")}
+}
+
+func (_c *MockSink_Name_Call) Run(run func()) *MockSink_Name_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSink_Name_Call) Return(s string) *MockSink_Name_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockSink_Name_Call) RunAndReturn(run func() string) *MockSink_Name_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,30 @@
+package cluster
+
+import "errors"
+
+var (
+	ErrUnknownMember = errors.New("cluster: node id is not a configured raft peer")
+	// ErrNoSuchMember is returned by removal of a member which has no address in the replicated membership.
+	ErrNoSuchMember = errors.New("cluster: node has no member address")
+	// ErrVoterChange is returned for attempts to add or remove raft voters at runtime. The raft library
+	// has no configuration changes, so the voter set changes only by restarting nodes with new raft peers.
+	ErrVoterChange = errors.New("cluster: raft voters can't be added or removed at runtime, update raft peers and restart the nodes")
+)
+
+// Member is a raft node and its public http address used for redirects.
+type Member struct {
+	ID       int    `json:"id"`
+	HTTPAddr string `json:"http_addr"`
+}
+
+//go:generate mockery
+type Membership interface {
+	// HTTPAddr returns public http address of the node
+	HTTPAddr(id int) (string, bool)
+	// Members returns known members ordered by id
+	Members() []Member
+	// Voters returns size of the raft voter set
+	Voters() int
+	// Replace makes members authoritative. Called by the state machine when membership is committed
+	Replace(members []Member)
+}
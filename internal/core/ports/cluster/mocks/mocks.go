@@ -0,0 +1,227 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package clustermocks
+
+import (
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockMembership creates a new instance of MockMembership. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMembership(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMembership {
+	mock := &MockMembership{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMembership is an autogenerated mock type for the Membership type
+type MockMembership struct {
+	mock.Mock
+}
+
+type MockMembership_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMembership) EXPECT() *MockMembership_Expecter {
+	return &MockMembership_Expecter{mock: &_m.Mock}
+}
+
+// HTTPAddr provides a mock function for the type MockMembership
+func (_mock *MockMembership) HTTPAddr(id int) (string, bool) {
+	ret := _mock.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HTTPAddr")
+	}
+
+	var r0 string
+	var r1 bool
+	if returnFunc, ok := ret.Get(0).(func(int) (string, bool)); ok {
+		return returnFunc(id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int) string); ok {
+		r0 = returnFunc(id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int) bool); ok {
+		r1 = returnFunc(id)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	return r0, r1
+}
+
+// MockMembership_HTTPAddr_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HTTPAddr'
+type MockMembership_HTTPAddr_Call struct {
+	*mock.Call
+}
+
+// HTTPAddr is a helper method to define mock.On call
+//   - id int
+func (_e *MockMembership_Expecter) HTTPAddr(id interface{}) *MockMembership_HTTPAddr_Call {
+	return &MockMembership_HTTPAddr_Call{Call: _e.mock.On("HTTPAddr", id)}
+}
+
+func (_c *MockMembership_HTTPAddr_Call) Run(run func(id int)) *MockMembership_HTTPAddr_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int
+		if args[0] != nil {
+			arg0 = args[0].(int)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMembership_HTTPAddr_Call) Return(s string, b bool) *MockMembership_HTTPAddr_Call {
+	_c.Call.Return(s, b)
+	return _c
+}
+
+func (_c *MockMembership_HTTPAddr_Call) RunAndReturn(run func(id int) (string, bool)) *MockMembership_HTTPAddr_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Members provides a mock function for the type MockMembership
+func (_mock *MockMembership) Members() []cluster.Member {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Members")
+	}
+
+	var r0 []cluster.Member
+	if returnFunc, ok := ret.Get(0).(func() []cluster.Member); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cluster.Member)
+		}
+	}
+	return r0
+}
+
+// MockMembership_Members_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Members'
+type MockMembership_Members_Call struct {
+	*mock.Call
+}
+
+// Members is a helper method to define mock.On call
+func (_e *MockMembership_Expecter) Members() *MockMembership_Members_Call {
+	return &MockMembership_Members_Call{Call: _e.mock.On("Members")}
+}
+
+func (_c *MockMembership_Members_Call) Run(run func()) *MockMembership_Members_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMembership_Members_Call) Return(members []cluster.Member) *MockMembership_Members_Call {
+	_c.Call.Return(members)
+	return _c
+}
+
+func (_c *MockMembership_Members_Call) RunAndReturn(run func() []cluster.Member) *MockMembership_Members_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Replace provides a mock function for the type MockMembership
+func (_mock *MockMembership) Replace(members []cluster.Member) {
+	_mock.Called(members)
+	return
+}
+
+// MockMembership_Replace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replace'
+type MockMembership_Replace_Call struct {
+	*mock.Call
+}
+
+// Replace is a helper method to define mock.On call
+//   - members []cluster.Member
+func (_e *MockMembership_Expecter) Replace(members interface{}) *MockMembership_Replace_Call {
+	return &MockMembership_Replace_Call{Call: _e.mock.On("Replace", members)}
+}
+
+func (_c *MockMembership_Replace_Call) Run(run func(members []cluster.Member)) *MockMembership_Replace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []cluster.Member
+		if args[0] != nil {
+			arg0 = args[0].([]cluster.Member)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMembership_Replace_Call) Return() *MockMembership_Replace_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMembership_Replace_Call) RunAndReturn(run func(members []cluster.Member)) *MockMembership_Replace_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Voters provides a mock function for the type MockMembership
+func (_mock *MockMembership) Voters() int {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Voters")
+	}
+
+	var r0 int
+	if returnFunc, ok := ret.Get(0).(func() int); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	return r0
+}
+
+// MockMembership_Voters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Voters'
+type MockMembership_Voters_Call struct {
+	*mock.Call
+}
+
+// Voters is a helper method to define mock.On call
+func (_e *MockMembership_Expecter) Voters() *MockMembership_Voters_Call {
+	return &MockMembership_Voters_Call{Call: _e.mock.On("Voters")}
+}
+
+func (_c *MockMembership_Voters_Call) Run(run func()) *MockMembership_Voters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMembership_Voters_Call) Return(n int) *MockMembership_Voters_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockMembership_Voters_Call) RunAndReturn(run func() int) *MockMembership_Voters_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,17 @@
+package encryption
+
+import "errors"
+
+var (
+	ErrUnknownKey = errors.New("encryption: unknown key id")
+	ErrMalformed  = errors.New("encryption: malformed ciphertext")
+	ErrDisabled   = errors.New("encryption: data is encrypted but encryption is disabled")
+)
+
+//go:generate mockery
+type Cipher interface {
+	// Encrypt seals plaintext with the active key. Key ID is embedded in the result
+	Encrypt(plaintext []byte) ([]byte, error)
+	// Decrypt opens ciphertext with the key it was sealed with
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
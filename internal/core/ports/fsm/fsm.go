@@ -0,0 +1,134 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+var (
+	ErrStateUnavailable = errors.New("fsm: state is unavailable, node stopped applying commands")
+	// ErrLeaseNotFound is returned for a lease that was never granted, was revoked or has expired.
+	ErrLeaseNotFound = errors.New("fsm: lease not found")
+	// ErrLockHeld is returned when a lock is held by another lease.
+	ErrLockHeld = errors.New("fsm: lock is held by another lease")
+	// ErrNamespacedLease is returned for lease and lock commands outside of the default namespace.
+	ErrNamespacedLease = errors.New("fsm: leases and locks exist in the default namespace only")
+	// ErrChangesCompacted is returned for changes which are no longer kept, e.g. after the log index
+	// of a sink which wasn't registered when they were applied.
+	ErrChangesCompacted = errors.New("fsm: changes after the index are no longer kept")
+)
+
+// ReadQuery builds a query reading the key in the namespace, empty namespace is the default one.
+// Queries of the default namespace are the keys themselves unless a key starts with a zero byte.
+func ReadQuery(namespace, key string) []byte {
+	if namespace == "" && !strings.HasPrefix(key, "\x00") {
+		return []byte(key)
+	}
+	return []byte("\x00" + namespace + "\x00" + key)
+}
+
+// ParseReadQuery returns the namespace and the key of a query built by ReadQuery.
+func ParseReadQuery(query []byte) (namespace, key string) {
+	q := string(query)
+	if rest, ok := strings.CutPrefix(q, "\x00"); ok {
+		if namespace, key, ok = strings.Cut(rest, "\x00"); ok {
+			return namespace, key
+		}
+	}
+	return "", q
+}
+
+// RestoreStatus describes the last snapshot restore attempt.
+type RestoreStatus struct {
+	Time          time.Time `json:"time"`
+	SnapshotIndex int64     `json:"snapshot_index"`
+	SnapshotSize  int       `json:"snapshot_size"`
+	Error         string    `json:"error,omitempty"`
+}
+
+//go:generate mockery
+type StatusReporter interface {
+	// LastRestore returns status of the last snapshot restore or nil if nothing was restored yet
+	LastRestore() *RestoreStatus
+	// Restoring reports whether a snapshot is being installed right now
+	Restoring() bool
+	// Available reports whether the state machine applies commands and serves reads
+	Available() bool
+}
+
+//go:generate mockery
+type AppliedIndex interface {
+	// AppliedIndex returns log index of the last command applied to the store
+	AppliedIndex() int64
+	// WaitApplied blocks until the applied index is at least logIndex or ctx is done
+	WaitApplied(ctx context.Context, logIndex int64) error
+	// AppliedAt returns time the last command or snapshot was applied. Zero if nothing was applied yet
+	AppliedAt() time.Time
+}
+
+//go:generate mockery
+type PushWatcher interface {
+	// WatchPushes returns a channel closed once a command pushing values to the list at key is applied
+	// or a snapshot is installed. stop releases the watch and must be called when the caller stops waiting
+	WatchPushes(key string) (pushed <-chan struct{}, stop func())
+}
+
+// Lease is a replicated lease. Keys attached to it are deleted when it's revoked or expires.
+type Lease struct {
+	// ID is the log index of the command that granted the lease.
+	ID int64 `json:"id"`
+	// TTLMillis is the time in milliseconds every keepalive extends the lease by.
+	TTLMillis int64 `json:"ttl_ms"`
+	// ExpiresAt is the deadline of the lease assigned by the leader with the last keepalive.
+	ExpiresAt time.Time `json:"expires_at"`
+	Keys      []string  `json:"keys"`
+}
+
+//go:generate mockery
+type LeaseReader interface {
+	// Lease returns the lease with id unless it's unknown or expired by now in unix milliseconds
+	Lease(id, now int64) (Lease, bool)
+	// ExpiredLeases returns ids of up to limit leases expired by now in unix milliseconds
+	ExpiredLeases(now int64, limit int) []int64
+}
+
+//go:generate mockery
+type ClusterClock interface {
+	// ClusterTime returns the latest time the leader stamped into an applied command in unix milliseconds,
+	// 0 if none. It's the same on replicas which applied the same entries regardless of their clocks
+	ClusterTime() int64
+}
+
+// Change is a command which changed the state, applied at the log index. A command which failed
+// after changing some keys, e.g. an mset with a rejected key, is recorded with the applied part only.
+type Change struct {
+	Index int64
+	// Seq orders changes applied from a single log entry, e.g. a batch.
+	Seq int
+	// Time is the cluster time the change was applied at in unix milliseconds.
+	Time    int64
+	Command *fsm_v1.Command
+	// Result is the new value written by commands like incr, nil for other commands.
+	Result []byte
+	// Deleted are keys deleted as a side effect of the command, e.g. keys of a revoked lease or expired keys.
+	Deleted []string
+}
+
+//go:generate mockery
+type ChangeFeed interface {
+	// Changes returns changes applied after the log index, whole entries up to about limit changes, and a channel
+	// closed once a newer change is applied or a snapshot is installed. Changes are kept until every registered
+	// sink acknowledges them, ErrChangesCompacted is returned if changes right after the index are gone
+	Changes(after int64, limit int) (changes []Change, next <-chan struct{}, err error)
+	// Cursor returns the log index up to which changes were delivered to the sink.
+	// ok is false if the sink isn't registered, so changes aren't kept for it
+	Cursor(sink string) (index int64, ok bool)
+	// Sinks returns registered sinks ordered by name
+	Sinks() []string
+	// Backlog returns number of kept changes not yet delivered to every sink
+	Backlog() int
+}
@@ -0,0 +1,913 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package fsmmocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockStatusReporter creates a new instance of MockStatusReporter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStatusReporter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStatusReporter {
+	mock := &MockStatusReporter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockStatusReporter is an autogenerated mock type for the StatusReporter type
+type MockStatusReporter struct {
+	mock.Mock
+}
+
+type MockStatusReporter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStatusReporter) EXPECT() *MockStatusReporter_Expecter {
+	return &MockStatusReporter_Expecter{mock: &_m.Mock}
+}
+
+// Available provides a mock function for the type MockStatusReporter
+func (_mock *MockStatusReporter) Available() bool {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Available")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockStatusReporter_Available_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Available'
+type MockStatusReporter_Available_Call struct {
+	*mock.Call
+}
+
+// Available is a helper method to define mock.On call
+func (_e *MockStatusReporter_Expecter) Available() *MockStatusReporter_Available_Call {
+	return &MockStatusReporter_Available_Call{Call: _e.mock.On("Available")}
+}
+
+func (_c *MockStatusReporter_Available_Call) Run(run func()) *MockStatusReporter_Available_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStatusReporter_Available_Call) Return(b bool) *MockStatusReporter_Available_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockStatusReporter_Available_Call) RunAndReturn(run func() bool) *MockStatusReporter_Available_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LastRestore provides a mock function for the type MockStatusReporter
+func (_mock *MockStatusReporter) LastRestore() *fsm.RestoreStatus {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastRestore")
+	}
+
+	var r0 *fsm.RestoreStatus
+	if returnFunc, ok := ret.Get(0).(func() *fsm.RestoreStatus); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fsm.RestoreStatus)
+		}
+	}
+	return r0
+}
+
+// MockStatusReporter_LastRestore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LastRestore'
+type MockStatusReporter_LastRestore_Call struct {
+	*mock.Call
+}
+
+// LastRestore is a helper method to define mock.On call
+func (_e *MockStatusReporter_Expecter) LastRestore() *MockStatusReporter_LastRestore_Call {
+	return &MockStatusReporter_LastRestore_Call{Call: _e.mock.On("LastRestore")}
+}
+
+func (_c *MockStatusReporter_LastRestore_Call) Run(run func()) *MockStatusReporter_LastRestore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStatusReporter_LastRestore_Call) Return(restoreStatus *fsm.RestoreStatus) *MockStatusReporter_LastRestore_Call {
+	_c.Call.Return(restoreStatus)
+	return _c
+}
+
+func (_c *MockStatusReporter_LastRestore_Call) RunAndReturn(run func() *fsm.RestoreStatus) *MockStatusReporter_LastRestore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restoring provides a mock function for the type MockStatusReporter
+func (_mock *MockStatusReporter) Restoring() bool {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restoring")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockStatusReporter_Restoring_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restoring'
+type MockStatusReporter_Restoring_Call struct {
+	*mock.Call
+}
+
+// Restoring is a helper method to define mock.On call
+func (_e *MockStatusReporter_Expecter) Restoring() *MockStatusReporter_Restoring_Call {
+	return &MockStatusReporter_Restoring_Call{Call: _e.mock.On("Restoring")}
+}
+
+func (_c *MockStatusReporter_Restoring_Call) Run(run func()) *MockStatusReporter_Restoring_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStatusReporter_Restoring_Call) Return(b bool) *MockStatusReporter_Restoring_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockStatusReporter_Restoring_Call) RunAndReturn(run func() bool) *MockStatusReporter_Restoring_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAppliedIndex creates a new instance of MockAppliedIndex. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAppliedIndex(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAppliedIndex {
+	mock := &MockAppliedIndex{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAppliedIndex is an autogenerated mock type for the AppliedIndex type
+type MockAppliedIndex struct {
+	mock.Mock
+}
+
+type MockAppliedIndex_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAppliedIndex) EXPECT() *MockAppliedIndex_Expecter {
+	return &MockAppliedIndex_Expecter{mock: &_m.Mock}
+}
+
+// AppliedAt provides a mock function for the type MockAppliedIndex
+func (_mock *MockAppliedIndex) AppliedAt() time.Time {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppliedAt")
+	}
+
+	var r0 time.Time
+	if returnFunc, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+	return r0
+}
+
+// MockAppliedIndex_AppliedAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AppliedAt'
+type MockAppliedIndex_AppliedAt_Call struct {
+	*mock.Call
+}
+
+// AppliedAt is a helper method to define mock.On call
+func (_e *MockAppliedIndex_Expecter) AppliedAt() *MockAppliedIndex_AppliedAt_Call {
+	return &MockAppliedIndex_AppliedAt_Call{Call: _e.mock.On("AppliedAt")}
+}
+
+func (_c *MockAppliedIndex_AppliedAt_Call) Run(run func()) *MockAppliedIndex_AppliedAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppliedIndex_AppliedAt_Call) Return(time1 time.Time) *MockAppliedIndex_AppliedAt_Call {
+	_c.Call.Return(time1)
+	return _c
+}
+
+func (_c *MockAppliedIndex_AppliedAt_Call) RunAndReturn(run func() time.Time) *MockAppliedIndex_AppliedAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AppliedIndex provides a mock function for the type MockAppliedIndex
+func (_mock *MockAppliedIndex) AppliedIndex() int64 {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppliedIndex")
+	}
+
+	var r0 int64
+	if returnFunc, ok := ret.Get(0).(func() int64); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	return r0
+}
+
+// MockAppliedIndex_AppliedIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AppliedIndex'
+type MockAppliedIndex_AppliedIndex_Call struct {
+	*mock.Call
+}
+
+// AppliedIndex is a helper method to define mock.On call
+func (_e *MockAppliedIndex_Expecter) AppliedIndex() *MockAppliedIndex_AppliedIndex_Call {
+	return &MockAppliedIndex_AppliedIndex_Call{Call: _e.mock.On("AppliedIndex")}
+}
+
+func (_c *MockAppliedIndex_AppliedIndex_Call) Run(run func()) *MockAppliedIndex_AppliedIndex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppliedIndex_AppliedIndex_Call) Return(n int64) *MockAppliedIndex_AppliedIndex_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockAppliedIndex_AppliedIndex_Call) RunAndReturn(run func() int64) *MockAppliedIndex_AppliedIndex_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WaitApplied provides a mock function for the type MockAppliedIndex
+func (_mock *MockAppliedIndex) WaitApplied(ctx context.Context, logIndex int64) error {
+	ret := _mock.Called(ctx, logIndex)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitApplied")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = returnFunc(ctx, logIndex)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAppliedIndex_WaitApplied_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitApplied'
+type MockAppliedIndex_WaitApplied_Call struct {
+	*mock.Call
+}
+
+// WaitApplied is a helper method to define mock.On call
+//   - ctx context.Context
+//   - logIndex int64
+func (_e *MockAppliedIndex_Expecter) WaitApplied(ctx interface{}, logIndex interface{}) *MockAppliedIndex_WaitApplied_Call {
+	return &MockAppliedIndex_WaitApplied_Call{Call: _e.mock.On("WaitApplied", ctx, logIndex)}
+}
+
+func (_c *MockAppliedIndex_WaitApplied_Call) Run(run func(ctx context.Context, logIndex int64)) *MockAppliedIndex_WaitApplied_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAppliedIndex_WaitApplied_Call) Return(err error) *MockAppliedIndex_WaitApplied_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAppliedIndex_WaitApplied_Call) RunAndReturn(run func(ctx context.Context, logIndex int64) error) *MockAppliedIndex_WaitApplied_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPushWatcher creates a new instance of MockPushWatcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPushWatcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPushWatcher {
+	mock := &MockPushWatcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPushWatcher is an autogenerated mock type for the PushWatcher type
+type MockPushWatcher struct {
+	mock.Mock
+}
+
+type MockPushWatcher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPushWatcher) EXPECT() *MockPushWatcher_Expecter {
+	return &MockPushWatcher_Expecter{mock: &_m.Mock}
+}
+
+// WatchPushes provides a mock function for the type MockPushWatcher
+func (_mock *MockPushWatcher) WatchPushes(key string) (<-chan struct{}, func()) {
+	ret := _mock.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchPushes")
+	}
+
+	var r0 <-chan struct{}
+	var r1 func()
+	if returnFunc, ok := ret.Get(0).(func(string) (<-chan struct{}, func())); ok {
+		return returnFunc(key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) <-chan struct{}); ok {
+		r0 = returnFunc(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) func()); ok {
+		r1 = returnFunc(key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+	return r0, r1
+}
+
+// MockPushWatcher_WatchPushes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchPushes'
+type MockPushWatcher_WatchPushes_Call struct {
+	*mock.Call
+}
+
+// WatchPushes is a helper method to define mock.On call
+//   - key string
+func (_e *MockPushWatcher_Expecter) WatchPushes(key interface{}) *MockPushWatcher_WatchPushes_Call {
+	return &MockPushWatcher_WatchPushes_Call{Call: _e.mock.On("WatchPushes", key)}
+}
+
+func (_c *MockPushWatcher_WatchPushes_Call) Run(run func(key string)) *MockPushWatcher_WatchPushes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPushWatcher_WatchPushes_Call) Return(pushed <-chan struct{}, stop func()) *MockPushWatcher_WatchPushes_Call {
+	_c.Call.Return(pushed, stop)
+	return _c
+}
+
+func (_c *MockPushWatcher_WatchPushes_Call) RunAndReturn(run func(key string) (<-chan struct{}, func())) *MockPushWatcher_WatchPushes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLeaseReader creates a new instance of MockLeaseReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLeaseReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLeaseReader {
+	mock := &MockLeaseReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLeaseReader is an autogenerated mock type for the LeaseReader type
+type MockLeaseReader struct {
+	mock.Mock
+}
+
+type MockLeaseReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLeaseReader) EXPECT() *MockLeaseReader_Expecter {
+	return &MockLeaseReader_Expecter{mock: &_m.Mock}
+}
+
+// ExpiredLeases provides a mock function for the type MockLeaseReader
+func (_mock *MockLeaseReader) ExpiredLeases(now int64, limit int) []int64 {
+	ret := _mock.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpiredLeases")
+	}
+
+	var r0 []int64
+	if returnFunc, ok := ret.Get(0).(func(int64, int) []int64); ok {
+		r0 = returnFunc(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+	return r0
+}
+
+// MockLeaseReader_ExpiredLeases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpiredLeases'
+type MockLeaseReader_ExpiredLeases_Call struct {
+	*mock.Call
+}
+
+// ExpiredLeases is a helper method to define mock.On call
+//   - now int64
+//   - limit int
+func (_e *MockLeaseReader_Expecter) ExpiredLeases(now interface{}, limit interface{}) *MockLeaseReader_ExpiredLeases_Call {
+	return &MockLeaseReader_ExpiredLeases_Call{Call: _e.mock.On("ExpiredLeases", now, limit)}
+}
+
+func (_c *MockLeaseReader_ExpiredLeases_Call) Run(run func(now int64, limit int)) *MockLeaseReader_ExpiredLeases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLeaseReader_ExpiredLeases_Call) Return(int64s []int64) *MockLeaseReader_ExpiredLeases_Call {
+	_c.Call.Return(int64s)
+	return _c
+}
+
+func (_c *MockLeaseReader_ExpiredLeases_Call) RunAndReturn(run func(now int64, limit int) []int64) *MockLeaseReader_ExpiredLeases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Lease provides a mock function for the type MockLeaseReader
+func (_mock *MockLeaseReader) Lease(id int64, now int64) (fsm.Lease, bool) {
+	ret := _mock.Called(id, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lease")
+	}
+
+	var r0 fsm.Lease
+	var r1 bool
+	if returnFunc, ok := ret.Get(0).(func(int64, int64) (fsm.Lease, bool)); ok {
+		return returnFunc(id, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64, int64) fsm.Lease); ok {
+		r0 = returnFunc(id, now)
+	} else {
+		r0 = ret.Get(0).(fsm.Lease)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64, int64) bool); ok {
+		r1 = returnFunc(id, now)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	return r0, r1
+}
+
+// MockLeaseReader_Lease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lease'
+type MockLeaseReader_Lease_Call struct {
+	*mock.Call
+}
+
+// Lease is a helper method to define mock.On call
+//   - id int64
+//   - now int64
+func (_e *MockLeaseReader_Expecter) Lease(id interface{}, now interface{}) *MockLeaseReader_Lease_Call {
+	return &MockLeaseReader_Lease_Call{Call: _e.mock.On("Lease", id, now)}
+}
+
+func (_c *MockLeaseReader_Lease_Call) Run(run func(id int64, now int64)) *MockLeaseReader_Lease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLeaseReader_Lease_Call) Return(lease fsm.Lease, b bool) *MockLeaseReader_Lease_Call {
+	_c.Call.Return(lease, b)
+	return _c
+}
+
+func (_c *MockLeaseReader_Lease_Call) RunAndReturn(run func(id int64, now int64) (fsm.Lease, bool)) *MockLeaseReader_Lease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockClusterClock creates a new instance of MockClusterClock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockClusterClock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockClusterClock {
+	mock := &MockClusterClock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockClusterClock is an autogenerated mock type for the ClusterClock type
+type MockClusterClock struct {
+	mock.Mock
+}
+
+type MockClusterClock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockClusterClock) EXPECT() *MockClusterClock_Expecter {
+	return &MockClusterClock_Expecter{mock: &_m.Mock}
+}
+
+// ClusterTime provides a mock function for the type MockClusterClock
+func (_mock *MockClusterClock) ClusterTime() int64 {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterTime")
+	}
+
+	var r0 int64
+	if returnFunc, ok := ret.Get(0).(func() int64); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	return r0
+}
+
+// MockClusterClock_ClusterTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClusterTime'
+type MockClusterClock_ClusterTime_Call struct {
+	*mock.Call
+}
+
+// ClusterTime is a helper method to define mock.On call
+func (_e *MockClusterClock_Expecter) ClusterTime() *MockClusterClock_ClusterTime_Call {
+	return &MockClusterClock_ClusterTime_Call{Call: _e.mock.On("ClusterTime")}
+}
+
+func (_c *MockClusterClock_ClusterTime_Call) Run(run func()) *MockClusterClock_ClusterTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockClusterClock_ClusterTime_Call) Return(n int64) *MockClusterClock_ClusterTime_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockClusterClock_ClusterTime_Call) RunAndReturn(run func() int64) *MockClusterClock_ClusterTime_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockChangeFeed creates a new instance of MockChangeFeed. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockChangeFeed(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockChangeFeed {
+	mock := &MockChangeFeed{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockChangeFeed is an autogenerated mock type for the ChangeFeed type
+type MockChangeFeed struct {
+	mock.Mock
+}
+
+type MockChangeFeed_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockChangeFeed) EXPECT() *MockChangeFeed_Expecter {
+	return &MockChangeFeed_Expecter{mock: &_m.Mock}
+}
+
+// Backlog provides a mock function for the type MockChangeFeed
+func (_mock *MockChangeFeed) Backlog() int {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Backlog")
+	}
+
+	var r0 int
+	if returnFunc, ok := ret.Get(0).(func() int); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	return r0
+}
+
+// MockChangeFeed_Backlog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backlog'
+type MockChangeFeed_Backlog_Call struct {
+	*mock.Call
+}
+
+// Backlog is a helper method to define mock.On call
+func (_e *MockChangeFeed_Expecter) Backlog() *MockChangeFeed_Backlog_Call {
+	return &MockChangeFeed_Backlog_Call{Call: _e.mock.On("Backlog")}
+}
+
+func (_c *MockChangeFeed_Backlog_Call) Run(run func()) *MockChangeFeed_Backlog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockChangeFeed_Backlog_Call) Return(n int) *MockChangeFeed_Backlog_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockChangeFeed_Backlog_Call) RunAndReturn(run func() int) *MockChangeFeed_Backlog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Changes provides a mock function for the type MockChangeFeed
+func (_mock *MockChangeFeed) Changes(after int64, limit int) ([]fsm.Change, <-chan struct{}, error) {
+	ret := _mock.Called(after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Changes")
+	}
+
+	var r0 []fsm.Change
+	var r1 <-chan struct{}
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(int64, int) ([]fsm.Change, <-chan struct{}, error)); ok {
+		return returnFunc(after, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64, int) []fsm.Change); ok {
+		r0 = returnFunc(after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]fsm.Change)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64, int) <-chan struct{}); ok {
+		r1 = returnFunc(after, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan struct{})
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(int64, int) error); ok {
+		r2 = returnFunc(after, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockChangeFeed_Changes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Changes'
+type MockChangeFeed_Changes_Call struct {
+	*mock.Call
+}
+
+// Changes is a helper method to define mock.On call
+//   - after int64
+//   - limit int
+func (_e *MockChangeFeed_Expecter) Changes(after interface{}, limit interface{}) *MockChangeFeed_Changes_Call {
+	return &MockChangeFeed_Changes_Call{Call: _e.mock.On("Changes", after, limit)}
+}
+
+func (_c *MockChangeFeed_Changes_Call) Run(run func(after int64, limit int)) *MockChangeFeed_Changes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockChangeFeed_Changes_Call) Return(changes []fsm.Change, next <-chan struct{}, err error) *MockChangeFeed_Changes_Call {
+	_c.Call.Return(changes, next, err)
+	return _c
+}
+
+func (_c *MockChangeFeed_Changes_Call) RunAndReturn(run func(after int64, limit int) ([]fsm.Change, <-chan struct{}, error)) *MockChangeFeed_Changes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Cursor provides a mock function for the type MockChangeFeed
+func (_mock *MockChangeFeed) Cursor(sink string) (int64, bool) {
+	ret := _mock.Called(sink)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cursor")
+	}
+
+	var r0 int64
+	var r1 bool
+	if returnFunc, ok := ret.Get(0).(func(string) (int64, bool)); ok {
+		return returnFunc(sink)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = returnFunc(sink)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = returnFunc(sink)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	return r0, r1
+}
+
+// MockChangeFeed_Cursor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Cursor'
+type MockChangeFeed_Cursor_Call struct {
+	*mock.Call
+}
+
+// Cursor is a helper method to define mock.On call
+//   - sink string
+func (_e *MockChangeFeed_Expecter) Cursor(sink interface{}) *MockChangeFeed_Cursor_Call {
+	return &MockChangeFeed_Cursor_Call{Call: _e.mock.On("Cursor", sink)}
+}
+
+func (_c *MockChangeFeed_Cursor_Call) Run(run func(sink string)) *MockChangeFeed_Cursor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockChangeFeed_Cursor_Call) Return(index int64, ok bool) *MockChangeFeed_Cursor_Call {
+	_c.Call.Return(index, ok)
+	return _c
+}
+
+func (_c *MockChangeFeed_Cursor_Call) RunAndReturn(run func(sink string) (int64, bool)) *MockChangeFeed_Cursor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Sinks provides a mock function for the type MockChangeFeed
+func (_mock *MockChangeFeed) Sinks() []string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sinks")
+	}
+
+	var r0 []string
+	if returnFunc, ok := ret.Get(0).(func() []string); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	return r0
+}
+
+// MockChangeFeed_Sinks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Sinks'
+type MockChangeFeed_Sinks_Call struct {
+	*mock.Call
+}
+
+// Sinks is a helper method to define mock.On call
+func (_e *MockChangeFeed_Expecter) Sinks() *MockChangeFeed_Sinks_Call {
+	return &MockChangeFeed_Sinks_Call{Call: _e.mock.On("Sinks")}
+}
+
+func (_c *MockChangeFeed_Sinks_Call) Run(run func()) *MockChangeFeed_Sinks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockChangeFeed_Sinks_Call) Return(strings []string) *MockChangeFeed_Sinks_Call {
+	_c.Call.Return(strings)
+	return _c
+}
+
+func (_c *MockChangeFeed_Sinks_Call) RunAndReturn(run func() []string) *MockChangeFeed_Sinks_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -7,16 +7,35 @@ import (
 
 var (
 	ErrPromiseTimeout = errors.New("promise: timeout exceeded")
+	ErrCompacted      = errors.New("promise: command was compacted into a snapshot, result is unknown")
 )
 
+// Result is an outcome of applying a command by the state machine.
+// Err is set if the command was committed but rejected by the state machine.
+// Batch holds results of every command of a batch in the same order.
+type Result struct {
+	Data  []byte
+	Err   error
+	Batch []Result
+}
+
 //go:generate mockery
 type FuturesStore interface {
 	StartGC(ctx context.Context)
 	NewFuture(logIndex int64) Future
-	Fulfill(logIndex int64)
+	Fulfill(logIndex int64, res Result)
+	// FulfillUpTo fulfills every awaited future at or below logIndex which is not fulfilled yet
+	FulfillUpTo(logIndex int64, res Result)
+	// Pending returns number of awaited futures which are not fulfilled yet
+	Pending() int
 }
 
 //go:generate mockery
 type Future interface {
+	// Wait blocks until the command is applied and returns its Result.Err
 	Wait(ctx context.Context) error
+	// Data returns Result.Data of the applied command
+	Data() []byte
+	// Result returns the whole Result of the applied command
+	Result() Result
 }
@@ -39,8 +39,8 @@ func (_m *MockFuturesStore) EXPECT() *MockFuturesStore_Expecter {
 }
 
 // Fulfill provides a mock function for the type MockFuturesStore
-func (_mock *MockFuturesStore) Fulfill(logIndex int64) {
-	_mock.Called(logIndex)
+func (_mock *MockFuturesStore) Fulfill(logIndex int64, res futures.Result) {
+	_mock.Called(logIndex, res)
 	return
 }
 
@@ -51,18 +51,24 @@ type MockFuturesStore_Fulfill_Call struct {
 
 // Fulfill is a helper method to define mock.On call
 //   - logIndex int64
-func (_e *MockFuturesStore_Expecter) Fulfill(logIndex interface{}) *MockFuturesStore_Fulfill_Call {
-	return &MockFuturesStore_Fulfill_Call{Call: _e.mock.On("Fulfill", logIndex)}
+//   - res futures.Result
+func (_e *MockFuturesStore_Expecter) Fulfill(logIndex interface{}, res interface{}) *MockFuturesStore_Fulfill_Call {
+	return &MockFuturesStore_Fulfill_Call{Call: _e.mock.On("Fulfill", logIndex, res)}
 }
 
-func (_c *MockFuturesStore_Fulfill_Call) Run(run func(logIndex int64)) *MockFuturesStore_Fulfill_Call {
+func (_c *MockFuturesStore_Fulfill_Call) Run(run func(logIndex int64, res futures.Result)) *MockFuturesStore_Fulfill_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 int64
 		if args[0] != nil {
 			arg0 = args[0].(int64)
 		}
+		var arg1 futures.Result
+		if args[1] != nil {
+			arg1 = args[1].(futures.Result)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -73,7 +79,53 @@ func (_c *MockFuturesStore_Fulfill_Call) Return() *MockFuturesStore_Fulfill_Call
 	return _c
 }
 
-func (_c *MockFuturesStore_Fulfill_Call) RunAndReturn(run func(logIndex int64)) *MockFuturesStore_Fulfill_Call {
+func (_c *MockFuturesStore_Fulfill_Call) RunAndReturn(run func(logIndex int64, res futures.Result)) *MockFuturesStore_Fulfill_Call {
+	_c.Run(run)
+	return _c
+}
+
+// FulfillUpTo provides a mock function for the type MockFuturesStore
+func (_mock *MockFuturesStore) FulfillUpTo(logIndex int64, res futures.Result) {
+	_mock.Called(logIndex, res)
+	return
+}
+
+// MockFuturesStore_FulfillUpTo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FulfillUpTo'
+type MockFuturesStore_FulfillUpTo_Call struct {
+	*mock.Call
+}
+
+// FulfillUpTo is a helper method to define mock.On call
+//   - logIndex int64
+//   - res futures.Result
+func (_e *MockFuturesStore_Expecter) FulfillUpTo(logIndex interface{}, res interface{}) *MockFuturesStore_FulfillUpTo_Call {
+	return &MockFuturesStore_FulfillUpTo_Call{Call: _e.mock.On("FulfillUpTo", logIndex, res)}
+}
+
+func (_c *MockFuturesStore_FulfillUpTo_Call) Run(run func(logIndex int64, res futures.Result)) *MockFuturesStore_FulfillUpTo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 futures.Result
+		if args[1] != nil {
+			arg1 = args[1].(futures.Result)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFuturesStore_FulfillUpTo_Call) Return() *MockFuturesStore_FulfillUpTo_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockFuturesStore_FulfillUpTo_Call) RunAndReturn(run func(logIndex int64, res futures.Result)) *MockFuturesStore_FulfillUpTo_Call {
 	_c.Run(run)
 	return _c
 }
@@ -131,6 +183,50 @@ func (_c *MockFuturesStore_NewFuture_Call) RunAndReturn(run func(logIndex int64)
 	return _c
 }
 
+// Pending provides a mock function for the type MockFuturesStore
+func (_mock *MockFuturesStore) Pending() int {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pending")
+	}
+
+	var r0 int
+	if returnFunc, ok := ret.Get(0).(func() int); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	return r0
+}
+
+// MockFuturesStore_Pending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pending'
+type MockFuturesStore_Pending_Call struct {
+	*mock.Call
+}
+
+// Pending is a helper method to define mock.On call
+func (_e *MockFuturesStore_Expecter) Pending() *MockFuturesStore_Pending_Call {
+	return &MockFuturesStore_Pending_Call{Call: _e.mock.On("Pending")}
+}
+
+func (_c *MockFuturesStore_Pending_Call) Run(run func()) *MockFuturesStore_Pending_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockFuturesStore_Pending_Call) Return(n int) *MockFuturesStore_Pending_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockFuturesStore_Pending_Call) RunAndReturn(run func() int) *MockFuturesStore_Pending_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartGC provides a mock function for the type MockFuturesStore
 func (_mock *MockFuturesStore) StartGC(ctx context.Context) {
 	_mock.Called(ctx)
@@ -198,6 +294,96 @@ func (_m *MockFuture) EXPECT() *MockFuture_Expecter {
 	return &MockFuture_Expecter{mock: &_m.Mock}
 }
 
+// Data provides a mock function for the type MockFuture
+func (_mock *MockFuture) Data() []byte {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Data")
+	}
+
+	var r0 []byte
+	if returnFunc, ok := ret.Get(0).(func() []byte); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	return r0
+}
+
+// MockFuture_Data_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Data'
+type MockFuture_Data_Call struct {
+	*mock.Call
+}
+
+// Data is a helper method to define mock.On call
+func (_e *MockFuture_Expecter) Data() *MockFuture_Data_Call {
+	return &MockFuture_Data_Call{Call: _e.mock.On("Data")}
+}
+
+func (_c *MockFuture_Data_Call) Run(run func()) *MockFuture_Data_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockFuture_Data_Call) Return(bytes []byte) *MockFuture_Data_Call {
+	_c.Call.Return(bytes)
+	return _c
+}
+
+func (_c *MockFuture_Data_Call) RunAndReturn(run func() []byte) *MockFuture_Data_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Result provides a mock function for the type MockFuture
+func (_mock *MockFuture) Result() futures.Result {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Result")
+	}
+
+	var r0 futures.Result
+	if returnFunc, ok := ret.Get(0).(func() futures.Result); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(futures.Result)
+	}
+	return r0
+}
+
+// MockFuture_Result_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Result'
+type MockFuture_Result_Call struct {
+	*mock.Call
+}
+
+// Result is a helper method to define mock.On call
+func (_e *MockFuture_Expecter) Result() *MockFuture_Result_Call {
+	return &MockFuture_Result_Call{Call: _e.mock.On("Result")}
+}
+
+func (_c *MockFuture_Result_Call) Run(run func()) *MockFuture_Result_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockFuture_Result_Call) Return(result futures.Result) *MockFuture_Result_Call {
+	_c.Call.Return(result)
+	return _c
+}
+
+func (_c *MockFuture_Result_Call) RunAndReturn(run func() futures.Result) *MockFuture_Result_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Wait provides a mock function for the type MockFuture
 func (_mock *MockFuture) Wait(ctx context.Context) error {
 	ret := _mock.Called(ctx)
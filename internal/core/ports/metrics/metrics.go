@@ -4,8 +4,10 @@ import "google.golang.org/grpc/codes"
 
 //go:generate mockery
 type Metrics interface {
-	HttpRequest(code int, method, path string, latency float64)
-	GrpcRequest(code codes.Code, service, method string, latency float64)
+	// HttpRequest observes a request to the namespace, empty namespace is the default one
+	HttpRequest(code int, method, path, namespace string, latency float64)
+	// GrpcRequest observes a request to the namespace, empty namespace is the default one
+	GrpcRequest(code codes.Code, service, method, namespace string, latency float64)
 
 	HttpPut(key string, duration float64)
 	HttpDelete(key string, duration float64)
@@ -14,4 +16,7 @@ type Metrics interface {
 	GrpcPut(key string, duration float64)
 	GrpcDelete(key string, duration float64)
 	GrpcGet(key string, duration float64)
+
+	// ProposalBatch observes number of commands committed as a single raft entry
+	ProposalBatch(size int)
 }
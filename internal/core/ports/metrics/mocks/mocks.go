@@ -175,8 +175,8 @@ func (_c *MockMetrics_GrpcPut_Call) RunAndReturn(run func(key string, duration f
 }
 
 // GrpcRequest provides a mock function for the type MockMetrics
-func (_mock *MockMetrics) GrpcRequest(code codes.Code, service string, method string, latency float64) {
-	_mock.Called(code, service, method, latency)
+func (_mock *MockMetrics) GrpcRequest(code codes.Code, service string, method string, namespace string, latency float64) {
+	_mock.Called(code, service, method, namespace, latency)
 	return
 }
 
@@ -189,12 +189,13 @@ type MockMetrics_GrpcRequest_Call struct {
 //   - code codes.Code
 //   - service string
 //   - method string
+//   - namespace string
 //   - latency float64
-func (_e *MockMetrics_Expecter) GrpcRequest(code interface{}, service interface{}, method interface{}, latency interface{}) *MockMetrics_GrpcRequest_Call {
-	return &MockMetrics_GrpcRequest_Call{Call: _e.mock.On("GrpcRequest", code, service, method, latency)}
+func (_e *MockMetrics_Expecter) GrpcRequest(code interface{}, service interface{}, method interface{}, namespace interface{}, latency interface{}) *MockMetrics_GrpcRequest_Call {
+	return &MockMetrics_GrpcRequest_Call{Call: _e.mock.On("GrpcRequest", code, service, method, namespace, latency)}
 }
 
-func (_c *MockMetrics_GrpcRequest_Call) Run(run func(code codes.Code, service string, method string, latency float64)) *MockMetrics_GrpcRequest_Call {
+func (_c *MockMetrics_GrpcRequest_Call) Run(run func(code codes.Code, service string, method string, namespace string, latency float64)) *MockMetrics_GrpcRequest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 codes.Code
 		if args[0] != nil {
@@ -208,15 +209,20 @@ func (_c *MockMetrics_GrpcRequest_Call) Run(run func(code codes.Code, service st
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 float64
+		var arg3 string
 		if args[3] != nil {
-			arg3 = args[3].(float64)
+			arg3 = args[3].(string)
+		}
+		var arg4 float64
+		if args[4] != nil {
+			arg4 = args[4].(float64)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
+			arg4,
 		)
 	})
 	return _c
@@ -227,7 +233,7 @@ func (_c *MockMetrics_GrpcRequest_Call) Return() *MockMetrics_GrpcRequest_Call {
 	return _c
 }
 
-func (_c *MockMetrics_GrpcRequest_Call) RunAndReturn(run func(code codes.Code, service string, method string, latency float64)) *MockMetrics_GrpcRequest_Call {
+func (_c *MockMetrics_GrpcRequest_Call) RunAndReturn(run func(code codes.Code, service string, method string, namespace string, latency float64)) *MockMetrics_GrpcRequest_Call {
 	_c.Run(run)
 	return _c
 }
@@ -371,8 +377,8 @@ func (_c *MockMetrics_HttpPut_Call) RunAndReturn(run func(key string, duration f
 }
 
 // HttpRequest provides a mock function for the type MockMetrics
-func (_mock *MockMetrics) HttpRequest(code int, method string, path string, latency float64) {
-	_mock.Called(code, method, path, latency)
+func (_mock *MockMetrics) HttpRequest(code int, method string, path string, namespace string, latency float64) {
+	_mock.Called(code, method, path, namespace, latency)
 	return
 }
 
@@ -385,12 +391,13 @@ type MockMetrics_HttpRequest_Call struct {
 //   - code int
 //   - method string
 //   - path string
+//   - namespace string
 //   - latency float64
-func (_e *MockMetrics_Expecter) HttpRequest(code interface{}, method interface{}, path interface{}, latency interface{}) *MockMetrics_HttpRequest_Call {
-	return &MockMetrics_HttpRequest_Call{Call: _e.mock.On("HttpRequest", code, method, path, latency)}
+func (_e *MockMetrics_Expecter) HttpRequest(code interface{}, method interface{}, path interface{}, namespace interface{}, latency interface{}) *MockMetrics_HttpRequest_Call {
+	return &MockMetrics_HttpRequest_Call{Call: _e.mock.On("HttpRequest", code, method, path, namespace, latency)}
 }
 
-func (_c *MockMetrics_HttpRequest_Call) Run(run func(code int, method string, path string, latency float64)) *MockMetrics_HttpRequest_Call {
+func (_c *MockMetrics_HttpRequest_Call) Run(run func(code int, method string, path string, namespace string, latency float64)) *MockMetrics_HttpRequest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 int
 		if args[0] != nil {
@@ -404,15 +411,20 @@ func (_c *MockMetrics_HttpRequest_Call) Run(run func(code int, method string, pa
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 float64
+		var arg3 string
 		if args[3] != nil {
-			arg3 = args[3].(float64)
+			arg3 = args[3].(string)
+		}
+		var arg4 float64
+		if args[4] != nil {
+			arg4 = args[4].(float64)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
+			arg4,
 		)
 	})
 	return _c
@@ -423,7 +435,47 @@ func (_c *MockMetrics_HttpRequest_Call) Return() *MockMetrics_HttpRequest_Call {
 	return _c
 }
 
-func (_c *MockMetrics_HttpRequest_Call) RunAndReturn(run func(code int, method string, path string, latency float64)) *MockMetrics_HttpRequest_Call {
+func (_c *MockMetrics_HttpRequest_Call) RunAndReturn(run func(code int, method string, path string, namespace string, latency float64)) *MockMetrics_HttpRequest_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ProposalBatch provides a mock function for the type MockMetrics
+func (_mock *MockMetrics) ProposalBatch(size int) {
+	_mock.Called(size)
+	return
+}
+
+// MockMetrics_ProposalBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProposalBatch'
+type MockMetrics_ProposalBatch_Call struct {
+	*mock.Call
+}
+
+// ProposalBatch is a helper method to define mock.On call
+//   - size int
+func (_e *MockMetrics_Expecter) ProposalBatch(size interface{}) *MockMetrics_ProposalBatch_Call {
+	return &MockMetrics_ProposalBatch_Call{Call: _e.mock.On("ProposalBatch", size)}
+}
+
+func (_c *MockMetrics_ProposalBatch_Call) Run(run func(size int)) *MockMetrics_ProposalBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int
+		if args[0] != nil {
+			arg0 = args[0].(int)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ProposalBatch_Call) Return() *MockMetrics_ProposalBatch_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ProposalBatch_Call) RunAndReturn(run func(size int)) *MockMetrics_ProposalBatch_Call {
 	_c.Run(run)
 	return _c
 }
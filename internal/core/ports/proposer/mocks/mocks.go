@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package proposermocks
+
+import (
+	"context"
+
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockProposer creates a new instance of MockProposer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockProposer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockProposer {
+	mock := &MockProposer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockProposer is an autogenerated mock type for the Proposer type
+type MockProposer struct {
+	mock.Mock
+}
+
+type MockProposer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockProposer) EXPECT() *MockProposer_Expecter {
+	return &MockProposer_Expecter{mock: &_m.Mock}
+}
+
+// Propose provides a mock function for the type MockProposer
+func (_mock *MockProposer) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Propose")
+	}
+
+	var r0 *proposer.Proposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) (*proposer.Proposal, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte) *proposer.Proposal); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*proposer.Proposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockProposer_Propose_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Propose'
+type MockProposer_Propose_Call struct {
+	*mock.Call
+}
+
+// Propose is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd []byte
+func (_e *MockProposer_Expecter) Propose(ctx interface{}, cmd interface{}) *MockProposer_Propose_Call {
+	return &MockProposer_Propose_Call{Call: _e.mock.On("Propose", ctx, cmd)}
+}
+
+func (_c *MockProposer_Propose_Call) Run(run func(ctx context.Context, cmd []byte)) *MockProposer_Propose_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []byte
+		if args[1] != nil {
+			arg1 = args[1].([]byte)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockProposer_Propose_Call) Return(proposal *proposer.Proposal, err error) *MockProposer_Propose_Call {
+	_c.Call.Return(proposal, err)
+	return _c
+}
+
+func (_c *MockProposer_Propose_Call) RunAndReturn(run func(ctx context.Context, cmd []byte) (*proposer.Proposal, error)) *MockProposer_Propose_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,22 @@
+package proposer
+
+import (
+	"context"
+
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+)
+
+// Proposal is an outcome of proposing a command to the raft log.
+type Proposal struct {
+	IsLeader bool
+	LeaderID int
+	LogIndex int64
+	// Future is fulfilled once the command is applied. Nil if the node is not a leader.
+	Future ftr.Future
+}
+
+//go:generate mockery
+type Proposer interface {
+	// Propose submits marshaled fsm command to the raft log.
+	Propose(ctx context.Context, cmd []byte) (*Proposal, error)
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -89,228 +90,2262 @@ func (_c *MockStore_Delete_Call) RunAndReturn(run func(key string) error) *MockS
 	return _c
 }
 
+// DeleteExpired provides a mock function for the type MockStore
+func (_mock *MockStore) DeleteExpired(keys []string, now int64) []string {
+	ret := _mock.Called(keys, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 []string
+	if returnFunc, ok := ret.Get(0).(func([]string, int64) []string); ok {
+		r0 = returnFunc(keys, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	return r0
+}
+
+// MockStore_DeleteExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpired'
+type MockStore_DeleteExpired_Call struct {
+	*mock.Call
+}
+
+// DeleteExpired is a helper method to define mock.On call
+//   - keys []string
+//   - now int64
+func (_e *MockStore_Expecter) DeleteExpired(keys interface{}, now interface{}) *MockStore_DeleteExpired_Call {
+	return &MockStore_DeleteExpired_Call{Call: _e.mock.On("DeleteExpired", keys, now)}
+}
+
+func (_c *MockStore_DeleteExpired_Call) Run(run func(keys []string, now int64)) *MockStore_DeleteExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []string
+		if args[0] != nil {
+			arg0 = args[0].([]string)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_DeleteExpired_Call) Return(strings []string) *MockStore_DeleteExpired_Call {
+	_c.Call.Return(strings)
+	return _c
+}
+
+func (_c *MockStore_DeleteExpired_Call) RunAndReturn(run func(keys []string, now int64) []string) *MockStore_DeleteExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Expire provides a mock function for the type MockStore
+func (_mock *MockStore) Expire(key string, expiresAt int64, now int64) error {
+	ret := _mock.Called(key, expiresAt, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Expire")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, int64, int64) error); ok {
+		r0 = returnFunc(key, expiresAt, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStore_Expire_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Expire'
+type MockStore_Expire_Call struct {
+	*mock.Call
+}
+
+// Expire is a helper method to define mock.On call
+//   - key string
+//   - expiresAt int64
+//   - now int64
+func (_e *MockStore_Expecter) Expire(key interface{}, expiresAt interface{}, now interface{}) *MockStore_Expire_Call {
+	return &MockStore_Expire_Call{Call: _e.mock.On("Expire", key, expiresAt, now)}
+}
+
+func (_c *MockStore_Expire_Call) Run(run func(key string, expiresAt int64, now int64)) *MockStore_Expire_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_Expire_Call) Return(err error) *MockStore_Expire_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStore_Expire_Call) RunAndReturn(run func(key string, expiresAt int64, now int64) error) *MockStore_Expire_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExpiredKeys provides a mock function for the type MockStore
+func (_mock *MockStore) ExpiredKeys(now int64, limit int) []string {
+	ret := _mock.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpiredKeys")
+	}
+
+	var r0 []string
+	if returnFunc, ok := ret.Get(0).(func(int64, int) []string); ok {
+		r0 = returnFunc(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	return r0
+}
+
+// MockStore_ExpiredKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpiredKeys'
+type MockStore_ExpiredKeys_Call struct {
+	*mock.Call
+}
+
+// ExpiredKeys is a helper method to define mock.On call
+//   - now int64
+//   - limit int
+func (_e *MockStore_Expecter) ExpiredKeys(now interface{}, limit interface{}) *MockStore_ExpiredKeys_Call {
+	return &MockStore_ExpiredKeys_Call{Call: _e.mock.On("ExpiredKeys", now, limit)}
+}
+
+func (_c *MockStore_ExpiredKeys_Call) Run(run func(now int64, limit int)) *MockStore_ExpiredKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ExpiredKeys_Call) Return(strings []string) *MockStore_ExpiredKeys_Call {
+	_c.Call.Return(strings)
+	return _c
+}
+
+func (_c *MockStore_ExpiredKeys_Call) RunAndReturn(run func(now int64, limit int) []string) *MockStore_ExpiredKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FlushAll provides a mock function for the type MockStore
+func (_mock *MockStore) FlushAll(expiresAt int64, now int64) {
+	_mock.Called(expiresAt, now)
+	return
+}
+
+// MockStore_FlushAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FlushAll'
+type MockStore_FlushAll_Call struct {
+	*mock.Call
+}
+
+// FlushAll is a helper method to define mock.On call
+//   - expiresAt int64
+//   - now int64
+func (_e *MockStore_Expecter) FlushAll(expiresAt interface{}, now interface{}) *MockStore_FlushAll_Call {
+	return &MockStore_FlushAll_Call{Call: _e.mock.On("FlushAll", expiresAt, now)}
+}
+
+func (_c *MockStore_FlushAll_Call) Run(run func(expiresAt int64, now int64)) *MockStore_FlushAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_FlushAll_Call) Return() *MockStore_FlushAll_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockStore_FlushAll_Call) RunAndReturn(run func(expiresAt int64, now int64)) *MockStore_FlushAll_Call {
+	_c.Run(run)
+	return _c
+}
+
 // Get provides a mock function for the type MockStore
 func (_mock *MockStore) Get(key string) (string, error) {
 	ret := _mock.Called(key)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
+		panic("no return value specified for Get")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return returnFunc(key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
+		r0 = returnFunc(key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockStore_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - key string
+func (_e *MockStore_Expecter) Get(key interface{}) *MockStore_Get_Call {
+	return &MockStore_Get_Call{Call: _e.mock.On("Get", key)}
+}
+
+func (_c *MockStore_Get_Call) Run(run func(key string)) *MockStore_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_Get_Call) Return(s string, err error) *MockStore_Get_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockStore_Get_Call) RunAndReturn(run func(key string) (string, error)) *MockStore_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HDel provides a mock function for the type MockStore
+func (_mock *MockStore) HDel(key string, fields []string, now int64) (int, error) {
+	ret := _mock.Called(key, fields, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HDel")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, []string, int64) (int, error)); ok {
+		return returnFunc(key, fields, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, []string, int64) int); ok {
+		r0 = returnFunc(key, fields, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, []string, int64) error); ok {
+		r1 = returnFunc(key, fields, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_HDel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HDel'
+type MockStore_HDel_Call struct {
+	*mock.Call
+}
+
+// HDel is a helper method to define mock.On call
+//   - key string
+//   - fields []string
+//   - now int64
+func (_e *MockStore_Expecter) HDel(key interface{}, fields interface{}, now interface{}) *MockStore_HDel_Call {
+	return &MockStore_HDel_Call{Call: _e.mock.On("HDel", key, fields, now)}
+}
+
+func (_c *MockStore_HDel_Call) Run(run func(key string, fields []string, now int64)) *MockStore_HDel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_HDel_Call) Return(n int, err error) *MockStore_HDel_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStore_HDel_Call) RunAndReturn(run func(key string, fields []string, now int64) (int, error)) *MockStore_HDel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HGet provides a mock function for the type MockStore
+func (_mock *MockStore) HGet(key string, field string) (string, error) {
+	ret := _mock.Called(key, field)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HGet")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return returnFunc(key, field)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = returnFunc(key, field)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(key, field)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_HGet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HGet'
+type MockStore_HGet_Call struct {
+	*mock.Call
+}
+
+// HGet is a helper method to define mock.On call
+//   - key string
+//   - field string
+func (_e *MockStore_Expecter) HGet(key interface{}, field interface{}) *MockStore_HGet_Call {
+	return &MockStore_HGet_Call{Call: _e.mock.On("HGet", key, field)}
+}
+
+func (_c *MockStore_HGet_Call) Run(run func(key string, field string)) *MockStore_HGet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_HGet_Call) Return(s string, err error) *MockStore_HGet_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockStore_HGet_Call) RunAndReturn(run func(key string, field string) (string, error)) *MockStore_HGet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HGetAll provides a mock function for the type MockStore
+func (_mock *MockStore) HGetAll(key string) (map[string]string, error) {
+	ret := _mock.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HGetAll")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (map[string]string, error)); ok {
+		return returnFunc(key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) map[string]string); ok {
+		r0 = returnFunc(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_HGetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HGetAll'
+type MockStore_HGetAll_Call struct {
+	*mock.Call
+}
+
+// HGetAll is a helper method to define mock.On call
+//   - key string
+func (_e *MockStore_Expecter) HGetAll(key interface{}) *MockStore_HGetAll_Call {
+	return &MockStore_HGetAll_Call{Call: _e.mock.On("HGetAll", key)}
+}
+
+func (_c *MockStore_HGetAll_Call) Run(run func(key string)) *MockStore_HGetAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_HGetAll_Call) Return(stringToString map[string]string, err error) *MockStore_HGetAll_Call {
+	_c.Call.Return(stringToString, err)
+	return _c
+}
+
+func (_c *MockStore_HGetAll_Call) RunAndReturn(run func(key string) (map[string]string, error)) *MockStore_HGetAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HIncrBy provides a mock function for the type MockStore
+func (_mock *MockStore) HIncrBy(key string, field string, delta int64, now int64) (int64, error) {
+	ret := _mock.Called(key, field, delta, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HIncrBy")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, int64, int64) (int64, error)); ok {
+		return returnFunc(key, field, delta, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, int64, int64) int64); ok {
+		r0 = returnFunc(key, field, delta, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, int64, int64) error); ok {
+		r1 = returnFunc(key, field, delta, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_HIncrBy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HIncrBy'
+type MockStore_HIncrBy_Call struct {
+	*mock.Call
+}
+
+// HIncrBy is a helper method to define mock.On call
+//   - key string
+//   - field string
+//   - delta int64
+//   - now int64
+func (_e *MockStore_Expecter) HIncrBy(key interface{}, field interface{}, delta interface{}, now interface{}) *MockStore_HIncrBy_Call {
+	return &MockStore_HIncrBy_Call{Call: _e.mock.On("HIncrBy", key, field, delta, now)}
+}
+
+func (_c *MockStore_HIncrBy_Call) Run(run func(key string, field string, delta int64, now int64)) *MockStore_HIncrBy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_HIncrBy_Call) Return(n int64, err error) *MockStore_HIncrBy_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStore_HIncrBy_Call) RunAndReturn(run func(key string, field string, delta int64, now int64) (int64, error)) *MockStore_HIncrBy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HSet provides a mock function for the type MockStore
+func (_mock *MockStore) HSet(key string, fields map[string]string, now int64) (int, error) {
+	ret := _mock.Called(key, fields, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HSet")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, map[string]string, int64) (int, error)); ok {
+		return returnFunc(key, fields, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, map[string]string, int64) int); ok {
+		r0 = returnFunc(key, fields, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, map[string]string, int64) error); ok {
+		r1 = returnFunc(key, fields, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_HSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HSet'
+type MockStore_HSet_Call struct {
+	*mock.Call
+}
+
+// HSet is a helper method to define mock.On call
+//   - key string
+//   - fields map[string]string
+//   - now int64
+func (_e *MockStore_Expecter) HSet(key interface{}, fields interface{}, now interface{}) *MockStore_HSet_Call {
+	return &MockStore_HSet_Call{Call: _e.mock.On("HSet", key, fields, now)}
+}
+
+func (_c *MockStore_HSet_Call) Run(run func(key string, fields map[string]string, now int64)) *MockStore_HSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 map[string]string
+		if args[1] != nil {
+			arg1 = args[1].(map[string]string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_HSet_Call) Return(n int, err error) *MockStore_HSet_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStore_HSet_Call) RunAndReturn(run func(key string, fields map[string]string, now int64) (int, error)) *MockStore_HSet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Items provides a mock function for the type MockStore
+func (_mock *MockStore) Items() map[string]string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Items")
+	}
+
+	var r0 map[string]string
+	if returnFunc, ok := ret.Get(0).(func() map[string]string); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+	return r0
+}
+
+// MockStore_Items_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Items'
+type MockStore_Items_Call struct {
+	*mock.Call
+}
+
+// Items is a helper method to define mock.On call
+func (_e *MockStore_Expecter) Items() *MockStore_Items_Call {
+	return &MockStore_Items_Call{Call: _e.mock.On("Items")}
+}
+
+func (_c *MockStore_Items_Call) Run(run func()) *MockStore_Items_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStore_Items_Call) Return(stringToString map[string]string) *MockStore_Items_Call {
+	_c.Call.Return(stringToString)
+	return _c
+}
+
+func (_c *MockStore_Items_Call) RunAndReturn(run func() map[string]string) *MockStore_Items_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LLen provides a mock function for the type MockStore
+func (_mock *MockStore) LLen(key string) (int, error) {
+	ret := _mock.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LLen")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return returnFunc(key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) int); ok {
+		r0 = returnFunc(key)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_LLen_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LLen'
+type MockStore_LLen_Call struct {
+	*mock.Call
+}
+
+// LLen is a helper method to define mock.On call
+//   - key string
+func (_e *MockStore_Expecter) LLen(key interface{}) *MockStore_LLen_Call {
+	return &MockStore_LLen_Call{Call: _e.mock.On("LLen", key)}
+}
+
+func (_c *MockStore_LLen_Call) Run(run func(key string)) *MockStore_LLen_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_LLen_Call) Return(n int, err error) *MockStore_LLen_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStore_LLen_Call) RunAndReturn(run func(key string) (int, error)) *MockStore_LLen_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LRange provides a mock function for the type MockStore
+func (_mock *MockStore) LRange(key string, start int, stop int) ([]string, error) {
+	ret := _mock.Called(key, start, stop)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LRange")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) ([]string, error)); ok {
+		return returnFunc(key, start, stop)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) []string); ok {
+		r0 = returnFunc(key, start, stop)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = returnFunc(key, start, stop)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_LRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LRange'
+type MockStore_LRange_Call struct {
+	*mock.Call
+}
+
+// LRange is a helper method to define mock.On call
+//   - key string
+//   - start int
+//   - stop int
+func (_e *MockStore_Expecter) LRange(key interface{}, start interface{}, stop interface{}) *MockStore_LRange_Call {
+	return &MockStore_LRange_Call{Call: _e.mock.On("LRange", key, start, stop)}
+}
+
+func (_c *MockStore_LRange_Call) Run(run func(key string, start int, stop int)) *MockStore_LRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_LRange_Call) Return(strings []string, err error) *MockStore_LRange_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockStore_LRange_Call) RunAndReturn(run func(key string, start int, stop int) ([]string, error)) *MockStore_LRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LastVersion provides a mock function for the type MockStore
+func (_mock *MockStore) LastVersion() uint64 {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastVersion")
+	}
+
+	var r0 uint64
+	if returnFunc, ok := ret.Get(0).(func() uint64); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+	return r0
+}
+
+// MockStore_LastVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LastVersion'
+type MockStore_LastVersion_Call struct {
+	*mock.Call
+}
+
+// LastVersion is a helper method to define mock.On call
+func (_e *MockStore_Expecter) LastVersion() *MockStore_LastVersion_Call {
+	return &MockStore_LastVersion_Call{Call: _e.mock.On("LastVersion")}
+}
+
+func (_c *MockStore_LastVersion_Call) Run(run func()) *MockStore_LastVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStore_LastVersion_Call) Return(v uint64) *MockStore_LastVersion_Call {
+	_c.Call.Return(v)
+	return _c
+}
+
+func (_c *MockStore_LastVersion_Call) RunAndReturn(run func() uint64) *MockStore_LastVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Lookup provides a mock function for the type MockStore
+func (_mock *MockStore) Lookup(key string, now int64) (store.Entry, error) {
+	ret := _mock.Called(key, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lookup")
+	}
+
+	var r0 store.Entry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int64) (store.Entry, error)); ok {
+		return returnFunc(key, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int64) store.Entry); ok {
+		r0 = returnFunc(key, now)
+	} else {
+		r0 = ret.Get(0).(store.Entry)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = returnFunc(key, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_Lookup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lookup'
+type MockStore_Lookup_Call struct {
+	*mock.Call
+}
+
+// Lookup is a helper method to define mock.On call
+//   - key string
+//   - now int64
+func (_e *MockStore_Expecter) Lookup(key interface{}, now interface{}) *MockStore_Lookup_Call {
+	return &MockStore_Lookup_Call{Call: _e.mock.On("Lookup", key, now)}
+}
+
+func (_c *MockStore_Lookup_Call) Run(run func(key string, now int64)) *MockStore_Lookup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_Lookup_Call) Return(entry store.Entry, err error) *MockStore_Lookup_Call {
+	_c.Call.Return(entry, err)
+	return _c
+}
+
+func (_c *MockStore_Lookup_Call) RunAndReturn(run func(key string, now int64) (store.Entry, error)) *MockStore_Lookup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Pop provides a mock function for the type MockStore
+func (_mock *MockStore) Pop(key string, count int, left bool, now int64) ([]string, error) {
+	ret := _mock.Called(key, count, left, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pop")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, bool, int64) ([]string, error)); ok {
+		return returnFunc(key, count, left, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, bool, int64) []string); ok {
+		r0 = returnFunc(key, count, left, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int, bool, int64) error); ok {
+		r1 = returnFunc(key, count, left, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_Pop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pop'
+type MockStore_Pop_Call struct {
+	*mock.Call
+}
+
+// Pop is a helper method to define mock.On call
+//   - key string
+//   - count int
+//   - left bool
+//   - now int64
+func (_e *MockStore_Expecter) Pop(key interface{}, count interface{}, left interface{}, now interface{}) *MockStore_Pop_Call {
+	return &MockStore_Pop_Call{Call: _e.mock.On("Pop", key, count, left, now)}
+}
+
+func (_c *MockStore_Pop_Call) Run(run func(key string, count int, left bool, now int64)) *MockStore_Pop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 bool
+		if args[2] != nil {
+			arg2 = args[2].(bool)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_Pop_Call) Return(strings []string, err error) *MockStore_Pop_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockStore_Pop_Call) RunAndReturn(run func(key string, count int, left bool, now int64) ([]string, error)) *MockStore_Pop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Push provides a mock function for the type MockStore
+func (_mock *MockStore) Push(key string, values []string, left bool, now int64) (int, error) {
+	ret := _mock.Called(key, values, left, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Push")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, []string, bool, int64) (int, error)); ok {
+		return returnFunc(key, values, left, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, []string, bool, int64) int); ok {
+		r0 = returnFunc(key, values, left, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, []string, bool, int64) error); ok {
+		r1 = returnFunc(key, values, left, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_Push_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Push'
+type MockStore_Push_Call struct {
+	*mock.Call
+}
+
+// Push is a helper method to define mock.On call
+//   - key string
+//   - values []string
+//   - left bool
+//   - now int64
+func (_e *MockStore_Expecter) Push(key interface{}, values interface{}, left interface{}, now interface{}) *MockStore_Push_Call {
+	return &MockStore_Push_Call{Call: _e.mock.On("Push", key, values, left, now)}
+}
+
+func (_c *MockStore_Push_Call) Run(run func(key string, values []string, left bool, now int64)) *MockStore_Push_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 bool
+		if args[2] != nil {
+			arg2 = args[2].(bool)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_Push_Call) Return(n int, err error) *MockStore_Push_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStore_Push_Call) RunAndReturn(run func(key string, values []string, left bool, now int64) (int, error)) *MockStore_Push_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Put provides a mock function for the type MockStore
+func (_mock *MockStore) Put(key string, value string) error {
+	ret := _mock.Called(key, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(key, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStore_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
+type MockStore_Put_Call struct {
+	*mock.Call
+}
+
+// Put is a helper method to define mock.On call
+//   - key string
+//   - value string
+func (_e *MockStore_Expecter) Put(key interface{}, value interface{}) *MockStore_Put_Call {
+	return &MockStore_Put_Call{Call: _e.mock.On("Put", key, value)}
+}
+
+func (_c *MockStore_Put_Call) Run(run func(key string, value string)) *MockStore_Put_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_Put_Call) Return(err error) *MockStore_Put_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStore_Put_Call) RunAndReturn(run func(key string, value string) error) *MockStore_Put_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutEntry provides a mock function for the type MockStore
+func (_mock *MockStore) PutEntry(e store.Entry) error {
+	ret := _mock.Called(e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutEntry")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(store.Entry) error); ok {
+		r0 = returnFunc(e)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStore_PutEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutEntry'
+type MockStore_PutEntry_Call struct {
+	*mock.Call
+}
+
+// PutEntry is a helper method to define mock.On call
+//   - e store.Entry
+func (_e *MockStore_Expecter) PutEntry(e interface{}) *MockStore_PutEntry_Call {
+	return &MockStore_PutEntry_Call{Call: _e.mock.On("PutEntry", e)}
+}
+
+func (_c *MockStore_PutEntry_Call) Run(run func(e store.Entry)) *MockStore_PutEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 store.Entry
+		if args[0] != nil {
+			arg0 = args[0].(store.Entry)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_PutEntry_Call) Return(err error) *MockStore_PutEntry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStore_PutEntry_Call) RunAndReturn(run func(e store.Entry) error) *MockStore_PutEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RangeEntries provides a mock function for the type MockStore
+func (_mock *MockStore) RangeEntries(fn func(e store.Entry) error) error {
+	ret := _mock.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RangeEntries")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(func(e store.Entry) error) error); ok {
+		r0 = returnFunc(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStore_RangeEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RangeEntries'
+type MockStore_RangeEntries_Call struct {
+	*mock.Call
+}
+
+// RangeEntries is a helper method to define mock.On call
+//   - fn func(e store.Entry) error
+func (_e *MockStore_Expecter) RangeEntries(fn interface{}) *MockStore_RangeEntries_Call {
+	return &MockStore_RangeEntries_Call{Call: _e.mock.On("RangeEntries", fn)}
+}
+
+func (_c *MockStore_RangeEntries_Call) Run(run func(fn func(e store.Entry) error)) *MockStore_RangeEntries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(e store.Entry) error
+		if args[0] != nil {
+			arg0 = args[0].(func(e store.Entry) error)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_RangeEntries_Call) Return(err error) *MockStore_RangeEntries_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStore_RangeEntries_Call) RunAndReturn(run func(fn func(e store.Entry) error) error) *MockStore_RangeEntries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreFrom provides a mock function for the type MockStore
+func (_mock *MockStore) RestoreFrom(fill func(put func(e store.Entry)) error) error {
+	ret := _mock.Called(fill)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreFrom")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(func(put func(e store.Entry)) error) error); ok {
+		r0 = returnFunc(fill)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStore_RestoreFrom_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreFrom'
+type MockStore_RestoreFrom_Call struct {
+	*mock.Call
+}
+
+// RestoreFrom is a helper method to define mock.On call
+//   - fill func(put func(e store.Entry)) error
+func (_e *MockStore_Expecter) RestoreFrom(fill interface{}) *MockStore_RestoreFrom_Call {
+	return &MockStore_RestoreFrom_Call{Call: _e.mock.On("RestoreFrom", fill)}
+}
+
+func (_c *MockStore_RestoreFrom_Call) Run(run func(fill func(put func(e store.Entry)) error)) *MockStore_RestoreFrom_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(put func(e store.Entry)) error
+		if args[0] != nil {
+			arg0 = args[0].(func(put func(e store.Entry)) error)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_RestoreFrom_Call) Return(err error) *MockStore_RestoreFrom_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStore_RestoreFrom_Call) RunAndReturn(run func(fill func(put func(e store.Entry)) error) error) *MockStore_RestoreFrom_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreFromSnapshot provides a mock function for the type MockStore
+func (_mock *MockStore) RestoreFromSnapshot(snapData map[string]string) {
+	_mock.Called(snapData)
+	return
+}
+
+// MockStore_RestoreFromSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreFromSnapshot'
+type MockStore_RestoreFromSnapshot_Call struct {
+	*mock.Call
+}
+
+// RestoreFromSnapshot is a helper method to define mock.On call
+//   - snapData map[string]string
+func (_e *MockStore_Expecter) RestoreFromSnapshot(snapData interface{}) *MockStore_RestoreFromSnapshot_Call {
+	return &MockStore_RestoreFromSnapshot_Call{Call: _e.mock.On("RestoreFromSnapshot", snapData)}
+}
+
+func (_c *MockStore_RestoreFromSnapshot_Call) Run(run func(snapData map[string]string)) *MockStore_RestoreFromSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 map[string]string
+		if args[0] != nil {
+			arg0 = args[0].(map[string]string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_RestoreFromSnapshot_Call) Return() *MockStore_RestoreFromSnapshot_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockStore_RestoreFromSnapshot_Call) RunAndReturn(run func(snapData map[string]string)) *MockStore_RestoreFromSnapshot_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RestoreVersion provides a mock function for the type MockStore
+func (_mock *MockStore) RestoreVersion(v uint64) {
+	_mock.Called(v)
+	return
+}
+
+// MockStore_RestoreVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreVersion'
+type MockStore_RestoreVersion_Call struct {
+	*mock.Call
+}
+
+// RestoreVersion is a helper method to define mock.On call
+//   - v uint64
+func (_e *MockStore_Expecter) RestoreVersion(v interface{}) *MockStore_RestoreVersion_Call {
+	return &MockStore_RestoreVersion_Call{Call: _e.mock.On("RestoreVersion", v)}
+}
+
+func (_c *MockStore_RestoreVersion_Call) Run(run func(v uint64)) *MockStore_RestoreVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 uint64
+		if args[0] != nil {
+			arg0 = args[0].(uint64)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_RestoreVersion_Call) Return() *MockStore_RestoreVersion_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockStore_RestoreVersion_Call) RunAndReturn(run func(v uint64)) *MockStore_RestoreVersion_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ScanShard provides a mock function for the type MockStore
+func (_mock *MockStore) ScanShard(cursor int, now int64) ([]string, int) {
+	ret := _mock.Called(cursor, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScanShard")
+	}
+
+	var r0 []string
+	var r1 int
+	if returnFunc, ok := ret.Get(0).(func(int, int64) ([]string, int)); ok {
+		return returnFunc(cursor, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int, int64) []string); ok {
+		r0 = returnFunc(cursor, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(int, int64) int); ok {
+		r1 = returnFunc(cursor, now)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	return r0, r1
+}
+
+// MockStore_ScanShard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScanShard'
+type MockStore_ScanShard_Call struct {
+	*mock.Call
+}
+
+// ScanShard is a helper method to define mock.On call
+//   - cursor int
+//   - now int64
+func (_e *MockStore_Expecter) ScanShard(cursor interface{}, now interface{}) *MockStore_ScanShard_Call {
+	return &MockStore_ScanShard_Call{Call: _e.mock.On("ScanShard", cursor, now)}
+}
+
+func (_c *MockStore_ScanShard_Call) Run(run func(cursor int, now int64)) *MockStore_ScanShard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int
+		if args[0] != nil {
+			arg0 = args[0].(int)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ScanShard_Call) Return(keys []string, next int) *MockStore_ScanShard_Call {
+	_c.Call.Return(keys, next)
+	return _c
+}
+
+func (_c *MockStore_ScanShard_Call) RunAndReturn(run func(cursor int, now int64) ([]string, int)) *MockStore_ScanShard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartMapRebuilder provides a mock function for the type MockStore
+func (_mock *MockStore) StartMapRebuilder(ctx context.Context, wg *sync.WaitGroup) {
+	_mock.Called(ctx, wg)
+	return
+}
+
+// MockStore_StartMapRebuilder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartMapRebuilder'
+type MockStore_StartMapRebuilder_Call struct {
+	*mock.Call
+}
+
+// StartMapRebuilder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - wg *sync.WaitGroup
+func (_e *MockStore_Expecter) StartMapRebuilder(ctx interface{}, wg interface{}) *MockStore_StartMapRebuilder_Call {
+	return &MockStore_StartMapRebuilder_Call{Call: _e.mock.On("StartMapRebuilder", ctx, wg)}
+}
+
+func (_c *MockStore_StartMapRebuilder_Call) Run(run func(ctx context.Context, wg *sync.WaitGroup)) *MockStore_StartMapRebuilder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *sync.WaitGroup
+		if args[1] != nil {
+			arg1 = args[1].(*sync.WaitGroup)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_StartMapRebuilder_Call) Return() *MockStore_StartMapRebuilder_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockStore_StartMapRebuilder_Call) RunAndReturn(run func(ctx context.Context, wg *sync.WaitGroup)) *MockStore_StartMapRebuilder_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ZAdd provides a mock function for the type MockStore
+func (_mock *MockStore) ZAdd(key string, members []store.ScoredMember, now int64) (int, error) {
+	ret := _mock.Called(key, members, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ZAdd")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, []store.ScoredMember, int64) (int, error)); ok {
+		return returnFunc(key, members, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, []store.ScoredMember, int64) int); ok {
+		r0 = returnFunc(key, members, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, []store.ScoredMember, int64) error); ok {
+		r1 = returnFunc(key, members, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_ZAdd_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ZAdd'
+type MockStore_ZAdd_Call struct {
+	*mock.Call
+}
+
+// ZAdd is a helper method to define mock.On call
+//   - key string
+//   - members []store.ScoredMember
+//   - now int64
+func (_e *MockStore_Expecter) ZAdd(key interface{}, members interface{}, now interface{}) *MockStore_ZAdd_Call {
+	return &MockStore_ZAdd_Call{Call: _e.mock.On("ZAdd", key, members, now)}
+}
+
+func (_c *MockStore_ZAdd_Call) Run(run func(key string, members []store.ScoredMember, now int64)) *MockStore_ZAdd_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []store.ScoredMember
+		if args[1] != nil {
+			arg1 = args[1].([]store.ScoredMember)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ZAdd_Call) Return(n int, err error) *MockStore_ZAdd_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStore_ZAdd_Call) RunAndReturn(run func(key string, members []store.ScoredMember, now int64) (int, error)) *MockStore_ZAdd_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ZIncrBy provides a mock function for the type MockStore
+func (_mock *MockStore) ZIncrBy(key string, member string, delta float64, now int64) (float64, error) {
+	ret := _mock.Called(key, member, delta, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ZIncrBy")
+	}
+
+	var r0 float64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, float64, int64) (float64, error)); ok {
+		return returnFunc(key, member, delta, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, float64, int64) float64); ok {
+		r0 = returnFunc(key, member, delta, now)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, float64, int64) error); ok {
+		r1 = returnFunc(key, member, delta, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_ZIncrBy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ZIncrBy'
+type MockStore_ZIncrBy_Call struct {
+	*mock.Call
+}
+
+// ZIncrBy is a helper method to define mock.On call
+//   - key string
+//   - member string
+//   - delta float64
+//   - now int64
+func (_e *MockStore_Expecter) ZIncrBy(key interface{}, member interface{}, delta interface{}, now interface{}) *MockStore_ZIncrBy_Call {
+	return &MockStore_ZIncrBy_Call{Call: _e.mock.On("ZIncrBy", key, member, delta, now)}
+}
+
+func (_c *MockStore_ZIncrBy_Call) Run(run func(key string, member string, delta float64, now int64)) *MockStore_ZIncrBy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 float64
+		if args[2] != nil {
+			arg2 = args[2].(float64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ZIncrBy_Call) Return(f float64, err error) *MockStore_ZIncrBy_Call {
+	_c.Call.Return(f, err)
+	return _c
+}
+
+func (_c *MockStore_ZIncrBy_Call) RunAndReturn(run func(key string, member string, delta float64, now int64) (float64, error)) *MockStore_ZIncrBy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ZPopMin provides a mock function for the type MockStore
+func (_mock *MockStore) ZPopMin(key string, count int, now int64) ([]store.ScoredMember, error) {
+	ret := _mock.Called(key, count, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ZPopMin")
+	}
+
+	var r0 []store.ScoredMember
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int64) ([]store.ScoredMember, error)); ok {
+		return returnFunc(key, count, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, int64) []store.ScoredMember); ok {
+		r0 = returnFunc(key, count, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.ScoredMember)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int, int64) error); ok {
+		r1 = returnFunc(key, count, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_ZPopMin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ZPopMin'
+type MockStore_ZPopMin_Call struct {
+	*mock.Call
+}
+
+// ZPopMin is a helper method to define mock.On call
+//   - key string
+//   - count int
+//   - now int64
+func (_e *MockStore_Expecter) ZPopMin(key interface{}, count interface{}, now interface{}) *MockStore_ZPopMin_Call {
+	return &MockStore_ZPopMin_Call{Call: _e.mock.On("ZPopMin", key, count, now)}
+}
+
+func (_c *MockStore_ZPopMin_Call) Run(run func(key string, count int, now int64)) *MockStore_ZPopMin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ZPopMin_Call) Return(scoredMembers []store.ScoredMember, err error) *MockStore_ZPopMin_Call {
+	_c.Call.Return(scoredMembers, err)
+	return _c
+}
+
+func (_c *MockStore_ZPopMin_Call) RunAndReturn(run func(key string, count int, now int64) ([]store.ScoredMember, error)) *MockStore_ZPopMin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ZRange provides a mock function for the type MockStore
+func (_mock *MockStore) ZRange(key string, start int, stop int) ([]store.ScoredMember, error) {
+	ret := _mock.Called(key, start, stop)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ZRange")
+	}
+
+	var r0 []store.ScoredMember
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) ([]store.ScoredMember, error)); ok {
+		return returnFunc(key, start, stop)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) []store.ScoredMember); ok {
+		r0 = returnFunc(key, start, stop)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.ScoredMember)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = returnFunc(key, start, stop)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_ZRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ZRange'
+type MockStore_ZRange_Call struct {
+	*mock.Call
+}
+
+// ZRange is a helper method to define mock.On call
+//   - key string
+//   - start int
+//   - stop int
+func (_e *MockStore_Expecter) ZRange(key interface{}, start interface{}, stop interface{}) *MockStore_ZRange_Call {
+	return &MockStore_ZRange_Call{Call: _e.mock.On("ZRange", key, start, stop)}
+}
+
+func (_c *MockStore_ZRange_Call) Run(run func(key string, start int, stop int)) *MockStore_ZRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ZRange_Call) Return(scoredMembers []store.ScoredMember, err error) *MockStore_ZRange_Call {
+	_c.Call.Return(scoredMembers, err)
+	return _c
+}
+
+func (_c *MockStore_ZRange_Call) RunAndReturn(run func(key string, start int, stop int) ([]store.ScoredMember, error)) *MockStore_ZRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ZRangeByScore provides a mock function for the type MockStore
+func (_mock *MockStore) ZRangeByScore(key string, min float64, max float64, limit int) ([]store.ScoredMember, error) {
+	ret := _mock.Called(key, min, max, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ZRangeByScore")
+	}
+
+	var r0 []store.ScoredMember
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, float64, float64, int) ([]store.ScoredMember, error)); ok {
+		return returnFunc(key, min, max, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, float64, float64, int) []store.ScoredMember); ok {
+		r0 = returnFunc(key, min, max, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.ScoredMember)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, float64, float64, int) error); ok {
+		r1 = returnFunc(key, min, max, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_ZRangeByScore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ZRangeByScore'
+type MockStore_ZRangeByScore_Call struct {
+	*mock.Call
+}
+
+// ZRangeByScore is a helper method to define mock.On call
+//   - key string
+//   - min float64
+//   - max float64
+//   - limit int
+func (_e *MockStore_Expecter) ZRangeByScore(key interface{}, min interface{}, max interface{}, limit interface{}) *MockStore_ZRangeByScore_Call {
+	return &MockStore_ZRangeByScore_Call{Call: _e.mock.On("ZRangeByScore", key, min, max, limit)}
+}
+
+func (_c *MockStore_ZRangeByScore_Call) Run(run func(key string, min float64, max float64, limit int)) *MockStore_ZRangeByScore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 float64
+		if args[1] != nil {
+			arg1 = args[1].(float64)
+		}
+		var arg2 float64
+		if args[2] != nil {
+			arg2 = args[2].(float64)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ZRangeByScore_Call) Return(scoredMembers []store.ScoredMember, err error) *MockStore_ZRangeByScore_Call {
+	_c.Call.Return(scoredMembers, err)
+	return _c
+}
+
+func (_c *MockStore_ZRangeByScore_Call) RunAndReturn(run func(key string, min float64, max float64, limit int) ([]store.ScoredMember, error)) *MockStore_ZRangeByScore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ZRem provides a mock function for the type MockStore
+func (_mock *MockStore) ZRem(key string, members []string, now int64) (int, error) {
+	ret := _mock.Called(key, members, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ZRem")
 	}
 
-	var r0 string
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return returnFunc(key)
+	if returnFunc, ok := ret.Get(0).(func(string, []string, int64) (int, error)); ok {
+		return returnFunc(key, members, now)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
-		r0 = returnFunc(key)
+	if returnFunc, ok := ret.Get(0).(func(string, []string, int64) int); ok {
+		r0 = returnFunc(key, members, now)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(key)
+	if returnFunc, ok := ret.Get(1).(func(string, []string, int64) error); ok {
+		r1 = returnFunc(key, members, now)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockStore_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockStore_Get_Call struct {
+// MockStore_ZRem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ZRem'
+type MockStore_ZRem_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
+// ZRem is a helper method to define mock.On call
 //   - key string
-func (_e *MockStore_Expecter) Get(key interface{}) *MockStore_Get_Call {
-	return &MockStore_Get_Call{Call: _e.mock.On("Get", key)}
+//   - members []string
+//   - now int64
+func (_e *MockStore_Expecter) ZRem(key interface{}, members interface{}, now interface{}) *MockStore_ZRem_Call {
+	return &MockStore_ZRem_Call{Call: _e.mock.On("ZRem", key, members, now)}
 }
 
-func (_c *MockStore_Get_Call) Run(run func(key string)) *MockStore_Get_Call {
+func (_c *MockStore_ZRem_Call) Run(run func(key string, members []string, now int64)) *MockStore_ZRem_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
 			arg0 = args[0].(string)
 		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStore_Get_Call) Return(s string, err error) *MockStore_Get_Call {
-	_c.Call.Return(s, err)
+func (_c *MockStore_ZRem_Call) Return(n int, err error) *MockStore_ZRem_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockStore_Get_Call) RunAndReturn(run func(key string) (string, error)) *MockStore_Get_Call {
+func (_c *MockStore_ZRem_Call) RunAndReturn(run func(key string, members []string, now int64) (int, error)) *MockStore_ZRem_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Items provides a mock function for the type MockStore
-func (_mock *MockStore) Items() map[string]string {
-	ret := _mock.Called()
+// ZScore provides a mock function for the type MockStore
+func (_mock *MockStore) ZScore(key string, member string) (float64, error) {
+	ret := _mock.Called(key, member)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Items")
+		panic("no return value specified for ZScore")
 	}
 
-	var r0 map[string]string
-	if returnFunc, ok := ret.Get(0).(func() map[string]string); ok {
-		r0 = returnFunc()
+	var r0 float64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) (float64, error)); ok {
+		return returnFunc(key, member)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) float64); ok {
+		r0 = returnFunc(key, member)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]string)
+		r0 = ret.Get(0).(float64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(key, member)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStore_ZScore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ZScore'
+type MockStore_ZScore_Call struct {
+	*mock.Call
+}
+
+// ZScore is a helper method to define mock.On call
+//   - key string
+//   - member string
+func (_e *MockStore_Expecter) ZScore(key interface{}, member interface{}) *MockStore_ZScore_Call {
+	return &MockStore_ZScore_Call{Call: _e.mock.On("ZScore", key, member)}
+}
+
+func (_c *MockStore_ZScore_Call) Run(run func(key string, member string)) *MockStore_ZScore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
 		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_ZScore_Call) Return(f float64, err error) *MockStore_ZScore_Call {
+	_c.Call.Return(f, err)
+	return _c
+}
+
+func (_c *MockStore_ZScore_Call) RunAndReturn(run func(key string, member string) (float64, error)) *MockStore_ZScore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockNamespaces creates a new instance of MockNamespaces. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockNamespaces(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockNamespaces {
+	mock := &MockNamespaces{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockNamespaces is an autogenerated mock type for the Namespaces type
+type MockNamespaces struct {
+	mock.Mock
+}
+
+type MockNamespaces_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockNamespaces) EXPECT() *MockNamespaces_Expecter {
+	return &MockNamespaces_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockNamespaces
+func (_mock *MockNamespaces) Create(ns store.Namespace) error {
+	ret := _mock.Called(ns)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(store.Namespace) error); ok {
+		r0 = returnFunc(ns)
+	} else {
+		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStore_Items_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Items'
-type MockStore_Items_Call struct {
+// MockNamespaces_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockNamespaces_Create_Call struct {
 	*mock.Call
 }
 
-// Items is a helper method to define mock.On call
-func (_e *MockStore_Expecter) Items() *MockStore_Items_Call {
-	return &MockStore_Items_Call{Call: _e.mock.On("Items")}
+// Create is a helper method to define mock.On call
+//   - ns store.Namespace
+func (_e *MockNamespaces_Expecter) Create(ns interface{}) *MockNamespaces_Create_Call {
+	return &MockNamespaces_Create_Call{Call: _e.mock.On("Create", ns)}
 }
 
-func (_c *MockStore_Items_Call) Run(run func()) *MockStore_Items_Call {
+func (_c *MockNamespaces_Create_Call) Run(run func(ns store.Namespace)) *MockNamespaces_Create_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 store.Namespace
+		if args[0] != nil {
+			arg0 = args[0].(store.Namespace)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
 
-func (_c *MockStore_Items_Call) Return(stringToString map[string]string) *MockStore_Items_Call {
-	_c.Call.Return(stringToString)
+func (_c *MockNamespaces_Create_Call) Return(err error) *MockNamespaces_Create_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStore_Items_Call) RunAndReturn(run func() map[string]string) *MockStore_Items_Call {
+func (_c *MockNamespaces_Create_Call) RunAndReturn(run func(ns store.Namespace) error) *MockNamespaces_Create_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Put provides a mock function for the type MockStore
-func (_mock *MockStore) Put(key string, value string) error {
-	ret := _mock.Called(key, value)
+// Delete provides a mock function for the type MockNamespaces
+func (_mock *MockNamespaces) Delete(name string) error {
+	ret := _mock.Called(name)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Put")
+		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = returnFunc(key, value)
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(name)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStore_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
-type MockStore_Put_Call struct {
+// MockNamespaces_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockNamespaces_Delete_Call struct {
 	*mock.Call
 }
 
-// Put is a helper method to define mock.On call
-//   - key string
-//   - value string
-func (_e *MockStore_Expecter) Put(key interface{}, value interface{}) *MockStore_Put_Call {
-	return &MockStore_Put_Call{Call: _e.mock.On("Put", key, value)}
+// Delete is a helper method to define mock.On call
+//   - name string
+func (_e *MockNamespaces_Expecter) Delete(name interface{}) *MockNamespaces_Delete_Call {
+	return &MockNamespaces_Delete_Call{Call: _e.mock.On("Delete", name)}
 }
 
-func (_c *MockStore_Put_Call) Run(run func(key string, value string)) *MockStore_Put_Call {
+func (_c *MockNamespaces_Delete_Call) Run(run func(name string)) *MockNamespaces_Delete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
 			arg0 = args[0].(string)
 		}
-		var arg1 string
-		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStore_Put_Call) Return(err error) *MockStore_Put_Call {
+func (_c *MockNamespaces_Delete_Call) Return(err error) *MockNamespaces_Delete_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStore_Put_Call) RunAndReturn(run func(key string, value string) error) *MockStore_Put_Call {
+func (_c *MockNamespaces_Delete_Call) RunAndReturn(run func(name string) error) *MockNamespaces_Delete_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RestoreFromSnapshot provides a mock function for the type MockStore
-func (_mock *MockStore) RestoreFromSnapshot(snapData map[string]string) {
-	_mock.Called(snapData)
-	return
+// List provides a mock function for the type MockNamespaces
+func (_mock *MockNamespaces) List() []store.Namespace {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []store.Namespace
+	if returnFunc, ok := ret.Get(0).(func() []store.Namespace); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.Namespace)
+		}
+	}
+	return r0
 }
 
-// MockStore_RestoreFromSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreFromSnapshot'
-type MockStore_RestoreFromSnapshot_Call struct {
+// MockNamespaces_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockNamespaces_List_Call struct {
 	*mock.Call
 }
 
-// RestoreFromSnapshot is a helper method to define mock.On call
-//   - snapData map[string]string
-func (_e *MockStore_Expecter) RestoreFromSnapshot(snapData interface{}) *MockStore_RestoreFromSnapshot_Call {
-	return &MockStore_RestoreFromSnapshot_Call{Call: _e.mock.On("RestoreFromSnapshot", snapData)}
+// List is a helper method to define mock.On call
+func (_e *MockNamespaces_Expecter) List() *MockNamespaces_List_Call {
+	return &MockNamespaces_List_Call{Call: _e.mock.On("List")}
 }
 
-func (_c *MockStore_RestoreFromSnapshot_Call) Run(run func(snapData map[string]string)) *MockStore_RestoreFromSnapshot_Call {
+func (_c *MockNamespaces_List_Call) Run(run func()) *MockNamespaces_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 map[string]string
+		run()
+	})
+	return _c
+}
+
+func (_c *MockNamespaces_List_Call) Return(namespaces []store.Namespace) *MockNamespaces_List_Call {
+	_c.Call.Return(namespaces)
+	return _c
+}
+
+func (_c *MockNamespaces_List_Call) RunAndReturn(run func() []store.Namespace) *MockNamespaces_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function for the type MockNamespaces
+func (_mock *MockNamespaces) Restore(spaces []store.Namespace, fill func(put func(namespace string, e store.Entry)) error) error {
+	ret := _mock.Called(spaces, fill)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func([]store.Namespace, func(put func(namespace string, e store.Entry)) error) error); ok {
+		r0 = returnFunc(spaces, fill)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockNamespaces_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type MockNamespaces_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - spaces []store.Namespace
+//   - fill func(put func(namespace string, e store.Entry)) error
+func (_e *MockNamespaces_Expecter) Restore(spaces interface{}, fill interface{}) *MockNamespaces_Restore_Call {
+	return &MockNamespaces_Restore_Call{Call: _e.mock.On("Restore", spaces, fill)}
+}
+
+func (_c *MockNamespaces_Restore_Call) Run(run func(spaces []store.Namespace, fill func(put func(namespace string, e store.Entry)) error)) *MockNamespaces_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []store.Namespace
 		if args[0] != nil {
-			arg0 = args[0].(map[string]string)
+			arg0 = args[0].([]store.Namespace)
+		}
+		var arg1 func(put func(namespace string, e store.Entry)) error
+		if args[1] != nil {
+			arg1 = args[1].(func(put func(namespace string, e store.Entry)) error)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStore_RestoreFromSnapshot_Call) Return() *MockStore_RestoreFromSnapshot_Call {
-	_c.Call.Return()
+func (_c *MockNamespaces_Restore_Call) Return(err error) *MockNamespaces_Restore_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStore_RestoreFromSnapshot_Call) RunAndReturn(run func(snapData map[string]string)) *MockStore_RestoreFromSnapshot_Call {
-	_c.Run(run)
+func (_c *MockNamespaces_Restore_Call) RunAndReturn(run func(spaces []store.Namespace, fill func(put func(namespace string, e store.Entry)) error) error) *MockNamespaces_Restore_Call {
+	_c.Call.Return(run)
 	return _c
 }
 
-// StartMapRebuilder provides a mock function for the type MockStore
-func (_mock *MockStore) StartMapRebuilder(ctx context.Context, wg *sync.WaitGroup) {
+// StartMapRebuilder provides a mock function for the type MockNamespaces
+func (_mock *MockNamespaces) StartMapRebuilder(ctx context.Context, wg *sync.WaitGroup) {
 	_mock.Called(ctx, wg)
 	return
 }
 
-// MockStore_StartMapRebuilder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartMapRebuilder'
-type MockStore_StartMapRebuilder_Call struct {
+// MockNamespaces_StartMapRebuilder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartMapRebuilder'
+type MockNamespaces_StartMapRebuilder_Call struct {
 	*mock.Call
 }
 
 // StartMapRebuilder is a helper method to define mock.On call
 //   - ctx context.Context
 //   - wg *sync.WaitGroup
-func (_e *MockStore_Expecter) StartMapRebuilder(ctx interface{}, wg interface{}) *MockStore_StartMapRebuilder_Call {
-	return &MockStore_StartMapRebuilder_Call{Call: _e.mock.On("StartMapRebuilder", ctx, wg)}
+func (_e *MockNamespaces_Expecter) StartMapRebuilder(ctx interface{}, wg interface{}) *MockNamespaces_StartMapRebuilder_Call {
+	return &MockNamespaces_StartMapRebuilder_Call{Call: _e.mock.On("StartMapRebuilder", ctx, wg)}
 }
 
-func (_c *MockStore_StartMapRebuilder_Call) Run(run func(ctx context.Context, wg *sync.WaitGroup)) *MockStore_StartMapRebuilder_Call {
+func (_c *MockNamespaces_StartMapRebuilder_Call) Run(run func(ctx context.Context, wg *sync.WaitGroup)) *MockNamespaces_StartMapRebuilder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -328,12 +2363,74 @@ func (_c *MockStore_StartMapRebuilder_Call) Run(run func(ctx context.Context, wg
 	return _c
 }
 
-func (_c *MockStore_StartMapRebuilder_Call) Return() *MockStore_StartMapRebuilder_Call {
+func (_c *MockNamespaces_StartMapRebuilder_Call) Return() *MockNamespaces_StartMapRebuilder_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockStore_StartMapRebuilder_Call) RunAndReturn(run func(ctx context.Context, wg *sync.WaitGroup)) *MockStore_StartMapRebuilder_Call {
+func (_c *MockNamespaces_StartMapRebuilder_Call) RunAndReturn(run func(ctx context.Context, wg *sync.WaitGroup)) *MockNamespaces_StartMapRebuilder_Call {
 	_c.Run(run)
 	return _c
 }
+
+// Store provides a mock function for the type MockNamespaces
+func (_mock *MockNamespaces) Store(name string) (store.Store, error) {
+	ret := _mock.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 store.Store
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (store.Store, error)); ok {
+		return returnFunc(name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) store.Store); ok {
+		r0 = returnFunc(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.Store)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNamespaces_Store_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Store'
+type MockNamespaces_Store_Call struct {
+	*mock.Call
+}
+
+// Store is a helper method to define mock.On call
+//   - name string
+func (_e *MockNamespaces_Expecter) Store(name interface{}) *MockNamespaces_Store_Call {
+	return &MockNamespaces_Store_Call{Call: _e.mock.On("Store", name)}
+}
+
+func (_c *MockNamespaces_Store_Call) Run(run func(name string)) *MockNamespaces_Store_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNamespaces_Store_Call) Return(store1 store.Store, err error) *MockNamespaces_Store_Call {
+	_c.Call.Return(store1, err)
+	return _c
+}
+
+func (_c *MockNamespaces_Store_Call) RunAndReturn(run func(name string) (store.Store, error)) *MockNamespaces_Store_Call {
+	_c.Call.Return(run)
+	return _c
+}
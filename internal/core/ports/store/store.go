@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
 	"sync"
 )
 
@@ -10,14 +12,218 @@ var (
 	ErrNoSuchKey     = errors.New("no such key")
 	ErrKeyTooLarge   = errors.New("key too large")
 	ErrValueTooLarge = errors.New("value too large")
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+	ErrNotInteger    = errors.New("value is not an integer or out of range")
+	// ErrVersionMismatch is returned by a conditional write if the key was modified since it was read.
+	ErrVersionMismatch = errors.New("key was modified since it was read")
+	// ErrWrongType is returned by an operation on a key holding a value of another kind.
+	ErrWrongType = errors.New("operation against a key holding the wrong kind of value")
+	// ErrNotANumber is returned by a sorted set write which would store a NaN score.
+	ErrNotANumber = errors.New("score is not a number")
+	// ErrNoSuchNamespace is returned for a namespace which wasn't created or was deleted.
+	ErrNoSuchNamespace = errors.New("no such namespace")
+	// ErrNamespaceExists is returned by creation of a namespace which already exists.
+	ErrNamespaceExists = errors.New("namespace already exists")
+	// ErrInvalidNamespace is returned for a malformed namespace name or limits.
+	ErrInvalidNamespace = errors.New("invalid namespace")
 )
 
+// DefaultNamespace names the namespace of keys written without one in metrics and listings.
+const DefaultNamespace = "default"
+
+// UnknownNamespace labels metrics of requests to namespaces which don't exist.
+const UnknownNamespace = "unknown"
+
+// Kind is a type of a stored value.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindHash
+	KindList
+	KindZSet
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindHash:
+		return "hash"
+	case KindList:
+		return "list"
+	case KindZSet:
+		return "zset"
+	default:
+		return "unknown"
+	}
+}
+
+// Entry is a stored key with its value and metadata.
+type Entry struct {
+	Key   string
+	Value string
+	// ExpiresAt is expiry deadline in unix milliseconds, zero if the key doesn't expire.
+	ExpiresAt int64
+	// Flags is opaque client data stored with the value, e.g. memcached flags.
+	Flags uint32
+	// Version is assigned by the store on every write of the key. It never repeats,
+	// even for a key deleted and written again, and is the same on every replica.
+	Version uint64
+	Kind    Kind
+	// Hash holds fields of a hash. It's filled only by RangeEntries.
+	Hash map[string]string
+	// List holds values of a list from head to tail. It's filled only by RangeEntries.
+	List []string
+	// ZSet holds members of a sorted set in ascending order. It's filled only by RangeEntries.
+	ZSet []ScoredMember
+}
+
+// ScoredMember is a member of a sorted set. Members are ordered by score, then by member.
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// Expired reports whether the entry is expired at now in unix milliseconds.
+func (e *Entry) Expired(now int64) bool {
+	return e.ExpiresAt > 0 && e.ExpiresAt <= now
+}
+
 //go:generate mockery
 type Store interface {
 	StartMapRebuilder(ctx context.Context, wg *sync.WaitGroup)
+	// Put stores value without expiry
 	Put(key, value string) error
+	// Get returns value of a key unless it's expired by the local clock. Keys of other kinds than string give ErrWrongType
 	Get(key string) (string, error)
 	Delete(key string) error
+	// Lookup returns a key which isn't expired at now in unix milliseconds.
+	// State machine passes time of the command, so replicas agree on the result.
+	// Value is set only for strings, content of other kinds isn't copied
+	Lookup(key string, now int64) (Entry, error)
+	// PutEntry stores value with expiry and flags of e. Version of e is ignored, a new one is assigned
+	PutEntry(e Entry) error
+	// Expire sets expiry deadline of a key alive at now. Deadline at or before now deletes the key
+	Expire(key string, expiresAt, now int64) error
+	// ExpiredKeys returns up to limit keys expired at now
+	ExpiredKeys(now int64, limit int) []string
+	// DeleteExpired deletes the keys which are expired at now and returns the deleted ones
+	DeleteExpired(keys []string, now int64) []string
+	// FlushAll makes every key expire at expiresAt unless it expires earlier. Deadline at or before now deletes all keys
+	FlushAll(expiresAt, now int64)
+	// ScanShard returns keys alive at now from the shard at cursor and the cursor
+	// of the next shard, which is zero after the last one
+	ScanShard(cursor int, now int64) (keys []string, next int)
 	Items() map[string]string
 	RestoreFromSnapshot(snapData map[string]string)
+	// RangeEntries calls fn with every entry. Content of collections is shared with the store
+	// and must not be retained after fn returns. Iteration stops at the first error
+	RangeEntries(fn func(e Entry) error) error
+	// RestoreFrom replaces content with entries passed to put by fill.
+	// Content is left untouched if fill returns an error
+	RestoreFrom(fill func(put func(e Entry)) error) error
+	// HSet sets fields of a hash alive at now and returns number of added fields
+	HSet(key string, fields map[string]string, now int64) (int, error)
+	// HGet returns a field of a hash unless the hash is expired by the local clock
+	HGet(key, field string) (string, error)
+	// HGetAll returns a copy of all fields of a hash unless it's expired by the local clock
+	HGetAll(key string) (map[string]string, error)
+	// HDel deletes fields of a hash alive at now and returns number of deleted fields.
+	// Hash without fields is deleted
+	HDel(key string, fields []string, now int64) (int, error)
+	// HIncrBy adds delta to the integer value of a hash field alive at now and returns the new value
+	HIncrBy(key, field string, delta, now int64) (int64, error)
+	// Push adds values to the head (left) or the tail of a list alive at now one by one
+	// and returns the new length of the list
+	Push(key string, values []string, left bool, now int64) (int, error)
+	// Pop removes up to count values from the head (left) or the tail of a list alive at now.
+	// List without values is deleted
+	Pop(key string, count int, left bool, now int64) ([]string, error)
+	// LRange returns values of a list between start and stop inclusive unless the list is expired
+	// by the local clock. Negative indexes count from the tail
+	LRange(key string, start, stop int) ([]string, error)
+	// LLen returns length of a list unless it's expired by the local clock
+	LLen(key string) (int, error)
+	// ZAdd sets scores of members of a sorted set alive at now and returns number of added members
+	ZAdd(key string, members []ScoredMember, now int64) (int, error)
+	// ZRem removes members of a sorted set alive at now and returns number of removed members.
+	// Sorted set without members is deleted
+	ZRem(key string, members []string, now int64) (int, error)
+	// ZScore returns score of a member unless the sorted set is expired by the local clock
+	ZScore(key, member string) (float64, error)
+	// ZRange returns members of a sorted set ranked between start and stop inclusive unless the set
+	// is expired by the local clock. Negative ranks count from the highest score
+	ZRange(key string, start, stop int) ([]ScoredMember, error)
+	// ZRangeByScore returns up to limit members of a sorted set with scores between min and max inclusive
+	// unless the set is expired by the local clock. Limit of zero or less returns every such member
+	ZRangeByScore(key string, min, max float64, limit int) ([]ScoredMember, error)
+	// ZIncrBy adds delta to the score of a member of a sorted set alive at now and returns the new score
+	ZIncrBy(key, member string, delta float64, now int64) (float64, error)
+	// ZPopMin removes up to count members with the lowest scores from a sorted set alive at now
+	ZPopMin(key string, count int, now int64) ([]ScoredMember, error)
+	// LastVersion returns the version assigned by the last write
+	LastVersion() uint64
+	// RestoreVersion raises the version counter to at least v, so versions of deleted keys aren't reused after a restore
+	RestoreVersion(v uint64)
+}
+
+// Namespace is an isolated keyspace with its own store and limits. Zero limits fall back to the store config,
+// limits can't be larger than the store config ones.
+type Namespace struct {
+	Name       string `json:"name"`
+	MaxKeySize int    `json:"max_key_size,omitempty"`
+	MaxValSize int    `json:"max_val_size,omitempty"`
+	// MaxKeys limits number of keys stored in the namespace, zero means no limit.
+	MaxKeys int `json:"max_keys,omitempty"`
+}
+
+var namespaceName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// CheckNamespace checks the namespace name and that its limits don't exceed ones of the store config.
+func CheckNamespace(ns Namespace, maxKeySize, maxValSize int) error {
+	switch {
+	case !namespaceName.MatchString(ns.Name) || ns.Name == DefaultNamespace:
+		return fmt.Errorf("%w: name must be 1-63 lowercase letters, digits, '-' or '_' and not %q",
+			ErrInvalidNamespace, DefaultNamespace)
+	case ns.MaxKeySize < 0 || ns.MaxKeySize > maxKeySize:
+		return fmt.Errorf("%w: max key size must be between 0 and %d", ErrInvalidNamespace, maxKeySize)
+	case ns.MaxValSize < 0 || ns.MaxValSize > maxValSize:
+		return fmt.Errorf("%w: max value size must be between 0 and %d", ErrInvalidNamespace, maxValSize)
+	case ns.MaxKeys < 0:
+		return fmt.Errorf("%w: max keys can't be negative", ErrInvalidNamespace)
+	}
+	return nil
+}
+
+// MetricsNamespace returns the name of the default or an existing namespace and UnknownNamespace otherwise,
+// so requested names can't grow the number of metric series. Nil namespaces have the default one only.
+func MetricsNamespace(namespaces Namespaces, name string) string {
+	if name == "" {
+		return name
+	}
+	if namespaces == nil {
+		return UnknownNamespace
+	}
+	if _, err := namespaces.Store(name); err != nil {
+		return UnknownNamespace
+	}
+	return name
+}
+
+//go:generate mockery
+type Namespaces interface {
+	// Store returns the store of a namespace. Empty name is the default namespace
+	Store(name string) (Store, error)
+	// Create adds an empty namespace
+	Create(ns Namespace) error
+	// Delete deletes a namespace with all its keys
+	Delete(name string) error
+	// List returns namespaces ordered by name, without the default one
+	List() []Namespace
+	// Restore replaces namespaces other than the default one with the given ones. Their entries are passed to put
+	// by fill in a single pass, and current namespaces are left untouched if fill returns an error
+	Restore(spaces []Namespace, fill func(put func(namespace string, e Entry)) error) error
+	// StartMapRebuilder starts rebuilding maps of namespace stores, including ones created later, until ctx is done
+	StartMapRebuilder(ctx context.Context, wg *sync.WaitGroup)
 }
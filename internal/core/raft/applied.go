@@ -0,0 +1,64 @@
+package raft
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// appliedIndex tracks log index of the last applied command and wakes up goroutines waiting for it.
+type appliedIndex struct {
+	idx atomic.Int64
+	// at is unix time in nanoseconds of the last update.
+	at atomic.Int64
+
+	mu sync.Mutex
+	// ch is closed on the next update. Nil if nobody waits.
+	ch chan struct{}
+}
+
+func (a *appliedIndex) load() int64 {
+	return a.idx.Load()
+}
+
+func (a *appliedIndex) loadTime() time.Time {
+	at := a.at.Load()
+	if at == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, at)
+}
+
+func (a *appliedIndex) store(idx int64) {
+	a.idx.Store(idx)
+	a.at.Store(time.Now().UnixNano())
+
+	a.mu.Lock()
+	if a.ch != nil {
+		close(a.ch)
+		a.ch = nil
+	}
+	a.mu.Unlock()
+}
+
+func (a *appliedIndex) wait(ctx context.Context, idx int64) error {
+	for {
+		a.mu.Lock()
+		if a.idx.Load() >= idx {
+			a.mu.Unlock()
+			return nil
+		}
+		if a.ch == nil {
+			a.ch = make(chan struct{})
+		}
+		ch := a.ch
+		a.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package raft
+
+import (
+	"bytes"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// changeLog keeps changes applied by the state machine for change data capture together with
+// replicated delivery cursors of registered sinks. Changes are kept until every sink acknowledges
+// them, so retention depends on replicated state only and is the same on every replica.
+type changeLog struct {
+	mu      sync.Mutex
+	changes []fsmport.Change
+	// floor is the log index up to which changes are no longer kept.
+	floor int64
+	// next is closed once a change is recorded or the log is reset, nil if nobody waits for it.
+	next chan struct{}
+	// cursors of registered sinks, changes aren't kept while there are none.
+	cursors map[string]int64
+}
+
+// record keeps the command applied at the index if there are sinks to deliver it to.
+func (c *changeLog) record(index, at int64, cmd *fsm_v1.Command, result []byte, deleted []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if index <= c.floor {
+		return
+	}
+	if len(c.cursors) == 0 {
+		c.floor = index
+		return
+	}
+	var seq int
+	if n := len(c.changes); n > 0 && c.changes[n-1].Index == index {
+		seq = c.changes[n-1].Seq + 1
+	}
+	c.changes = append(c.changes, fsmport.Change{
+		Index: index, Seq: seq, Time: at, Command: cmd, Result: result, Deleted: deleted,
+	})
+	c.wake()
+}
+
+// since returns changes applied after the index, see fsmport.ChangeFeed.
+func (c *changeLog) since(after int64, limit int) ([]fsmport.Change, <-chan struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.next == nil {
+		c.next = make(chan struct{})
+	}
+	if after < c.floor {
+		return nil, c.next, fsmport.ErrChangesCompacted
+	}
+	limit = max(limit, 1)
+	start := sort.Search(len(c.changes), func(i int) bool { return c.changes[i].Index > after })
+	end := start
+	// Changes of one entry are returned together, so a cursor never points into the middle of an entry.
+	for end < len(c.changes) && (end-start < limit || c.changes[end].Index == c.changes[end-1].Index) {
+		end++
+	}
+	return slices.Clone(c.changes[start:end]), c.next, nil
+}
+
+// register replaces the set of sinks at the index. Added sinks get changes applied after the index.
+func (c *changeLog) register(index int64, sinks []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cursors := make(map[string]int64, len(sinks))
+	for _, sink := range sinks {
+		cur, ok := c.cursors[sink]
+		if !ok {
+			cur = index
+		}
+		cursors[sink] = cur
+	}
+	c.cursors = cursors
+	if len(cursors) == 0 {
+		c.changes = nil
+		c.floor = max(c.floor, index)
+	}
+	c.trim()
+}
+
+// reset replaces kept changes and cursors with ones from a snapshot taken at the index.
+func (c *changeLog) reset(index int64, cursors []*fsm_v1.CdcCursor, changes []*fsm_v1.CdcChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cursors = make(map[string]int64, len(cursors))
+	for _, cur := range cursors {
+		c.cursors[cur.Sink] = cur.Index
+	}
+	c.changes = make([]fsmport.Change, 0, len(changes))
+	for _, ch := range changes {
+		c.changes = append(c.changes, fsmport.Change{
+			Index: ch.Index, Seq: int(ch.Seq), Time: ch.Time, Command: ch.Command, Result: ch.Result, Deleted: ch.Deleted,
+		})
+	}
+	// Snapshots keep changes after the lowest cursor, older ones were delivered everywhere.
+	c.floor = index
+	for _, cur := range c.cursors {
+		c.floor = min(c.floor, cur)
+	}
+	c.wake()
+}
+
+// ack moves the cursor of the sink forward and drops changes delivered to every sink.
+// Cursors never go back, so a stale ack is ignored, as well as an ack of an unregistered sink.
+func (c *changeLog) ack(cur *fsm_v1.CdcCursor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, ok := c.cursors[cur.GetSink()]
+	if !ok {
+		return
+	}
+	c.cursors[cur.GetSink()] = max(old, cur.GetIndex())
+	c.trim()
+}
+
+// trim drops changes up to the lowest cursor. Must be called with the lock held.
+func (c *changeLog) trim() {
+	if len(c.cursors) == 0 {
+		return
+	}
+	low := int64(math.MaxInt64)
+	for _, cur := range c.cursors {
+		low = min(low, cur)
+	}
+	i := sort.Search(len(c.changes), func(i int) bool { return c.changes[i].Index > low })
+	if i > 0 {
+		c.changes = slices.Delete(c.changes, 0, i)
+	}
+	c.floor = max(c.floor, low)
+}
+
+func (c *changeLog) cursor(sink string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	idx, ok := c.cursors[sink]
+	return idx, ok
+}
+
+func (c *changeLog) sinks() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Sorted(maps.Keys(c.cursors))
+}
+
+func (c *changeLog) backlog() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.changes)
+}
+
+// snapshot returns cursors ordered by sink and kept changes.
+func (c *changeLog) snapshot() ([]*fsm_v1.CdcCursor, []*fsm_v1.CdcChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cursors := make([]*fsm_v1.CdcCursor, 0, len(c.cursors))
+	for sink, idx := range c.cursors {
+		cursors = append(cursors, &fsm_v1.CdcCursor{Sink: sink, Index: idx})
+	}
+	slices.SortFunc(cursors, func(a, b *fsm_v1.CdcCursor) int { return strings.Compare(a.Sink, b.Sink) })
+
+	changes := make([]*fsm_v1.CdcChange, 0, len(c.changes))
+	for _, ch := range c.changes {
+		changes = append(changes, &fsm_v1.CdcChange{
+			Index: ch.Index, Seq: int32(ch.Seq), Time: ch.Time, Command: ch.Command, Result: ch.Result, Deleted: ch.Deleted,
+		})
+	}
+	return cursors, changes
+}
+
+// wake must be called with the lock held.
+func (c *changeLog) wake() {
+	if c.next != nil {
+		close(c.next)
+		c.next = nil
+	}
+}
+
+// isChange reports whether the command applied without an error changed the state visible to clients.
+// Commands keeping the cluster itself running and conditional writes which were skipped aren't changes.
+func isChange(cmd *fsm_v1.Command, res ftr.Result) bool {
+	switch cmd.Command.(type) {
+	case *fsm_v1.Command_Tick, *fsm_v1.Command_Membership, *fsm_v1.Command_MemberSet, *fsm_v1.Command_MemberRemove,
+		*fsm_v1.Command_CdcAck, *fsm_v1.Command_CdcSinks, *fsm_v1.Command_Batch, *fsm_v1.Command_Sealed:
+		return false
+	case *fsm_v1.Command_Set, *fsm_v1.Command_Expire:
+		return !bytes.Equal(res.Data, resultSkipped)
+	}
+	return cmd.Command != nil
+}
+
+// changeResult returns result data of commands whose result is the value they wrote.
+func changeResult(cmd *fsm_v1.Command, res ftr.Result) []byte {
+	switch cmd.Command.(type) {
+	case *fsm_v1.Command_Incr, *fsm_v1.Command_Counter, *fsm_v1.Command_Hincrby, *fsm_v1.Command_Zincrby:
+		return res.Data
+	}
+	return nil
+}
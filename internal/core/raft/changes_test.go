@@ -0,0 +1,207 @@
+package raft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func putChange(key, value string) *fsm_v1.Command {
+	return &fsm_v1.Command{Command: &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: key, Value: value}}}
+}
+
+func ackCmd(sink string, index int64) *fsm_v1.Command {
+	return &fsm_v1.Command{Command: &fsm_v1.Command_CdcAck{CdcAck: &fsm_v1.CdcAckCommand{
+		Cursor: &fsm_v1.CdcCursor{Sink: sink, Index: index},
+	}}}
+}
+
+func changeIndexes(changes []fsmport.Change) [][2]int64 {
+	out := make([][2]int64, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, [2]int64{c.Index, int64(c.Seq)})
+	}
+	return out
+}
+
+func sinksCmd(sinks ...string) *fsm_v1.Command {
+	return &fsm_v1.Command{Command: &fsm_v1.Command_CdcSinks{CdcSinks: &fsm_v1.CdcSinksCommand{Sinks: sinks}}}
+}
+
+func TestFSM_Changes(t *testing.T) {
+	t.Run("records applied changes", func(t *testing.T) {
+		f, apply := newStoreFSM(t, withCDCSinks("analytics"))
+		require.NoError(t, apply(1, putChange("a", "1")).Err)
+		apply(2, &fsm_v1.Command{Now: 5000, Command: &fsm_v1.Command_Tick{Tick: &fsm_v1.TickCommand{}}})
+		skipped := &fsm_v1.Command{Command: &fsm_v1.Command_Set{Set: &fsm_v1.SetCommand{
+			Key: "a", Value: "2", Condition: fsm_v1.SetCondition_SET_CONDITION_NOT_EXISTS,
+		}}}
+		assert.Equal(t, resultSkipped, apply(3, skipped).Data)
+		assert.Error(t, apply(4, lockCmd("a", 1, 1000)).Err)
+
+		batch := &fsm_v1.BatchCommand{}
+		for _, cmd := range []*fsm_v1.Command{putChange("b", "1"), putChange("c", "1")} {
+			data, err := proto.Marshal(cmd)
+			require.NoError(t, err)
+			batch.Commands = append(batch.Commands, data)
+		}
+		apply(5, &fsm_v1.Command{Command: &fsm_v1.Command_Batch{Batch: batch}})
+
+		changes, _, err := f.Changes(0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, [][2]int64{{1, 0}, {5, 0}, {5, 1}}, changeIndexes(changes))
+		assert.Equal(t, "c", changes[2].Command.GetPut().GetKey())
+		assert.Equal(t, int64(5000), changes[1].Time)
+		assert.Equal(t, 3, f.Backlog())
+
+		changes, _, _ = f.Changes(1, 1)
+		assert.Equal(t, [][2]int64{{5, 0}, {5, 1}}, changeIndexes(changes), "entries are not split")
+	})
+
+	t.Run("records applied part of failed commands", func(t *testing.T) {
+		// The lock takes the whole quota, so a longer token of the next holder doesn't fit.
+		f, apply := newStoreFSM(t, withCDCSinks("analytics"), withQuotas(cfg.QuotaCfg{Prefix: "lock", MaxBytes: 5}))
+
+		mset := &fsm_v1.Command{Command: &fsm_v1.Command_Mset{Mset: &fsm_v1.MSetCommand{Puts: []*fsm_v1.PutCommand{
+			{Key: "a", Value: "1"}, {Key: "b", Value: strings.Repeat("v", 101)}, {Key: "c", Value: "1"},
+		}}}}
+		assert.ErrorIs(t, apply(1, mset).Err, pstore.ErrValueTooLarge)
+
+		apply(2, grantCmd(100, 1000))
+		require.NoError(t, apply(3, lockCmd("lock", 2, 1000)).Err)
+		apply(10, grantCmd(100, 1150))
+		assert.ErrorIs(t, apply(11, lockCmd("lock", 10, 1200)).Err, pstore.ErrQuotaExceeded)
+
+		changes, _, err := f.Changes(0, 10)
+		require.NoError(t, err)
+		require.Equal(t, [][2]int64{{1, 0}, {2, 0}, {3, 0}, {10, 0}, {11, 0}}, changeIndexes(changes))
+		var stored []string
+		for _, put := range changes[0].Command.GetMset().GetPuts() {
+			stored = append(stored, put.Key)
+		}
+		assert.Equal(t, []string{"a", "c"}, stored, "rejected keys aren't recorded")
+		assert.Equal(t, int64(2), changes[4].Command.GetLeaseRevoke().GetId(), "expired lease was revoked")
+		assert.Equal(t, []string{"lock"}, changes[4].Deleted)
+	})
+
+	t.Run("wakes up waiters", func(t *testing.T) {
+		f, apply := newStoreFSM(t, withCDCSinks("analytics"))
+		changes, next, _ := f.Changes(0, 10)
+		assert.Empty(t, changes)
+
+		apply(1, putChange("a", "1"))
+		select {
+		case <-next:
+		default:
+			t.Fatal("waiter is not woken up")
+		}
+	})
+
+	t.Run("keeps changes until every sink acknowledges them", func(t *testing.T) {
+		f, apply := newStoreFSM(t, withCDCSinks("analytics", "files"))
+		for i := range int64(3) {
+			apply(i+1, putChange("a", "1"))
+		}
+
+		apply(4, ackCmd("analytics", 3))
+		assert.Equal(t, 3, f.Backlog())
+		apply(5, ackCmd("files", 2))
+		apply(6, ackCmd("files", 1))
+		assert.Equal(t, 1, f.Backlog())
+
+		cur, ok := f.Cursor("files")
+		assert.True(t, ok)
+		assert.Equal(t, int64(2), cur, "cursors don't go back")
+
+		changes, _, err := f.Changes(2, 10)
+		require.NoError(t, err)
+		assert.Equal(t, [][2]int64{{3, 0}}, changeIndexes(changes))
+		_, _, err = f.Changes(1, 10)
+		assert.ErrorIs(t, err, fsmport.ErrChangesCompacted)
+	})
+
+	t.Run("not kept without sinks", func(t *testing.T) {
+		f, apply := newStoreFSM(t)
+		apply(1, putChange("a", "1"))
+		apply(2, ackCmd("analytics", 1))
+
+		assert.Zero(t, f.Backlog())
+		assert.Empty(t, f.Sinks())
+		_, ok := f.Cursor("analytics")
+		assert.False(t, ok, "unregistered sinks are not acknowledged")
+	})
+
+	t.Run("registers sinks", func(t *testing.T) {
+		f, apply := newStoreFSM(t)
+		apply(1, putChange("a", "1"))
+		apply(2, sinksCmd("analytics"))
+		apply(3, putChange("b", "1"))
+
+		assert.Equal(t, []string{"analytics"}, f.Sinks())
+		cur, _ := f.Cursor("analytics")
+		assert.Equal(t, int64(2), cur, "added sinks get changes after registration")
+		changes, _, err := f.Changes(cur, 10)
+		require.NoError(t, err)
+		assert.Equal(t, [][2]int64{{3, 0}}, changeIndexes(changes))
+
+		apply(4, sinksCmd("analytics", "files"))
+		cur, _ = f.Cursor("files")
+		assert.Equal(t, int64(4), cur)
+		assert.Equal(t, 1, f.Backlog(), "changes are kept for the lagging sink")
+
+		apply(5, sinksCmd("files"))
+		assert.Equal(t, []string{"files"}, f.Sinks())
+		assert.Zero(t, f.Backlog(), "changes of removed sinks are dropped")
+	})
+
+	t.Run("records keys deleted as a side effect", func(t *testing.T) {
+		f, apply := newStoreFSM(t, withCDCSinks("audit"))
+		apply(1, grantCmd(100, 1000))
+		apply(2, grantCmd(100, 1000))
+		apply(3, lockCmd("a", 1, 1000))
+		apply(4, lockCmd("b", 2, 1000))
+		apply(5, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseRevoke{LeaseRevoke: &fsm_v1.LeaseRevokeCommand{Id: 1, Now: 1010}}})
+		apply(6, &fsm_v1.Command{Command: &fsm_v1.Command_Reap{Reap: &fsm_v1.ReapCommand{Leases: []int64{2}, Now: 1200}}})
+		apply(7, putChange("c", "1"))
+
+		changes, _, err := f.Changes(4, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		assert.Equal(t, []string{"a"}, changes[0].Deleted)
+		assert.Equal(t, []string{"b"}, changes[1].Deleted)
+		assert.Empty(t, changes[2].Deleted)
+	})
+
+	t.Run("undelivered changes survive snapshots", func(t *testing.T) {
+		src, apply := newStoreFSM(t, withCDCSinks("analytics", "files"))
+		for i := range int64(3) {
+			apply(i+1, putChange("a", "1"))
+		}
+		apply(4, ackCmd("analytics", 3))
+		apply(5, ackCmd("files", 1))
+		src.applied.store(5)
+
+		data, _, err := src.Snapshot()
+		require.NoError(t, err)
+
+		dst, _ := newStoreFSM(t)
+		require.NoError(t, dst.Restore(data))
+		assert.Equal(t, []string{"analytics", "files"}, dst.Sinks())
+		cur, _ := dst.Cursor("files")
+		assert.Equal(t, int64(1), cur)
+
+		changes, _, err := dst.Changes(cur, 10)
+		require.NoError(t, err)
+		assert.Equal(t, [][2]int64{{2, 0}, {3, 0}}, changeIndexes(changes))
+		assert.Equal(t, "a", changes[0].Command.GetPut().GetKey())
+		_, _, err = dst.Changes(0, 10)
+		assert.ErrorIs(t, err, fsmport.ErrChangesCompacted)
+	})
+}
@@ -0,0 +1,33 @@
+package raft
+
+import "sync/atomic"
+
+// clusterClock is the time replicas agree on. It's advanced only by times stamped into applied
+// commands by the leader and never goes back, so time-dependent logic of the fsm gives the same
+// results on every replica regardless of its own clock.
+type clusterClock struct {
+	// now is unix time in milliseconds, changed only by the apply loop.
+	now atomic.Int64
+}
+
+// advance moves the clock forward to the stamp. Zero stamp of a command proposed by an older node is ignored.
+func (c *clusterClock) advance(stamp int64) {
+	if stamp > c.now.Load() {
+		c.now.Store(stamp)
+	}
+}
+
+// restore sets the clock to the time from a snapshot.
+func (c *clusterClock) restore(now int64) {
+	c.now.Store(now)
+}
+
+func (c *clusterClock) load() int64 {
+	return c.now.Load()
+}
+
+// at returns the time a command carrying now is applied at. It's the cluster time unless now is later,
+// so a leader with a slower clock can't bring expired keys and leases back.
+func (c *clusterClock) at(now int64) int64 {
+	return max(now, c.now.Load())
+}
@@ -0,0 +1,118 @@
+package raft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStampingProposer(t *testing.T) {
+	r := &recordingRaft{StubRaft: rmocks.NewStubRaft(nil, true, 0)}
+	p := NewStampingProposer(NewProposer(r, NewApplyFuture()))
+	p.now = func() time.Time { return time.UnixMilli(1234) }
+
+	cmd := putCmd(t, "k")
+	orig := append([]byte(nil), cmd...)
+	_, err := p.Propose(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, orig, cmd)
+
+	var got fsm_v1.Command
+	require.NoError(t, proto.Unmarshal(r.entries[0], &got))
+	assert.Equal(t, int64(1234), got.GetNow())
+	assert.Equal(t, "k", got.GetPut().GetKey())
+}
+
+func TestFSM_ClusterTimeWithSkewedLeaders(t *testing.T) {
+	r := &recordingRaft{StubRaft: rmocks.NewStubRaft(nil, true, 0)}
+	// Leader B is elected after leader A, but its clock is 5 seconds behind.
+	clockA, clockB := int64(10_000), int64(5_000)
+	leaderA := NewStampingProposer(NewProposer(r, NewApplyFuture()))
+	leaderA.now = func() time.Time { return time.UnixMilli(clockA) }
+	leaderB := NewStampingProposer(NewProposer(r, NewApplyFuture()))
+	leaderB.now = func() time.Time { return time.UnixMilli(clockB) }
+
+	propose := func(p *stampingProposer, cmd *fsm_v1.Command) {
+		data, err := proto.Marshal(cmd)
+		require.NoError(t, err)
+		_, err = p.Propose(context.Background(), data)
+		require.NoError(t, err)
+	}
+	tick := &fsm_v1.Command{Command: &fsm_v1.Command_Tick{Tick: &fsm_v1.TickCommand{}}}
+
+	propose(leaderA, &fsm_v1.Command{Command: &fsm_v1.Command_Set{Set: &fsm_v1.SetCommand{
+		Key: "k", Value: "v", ExpiresAt: clockA + 500, Now: clockA,
+	}}})
+	propose(leaderA, grantCmd(1000, clockA))
+	clockA += 600
+	propose(leaderA, tick)
+	// The key expired by the cluster time although it's alive by the clock of leader B.
+	propose(leaderB, &fsm_v1.Command{Command: &fsm_v1.Command_Incr{Incr: &fsm_v1.IncrCommand{Key: "k", Delta: 1, Now: clockB}}})
+	propose(leaderB, lockCmd("lock", 2, clockB))
+	clockA += 600
+	propose(leaderA, tick)
+	// Ticks of the slower leader don't move the cluster time back.
+	propose(leaderB, tick)
+
+	replicas := make([]*storeFSM, 2)
+	results := make([][]ftr.Result, 2)
+	for i := range replicas {
+		replicas[i], _ = newStoreFSM(t)
+		for idx, data := range r.entries {
+			results[i] = append(results[i], replicas[i].applyCommand(int64(idx+1), data))
+		}
+	}
+
+	assert.Equal(t, results[0], results[1])
+	assert.Equal(t, ftr.Result{Data: []byte("1")}, results[0][3])
+	assert.Equal(t, ftr.Result{Data: []byte("5")}, results[0][4])
+	for _, f := range replicas {
+		assert.Equal(t, int64(11_200), f.ClusterTime())
+		val, err := f.store.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "1", val)
+		// The lease granted at 10000 with 1s TTL has expired by the cluster time.
+		assert.Equal(t, []int64{2}, f.ExpiredLeases(f.ClusterTime(), 10))
+	}
+}
+
+func TestFSM_CommandsApplyAtEnvelopeTime(t *testing.T) {
+	r := &recordingRaft{StubRaft: rmocks.NewStubRaft(nil, true, 0)}
+	p := NewStampingProposer(NewProposer(r, NewApplyFuture()))
+	p.now = func() time.Time { return time.UnixMilli(10_000) }
+
+	// Handlers leave the time of a command unset, the stamp of the proposing leader is the only one.
+	data, err := proto.Marshal(&fsm_v1.Command{Command: &fsm_v1.Command_LeaseGrant{
+		LeaseGrant: &fsm_v1.LeaseGrantCommand{TtlMs: 1000},
+	}})
+	require.NoError(t, err)
+	_, err = p.Propose(context.Background(), data)
+	require.NoError(t, err)
+
+	f, _ := newStoreFSM(t)
+	res := f.applyCommand(1, r.entries[0])
+	require.NoError(t, res.Err)
+	assert.Equal(t, int64(10_000), f.ClusterTime())
+	assert.Empty(t, f.ExpiredLeases(10_999, 10))
+	assert.Equal(t, []int64{1}, f.ExpiredLeases(11_000, 10))
+}
+
+func TestSnapshot_KeepsClusterTime(t *testing.T) {
+	src, apply := newStoreFSM(t)
+	apply(1, &fsm_v1.Command{Now: 7000, Command: &fsm_v1.Command_Tick{Tick: &fsm_v1.TickCommand{}}})
+	require.Equal(t, int64(7000), src.ClusterTime())
+
+	data, _, err := src.Snapshot()
+	require.NoError(t, err)
+
+	dst, _ := newStoreFSM(t)
+	require.NoError(t, dst.Restore(data))
+	assert.Equal(t, int64(7000), dst.ClusterTime())
+}
@@ -2,9 +2,18 @@ package raft
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	"github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/encryption"
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
 	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	"github.com/shrtyk/kv-store/pkg/logger"
@@ -13,28 +22,83 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-var _ raftapi.FSM = (*storeFSM)(nil)
+var (
+	_ raftapi.FSM            = (*storeFSM)(nil)
+	_ fsmport.StatusReporter = (*storeFSM)(nil)
+	_ fsmport.AppliedIndex   = (*storeFSM)(nil)
+	_ fsmport.PushWatcher    = (*storeFSM)(nil)
+	_ fsmport.LeaseReader    = (*storeFSM)(nil)
+	_ fsmport.ClusterClock   = (*storeFSM)(nil)
+	_ fsmport.ChangeFeed     = (*storeFSM)(nil)
+)
+
+var (
+	ErrUnknownCommand = errors.New("fsm: unknown command type")
+	// ErrInvalidLeaseTTL is returned for a lease granted without a positive TTL.
+	ErrInvalidLeaseTTL = errors.New("fsm: lease ttl must be positive")
+	// ErrLeaseExists is returned for a lease granted with an id of an existing lease.
+	ErrLeaseExists = errors.New("fsm: lease already exists")
+	// ErrNamespacesDisabled is returned for namespace commands applied by a state machine without namespaces.
+	ErrNamespacesDisabled = errors.New("fsm: namespaces are disabled")
+)
 
 type storeFSM struct {
 	futuresStore ftr.FuturesStore
 	log          *slog.Logger
 	store        store.Store
-	appCh        <-chan *raftapi.ApplyMessage
+	// namespaces keeps stores of namespaces other than the default one. May be nil.
+	namespaces  store.Namespaces
+	appCh       <-chan *raftapi.ApplyMessage
+	compression Compression
+	// cipher encrypts snapshots and decrypts sealed commands. Nil if encryption is disabled.
+	cipher encryption.Cipher
+	// membership is updated with committed membership changes. May be nil.
+	membership cluster.Membership
+	// members is the last committed membership, nil if it was never replicated.
+	members atomic.Pointer[fsm_v1.MembershipCommand]
+
+	// mu serializes applying commands with taking and installing snapshots,
+	// so a snapshot holds exactly the state at its applied index.
+	mu      sync.Mutex
+	applied appliedIndex
+	pushes  pushWatchers
+	leases  leaseTable
+	clock   clusterClock
+	changes changeLog
+	// deleted collects keys deleted as a side effect of the command being applied, e.g. keys of revoked leases.
+	deleted []string
+	// partial is the part of a failed command which was applied anyway, e.g. keys an mset stored before one
+	// was rejected. It's recorded as the change in place of the command.
+	partial *fsm_v1.Command
+	// appliedTerm is a term of the last installed snapshot. raft-core doesn't report
+	// terms of applied commands, so it's a lower bound of the applied entry's term.
+	appliedTerm atomic.Int64
 
-	lastAppliedIdx int64
+	// failed is set when snapshot restore failed and the store content can't be trusted.
+	failed      atomic.Bool
+	restoring   atomic.Bool
+	lastRestore atomic.Pointer[fsmport.RestoreStatus]
 }
 
 func NewFSM(
 	log *slog.Logger,
 	store store.Store,
+	namespaces store.Namespaces,
 	futureApplier ftr.FuturesStore,
 	appCh <-chan *raftapi.ApplyMessage,
+	compression Compression,
+	cipher encryption.Cipher,
+	membership cluster.Membership,
 ) raftapi.FSM {
 	return &storeFSM{
 		log:          log,
 		store:        store,
+		namespaces:   namespaces,
 		appCh:        appCh,
 		futuresStore: futureApplier,
+		compression:  compression,
+		cipher:       cipher,
+		membership:   membership,
 	}
 }
 
@@ -47,67 +111,926 @@ func (f *storeFSM) Start(ctx context.Context) {
 			return
 		case msg := <-f.appCh:
 			if msg.CommandValid {
-				f.applyCommand(msg.Command)
-				f.lastAppliedIdx = msg.CommandIndex
-				f.futuresStore.Fulfill(msg.CommandIndex)
+				if f.failed.Load() {
+					// Applying on top of unknown state would silently diverge from other replicas.
+					f.futuresStore.Fulfill(msg.CommandIndex, ftr.Result{Err: fsmport.ErrStateUnavailable})
+					continue
+				}
+				f.mu.Lock()
+				res := f.applyCommand(msg.CommandIndex, msg.Command)
+				f.applied.store(msg.CommandIndex)
+				f.mu.Unlock()
+				f.futuresStore.Fulfill(msg.CommandIndex, res)
 			}
 			if msg.SnapshotValid {
-				if err := f.Restore(msg.Snapshot); err != nil {
-					f.log.Error("failed to restore snapshot", logger.ErrorAttr(err))
-					panic("failed to restore snapshot: " + err.Error())
+				if err := f.restore(msg.Snapshot, msg.SnapshotIndex, msg.SnapshotTerm); err != nil {
+					f.log.Error(
+						"refused to install snapshot, node stops applying commands until a valid snapshot is installed or restart",
+						slog.Int64("snapshot_index", msg.SnapshotIndex),
+						logger.ErrorAttr(err))
 				}
 			}
 		}
 	}
 }
 
-func (f *storeFSM) applyCommand(data []byte) {
+// applyCommand applies the command at the log index to the store. Returned result is
+// delivered to the client waiting for the command's log index.
+func (f *storeFSM) applyCommand(index int64, data []byte) ftr.Result {
 	var cmd fsm_v1.Command
 	if err := proto.Unmarshal(data, &cmd); err != nil {
 		f.log.Error("failed to unmarshal command", logger.ErrorAttr(err))
-		return
+		return ftr.Result{Err: err}
+	}
+
+	if c, ok := cmd.Command.(*fsm_v1.Command_Batch); ok {
+		f.log.Debug("applying batch command", slog.Int("size", len(c.Batch.Commands)))
+		f.clock.advance(cmd.Now)
+		res := ftr.Result{Batch: make([]ftr.Result, len(c.Batch.Commands))}
+		for i, data := range c.Batch.Commands {
+			var sub fsm_v1.Command
+			if err := proto.Unmarshal(data, &sub); err != nil {
+				f.log.Error("failed to unmarshal batched command", logger.ErrorAttr(err))
+				res.Batch[i].Err = err
+				continue
+			}
+			res.Batch[i] = f.apply(index, &sub)
+		}
+		return res
+	}
+	return f.apply(index, &cmd)
+}
+
+// apply applies a single command. Times carried by commands are taken no earlier than the cluster time.
+func (f *storeFSM) apply(index int64, cmd *fsm_v1.Command) ftr.Result {
+	f.clock.advance(cmd.Now)
+	if cmd.Namespace != "" && isLeaseCommand(cmd) {
+		return ftr.Result{Err: fsmport.ErrNamespacedLease}
+	}
+	ks, err := f.keyspace(cmd.Namespace)
+	if err != nil {
+		return ftr.Result{Err: err}
 	}
 
+	var res ftr.Result
 	switch c := cmd.Command.(type) {
 	case *fsm_v1.Command_Put:
 		f.log.Debug("applying put command", slog.String("key", c.Put.Key))
-		if err := f.store.Put(c.Put.Key, c.Put.Value); err != nil {
-			f.log.Error("failed to apply put command", logger.ErrorAttr(err))
+		if err := ks.store.Put(c.Put.Key, c.Put.Value); err != nil {
+			f.log.Debug("put command rejected", logger.ErrorAttr(err))
+			res.Err = err
+			break
 		}
+		ks.leases.detach(c.Put.Key)
 	case *fsm_v1.Command_Delete:
 		f.log.Debug("applying delete command", slog.String("key", c.Delete.Key))
-		if err := f.store.Delete(c.Delete.Key); err != nil {
-			f.log.Error("failed to apply delete command", logger.ErrorAttr(err))
+		if err := ks.store.Delete(c.Delete.Key); err != nil {
+			f.log.Debug("delete command rejected", logger.ErrorAttr(err))
+			res.Err = err
+			break
+		}
+		ks.leases.detach(c.Delete.Key)
+	case *fsm_v1.Command_Set:
+		f.log.Debug("applying set command", slog.String("key", c.Set.Key))
+		res = f.applySet(ks, c.Set)
+	case *fsm_v1.Command_Incr:
+		f.log.Debug("applying incr command", slog.String("key", c.Incr.Key))
+		res = f.applyIncr(ks, c.Incr)
+	case *fsm_v1.Command_Expire:
+		f.log.Debug("applying expire command", slog.String("key", c.Expire.Key))
+		res = f.applyExpire(ks, c.Expire)
+	case *fsm_v1.Command_Mset:
+		f.log.Debug("applying mset command", slog.Int("keys", len(c.Mset.Puts)))
+		res = f.applyMSet(ks, c.Mset)
+	case *fsm_v1.Command_Del:
+		f.log.Debug("applying del command", slog.Int("keys", len(c.Del.Keys)))
+		res = f.applyDel(ks, c.Del)
+	case *fsm_v1.Command_Counter:
+		f.log.Debug("applying counter command", slog.String("key", c.Counter.Key))
+		res = f.applyCounter(ks, c.Counter)
+	case *fsm_v1.Command_Hset:
+		f.log.Debug("applying hset command", slog.String("key", c.Hset.Key))
+		added, err := ks.store.HSet(c.Hset.Key, c.Hset.Fields, f.clock.at(c.Hset.Now))
+		res = countResult(added, err)
+	case *fsm_v1.Command_Hdel:
+		f.log.Debug("applying hdel command", slog.String("key", c.Hdel.Key))
+		deleted, err := ks.store.HDel(c.Hdel.Key, c.Hdel.Fields, f.clock.at(c.Hdel.Now))
+		res = countResult(deleted, err)
+	case *fsm_v1.Command_Hincrby:
+		f.log.Debug("applying hincrby command", slog.String("key", c.Hincrby.Key))
+		n, err := ks.store.HIncrBy(c.Hincrby.Key, c.Hincrby.Field, c.Hincrby.Delta, f.clock.at(c.Hincrby.Now))
+		if err != nil {
+			res.Err = err
+			break
 		}
+		res.Data = []byte(strconv.FormatInt(n, 10))
+	case *fsm_v1.Command_Push:
+		f.log.Debug("applying push command", slog.String("key", c.Push.Key))
+		length, err := ks.store.Push(c.Push.Key, c.Push.Values, c.Push.Left, f.clock.at(c.Push.Now))
+		if err == nil && len(c.Push.Values) > 0 {
+			f.pushes.notify(c.Push.Key)
+		}
+		res = countResult(length, err)
+	case *fsm_v1.Command_Pop:
+		f.log.Debug("applying pop command", slog.String("key", c.Pop.Key))
+		res = f.applyPop(ks, c.Pop)
+	case *fsm_v1.Command_Zadd:
+		f.log.Debug("applying zadd command", slog.String("key", c.Zadd.Key))
+		res = countResult(ks.store.ZAdd(c.Zadd.Key, fromPBMembers(c.Zadd.Members), f.clock.at(c.Zadd.Now)))
+	case *fsm_v1.Command_Zrem:
+		f.log.Debug("applying zrem command", slog.String("key", c.Zrem.Key))
+		res = countResult(ks.store.ZRem(c.Zrem.Key, c.Zrem.Members, f.clock.at(c.Zrem.Now)))
+	case *fsm_v1.Command_Zincrby:
+		f.log.Debug("applying zincrby command", slog.String("key", c.Zincrby.Key))
+		score, err := ks.store.ZIncrBy(c.Zincrby.Key, c.Zincrby.Member, c.Zincrby.Delta, f.clock.at(c.Zincrby.Now))
+		if err != nil {
+			res.Err = err
+			break
+		}
+		res.Data = []byte(strconv.FormatFloat(score, 'g', -1, 64))
+	case *fsm_v1.Command_Zpopmin:
+		f.log.Debug("applying zpopmin command", slog.String("key", c.Zpopmin.Key))
+		res = f.applyZPopMin(ks, c.Zpopmin)
+	case *fsm_v1.Command_JsonPatch:
+		f.log.Debug("applying json patch command", slog.String("key", c.JsonPatch.Key))
+		res = f.applyJSONPatch(ks, c.JsonPatch)
+	case *fsm_v1.Command_Flush:
+		f.log.Info("applying flush command", slog.Int64("expires_at", c.Flush.ExpiresAt))
+		now := f.clock.at(c.Flush.Now)
+		ks.store.FlushAll(c.Flush.ExpiresAt, now)
+		if c.Flush.ExpiresAt <= now {
+			ks.leases.detachAll()
+		}
+	case *fsm_v1.Command_Reap:
+		now := f.clock.at(c.Reap.Now)
+		deleted := ks.store.DeleteExpired(c.Reap.Keys, now)
+		f.deleted = append(f.deleted, deleted...)
+		ks.leases.detach(c.Reap.Keys...)
+		revoked := 0
+		for _, id := range c.Reap.Leases {
+			// A keepalive could have been applied after the lease was found expired.
+			if !f.leases.alive(id, now) {
+				if _, ok := f.revokeLease(id); ok {
+					revoked++
+				}
+			}
+		}
+		f.log.Debug("applied reap command", slog.Int("deleted", len(deleted)), slog.Int("revoked_leases", revoked))
+	case *fsm_v1.Command_LeaseGrant:
+		f.log.Debug("applying lease grant command", slog.Int64("ttl_ms", c.LeaseGrant.TtlMs))
+		if c.LeaseGrant.TtlMs <= 0 {
+			res.Err = ErrInvalidLeaseTTL
+			break
+		}
+		id := f.leases.nextID(index)
+		if err := f.leases.grant(id, c.LeaseGrant.TtlMs, f.clock.at(c.LeaseGrant.Now)); err != nil {
+			res.Err = err
+			break
+		}
+		res.Data = []byte(strconv.FormatInt(id, 10))
+	case *fsm_v1.Command_LeaseKeepAlive:
+		f.log.Debug("applying lease keepalive command", slog.Int64("lease", c.LeaseKeepAlive.Id))
+		deadline, err := f.leases.keepAlive(c.LeaseKeepAlive.Id, f.clock.at(c.LeaseKeepAlive.Now))
+		if err != nil {
+			res.Err = err
+			break
+		}
+		res.Data = []byte(strconv.FormatInt(deadline, 10))
+	case *fsm_v1.Command_LeaseRevoke:
+		f.log.Debug("applying lease revoke command", slog.Int64("lease", c.LeaseRevoke.Id))
+		deleted, ok := f.revokeLease(c.LeaseRevoke.Id)
+		if !ok {
+			res.Err = fsmport.ErrLeaseNotFound
+			break
+		}
+		res.Data = []byte(strconv.Itoa(deleted))
+	case *fsm_v1.Command_LeaseAttach:
+		f.log.Debug("applying lease attach command", slog.Int64("lease", c.LeaseAttach.Id))
+		res = f.applyLeaseAttach(c.LeaseAttach)
+	case *fsm_v1.Command_Lock:
+		f.log.Debug("applying lock command", slog.String("key", c.Lock.Key), slog.Int64("lease", c.Lock.Lease))
+		res = f.applyLock(index, c.Lock)
+	case *fsm_v1.Command_Unlock:
+		f.log.Debug("applying unlock command", slog.String("key", c.Unlock.Key), slog.Int64("lease", c.Unlock.Lease))
+		res = f.applyUnlock(c.Unlock)
+	case *fsm_v1.Command_NamespaceCreate:
+		f.log.Info("applying namespace create command", slog.String("namespace", c.NamespaceCreate.GetNamespace().GetName()))
+		res.Err = f.createNamespace(c.NamespaceCreate.GetNamespace())
+	case *fsm_v1.Command_NamespaceDelete:
+		f.log.Info("applying namespace delete command", slog.String("namespace", c.NamespaceDelete.Name))
+		res.Err = f.deleteNamespace(c.NamespaceDelete.Name)
+	case *fsm_v1.Command_Tick:
+		f.log.Debug("applied tick command", slog.Int64("cluster_time", f.clock.load()))
+	case *fsm_v1.Command_CdcAck:
+		f.log.Debug("applying cdc ack command", slog.String("sink", c.CdcAck.GetCursor().GetSink()))
+		f.changes.ack(c.CdcAck.GetCursor())
+	case *fsm_v1.Command_CdcSinks:
+		f.log.Info("applying cdc sinks command", slog.Any("sinks", c.CdcSinks.Sinks))
+		f.changes.register(index, c.CdcSinks.Sinks)
+	case *fsm_v1.Command_Membership:
+		f.log.Info("applying membership change", slog.Int("members", len(c.Membership.Members)))
+		f.setMembership(c.Membership)
+	case *fsm_v1.Command_MemberSet:
+		f.log.Info("applying member set command", slog.Int("id", int(c.MemberSet.GetMember().GetId())))
+		f.setMember(c.MemberSet.GetMember())
+	case *fsm_v1.Command_MemberRemove:
+		f.log.Info("applying member remove command", slog.Int("id", int(c.MemberRemove.Id)))
+		res.Err = f.removeMember(c.MemberRemove.Id)
+	case *fsm_v1.Command_Sealed:
+		inner, err := f.unseal(c.Sealed)
+		if err != nil {
+			f.log.Error("failed to decrypt command", logger.ErrorAttr(err))
+			res.Err = err
+			break
+		}
+		return f.apply(index, inner)
 	default:
+		// Nested batches are not allowed.
 		f.log.Error("unknown command type")
+		res.Err = ErrUnknownCommand
+	}
+	if change := f.change(cmd, res); change != nil {
+		f.changes.record(index, f.clock.load(), change, changeResult(change, res), f.deleted)
+	}
+	f.deleted, f.partial = nil, nil
+	return res
+}
+
+// change returns the command to record as a change of the state: the applied command itself, or the part
+// of a failed command which was applied anyway. It's nil if nothing was changed.
+func (f *storeFSM) change(cmd *fsm_v1.Command, res ftr.Result) *fsm_v1.Command {
+	if res.Err == nil {
+		if isChange(cmd, res) {
+			return cmd
+		}
+		return nil
+	}
+	if f.partial == nil {
+		return nil
+	}
+	return &fsm_v1.Command{Command: f.partial.Command, Now: cmd.Now, Namespace: cmd.Namespace, Origin: cmd.Origin}
+}
+
+// keyspace is the store of the namespace a command is applied to.
+type keyspace struct {
+	store store.Store
+	// leases is nil outside of the default namespace.
+	leases *leaseTable
+}
+
+// keyspace returns the keyspace of the namespace, empty name is the default namespace.
+func (f *storeFSM) keyspace(namespace string) (keyspace, error) {
+	if namespace == "" {
+		return keyspace{store: f.store, leases: &f.leases}, nil
+	}
+	if f.namespaces == nil {
+		return keyspace{}, store.ErrNoSuchNamespace
+	}
+	st, err := f.namespaces.Store(namespace)
+	if err != nil {
+		return keyspace{}, err
+	}
+	return keyspace{store: st}, nil
+}
+
+func isLeaseCommand(cmd *fsm_v1.Command) bool {
+	switch cmd.Command.(type) {
+	case *fsm_v1.Command_LeaseGrant, *fsm_v1.Command_LeaseKeepAlive, *fsm_v1.Command_LeaseRevoke,
+		*fsm_v1.Command_LeaseAttach, *fsm_v1.Command_Lock, *fsm_v1.Command_Unlock:
+		return true
+	}
+	return false
+}
+
+func (f *storeFSM) createNamespace(ns *fsm_v1.Namespace) error {
+	if f.namespaces == nil {
+		return ErrNamespacesDisabled
+	}
+	return f.namespaces.Create(fromPBNamespace(ns))
+}
+
+func (f *storeFSM) deleteNamespace(name string) error {
+	if f.namespaces == nil {
+		return ErrNamespacesDisabled
+	}
+	return f.namespaces.Delete(name)
+}
+
+// Results of commands reporting whether they took effect.
+var (
+	resultApplied = []byte("1")
+	resultSkipped = []byte("0")
+)
+
+// countResult builds a result of a command reporting the number of affected items.
+func countResult(n int, err error) ftr.Result {
+	if err != nil {
+		return ftr.Result{Err: err}
+	}
+	return ftr.Result{Data: []byte(strconv.Itoa(n))}
+}
+
+func (f *storeFSM) applyPop(ks keyspace, c *fsm_v1.PopCommand) ftr.Result {
+	vals, err := ks.store.Pop(c.Key, int(c.Count), c.Left, f.clock.at(c.Now))
+	if err != nil {
+		return ftr.Result{Err: err}
+	}
+	data, err := proto.Marshal(&fsm_v1.PopResult{Values: vals})
+	if err != nil {
+		return ftr.Result{Err: err}
+	}
+	return ftr.Result{Data: data}
+}
+
+func (f *storeFSM) applyZPopMin(ks keyspace, c *fsm_v1.ZPopMinCommand) ftr.Result {
+	members, err := ks.store.ZPopMin(c.Key, int(c.Count), f.clock.at(c.Now))
+	if err != nil {
+		return ftr.Result{Err: err}
+	}
+	data, err := proto.Marshal(&fsm_v1.ZPopResult{Members: toPBMembers(members)})
+	if err != nil {
+		return ftr.Result{Err: err}
+	}
+	return ftr.Result{Data: data}
+}
+
+func fromPBMembers(members []*fsm_v1.ScoredMember) []store.ScoredMember {
+	if len(members) == 0 {
+		return nil
+	}
+	out := make([]store.ScoredMember, 0, len(members))
+	for _, m := range members {
+		out = append(out, store.ScoredMember{Member: m.GetMember(), Score: m.GetScore()})
+	}
+	return out
+}
+
+func fromPBNamespace(ns *fsm_v1.Namespace) store.Namespace {
+	return store.Namespace{
+		Name:       ns.GetName(),
+		MaxKeySize: int(ns.GetMaxKeySize()),
+		MaxValSize: int(ns.GetMaxValSize()),
+		MaxKeys:    int(ns.GetMaxKeys()),
+	}
+}
+
+func toPBNamespace(ns store.Namespace) *fsm_v1.Namespace {
+	return &fsm_v1.Namespace{
+		Name:       ns.Name,
+		MaxKeySize: int32(ns.MaxKeySize),
+		MaxValSize: int32(ns.MaxValSize),
+		MaxKeys:    int32(ns.MaxKeys),
+	}
+}
+
+func toPBMembers(members []store.ScoredMember) []*fsm_v1.ScoredMember {
+	if len(members) == 0 {
+		return nil
+	}
+	out := make([]*fsm_v1.ScoredMember, 0, len(members))
+	for _, m := range members {
+		out = append(out, &fsm_v1.ScoredMember{Member: m.Member, Score: m.Score})
+	}
+	return out
+}
+
+// applySet evaluates the condition of the command at the time it was proposed,
+// so every replica makes the same decision regardless of its own clock.
+func (f *storeFSM) applySet(ks keyspace, c *fsm_v1.SetCommand) ftr.Result {
+	e, err := ks.store.Lookup(c.Key, f.clock.at(c.Now))
+	exists := err == nil
+	if (c.Condition == fsm_v1.SetCondition_SET_CONDITION_NOT_EXISTS && exists) ||
+		(c.Condition == fsm_v1.SetCondition_SET_CONDITION_EXISTS && !exists) {
+		return ftr.Result{Data: resultSkipped}
+	}
+	if c.Version != 0 {
+		if !exists {
+			return ftr.Result{Err: store.ErrNoSuchKey}
+		}
+		if e.Version != c.Version {
+			return ftr.Result{Err: store.ErrVersionMismatch}
+		}
+	}
+	err = ks.store.PutEntry(store.Entry{Key: c.Key, Value: c.Value, ExpiresAt: c.ExpiresAt, Flags: c.Flags})
+	if err != nil {
+		f.log.Debug("set command rejected", logger.ErrorAttr(err))
+		return ftr.Result{Err: err}
+	}
+	ks.leases.detach(c.Key)
+	return ftr.Result{Data: resultApplied}
+}
+
+// applyIncr adds delta to the integer value of the key keeping its expiry.
+func (f *storeFSM) applyIncr(ks keyspace, c *fsm_v1.IncrCommand) ftr.Result {
+	var cur int64
+	e, err := ks.store.Lookup(c.Key, f.clock.at(c.Now))
+	if err == nil {
+		if e.Kind != store.KindString {
+			return ftr.Result{Err: store.ErrWrongType}
+		}
+		if cur, err = strconv.ParseInt(e.Value, 10, 64); err != nil {
+			return ftr.Result{Err: store.ErrNotInteger}
+		}
+	}
+	next := cur + c.Delta
+	if (c.Delta > 0 && next < cur) || (c.Delta < 0 && next > cur) {
+		return ftr.Result{Err: store.ErrNotInteger}
+	}
+
+	value := strconv.FormatInt(next, 10)
+	err = ks.store.PutEntry(store.Entry{Key: c.Key, Value: value, ExpiresAt: e.ExpiresAt, Flags: e.Flags})
+	if err != nil {
+		f.log.Debug("incr command rejected", logger.ErrorAttr(err))
+		return ftr.Result{Err: err}
+	}
+	return ftr.Result{Data: []byte(value)}
+}
+
+// applyJSONPatch patches the JSON document at key keeping its expiry and flags.
+// A missing key is patched as null. Failed patches leave the document untouched.
+func (f *storeFSM) applyJSONPatch(ks keyspace, c *fsm_v1.JsonPatchCommand) ftr.Result {
+	var doc []byte
+	e, err := ks.store.Lookup(c.Key, f.clock.at(c.Now))
+	switch {
+	case err == nil:
+		if e.Kind != store.KindString {
+			return ftr.Result{Err: store.ErrWrongType}
+		}
+		doc = []byte(e.Value)
+	case !errors.Is(err, store.ErrNoSuchKey):
+		return ftr.Result{Err: err}
+	}
+
+	patched, err := jsondoc.Patch(doc, c.Patch, jsondoc.PatchType(c.Type))
+	if err != nil {
+		return ftr.Result{Err: err}
+	}
+	err = ks.store.PutEntry(store.Entry{Key: c.Key, Value: string(patched), ExpiresAt: e.ExpiresAt, Flags: e.Flags})
+	if err != nil {
+		f.log.Debug("json patch command rejected", logger.ErrorAttr(err))
+		return ftr.Result{Err: err}
+	}
+	return ftr.Result{Data: patched}
+}
+
+// applyCounter changes unsigned value of an existing key keeping its expiry and flags.
+func (f *storeFSM) applyCounter(ks keyspace, c *fsm_v1.CounterCommand) ftr.Result {
+	e, err := ks.store.Lookup(c.Key, f.clock.at(c.Now))
+	if err != nil {
+		return ftr.Result{Err: err}
+	}
+	if e.Kind != store.KindString {
+		return ftr.Result{Err: store.ErrWrongType}
+	}
+	cur, err := strconv.ParseUint(e.Value, 10, 64)
+	if err != nil {
+		return ftr.Result{Err: store.ErrNotInteger}
+	}
+	next := cur + c.Delta
+	if c.Decr {
+		next = cur - min(cur, c.Delta)
+	}
+
+	value := strconv.FormatUint(next, 10)
+	err = ks.store.PutEntry(store.Entry{Key: c.Key, Value: value, ExpiresAt: e.ExpiresAt, Flags: e.Flags})
+	if err != nil {
+		f.log.Debug("counter command rejected", logger.ErrorAttr(err))
+		return ftr.Result{Err: err}
+	}
+	return ftr.Result{Data: []byte(value)}
+}
+
+// applyMSet stores every key even if some of them are rejected and reports the first error.
+func (f *storeFSM) applyMSet(ks keyspace, c *fsm_v1.MSetCommand) ftr.Result {
+	var res ftr.Result
+	stored := make([]*fsm_v1.PutCommand, 0, len(c.Puts))
+	for _, put := range c.Puts {
+		if err := ks.store.Put(put.Key, put.Value); err != nil {
+			if res.Err == nil {
+				f.log.Debug("mset command rejected", logger.ErrorAttr(err))
+				res.Err = err
+			}
+			continue
+		}
+		ks.leases.detach(put.Key)
+		stored = append(stored, put)
+	}
+	if res.Err != nil && len(stored) > 0 {
+		f.partial = &fsm_v1.Command{Command: &fsm_v1.Command_Mset{Mset: &fsm_v1.MSetCommand{Puts: stored}}}
+	}
+	return res
+}
+
+func (f *storeFSM) applyDel(ks keyspace, c *fsm_v1.DelCommand) ftr.Result {
+	deleted, now := 0, f.clock.at(c.Now)
+	for i, key := range c.Keys {
+		if _, err := ks.store.Lookup(key, now); err == nil {
+			deleted++
+		}
+		if err := ks.store.Delete(key); err != nil {
+			f.log.Debug("del command rejected", logger.ErrorAttr(err))
+			if i > 0 {
+				f.partial = &fsm_v1.Command{Command: &fsm_v1.Command_Del{Del: &fsm_v1.DelCommand{Keys: c.Keys[:i], Now: c.Now}}}
+			}
+			return ftr.Result{Err: err}
+		}
+		ks.leases.detach(key)
+	}
+	return ftr.Result{Data: []byte(strconv.Itoa(deleted))}
+}
+
+// revokeLease deletes the lease and keys attached to it and returns the number of deleted keys.
+// Leases exist in the default namespace only, so keys of leases live in the default store.
+func (f *storeFSM) revokeLease(id int64) (int, bool) {
+	keys, ok := f.leases.revoke(id)
+	if !ok {
+		return 0, false
+	}
+	for _, key := range keys {
+		if err := f.store.Delete(key); err != nil {
+			f.log.Warn("failed to delete key of revoked lease", slog.String("key", key), logger.ErrorAttr(err))
+		}
+	}
+	f.deleted = append(f.deleted, keys...)
+	return len(keys), true
+}
+
+// applyLeaseAttach attaches keys to a live lease. Nothing is attached if any of the keys is missing.
+func (f *storeFSM) applyLeaseAttach(c *fsm_v1.LeaseAttachCommand) ftr.Result {
+	now := f.clock.at(c.Now)
+	if !f.leases.alive(c.Id, now) {
+		return ftr.Result{Err: fsmport.ErrLeaseNotFound}
+	}
+	for _, key := range c.Keys {
+		if _, err := f.store.Lookup(key, now); err != nil {
+			return ftr.Result{Err: err}
+		}
+	}
+	for _, key := range c.Keys {
+		f.leases.attach(c.Id, key)
+	}
+	return ftr.Result{Data: []byte(strconv.Itoa(len(c.Keys)))}
+}
+
+// applyLock puts the fencing token, a new lease id, at a missing key and attaches
+// the key to the lease. The holder of the lock gets its token again. A lock of an expired lease
+// is taken over after the lease is revoked.
+func (f *storeFSM) applyLock(index int64, c *fsm_v1.LockCommand) ftr.Result {
+	now := f.clock.at(c.Now)
+	if !f.leases.alive(c.Lease, now) {
+		return ftr.Result{Err: fsmport.ErrLeaseNotFound}
+	}
+
+	e, err := f.store.Lookup(c.Key, now)
+	switch {
+	case err == nil:
+		holder, held := f.leases.owner(c.Key)
+		switch {
+		case held && holder == c.Lease:
+			return ftr.Result{Data: []byte(e.Value)}
+		case held && !f.leases.alive(holder, now):
+			f.revokeLease(holder)
+			// The expired lease stays revoked even if the lock can't be put.
+			revoked := &fsm_v1.LeaseRevokeCommand{Id: holder, Now: c.Now}
+			f.partial = &fsm_v1.Command{Command: &fsm_v1.Command_LeaseRevoke{LeaseRevoke: revoked}}
+		default:
+			// Keys without a lease can't be taken over either.
+			return ftr.Result{Err: fsmport.ErrLockHeld}
+		}
+	case !errors.Is(err, store.ErrNoSuchKey):
+		return ftr.Result{Err: err}
+	}
+
+	token := strconv.FormatInt(f.leases.nextID(index), 10)
+	if err := f.store.Put(c.Key, token); err != nil {
+		f.log.Debug("lock command rejected", logger.ErrorAttr(err))
+		return ftr.Result{Err: err}
+	}
+	f.leases.attach(c.Lease, c.Key)
+	return ftr.Result{Data: []byte(token)}
+}
+
+// applyUnlock deletes the lock at key if it's held by the lease.
+func (f *storeFSM) applyUnlock(c *fsm_v1.UnlockCommand) ftr.Result {
+	holder, held := f.leases.owner(c.Key)
+	if !held {
+		return ftr.Result{Err: store.ErrNoSuchKey}
+	}
+	if holder != c.Lease {
+		return ftr.Result{Err: fsmport.ErrLockHeld}
+	}
+	if err := f.store.Delete(c.Key); err != nil {
+		return ftr.Result{Err: err}
+	}
+	f.leases.detach(c.Key)
+	return ftr.Result{}
+}
+
+func (f *storeFSM) applyExpire(ks keyspace, c *fsm_v1.ExpireCommand) ftr.Result {
+	err := ks.store.Expire(c.Key, c.ExpiresAt, f.clock.at(c.Now))
+	switch {
+	case errors.Is(err, store.ErrNoSuchKey):
+		return ftr.Result{Data: resultSkipped}
+	case err != nil:
+		return ftr.Result{Err: err}
+	default:
+		return ftr.Result{Data: resultApplied}
+	}
+}
+
+// unseal decrypts a sealed command. Missing key is a local misconfiguration, so instead of
+// skipping the command and diverging from other replicas the state machine stops applying commands.
+func (f *storeFSM) unseal(sealed []byte) (*fsm_v1.Command, error) {
+	if f.cipher == nil {
+		f.failed.Store(true)
+		return nil, encryption.ErrDisabled
+	}
+	data, err := f.cipher.Decrypt(sealed)
+	if err != nil {
+		if errors.Is(err, encryption.ErrUnknownKey) {
+			f.failed.Store(true)
+		}
+		return nil, err
+	}
+
+	cmd := new(fsm_v1.Command)
+	if err := proto.Unmarshal(data, cmd); err != nil {
+		return nil, err
+	}
+	if _, ok := cmd.Command.(*fsm_v1.Command_Sealed); ok {
+		return nil, ErrUnknownCommand
+	}
+	return cmd, nil
+}
+
+func (f *storeFSM) setMembership(m *fsm_v1.MembershipCommand) {
+	f.members.Store(m)
+	if f.membership == nil {
+		return
+	}
+	members := make([]cluster.Member, 0, len(m.Members))
+	for _, member := range m.Members {
+		members = append(members, cluster.Member{ID: int(member.Id), HTTPAddr: member.HttpAddr})
+	}
+	f.membership.Replace(members)
+}
+
+// currentMembers returns the last committed membership or, until one is committed, addresses
+// the nodes are configured with. The returned slice must not be modified.
+func (f *storeFSM) currentMembers() []*fsm_v1.Member {
+	if m := f.members.Load(); m != nil {
+		return m.Members
+	}
+	if f.membership == nil {
+		return nil
+	}
+	var members []*fsm_v1.Member
+	for _, m := range f.membership.Members() {
+		members = append(members, &fsm_v1.Member{Id: int32(m.ID), HttpAddr: m.HTTPAddr})
+	}
+	return members
+}
+
+// setMember replaces the address of the member or adds it, keeping members ordered by id.
+func (f *storeFSM) setMember(member *fsm_v1.Member) {
+	current := f.currentMembers()
+	members := make([]*fsm_v1.Member, 0, len(current)+1)
+	for _, m := range current {
+		if m.Id < member.Id {
+			members = append(members, m)
+		}
 	}
+	members = append(members, member)
+	for _, m := range current {
+		if m.Id > member.Id {
+			members = append(members, m)
+		}
+	}
+	f.setMembership(&fsm_v1.MembershipCommand{Members: members})
+}
+
+func (f *storeFSM) removeMember(id int32) error {
+	current := f.currentMembers()
+	members := make([]*fsm_v1.Member, 0, len(current))
+	for _, m := range current {
+		if m.Id != id {
+			members = append(members, m)
+		}
+	}
+	if len(members) == len(current) {
+		return cluster.ErrNoSuchMember
+	}
+	f.setMembership(&fsm_v1.MembershipCommand{Members: members})
+	return nil
 }
 
+// Snapshot encodes the state at the last applied index. Commands aren't applied until it's encoded.
 func (f *storeFSM) Snapshot() ([]byte, int64, error) {
-	snapshot := &fsm_v1.SnapshotState{
-		Items: f.store.Items(),
+	f.mu.Lock()
+	if f.failed.Load() {
+		f.mu.Unlock()
+		return nil, 0, fsmport.ErrStateUnavailable
 	}
-	b, err := proto.Marshal(snapshot)
+
+	lastApplied := f.applied.load()
+	cdcCursors, cdcChanges := f.changes.snapshot()
+	b, err := encodeSnapshot(f.store, f.namespaces, &snapshotHeader{
+		compression:  f.compression,
+		appliedIndex: lastApplied,
+		appliedTerm:  f.appliedTerm.Load(),
+		membership:   f.members.Load(),
+		leases:       f.leases.snapshot(),
+		lastLeaseID:  f.leases.last(),
+		clusterTime:  f.clock.load(),
+		cdcCursors:   cdcCursors,
+		cdcChanges:   cdcChanges,
+	})
+	f.mu.Unlock()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to marshal snapshot: %w", err)
+		return nil, 0, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if f.cipher != nil {
+		if b, err = sealSnapshot(b, f.cipher); err != nil {
+			return nil, 0, fmt.Errorf("failed to encrypt snapshot: %w", err)
+		}
 	}
-	return b, f.lastAppliedIdx, nil
+	return b, lastApplied, nil
 }
 
+// Restore installs the snapshot. Applied index and term are taken from the snapshot itself.
 func (f *storeFSM) Restore(data []byte) error {
+	return f.restore(data, 0, 0)
+}
+
+// restore installs the snapshot and records the outcome. Store content is left untouched
+// if the snapshot is corrupted, but the state machine refuses to apply commands or serve reads.
+// Zero snapshotIdx and snapshotTerm are replaced with ones recorded in the snapshot.
+func (f *storeFSM) restore(data []byte, snapshotIdx, snapshotTerm int64) error {
+	status := &fsmport.RestoreStatus{
+		Time:          time.Now(),
+		SnapshotIndex: snapshotIdx,
+		SnapshotSize:  len(data),
+	}
+	defer f.lastRestore.Store(status)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.restoring.Store(true)
+	defer f.restoring.Store(false)
+
+	h, err := f.install(data)
+	if err != nil {
+		status.Error = err.Error()
+		f.failed.Store(true)
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	if snapshotIdx == 0 {
+		snapshotIdx = h.appliedIndex
+		status.SnapshotIndex = snapshotIdx
+	}
+	if snapshotTerm == 0 {
+		snapshotTerm = h.appliedTerm
+	}
+	if h.membership != nil {
+		f.setMembership(h.membership)
+	}
+	if h.lastVersion > 0 {
+		f.store.RestoreVersion(h.lastVersion)
+	}
+	f.leases.restore(h.leases, h.lastLeaseID)
+	f.clock.restore(h.clusterTime)
+	// Undelivered changes up to the snapshot index come with the snapshot.
+	f.changes.reset(snapshotIdx, h.cdcCursors, h.cdcChanges)
+	f.appliedTerm.Store(snapshotTerm)
+	f.applied.store(snapshotIdx)
+	f.failed.Store(false)
+	// Lists could get values, waiters check them again.
+	f.pushes.notifyAll()
+
+	// Commands up to the snapshot index won't be applied one by one, so their results are lost.
+	if snapshotIdx > 0 {
+		f.futuresStore.FulfillUpTo(snapshotIdx, ftr.Result{Err: ftr.ErrCompacted})
+	}
+	return nil
+}
+
+// install replaces store content and returns the snapshot header.
+// Header of a legacy snapshot is empty.
+func (f *storeFSM) install(data []byte) (*snapshotHeader, error) {
+	if isSealedSnapshot(data) {
+		var err error
+		if data, err = openSnapshot(data, f.cipher); err != nil {
+			return nil, err
+		}
+	}
+
+	var restore namespacesRestore
+	if f.namespaces != nil {
+		restore = f.namespaces.Restore
+	}
+	if isChunkedSnapshot(data) {
+		// The default store is replaced only if namespaces were restored too.
+		var h *snapshotHeader
+		err := f.store.RestoreFrom(func(put func(e store.Entry)) error {
+			var err error
+			h, err = decodeSnapshot(data, put, restore)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return h, nil
+	}
+
 	s := new(fsm_v1.SnapshotState)
 	if err := proto.Unmarshal(data, s); err != nil {
-		return fmt.Errorf("failed to unmarshal snapshot data: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrSnapshotCorrupted, err)
 	}
-
 	f.store.RestoreFromSnapshot(s.Items)
-	return nil
+	// Legacy snapshots have no namespaces.
+	if restore != nil {
+		if err := restore(nil, func(func(string, store.Entry)) error { return nil }); err != nil {
+			return nil, err
+		}
+	}
+	return new(snapshotHeader), nil
+}
+
+func (f *storeFSM) AppliedIndex() int64 {
+	return f.applied.load()
 }
 
+func (f *storeFSM) AppliedAt() time.Time {
+	return f.applied.loadTime()
+}
+
+func (f *storeFSM) WatchPushes(key string) (<-chan struct{}, func()) {
+	return f.pushes.watch(key)
+}
+
+func (f *storeFSM) Changes(after int64, limit int) ([]fsmport.Change, <-chan struct{}, error) {
+	return f.changes.since(after, limit)
+}
+
+func (f *storeFSM) Cursor(sink string) (int64, bool) {
+	return f.changes.cursor(sink)
+}
+
+func (f *storeFSM) Sinks() []string {
+	return f.changes.sinks()
+}
+
+func (f *storeFSM) Backlog() int {
+	return f.changes.backlog()
+}
+
+func (f *storeFSM) ClusterTime() int64 {
+	return f.clock.load()
+}
+
+func (f *storeFSM) Lease(id, now int64) (fsmport.Lease, bool) {
+	return f.leases.get(id, now)
+}
+
+func (f *storeFSM) ExpiredLeases(now int64, limit int) []int64 {
+	return f.leases.expired(now, limit)
+}
+
+func (f *storeFSM) WaitApplied(ctx context.Context, logIndex int64) error {
+	if f.failed.Load() {
+		return fsmport.ErrStateUnavailable
+	}
+	return f.applied.wait(ctx, logIndex)
+}
+
+// LastRestore returns status of the last snapshot restore attempt.
+func (f *storeFSM) LastRestore() *fsmport.RestoreStatus {
+	return f.lastRestore.Load()
+}
+
+func (f *storeFSM) Restoring() bool {
+	return f.restoring.Load()
+}
+
+func (f *storeFSM) Available() bool {
+	return !f.failed.Load()
+}
+
+// Read returns value of the key in query built by fsmport.ReadQuery. Empty query only confirms the state is readable,
+// so callers use it as a barrier before reading the store directly.
 func (f *storeFSM) Read(query []byte) ([]byte, error) {
-	key := string(query)
-	value, err := f.store.Get(key)
+	if f.failed.Load() {
+		return nil, fsmport.ErrStateUnavailable
+	}
+	if len(query) == 0 {
+		return nil, nil
+	}
+	namespace, key := fsmport.ParseReadQuery(query)
+	ks, err := f.keyspace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ks.store.Get(key)
 	if err != nil {
 		return nil, err
 	}
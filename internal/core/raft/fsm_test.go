@@ -2,6 +2,9 @@ package raft
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,10 +13,21 @@ import (
 	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
 	raftapi "github.com/shrtyk/raft-core/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"google.golang.org/protobuf/proto"
 
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/cluster"
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
+	clusterport "github.com/shrtyk/kv-store/internal/core/ports/cluster"
+	"github.com/shrtyk/kv-store/internal/core/ports/encryption"
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
 	futuresmocks "github.com/shrtyk/kv-store/internal/core/ports/futures/mocks"
+	corestore "github.com/shrtyk/kv-store/internal/core/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
 	"github.com/shrtyk/kv-store/pkg/logger"
 )
 
@@ -30,11 +44,107 @@ func setup(t *testing.T) fsmSetup {
 	mockFutures := futuresmocks.NewMockFuturesStore(t)
 	appCh := make(chan *raftapi.ApplyMessage, 1)
 
-	fsm := NewFSM(slogger, mockStore, mockFutures, appCh).(*storeFSM)
+	fsm := NewFSM(slogger, mockStore, nil, mockFutures, appCh, CompressionNone, nil, nil).(*storeFSM)
 
 	return fsmSetup{fsm, mockStore, mockFutures, appCh}
 }
 
+type storeFSMOpts struct {
+	namespaces bool
+	cdcSinks   []string
+	quotas     []cfg.QuotaCfg
+}
+
+type storeFSMOpt func(*storeFSMOpts)
+
+func withNamespaces() storeFSMOpt {
+	return func(o *storeFSMOpts) { o.namespaces = true }
+}
+
+// withCDCSinks registers change data capture sinks before the first command.
+func withCDCSinks(sinks ...string) storeFSMOpt {
+	return func(o *storeFSMOpts) { o.cdcSinks = sinks }
+}
+
+// withQuotas limits usage of the default store.
+func withQuotas(quotas ...cfg.QuotaCfg) storeFSMOpt {
+	return func(o *storeFSMOpts) { o.quotas = quotas }
+}
+
+// newStoreFSM builds a state machine over real stores. Returned apply marshals the command
+// and applies it at the log index bypassing the apply loop.
+func newStoreFSM(t *testing.T, opts ...storeFSMOpt) (*storeFSM, func(index int64, cmd *fsm_v1.Command) ftr.Result) {
+	var o storeFSMOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	l, _ := tu.NewMockLogger()
+	storeCfg := tu.NewMockStoreCfg()
+	storeCfg.Quotas = o.quotas
+	st := corestore.NewStore(&sync.WaitGroup{}, storeCfg, tu.NewMockShardsCfg(), l)
+	var spaces store.Namespaces
+	if o.namespaces {
+		spaces = corestore.NewNamespaces(st, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	}
+	f := NewFSM(logger.NewLogger("dev"), st, spaces, NewApplyFuture(), nil, CompressionNone, nil, nil).(*storeFSM)
+	if len(o.cdcSinks) > 0 {
+		f.changes.register(0, o.cdcSinks)
+	}
+	return f, func(index int64, cmd *fsm_v1.Command) ftr.Result {
+		data, err := proto.Marshal(cmd)
+		require.NoError(t, err)
+		return f.applyCommand(index, data)
+	}
+}
+
+// testCipher xors data with its key id and prefixes the result with the id.
+// rangeEntries returns a mocked RangeEntries passing content of every shard.
+func rangeEntries(shards ...map[string]string) func(fn func(store.Entry) error) error {
+	return func(fn func(store.Entry) error) error {
+		for _, shard := range shards {
+			for k, v := range shard {
+				if err := fn(store.Entry{Key: k, Value: v}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+type testCipher struct{ id byte }
+
+func (c testCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	out := []byte{c.id}
+	for _, b := range plaintext {
+		out = append(out, b^c.id)
+	}
+	return out, nil
+}
+
+func (c testCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 || ciphertext[0] != c.id {
+		return nil, encryption.ErrUnknownKey
+	}
+	out := make([]byte, 0, len(ciphertext)-1)
+	for _, b := range ciphertext[1:] {
+		out = append(out, b^c.id)
+	}
+	return out, nil
+}
+
+func sealedCmd(t *testing.T, c encryption.Cipher, cmd *fsm_v1.Command) []byte {
+	t.Helper()
+	data, err := proto.Marshal(cmd)
+	require.NoError(t, err)
+	sealed, err := c.Encrypt(data)
+	require.NoError(t, err)
+	data, err = proto.Marshal(&fsm_v1.Command{Command: &fsm_v1.Command_Sealed{Sealed: sealed}})
+	require.NoError(t, err)
+	return data
+}
+
 func TestFSM_Apply(t *testing.T) {
 	t.Run("put command", func(t *testing.T) {
 		s := setup(t)
@@ -50,7 +160,7 @@ func TestFSM_Apply(t *testing.T) {
 		assert.NoError(t, err)
 
 		s.mockStore.On("Put", key, value).Return(nil).Once()
-		s.mockFutures.On("Fulfill", logIndex).Return().Once()
+		s.mockFutures.On("Fulfill", logIndex, ftr.Result{}).Return().Once()
 
 		s.appCh <- &raftapi.ApplyMessage{
 			CommandValid: true,
@@ -79,7 +189,212 @@ func TestFSM_Apply(t *testing.T) {
 		assert.NoError(t, err)
 
 		s.mockStore.On("Delete", key).Return(nil).Once()
-		s.mockFutures.On("Fulfill", logIndex).Return().Once()
+		s.mockFutures.On("Fulfill", logIndex, ftr.Result{}).Return().Once()
+
+		s.appCh <- &raftapi.ApplyMessage{
+			CommandValid: true,
+			Command:      cmdBytes,
+			CommandIndex: logIndex,
+		}
+
+		go s.fsm.Start(context.Background())
+		time.Sleep(10 * time.Millisecond)
+
+		s.mockStore.AssertExpectations(t)
+		s.mockFutures.AssertExpectations(t)
+	})
+
+	t.Run("hash commands", func(t *testing.T) {
+		s := setup(t)
+		fields := map[string]string{"f": "v"}
+
+		hset, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Hset{Hset: &fsm_v1.HSetCommand{Key: "h", Fields: fields, Now: 1}},
+		})
+		assert.NoError(t, err)
+		hincr, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Hincrby{Hincrby: &fsm_v1.HIncrByCommand{Key: "h", Field: "n", Delta: 2, Now: 1}},
+		})
+		assert.NoError(t, err)
+		hdel, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Hdel{Hdel: &fsm_v1.HDelCommand{Key: "s", Fields: []string{"f"}, Now: 1}},
+		})
+		assert.NoError(t, err)
+
+		s.mockStore.On("HSet", "h", fields, int64(1)).Return(1, nil).Once()
+		s.mockStore.On("HIncrBy", "h", "n", int64(2), int64(1)).Return(int64(7), nil).Once()
+		s.mockStore.On("HDel", "s", []string{"f"}, int64(1)).Return(0, store.ErrWrongType).Once()
+		s.mockFutures.On("Fulfill", int64(1), ftr.Result{Data: []byte("1")}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(2), ftr.Result{Data: []byte("7")}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(3), ftr.Result{Err: store.ErrWrongType}).Return().Once()
+
+		go s.fsm.Start(context.Background())
+		for i, cmd := range [][]byte{hset, hincr, hdel} {
+			s.appCh <- &raftapi.ApplyMessage{
+				CommandValid: true,
+				Command:      cmd,
+				CommandIndex: int64(i + 1),
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		s.mockStore.AssertExpectations(t)
+		s.mockFutures.AssertExpectations(t)
+	})
+
+	t.Run("list commands", func(t *testing.T) {
+		s := setup(t)
+
+		push, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Push{Push: &fsm_v1.PushCommand{Key: "l", Values: []string{"a", "b"}, Now: 1}},
+		})
+		assert.NoError(t, err)
+		pop, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Pop{Pop: &fsm_v1.PopCommand{Key: "l", Count: 1, Left: true, Now: 1}},
+		})
+		assert.NoError(t, err)
+		popped, err := proto.Marshal(&fsm_v1.PopResult{Values: []string{"a"}})
+		assert.NoError(t, err)
+
+		pushed, stop := s.fsm.WatchPushes("l")
+		defer stop()
+		other, stopOther := s.fsm.WatchPushes("other")
+		defer stopOther()
+
+		s.mockStore.On("Push", "l", []string{"a", "b"}, false, int64(1)).Return(2, nil).Once()
+		s.mockStore.On("Pop", "l", 1, true, int64(1)).Return([]string{"a"}, nil).Once()
+		s.mockFutures.On("Fulfill", int64(1), ftr.Result{Data: []byte("2")}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(2), ftr.Result{Data: popped}).Return().Once()
+
+		go s.fsm.Start(context.Background())
+		for i, cmd := range [][]byte{push, pop} {
+			s.appCh <- &raftapi.ApplyMessage{
+				CommandValid: true,
+				Command:      cmd,
+				CommandIndex: int64(i + 1),
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		s.mockStore.AssertExpectations(t)
+		s.mockFutures.AssertExpectations(t)
+		assert.True(t, isClosed(pushed))
+		assert.False(t, isClosed(other))
+	})
+
+	t.Run("sorted set commands", func(t *testing.T) {
+		s := setup(t)
+		members := []store.ScoredMember{{Member: "a", Score: 1.5}}
+
+		zadd, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Zadd{Zadd: &fsm_v1.ZAddCommand{
+				Key: "z", Members: []*fsm_v1.ScoredMember{{Member: "a", Score: 1.5}}, Now: 1,
+			}},
+		})
+		assert.NoError(t, err)
+		zincr, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Zincrby{Zincrby: &fsm_v1.ZIncrByCommand{Key: "z", Member: "a", Delta: 1, Now: 1}},
+		})
+		assert.NoError(t, err)
+		zpop, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Zpopmin{Zpopmin: &fsm_v1.ZPopMinCommand{Key: "z", Count: 2, Now: 1}},
+		})
+		assert.NoError(t, err)
+		zrem, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Zrem{Zrem: &fsm_v1.ZRemCommand{Key: "s", Members: []string{"a"}, Now: 1}},
+		})
+		assert.NoError(t, err)
+		popped, err := proto.Marshal(&fsm_v1.ZPopResult{Members: []*fsm_v1.ScoredMember{{Member: "a", Score: 2.5}}})
+		assert.NoError(t, err)
+
+		s.mockStore.On("ZAdd", "z", members, int64(1)).Return(1, nil).Once()
+		s.mockStore.On("ZIncrBy", "z", "a", 1.0, int64(1)).Return(2.5, nil).Once()
+		s.mockStore.On("ZPopMin", "z", 2, int64(1)).Return([]store.ScoredMember{{Member: "a", Score: 2.5}}, nil).Once()
+		s.mockStore.On("ZRem", "s", []string{"a"}, int64(1)).Return(0, store.ErrWrongType).Once()
+		s.mockFutures.On("Fulfill", int64(1), ftr.Result{Data: []byte("1")}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(2), ftr.Result{Data: []byte("2.5")}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(3), ftr.Result{Data: popped}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(4), ftr.Result{Err: store.ErrWrongType}).Return().Once()
+
+		go s.fsm.Start(context.Background())
+		for i, cmd := range [][]byte{zadd, zincr, zpop, zrem} {
+			s.appCh <- &raftapi.ApplyMessage{
+				CommandValid: true,
+				Command:      cmd,
+				CommandIndex: int64(i + 1),
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		s.mockStore.AssertExpectations(t)
+		s.mockFutures.AssertExpectations(t)
+	})
+
+	t.Run("json patch command", func(t *testing.T) {
+		s := setup(t)
+
+		patch := func(key string, typ fsm_v1.PatchType, p string) []byte {
+			data, err := proto.Marshal(&fsm_v1.Command{
+				Command: &fsm_v1.Command_JsonPatch{JsonPatch: &fsm_v1.JsonPatchCommand{Key: key, Patch: []byte(p), Type: typ, Now: 1}},
+			})
+			assert.NoError(t, err)
+			return data
+		}
+		doc := store.Entry{Key: "doc", Value: `{"a":1}`, ExpiresAt: 50, Flags: 2}
+
+		s.mockStore.On("Lookup", "new", int64(1)).Return(store.Entry{}, store.ErrNoSuchKey).Once()
+		s.mockStore.On("PutEntry", store.Entry{Key: "new", Value: `{"a":1}`}).Return(nil).Once()
+		s.mockStore.On("Lookup", "doc", int64(1)).Return(doc, nil).Twice()
+		s.mockStore.On("PutEntry", store.Entry{Key: "doc", Value: `{"a":[2]}`, ExpiresAt: 50, Flags: 2}).Return(nil).Once()
+		s.mockStore.On("Lookup", "h", int64(1)).Return(store.Entry{Key: "h", Kind: store.KindHash}, nil).Once()
+		s.mockFutures.On("Fulfill", int64(1), ftr.Result{Data: []byte(`{"a":1}`)}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(2), ftr.Result{Data: []byte(`{"a":[2]}`)}).Return().Once()
+		s.mockFutures.On("Fulfill", int64(3), mock.MatchedBy(func(res ftr.Result) bool {
+			return errors.Is(res.Err, jsondoc.ErrPatchFailed)
+		})).Return().Once()
+		s.mockFutures.On("Fulfill", int64(4), ftr.Result{Err: store.ErrWrongType}).Return().Once()
+
+		go s.fsm.Start(context.Background())
+		for i, cmd := range [][]byte{
+			patch("new", fsm_v1.PatchType_PATCH_TYPE_MERGE_PATCH, `{"a":1,"b":null}`),
+			patch("doc", fsm_v1.PatchType_PATCH_TYPE_JSON_PATCH, `[{"op":"replace","path":"/a","value":[2]}]`),
+			patch("doc", fsm_v1.PatchType_PATCH_TYPE_JSON_PATCH, `[{"op":"test","path":"/a","value":2}]`),
+			patch("h", fsm_v1.PatchType_PATCH_TYPE_MERGE_PATCH, `{}`),
+		} {
+			s.appCh <- &raftapi.ApplyMessage{
+				CommandValid: true,
+				Command:      cmd,
+				CommandIndex: int64(i + 1),
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		s.mockStore.AssertExpectations(t)
+		s.mockFutures.AssertExpectations(t)
+	})
+
+	t.Run("batch command", func(t *testing.T) {
+		s := setup(t)
+		logIndex := int64(789)
+
+		put, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: "key", Value: "value"}},
+		})
+		assert.NoError(t, err)
+		del, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Delete{Delete: &fsm_v1.DeleteCommand{Key: "other"}},
+		})
+		assert.NoError(t, err)
+		cmdBytes, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Batch{Batch: &fsm_v1.BatchCommand{Commands: [][]byte{put, del}}},
+		})
+		assert.NoError(t, err)
+
+		s.mockStore.On("Put", "key", "value").Return(store.ErrQuotaExceeded).Once()
+		s.mockStore.On("Delete", "other").Return(nil).Once()
+		s.mockFutures.On("Fulfill", logIndex, ftr.Result{
+			Batch: []ftr.Result{{Err: store.ErrQuotaExceeded}, {}},
+		}).Return().Once()
 
 		s.appCh <- &raftapi.ApplyMessage{
 			CommandValid: true,
@@ -95,21 +410,328 @@ func TestFSM_Apply(t *testing.T) {
 	})
 }
 
+func TestFSM_ApplyConditional(t *testing.T) {
+	apply := func(t *testing.T, s fsmSetup, cmd *fsm_v1.Command) ftr.Result {
+		data, err := proto.Marshal(cmd)
+		require.NoError(t, err)
+		return s.fsm.applyCommand(1, data)
+	}
+	now := int64(1_000)
+
+	t.Run("set if not exists", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Set{Set: &fsm_v1.SetCommand{
+			Key: "key", Value: "v", Condition: fsm_v1.SetCondition_SET_CONDITION_NOT_EXISTS, ExpiresAt: now + 10, Now: now,
+		}}}
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{}, store.ErrNoSuchKey).Once()
+		s.mockStore.EXPECT().PutEntry(store.Entry{Key: "key", Value: "v", ExpiresAt: now + 10}).Return(nil).Once()
+		assert.Equal(t, resultApplied, apply(t, s, cmd).Data)
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key"}, nil).Once()
+		assert.Equal(t, resultSkipped, apply(t, s, cmd).Data)
+	})
+
+	t.Run("set if exists", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Set{Set: &fsm_v1.SetCommand{
+			Key: "key", Value: "v", Condition: fsm_v1.SetCondition_SET_CONDITION_EXISTS, Now: now,
+		}}}
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{}, store.ErrNoSuchKey).Once()
+		assert.Equal(t, resultSkipped, apply(t, s, cmd).Data)
+	})
+
+	t.Run("incr keeps expiry", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Incr{Incr: &fsm_v1.IncrCommand{Key: "key", Delta: 5, Now: now}}}
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Value: "-2", ExpiresAt: now + 10, Flags: 2}, nil).Once()
+		s.mockStore.EXPECT().PutEntry(store.Entry{Key: "key", Value: "3", ExpiresAt: now + 10, Flags: 2}).Return(nil).Once()
+		res := apply(t, s, cmd)
+		assert.NoError(t, res.Err)
+		assert.Equal(t, []byte("3"), res.Data)
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Value: "abc"}, nil).Once()
+		assert.ErrorIs(t, apply(t, s, cmd).Err, store.ErrNotInteger)
+	})
+
+	t.Run("incr overflow", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Incr{Incr: &fsm_v1.IncrCommand{Key: "key", Delta: 1, Now: now}}}
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Value: "9223372036854775807"}, nil).Once()
+		assert.ErrorIs(t, apply(t, s, cmd).Err, store.ErrNotInteger)
+	})
+
+	t.Run("cas", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Set{Set: &fsm_v1.SetCommand{
+			Key: "key", Value: "v", Flags: 3, Version: 7, Now: now,
+		}}}
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{}, store.ErrNoSuchKey).Once()
+		assert.ErrorIs(t, apply(t, s, cmd).Err, store.ErrNoSuchKey)
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Version: 8}, nil).Once()
+		assert.ErrorIs(t, apply(t, s, cmd).Err, store.ErrVersionMismatch)
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Version: 7}, nil).Once()
+		s.mockStore.EXPECT().PutEntry(store.Entry{Key: "key", Value: "v", Flags: 3}).Return(nil).Once()
+		assert.Equal(t, resultApplied, apply(t, s, cmd).Data)
+	})
+
+	t.Run("counter", func(t *testing.T) {
+		s := setup(t)
+		counter := func(delta uint64, decr bool) *fsm_v1.Command {
+			return &fsm_v1.Command{Command: &fsm_v1.Command_Counter{Counter: &fsm_v1.CounterCommand{
+				Key: "key", Delta: delta, Decr: decr, Now: now,
+			}}}
+		}
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{}, store.ErrNoSuchKey).Once()
+		assert.ErrorIs(t, apply(t, s, counter(1, false)).Err, store.ErrNoSuchKey)
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Value: "18446744073709551615", Flags: 1}, nil).Once()
+		s.mockStore.EXPECT().PutEntry(store.Entry{Key: "key", Value: "1", Flags: 1}).Return(nil).Once()
+		assert.Equal(t, []byte("1"), apply(t, s, counter(2, false)).Data)
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Value: "5", ExpiresAt: now + 10}, nil).Once()
+		s.mockStore.EXPECT().PutEntry(store.Entry{Key: "key", Value: "0", ExpiresAt: now + 10}).Return(nil).Once()
+		assert.Equal(t, []byte("0"), apply(t, s, counter(9, true)).Data)
+
+		s.mockStore.EXPECT().Lookup("key", now).Return(store.Entry{Key: "key", Value: "-1"}, nil).Once()
+		assert.ErrorIs(t, apply(t, s, counter(1, true)).Err, store.ErrNotInteger)
+	})
+
+	t.Run("expire missing key", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Expire{Expire: &fsm_v1.ExpireCommand{Key: "key", ExpiresAt: now + 10, Now: now}}}
+
+		s.mockStore.EXPECT().Expire("key", now+10, now).Return(store.ErrNoSuchKey).Once()
+		assert.Equal(t, resultSkipped, apply(t, s, cmd).Data)
+	})
+
+	t.Run("mset reports first error", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Mset{Mset: &fsm_v1.MSetCommand{Puts: []*fsm_v1.PutCommand{
+			{Key: "a", Value: "1"}, {Key: "b", Value: "2"},
+		}}}}
+
+		s.mockStore.EXPECT().Put("a", "1").Return(store.ErrQuotaExceeded).Once()
+		s.mockStore.EXPECT().Put("b", "2").Return(nil).Once()
+		assert.ErrorIs(t, apply(t, s, cmd).Err, store.ErrQuotaExceeded)
+	})
+
+	t.Run("del counts alive keys", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Del{Del: &fsm_v1.DelCommand{Keys: []string{"a", "b"}, Now: now}}}
+
+		s.mockStore.EXPECT().Lookup("a", now).Return(store.Entry{Key: "a"}, nil).Once()
+		s.mockStore.EXPECT().Lookup("b", now).Return(store.Entry{}, store.ErrNoSuchKey).Once()
+		s.mockStore.EXPECT().Delete("a").Return(nil).Once()
+		s.mockStore.EXPECT().Delete("b").Return(nil).Once()
+		assert.Equal(t, []byte("1"), apply(t, s, cmd).Data)
+	})
+
+	t.Run("reap", func(t *testing.T) {
+		s := setup(t)
+		cmd := &fsm_v1.Command{Command: &fsm_v1.Command_Reap{Reap: &fsm_v1.ReapCommand{Keys: []string{"a", "b"}, Now: now}}}
+
+		s.mockStore.EXPECT().DeleteExpired([]string{"a", "b"}, now).Return([]string{"a", "b"}).Once()
+		assert.NoError(t, apply(t, s, cmd).Err)
+	})
+}
+
+func TestFSM_ApplySealed(t *testing.T) {
+	put := &fsm_v1.Command{Command: &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: "key", Value: "value"}}}
+
+	t.Run("decrypts command", func(t *testing.T) {
+		s := setup(t)
+		s.fsm.cipher = testCipher{id: 1}
+		s.mockStore.On("Put", "key", "value").Return(nil).Once()
+
+		res := s.fsm.applyCommand(1, sealedCmd(t, testCipher{id: 1}, put))
+
+		assert.NoError(t, res.Err)
+		assert.False(t, s.fsm.failed.Load())
+	})
+
+	t.Run("unknown key stops applying", func(t *testing.T) {
+		s := setup(t)
+		s.fsm.cipher = testCipher{id: 1}
+
+		res := s.fsm.applyCommand(1, sealedCmd(t, testCipher{id: 2}, put))
+
+		assert.ErrorIs(t, res.Err, encryption.ErrUnknownKey)
+		assert.True(t, s.fsm.failed.Load())
+	})
+
+	t.Run("encryption disabled stops applying", func(t *testing.T) {
+		s := setup(t)
+
+		res := s.fsm.applyCommand(1, sealedCmd(t, testCipher{id: 1}, put))
+
+		assert.ErrorIs(t, res.Err, encryption.ErrDisabled)
+		assert.True(t, s.fsm.failed.Load())
+	})
+}
+
+func TestFSM_Membership(t *testing.T) {
+	s := setup(t)
+	registry := cluster.NewRegistry(3, []string{"http://a:8080", "http://b:8080", "http://c:8080"})
+	s.fsm.membership = registry
+
+	cmd := &fsm_v1.MembershipCommand{Members: []*fsm_v1.Member{
+		{Id: 0, HttpAddr: "http://a:8080"},
+		{Id: 2, HttpAddr: "http://new-c:8080"},
+	}}
+	data, err := proto.Marshal(&fsm_v1.Command{Command: &fsm_v1.Command_Membership{Membership: cmd}})
+	require.NoError(t, err)
+
+	res := s.fsm.applyCommand(1, data)
+	require.NoError(t, res.Err)
+	addr, ok := registry.HTTPAddr(2)
+	assert.True(t, ok)
+	assert.Equal(t, "http://new-c:8080", addr)
+	_, ok = registry.HTTPAddr(1)
+	assert.False(t, ok)
+
+	// Membership survives snapshot restore.
+	s.mockStore.EXPECT().RangeEntries(mock.Anything).Return(nil).Once()
+	s.mockStore.EXPECT().LastVersion().Return(0).Once()
+	snapBytes, _, err := s.fsm.Snapshot()
+	require.NoError(t, err)
+
+	r := setup(t)
+	restored := cluster.NewRegistry(3, []string{"http://a:8080", "http://b:8080", "http://c:8080"})
+	r.fsm.membership = restored
+	r.mockStore.EXPECT().RestoreFrom(mock.Anything).RunAndReturn(func(fill func(func(e store.Entry)) error) error {
+		return fill(func(e store.Entry) {})
+	}).Once()
+
+	require.NoError(t, r.fsm.Restore(snapBytes))
+	assert.Equal(t, registry.Members(), restored.Members())
+}
+
+func TestFSM_MemberCommands(t *testing.T) {
+	s := setup(t)
+	registry := cluster.NewRegistry(3, []string{"http://a:8080", "http://b:8080", "http://c:8080"})
+	s.fsm.membership = registry
+
+	apply := func(index int64, cmd *fsm_v1.Command) ftr.Result {
+		data, err := proto.Marshal(cmd)
+		require.NoError(t, err)
+		return s.fsm.applyCommand(index, data)
+	}
+	set := func(id int32, addr string) *fsm_v1.Command {
+		return &fsm_v1.Command{Command: &fsm_v1.Command_MemberSet{MemberSet: &fsm_v1.MemberSetCommand{
+			Member: &fsm_v1.Member{Id: id, HttpAddr: addr},
+		}}}
+	}
+	remove := func(id int32) *fsm_v1.Command {
+		return &fsm_v1.Command{Command: &fsm_v1.Command_MemberRemove{MemberRemove: &fsm_v1.MemberRemoveCommand{Id: id}}}
+	}
+
+	// Changes of different members proposed at the same time are both kept.
+	require.NoError(t, apply(1, set(2, "http://new-c:8080")).Err)
+	require.NoError(t, apply(2, set(0, "http://new-a:8080")).Err)
+	require.NoError(t, apply(3, remove(1)).Err)
+	assert.Equal(t, []clusterport.Member{
+		{ID: 0, HTTPAddr: "http://new-a:8080"},
+		{ID: 2, HTTPAddr: "http://new-c:8080"},
+	}, registry.Members())
+
+	assert.ErrorIs(t, apply(4, remove(1)).Err, clusterport.ErrNoSuchMember)
+	assert.Len(t, s.fsm.members.Load().Members, 2)
+
+	// Members stay ordered by id, as snapshots store them.
+	require.NoError(t, apply(5, set(1, "http://b:8080")).Err)
+	ids := make([]int32, 0, 3)
+	for _, m := range s.fsm.members.Load().Members {
+		ids = append(ids, m.Id)
+	}
+	assert.Equal(t, []int32{0, 1, 2}, ids)
+}
+
 func TestFSM_Snapshot(t *testing.T) {
 	s := setup(t)
-	items := map[string]string{"key1": "val1", "key2": "val2"}
-	s.fsm.lastAppliedIdx = 100
+	shards := []map[string]string{
+		{"key1": "val1", "key2": "val2"},
+		{},
+		{"key3": "val3"},
+	}
+	s.fsm.applied.store(100)
+	s.fsm.appliedTerm.Store(4)
 
-	s.mockStore.On("Items").Return(items).Once()
+	s.mockStore.EXPECT().RangeEntries(mock.Anything).RunAndReturn(rangeEntries(shards...)).Once()
+	s.mockStore.EXPECT().LastVersion().Return(0).Once()
 
 	snapBytes, lastIndex, err := s.fsm.Snapshot()
 	assert.NoError(t, err)
-	assert.Equal(t, s.fsm.lastAppliedIdx, lastIndex)
+	assert.Equal(t, int64(100), lastIndex)
 
-	var snapshot fsm_v1.SnapshotState
-	err = proto.Unmarshal(snapBytes, &snapshot)
+	items := make(map[string]string)
+	h, err := decodeSnapshot(snapBytes, func(e store.Entry) { items[e.Key] = e.Value }, nil)
 	assert.NoError(t, err)
-	assert.Equal(t, items, snapshot.Items)
+	assert.Equal(t, int64(100), h.appliedIndex)
+	assert.Equal(t, int64(4), h.appliedTerm)
+	assert.Equal(t, uint64(3), h.keyCount)
+	assert.Equal(t, map[string]string{"key1": "val1", "key2": "val2", "key3": "val3"}, items)
+}
+
+// Commands like INCR aren't idempotent, so a snapshot holding state newer than its index
+// makes a replica replaying the log after the snapshot diverge.
+func TestFSM_SnapshotWhileApplying(t *testing.T) {
+	const n = 2000
+	src, _ := newStoreFSM(t)
+	// Encoding many keys takes long enough for commands to be applied meanwhile.
+	src.store.RestoreFromSnapshot(newTestItems(t, 20_000))
+	appCh := make(chan *raftapi.ApplyMessage)
+	src.appCh = appCh
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go src.Start(ctx)
+
+	cmds := make([][]byte, n+1)
+	for i := 1; i <= n; i++ {
+		var err error
+		cmds[i], err = proto.Marshal(&fsm_v1.Command{Command: &fsm_v1.Command_Incr{Incr: &fsm_v1.IncrCommand{Key: "n", Delta: 1}}})
+		require.NoError(t, err)
+	}
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := 1; i <= n; i++ {
+			appCh <- &raftapi.ApplyMessage{CommandValid: true, Command: cmds[i], CommandIndex: int64(i)}
+		}
+	})
+
+	type snapshot struct {
+		data  []byte
+		index int64
+	}
+	var snapshots []snapshot
+	for len(snapshots) < 20 && src.AppliedIndex() < n {
+		data, index, err := src.Snapshot()
+		require.NoError(t, err)
+		snapshots = append(snapshots, snapshot{data, index})
+		time.Sleep(100 * time.Microsecond)
+	}
+	wg.Wait()
+	require.NoError(t, src.WaitApplied(ctx, n))
+	require.NotEmpty(t, snapshots)
+
+	for _, snap := range snapshots {
+		dst, _ := newStoreFSM(t)
+		require.NoError(t, dst.Restore(snap.data))
+		require.Equal(t, snap.index, dst.AppliedIndex())
+		for i := snap.index + 1; i <= n; i++ {
+			require.NoError(t, dst.applyCommand(i, cmds[i]).Err)
+		}
+		val, err := dst.store.Get("n")
+		require.NoError(t, err)
+		require.Equal(t, strconv.Itoa(n), val, "snapshot at index %d", snap.index)
+	}
 }
 
 func TestFSM_Restore(t *testing.T) {
@@ -128,19 +750,175 @@ func TestFSM_Restore(t *testing.T) {
 		s.mockStore.AssertExpectations(t)
 	})
 
-	t.Run("panics on malformed snapshot", func(t *testing.T) {
+	t.Run("restores from chunked snapshot", func(t *testing.T) {
+		s := setup(t)
+		items := map[string]string{"key1": "val1", "key2": "val2"}
+		s.mockStore.EXPECT().RangeEntries(mock.Anything).RunAndReturn(rangeEntries(items)).Once()
+		s.mockStore.EXPECT().LastVersion().Return(9).Once()
+		snapBytes, _, err := s.fsm.Snapshot()
+		assert.NoError(t, err)
+
+		restored := make(map[string]string)
+		s.mockStore.EXPECT().RestoreFrom(mock.Anything).RunAndReturn(func(fill func(func(e store.Entry)) error) error {
+			return fill(func(e store.Entry) { restored[e.Key] = e.Value })
+		}).Once()
+		s.mockStore.EXPECT().RestoreVersion(uint64(9)).Return().Once()
+
+		err = s.fsm.Restore(snapBytes)
+		assert.NoError(t, err)
+		assert.Equal(t, items, restored)
+	})
+
+	t.Run("restores applied index and term", func(t *testing.T) {
+		s := setup(t)
+		s.fsm.applied.store(20)
+		s.fsm.appliedTerm.Store(3)
+		s.mockStore.EXPECT().RangeEntries(mock.Anything).RunAndReturn(rangeEntries(map[string]string{"key1": "val1"})).Once()
+		s.mockStore.EXPECT().LastVersion().Return(0).Once()
+		snapBytes, _, err := s.fsm.Snapshot()
+		require.NoError(t, err)
+
+		s.mockStore.EXPECT().RestoreFrom(mock.Anything).RunAndReturn(func(fill func(func(e store.Entry)) error) error {
+			return fill(func(e store.Entry) {})
+		}).Twice()
+		s.mockFutures.On("FulfillUpTo", int64(20), ftr.Result{Err: ftr.ErrCompacted}).Return().Once()
+		s.mockFutures.On("FulfillUpTo", int64(25), ftr.Result{Err: ftr.ErrCompacted}).Return().Once()
+
+		s.fsm.applied.store(0)
+		s.fsm.appliedTerm.Store(0)
+		require.NoError(t, s.fsm.Restore(snapBytes))
+		assert.Equal(t, int64(20), s.fsm.AppliedIndex())
+		assert.Equal(t, int64(3), s.fsm.appliedTerm.Load())
+
+		// Index and term reported by raft take precedence.
+		require.NoError(t, s.fsm.restore(snapBytes, 25, 5))
+		assert.Equal(t, int64(25), s.fsm.AppliedIndex())
+		assert.Equal(t, int64(5), s.fsm.appliedTerm.Load())
+		assert.Equal(t, int64(25), s.fsm.LastRestore().SnapshotIndex)
+	})
+
+	t.Run("restores from encrypted snapshot", func(t *testing.T) {
+		s := setup(t)
+		s.fsm.cipher = testCipher{id: 7}
+		items := map[string]string{"key1": "secret"}
+		s.mockStore.EXPECT().RangeEntries(mock.Anything).RunAndReturn(rangeEntries(items)).Once()
+		s.mockStore.EXPECT().LastVersion().Return(0).Once()
+		snapBytes, _, err := s.fsm.Snapshot()
+		require.NoError(t, err)
+		assert.True(t, isSealedSnapshot(snapBytes))
+		assert.NotContains(t, string(snapBytes), "secret")
+
+		restored := make(map[string]string)
+		s.mockStore.EXPECT().RestoreFrom(mock.Anything).RunAndReturn(func(fill func(func(e store.Entry)) error) error {
+			return fill(func(e store.Entry) { restored[e.Key] = e.Value })
+		}).Once()
+
+		require.NoError(t, s.fsm.Restore(snapBytes))
+		assert.Equal(t, items, restored)
+
+		s.fsm.cipher = nil
+		assert.ErrorIs(t, s.fsm.Restore(snapBytes), encryption.ErrDisabled)
+	})
+
+	t.Run("refuses malformed snapshot", func(t *testing.T) {
 		s := setup(t)
+		logIndex := int64(11)
+		cmdBytes, err := proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Delete{Delete: &fsm_v1.DeleteCommand{Key: "key"}},
+		})
+		assert.NoError(t, err)
+
+		s.mockFutures.On("Fulfill", logIndex, ftr.Result{Err: fsmport.ErrStateUnavailable}).Return().Once()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go s.fsm.Start(ctx)
 		s.appCh <- &raftapi.ApplyMessage{
 			SnapshotValid: true,
 			Snapshot:      []byte("invalid data"),
+			SnapshotIndex: 10,
 		}
+		s.appCh <- &raftapi.ApplyMessage{
+			CommandValid: true,
+			Command:      cmdBytes,
+			CommandIndex: logIndex,
+		}
+		time.Sleep(10 * time.Millisecond)
 
-		assert.Panics(t, func() {
-			s.fsm.Start(context.Background())
-		})
+		status := s.fsm.LastRestore()
+		if assert.NotNil(t, status) {
+			assert.Equal(t, int64(10), status.SnapshotIndex)
+			assert.NotEmpty(t, status.Error)
+		}
+		_, err = s.fsm.Read([]byte("key"))
+		assert.ErrorIs(t, err, fsmport.ErrStateUnavailable)
+		_, _, err = s.fsm.Snapshot()
+		assert.ErrorIs(t, err, fsmport.ErrStateUnavailable)
+		s.mockFutures.AssertExpectations(t)
+	})
+
+	t.Run("refuses corrupted chunked snapshot", func(t *testing.T) {
+		s := setup(t)
+		s.mockStore.EXPECT().RangeEntries(mock.Anything).RunAndReturn(rangeEntries(map[string]string{"key1": "val1"})).Once()
+		s.mockStore.EXPECT().LastVersion().Return(0).Once()
+		snapBytes, _, err := s.fsm.Snapshot()
+		assert.NoError(t, err)
+		snapBytes[len(snapBytes)-1] ^= 0xff
+
+		s.mockStore.EXPECT().RestoreFrom(mock.Anything).RunAndReturn(func(fill func(func(e store.Entry)) error) error {
+			return fill(func(e store.Entry) {})
+		}).Once()
+
+		assert.True(t, s.fsm.Available())
+		err = s.fsm.Restore(snapBytes)
+		assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+		assert.NotEmpty(t, s.fsm.LastRestore().Error)
+		assert.False(t, s.fsm.Available())
+		assert.False(t, s.fsm.Restoring())
 	})
 }
 
+func TestFSM_WaitApplied(t *testing.T) {
+	s := setup(t)
+	s.mockStore.On("Delete", "key").Return(nil)
+	s.mockFutures.On("Fulfill", mock.Anything, ftr.Result{}).Return()
+	cmdBytes, err := proto.Marshal(&fsm_v1.Command{
+		Command: &fsm_v1.Command_Delete{Delete: &fsm_v1.DeleteCommand{Key: "key"}},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.fsm.Start(ctx)
+	assert.True(t, s.fsm.AppliedAt().IsZero())
+	start := time.Now()
+
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- s.fsm.WaitApplied(ctx, 2) }()
+
+	s.appCh <- &raftapi.ApplyMessage{CommandValid: true, Command: cmdBytes, CommandIndex: 1}
+	select {
+	case err := <-waitErr:
+		t.Fatalf("wait returned before index was applied: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.appCh <- &raftapi.ApplyMessage{CommandValid: true, Command: cmdBytes, CommandIndex: 2}
+	select {
+	case err := <-waitErr:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("wait wasn't released after index was applied")
+	}
+	assert.Equal(t, int64(2), s.fsm.AppliedIndex())
+	assert.False(t, s.fsm.AppliedAt().Before(start))
+	assert.NoError(t, s.fsm.WaitApplied(ctx, 1))
+
+	tCtx, tCancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer tCancel()
+	assert.ErrorIs(t, s.fsm.WaitApplied(tCtx, 3), context.DeadlineExceeded)
+}
+
 func TestFSM_Read(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		s := setup(t)
@@ -18,6 +18,7 @@ type applyFuture struct {
 	mu       sync.RWMutex
 	promises map[int64]*promise
 	pool     sync.Pool
+	pending  atomic.Int64
 }
 
 func NewApplyFuture() *applyFuture {
@@ -50,7 +51,11 @@ func (af *applyFuture) cleanMap() {
 	af.mu.Lock()
 	defer af.mu.Unlock()
 	for i, p := range af.promises {
-		if atomic.LoadUint32(&p.isStale) == stale || isClosed(p.done) {
+		closed := isClosed(p.done)
+		if atomic.LoadUint32(&p.isStale) == stale || closed {
+			if !closed {
+				af.pending.Add(-1)
+			}
 			delete(af.promises, i)
 			af.pool.Put(p)
 		}
@@ -68,10 +73,15 @@ func (af *applyFuture) NewFuture(logIdx int64) ftr.Future {
 	p := af.pool.Get().(*promise)
 	p.reset()
 	af.promises[logIdx] = p
+	af.pending.Add(1)
 	return p
 }
 
-func isClosed(ch chan struct{}) bool {
+func (af *applyFuture) Pending() int {
+	return int(af.pending.Load())
+}
+
+func isClosed(ch <-chan struct{}) bool {
 	if ch == nil {
 		return false
 	}
@@ -83,16 +93,34 @@ func isClosed(ch chan struct{}) bool {
 	}
 }
 
-func (af *applyFuture) Fulfill(logIdx int64) {
+func (af *applyFuture) Fulfill(logIdx int64, res ftr.Result) {
 	af.mu.Lock()
 	defer af.mu.Unlock()
-	if p, exists := af.promises[logIdx]; exists {
-		close(p.done)
-	} else {
-		p := af.pool.Get().(*promise)
+	p, exists := af.promises[logIdx]
+	if !exists {
+		p = af.pool.Get().(*promise)
 		p.reset()
-		close(p.done)
 		af.promises[logIdx] = p
+	} else if !isClosed(p.done) {
+		af.pending.Add(-1)
+	}
+	if isClosed(p.done) {
+		return
+	}
+	p.res = res
+	close(p.done)
+}
+
+func (af *applyFuture) FulfillUpTo(logIdx int64, res ftr.Result) {
+	af.mu.Lock()
+	defer af.mu.Unlock()
+	for i, p := range af.promises {
+		if i > logIdx || isClosed(p.done) {
+			continue
+		}
+		af.pending.Add(-1)
+		p.res = res
+		close(p.done)
 	}
 }
 
@@ -104,10 +132,12 @@ const (
 type promise struct {
 	isStale uint32
 	done    chan struct{}
+	res     ftr.Result
 }
 
 func (p *promise) reset() {
 	p.done = make(chan struct{})
+	p.res = ftr.Result{}
 	atomic.StoreUint32(&p.isStale, nonStale)
 }
 
@@ -117,6 +147,14 @@ func (p *promise) Wait(ctx context.Context) error {
 		atomic.StoreUint32(&p.isStale, stale)
 		return ftr.ErrPromiseTimeout
 	case <-p.done:
-		return nil
+		return p.res.Err
 	}
 }
+
+func (p *promise) Data() []byte {
+	return p.res.Data
+}
+
+func (p *promise) Result() ftr.Result {
+	return p.res
+}
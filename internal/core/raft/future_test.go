@@ -32,7 +32,7 @@ func TestApplyFuture_NewFuture_And_Fulfill(t *testing.T) {
 
 	time.Sleep(10 * time.Millisecond)
 
-	af.Fulfill(logIdx)
+	af.Fulfill(logIdx, ftr.Result{})
 	wg.Wait()
 
 	assert.NoError(t, err)
@@ -42,7 +42,7 @@ func TestApplyFuture_Fulfill_Before_NewFuture(t *testing.T) {
 	af := NewApplyFuture()
 	logIdx := int64(2)
 
-	af.Fulfill(logIdx)
+	af.Fulfill(logIdx, ftr.Result{})
 
 	future := af.NewFuture(logIdx)
 	require.NotNil(t, future)
@@ -75,7 +75,7 @@ func TestApplyFuture_GC(t *testing.T) {
 	af := NewApplyFuture()
 
 	future1 := af.NewFuture(1)
-	af.Fulfill(1)
+	af.Fulfill(1, ftr.Result{})
 	err := future1.Wait(context.Background())
 	require.NoError(t, err)
 
@@ -139,6 +139,45 @@ func TestApplyFuture_NewFuture_Idempotency(t *testing.T) {
 	})
 
 	time.Sleep(10 * time.Millisecond)
-	af.Fulfill(logIdx)
+	af.Fulfill(logIdx, ftr.Result{})
 	wg.Wait()
 }
+
+func TestApplyFuture_Pending(t *testing.T) {
+	af := NewApplyFuture()
+
+	af.NewFuture(1)
+	af.NewFuture(2)
+	af.NewFuture(2)
+	assert.Equal(t, 2, af.Pending())
+
+	af.Fulfill(1, ftr.Result{})
+	af.Fulfill(1, ftr.Result{})
+	assert.Equal(t, 1, af.Pending())
+
+	// Fulfilled before anyone waits for it.
+	af.Fulfill(3, ftr.Result{})
+	af.NewFuture(3)
+	assert.Equal(t, 1, af.Pending())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = af.NewFuture(2).Wait(ctx)
+	af.cleanMap()
+	assert.Equal(t, 0, af.Pending())
+}
+
+func TestApplyFuture_FulfillUpTo(t *testing.T) {
+	af := NewApplyFuture()
+	f1, f2, f3 := af.NewFuture(1), af.NewFuture(2), af.NewFuture(3)
+	af.Fulfill(1, ftr.Result{})
+
+	af.FulfillUpTo(2, ftr.Result{Err: ftr.ErrCompacted})
+
+	assert.NoError(t, f1.Wait(context.Background()))
+	assert.ErrorIs(t, f2.Wait(context.Background()), ftr.ErrCompacted)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, f3.Wait(ctx), ftr.ErrPromiseTimeout)
+	assert.Equal(t, 1, af.Pending())
+}
@@ -0,0 +1,227 @@
+package raft
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+)
+
+// leaseTable holds replicated leases. It's changed only by the apply loop, but read by API handlers
+// and the reaper. Expiry is checked against the time stamped in commands, so every replica
+// sees a lease expire at the same point of the log.
+type leaseTable struct {
+	mu     sync.RWMutex
+	leases map[int64]*lease
+	// owners maps attached keys to ids of their leases.
+	owners map[string]int64
+	// lastID is the last id given to a lease or a fencing token.
+	lastID int64
+}
+
+type lease struct {
+	ttl       int64
+	expiresAt int64
+	keys      map[string]struct{}
+}
+
+func (l *lease) expired(now int64) bool {
+	return l.expiresAt <= now
+}
+
+// nextID returns a new id for a lease or a fencing token of the command at the log index. It's the index
+// itself, unless an earlier command batched in the same entry took it, then the next unused id.
+// Ids only grow, so fencing tokens of a key do too.
+func (t *leaseTable) nextID(index int64) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastID = max(index, t.lastID+1)
+	return t.lastID
+}
+
+func (t *leaseTable) grant(id, ttl, now int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.leases == nil {
+		t.leases = make(map[int64]*lease)
+		t.owners = make(map[string]int64)
+	}
+	if _, ok := t.leases[id]; ok {
+		return ErrLeaseExists
+	}
+	t.leases[id] = &lease{ttl: ttl, expiresAt: now + ttl, keys: make(map[string]struct{})}
+	return nil
+}
+
+// keepAlive moves the deadline of a live lease and returns it.
+func (t *leaseTable) keepAlive(id, now int64) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l, ok := t.leases[id]
+	if !ok || l.expired(now) {
+		return 0, fsmport.ErrLeaseNotFound
+	}
+	l.expiresAt = now + l.ttl
+	return l.expiresAt, nil
+}
+
+// alive reports whether the lease exists and isn't expired by now.
+func (t *leaseTable) alive(id, now int64) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	l, ok := t.leases[id]
+	return ok && !l.expired(now)
+}
+
+// owner returns id of the lease the key is attached to.
+func (t *leaseTable) owner(key string) (int64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	id, ok := t.owners[key]
+	return id, ok
+}
+
+// attach attaches the key to a live lease, detaching it from its previous lease.
+func (t *leaseTable) attach(id int64, key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if prev, ok := t.owners[key]; ok {
+		delete(t.leases[prev].keys, key)
+	}
+	t.leases[id].keys[key] = struct{}{}
+	t.owners[key] = id
+}
+
+// detach detaches the keys from their leases, e.g. after they were overwritten or deleted.
+// Nil table belongs to keys outside of the default namespace, which have no leases.
+func (t *leaseTable) detach(keys ...string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, key := range keys {
+		if id, ok := t.owners[key]; ok {
+			delete(t.leases[id].keys, key)
+			delete(t.owners, key)
+		}
+	}
+}
+
+// detachAll detaches every key keeping the leases.
+func (t *leaseTable) detachAll() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, l := range t.leases {
+		clear(l.keys)
+	}
+	clear(t.owners)
+}
+
+// revoke deletes the lease and returns keys which were attached to it in sorted order.
+func (t *leaseTable) revoke(id int64) ([]string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l, ok := t.leases[id]
+	if !ok {
+		return nil, false
+	}
+	delete(t.leases, id)
+	keys := slices.Sorted(maps.Keys(l.keys))
+	for _, key := range keys {
+		delete(t.owners, key)
+	}
+	return keys, true
+}
+
+func (t *leaseTable) get(id, now int64) (fsmport.Lease, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	l, ok := t.leases[id]
+	if !ok || l.expired(now) {
+		return fsmport.Lease{}, false
+	}
+	return fsmport.Lease{
+		ID:        id,
+		TTLMillis: l.ttl,
+		ExpiresAt: time.UnixMilli(l.expiresAt),
+		Keys:      slices.Sorted(maps.Keys(l.keys)),
+	}, true
+}
+
+// expired returns ids of up to limit leases expired by now in ascending order.
+func (t *leaseTable) expired(now int64, limit int) []int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var ids []int64
+	for id, l := range t.leases {
+		if l.expired(now) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids[:min(len(ids), limit)]
+}
+
+// last returns the last id given to a lease or a fencing token.
+func (t *leaseTable) last() int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lastID
+}
+
+// snapshot returns the leases ordered by id.
+func (t *leaseTable) snapshot() []*fsm_v1.Lease {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]*fsm_v1.Lease, 0, len(t.leases))
+	for _, id := range slices.Sorted(maps.Keys(t.leases)) {
+		l := t.leases[id]
+		out = append(out, &fsm_v1.Lease{
+			Id:        id,
+			TtlMs:     l.ttl,
+			ExpiresAt: l.expiresAt,
+			Keys:      slices.Sorted(maps.Keys(l.keys)),
+		})
+	}
+	return out
+}
+
+// restore replaces the leases and the last given id with ones from a snapshot.
+// Snapshots taken before the last id was stored hold leases granted at their log indexes only.
+func (t *leaseTable) restore(leases []*fsm_v1.Lease, lastID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.leases = make(map[int64]*lease, len(leases))
+	t.owners = make(map[string]int64)
+	t.lastID = lastID
+	for _, pl := range leases {
+		t.lastID = max(t.lastID, pl.Id)
+		l := &lease{ttl: pl.TtlMs, expiresAt: pl.ExpiresAt, keys: make(map[string]struct{}, len(pl.Keys))}
+		for _, key := range pl.Keys {
+			l.keys[key] = struct{}{}
+			t.owners[key] = pl.Id
+		}
+		t.leases[pl.Id] = l
+	}
+}
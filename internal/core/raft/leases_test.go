@@ -0,0 +1,160 @@
+package raft
+
+import (
+	"testing"
+
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func grantCmd(ttl, now int64) *fsm_v1.Command {
+	return &fsm_v1.Command{Command: &fsm_v1.Command_LeaseGrant{LeaseGrant: &fsm_v1.LeaseGrantCommand{TtlMs: ttl, Now: now}}}
+}
+
+func lockCmd(key string, lease, now int64) *fsm_v1.Command {
+	return &fsm_v1.Command{Command: &fsm_v1.Command_Lock{Lock: &fsm_v1.LockCommand{Key: key, Lease: lease, Now: now}}}
+}
+
+func TestFSM_Leases(t *testing.T) {
+	f, apply := newStoreFSM(t)
+	st := f.store
+
+	assert.Equal(t, ftr.Result{Data: []byte("10")}, apply(10, grantCmd(100, 1000)))
+	assert.ErrorIs(t, apply(11, grantCmd(0, 1000)).Err, ErrInvalidLeaseTTL)
+
+	require.NoError(t, st.Put("data", "v"))
+	attach := func(keys ...string) *fsm_v1.Command {
+		return &fsm_v1.Command{Command: &fsm_v1.Command_LeaseAttach{LeaseAttach: &fsm_v1.LeaseAttachCommand{Id: 10, Keys: keys, Now: 1000}}}
+	}
+	assert.ErrorIs(t, apply(12, attach("data", "missing")).Err, pstore.ErrNoSuchKey)
+	assert.Equal(t, ftr.Result{Data: []byte("1")}, apply(13, attach("data")))
+
+	// The token is a new id, the index of the acquiring command, the holder gets it again.
+	assert.Equal(t, ftr.Result{Data: []byte("14")}, apply(14, lockCmd("lock", 10, 1000)))
+	assert.Equal(t, ftr.Result{Data: []byte("14")}, apply(15, lockCmd("lock", 10, 1010)))
+	apply(16, grantCmd(50, 1000))
+	assert.ErrorIs(t, apply(17, lockCmd("lock", 16, 1010)).Err, fsmport.ErrLockHeld)
+	assert.ErrorIs(t, apply(18, lockCmd("data", 16, 1010)).Err, fsmport.ErrLockHeld)
+
+	keepAlive := &fsm_v1.Command{Command: &fsm_v1.Command_LeaseKeepAlive{LeaseKeepAlive: &fsm_v1.LeaseKeepAliveCommand{Id: 10, Now: 1090}}}
+	assert.Equal(t, ftr.Result{Data: []byte("1190")}, apply(19, keepAlive))
+	lease, ok := f.Lease(10, 1100)
+	require.True(t, ok)
+	assert.Equal(t, []string{"data", "lock"}, lease.Keys)
+	assert.Equal(t, int64(100), lease.TTLMillis)
+	assert.Equal(t, int64(1190), lease.ExpiresAt.UnixMilli())
+
+	// Lease 16 expired at 1050, lease 10 expires at 1190 and its lock is taken over.
+	assert.ErrorIs(t, apply(20, lockCmd("lock", 16, 1100)).Err, fsmport.ErrLeaseNotFound)
+	apply(22, grantCmd(1000, 1195))
+	assert.Equal(t, []int64{10, 16}, f.ExpiredLeases(1195, 10))
+	assert.Equal(t, ftr.Result{Data: []byte("23")}, apply(23, lockCmd("lock", 22, 1195)))
+	_, err := st.Get("data")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+	_, ok = f.Lease(10, 0)
+	assert.False(t, ok)
+	val, err := st.Get("lock")
+	require.NoError(t, err)
+	assert.Equal(t, "23", val)
+
+	unlock := func(lease int64) *fsm_v1.Command {
+		return &fsm_v1.Command{Command: &fsm_v1.Command_Unlock{Unlock: &fsm_v1.UnlockCommand{Key: "lock", Lease: lease, Now: 1200}}}
+	}
+	assert.ErrorIs(t, apply(24, unlock(16)).Err, fsmport.ErrLockHeld)
+	assert.Equal(t, ftr.Result{}, apply(25, unlock(22)))
+	assert.ErrorIs(t, apply(26, unlock(22)).Err, pstore.ErrNoSuchKey)
+
+	// Overwritten keys are detached, so revoking the lease keeps them.
+	apply(27, lockCmd("lock", 22, 1200))
+	apply(28, &fsm_v1.Command{Command: &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: "lock", Value: "mine"}}})
+	revoke := &fsm_v1.Command{Command: &fsm_v1.Command_LeaseRevoke{LeaseRevoke: &fsm_v1.LeaseRevokeCommand{Id: 22, Now: 1200}}}
+	assert.Equal(t, ftr.Result{Data: []byte("0")}, apply(29, revoke))
+	assert.ErrorIs(t, apply(30, revoke).Err, fsmport.ErrLeaseNotFound)
+	val, err = st.Get("lock")
+	require.NoError(t, err)
+	assert.Equal(t, "mine", val)
+}
+
+func TestFSM_BatchedLeaseGrants(t *testing.T) {
+	f, apply := newStoreFSM(t)
+
+	batch := &fsm_v1.BatchCommand{}
+	for _, cmd := range []*fsm_v1.Command{
+		grantCmd(100, 1000), grantCmd(100, 1000), lockCmd("a", 5, 1000), lockCmd("b", 6, 1000),
+	} {
+		data, err := proto.Marshal(cmd)
+		require.NoError(t, err)
+		batch.Commands = append(batch.Commands, data)
+	}
+	res := apply(5, &fsm_v1.Command{Command: &fsm_v1.Command_Batch{Batch: batch}})
+	require.Len(t, res.Batch, 4)
+	assert.Equal(t, []string{"5", "6", "7", "8"}, []string{
+		string(res.Batch[0].Data), string(res.Batch[1].Data), string(res.Batch[2].Data), string(res.Batch[3].Data),
+	}, "leases and tokens of an entry get their own ids")
+
+	// Each lease holds only its own lock.
+	assert.Equal(t, "7", string(apply(6, lockCmd("a", 5, 1000)).Data))
+	assert.ErrorIs(t, apply(7, lockCmd("a", 6, 1000)).Err, fsmport.ErrLockHeld)
+	// Later commands don't reuse ids given to the batch.
+	assert.Equal(t, ftr.Result{Data: []byte("9")}, apply(8, grantCmd(100, 1000)))
+	assert.Equal(t, ftr.Result{Data: []byte("10")}, apply(10, grantCmd(100, 1000)))
+
+	data, _, err := f.Snapshot()
+	require.NoError(t, err)
+	dst, apply := newStoreFSM(t)
+	require.NoError(t, dst.Restore(data))
+	assert.Equal(t, ftr.Result{Data: []byte("11")}, apply(10, grantCmd(100, 1000)), "last id is restored from snapshot")
+}
+
+func TestLeaseTable_RejectsExistingID(t *testing.T) {
+	var tbl leaseTable
+	require.NoError(t, tbl.grant(1, 100, 1000))
+	assert.ErrorIs(t, tbl.grant(1, 100, 1000), ErrLeaseExists)
+}
+
+func TestFSM_ReapRevokesExpiredLeases(t *testing.T) {
+	f, apply := newStoreFSM(t)
+	st := f.store
+
+	apply(1, grantCmd(100, 1000))
+	apply(2, grantCmd(100, 1050))
+	apply(3, lockCmd("a", 1, 1000))
+	apply(4, lockCmd("b", 2, 1050))
+
+	// Lease 2 was kept alive after the reaper found it expired.
+	apply(5, &fsm_v1.Command{Command: &fsm_v1.Command_LeaseKeepAlive{LeaseKeepAlive: &fsm_v1.LeaseKeepAliveCommand{Id: 2, Now: 1140}}})
+	apply(6, &fsm_v1.Command{Command: &fsm_v1.Command_Reap{Reap: &fsm_v1.ReapCommand{Leases: []int64{1, 2}, Now: 1150}}})
+
+	_, err := st.Get("a")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+	_, err = st.Get("b")
+	assert.NoError(t, err)
+	assert.Empty(t, f.ExpiredLeases(1150, 10))
+	_, ok := f.Lease(2, 1150)
+	assert.True(t, ok)
+}
+
+func TestSnapshot_KeepsLeases(t *testing.T) {
+	src, apply := newStoreFSM(t)
+	apply(1, grantCmd(100, 1000))
+	apply(2, lockCmd("a", 1, 1000))
+	apply(3, grantCmd(500, 1000))
+
+	data, _, err := src.Snapshot()
+	require.NoError(t, err)
+
+	dst, apply := newStoreFSM(t)
+	require.NoError(t, dst.Restore(data))
+	for _, id := range []int64{1, 3} {
+		want, _ := src.Lease(id, 1000)
+		got, ok := dst.Lease(id, 1000)
+		require.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	assert.ErrorIs(t, apply(4, lockCmd("a", 3, 1000)).Err, fsmport.ErrLockHeld)
+}
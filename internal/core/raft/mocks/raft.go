@@ -2,7 +2,9 @@ package mocks
 
 import (
 	"context"
+	"sync/atomic"
 
+	"github.com/shrtyk/kv-store/internal/core/jsondoc"
 	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
 	raftapi "github.com/shrtyk/raft-core/api"
@@ -17,7 +19,7 @@ type StubRaft struct {
 	errCh         chan error
 	isLeader      bool
 	leaderID      int
-	killed        bool
+	killed        atomic.Bool
 	term          int64
 	readOnlyData  []byte
 	readOnlyError error
@@ -47,16 +49,63 @@ func (m *StubRaft) Submit(data []byte) *raftapi.SubmitResult {
 		return &raftapi.SubmitResult{IsLeader: true}
 	}
 
+	m.apply(cmd)
+
+	return &raftapi.SubmitResult{
+		IsLeader: true,
+		LogIndex: 1,
+	}
+}
+
+func (m *StubRaft) apply(cmd *fsm_v1.Command) {
 	switch c := cmd.Command.(type) {
 	case *fsm_v1.Command_Put:
 		_ = m.store.Put(c.Put.Key, c.Put.Value)
 	case *fsm_v1.Command_Delete:
 		_ = m.store.Delete(c.Delete.Key)
-	}
-
-	return &raftapi.SubmitResult{
-		IsLeader: true,
-		LogIndex: 1,
+	case *fsm_v1.Command_Hset:
+		_, _ = m.store.HSet(c.Hset.Key, c.Hset.Fields, c.Hset.Now)
+	case *fsm_v1.Command_Hdel:
+		_, _ = m.store.HDel(c.Hdel.Key, c.Hdel.Fields, c.Hdel.Now)
+	case *fsm_v1.Command_Hincrby:
+		_, _ = m.store.HIncrBy(c.Hincrby.Key, c.Hincrby.Field, c.Hincrby.Delta, c.Hincrby.Now)
+	case *fsm_v1.Command_Push:
+		_, _ = m.store.Push(c.Push.Key, c.Push.Values, c.Push.Left, c.Push.Now)
+	case *fsm_v1.Command_Pop:
+		_, _ = m.store.Pop(c.Pop.Key, int(c.Pop.Count), c.Pop.Left, c.Pop.Now)
+	case *fsm_v1.Command_Zadd:
+		members := make([]store.ScoredMember, 0, len(c.Zadd.Members))
+		for _, sm := range c.Zadd.Members {
+			members = append(members, store.ScoredMember{Member: sm.Member, Score: sm.Score})
+		}
+		_, _ = m.store.ZAdd(c.Zadd.Key, members, c.Zadd.Now)
+	case *fsm_v1.Command_Zrem:
+		_, _ = m.store.ZRem(c.Zrem.Key, c.Zrem.Members, c.Zrem.Now)
+	case *fsm_v1.Command_Zincrby:
+		_, _ = m.store.ZIncrBy(c.Zincrby.Key, c.Zincrby.Member, c.Zincrby.Delta, c.Zincrby.Now)
+	case *fsm_v1.Command_Zpopmin:
+		_, _ = m.store.ZPopMin(c.Zpopmin.Key, int(c.Zpopmin.Count), c.Zpopmin.Now)
+	case *fsm_v1.Command_JsonPatch:
+		var doc []byte
+		e, err := m.store.Lookup(c.JsonPatch.Key, c.JsonPatch.Now)
+		if err == nil {
+			doc = []byte(e.Value)
+		}
+		if patched, err := jsondoc.Patch(doc, c.JsonPatch.Patch, jsondoc.PatchType(c.JsonPatch.Type)); err == nil {
+			_ = m.store.PutEntry(store.Entry{Key: c.JsonPatch.Key, Value: string(patched), ExpiresAt: e.ExpiresAt, Flags: e.Flags})
+		}
+	case *fsm_v1.Command_Lock:
+		// Every stubbed entry is at index 1, so it's the fencing token.
+		_ = m.store.Put(c.Lock.Key, "1")
+	case *fsm_v1.Command_Unlock:
+		_ = m.store.Delete(c.Unlock.Key)
+	case *fsm_v1.Command_Batch:
+		for _, data := range c.Batch.Commands {
+			sub := &fsm_v1.Command{}
+			if err := proto.Unmarshal(data, sub); err == nil {
+				m.apply(sub)
+			}
+		}
 	}
 }
 
@@ -72,6 +121,11 @@ func (m *StubRaft) ReadOnly(ctx context.Context, query []byte) (*raftapi.ReadOnl
 		return nil, m.readOnlyError
 	}
 
+	// Empty query is a read barrier like in the state machine.
+	if m.readOnlyData == nil && len(query) == 0 {
+		return &raftapi.ReadOnlyResult{IsLeader: true}, nil
+	}
+
 	if m.readOnlyData == nil {
 		key := string(query)
 		val, err := m.store.Get(key)
@@ -103,17 +157,17 @@ func (m *StubRaft) PersistedStateSize() (int, error) {
 }
 
 func (m *StubRaft) Start() error {
-	m.killed = false
+	m.killed.Store(false)
 	return nil
 }
 
 func (m *StubRaft) Stop() error {
-	m.killed = true
+	m.killed.Store(true)
 	return nil
 }
 
 func (m *StubRaft) Killed() bool {
-	return m.killed
+	return m.killed.Load()
 }
 
 func (m *StubRaft) Errors() <-chan error {
@@ -0,0 +1,113 @@
+package raft
+
+import (
+	"testing"
+
+	fsmport "github.com/shrtyk/kv-store/internal/core/ports/fsm"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createNamespaceCmd(name string, maxKeys int32) *fsm_v1.Command {
+	return &fsm_v1.Command{Command: &fsm_v1.Command_NamespaceCreate{NamespaceCreate: &fsm_v1.NamespaceCreateCommand{
+		Namespace: &fsm_v1.Namespace{Name: name, MaxKeys: maxKeys},
+	}}}
+}
+
+func nsPutCmd(namespace, key, value string) *fsm_v1.Command {
+	return &fsm_v1.Command{
+		Namespace: namespace,
+		Command:   &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: key, Value: value}},
+	}
+}
+
+func TestFSM_Namespaces(t *testing.T) {
+	f, apply := newStoreFSM(t, withNamespaces())
+
+	assert.ErrorIs(t, apply(1, nsPutCmd("team-a", "k", "a")).Err, pstore.ErrNoSuchNamespace)
+	require.NoError(t, apply(2, createNamespaceCmd("team-a", 1)).Err)
+	assert.ErrorIs(t, apply(3, createNamespaceCmd("team-a", 0)).Err, pstore.ErrNamespaceExists)
+
+	require.NoError(t, apply(4, nsPutCmd("team-a", "k", "a")).Err)
+	require.NoError(t, apply(5, nsPutCmd("", "k", "default")).Err)
+	assert.ErrorIs(t, apply(6, nsPutCmd("team-a", "k2", "a")).Err, pstore.ErrQuotaExceeded)
+
+	val, err := f.Read(fsmport.ReadQuery("team-a", "k"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(val))
+	val, err = f.Read(fsmport.ReadQuery("", "k"))
+	require.NoError(t, err)
+	assert.Equal(t, "default", string(val))
+
+	lock := lockCmd("lock", 1, 1000)
+	lock.Namespace = "team-a"
+	assert.ErrorIs(t, apply(7, lock).Err, fsmport.ErrNamespacedLease)
+
+	deleteNs := &fsm_v1.Command{Command: &fsm_v1.Command_NamespaceDelete{NamespaceDelete: &fsm_v1.NamespaceDeleteCommand{Name: "team-a"}}}
+	require.NoError(t, apply(8, deleteNs).Err)
+	assert.ErrorIs(t, apply(9, deleteNs).Err, pstore.ErrNoSuchNamespace)
+	_, err = f.Read(fsmport.ReadQuery("team-a", "k"))
+	assert.ErrorIs(t, err, pstore.ErrNoSuchNamespace)
+}
+
+func TestSnapshot_KeepsNamespaces(t *testing.T) {
+	src, apply := newStoreFSM(t, withNamespaces())
+	require.NoError(t, apply(1, createNamespaceCmd("team-a", 10)).Err)
+	require.NoError(t, apply(2, createNamespaceCmd("team-b", 0)).Err)
+	require.NoError(t, apply(3, nsPutCmd("team-a", "k", "a")).Err)
+	require.NoError(t, apply(4, nsPutCmd("", "k", "default")).Err)
+
+	data, _, err := src.Snapshot()
+	require.NoError(t, err)
+
+	dst, _ := newStoreFSM(t, withNamespaces())
+	require.NoError(t, dst.Restore(data))
+	assert.Equal(t, []pstore.Namespace{{Name: "team-a", MaxKeys: 10}, {Name: "team-b"}}, dst.namespaces.List())
+	val, err := dst.Read(fsmport.ReadQuery("team-a", "k"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(val))
+	val, err = dst.Read(fsmport.ReadQuery("", "k"))
+	require.NoError(t, err)
+	assert.Equal(t, "default", string(val))
+	_, err = dst.Read(fsmport.ReadQuery("team-b", "k"))
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+}
+
+func TestDecodeSnapshot_PassesNamespaceEntries(t *testing.T) {
+	src, apply := newStoreFSM(t, withNamespaces())
+	require.NoError(t, apply(1, createNamespaceCmd("team-a", 0)).Err)
+	require.NoError(t, apply(2, nsPutCmd("team-a", "k", "a")).Err)
+	require.NoError(t, apply(3, nsPutCmd("", "k", "default")).Err)
+	data, _, err := src.Snapshot()
+	require.NoError(t, err)
+
+	var defaults, namespaced []string
+	h, err := decodeSnapshot(data, func(e pstore.Entry) { defaults = append(defaults, e.Key) },
+		func(spaces []pstore.Namespace, fill func(put func(string, pstore.Entry)) error) error {
+			assert.Equal(t, []pstore.Namespace{{Name: "team-a"}}, spaces)
+			assert.Equal(t, []string{"k"}, defaults, "default entries come first")
+			return fill(func(ns string, e pstore.Entry) { namespaced = append(namespaced, ns+"/"+e.Key) })
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a/k"}, namespaced)
+	assert.Equal(t, src.store.LastVersion(), h.lastVersion, "chunks after namespace entries are decoded")
+
+	_, err = decodeSnapshot(data, func(pstore.Entry) {}, nil)
+	assert.ErrorIs(t, err, ErrNamespacesDisabled)
+}
+
+func TestReadQuery(t *testing.T) {
+	for _, tc := range []struct{ namespace, key string }{
+		{"", "k"},
+		{"", "\x00k"},
+		{"team-a", "k"},
+		{"team-a", ""},
+	} {
+		namespace, key := fsmport.ParseReadQuery(fsmport.ReadQuery(tc.namespace, tc.key))
+		assert.Equal(t, tc.namespace, namespace)
+		assert.Equal(t, tc.key, key)
+	}
+	assert.Equal(t, []byte("k"), fsmport.ReadQuery("", "k"))
+}
@@ -0,0 +1,274 @@
+package raft
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/encryption"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	"github.com/shrtyk/kv-store/internal/core/ports/metrics"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ proposer.Proposer = (*directProposer)(nil)
+	_ proposer.Proposer = (*Batcher)(nil)
+	_ proposer.Proposer = (*sealingProposer)(nil)
+	_ proposer.Proposer = (*stampingProposer)(nil)
+	_ ftr.Future        = (*batchFuture)(nil)
+)
+
+// directProposer submits every command as a separate log entry.
+type directProposer struct {
+	raft    raftapi.Raft
+	futures ftr.FuturesStore
+}
+
+func NewProposer(raft raftapi.Raft, futures ftr.FuturesStore) *directProposer {
+	return &directProposer{raft: raft, futures: futures}
+}
+
+func (p *directProposer) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	res := p.raft.Submit(cmd)
+	prop := &proposer.Proposal{
+		IsLeader: res.IsLeader,
+		LeaderID: res.LeaderID,
+		LogIndex: res.LogIndex,
+	}
+	if res.IsLeader {
+		prop.Future = p.futures.NewFuture(res.LogIndex)
+	}
+	return prop, nil
+}
+
+// sealingProposer encrypts commands before passing them to the next proposer,
+// so the raft log never contains plaintext keys and values.
+type sealingProposer struct {
+	next   proposer.Proposer
+	cipher encryption.Cipher
+}
+
+func NewSealingProposer(next proposer.Proposer, c encryption.Cipher) *sealingProposer {
+	return &sealingProposer{next: next, cipher: c}
+}
+
+func (p *sealingProposer) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	sealed, err := p.cipher.Encrypt(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt command: %w", err)
+	}
+	data, err := proto.Marshal(&fsm_v1.Command{Command: &fsm_v1.Command_Sealed{Sealed: sealed}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sealed command: %w", err)
+	}
+	return p.next.Propose(ctx, data)
+}
+
+// commandNowField is the number of the Command field holding the leader's time.
+var commandNowField = (&fsm_v1.Command{}).ProtoReflect().Descriptor().Fields().ByName("now").Number()
+
+// stampingProposer stamps commands with the local time before passing them to the next proposer.
+// Only the leader gets its commands into the log, so replicas apply the time of the leader.
+type stampingProposer struct {
+	next proposer.Proposer
+	now  func() time.Time
+}
+
+func NewStampingProposer(next proposer.Proposer) *stampingProposer {
+	return &stampingProposer{next: next, now: time.Now}
+}
+
+func (p *stampingProposer) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	// Parsing concatenated messages merges them, so the field is appended without unmarshaling the command.
+	data := protowire.AppendTag(slices.Clip(cmd), commandNowField, protowire.VarintType)
+	data = protowire.AppendVarint(data, uint64(p.now().UnixMilli()))
+	return p.next.Propose(ctx, data)
+}
+
+type batchReq struct {
+	cmd   []byte
+	resCh chan batchRes
+}
+
+type batchRes struct {
+	prop *proposer.Proposal
+	err  error
+}
+
+// Batcher coalesces concurrently proposed commands into a single log entry.
+type Batcher struct {
+	cfg     *cfg.BatchCfg
+	raft    raftapi.Raft
+	futures ftr.FuturesStore
+	metrics metrics.Metrics
+	logger  *slog.Logger
+
+	reqs chan *batchReq
+}
+
+func NewBatcher(
+	c *cfg.BatchCfg,
+	raft raftapi.Raft,
+	futures ftr.FuturesStore,
+	m metrics.Metrics,
+	l *slog.Logger,
+) *Batcher {
+	return &Batcher{
+		cfg:     c,
+		raft:    raft,
+		futures: futures,
+		metrics: m,
+		logger:  l,
+		reqs:    make(chan *batchReq, max(c.MaxSize, 1)),
+	}
+}
+
+// Propose enqueues the command and waits until the batch it belongs to is submitted.
+func (b *Batcher) Propose(ctx context.Context, cmd []byte) (*proposer.Proposal, error) {
+	req := &batchReq{cmd: cmd, resCh: make(chan batchRes, 1)}
+	select {
+	case b.reqs <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case res := <-req.resCh:
+		return res.prop, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Start collects and submits batches until ctx is done.
+func (b *Batcher) Start(ctx context.Context) {
+	batch := make([]*batchReq, 0, b.cfg.MaxSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-b.reqs:
+			batch = append(batch[:0], req)
+		}
+
+		batch = b.collect(ctx, batch)
+		b.flush(batch)
+		clear(batch)
+	}
+}
+
+// collect appends queued requests to the batch until the window
+// is over or the batch is full.
+func (b *Batcher) collect(ctx context.Context, batch []*batchReq) []*batchReq {
+	size := len(batch[0].cmd)
+	full := func() bool {
+		return (b.cfg.MaxSize > 0 && len(batch) >= b.cfg.MaxSize) ||
+			(b.cfg.MaxBytes > 0 && size >= b.cfg.MaxBytes)
+	}
+
+	if b.cfg.Window <= 0 {
+		for !full() {
+			select {
+			case req := <-b.reqs:
+				batch = append(batch, req)
+				size += len(req.cmd)
+			default:
+				return batch
+			}
+		}
+		return batch
+	}
+
+	t := time.NewTimer(b.cfg.Window)
+	defer t.Stop()
+	for !full() {
+		select {
+		case req := <-b.reqs:
+			batch = append(batch, req)
+			size += len(req.cmd)
+		case <-t.C:
+			return batch
+		case <-ctx.Done():
+			return batch
+		}
+	}
+	return batch
+}
+
+func (b *Batcher) flush(batch []*batchReq) {
+	data := batch[0].cmd
+	if len(batch) > 1 {
+		cmds := make([][]byte, len(batch))
+		for i, req := range batch {
+			cmds[i] = req.cmd
+		}
+		var err error
+		data, err = proto.Marshal(&fsm_v1.Command{
+			Command: &fsm_v1.Command_Batch{Batch: &fsm_v1.BatchCommand{Commands: cmds}},
+		})
+		if err != nil {
+			b.logger.Error("failed to marshal batch command", logger.ErrorAttr(err))
+			for _, req := range batch {
+				req.resCh <- batchRes{err: fmt.Errorf("failed to marshal batch command: %w", err)}
+			}
+			return
+		}
+	}
+
+	res := b.raft.Submit(data)
+	var future ftr.Future
+	if res.IsLeader {
+		b.metrics.ProposalBatch(len(batch))
+		future = b.futures.NewFuture(res.LogIndex)
+	}
+
+	for i, req := range batch {
+		prop := &proposer.Proposal{
+			IsLeader: res.IsLeader,
+			LeaderID: res.LeaderID,
+			LogIndex: res.LogIndex,
+		}
+		switch {
+		case !res.IsLeader:
+		case len(batch) == 1:
+			prop.Future = future
+		default:
+			prop.Future = &batchFuture{future: future, pos: i}
+		}
+		req.resCh <- batchRes{prop: prop}
+	}
+}
+
+// batchFuture is a future of a single command within a batch.
+type batchFuture struct {
+	future ftr.Future
+	pos    int
+}
+
+func (f *batchFuture) Wait(ctx context.Context) error {
+	if err := f.future.Wait(ctx); err != nil {
+		return err
+	}
+	return f.Result().Err
+}
+
+func (f *batchFuture) Data() []byte {
+	return f.Result().Data
+}
+
+func (f *batchFuture) Result() ftr.Result {
+	res := f.future.Result()
+	if res.Err != nil || f.pos >= len(res.Batch) {
+		return ftr.Result{Err: res.Err}
+	}
+	return res.Batch[f.pos]
+}
@@ -0,0 +1,150 @@
+package raft
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	ftr "github.com/shrtyk/kv-store/internal/core/ports/futures"
+	metricsmocks "github.com/shrtyk/kv-store/internal/core/ports/metrics/mocks"
+	"github.com/shrtyk/kv-store/internal/core/ports/proposer"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	rmocks "github.com/shrtyk/kv-store/internal/core/raft/mocks"
+	"github.com/shrtyk/kv-store/pkg/logger"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	raftapi "github.com/shrtyk/raft-core/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordingRaft remembers submitted entries instead of replicating them.
+type recordingRaft struct {
+	*rmocks.StubRaft
+	mu      sync.Mutex
+	entries [][]byte
+}
+
+func (r *recordingRaft) Submit(data []byte) *raftapi.SubmitResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, data)
+	return &raftapi.SubmitResult{IsLeader: true, LogIndex: int64(len(r.entries))}
+}
+
+func putCmd(t *testing.T, key string) []byte {
+	t.Helper()
+	b, err := proto.Marshal(&fsm_v1.Command{
+		Command: &fsm_v1.Command_Put{Put: &fsm_v1.PutCommand{Key: key, Value: "v"}},
+	})
+	require.NoError(t, err)
+	return b
+}
+
+func TestDirectProposer(t *testing.T) {
+	r := &recordingRaft{StubRaft: rmocks.NewStubRaft(nil, true, 0)}
+	af := NewApplyFuture()
+	p := NewProposer(r, af)
+
+	prop, err := p.Propose(context.Background(), putCmd(t, "k"))
+	require.NoError(t, err)
+	assert.True(t, prop.IsLeader)
+	assert.Equal(t, int64(1), prop.LogIndex)
+	require.NotNil(t, prop.Future)
+
+	follower := NewProposer(rmocks.NewStubRaft(nil, false, 2), af)
+	prop, err = follower.Propose(context.Background(), putCmd(t, "k"))
+	require.NoError(t, err)
+	assert.False(t, prop.IsLeader)
+	assert.Equal(t, 2, prop.LeaderID)
+	assert.Nil(t, prop.Future)
+}
+
+func TestSealingProposer(t *testing.T) {
+	r := &recordingRaft{StubRaft: rmocks.NewStubRaft(nil, true, 0)}
+	c := testCipher{id: 3}
+	p := NewSealingProposer(NewProposer(r, NewApplyFuture()), c)
+
+	cmd := putCmd(t, "secret-key")
+	_, err := p.Propose(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Len(t, r.entries, 1)
+	assert.NotContains(t, string(r.entries[0]), "secret-key")
+
+	var entry fsm_v1.Command
+	require.NoError(t, proto.Unmarshal(r.entries[0], &entry))
+	sealed, ok := entry.Command.(*fsm_v1.Command_Sealed)
+	require.True(t, ok)
+	opened, err := c.Decrypt(sealed.Sealed)
+	require.NoError(t, err)
+	assert.Equal(t, cmd, opened)
+}
+
+func TestBatcher(t *testing.T) {
+	r := &recordingRaft{StubRaft: rmocks.NewStubRaft(nil, true, 0)}
+	af := NewApplyFuture()
+	m := metricsmocks.NewMockMetrics(t)
+	m.On("ProposalBatch", 3).Return().Once()
+
+	b := NewBatcher(&cfg.BatchCfg{Window: time.Second, MaxSize: 3}, r, af, m, logger.NewLogger("dev"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Start(ctx)
+
+	props := make([]*proposer.Proposal, 3)
+	var wg sync.WaitGroup
+	for i := range props {
+		wg.Go(func() {
+			prop, err := b.Propose(ctx, putCmd(t, "k"))
+			assert.NoError(t, err)
+			props[i] = prop
+		})
+	}
+	wg.Wait()
+
+	require.Len(t, r.entries, 1)
+	var cmd fsm_v1.Command
+	require.NoError(t, proto.Unmarshal(r.entries[0], &cmd))
+	require.Len(t, cmd.GetBatch().GetCommands(), 3)
+
+	af.Fulfill(1, ftr.Result{Batch: []ftr.Result{{}, {Err: store.ErrQuotaExceeded}, {}}})
+
+	var failed int
+	for _, prop := range props {
+		assert.Equal(t, int64(1), prop.LogIndex)
+		bf, ok := prop.Future.(*batchFuture)
+		require.True(t, ok)
+		err := prop.Future.Wait(ctx)
+		if bf.pos == 1 {
+			assert.ErrorIs(t, err, store.ErrQuotaExceeded)
+			failed++
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, 1, failed)
+}
+
+func TestBatcher_SingleCommand(t *testing.T) {
+	r := &recordingRaft{StubRaft: rmocks.NewStubRaft(nil, true, 0)}
+	af := NewApplyFuture()
+	m := metricsmocks.NewMockMetrics(t)
+	m.On("ProposalBatch", 1).Return().Once()
+
+	b := NewBatcher(&cfg.BatchCfg{MaxSize: 8}, r, af, m, logger.NewLogger("dev"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Start(ctx)
+
+	cmd := putCmd(t, "k")
+	prop, err := b.Propose(ctx, cmd)
+	require.NoError(t, err)
+
+	// A lone command is submitted as is, without batch wrapper.
+	require.Len(t, r.entries, 1)
+	assert.Equal(t, cmd, r.entries[0])
+	_, isBatch := prop.Future.(*batchFuture)
+	assert.False(t, isBatch)
+}
@@ -0,0 +1,68 @@
+package raft
+
+import "sync"
+
+// pushWatchers wakes up goroutines waiting for values pushed to lists.
+type pushWatchers struct {
+	mu sync.Mutex
+	// watches holds a watch per key somebody waits for.
+	watches map[string]*pushWatch
+}
+
+// pushWatch is shared by every waiter of a key and is replaced after each push.
+type pushWatch struct {
+	ch   chan struct{}
+	refs int
+}
+
+func (p *pushWatchers) watch(key string) (<-chan struct{}, func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.watches == nil {
+		p.watches = make(map[string]*pushWatch)
+	}
+	w, ok := p.watches[key]
+	if !ok {
+		w = &pushWatch{ch: make(chan struct{})}
+		p.watches[key] = w
+	}
+	w.refs++
+
+	var once sync.Once
+	return w.ch, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			// Watch is already gone if it was fired.
+			if p.watches[key] != w {
+				return
+			}
+			if w.refs--; w.refs == 0 {
+				delete(p.watches, key)
+			}
+		})
+	}
+}
+
+// notify wakes up waiters of the key.
+func (p *pushWatchers) notify(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if w, ok := p.watches[key]; ok {
+		close(w.ch)
+		delete(p.watches, key)
+	}
+}
+
+// notifyAll wakes up every waiter, e.g. after the whole state was replaced.
+func (p *pushWatchers) notifyAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, w := range p.watches {
+		close(w.ch)
+		delete(p.watches, key)
+	}
+}
@@ -0,0 +1,38 @@
+package raft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushWatchers(t *testing.T) {
+	var p pushWatchers
+
+	a1, stopA1 := p.watch("a")
+	a2, stopA2 := p.watch("a")
+	b, stopB := p.watch("b")
+	assert.Len(t, p.watches, 2)
+
+	// Waiters of a key share a watch, a push wakes all of them and the next waiter gets a new one.
+	p.notify("a")
+	assert.True(t, isClosed(a1))
+	assert.True(t, isClosed(a2))
+	assert.False(t, isClosed(b))
+	a3, stopA3 := p.watch("a")
+	assert.False(t, isClosed(a3))
+	stopA1()
+	stopA2()
+	assert.Contains(t, p.watches, "a")
+
+	// Stopping the last waiter releases the watch, stopping twice is a no-op.
+	stopA3()
+	stopA3()
+	assert.NotContains(t, p.watches, "a")
+
+	p.notify("missing")
+	p.notifyAll()
+	assert.True(t, isClosed(b))
+	assert.Empty(t, p.watches)
+	stopB()
+}
@@ -0,0 +1,543 @@
+package raft
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/shrtyk/kv-store/internal/core/ports/encryption"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
+	fsm_v1 "github.com/shrtyk/kv-store/proto/fsm/gen"
+	"google.golang.org/protobuf/proto"
+)
+
+// Chunked snapshot layout, all integers are big-endian:
+//
+//	header  | magic "KVSS" | version uint16 | compression uint8 | applied index uint64 | applied term uint64 | key count uint64 |
+//	body    | chunks: length uint32 | fsm_v1.SnapshotChunk | ... repeated, then length uint32 = 0 |
+//	trailer | crc32c of header and stored body uint32 |
+//
+// Body is compressed as a single stream with the compression from the header.
+// Version 2 has no applied term field. Version 1 has no compression field either and is never compressed.
+// Snapshots without the magic prefix are legacy fsm_v1.SnapshotState messages.
+//
+// Encrypted snapshots are magic "KVSE" followed by the whole snapshot sealed with encryption.Cipher.
+const (
+	snapshotMagic       = "KVSS"
+	sealedSnapshotMagic = "KVSE"
+	snapshotVersion     = 3
+	snapshotTrailerLen  = 4
+
+	// maxChunkEntries bounds memory needed to encode or decode a single chunk.
+	maxChunkEntries = 4096
+)
+
+var (
+	ErrSnapshotCorrupted   = errors.New("snapshot: corrupted data")
+	ErrSnapshotVersion     = errors.New("snapshot: unsupported format version")
+	ErrUnknownCompression  = errors.New("snapshot: unknown compression")
+	errSnapshotHeaderShort = fmt.Errorf("%w: too short", ErrSnapshotCorrupted)
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Compression of snapshot body.
+type Compression uint8
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionZstd
+	CompressionSnappy
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZstd:
+		return "zstd"
+	case CompressionSnappy:
+		return "snappy"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseCompression(s string) (Compression, error) {
+	switch s {
+	case "", "none":
+		return CompressionNone, nil
+	case "gzip":
+		return CompressionGzip, nil
+	case "zstd":
+		return CompressionZstd, nil
+	case "snappy":
+		return CompressionSnappy, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCompression, s)
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func (c Compression) writer(w io.Writer) (io.WriteCloser, error) {
+	switch c {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	case CompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCompression, c)
+	}
+}
+
+func (c Compression) reader(r io.Reader) (io.ReadCloser, error) {
+	switch c {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	case CompressionSnappy:
+		return io.NopCloser(snappy.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCompression, c)
+	}
+}
+
+type snapshotHeader struct {
+	version      uint16
+	compression  Compression
+	appliedIndex int64
+	appliedTerm  int64
+	keyCount     uint64
+	// membership is stored in the body and is nil if it was never replicated.
+	membership *fsm_v1.MembershipCommand
+	// lastVersion is stored in the body after entries and is zero if the store has no versions.
+	lastVersion uint64
+	// leases are stored in the body after membership.
+	leases []*fsm_v1.Lease
+	// clusterTime is stored in the body after leases and is zero if no command was stamped.
+	clusterTime int64
+	// lastLeaseID is stored in the body after cluster time and is zero if no lease or lock was given.
+	lastLeaseID int64
+	// namespaces are stored in the body after the last lease id. Their entries follow entries of the default
+	// namespace and are passed on by decodeSnapshot once namespaces are restored.
+	namespaces []store.Namespace
+	// cdcCursors are stored in the body after namespaces.
+	cdcCursors []*fsm_v1.CdcCursor
+	// cdcChanges are stored in the body after cdc cursors.
+	cdcChanges []*fsm_v1.CdcChange
+}
+
+func headerSize(version uint16) int {
+	switch version {
+	case 1:
+		return len(snapshotMagic) + 2 + 8 + 8
+	case 2:
+		return len(snapshotMagic) + 2 + 1 + 8 + 8
+	default:
+		return len(snapshotMagic) + 2 + 1 + 8 + 8 + 8
+	}
+}
+
+func isChunkedSnapshot(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(snapshotMagic))
+}
+
+func isSealedSnapshot(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sealedSnapshotMagic))
+}
+
+func sealSnapshot(data []byte, c encryption.Cipher) ([]byte, error) {
+	sealed, err := c.Encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(sealedSnapshotMagic), sealed...), nil
+}
+
+func openSnapshot(data []byte, c encryption.Cipher) ([]byte, error) {
+	if c == nil {
+		return nil, encryption.ErrDisabled
+	}
+	return c.Decrypt(data[len(sealedSnapshotMagic):])
+}
+
+func readSnapshotHeader(data []byte) (*snapshotHeader, error) {
+	if len(data) < len(snapshotMagic)+2 || !isChunkedSnapshot(data) {
+		return nil, errSnapshotHeaderShort
+	}
+	h := &snapshotHeader{version: binary.BigEndian.Uint16(data[4:])}
+	if h.version < 1 || h.version > snapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, h.version)
+	}
+	if len(data) < headerSize(h.version) {
+		return nil, errSnapshotHeaderShort
+	}
+
+	rest := data[6:]
+	if h.version > 1 {
+		h.compression = Compression(rest[0])
+		rest = rest[1:]
+	}
+	h.appliedIndex = int64(binary.BigEndian.Uint64(rest))
+	rest = rest[8:]
+	if h.version > 2 {
+		h.appliedTerm = int64(binary.BigEndian.Uint64(rest))
+		rest = rest[8:]
+	}
+	h.keyCount = binary.BigEndian.Uint64(rest)
+	return h, nil
+}
+
+// encodeSnapshot writes content of the store and stores of namespaces, if any, chunk by chunk
+// without copying the whole store first. Version, key count and namespaces of h are ignored.
+func encodeSnapshot(st store.Store, spaces store.Namespaces, h *snapshotHeader) ([]byte, error) {
+	hSize := headerSize(snapshotVersion)
+	buf := bytes.NewBuffer(make([]byte, hSize, 64*1024))
+	w, err := h.compression.writer(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		count   uint64
+		scratch []byte
+		lenBuf  [4]byte
+		chunk   = &fsm_v1.SnapshotChunk{}
+	)
+	writeChunk := func() error {
+		var err error
+		scratch, err = proto.MarshalOptions{}.MarshalAppend(scratch[:0], chunk)
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot chunk: %w", err)
+		}
+		binary.BigEndian.PutUint32(lenBuf[:], uint32(len(scratch)))
+		if _, err := w.Write(lenBuf[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(scratch); err != nil {
+			return err
+		}
+		count += uint64(len(chunk.Entries))
+		chunk.Entries = chunk.Entries[:0]
+		return nil
+	}
+	flush := func() error {
+		if len(chunk.Entries) == 0 {
+			return nil
+		}
+		return writeChunk()
+	}
+
+	if h.membership != nil {
+		chunk.Membership = h.membership
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+		chunk.Membership = nil
+	}
+	if len(h.leases) > 0 {
+		chunk.Leases = h.leases
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+		chunk.Leases = nil
+	}
+	if h.clusterTime > 0 {
+		chunk.ClusterTime = h.clusterTime
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+		chunk.ClusterTime = 0
+	}
+	if h.lastLeaseID > 0 {
+		chunk.LastLeaseId = h.lastLeaseID
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+		chunk.LastLeaseId = 0
+	}
+	var nsList []store.Namespace
+	if spaces != nil {
+		nsList = spaces.List()
+	}
+	if len(nsList) > 0 {
+		for _, ns := range nsList {
+			chunk.Namespaces = append(chunk.Namespaces, toPBNamespace(ns))
+		}
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+		chunk.Namespaces = nil
+	}
+	if len(h.cdcCursors) > 0 {
+		chunk.CdcCursors = h.cdcCursors
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+		chunk.CdcCursors = nil
+	}
+	for changes := range slices.Chunk(h.cdcChanges, maxChunkEntries) {
+		chunk.CdcChanges = changes
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+		chunk.CdcChanges = nil
+	}
+
+	writeEntries := func(st store.Store) error {
+		return st.RangeEntries(func(e store.Entry) error {
+			chunk.Entries = append(chunk.Entries, &fsm_v1.SnapshotEntry{
+				Key:       e.Key,
+				Value:     e.Value,
+				ExpiresAt: e.ExpiresAt,
+				Flags:     e.Flags,
+				Version:   e.Version,
+				Kind:      fsm_v1.ValueKind(e.Kind),
+				// Chunks are written after the store is unlocked.
+				Hash: maps.Clone(e.Hash),
+				List: slices.Clone(e.List),
+				Zset: toPBMembers(e.ZSet),
+			})
+			if len(chunk.Entries) >= maxChunkEntries {
+				return flush()
+			}
+			return nil
+		})
+	}
+	if err := writeEntries(st); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	for _, ns := range nsList {
+		nsStore, err := spaces.Store(ns.Name)
+		if err != nil {
+			return nil, err
+		}
+		chunk.Namespace = ns.Name
+		if err := writeEntries(nsStore); err != nil {
+			return nil, err
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	chunk.Namespace = ""
+	// Versions of deleted keys may be above versions of stored ones.
+	if v := st.LastVersion(); v > 0 {
+		chunk.LastVersion = v
+		if err := writeChunk(); err != nil {
+			return nil, err
+		}
+	}
+
+	binary.BigEndian.PutUint32(lenBuf[:], 0)
+	if _, err := w.Write(lenBuf[:]); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+
+	b := buf.Bytes()
+	copy(b, snapshotMagic)
+	binary.BigEndian.PutUint16(b[4:], snapshotVersion)
+	b[6] = byte(h.compression)
+	binary.BigEndian.PutUint64(b[7:], uint64(h.appliedIndex))
+	binary.BigEndian.PutUint64(b[15:], uint64(h.appliedTerm))
+	binary.BigEndian.PutUint64(b[23:], count)
+
+	return binary.BigEndian.AppendUint32(b, crc32.Checksum(b, crcTable)), nil
+}
+
+// verifySnapshot checks the snapshot checksum without decoding it.
+func verifySnapshot(data []byte) (*snapshotHeader, error) {
+	h, err := readSnapshotHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < headerSize(h.version)+snapshotTrailerLen {
+		return nil, errSnapshotHeaderShort
+	}
+
+	body := data[:len(data)-snapshotTrailerLen]
+	if crc32.Checksum(body, crcTable) != binary.BigEndian.Uint32(data[len(body):]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrSnapshotCorrupted)
+	}
+	return h, nil
+}
+
+// namespacesRestore replaces namespaces with ones of a snapshot. It must call fill, which decodes the rest
+// of the snapshot passing entries of the namespaces to put.
+type namespacesRestore func(spaces []store.Namespace, fill func(put func(namespace string, e store.Entry)) error) error
+
+// decodeSnapshot verifies the checksum and passes every entry of the default namespace to put chunk by chunk.
+// Once they are read, namespaces are restored by restore, so their entries are passed on without being collected.
+// Nil restore rejects snapshots with namespaces.
+func decodeSnapshot(data []byte, put func(e store.Entry), restore namespacesRestore) (*snapshotHeader, error) {
+	h, err := verifySnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	if restore == nil {
+		restore = func(spaces []store.Namespace, fill func(put func(string, store.Entry)) error) error {
+			if len(spaces) > 0 {
+				return ErrNamespacesDisabled
+			}
+			return fill(func(string, store.Entry) {})
+		}
+	}
+
+	body := data[headerSize(h.version) : len(data)-snapshotTrailerLen]
+	zr, err := h.compression.reader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSnapshotCorrupted, err)
+	}
+	defer func() { _ = zr.Close() }()
+	r := bufio.NewReader(zr)
+
+	var (
+		count   uint64
+		lenBuf  [4]byte
+		scratch []byte
+		chunk   = &fsm_v1.SnapshotChunk{}
+		// pending is the first chunk of namespace entries, read before namespaces are restored.
+		pending bool
+		end     bool
+	)
+	// next reads the next chunk into chunk and reports whether there was one.
+	next := func() (bool, error) {
+		if pending {
+			pending = false
+			return true, nil
+		}
+		if end {
+			return false, nil
+		}
+		if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+			return false, fmt.Errorf("%w: %w", ErrSnapshotCorrupted, err)
+		}
+		n := binary.BigEndian.Uint32(lenBuf[:])
+		if n == 0 {
+			end = true
+			return false, nil
+		}
+
+		if cap(scratch) < int(n) {
+			scratch = make([]byte, n)
+		}
+		scratch = scratch[:n]
+		if _, err := io.ReadFull(r, scratch); err != nil {
+			return false, fmt.Errorf("%w: truncated chunk: %w", ErrSnapshotCorrupted, err)
+		}
+
+		chunk.Reset()
+		if err := proto.Unmarshal(scratch, chunk); err != nil {
+			return false, fmt.Errorf("%w: %w", ErrSnapshotCorrupted, err)
+		}
+		return true, nil
+	}
+	// decode reads chunks until the end of the body. Without putNamespace it stops
+	// at the first chunk of namespace entries, which is left pending.
+	decode := func(putNamespace func(namespace string, e store.Entry)) error {
+		for {
+			ok, err := next()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			if chunk.Namespace != "" && putNamespace == nil {
+				pending = true
+				return nil
+			}
+
+			if chunk.Membership != nil {
+				h.membership = chunk.Membership
+				chunk = &fsm_v1.SnapshotChunk{}
+			}
+			if len(chunk.Leases) > 0 {
+				h.leases = append(h.leases, chunk.Leases...)
+				chunk = &fsm_v1.SnapshotChunk{}
+			}
+			if len(chunk.CdcCursors) > 0 {
+				h.cdcCursors = append(h.cdcCursors, chunk.CdcCursors...)
+				chunk = &fsm_v1.SnapshotChunk{}
+			}
+			if len(chunk.CdcChanges) > 0 {
+				h.cdcChanges = append(h.cdcChanges, chunk.CdcChanges...)
+				chunk = &fsm_v1.SnapshotChunk{}
+			}
+			for _, ns := range chunk.Namespaces {
+				h.namespaces = append(h.namespaces, fromPBNamespace(ns))
+			}
+			h.lastVersion = max(h.lastVersion, chunk.LastVersion)
+			h.clusterTime = max(h.clusterTime, chunk.ClusterTime)
+			h.lastLeaseID = max(h.lastLeaseID, chunk.LastLeaseId)
+			putEntry := put
+			if ns := chunk.Namespace; ns != "" {
+				putEntry = func(e store.Entry) { putNamespace(ns, e) }
+			}
+			for _, e := range chunk.Entries {
+				putEntry(store.Entry{
+					Key:       e.Key,
+					Value:     e.Value,
+					ExpiresAt: e.ExpiresAt,
+					Flags:     e.Flags,
+					Version:   e.Version,
+					Kind:      store.Kind(e.Kind),
+					Hash:      e.Hash,
+					List:      e.List,
+					ZSet:      fromPBMembers(e.Zset),
+				})
+			}
+			count += uint64(len(chunk.Entries))
+		}
+	}
+
+	// Namespaces are stored before entries, which start with the default namespace.
+	if err := decode(nil); err != nil {
+		return nil, err
+	}
+	if err := restore(h.namespaces, decode); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.ReadByte(); err != io.EOF {
+		return nil, fmt.Errorf("%w: trailing data", ErrSnapshotCorrupted)
+	}
+	if count != h.keyCount {
+		return nil, fmt.Errorf("%w: expected %d keys, got %d", ErrSnapshotCorrupted, h.keyCount, count)
+	}
+	return h, nil
+}
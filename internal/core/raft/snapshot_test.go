@@ -0,0 +1,263 @@
+package raft
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	"github.com/shrtyk/kv-store/internal/core/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestItems(t *testing.T, n int) map[string]string {
+	t.Helper()
+	items := make(map[string]string, n)
+	for i := range n {
+		items[fmt.Sprintf("key-%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	return items
+}
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	shCfg := tu.NewMockShardsCfg()
+	shCfg.ShardsCount = 4
+
+	src := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), shCfg, l)
+	// More keys than fit in a single chunk of one shard.
+	items := newTestItems(t, 4*maxChunkEntries+10)
+	src.RestoreFromSnapshot(items)
+
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionZstd, CompressionSnappy} {
+		t.Run(c.String(), func(t *testing.T) {
+			data, err := encodeSnapshot(src, nil, &snapshotHeader{appliedIndex: 42, appliedTerm: 3, compression: c})
+			require.NoError(t, err)
+
+			dst := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), shCfg, l)
+			var h *snapshotHeader
+			err = dst.RestoreFrom(func(put func(e pstore.Entry)) error {
+				h, err = decodeSnapshot(data, put, nil)
+				return err
+			})
+			require.NoError(t, err)
+			assert.Equal(t, c, h.compression)
+			assert.Equal(t, int64(42), h.appliedIndex)
+			assert.Equal(t, int64(3), h.appliedTerm)
+			assert.Equal(t, uint64(len(items)), h.keyCount)
+			assert.Equal(t, items, dst.Items())
+		})
+	}
+}
+
+func TestSnapshot_KeepsMetadata(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	src := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	require.NoError(t, src.PutEntry(pstore.Entry{Key: "a", Value: "1", ExpiresAt: 5_000, Flags: 3}))
+	require.NoError(t, src.Put("b", "2"))
+	require.NoError(t, src.Delete("b"))
+
+	data, err := encodeSnapshot(src, nil, &snapshotHeader{})
+	require.NoError(t, err)
+
+	dst := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	var h *snapshotHeader
+	err = dst.RestoreFrom(func(put func(e pstore.Entry)) error {
+		h, err = decodeSnapshot(data, put, nil)
+		return err
+	})
+	require.NoError(t, err)
+	// Version of the deleted key is kept, so it isn't assigned again.
+	assert.Equal(t, uint64(2), h.lastVersion)
+
+	e, err := dst.Lookup("a", 1_000)
+	require.NoError(t, err)
+	assert.Equal(t, pstore.Entry{Key: "a", Value: "1", ExpiresAt: 5_000, Flags: 3, Version: 1}, e)
+}
+
+func TestSnapshot_KeepsHashes(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	src := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := time.Now().UnixMilli()
+	_, err := src.HSet("h", map[string]string{"a": "1", "b": "2"}, now)
+	require.NoError(t, err)
+	require.NoError(t, src.Expire("h", now+60_000, now))
+	require.NoError(t, src.Put("s", "v"))
+
+	data, err := encodeSnapshot(src, nil, &snapshotHeader{compression: CompressionZstd})
+	require.NoError(t, err)
+
+	dst := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	err = dst.RestoreFrom(func(put func(e pstore.Entry)) error {
+		_, err := decodeSnapshot(data, put, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	fields, err := dst.HGetAll("h")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, fields)
+	e, err := dst.Lookup("h", now)
+	require.NoError(t, err)
+	assert.Equal(t, pstore.KindHash, e.Kind)
+	assert.Equal(t, now+60_000, e.ExpiresAt)
+	val, err := dst.Get("s")
+	require.NoError(t, err)
+	assert.Equal(t, "v", val)
+}
+
+func TestSnapshot_KeepsLists(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	src := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := time.Now().UnixMilli()
+	_, err := src.Push("l", []string{"a", "b", "c"}, false, now)
+	require.NoError(t, err)
+	_, err = src.Pop("l", 1, true, now)
+	require.NoError(t, err)
+
+	data, err := encodeSnapshot(src, nil, &snapshotHeader{compression: CompressionSnappy})
+	require.NoError(t, err)
+
+	dst := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	err = dst.RestoreFrom(func(put func(e pstore.Entry)) error {
+		_, err := decodeSnapshot(data, put, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	vals, err := dst.LRange("l", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, vals)
+}
+
+func TestSnapshot_KeepsSortedSets(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	src := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := time.Now().UnixMilli()
+	members := []pstore.ScoredMember{{Member: "low", Score: math.Inf(-1)}, {Member: "a", Score: 1}, {Member: "b", Score: 1}}
+	_, err := src.ZAdd("z", members, now)
+	require.NoError(t, err)
+
+	data, err := encodeSnapshot(src, nil, &snapshotHeader{compression: CompressionGzip})
+	require.NoError(t, err)
+
+	dst := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	err = dst.RestoreFrom(func(put func(e pstore.Entry)) error {
+		_, err := decodeSnapshot(data, put, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	got, err := dst.ZRange("z", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, members, got)
+	score, err := dst.ZScore("z", "b")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, score)
+}
+
+func TestSnapshot_CompressionShrinks(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	st := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	st.RestoreFromSnapshot(newTestItems(t, 1000))
+
+	plain, err := encodeSnapshot(st, nil, &snapshotHeader{appliedIndex: 1, appliedTerm: 1, compression: CompressionNone})
+	require.NoError(t, err)
+	for _, c := range []Compression{CompressionGzip, CompressionZstd, CompressionSnappy} {
+		compressed, err := encodeSnapshot(st, nil, &snapshotHeader{appliedIndex: 1, appliedTerm: 1, compression: c})
+		require.NoError(t, err)
+		assert.Less(t, len(compressed), len(plain), c.String())
+	}
+}
+
+func TestSnapshot_ReadsOlderVersions(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	st := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	items := newTestItems(t, 10)
+	st.RestoreFromSnapshot(items)
+
+	v3, err := encodeSnapshot(st, nil, &snapshotHeader{appliedIndex: 5, appliedTerm: 2, compression: CompressionNone})
+	require.NoError(t, err)
+	body := v3[headerSize(3) : len(v3)-snapshotTrailerLen]
+
+	// Version 2 has no applied term, version 1 has no compression byte either.
+	older := map[uint16][]byte{
+		1: append(append(append([]byte(nil), v3[:6]...), v3[7:15]...), v3[23:31]...),
+		2: append(append([]byte(nil), v3[:15]...), v3[23:31]...),
+	}
+	for version, header := range older {
+		binary.BigEndian.PutUint16(header[4:], version)
+		data := append(header, body...)
+		data = binary.BigEndian.AppendUint32(data, crc32.Checksum(data, crcTable))
+
+		got := make(map[string]string)
+		h, err := decodeSnapshot(data, func(e pstore.Entry) { got[e.Key] = e.Value }, nil)
+		require.NoError(t, err)
+		assert.Equal(t, version, h.version)
+		assert.Equal(t, int64(5), h.appliedIndex)
+		assert.Equal(t, int64(0), h.appliedTerm)
+		assert.Equal(t, items, got)
+	}
+}
+
+func TestParseCompression(t *testing.T) {
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionZstd, CompressionSnappy} {
+		parsed, err := ParseCompression(c.String())
+		require.NoError(t, err)
+		assert.Equal(t, c, parsed)
+	}
+
+	_, err := ParseCompression("lz4")
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+}
+
+func TestSnapshot_Empty(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	st := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+
+	data, err := encodeSnapshot(st, nil, &snapshotHeader{})
+	require.NoError(t, err)
+
+	h, err := decodeSnapshot(data, func(pstore.Entry) { t.Fatal("unexpected entry") }, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), h.keyCount)
+}
+
+func TestSnapshot_Corrupted(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	shCfg := tu.NewMockShardsCfg()
+	shCfg.ShardsCount = 4
+	st := store.NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), shCfg, l)
+	st.RestoreFromSnapshot(newTestItems(t, 100))
+
+	data, err := encodeSnapshot(st, nil, &snapshotHeader{appliedIndex: 7, appliedTerm: 1, compression: CompressionZstd})
+	require.NoError(t, err)
+
+	flipped := append([]byte(nil), data...)
+	flipped[headerSize(snapshotVersion)+10] ^= 0xff
+	_, err = decodeSnapshot(flipped, func(pstore.Entry) {}, nil)
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+
+	_, err = decodeSnapshot(data[:len(data)/2], func(pstore.Entry) {}, nil)
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+
+	future := append([]byte(nil), data...)
+	binary.BigEndian.PutUint16(future[4:], snapshotVersion+1)
+	_, err = decodeSnapshot(future, func(pstore.Entry) {}, nil)
+	assert.ErrorIs(t, err, ErrSnapshotVersion)
+
+	// Store content is kept if the snapshot can't be decoded.
+	before := st.Items()
+	err = st.RestoreFrom(func(put func(e pstore.Entry)) error {
+		_, err := decodeSnapshot(flipped, put, nil)
+		return err
+	})
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+	assert.Equal(t, before, st.Items())
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+)
+
+// Op is a class of operations limited by its own token bucket.
+type Op int
+
+const (
+	OpRead Op = iota
+	OpWrite
+)
+
+type bucketKey struct {
+	client string
+	op     Op
+}
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// Limiter is a per client token bucket rate limiter.
+// Read and write operations of the same client are limited independently.
+type Limiter struct {
+	enabled     bool
+	limits      [2]cfg.LimitCfg
+	idleTimeout time.Duration
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+	now     func() time.Time
+}
+
+func NewLimiter(c *cfg.RateLimitCfg) *Limiter {
+	return &Limiter{
+		enabled:     c.Enabled,
+		limits:      [2]cfg.LimitCfg{OpRead: c.Read, OpWrite: c.Write},
+		idleTimeout: c.IdleTimeout,
+		buckets:     make(map[bucketKey]*bucket),
+		now:         time.Now,
+	}
+}
+
+// Enabled reports whether requests are limited.
+// Nil limiter is treated as disabled.
+func (l *Limiter) Enabled() bool {
+	return l != nil && l.enabled
+}
+
+// Allow takes a token from the client bucket of the given operation.
+// If the bucket is empty it returns false and the time after which a token will be available.
+func (l *Limiter) Allow(client string, op Op) (bool, time.Duration) {
+	if !l.Enabled() {
+		return true, 0
+	}
+	limit := l.limits[op]
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	burst := math.Max(float64(limit.Burst), 1)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	k := bucketKey{client: client, op: op}
+	b, ok := l.buckets[k]
+	if !ok {
+		b = &bucket{tokens: burst, lastSeen: now}
+		l.buckets[k] = b
+	}
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.lastSeen).Seconds()*limit.Rate)
+	b.lastSeen = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := (1 - b.tokens) / limit.Rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// Start periodically drops buckets of idle clients until ctx is done.
+func (l *Limiter) Start(ctx context.Context) {
+	if !l.Enabled() || l.idleTimeout <= 0 {
+		return
+	}
+
+	t := time.NewTicker(l.idleTimeout)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			l.evictIdle()
+		}
+	}
+}
+
+func (l *Limiter) evictIdle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for k, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= l.idleTimeout {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// RetryAfterSeconds rounds the duration up to whole seconds, at least one.
+func RetryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(now *time.Time) *Limiter {
+	l := NewLimiter(&cfg.RateLimitCfg{
+		Enabled:     true,
+		Read:        cfg.LimitCfg{Rate: 0},
+		Write:       cfg.LimitCfg{Rate: 2, Burst: 2},
+		IdleTimeout: time.Minute,
+	})
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+
+	ok, _ := l.Allow("alice", OpWrite)
+	assert.True(t, ok)
+	ok, _ = l.Allow("alice", OpWrite)
+	assert.True(t, ok)
+
+	ok, retry := l.Allow("alice", OpWrite)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retry)
+
+	// Other clients and unlimited operations are not affected.
+	ok, _ = l.Allow("bob", OpWrite)
+	assert.True(t, ok)
+	for range 10 {
+		ok, _ = l.Allow("alice", OpRead)
+		assert.True(t, ok)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("alice", OpWrite)
+	assert.True(t, ok)
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	var nilLimiter *Limiter
+	ok, _ := nilLimiter.Allow("alice", OpWrite)
+	assert.True(t, ok)
+
+	l := NewLimiter(&cfg.RateLimitCfg{Write: cfg.LimitCfg{Rate: 1, Burst: 1}})
+	for range 5 {
+		ok, _ = l.Allow("alice", OpWrite)
+		assert.True(t, ok)
+	}
+}
+
+func TestLimiter_EvictIdle(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+
+	l.Allow("alice", OpWrite)
+	now = now.Add(30 * time.Second)
+	l.Allow("bob", OpWrite)
+
+	now = now.Add(30 * time.Second)
+	l.evictIdle()
+	assert.Len(t, l.buckets, 1)
+	assert.Contains(t, l.buckets, bucketKey{client: "bob", op: OpWrite})
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, 1, RetryAfterSeconds(0))
+	assert.Equal(t, 1, RetryAfterSeconds(200*time.Millisecond))
+	assert.Equal(t, 3, RetryAfterSeconds(2100*time.Millisecond))
+}
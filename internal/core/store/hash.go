@@ -0,0 +1,136 @@
+package store
+
+import (
+	"maps"
+	"strconv"
+	"time"
+
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+)
+
+func (s *store) HSet(key string, fields map[string]string, now int64) (int, error) {
+	if err := s.checkField(key, fields); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	err := s.storage.update(key, pstore.KindHash, now, func(obj object, prevSize int, existed bool) (object, error) {
+		h, _ := obj.(*hashValue)
+		if h == nil {
+			h = newHashValue(nil)
+		}
+		if err := s.reserve(key, prevSize, existed, h.sizeAfterSet(fields)); err != nil {
+			return nil, err
+		}
+		added = h.set(fields)
+		return h, nil
+	})
+	return added, err
+}
+
+func (s *store) HGet(key, field string) (string, error) {
+	var (
+		val string
+		ok  bool
+	)
+	err := s.storage.view(key, pstore.KindHash, time.Now().UnixMilli(), func(obj object) {
+		val, ok = obj.(*hashValue).fields[field]
+	})
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", pstore.ErrNoSuchKey
+	}
+	return val, nil
+}
+
+func (s *store) HGetAll(key string) (map[string]string, error) {
+	var fields map[string]string
+	err := s.storage.view(key, pstore.KindHash, time.Now().UnixMilli(), func(obj object) {
+		fields = maps.Clone(obj.(*hashValue).fields)
+	})
+	return fields, err
+}
+
+func (s *store) HDel(key string, fields []string, now int64) (int, error) {
+	deleted := 0
+	err := s.storage.update(key, pstore.KindHash, now, func(obj object, prevSize int, existed bool) (object, error) {
+		h, _ := obj.(*hashValue)
+		if h != nil {
+			deleted = h.del(fields)
+		}
+		if h == nil || len(h.fields) == 0 {
+			// Expired value, if any, is deleted as well.
+			if existed {
+				s.release(key, prevSize)
+			}
+			return nil, nil
+		}
+		_ = s.reserve(key, prevSize, existed, h.size())
+		return h, nil
+	})
+	return deleted, err
+}
+
+func (s *store) HIncrBy(key, field string, delta, now int64) (int64, error) {
+	if err := s.checkField(key, map[string]string{field: ""}); err != nil {
+		return 0, err
+	}
+
+	var next int64
+	err := s.storage.update(key, pstore.KindHash, now, func(obj object, prevSize int, existed bool) (object, error) {
+		h, _ := obj.(*hashValue)
+		if h == nil {
+			h = newHashValue(nil)
+		}
+		var cur int64
+		if v, ok := h.fields[field]; ok {
+			var err error
+			if cur, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return nil, pstore.ErrNotInteger
+			}
+		}
+		next = cur + delta
+		if (delta > 0 && next < cur) || (delta < 0 && next > cur) {
+			return nil, pstore.ErrNotInteger
+		}
+
+		fields := map[string]string{field: strconv.FormatInt(next, 10)}
+		if err := s.reserve(key, prevSize, existed, h.sizeAfterSet(fields)); err != nil {
+			return nil, err
+		}
+		h.set(fields)
+		return h, nil
+	})
+	return next, err
+}
+
+// checkField checks sizes of the key and fields. Field names are limited like keys.
+func (s *store) checkField(key string, fields map[string]string) error {
+	if len(key) > s.cfg.MaxKeySize {
+		return pstore.ErrKeyTooLarge
+	}
+	for f, v := range fields {
+		if len(f) > s.cfg.MaxKeySize {
+			return pstore.ErrKeyTooLarge
+		}
+		if len(v) > s.cfg.MaxValSize {
+			return pstore.ErrValueTooLarge
+		}
+	}
+	return nil
+}
+
+func (s *store) reserve(key string, prevSize int, existed bool, size int) error {
+	if s.quotas == nil {
+		return nil
+	}
+	return s.quotas.reserve(key, prevSize, existed, size)
+}
+
+func (s *store) release(key string, prevSize int) {
+	if s.quotas != nil {
+		s.quotas.release(key, prevSize)
+	}
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_Hash(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	added, err := s.HSet("h", map[string]string{"a": "1", "b": "2"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added)
+	added, err = s.HSet("h", map[string]string{"b": "3", "c": "4"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	val, err := s.HGet("h", "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", val)
+	_, err = s.HGet("h", "missing")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+	_, err = s.HGet("missing", "a")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+
+	fields, err := s.HGetAll("h")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "3", "c": "4"}, fields)
+
+	n, err := s.HIncrBy("h", "a", 41, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), n)
+	n, err = s.HIncrBy("h", "new", -2, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-2), n)
+	_, err = s.HSet("h", map[string]string{"max": "9223372036854775807"}, now)
+	assert.NoError(t, err)
+	_, err = s.HIncrBy("h", "max", 1, now)
+	assert.ErrorIs(t, err, pstore.ErrNotInteger)
+	_, err = s.HSet("h", map[string]string{"text": "abc"}, now)
+	assert.NoError(t, err)
+	_, err = s.HIncrBy("h", "text", 1, now)
+	assert.ErrorIs(t, err, pstore.ErrNotInteger)
+	_, err = s.HIncrBy("h", "new", math.MinInt64, now)
+	assert.ErrorIs(t, err, pstore.ErrNotInteger)
+
+	deleted, err := s.HDel("h", []string{"a", "b", "c", "new", "max", "missing"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, deleted)
+
+	// Hash without fields is deleted.
+	deleted, err = s.HDel("h", []string{"text"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	_, err = s.Lookup("h", now)
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+	deleted, err = s.HDel("h", []string{"text"}, now)
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestStore_HashWrongType(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	assert.NoError(t, s.Put("str", "v"))
+	_, err := s.HSet("str", map[string]string{"a": "1"}, now)
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	_, err = s.HGet("str", "a")
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	_, err = s.HGetAll("str")
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	_, err = s.HDel("str", []string{"a"}, now)
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	_, err = s.HIncrBy("str", "a", 1, now)
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+
+	_, err = s.HSet("hash", map[string]string{"a": "1"}, now)
+	assert.NoError(t, err)
+	_, err = s.Get("hash")
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	e, err := s.Lookup("hash", now)
+	assert.NoError(t, err)
+	assert.Equal(t, pstore.KindHash, e.Kind)
+
+	// Plain writes replace a value of any kind.
+	assert.NoError(t, s.Put("hash", "v"))
+	val, err := s.Get("hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "v", val)
+
+	assert.NoError(t, s.Delete("str"))
+	_, err = s.HSet("str", map[string]string{"a": "1"}, now)
+	assert.NoError(t, err)
+}
+
+func TestStore_HashExpiry(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	_, err := s.HSet("h", map[string]string{"a": "1"}, now)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Expire("h", now+10, now))
+
+	// Writing into an expired hash starts a new one without expiry.
+	added, err := s.HSet("h", map[string]string{"b": "2"}, now+10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+	e, err := s.Lookup("h", now+20)
+	assert.NoError(t, err)
+	assert.Zero(t, e.ExpiresAt)
+	fields, err := s.HGetAll("h")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"b": "2"}, fields)
+
+	assert.NoError(t, s.Expire("h", now+30, now+20))
+	assert.Equal(t, []string{"h"}, s.ExpiredKeys(now+30, 10))
+	assert.Equal(t, []string{"h"}, s.DeleteExpired([]string{"h"}, now+30))
+	_, err = s.HGetAll("h")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+}
+
+func TestStore_HashQuotas(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	stCfg := tu.NewMockStoreCfg()
+	stCfg.Quotas = []cfg.QuotaCfg{{Prefix: "", MaxBytes: 17}}
+	s := NewStore(&sync.WaitGroup{}, stCfg, tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	// Key and every field name and value are accounted.
+	_, err := s.HSet("h", map[string]string{"ab": "cd"}, now)
+	assert.NoError(t, err)
+	_, err = s.HSet("h", map[string]string{"ef": "0123456789a"}, now)
+	assert.ErrorIs(t, err, pstore.ErrQuotaExceeded)
+	_, err = s.HSet("h", map[string]string{"ef": "0123456789"}, now)
+	assert.NoError(t, err)
+	_, err = s.HIncrBy("h", "g", 1, now)
+	assert.ErrorIs(t, err, pstore.ErrQuotaExceeded)
+	assert.ErrorIs(t, s.Put("x", ""), pstore.ErrQuotaExceeded)
+
+	// Deleting fields releases their bytes, deleting the last one releases the key.
+	_, err = s.HDel("h", []string{"ef"}, now)
+	assert.NoError(t, err)
+	_, err = s.HIncrBy("h", "g", 1, now)
+	assert.NoError(t, err)
+	_, err = s.HDel("h", []string{"ab", "g"}, now)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Put("y", "0123456789abcdef"))
+}
+
+func TestStore_HashRestore(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	_, err := s.HSet("h", map[string]string{"a": "1", "b": "2"}, now)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Put("s", "v"))
+
+	var entries []pstore.Entry
+	assert.NoError(t, s.RangeEntries(func(e pstore.Entry) error {
+		if e.Kind == pstore.KindHash {
+			assert.Equal(t, map[string]string{"a": "1", "b": "2"}, e.Hash)
+		}
+		entries = append(entries, e)
+		return nil
+	}))
+	assert.Len(t, entries, 2)
+
+	restored := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	assert.NoError(t, restored.RestoreFrom(func(put func(e pstore.Entry)) error {
+		for _, e := range entries {
+			put(e)
+		}
+		return nil
+	}))
+	fields, err := restored.HGetAll("h")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, fields)
+	val, err := restored.Get("s")
+	assert.NoError(t, err)
+	assert.Equal(t, "v", val)
+
+	assert.Error(t, restored.RestoreFrom(func(put func(e pstore.Entry)) error {
+		put(pstore.Entry{Key: "bad", Kind: pstore.Kind(100)})
+		return nil
+	}))
+}
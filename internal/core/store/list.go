@@ -0,0 +1,89 @@
+package store
+
+import (
+	"time"
+
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+)
+
+func (s *store) Push(key string, values []string, left bool, now int64) (int, error) {
+	if len(key) > s.cfg.MaxKeySize {
+		return 0, pstore.ErrKeyTooLarge
+	}
+	for _, v := range values {
+		if len(v) > s.cfg.MaxValSize {
+			return 0, pstore.ErrValueTooLarge
+		}
+	}
+
+	length := 0
+	err := s.storage.update(key, pstore.KindList, now, func(obj object, prevSize int, existed bool) (object, error) {
+		l, _ := obj.(*listValue)
+		if l == nil {
+			if len(values) == 0 {
+				return nil, nil
+			}
+			l = newListValue(nil)
+		}
+		if err := s.reserve(key, prevSize, existed, l.size()+listSize(values)); err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			l.push(v, left)
+		}
+		length = l.n
+		return l, nil
+	})
+	return length, err
+}
+
+func (s *store) Pop(key string, count int, left bool, now int64) ([]string, error) {
+	var popped []string
+	err := s.storage.update(key, pstore.KindList, now, func(obj object, prevSize int, existed bool) (object, error) {
+		l, _ := obj.(*listValue)
+		if l != nil {
+			popped = make([]string, 0, min(count, l.n))
+			for range min(count, l.n) {
+				popped = append(popped, l.pop(left))
+			}
+		}
+		if l == nil || l.n == 0 {
+			// Expired value, if any, is deleted as well.
+			if existed {
+				s.release(key, prevSize)
+			}
+			return nil, nil
+		}
+		_ = s.reserve(key, prevSize, existed, l.size())
+		return l, nil
+	})
+	return popped, err
+}
+
+func (s *store) LRange(key string, start, stop int) ([]string, error) {
+	var vals []string
+	err := s.storage.view(key, pstore.KindList, time.Now().UnixMilli(), func(obj object) {
+		l := obj.(*listValue)
+		if start < 0 {
+			start = max(start+l.n, 0)
+		}
+		if stop < 0 {
+			stop += l.n
+		}
+		stop = min(stop, l.n-1)
+		if start > stop {
+			vals = []string{}
+			return
+		}
+		vals = l.slice(start, stop+1)
+	})
+	return vals, err
+}
+
+func (s *store) LLen(key string) (int, error) {
+	n := 0
+	err := s.storage.view(key, pstore.KindList, time.Now().UnixMilli(), func(obj object) {
+		n = obj.(*listValue).n
+	})
+	return n, err
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_List(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	n, err := s.Push("l", []string{"b", "c"}, false, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	// Values are pushed one by one, so the last one ends up at the head.
+	n, err = s.Push("l", []string{"a", "z"}, true, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+
+	vals, err := s.LRange("l", 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"z", "a", "b", "c"}, vals)
+	vals, err = s.LRange("l", -2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, vals)
+	vals, err = s.LRange("l", 3, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, vals)
+	_, err = s.LRange("missing", 0, -1)
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+
+	vals, err = s.Pop("l", 1, true, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"z"}, vals)
+	vals, err = s.Pop("l", 2, false, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "b"}, vals)
+	n, err = s.LLen("l")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// List without values is deleted.
+	vals, err = s.Pop("l", 5, true, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, vals)
+	_, err = s.LLen("l")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+	vals, err = s.Pop("l", 1, true, now)
+	assert.NoError(t, err)
+	assert.Empty(t, vals)
+
+	assert.NoError(t, s.Put("s", "v"))
+	_, err = s.Push("s", []string{"a"}, true, now)
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	_, err = s.Pop("s", 1, true, now)
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	_, err = s.HSet("l2", map[string]string{"f": "v"}, now)
+	assert.NoError(t, err)
+	_, err = s.LLen("l2")
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+}
+
+func TestStore_ListQueue(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	// Ring buffer wraps around and grows while values are pushed to both ends.
+	next := 0
+	for i := range 200 {
+		_, err := s.Push("q", []string{strconv.Itoa(i)}, i%3 == 0, now)
+		assert.NoError(t, err)
+		if i%2 == 0 {
+			vals, err := s.Pop("q", 1, false, now)
+			assert.NoError(t, err)
+			assert.Len(t, vals, 1)
+			next++
+		}
+	}
+	n, err := s.LLen("q")
+	assert.NoError(t, err)
+	assert.Equal(t, 200-next, n)
+
+	all, err := s.LRange("q", 0, -1)
+	assert.NoError(t, err)
+	popped, err := s.Pop("q", 1000, true, now)
+	assert.NoError(t, err)
+	assert.Equal(t, all, popped)
+}
+
+func TestStore_ListQuotasAndRestore(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	stCfg := tu.NewMockStoreCfg()
+	stCfg.Quotas = []cfg.QuotaCfg{{Prefix: "", MaxBytes: 6}}
+	s := NewStore(&sync.WaitGroup{}, stCfg, tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	_, err := s.Push("l", []string{"ab", "cd"}, false, now)
+	assert.NoError(t, err)
+	_, err = s.Push("l", []string{"ef"}, false, now)
+	assert.ErrorIs(t, err, pstore.ErrQuotaExceeded)
+	_, err = s.Push("l", []string{"e"}, false, now)
+	assert.NoError(t, err)
+
+	var entries []pstore.Entry
+	assert.NoError(t, s.RangeEntries(func(e pstore.Entry) error {
+		entries = append(entries, e)
+		return nil
+	}))
+	assert.Equal(t, []string{"ab", "cd", "e"}, entries[0].List)
+
+	_, err = s.Pop("l", 3, true, now)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Put("x", "01234"))
+
+	assert.NoError(t, s.RestoreFrom(func(put func(e pstore.Entry)) error {
+		for _, e := range entries {
+			put(e)
+		}
+		return nil
+	}))
+	vals, err := s.LRange("l", 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ab", "cd", "e"}, vals)
+	_, err = s.Push("l", []string{"f"}, true, now)
+	assert.ErrorIs(t, err, pstore.ErrQuotaExceeded)
+}
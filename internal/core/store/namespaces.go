@@ -0,0 +1,193 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+)
+
+type namespace struct {
+	spec  pstore.Namespace
+	store *store
+	// stop stops the map rebuilder of the store, nil until the rebuilder is started.
+	stop context.CancelFunc
+}
+
+// namespaces keeps a separate store per namespace next to the default one.
+type namespaces struct {
+	mu     sync.RWMutex
+	def    pstore.Store
+	spaces map[string]*namespace
+
+	cfg      *cfg.StoreCfg
+	shardCfg *cfg.ShardsCfg
+	logger   *slog.Logger
+
+	// ctx and wg are set once map rebuilders are started, so stores of new namespaces get their own.
+	ctx context.Context
+	wg  *sync.WaitGroup
+}
+
+func NewNamespaces(def pstore.Store, cfg *cfg.StoreCfg, shardCfg *cfg.ShardsCfg, l *slog.Logger) *namespaces {
+	return &namespaces{
+		def:      def,
+		spaces:   make(map[string]*namespace),
+		cfg:      cfg,
+		shardCfg: shardCfg,
+		logger:   l,
+	}
+}
+
+func (n *namespaces) Store(name string) (pstore.Store, error) {
+	if name == "" {
+		return n.def, nil
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	ns, ok := n.spaces[name]
+	if !ok {
+		return nil, pstore.ErrNoSuchNamespace
+	}
+	return ns.store, nil
+}
+
+func (n *namespaces) Create(spec pstore.Namespace) error {
+	if err := pstore.CheckNamespace(spec, n.cfg.MaxKeySize, n.cfg.MaxValSize); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.spaces[spec.Name]; ok {
+		return pstore.ErrNamespaceExists
+	}
+	n.spaces[spec.Name] = n.newNamespace(spec)
+	return nil
+}
+
+func (n *namespaces) Delete(name string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	ns, ok := n.spaces[name]
+	if !ok {
+		return pstore.ErrNoSuchNamespace
+	}
+	if ns.stop != nil {
+		ns.stop()
+	}
+	delete(n.spaces, name)
+	return nil
+}
+
+func (n *namespaces) List() []pstore.Namespace {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	list := make([]pstore.Namespace, 0, len(n.spaces))
+	for _, ns := range n.spaces {
+		list = append(list, ns.spec)
+	}
+	slices.SortFunc(list, func(a, b pstore.Namespace) int { return strings.Compare(a.Name, b.Name) })
+	return list
+}
+
+func (n *namespaces) Restore(specs []pstore.Namespace, fill func(put func(namespace string, e pstore.Entry)) error) error {
+	staged := make([]*namespace, 0, len(specs))
+	spaces := make(map[string]*namespace, len(specs))
+	for _, spec := range specs {
+		ns := &namespace{spec: spec, store: n.newStore(spec)}
+		staged = append(staged, ns)
+		spaces[spec.Name] = ns
+	}
+	puts := make(map[string]func(e pstore.Entry), len(specs))
+	err := restoreStaged(staged, puts, func() error {
+		var unknown error
+		err := fill(func(name string, e pstore.Entry) {
+			put, ok := puts[name]
+			if !ok {
+				unknown = cmp.Or(unknown, fmt.Errorf("%w: %q", pstore.ErrNoSuchNamespace, name))
+				return
+			}
+			put(e)
+		})
+		return cmp.Or(err, unknown)
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, ns := range n.spaces {
+		if ns.stop != nil {
+			ns.stop()
+		}
+	}
+	for _, ns := range spaces {
+		n.startRebuilder(ns)
+	}
+	n.spaces = spaces
+	return nil
+}
+
+// restoreStaged fills new stores of namespaces in a single pass of fill. Every store is restored within
+// restore of the previous one, so fill runs once puts of all of them are collected.
+func restoreStaged(staged []*namespace, puts map[string]func(e pstore.Entry), fill func() error) error {
+	if len(staged) == 0 {
+		return fill()
+	}
+	ns := staged[0]
+	return ns.store.RestoreFrom(func(put func(e pstore.Entry)) error {
+		puts[ns.spec.Name] = put
+		return restoreStaged(staged[1:], puts, fill)
+	})
+}
+
+func (n *namespaces) StartMapRebuilder(ctx context.Context, wg *sync.WaitGroup) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.ctx, n.wg = ctx, wg
+	for _, ns := range n.spaces {
+		n.startRebuilder(ns)
+	}
+}
+
+// newNamespace must be called with the lock held.
+func (n *namespaces) newNamespace(spec pstore.Namespace) *namespace {
+	ns := &namespace{spec: spec, store: n.newStore(spec)}
+	n.startRebuilder(ns)
+	return ns
+}
+
+// startRebuilder must be called with the lock held.
+func (n *namespaces) startRebuilder(ns *namespace) {
+	if n.ctx == nil || ns.stop != nil {
+		return
+	}
+	var ctx context.Context
+	ctx, ns.stop = context.WithCancel(n.ctx)
+	ns.store.StartMapRebuilder(ctx, n.wg)
+}
+
+// newStore creates an empty store with the namespace limits applied on top of the store config.
+func (n *namespaces) newStore(spec pstore.Namespace) *store {
+	c := *n.cfg
+	if spec.MaxKeySize > 0 {
+		c.MaxKeySize = spec.MaxKeySize
+	}
+	if spec.MaxValSize > 0 {
+		c.MaxValSize = spec.MaxValSize
+	}
+	c.Quotas = nil
+	if spec.MaxKeys > 0 {
+		c.Quotas = []cfg.QuotaCfg{{MaxKeys: spec.MaxKeys}}
+	}
+	return NewStore(n.wg, &c, n.shardCfg, n.logger.With(slog.String("namespace", spec.Name)))
+}
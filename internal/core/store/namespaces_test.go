@@ -0,0 +1,108 @@
+package store
+
+import (
+	"sync"
+	"testing"
+
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNamespaces(t *testing.T) (*namespaces, pstore.Store) {
+	t.Helper()
+	l, _ := tu.NewMockLogger()
+	def := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	return NewNamespaces(def, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l), def
+}
+
+func TestNamespaces(t *testing.T) {
+	t.Run("isolates keys", func(t *testing.T) {
+		n, def := newTestNamespaces(t)
+		require.NoError(t, n.Create(pstore.Namespace{Name: "team-a"}))
+		require.NoError(t, n.Create(pstore.Namespace{Name: "team-b"}))
+
+		st, err := n.Store("")
+		require.NoError(t, err)
+		assert.Same(t, def, st)
+
+		a, err := n.Store("team-a")
+		require.NoError(t, err)
+		b, err := n.Store("team-b")
+		require.NoError(t, err)
+		require.NoError(t, a.Put("k", "a"))
+		require.NoError(t, b.Put("k", "b"))
+
+		val, err := a.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "a", val)
+		val, err = b.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "b", val)
+		_, err = def.Get("k")
+		assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+	})
+
+	t.Run("applies limits", func(t *testing.T) {
+		n, _ := newTestNamespaces(t)
+		require.NoError(t, n.Create(pstore.Namespace{Name: "small", MaxKeySize: 3, MaxValSize: 3, MaxKeys: 1}))
+		st, err := n.Store("small")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, st.Put("long", "v"), pstore.ErrKeyTooLarge)
+		assert.ErrorIs(t, st.Put("k", "long"), pstore.ErrValueTooLarge)
+		require.NoError(t, st.Put("k1", "v"))
+		assert.ErrorIs(t, st.Put("k2", "v"), pstore.ErrQuotaExceeded)
+	})
+
+	t.Run("create and delete", func(t *testing.T) {
+		n, _ := newTestNamespaces(t)
+		require.NoError(t, n.Create(pstore.Namespace{Name: "b"}))
+		require.NoError(t, n.Create(pstore.Namespace{Name: "a", MaxKeys: 5}))
+		assert.ErrorIs(t, n.Create(pstore.Namespace{Name: "a"}), pstore.ErrNamespaceExists)
+		assert.ErrorIs(t, n.Create(pstore.Namespace{Name: "Bad Name"}), pstore.ErrInvalidNamespace)
+		assert.ErrorIs(t, n.Create(pstore.Namespace{Name: pstore.DefaultNamespace}), pstore.ErrInvalidNamespace)
+		assert.ErrorIs(t, n.Create(pstore.Namespace{Name: "big", MaxValSize: 1000}), pstore.ErrInvalidNamespace)
+		assert.Equal(t, []pstore.Namespace{{Name: "a", MaxKeys: 5}, {Name: "b"}}, n.List())
+
+		require.NoError(t, n.Delete("a"))
+		assert.ErrorIs(t, n.Delete("a"), pstore.ErrNoSuchNamespace)
+		_, err := n.Store("a")
+		assert.ErrorIs(t, err, pstore.ErrNoSuchNamespace)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		n, _ := newTestNamespaces(t)
+		require.NoError(t, n.Create(pstore.Namespace{Name: "old"}))
+
+		// Current namespaces stay if the fill fails.
+		err := n.Restore([]pstore.Namespace{{Name: "new"}}, func(put func(string, pstore.Entry)) error {
+			put("missing", pstore.Entry{Key: "k", Value: "v"})
+			return nil
+		})
+		assert.ErrorIs(t, err, pstore.ErrNoSuchNamespace)
+		assert.Equal(t, []pstore.Namespace{{Name: "old"}}, n.List())
+
+		spaces := []pstore.Namespace{{Name: "new", MaxKeys: 1}, {Name: "other"}}
+		err = n.Restore(spaces, func(put func(string, pstore.Entry)) error {
+			put("new", pstore.Entry{Key: "k", Value: "v"})
+			put("other", pstore.Entry{Key: "k", Value: "o"})
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, spaces, n.List())
+		st, err := n.Store("new")
+		require.NoError(t, err)
+		val, err := st.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "v", val)
+		assert.ErrorIs(t, st.Put("k2", "v"), pstore.ErrQuotaExceeded)
+		st, err = n.Store("other")
+		require.NoError(t, err)
+		val, err = st.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "o", val)
+	})
+}
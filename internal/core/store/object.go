@@ -0,0 +1,452 @@
+package store
+
+import (
+	"fmt"
+
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+)
+
+// object is a value of a kind other than string.
+type object interface {
+	kind() pstore.Kind
+	// size is a number of bytes accounted by quotas, without the key.
+	size() int
+	// fill sets kind and content of e. Content is shared with the object.
+	fill(e *pstore.Entry)
+}
+
+// newObject builds an object from a restored entry.
+func newObject(e pstore.Entry) (object, error) {
+	switch e.Kind {
+	case pstore.KindHash:
+		return newHashValue(e.Hash), nil
+	case pstore.KindList:
+		return newListValue(e.List), nil
+	case pstore.KindZSet:
+		return newZSetValue(e.ZSet), nil
+	default:
+		return nil, fmt.Errorf("unknown kind of key %q: %d", e.Key, e.Kind)
+	}
+}
+
+// entrySizeOf returns a number of bytes of the entry value accounted by quotas.
+func entrySizeOf(e pstore.Entry) int {
+	switch e.Kind {
+	case pstore.KindHash:
+		return hashSize(e.Hash)
+	case pstore.KindList:
+		return listSize(e.List)
+	case pstore.KindZSet:
+		return zsetSize(e.ZSet)
+	default:
+		return len(e.Value)
+	}
+}
+
+// hashValue is a map of fields. It tracks its size to account quotas without iterating fields.
+type hashValue struct {
+	fields map[string]string
+	bytes  int
+}
+
+func newHashValue(fields map[string]string) *hashValue {
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	return &hashValue{fields: fields, bytes: hashSize(fields)}
+}
+
+func hashSize(fields map[string]string) int {
+	n := 0
+	for f, v := range fields {
+		n += len(f) + len(v)
+	}
+	return n
+}
+
+func (h *hashValue) kind() pstore.Kind {
+	return pstore.KindHash
+}
+
+func (h *hashValue) size() int {
+	return h.bytes
+}
+
+func (h *hashValue) fill(e *pstore.Entry) {
+	e.Kind = pstore.KindHash
+	e.Hash = h.fields
+}
+
+// sizeAfterSet returns the size the hash would have after set.
+func (h *hashValue) sizeAfterSet(fields map[string]string) int {
+	n := h.bytes
+	for f, v := range fields {
+		if old, ok := h.fields[f]; ok {
+			n += len(v) - len(old)
+		} else {
+			n += len(f) + len(v)
+		}
+	}
+	return n
+}
+
+// set stores fields and returns the number of added ones.
+func (h *hashValue) set(fields map[string]string) int {
+	added := 0
+	for f, v := range fields {
+		if old, ok := h.fields[f]; ok {
+			h.bytes -= len(old)
+		} else {
+			h.bytes += len(f)
+			added++
+		}
+		h.bytes += len(v)
+		h.fields[f] = v
+	}
+	return added
+}
+
+// del deletes fields and returns the number of deleted ones.
+func (h *hashValue) del(fields []string) int {
+	deleted := 0
+	for _, f := range fields {
+		if old, ok := h.fields[f]; ok {
+			h.bytes -= len(f) + len(old)
+			delete(h.fields, f)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// listValue is a double-ended queue of values kept in a ring buffer,
+// so pushes and pops on both ends don't move other values.
+type listValue struct {
+	buf   []string
+	head  int
+	n     int
+	bytes int
+}
+
+func newListValue(vals []string) *listValue {
+	l := &listValue{}
+	for _, v := range vals {
+		l.push(v, false)
+	}
+	return l
+}
+
+func listSize(vals []string) int {
+	n := 0
+	for _, v := range vals {
+		n += len(v)
+	}
+	return n
+}
+
+func (l *listValue) kind() pstore.Kind {
+	return pstore.KindList
+}
+
+func (l *listValue) size() int {
+	return l.bytes
+}
+
+func (l *listValue) fill(e *pstore.Entry) {
+	e.Kind = pstore.KindList
+	e.List = l.slice(0, l.n)
+}
+
+func (l *listValue) push(v string, left bool) {
+	if l.n == len(l.buf) {
+		l.resize(max(8, 2*l.n))
+	}
+	if left {
+		l.head = (l.head - 1 + len(l.buf)) % len(l.buf)
+		l.buf[l.head] = v
+	} else {
+		l.buf[(l.head+l.n)%len(l.buf)] = v
+	}
+	l.n++
+	l.bytes += len(v)
+}
+
+func (l *listValue) pop(left bool) string {
+	i := (l.head + l.n - 1) % len(l.buf)
+	if left {
+		i = l.head
+		l.head = (l.head + 1) % len(l.buf)
+	}
+	v := l.buf[i]
+	l.buf[i] = ""
+	l.n--
+	l.bytes -= len(v)
+	// Releases memory of queues which were drained after a burst.
+	if len(l.buf) > 64 && l.n < len(l.buf)/4 {
+		l.resize(len(l.buf) / 2)
+	}
+	return v
+}
+
+// slice returns a copy of values from index from up to index to exclusive.
+func (l *listValue) slice(from, to int) []string {
+	vals := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		vals = append(vals, l.buf[(l.head+i)%len(l.buf)])
+	}
+	return vals
+}
+
+func (l *listValue) resize(size int) {
+	buf := make([]string, size)
+	for i := range l.n {
+		buf[i] = l.buf[(l.head+i)%len(l.buf)]
+	}
+	l.buf = buf
+	l.head = 0
+}
+
+// zsetMaxLevel bounds the height of skiplist nodes, enough for 4^32 members.
+const zsetMaxLevel = 32
+
+// zsetValue is a sorted set kept in a skiplist ordered by score and member, with a map of
+// member scores for lookups. Every level counts the members it skips, so ranks are found
+// without walking the bottom level.
+type zsetValue struct {
+	head   *zsetNode
+	level  int
+	n      int
+	scores map[string]float64
+	bytes  int
+	// rnd picks node levels. It's seeded the same way on every replica,
+	// so replicas applying the same commands build the same skiplist.
+	rnd uint64
+}
+
+type zsetNode struct {
+	member string
+	score  float64
+	levels []zsetLevel
+}
+
+type zsetLevel struct {
+	next *zsetNode
+	// span is the number of members between the node and next, including next.
+	span int
+}
+
+func newZSetValue(members []pstore.ScoredMember) *zsetValue {
+	z := &zsetValue{
+		head:   &zsetNode{levels: make([]zsetLevel, zsetMaxLevel)},
+		level:  1,
+		scores: make(map[string]float64, len(members)),
+		rnd:    0x9e3779b97f4a7c15,
+	}
+	for _, m := range members {
+		z.set(m.Member, m.Score)
+	}
+	return z
+}
+
+// zsetMemberSize is a number of bytes accounted for a member besides its name.
+const zsetMemberSize = 8
+
+func zsetSize(members []pstore.ScoredMember) int {
+	n := 0
+	for _, m := range members {
+		n += len(m.Member) + zsetMemberSize
+	}
+	return n
+}
+
+func (z *zsetValue) kind() pstore.Kind {
+	return pstore.KindZSet
+}
+
+func (z *zsetValue) size() int {
+	return z.bytes
+}
+
+func (z *zsetValue) fill(e *pstore.Entry) {
+	e.Kind = pstore.KindZSet
+	e.ZSet = z.rangeByRank(0, z.n)
+}
+
+// sizeAfterAdd returns the size the set would have after members are set.
+func (z *zsetValue) sizeAfterAdd(members []pstore.ScoredMember) int {
+	n := z.bytes
+	seen := make(map[string]struct{}, len(members))
+	for _, m := range members {
+		if _, ok := z.scores[m.Member]; ok {
+			continue
+		}
+		if _, ok := seen[m.Member]; !ok {
+			seen[m.Member] = struct{}{}
+			n += len(m.Member) + zsetMemberSize
+		}
+	}
+	return n
+}
+
+// set stores the score of a member and reports whether the member was added.
+func (z *zsetValue) set(member string, score float64) bool {
+	old, ok := z.scores[member]
+	if ok {
+		if old == score {
+			return false
+		}
+		z.unlink(member, old)
+	}
+	z.insert(member, score)
+	z.scores[member] = score
+	if !ok {
+		z.bytes += len(member) + zsetMemberSize
+	}
+	return !ok
+}
+
+// del removes a member and reports whether it was there.
+func (z *zsetValue) del(member string) bool {
+	score, ok := z.scores[member]
+	if !ok {
+		return false
+	}
+	z.unlink(member, score)
+	delete(z.scores, member)
+	z.bytes -= len(member) + zsetMemberSize
+	return true
+}
+
+// popMin removes up to count members with the lowest scores.
+func (z *zsetValue) popMin(count int) []pstore.ScoredMember {
+	popped := make([]pstore.ScoredMember, 0, min(count, z.n))
+	for len(popped) < count {
+		first := z.head.levels[0].next
+		if first == nil {
+			break
+		}
+		popped = append(popped, pstore.ScoredMember{Member: first.member, Score: first.score})
+		z.del(first.member)
+	}
+	return popped
+}
+
+// rangeByRank returns members ranked from index from up to index to exclusive.
+func (z *zsetValue) rangeByRank(from, to int) []pstore.ScoredMember {
+	members := make([]pstore.ScoredMember, 0, max(to-from, 0))
+	x := z.head
+	traversed := 0
+	for i := z.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && traversed+x.levels[i].span <= from {
+			traversed += x.levels[i].span
+			x = x.levels[i].next
+		}
+	}
+	for x = x.levels[0].next; x != nil && len(members) < to-from; x = x.levels[0].next {
+		members = append(members, pstore.ScoredMember{Member: x.member, Score: x.score})
+	}
+	return members
+}
+
+// rangeByScore returns up to limit members with scores between lo and hi inclusive.
+func (z *zsetValue) rangeByScore(lo, hi float64, limit int) []pstore.ScoredMember {
+	members := []pstore.ScoredMember{}
+	x := z.head
+	for i := z.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next.score < lo {
+			x = x.levels[i].next
+		}
+	}
+	for x = x.levels[0].next; x != nil && x.score <= hi; x = x.levels[0].next {
+		if limit > 0 && len(members) == limit {
+			break
+		}
+		members = append(members, pstore.ScoredMember{Member: x.member, Score: x.score})
+	}
+	return members
+}
+
+func (z *zsetValue) insert(member string, score float64) {
+	var (
+		update [zsetMaxLevel]*zsetNode
+		rank   [zsetMaxLevel]int
+	)
+	x := z.head
+	for i := z.level - 1; i >= 0; i-- {
+		if i < z.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && zsetLess(x.levels[i].next, member, score) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	level := z.randomLevel()
+	if level > z.level {
+		for i := z.level; i < level; i++ {
+			update[i] = z.head
+			z.head.levels[i].span = z.n
+		}
+		z.level = level
+	}
+
+	node := &zsetNode{member: member, score: score, levels: make([]zsetLevel, level)}
+	for i := range level {
+		node.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = node
+		node.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < z.level; i++ {
+		update[i].levels[i].span++
+	}
+	z.n++
+}
+
+// unlink removes the node of a member from the skiplist. Score must be the current one.
+func (z *zsetValue) unlink(member string, score float64) {
+	var update [zsetMaxLevel]*zsetNode
+	x := z.head
+	for i := z.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && zsetLess(x.levels[i].next, member, score) {
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	node := x.levels[0].next
+	for i := range z.level {
+		if update[i].levels[i].next == node {
+			update[i].levels[i].span += node.levels[i].span - 1
+			update[i].levels[i].next = node.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	for z.level > 1 && z.head.levels[z.level-1].next == nil {
+		z.level--
+	}
+	z.n--
+}
+
+// randomLevel returns a level where each next one is 4 times less likely.
+func (z *zsetValue) randomLevel() int {
+	level := 1
+	for level < zsetMaxLevel {
+		z.rnd ^= z.rnd << 13
+		z.rnd ^= z.rnd >> 7
+		z.rnd ^= z.rnd << 17
+		if z.rnd&3 != 0 {
+			break
+		}
+		level++
+	}
+	return level
+}
+
+// zsetLess reports whether node n goes before member with score.
+func zsetLess(n *zsetNode, member string, score float64) bool {
+	return n.score < score || (n.score == score && n.member < member)
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+)
+
+type quotaUsage struct {
+	cfg   cfg.QuotaCfg
+	keys  int
+	bytes int64
+}
+
+// quotas tracks amount of keys and bytes stored under configured key prefixes.
+type quotas struct {
+	mu    sync.Mutex
+	items []*quotaUsage
+}
+
+func newQuotas(cfgs []cfg.QuotaCfg) *quotas {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	q := &quotas{items: make([]*quotaUsage, len(cfgs))}
+	for i, c := range cfgs {
+		q.items[i] = &quotaUsage{cfg: c}
+	}
+	return q
+}
+
+// entrySize returns bytes accounted for a key with a value of the given size.
+func entrySize(key string, size int) int64 {
+	return int64(len(key) + size)
+}
+
+// reserve accounts replacing old value of the key with the new one, given their sizes.
+// Nothing is accounted if any of matching quotas would be exceeded.
+func (q *quotas) reserve(key string, oldSize int, existed bool, newSize int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var keysDelta int
+	bytesDelta := entrySize(key, newSize)
+	if existed {
+		bytesDelta -= entrySize(key, oldSize)
+	} else {
+		keysDelta = 1
+	}
+
+	for _, u := range q.items {
+		if !strings.HasPrefix(key, u.cfg.Prefix) {
+			continue
+		}
+		if u.cfg.MaxKeys > 0 && u.keys+keysDelta > u.cfg.MaxKeys {
+			return pstore.ErrQuotaExceeded
+		}
+		if u.cfg.MaxBytes > 0 && bytesDelta > 0 && u.bytes+bytesDelta > u.cfg.MaxBytes {
+			return pstore.ErrQuotaExceeded
+		}
+	}
+
+	for _, u := range q.items {
+		if strings.HasPrefix(key, u.cfg.Prefix) {
+			u.keys += keysDelta
+			u.bytes += bytesDelta
+		}
+	}
+	return nil
+}
+
+func (q *quotas) release(key string, oldSize int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, u := range q.items {
+		if strings.HasPrefix(key, u.cfg.Prefix) {
+			u.keys--
+			u.bytes -= entrySize(key, oldSize)
+		}
+	}
+}
+
+// reset recalculates usage from scratch.
+func (q *quotas) reset(items map[string]string) {
+	q.clear()
+	for k, v := range items {
+		q.add(k, len(v))
+	}
+}
+
+// replace takes over usage counted by other.
+func (q *quotas) replace(other *quotas) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	q.items = other.items
+}
+
+func (q *quotas) clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, u := range q.items {
+		u.keys = 0
+		u.bytes = 0
+	}
+}
+
+// add accounts a new entry without checking the limits.
+func (q *quotas) add(key string, size int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, u := range q.items {
+		if strings.HasPrefix(key, u.cfg.Prefix) {
+			u.keys++
+			u.bytes += entrySize(key, size)
+		}
+	}
+}
@@ -1,12 +1,15 @@
 package store
 
 import (
+	"cmp"
 	"context"
 	"maps"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shrtyk/kv-store/internal/cfg"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
 )
 
 const (
@@ -15,9 +18,16 @@ const (
 )
 
 type Shard struct {
-	cfg     *cfg.ShardsCfg
-	mu      sync.RWMutex
-	m       map[string]string
+	cfg *cfg.ShardsCfg
+	mu  sync.RWMutex
+	m   map[string]string
+	// objs holds values of other kinds than string. A key is either in m or in objs.
+	objs map[string]object
+	// exp holds expiry deadlines in unix milliseconds of keys which expire.
+	exp map[string]int64
+	// ver holds versions of all keys, flags holds non-zero flags.
+	ver     map[string]uint64
+	flags   map[string]uint32
 	puts    uint64
 	deletes uint64
 	maxSize int
@@ -28,6 +38,8 @@ type ShardedMap struct {
 	shardsCfg *cfg.ShardsCfg
 	shards    []*Shard
 	hash      Hasher
+	// version is the last version assigned to a written key.
+	version atomic.Uint64
 }
 
 func (s *Shard) needsRebuild() bool {
@@ -35,7 +47,7 @@ func (s *Shard) needsRebuild() bool {
 	dels := s.deletes
 	puts := s.puts
 	maxSize := s.maxSize
-	curSize := len(s.m)
+	curSize := s.len()
 	s.mu.RUnlock()
 
 	totalOps := puts + dels
@@ -52,14 +64,30 @@ func (s *Shard) rebuild() {
 	s.mu.RLock()
 	newMap := make(map[string]string, len(s.m))
 	maps.Copy(newMap, s.m)
+	newObjs := make(map[string]object, len(s.objs))
+	maps.Copy(newObjs, s.objs)
+	newExp := make(map[string]int64, len(s.exp))
+	maps.Copy(newExp, s.exp)
+	newVer := make(map[string]uint64, len(s.ver))
+	maps.Copy(newVer, s.ver)
+	newFlags := make(map[string]uint32, len(s.flags))
+	maps.Copy(newFlags, s.flags)
 	s.mu.RUnlock()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.m = newMap
+	s.objs = newObjs
+	s.exp = newExp
+	s.ver = newVer
+	s.flags = newFlags
 	s.puts = 0
 	s.deletes = 0
-	s.maxSize = len(s.m)
+	s.maxSize = s.len()
+}
+
+func (s *Shard) len() int {
+	return len(s.m) + len(s.objs)
 }
 
 type Hasher interface {
@@ -73,10 +101,7 @@ func NewShardedMap(shardsCfg *cfg.ShardsCfg, shardsCount int, hasher Hasher) *Sh
 
 	shards := make([]*Shard, shardsCount)
 	for i := 0; i < shardsCount; i++ {
-		shards[i] = &Shard{
-			cfg: shardsCfg,
-			m:   make(map[string]string),
-		}
+		shards[i] = newShard(shardsCfg)
 	}
 
 	return &ShardedMap{
@@ -86,6 +111,17 @@ func NewShardedMap(shardsCfg *cfg.ShardsCfg, shardsCount int, hasher Hasher) *Sh
 	}
 }
 
+func newShard(shardsCfg *cfg.ShardsCfg) *Shard {
+	return &Shard{
+		cfg:   shardsCfg,
+		m:     make(map[string]string),
+		objs:  make(map[string]object),
+		exp:   make(map[string]int64),
+		ver:   make(map[string]uint64),
+		flags: make(map[string]uint32),
+	}
+}
+
 func (m *ShardedMap) getShard(key string) *Shard {
 	return m.shards[m.hash.Sum64(key)%uint64(len(m.shards))]
 }
@@ -97,8 +133,127 @@ func (m *ShardedMap) Put(key, value string) {
 	defer shard.mu.Unlock()
 
 	shard.m[key] = value
+	delete(shard.objs, key)
+	delete(shard.exp, key)
+	delete(shard.flags, key)
+	shard.ver[key] = m.version.Add(1)
+	shard.puts++
+	shard.maxSize = max(shard.maxSize, shard.len())
+}
+
+// PutEntry stores value with expiry and flags of e and assigns it a new version.
+func (m *ShardedMap) PutEntry(e pstore.Entry) {
+	shard := m.getShard(e.Key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.m[e.Key] = e.Value
+	delete(shard.objs, e.Key)
+	shard.setExpiry(e.Key, e.ExpiresAt)
+	shard.setFlags(e.Key, e.Flags)
+	shard.ver[e.Key] = m.version.Add(1)
+	shard.puts++
+	shard.maxSize = max(shard.maxSize, shard.len())
+}
+
+// update changes the object of a key alive at now under the shard lock. fn gets nil if there is
+// no such key and size of the value the key held even if it's expired. The key is deleted if fn
+// returns nil object. Keys holding a string or an object of another kind give ErrWrongType.
+func (m *ShardedMap) update(
+	key string,
+	kind pstore.Kind,
+	now int64,
+	fn func(obj object, prevSize int, existed bool) (object, error),
+) error {
+	shard := m.getShard(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	obj, err := shard.live(key, kind, now)
+	if err != nil {
+		return err
+	}
+	prevSize, existed := shard.sizeOf(key)
+	next, err := fn(obj, prevSize, existed)
+	if err != nil {
+		return err
+	}
+
+	if next == nil {
+		if existed {
+			shard.delete(key)
+		}
+		return nil
+	}
+	if obj == nil {
+		// Expired value is replaced together with its metadata.
+		delete(shard.m, key)
+		delete(shard.exp, key)
+		delete(shard.flags, key)
+	}
+	shard.objs[key] = next
+	shard.ver[key] = m.version.Add(1)
 	shard.puts++
-	shard.maxSize = max(shard.maxSize, len(shard.m))
+	shard.maxSize = max(shard.maxSize, shard.len())
+	return nil
+}
+
+// view calls fn with the object of a key alive at now under the shard read lock.
+func (m *ShardedMap) view(key string, kind pstore.Kind, now int64, fn func(obj object)) error {
+	shard := m.getShard(key)
+
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	obj, err := shard.live(key, kind, now)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		return pstore.ErrNoSuchKey
+	}
+	fn(obj)
+	return nil
+}
+
+// live returns the object of a key alive at now, nil if there is no such key.
+func (s *Shard) live(key string, kind pstore.Kind, now int64) (object, error) {
+	if at, ok := s.exp[key]; ok && at <= now {
+		return nil, nil
+	}
+	if _, ok := s.m[key]; ok {
+		return nil, pstore.ErrWrongType
+	}
+	obj, ok := s.objs[key]
+	if !ok {
+		return nil, nil
+	}
+	if obj.kind() != kind {
+		return nil, pstore.ErrWrongType
+	}
+	return obj, nil
+}
+
+// SizeOf returns size of the value of a key accounted by quotas, regardless of its kind and expiry.
+func (m *ShardedMap) SizeOf(key string) (int, bool) {
+	shard := m.getShard(key)
+
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	return shard.sizeOf(key)
+}
+
+func (s *Shard) sizeOf(key string) (int, bool) {
+	if val, ok := s.m[key]; ok {
+		return len(val), true
+	}
+	if obj, ok := s.objs[key]; ok {
+		return obj.size(), true
+	}
+	return 0, false
 }
 
 func (m *ShardedMap) Get(key string) (string, bool) {
@@ -111,21 +266,190 @@ func (m *ShardedMap) Get(key string) (string, bool) {
 	return val, ok
 }
 
+// Lookup returns the entry of a key regardless of its expiry.
+func (m *ShardedMap) Lookup(key string) (pstore.Entry, bool) {
+	shard := m.getShard(key)
+
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	if val, ok := shard.m[key]; ok {
+		return shard.entry(key, val), true
+	}
+	obj, ok := shard.objs[key]
+	if !ok {
+		return pstore.Entry{}, false
+	}
+	e := shard.entry(key, "")
+	e.Kind = obj.kind()
+	return e, true
+}
+
+func (s *Shard) entry(key, val string) pstore.Entry {
+	return pstore.Entry{
+		Key:       key,
+		Value:     val,
+		ExpiresAt: s.exp[key],
+		Flags:     s.flags[key],
+		Version:   s.ver[key],
+	}
+}
+
+// SetExpiry sets expiry deadline of an existing key. Zero removes the expiry.
+func (m *ShardedMap) SetExpiry(key string, expiresAt int64) bool {
+	shard := m.getShard(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if _, ok := shard.sizeOf(key); !ok {
+		return false
+	}
+	shard.setExpiry(key, expiresAt)
+	return true
+}
+
+func (s *Shard) setExpiry(key string, expiresAt int64) {
+	if expiresAt > 0 {
+		s.exp[key] = expiresAt
+	} else {
+		delete(s.exp, key)
+	}
+}
+
+func (s *Shard) setFlags(key string, flags uint32) {
+	if flags != 0 {
+		s.flags[key] = flags
+	} else {
+		delete(s.flags, key)
+	}
+}
+
 func (m *ShardedMap) Delete(key string) {
 	shard := m.getShard(key)
 
 	shard.mu.Lock()
 	defer shard.mu.Unlock()
 
-	delete(shard.m, key)
-	shard.deletes++
+	shard.delete(key)
+}
+
+func (s *Shard) delete(key string) {
+	delete(s.m, key)
+	delete(s.objs, key)
+	delete(s.exp, key)
+	delete(s.ver, key)
+	delete(s.flags, key)
+	s.deletes++
+}
+
+// ExpireAll makes every key expire at expiresAt unless it expires earlier.
+func (m *ShardedMap) ExpireAll(expiresAt int64) {
+	m.mu.Lock()
+	shards := m.shards
+	m.mu.Unlock()
+
+	for _, shard := range shards {
+		shard.mu.Lock()
+		for k := range shard.m {
+			shard.expireBy(k, expiresAt)
+		}
+		for k := range shard.objs {
+			shard.expireBy(k, expiresAt)
+		}
+		shard.mu.Unlock()
+	}
+}
+
+func (s *Shard) expireBy(key string, expiresAt int64) {
+	if at, ok := s.exp[key]; !ok || at > expiresAt {
+		s.exp[key] = expiresAt
+	}
+}
+
+// LastVersion returns the last version assigned to a written key.
+func (m *ShardedMap) LastVersion() uint64 {
+	return m.version.Load()
+}
+
+// RestoreVersion raises the version counter to at least v.
+func (m *ShardedMap) RestoreVersion(v uint64) {
+	for {
+		cur := m.version.Load()
+		if cur >= v || m.version.CompareAndSwap(cur, v) {
+			return
+		}
+	}
+}
+
+// ExpiredKeys returns up to limit keys expired at now.
+func (m *ShardedMap) ExpiredKeys(now int64, limit int) []string {
+	m.mu.Lock()
+	shards := m.shards
+	m.mu.Unlock()
+
+	var keys []string
+	for _, shard := range shards {
+		shard.mu.RLock()
+		for k, at := range shard.exp {
+			if len(keys) >= limit {
+				break
+			}
+			if at <= now {
+				keys = append(keys, k)
+			}
+		}
+		shard.mu.RUnlock()
+		if len(keys) >= limit {
+			break
+		}
+	}
+	return keys
+}
+
+// ScanShard returns keys alive at now from the shard at cursor and the cursor
+// of the next shard, which is zero after the last one.
+func (m *ShardedMap) ScanShard(cursor int, now int64) ([]string, int) {
+	m.mu.Lock()
+	shards := m.shards
+	m.mu.Unlock()
+
+	if cursor < 0 || cursor >= len(shards) {
+		return nil, 0
+	}
+	shard := shards[cursor]
+
+	shard.mu.RLock()
+	keys := make([]string, 0, shard.len())
+	for k := range shard.m {
+		if !shard.expired(k, now) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range shard.objs {
+		if !shard.expired(k, now) {
+			keys = append(keys, k)
+		}
+	}
+	shard.mu.RUnlock()
+
+	next := cursor + 1
+	if next == len(shards) {
+		next = 0
+	}
+	return keys, next
+}
+
+func (s *Shard) expired(key string, now int64) bool {
+	at, ok := s.exp[key]
+	return ok && at <= now
 }
 
 func (m *ShardedMap) Len() int {
 	count := 0
 	for _, shard := range m.shards {
 		shard.mu.RLock()
-		count += len(shard.m)
+		count += shard.len()
 		shard.mu.RUnlock()
 	}
 	return count
@@ -142,22 +466,90 @@ func (m *ShardedMap) Items() map[string]string {
 }
 
 func (m *ShardedMap) RestoreFromSnapshot(snapData map[string]string) {
-	newShards := make([]*Shard, m.shardsCfg.ShardsCount)
-	for i := range m.shardsCfg.ShardsCount {
-		newShards[i] = &Shard{
-			cfg: m.shardsCfg,
-			m:   make(map[string]string),
+	_ = m.RestoreFrom(func(put func(e pstore.Entry)) error {
+		for k, v := range snapData {
+			put(pstore.Entry{Key: k, Value: v})
 		}
+		return nil
+	})
+}
+
+// RestoreFrom replaces content of the map with entries passed to put by fill.
+// Current content is left untouched if fill returns an error or passes an entry of unknown kind.
+// Objects take over collections of entries.
+func (m *ShardedMap) RestoreFrom(fill func(put func(e pstore.Entry)) error) error {
+	m.mu.Lock()
+	count := len(m.shards)
+	m.mu.Unlock()
+
+	newShards := make([]*Shard, count)
+	for i := range count {
+		newShards[i] = newShard(m.shardsCfg)
 	}
 
-	for k, v := range snapData {
-		s := newShards[m.hash.Sum64(k)%uint64(m.shardsCfg.ShardsCount)]
-		s.m[k] = v
+	var (
+		version uint64
+		objErr  error
+	)
+	err := fill(func(e pstore.Entry) {
+		s := newShards[m.hash.Sum64(e.Key)%uint64(count)]
+		if e.Kind == pstore.KindString {
+			s.m[e.Key] = e.Value
+		} else {
+			obj, err := newObject(e)
+			if err != nil {
+				objErr = cmp.Or(objErr, err)
+				return
+			}
+			s.objs[e.Key] = obj
+		}
+		s.setExpiry(e.Key, e.ExpiresAt)
+		s.setFlags(e.Key, e.Flags)
+		// Legacy snapshots have no versions, so keys get new ones.
+		if e.Version == 0 {
+			e.Version = version + 1
+		}
+		s.ver[e.Key] = e.Version
+		version = max(version, e.Version)
+		s.maxSize = max(s.maxSize, s.len())
+	})
+	if err = cmp.Or(err, objErr); err != nil {
+		return err
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.shards = newShards
+	m.version.Store(version)
+	return nil
+}
+
+// RangeEntries calls fn with every entry while its shard is read locked,
+// so fn must not access the map. Iteration stops at the first error.
+func (m *ShardedMap) RangeEntries(fn func(e pstore.Entry) error) error {
+	m.mu.Lock()
+	shards := m.shards
+	m.mu.Unlock()
+
+	for _, shard := range shards {
+		shard.mu.RLock()
+		for k, v := range shard.m {
+			if err := fn(shard.entry(k, v)); err != nil {
+				shard.mu.RUnlock()
+				return err
+			}
+		}
+		for k, obj := range shard.objs {
+			e := shard.entry(k, "")
+			obj.fill(&e)
+			if err := fn(e); err != nil {
+				shard.mu.RUnlock()
+				return err
+			}
+		}
+		shard.mu.RUnlock()
+	}
+	return nil
 }
 
 func (m *ShardedMap) StartShardsSupervisor(ctx context.Context, wg *sync.WaitGroup) {
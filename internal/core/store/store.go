@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/shrtyk/kv-store/internal/cfg"
 	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
@@ -12,6 +13,7 @@ import (
 type store struct {
 	cfg     *cfg.StoreCfg
 	storage *ShardedMap
+	quotas  *quotas
 
 	logger *slog.Logger
 }
@@ -20,34 +22,104 @@ func NewStore(wg *sync.WaitGroup, cfg *cfg.StoreCfg, shardCfg *cfg.ShardsCfg, l
 	return &store{
 		cfg:     cfg,
 		storage: NewShardedMap(shardCfg, shardCfg.ShardsCount, Xxhasher{}),
+		quotas:  newQuotas(cfg.Quotas),
 		logger:  l,
 	}
 }
 
 func (s *store) Put(key, value string) error {
-	if len(key) > s.cfg.MaxKeySize {
+	return s.PutEntry(pstore.Entry{Key: key, Value: value})
+}
+
+func (s *store) PutEntry(e pstore.Entry) error {
+	if len(e.Key) > s.cfg.MaxKeySize {
 		return pstore.ErrKeyTooLarge
 	}
-	if len(value) > s.cfg.MaxValSize {
+	if len(e.Value) > s.cfg.MaxValSize {
 		return pstore.ErrValueTooLarge
 	}
-	s.storage.Put(key, value)
+	if s.quotas != nil {
+		old, existed := s.storage.SizeOf(e.Key)
+		if err := s.quotas.reserve(e.Key, old, existed, len(e.Value)); err != nil {
+			return err
+		}
+	}
+
+	s.storage.PutEntry(e)
 	return nil
 }
 
 func (s *store) Get(key string) (string, error) {
-	val, ok := s.storage.Get(key)
-	if !ok {
-		return "", pstore.ErrNoSuchKey
+	e, err := s.Lookup(key, time.Now().UnixMilli())
+	if err != nil {
+		return "", err
+	}
+	if e.Kind != pstore.KindString {
+		return "", pstore.ErrWrongType
 	}
-	return val, nil
+	return e.Value, nil
+}
+
+func (s *store) Lookup(key string, now int64) (pstore.Entry, error) {
+	e, ok := s.storage.Lookup(key)
+	if !ok || e.Expired(now) {
+		return pstore.Entry{}, pstore.ErrNoSuchKey
+	}
+	return e, nil
 }
 
 func (s *store) Delete(key string) error {
+	if s.quotas != nil {
+		if old, existed := s.storage.SizeOf(key); existed {
+			s.quotas.release(key, old)
+		}
+	}
+
 	s.storage.Delete(key)
 	return nil
 }
 
+func (s *store) Expire(key string, expiresAt, now int64) error {
+	if _, err := s.Lookup(key, now); err != nil {
+		return err
+	}
+	if expiresAt > 0 && expiresAt <= now {
+		return s.Delete(key)
+	}
+	s.storage.SetExpiry(key, expiresAt)
+	return nil
+}
+
+func (s *store) ExpiredKeys(now int64, limit int) []string {
+	return s.storage.ExpiredKeys(now, limit)
+}
+
+func (s *store) DeleteExpired(keys []string, now int64) []string {
+	var deleted []string
+	for _, key := range keys {
+		// The key could have been overwritten after it was picked for deletion.
+		if e, ok := s.storage.Lookup(key); ok && e.Expired(now) {
+			_ = s.Delete(key)
+			deleted = append(deleted, key)
+		}
+	}
+	return deleted
+}
+
+func (s *store) FlushAll(expiresAt, now int64) {
+	if expiresAt > now {
+		s.storage.ExpireAll(expiresAt)
+		return
+	}
+	version := s.storage.LastVersion()
+	_ = s.RestoreFrom(func(put func(e pstore.Entry)) error { return nil })
+	s.storage.RestoreVersion(version)
+}
+
+func (s *store) ScanShard(cursor int, now int64) ([]string, int) {
+	return s.storage.ScanShard(cursor, now)
+}
+
 func (s *store) StartMapRebuilder(ctx context.Context, wg *sync.WaitGroup) {
 	s.storage.StartShardsSupervisor(ctx, wg)
 }
@@ -58,4 +130,43 @@ func (s *store) Items() map[string]string {
 
 func (s *store) RestoreFromSnapshot(snapData map[string]string) {
 	s.storage.RestoreFromSnapshot(snapData)
+	if s.quotas != nil {
+		s.quotas.reset(snapData)
+	}
+}
+
+func (s *store) RestoreFrom(fill func(put func(e pstore.Entry)) error) error {
+	var usage *quotas
+	if s.quotas != nil {
+		usage = newQuotas(s.cfg.Quotas)
+	}
+
+	err := s.storage.RestoreFrom(func(put func(e pstore.Entry)) error {
+		return fill(func(e pstore.Entry) {
+			put(e)
+			if usage != nil {
+				usage.add(e.Key, entrySizeOf(e))
+			}
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if usage != nil {
+		s.quotas.replace(usage)
+	}
+	return nil
+}
+
+func (s *store) RangeEntries(fn func(e pstore.Entry) error) error {
+	return s.storage.RangeEntries(fn)
+}
+
+func (s *store) LastVersion() uint64 {
+	return s.storage.LastVersion()
+}
+
+func (s *store) RestoreVersion(v uint64) {
+	s.storage.RestoreVersion(v)
 }
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/shrtyk/kv-store/internal/cfg"
 	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
 	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
 	"github.com/stretchr/testify/assert"
@@ -46,6 +47,142 @@ func TestStore(t *testing.T) {
 	assert.ErrorIs(t, err, pstore.ErrValueTooLarge)
 }
 
+func TestStore_Quotas(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	stCfg := tu.NewMockStoreCfg()
+	stCfg.Quotas = []cfg.QuotaCfg{
+		{Prefix: "jobs:", MaxKeys: 2},
+		{Prefix: "", MaxBytes: 30},
+	}
+	shCfg := tu.NewMockShardsCfg()
+	shCfg.ShardsCount = 4
+	s := NewStore(&sync.WaitGroup{}, stCfg, shCfg, l)
+
+	assert.NoError(t, s.Put("jobs:1", "a"))
+	assert.NoError(t, s.Put("jobs:2", "b"))
+	assert.ErrorIs(t, s.Put("jobs:3", "c"), pstore.ErrQuotaExceeded)
+
+	// Overwriting existing key does not count as a new one.
+	assert.NoError(t, s.Put("jobs:2", "bb"))
+
+	assert.NoError(t, s.Delete("jobs:1"))
+	assert.NoError(t, s.Put("jobs:3", "c"))
+
+	// 15 bytes used by jobs keys, byte quota applies to every key.
+	assert.ErrorIs(t, s.Put("other", "0123456789a"), pstore.ErrQuotaExceeded)
+	assert.NoError(t, s.Put("other", "0123456789"))
+	_, err := s.Get("jobs:1")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+
+	s.RestoreFromSnapshot(map[string]string{"jobs:a": "1"})
+	assert.NoError(t, s.Put("jobs:b", "2"))
+	assert.ErrorIs(t, s.Put("jobs:c", "3"), pstore.ErrQuotaExceeded)
+}
+
+func TestStore_Expiry(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	shCfg := tu.NewMockShardsCfg()
+	shCfg.ShardsCount = 2
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), shCfg, l)
+	now := int64(1_000)
+
+	assert.NoError(t, s.PutEntry(pstore.Entry{Key: "short", Value: "1", ExpiresAt: now + 10}))
+	assert.NoError(t, s.PutEntry(pstore.Entry{Key: "long", Value: "2", ExpiresAt: now + 100}))
+	assert.NoError(t, s.Put("forever", "3"))
+
+	e, err := s.Lookup("short", now)
+	assert.NoError(t, err)
+	assert.Equal(t, pstore.Entry{Key: "short", Value: "1", ExpiresAt: now + 10, Version: 1}, e)
+	_, err = s.Lookup("short", now+10)
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+
+	// Overwriting a key clears its expiry.
+	assert.NoError(t, s.Put("long", "2"))
+	e, err = s.Lookup("long", now+1_000)
+	assert.NoError(t, err)
+	assert.Zero(t, e.ExpiresAt)
+
+	assert.NoError(t, s.Expire("forever", now+20, now))
+	assert.ErrorIs(t, s.Expire("missing", now+20, now), pstore.ErrNoSuchKey)
+	assert.ErrorIs(t, s.Expire("short", now+20, now+10), pstore.ErrNoSuchKey)
+
+	expired := s.ExpiredKeys(now+20, 10)
+	assert.ElementsMatch(t, []string{"short", "forever"}, expired)
+	assert.Len(t, s.ExpiredKeys(now+20, 1), 1)
+
+	// Key overwritten after it was picked for deletion survives.
+	assert.NoError(t, s.Put("forever", "4"))
+	assert.Equal(t, []string{"short"}, s.DeleteExpired(expired, now+20))
+	assert.Empty(t, s.ExpiredKeys(now+20, 10))
+
+	var keys []string
+	cursor := 0
+	for {
+		var batch []string
+		batch, cursor = s.ScanShard(cursor, now)
+		keys = append(keys, batch...)
+		if cursor == 0 {
+			break
+		}
+	}
+	assert.ElementsMatch(t, []string{"long", "forever"}, keys)
+
+	// Expiry deadline in the past deletes the key.
+	assert.NoError(t, s.Expire("long", now, now))
+	_, err = s.Get("long")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+}
+
+func TestStore_Versions(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	shCfg := tu.NewMockShardsCfg()
+	shCfg.ShardsCount = 2
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), shCfg, l)
+	now := int64(1_000)
+
+	assert.NoError(t, s.PutEntry(pstore.Entry{Key: "a", Value: "1", Flags: 7, Version: 100}))
+	assert.NoError(t, s.Put("b", "2"))
+	e, err := s.Lookup("a", now)
+	assert.NoError(t, err)
+	assert.Equal(t, pstore.Entry{Key: "a", Value: "1", Flags: 7, Version: 1}, e)
+
+	// Every write assigns a new version and Put clears flags.
+	assert.NoError(t, s.Put("a", "3"))
+	e, err = s.Lookup("a", now)
+	assert.NoError(t, err)
+	assert.Equal(t, pstore.Entry{Key: "a", Value: "3", Version: 3}, e)
+
+	assert.NoError(t, s.Delete("b"))
+	assert.Equal(t, uint64(3), s.LastVersion())
+
+	// Snapshot restore keeps versions and the counter is raised separately.
+	var entries []pstore.Entry
+	assert.NoError(t, s.RangeEntries(func(e pstore.Entry) error {
+		entries = append(entries, e)
+		return nil
+	}))
+	assert.NoError(t, s.RestoreFrom(func(put func(e pstore.Entry)) error {
+		for _, e := range entries {
+			put(e)
+		}
+		return nil
+	}))
+	s.RestoreVersion(2)
+	assert.Equal(t, uint64(3), s.LastVersion())
+
+	// Delayed flush keeps keys until the deadline, immediate flush keeps the counter.
+	s.FlushAll(now+10, now)
+	_, err = s.Lookup("a", now)
+	assert.NoError(t, err)
+	_, err = s.Lookup("a", now+10)
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+
+	s.FlushAll(0, now)
+	_, err = s.Get("a")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+	assert.Equal(t, uint64(3), s.LastVersion())
+}
+
 func largeString(maxKeySize, maxValSize int) string {
 	b := make([]byte, maxKeySize+maxValSize)
 	_, err := rand.Read(b)
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"math"
+	"time"
+
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+)
+
+func (s *store) ZAdd(key string, members []pstore.ScoredMember, now int64) (int, error) {
+	if len(key) > s.cfg.MaxKeySize {
+		return 0, pstore.ErrKeyTooLarge
+	}
+	for _, m := range members {
+		if err := s.checkMember(m.Member, m.Score); err != nil {
+			return 0, err
+		}
+	}
+
+	added := 0
+	err := s.storage.update(key, pstore.KindZSet, now, func(obj object, prevSize int, existed bool) (object, error) {
+		z, _ := obj.(*zsetValue)
+		if z == nil {
+			if len(members) == 0 {
+				return nil, nil
+			}
+			z = newZSetValue(nil)
+		}
+		if err := s.reserve(key, prevSize, existed, z.sizeAfterAdd(members)); err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if z.set(m.Member, m.Score) {
+				added++
+			}
+		}
+		return z, nil
+	})
+	return added, err
+}
+
+func (s *store) ZRem(key string, members []string, now int64) (int, error) {
+	removed := 0
+	err := s.storage.update(key, pstore.KindZSet, now, func(obj object, prevSize int, existed bool) (object, error) {
+		z, _ := obj.(*zsetValue)
+		if z != nil {
+			for _, m := range members {
+				if z.del(m) {
+					removed++
+				}
+			}
+		}
+		return s.keepZSet(key, z, prevSize, existed), nil
+	})
+	return removed, err
+}
+
+func (s *store) ZScore(key, member string) (float64, error) {
+	var (
+		score float64
+		ok    bool
+	)
+	err := s.storage.view(key, pstore.KindZSet, time.Now().UnixMilli(), func(obj object) {
+		score, ok = obj.(*zsetValue).scores[member]
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, pstore.ErrNoSuchKey
+	}
+	return score, nil
+}
+
+func (s *store) ZRange(key string, start, stop int) ([]pstore.ScoredMember, error) {
+	var members []pstore.ScoredMember
+	err := s.storage.view(key, pstore.KindZSet, time.Now().UnixMilli(), func(obj object) {
+		z := obj.(*zsetValue)
+		if start < 0 {
+			start = max(start+z.n, 0)
+		}
+		if stop < 0 {
+			stop += z.n
+		}
+		stop = min(stop, z.n-1)
+		if start > stop {
+			members = []pstore.ScoredMember{}
+			return
+		}
+		members = z.rangeByRank(start, stop+1)
+	})
+	return members, err
+}
+
+func (s *store) ZRangeByScore(key string, min, max float64, limit int) ([]pstore.ScoredMember, error) {
+	var members []pstore.ScoredMember
+	err := s.storage.view(key, pstore.KindZSet, time.Now().UnixMilli(), func(obj object) {
+		members = obj.(*zsetValue).rangeByScore(min, max, limit)
+	})
+	return members, err
+}
+
+func (s *store) ZIncrBy(key, member string, delta float64, now int64) (float64, error) {
+	if len(key) > s.cfg.MaxKeySize {
+		return 0, pstore.ErrKeyTooLarge
+	}
+	if err := s.checkMember(member, delta); err != nil {
+		return 0, err
+	}
+
+	var next float64
+	err := s.storage.update(key, pstore.KindZSet, now, func(obj object, prevSize int, existed bool) (object, error) {
+		z, _ := obj.(*zsetValue)
+		if z == nil {
+			z = newZSetValue(nil)
+		}
+		next = z.scores[member] + delta
+		if math.IsNaN(next) {
+			return nil, pstore.ErrNotANumber
+		}
+
+		m := []pstore.ScoredMember{{Member: member, Score: next}}
+		if err := s.reserve(key, prevSize, existed, z.sizeAfterAdd(m)); err != nil {
+			return nil, err
+		}
+		z.set(member, next)
+		return z, nil
+	})
+	return next, err
+}
+
+func (s *store) ZPopMin(key string, count int, now int64) ([]pstore.ScoredMember, error) {
+	var popped []pstore.ScoredMember
+	err := s.storage.update(key, pstore.KindZSet, now, func(obj object, prevSize int, existed bool) (object, error) {
+		z, _ := obj.(*zsetValue)
+		if z != nil {
+			popped = z.popMin(count)
+		}
+		return s.keepZSet(key, z, prevSize, existed), nil
+	})
+	return popped, err
+}
+
+// keepZSet returns the sorted set to store after members were removed from it,
+// or nil to delete it together with its quota when it's empty.
+func (s *store) keepZSet(key string, z *zsetValue, prevSize int, existed bool) object {
+	if z == nil || z.n == 0 {
+		// Expired value, if any, is deleted as well.
+		if existed {
+			s.release(key, prevSize)
+		}
+		return nil
+	}
+	_ = s.reserve(key, prevSize, existed, z.size())
+	return z
+}
+
+// checkMember checks a member of a sorted set. Members are limited like values.
+func (s *store) checkMember(member string, score float64) error {
+	if len(member) > s.cfg.MaxValSize {
+		return pstore.ErrValueTooLarge
+	}
+	if math.IsNaN(score) {
+		return pstore.ErrNotANumber
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	pstore "github.com/shrtyk/kv-store/internal/core/ports/store"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scored(member string, score float64) pstore.ScoredMember {
+	return pstore.ScoredMember{Member: member, Score: score}
+}
+
+func TestStore_ZSet(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	added, err := s.ZAdd("z", []pstore.ScoredMember{scored("b", 2), scored("a", 1), scored("c", 2)}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, added)
+	added, err = s.ZAdd("z", []pstore.ScoredMember{scored("a", 3), scored("d", -1)}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	// Members with equal scores are ordered by member.
+	members, err := s.ZRange("z", 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []pstore.ScoredMember{scored("d", -1), scored("b", 2), scored("c", 2), scored("a", 3)}, members)
+	members, err = s.ZRange("z", -2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []pstore.ScoredMember{scored("c", 2), scored("a", 3)}, members)
+	members, err = s.ZRangeByScore("z", 0, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []pstore.ScoredMember{scored("b", 2), scored("c", 2)}, members)
+	members, err = s.ZRangeByScore("z", math.Inf(-1), math.Inf(1), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []pstore.ScoredMember{scored("d", -1)}, members)
+
+	score, err := s.ZScore("z", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, score)
+	_, err = s.ZScore("z", "missing")
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+
+	score, err = s.ZIncrBy("z", "d", 10.5, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 9.5, score)
+	score, err = s.ZIncrBy("z", "e", 0.5, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, score)
+	_, err = s.ZIncrBy("z", "inf", math.Inf(1), now)
+	assert.NoError(t, err)
+	_, err = s.ZIncrBy("z", "inf", math.Inf(-1), now)
+	assert.ErrorIs(t, err, pstore.ErrNotANumber)
+	_, err = s.ZAdd("z", []pstore.ScoredMember{scored("nan", math.NaN())}, now)
+	assert.ErrorIs(t, err, pstore.ErrNotANumber)
+
+	popped, err := s.ZPopMin("z", 2, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []pstore.ScoredMember{scored("e", 0.5), scored("b", 2)}, popped)
+	removed, err := s.ZRem("z", []string{"c", "missing"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	// Sorted set without members is deleted.
+	popped, err = s.ZPopMin("z", 10, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []pstore.ScoredMember{scored("a", 3), scored("d", 9.5), scored("inf", math.Inf(1))}, popped)
+	_, err = s.ZRange("z", 0, -1)
+	assert.ErrorIs(t, err, pstore.ErrNoSuchKey)
+
+	assert.NoError(t, s.Put("s", "v"))
+	_, err = s.ZAdd("s", []pstore.ScoredMember{scored("a", 1)}, now)
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+	_, err = s.ZScore("s", "a")
+	assert.ErrorIs(t, err, pstore.ErrWrongType)
+}
+
+func TestStore_ZSetMatchesSortedMembers(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	s := NewStore(&sync.WaitGroup{}, tu.NewMockStoreCfg(), tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	scores := make(map[string]float64)
+	for range 2000 {
+		member := strconv.Itoa(rnd.IntN(300))
+		switch rnd.IntN(4) {
+		case 0:
+			_, err := s.ZRem("z", []string{member}, now)
+			require.NoError(t, err)
+			delete(scores, member)
+		default:
+			score := float64(rnd.IntN(50))
+			_, err := s.ZAdd("z", []pstore.ScoredMember{scored(member, score)}, now)
+			require.NoError(t, err)
+			scores[member] = score
+		}
+	}
+
+	want := make([]pstore.ScoredMember, 0, len(scores))
+	for m, sc := range scores {
+		want = append(want, pstore.ScoredMember{Member: m, Score: sc})
+	}
+	slices.SortFunc(want, func(a, b pstore.ScoredMember) int {
+		return cmp.Or(cmp.Compare(a.Score, b.Score), cmp.Compare(a.Member, b.Member))
+	})
+
+	all, err := s.ZRange("z", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, want, all)
+	for _, rank := range []int{0, 1, 17, len(want) / 2, len(want) - 1} {
+		got, err := s.ZRange("z", rank, rank+2)
+		require.NoError(t, err)
+		assert.Equal(t, want[rank:min(rank+3, len(want))], got, "rank %d", rank)
+	}
+	byScore, err := s.ZRangeByScore("z", 10, 20, 0)
+	require.NoError(t, err)
+	for _, m := range byScore {
+		assert.True(t, m.Score >= 10 && m.Score <= 20)
+	}
+	assert.Equal(t, slices.IndexFunc(want, func(m pstore.ScoredMember) bool { return m.Score >= 10 }),
+		slices.Index(want, byScore[0]))
+}
+
+func TestStore_ZSetQuotasAndRestore(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	stCfg := tu.NewMockStoreCfg()
+	// Key and every member with its 8 byte score are accounted.
+	stCfg.Quotas = []cfg.QuotaCfg{{Prefix: "", MaxBytes: 21}}
+	s := NewStore(&sync.WaitGroup{}, stCfg, tu.NewMockShardsCfg(), l)
+	now := int64(1_000)
+
+	_, err := s.ZAdd("z", []pstore.ScoredMember{scored("a", 1), scored("b", 2)}, now)
+	assert.NoError(t, err)
+	_, err = s.ZAdd("z", []pstore.ScoredMember{scored("c", 3)}, now)
+	assert.ErrorIs(t, err, pstore.ErrQuotaExceeded)
+	_, err = s.ZIncrBy("z", "a", 5, now)
+	assert.NoError(t, err)
+
+	var entries []pstore.Entry
+	assert.NoError(t, s.RangeEntries(func(e pstore.Entry) error {
+		entries = append(entries, e)
+		return nil
+	}))
+	assert.Equal(t, []pstore.ScoredMember{scored("b", 2), scored("a", 6)}, entries[0].ZSet)
+
+	_, err = s.ZPopMin("z", 2, now)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Put("x", "0123456789"))
+
+	assert.NoError(t, s.RestoreFrom(func(put func(e pstore.Entry)) error {
+		for _, e := range entries {
+			put(e)
+		}
+		return nil
+	}))
+	members, err := s.ZRange("z", 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []pstore.ScoredMember{scored("b", 2), scored("a", 6)}, members)
+	_, err = s.ZAdd("z", []pstore.ScoredMember{scored("c", 3)}, now)
+	assert.ErrorIs(t, err, pstore.ErrQuotaExceeded)
+}
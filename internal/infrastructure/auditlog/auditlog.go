@@ -0,0 +1,173 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/audit"
+	"github.com/shrtyk/kv-store/pkg/logger"
+)
+
+var _ audit.Logger = (*fileLogger)(nil)
+
+var errClosed = errors.New("auditlog: audit log is closed")
+
+const rotatedTimeLayout = "20060102T150405.000000000"
+
+// fileLogger appends audit records as JSON lines to a file and rotates it
+// once it grows over the configured size or becomes older than the configured age.
+type fileLogger struct {
+	mu       sync.Mutex
+	cfg      *cfg.AuditCfg
+	log      *slog.Logger
+	f        *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func NewFileLogger(cfg *cfg.AuditCfg, l *slog.Logger) (*fileLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	fl := &fileLogger{
+		cfg: cfg,
+		log: l,
+		now: time.Now,
+	}
+	if err := fl.open(); err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+func (a *fileLogger) open() error {
+	f, err := os.OpenFile(a.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+
+	a.f = f
+	a.size = info.Size()
+	a.openedAt = a.now()
+	return nil
+}
+
+// Record writes the record to the current audit file.
+func (a *fileLogger) Record(rec *audit.Record) error {
+	if rec.Time.IsZero() {
+		rec.Time = a.now().UTC()
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	b = append(b, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.f == nil {
+		return errClosed
+	}
+
+	if a.needsRotation(len(b)) {
+		if err := a.rotate(); err != nil {
+			a.log.Error("failed to rotate audit log", logger.ErrorAttr(err))
+		}
+	}
+
+	n, err := a.f.Write(b)
+	a.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+func (a *fileLogger) needsRotation(next int) bool {
+	if a.size == 0 {
+		return false
+	}
+	if a.cfg.MaxSize > 0 && a.size+int64(next) > a.cfg.MaxSize {
+		return true
+	}
+	if a.cfg.MaxAge > 0 && a.now().Sub(a.openedAt) >= a.cfg.MaxAge {
+		return true
+	}
+	return false
+}
+
+func (a *fileLogger) rotate() error {
+	if err := a.f.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	a.f = nil
+
+	if err := os.Rename(a.cfg.Path, a.rotatedName(a.now())); err != nil {
+		return fmt.Errorf("failed to rename audit log: %w", err)
+	}
+	if err := a.open(); err != nil {
+		return err
+	}
+	return a.prune()
+}
+
+func (a *fileLogger) rotatedName(t time.Time) string {
+	ext := filepath.Ext(a.cfg.Path)
+	base := strings.TrimSuffix(a.cfg.Path, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.UTC().Format(rotatedTimeLayout), ext)
+}
+
+// prune removes the oldest rotated files exceeding MaxBackups.
+func (a *fileLogger) prune() error {
+	if a.cfg.MaxBackups <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(a.cfg.Path)
+	pattern := strings.TrimSuffix(a.cfg.Path, ext) + "-*" + ext
+	backups, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("failed to list audit log backups: %w", err)
+	}
+	if len(backups) <= a.cfg.MaxBackups {
+		return nil
+	}
+
+	// Rotated names embed a sortable timestamp.
+	slices.Sort(backups)
+	for _, name := range backups[:len(backups)-a.cfg.MaxBackups] {
+		if err := os.Remove(name); err != nil {
+			return fmt.Errorf("failed to remove audit log backup: %w", err)
+		}
+	}
+	return nil
+}
+
+func (a *fileLogger) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.f == nil {
+		return nil
+	}
+	err := a.f.Close()
+	a.f = nil
+	return err
+}
@@ -0,0 +1,132 @@
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/audit"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readRecords(t *testing.T, path string) []audit.Record {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+
+	var recs []audit.Record
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec audit.Record
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &rec))
+		recs = append(recs, rec)
+	}
+	require.NoError(t, sc.Err())
+	return recs
+}
+
+func TestFileLogger_Record(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	fl, err := NewFileLogger(&cfg.AuditCfg{Path: path}, l)
+	require.NoError(t, err)
+
+	require.NoError(t, fl.Record(&audit.Record{
+		Op:        audit.OpPut,
+		Key:       "key",
+		ValueSize: 3,
+		ValueHash: audit.HashValue([]byte("val")),
+		Caller:    "10.0.0.1",
+		RequestID: "req-1",
+		LogIndex:  7,
+	}))
+	require.NoError(t, fl.Record(&audit.Record{Op: audit.OpDelete, Key: "key", LogIndex: 8}))
+	require.NoError(t, fl.Close())
+	assert.ErrorIs(t, fl.Record(&audit.Record{Op: audit.OpDelete, Key: "key", LogIndex: 9}), errClosed)
+
+	recs := readRecords(t, path)
+	require.Len(t, recs, 2)
+	assert.Equal(t, audit.OpPut, recs[0].Op)
+	assert.Equal(t, int64(7), recs[0].LogIndex)
+	assert.Equal(t, "req-1", recs[0].RequestID)
+	assert.False(t, recs[0].Time.IsZero())
+	assert.Equal(t, audit.OpDelete, recs[1].Op)
+	assert.Empty(t, recs[1].ValueHash)
+}
+
+func TestFileLogger_RotateBySize(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	fl, err := NewFileLogger(&cfg.AuditCfg{Path: path, MaxSize: 200, MaxBackups: 2}, l)
+	require.NoError(t, err)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := 0
+	fl.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Second)
+	}
+
+	for i := range 20 {
+		require.NoError(t, fl.Record(&audit.Record{Op: audit.OpPut, Key: "some-key", LogIndex: int64(i)}))
+	}
+	require.NoError(t, fl.Close())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(200))
+
+	recs := readRecords(t, path)
+	require.NotEmpty(t, recs)
+	assert.Equal(t, int64(19), recs[len(recs)-1].LogIndex)
+}
+
+func TestFileLogger_RotateByAge(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	fl, err := NewFileLogger(&cfg.AuditCfg{Path: path, MaxAge: time.Hour}, l)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fl.now = func() time.Time { return now }
+	fl.openedAt = now
+
+	require.NoError(t, fl.Record(&audit.Record{Op: audit.OpPut, Key: "a", LogIndex: 1}))
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, fl.Record(&audit.Record{Op: audit.OpPut, Key: "b", LogIndex: 2}))
+	require.NoError(t, fl.Close())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	assert.Equal(t, "a", readRecords(t, backups[0])[0].Key)
+	assert.Equal(t, "b", readRecords(t, path)[0].Key)
+}
+
+func TestFileLogger_ReopenAppends(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	for i := range 2 {
+		fl, err := NewFileLogger(&cfg.AuditCfg{Path: path}, l)
+		require.NoError(t, err)
+		require.NoError(t, fl.Record(&audit.Record{Op: audit.OpPut, Key: "k", LogIndex: int64(i)}))
+		require.NoError(t, fl.Close())
+	}
+
+	assert.Len(t, readRecords(t, path), 2)
+}
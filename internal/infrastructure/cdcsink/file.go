@@ -0,0 +1,168 @@
+package cdcsink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+)
+
+var _ cdc.Sink = (*fileSink)(nil)
+
+var errClosed = errors.New("cdc sink is closed")
+
+const rotatedTimeLayout = "20060102T150405.000000000"
+
+// fileSink appends events as JSON lines to a file and rotates it once it grows over
+// the configured size or becomes older than the configured age.
+type fileSink struct {
+	mu       sync.Mutex
+	cfg      *cfg.CDCFileCfg
+	f        *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func NewFileSink(cfg *cfg.CDCFileCfg) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create cdc directory: %w", err)
+	}
+
+	s := &fileSink{cfg: cfg, now: time.Now}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) Name() string {
+	return s.cfg.Name
+}
+
+// Deliver appends the events and syncs the file, so delivered events survive a crash.
+func (s *fileSink) Deliver(ctx context.Context, events []cdc.Event) error {
+	var buf []byte
+	for _, e := range events {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cdc event: %w", err)
+		}
+		buf = append(append(buf, b...), '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return errClosed
+	}
+	if s.needsRotation(len(buf)) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.f.Write(buf)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write cdc events: %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync cdc file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open cdc file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat cdc file: %w", err)
+	}
+
+	s.f = f
+	s.size = info.Size()
+	s.openedAt = s.now()
+	return nil
+}
+
+func (s *fileSink) needsRotation(next int) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.cfg.MaxSize > 0 && s.size+int64(next) > s.cfg.MaxSize {
+		return true
+	}
+	if s.cfg.MaxAge > 0 && s.now().Sub(s.openedAt) >= s.cfg.MaxAge {
+		return true
+	}
+	return false
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return fmt.Errorf("failed to close cdc file: %w", err)
+	}
+	s.f = nil
+
+	ext := filepath.Ext(s.cfg.Path)
+	base := strings.TrimSuffix(s.cfg.Path, ext)
+	rotated := fmt.Sprintf("%s-%s%s", base, s.now().UTC().Format(rotatedTimeLayout), ext)
+	if err := os.Rename(s.cfg.Path, rotated); err != nil {
+		// The current file is reopened, so the next delivery tries to rotate it again.
+		return errors.Join(fmt.Errorf("failed to rename cdc file: %w", err), s.open())
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	return s.prune(base + "-*" + ext)
+}
+
+// prune removes the oldest rotated files exceeding MaxBackups.
+func (s *fileSink) prune(pattern string) error {
+	if s.cfg.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("failed to list rotated cdc files: %w", err)
+	}
+	if len(backups) <= s.cfg.MaxBackups {
+		return nil
+	}
+
+	// Rotated names embed a sortable timestamp.
+	slices.Sort(backups)
+	for _, name := range backups[:len(backups)-s.cfg.MaxBackups] {
+		if err := os.Remove(name); err != nil {
+			return fmt.Errorf("failed to remove rotated cdc file: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
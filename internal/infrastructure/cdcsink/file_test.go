@@ -0,0 +1,114 @@
+package cdcsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readEvents(t *testing.T, path string) []cdc.Event {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+
+	var events []cdc.Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e cdc.Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &e))
+		events = append(events, e)
+	}
+	require.NoError(t, sc.Err())
+	return events
+}
+
+func event(index int64) cdc.Event {
+	return cdc.Event{Index: index, Time: time.Unix(0, 0).UTC(), Op: "put", Command: json.RawMessage(`{"key":"k","value":"v"}`)}
+}
+
+func TestFileSink_Deliver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cdc", "changes.jsonl")
+	s, err := NewFileSink(&cfg.CDCFileCfg{Name: "files", Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, "files", s.Name())
+
+	require.NoError(t, s.Deliver(context.Background(), []cdc.Event{event(1), event(2)}))
+	require.NoError(t, s.Close())
+	assert.ErrorIs(t, s.Deliver(context.Background(), []cdc.Event{event(3)}), errClosed)
+
+	// Reopened sink appends to the same file.
+	s, err = NewFileSink(&cfg.CDCFileCfg{Name: "files", Path: path})
+	require.NoError(t, err)
+	require.NoError(t, s.Deliver(context.Background(), []cdc.Event{event(3)}))
+	require.NoError(t, s.Close())
+
+	events := readEvents(t, path)
+	require.Len(t, events, 3)
+	assert.Equal(t, int64(3), events[2].Index)
+	assert.Equal(t, "put", events[2].Op)
+	assert.JSONEq(t, `{"key":"k","value":"v"}`, string(events[2].Command))
+}
+
+func TestFileSink_RotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "changes.jsonl")
+	s, err := NewFileSink(&cfg.CDCFileCfg{Path: path, MaxSize: 300, MaxBackups: 2})
+	require.NoError(t, err)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := 0
+	s.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Second)
+	}
+
+	for i := range 20 {
+		require.NoError(t, s.Deliver(context.Background(), []cdc.Event{event(int64(i))}))
+	}
+	require.NoError(t, s.Close())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "changes-*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(300))
+
+	events := readEvents(t, path)
+	require.NotEmpty(t, events)
+	assert.Equal(t, int64(19), events[len(events)-1].Index)
+}
+
+func TestFileSink_RotateByAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "changes.jsonl")
+	s, err := NewFileSink(&cfg.CDCFileCfg{Path: path, MaxAge: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.openedAt = now
+
+	require.NoError(t, s.Deliver(context.Background(), []cdc.Event{event(1)}))
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, s.Deliver(context.Background(), []cdc.Event{event(2)}))
+	require.NoError(t, s.Close())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "changes-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	assert.Equal(t, int64(1), readEvents(t, backups[0])[0].Index)
+	assert.Equal(t, int64(2), readEvents(t, path)[0].Index)
+}
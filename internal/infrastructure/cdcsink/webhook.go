@@ -0,0 +1,66 @@
+package cdcsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+)
+
+var _ cdc.Sink = (*webhookSink)(nil)
+
+// webhookSink posts events as a JSON array to an HTTP endpoint.
+// Events are delivered once the endpoint answers with a 2xx status.
+type webhookSink struct {
+	cfg    *cfg.CDCWebhookCfg
+	client *http.Client
+}
+
+func NewWebhookSink(cfg *cfg.CDCWebhookCfg) *webhookSink {
+	return &webhookSink{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (s *webhookSink) Name() string {
+	return s.cfg.Name
+}
+
+func (s *webhookSink) Deliver(ctx context.Context, events []cdc.Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cdc events: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Drained body lets the connection be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,45 @@
+package cdcsink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/cdc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink_Deliver(t *testing.T) {
+	status := http.StatusNoContent
+	var got []cdc.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		got = nil
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSink(&cfg.CDCWebhookCfg{
+		Name:    "analytics",
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+		Timeout: time.Second,
+	})
+	defer func() { require.NoError(t, s.Close()) }()
+	assert.Equal(t, "analytics", s.Name())
+
+	require.NoError(t, s.Deliver(context.Background(), []cdc.Event{event(1), event(2)}))
+	require.Len(t, got, 2)
+	assert.Equal(t, int64(2), got[1].Index)
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, s.Deliver(context.Background(), []cdc.Event{event(3)}))
+}
@@ -0,0 +1,150 @@
+package keyring
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/encryption"
+)
+
+// Ciphertext layout:
+//
+//	| version uint8 | key id length uint8 | key id | nonce | AES-GCM sealed data and tag |
+//
+// Version, key id length and key id are authenticated as additional data.
+const envelopeVersion = 1
+
+var (
+	ErrNoKeyFile     = errors.New("encryption: key_file must be set")
+	ErrNoKeys        = errors.New("encryption: key file has no keys")
+	ErrInvalidKeyRow = errors.New("encryption: invalid key file row")
+)
+
+var _ encryption.Cipher = (*Keyring)(nil)
+
+// Keyring encrypts with a single active key and decrypts with any key it knows about,
+// so keys can be rotated by adding a new one and making it active.
+type Keyring struct {
+	active string
+	aeads  map[string]cipher.AEAD
+}
+
+// NewKeyring loads keys from c.KeyFile. Every non-empty row not starting with '#'
+// is "<key id> <base64 encoded 16, 24 or 32 byte key>". The last key is active
+// unless c.ActiveKey is set.
+func NewKeyring(c *cfg.EncryptionCfg) (*Keyring, error) {
+	if c.KeyFile == "" {
+		return nil, ErrNoKeyFile
+	}
+	data, err := os.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return parseKeys(data, c.ActiveKey)
+}
+
+func parseKeys(data []byte, active string) (*Keyring, error) {
+	k := &Keyring{aeads: make(map[string]cipher.AEAD)}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for row := 1; sc.Scan(); row++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) > 255 {
+			return nil, fmt.Errorf("%w %d", ErrInvalidKeyRow, row)
+		}
+		id := fields[0]
+		if _, ok := k.aeads[id]; ok {
+			return nil, fmt.Errorf("%w %d: duplicate key id %q", ErrInvalidKeyRow, row, id)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidKeyRow, row, err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidKeyRow, row, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidKeyRow, row, err)
+		}
+
+		k.aeads[id] = aead
+		k.active = id
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	if len(k.aeads) == 0 {
+		return nil, ErrNoKeys
+	}
+	if active != "" {
+		if _, ok := k.aeads[active]; !ok {
+			return nil, fmt.Errorf("%w: active key %q", encryption.ErrUnknownKey, active)
+		}
+		k.active = active
+	}
+	return k, nil
+}
+
+// ActiveKey returns id of the key used for encryption.
+func (k *Keyring) ActiveKey() string {
+	return k.active
+}
+
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	aead := k.aeads[k.active]
+	hLen := 2 + len(k.active)
+
+	out := make([]byte, hLen+aead.NonceSize(), hLen+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	out[0] = envelopeVersion
+	out[1] = byte(len(k.active))
+	copy(out[2:], k.active)
+
+	nonce := out[hLen:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(out, nonce, plaintext, out[:hLen]), nil
+}
+
+func (k *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != envelopeVersion {
+		return nil, encryption.ErrMalformed
+	}
+	hLen := 2 + int(ciphertext[1])
+	if len(ciphertext) < hLen {
+		return nil, encryption.ErrMalformed
+	}
+
+	id := string(ciphertext[2:hLen])
+	aead, ok := k.aeads[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", encryption.ErrUnknownKey, id)
+	}
+	if len(ciphertext) < hLen+aead.NonceSize()+aead.Overhead() {
+		return nil, encryption.ErrMalformed
+	}
+
+	nonce := ciphertext[hLen : hLen+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, ciphertext[hLen+aead.NonceSize():], ciphertext[:hLen])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", encryption.ErrMalformed, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,117 @@
+package keyring
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/internal/core/ports/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T, size int) string {
+	t.Helper()
+	key := make([]byte, size)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	path := writeKeyFile(t, fmt.Sprintf("# test keys\nk1 %s\n\nk2 %s\n", newKey(t, 32), newKey(t, 16)))
+	k, err := NewKeyring(&cfg.EncryptionCfg{KeyFile: path})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", k.ActiveKey())
+
+	plaintext := []byte("customer data")
+	ct, err := k.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.NotContains(t, string(ct), string(plaintext))
+
+	got, err := k.Decrypt(ct)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, got)
+
+	// Same plaintext never produces the same ciphertext.
+	ct2, err := k.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.NotEqual(t, ct, ct2)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	k1, k2 := newKey(t, 32), newKey(t, 32)
+	old, err := NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, "k1 "+k1)})
+	require.NoError(t, err)
+	ct, err := old.Encrypt([]byte("value"))
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, fmt.Sprintf("k1 %s\nk2 %s", k1, k2))})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", rotated.ActiveKey())
+	got, err := rotated.Decrypt(ct)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+
+	newCt, err := rotated.Encrypt([]byte("value"))
+	require.NoError(t, err)
+	_, err = old.Decrypt(newCt)
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+
+	pinned, err := NewKeyring(&cfg.EncryptionCfg{
+		KeyFile:   writeKeyFile(t, fmt.Sprintf("k1 %s\nk2 %s", k1, k2)),
+		ActiveKey: "k1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", pinned.ActiveKey())
+}
+
+func TestKeyring_Tampered(t *testing.T) {
+	k, err := NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, "k1 "+newKey(t, 32))})
+	require.NoError(t, err)
+	ct, err := k.Encrypt([]byte("value"))
+	require.NoError(t, err)
+
+	tampered := append([]byte(nil), ct...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = k.Decrypt(tampered)
+	assert.ErrorIs(t, err, encryption.ErrMalformed)
+
+	_, err = k.Decrypt(ct[:5])
+	assert.ErrorIs(t, err, encryption.ErrMalformed)
+
+	_, err = k.Decrypt([]byte("plaintext"))
+	assert.ErrorIs(t, err, encryption.ErrMalformed)
+}
+
+func TestNewKeyring_Errors(t *testing.T) {
+	_, err := NewKeyring(&cfg.EncryptionCfg{})
+	assert.ErrorIs(t, err, ErrNoKeyFile)
+
+	_, err = NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, "# no keys\n")})
+	assert.ErrorIs(t, err, ErrNoKeys)
+
+	_, err = NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, "k1")})
+	assert.ErrorIs(t, err, ErrInvalidKeyRow)
+
+	_, err = NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, "k1 "+newKey(t, 10))})
+	assert.ErrorIs(t, err, ErrInvalidKeyRow)
+
+	key := newKey(t, 32)
+	_, err = NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, fmt.Sprintf("k1 %s\nk1 %s", key, key))})
+	assert.ErrorIs(t, err, ErrInvalidKeyRow)
+
+	_, err = NewKeyring(&cfg.EncryptionCfg{KeyFile: writeKeyFile(t, "k1 "+key), ActiveKey: "k2"})
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+}
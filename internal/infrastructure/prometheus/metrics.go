@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	p "github.com/prometheus/client_golang/prometheus"
+	"github.com/shrtyk/kv-store/internal/core/ports/store"
 	"google.golang.org/grpc/codes"
 )
 
@@ -28,6 +29,8 @@ type metrics struct {
 
 	kvOperationsCounter   *p.CounterVec
 	kvOperationsHistogram *p.HistogramVec
+
+	proposalBatchSize p.Histogram
 }
 
 func NewPrometheusMetrics() *metrics {
@@ -45,17 +48,24 @@ func NewPrometheusMetrics() *metrics {
 	requests := p.NewCounterVec(p.CounterOpts{
 		Name: "requests_total",
 		Help: "The total number of http/grpc requests",
-	}, []string{"transport", "code", "method", "endpoint"})
+	}, []string{"transport", "code", "method", "endpoint", "namespace"})
 
 	requestsHistogram := p.NewHistogramVec(p.HistogramOpts{
 		Name:    "requests_seconds",
 		Help:    "The http/grpc requests latency in seconds",
 		Buckets: p.DefBuckets,
-	}, []string{"transport", "code", "method", "endpoint"})
+	}, []string{"transport", "code", "method", "endpoint", "namespace"})
+
+	batchSize := p.NewHistogram(p.HistogramOpts{
+		Name:    "raft_proposal_batch_size",
+		Help:    "The number of commands committed as a single raft entry",
+		Buckets: p.ExponentialBuckets(1, 2, 10),
+	})
 
 	p.MustRegister(
 		opsCounter, opsHistogram,
 		requests, requestsHistogram,
+		batchSize,
 	)
 
 	return &metrics{
@@ -63,6 +73,7 @@ func NewPrometheusMetrics() *metrics {
 		kvOperationsHistogram: opsHistogram,
 		requests:              requests,
 		requestsHistogram:     requestsHistogram,
+		proposalBatchSize:     batchSize,
 	}
 }
 
@@ -103,30 +114,44 @@ func (m *metrics) GrpcGet(key string, duration float64) {
 }
 
 // HttpRequest increments the request counter and observes the latency
-func (m *metrics) HttpRequest(code int, method, path string, latency float64) {
-	labels := []string{string(httpApi), strconv.Itoa(code), method, path}
+func (m *metrics) HttpRequest(code int, method, path, namespace string, latency float64) {
+	labels := []string{string(httpApi), strconv.Itoa(code), method, path, namespaceLabel(namespace)}
 	m.requests.WithLabelValues(labels...).Inc()
 	m.requestsHistogram.WithLabelValues(labels...).Observe(latency)
 }
 
 // GrpcRequest increments the request counter and observes the latency
-func (m *metrics) GrpcRequest(code codes.Code, service, method string, latency float64) {
-	labels := []string{string(grpcApi), code.String(), method, service}
+func (m *metrics) GrpcRequest(code codes.Code, service, method, namespace string, latency float64) {
+	labels := []string{string(grpcApi), code.String(), method, service, namespaceLabel(namespace)}
 	m.requests.WithLabelValues(labels...).Inc()
 	m.requestsHistogram.WithLabelValues(labels...).Observe(latency)
 }
 
+// namespaceLabel names the default namespace, so requests to it are distinguishable from other labels.
+func namespaceLabel(namespace string) string {
+	if namespace == "" {
+		return store.DefaultNamespace
+	}
+	return namespace
+}
+
+// ProposalBatch observes the number of commands in a proposed raft entry
+func (m *metrics) ProposalBatch(size int) {
+	m.proposalBatchSize.Observe(float64(size))
+}
+
 type mock struct{}
 
 func NewMockMetrics() *mock {
 	return &mock{}
 }
 
-func (m *mock) HttpPut(key string, duration float64)                                 {}
-func (m *mock) HttpDelete(key string, duration float64)                              {}
-func (m *mock) HttpGet(key string, duration float64)                                 {}
-func (m *mock) HttpRequest(code int, method, path string, latency float64)           {}
-func (m *mock) GrpcPut(key string, duration float64)                                 {}
-func (m *mock) GrpcDelete(key string, duration float64)                              {}
-func (m *mock) GrpcGet(key string, duration float64)                                 {}
-func (m *mock) GrpcRequest(code codes.Code, service, method string, latency float64) {}
+func (m *mock) HttpPut(key string, duration float64)                                            {}
+func (m *mock) HttpDelete(key string, duration float64)                                         {}
+func (m *mock) HttpGet(key string, duration float64)                                            {}
+func (m *mock) HttpRequest(code int, method, path, namespace string, latency float64)           {}
+func (m *mock) GrpcPut(key string, duration float64)                                            {}
+func (m *mock) GrpcDelete(key string, duration float64)                                         {}
+func (m *mock) GrpcGet(key string, duration float64)                                            {}
+func (m *mock) GrpcRequest(code codes.Code, service, method, namespace string, latency float64) {}
+func (m *mock) ProposalBatch(size int)                                                          {}
@@ -113,9 +113,9 @@ func TestRequestMetrics(t *testing.T) {
 	m.requestsHistogram.Reset()
 
 	// HTTP Request
-	m.HttpRequest(http.StatusOK, http.MethodGet, "/test", 0.1)
+	m.HttpRequest(http.StatusOK, http.MethodGet, "/test", "", 0.1)
 	metric := &dto.Metric{}
-	httpLabels := []string{string(httpApi), strconv.Itoa(http.StatusOK), http.MethodGet, "/test"}
+	httpLabels := []string{string(httpApi), strconv.Itoa(http.StatusOK), http.MethodGet, "/test", "default"}
 	err := m.requests.WithLabelValues(httpLabels...).Write(metric)
 	require.NoError(t, err)
 	assert.Equal(t, float64(1), metric.Counter.GetValue())
@@ -127,8 +127,8 @@ func TestRequestMetrics(t *testing.T) {
 	assert.Equal(t, uint64(1), metric.Histogram.GetSampleCount())
 
 	// gRPC Request
-	m.GrpcRequest(codes.OK, "TestService", "TestMethod", 0.1)
-	grpcLabels := []string{string(grpcApi), codes.OK.String(), "TestMethod", "TestService"}
+	m.GrpcRequest(codes.OK, "TestService", "TestMethod", "team-a", 0.1)
+	grpcLabels := []string{string(grpcApi), codes.OK.String(), "TestMethod", "TestService", "team-a"}
 	err = m.requests.WithLabelValues(grpcLabels...).Write(metric)
 	require.NoError(t, err)
 	assert.Equal(t, float64(1), metric.Counter.GetValue())
@@ -150,6 +150,16 @@ func TestMockMetrics(t *testing.T) {
 	mock.GrpcPut("key", 0.1)
 	mock.GrpcDelete("key", 0.1)
 	mock.GrpcGet("key", 0.1)
-	mock.HttpRequest(200, "GET", "/path", 0.1)
-	mock.GrpcRequest(codes.OK, "service", "method", 0.1)
+	mock.HttpRequest(200, "GET", "/path", "", 0.1)
+	mock.GrpcRequest(codes.OK, "service", "method", "", 0.1)
+	mock.ProposalBatch(1)
+}
+
+func TestProposalBatch(t *testing.T) {
+	m.ProposalBatch(4)
+	metric := &dto.Metric{}
+	err := m.proposalBatchSize.(prometheus.Metric).Write(metric)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), metric.Histogram.GetSampleCount())
+	assert.Equal(t, float64(4), metric.Histogram.GetSampleSum())
 }
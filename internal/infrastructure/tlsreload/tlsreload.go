@@ -0,0 +1,221 @@
+package tlsreload
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	"github.com/shrtyk/kv-store/pkg/logger"
+)
+
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+var (
+	ErrNoCertificate     = errors.New("tls: cert_file and key_file must be set")
+	ErrNoClientCA        = errors.New("tls: client_ca_file must be set to verify client certificates")
+	ErrUnknownClientAuth = errors.New("tls: unknown client_auth mode")
+	ErrUnknownMinVersion = errors.New("tls: unknown min_version")
+)
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader keeps server certificate and client CA pool loaded from disk
+// and reloads them when files change, so handshakes always use the latest pair.
+type Reloader struct {
+	cfg        *cfg.TLSCfg
+	log        *slog.Logger
+	clientAuth tls.ClientAuthType
+	minVersion uint16
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stamps    map[string]fileStamp
+}
+
+func NewReloader(c *cfg.TLSCfg, l *slog.Logger) (*Reloader, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, ErrNoCertificate
+	}
+
+	clientAuth, err := parseClientAuth(c.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth != tls.NoClientCert && c.ClientCAFile == "" {
+		return nil, ErrNoClientCA
+	}
+
+	minVersion, err := parseMinVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reloader{
+		cfg:        c,
+		log:        l,
+		clientAuth: clientAuth,
+		minVersion: minVersion,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func parseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch mode {
+	case "", ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownClientAuth, mode)
+	}
+}
+
+func parseMinVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownMinVersion, v)
+	}
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+func (r *Reloader) currentStamps() (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp, 3)
+	for _, name := range r.files() {
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", name, err)
+		}
+		stamps[name] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps, nil
+}
+
+func (r *Reloader) load() error {
+	stamps, err := r.currentStamps()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client ca: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCAs = pool
+	r.stamps = stamps
+	return nil
+}
+
+// changed reports whether any of watched files was modified since the last load.
+func (r *Reloader) changed() bool {
+	stamps, err := r.currentStamps()
+	if err != nil {
+		r.log.Warn("failed to check tls files", logger.ErrorAttr(err))
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, s := range stamps {
+		if prev, ok := r.stamps[name]; !ok || prev != s {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reloader) reloadIfChanged() {
+	if !r.changed() {
+		return
+	}
+	if err := r.load(); err != nil {
+		// Keep serving with previously loaded certificates.
+		r.log.Error("failed to reload tls certificates", logger.ErrorAttr(err))
+		return
+	}
+	r.log.Info("tls certificates reloaded", slog.String("cert", r.cfg.CertFile))
+}
+
+// Start periodically checks certificate files until ctx is done.
+func (r *Reloader) Start(ctx context.Context) {
+	interval := r.cfg.ReloadInterval
+	if interval <= 0 {
+		return
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			r.reloadIfChanged()
+		}
+	}
+}
+
+// TLSConfig returns server side config resolving certificate and
+// client CAs on every handshake.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   r.minVersion,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   r.clientAuth,
+				ClientCAs:    r.clientCAs,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,269 @@
+package tlsreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shrtyk/kv-store/internal/cfg"
+	tu "github.com/shrtyk/kv-store/internal/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kv-store test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns PEM encoded certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, cn string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+type fixture struct {
+	ca  *testCA
+	cfg *cfg.TLSCfg
+}
+
+func newFixture(t *testing.T, clientAuth string) *fixture {
+	t.Helper()
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "server-1", 2, x509.ExtKeyUsageServerAuth)
+
+	c := &cfg.TLSCfg{
+		Enabled:      true,
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+		ClientAuth:   clientAuth,
+		MinVersion:   "1.2",
+	}
+	writeFile(t, c.CertFile, certPEM)
+	writeFile(t, c.KeyFile, keyPEM)
+	writeFile(t, c.ClientCAFile, ca.pem)
+
+	return &fixture{ca: ca, cfg: c}
+}
+
+func (f *fixture) client(t *testing.T, withCert bool) *http.Client {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AddCert(f.ca.cert)
+	tlsConf := &tls.Config{RootCAs: roots}
+	if withCert {
+		certPEM, keyPEM := f.ca.issue(t, "client", 10, x509.ExtKeyUsageClientAuth)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, err)
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConf}}
+}
+
+func startServer(t *testing.T, r *Reloader) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if len(req.TLS.PeerCertificates) > 0 {
+			_, _ = w.Write([]byte(req.TLS.PeerCertificates[0].Subject.CommonName))
+		}
+	}))
+	srv.TLS = r.TLSConfig()
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewReloader_Validation(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+
+	_, err := NewReloader(&cfg.TLSCfg{}, l)
+	assert.ErrorIs(t, err, ErrNoCertificate)
+
+	f := newFixture(t, ClientAuthRequire)
+	f.cfg.ClientCAFile = ""
+	_, err = NewReloader(f.cfg, l)
+	assert.ErrorIs(t, err, ErrNoClientCA)
+
+	f = newFixture(t, "sometimes")
+	_, err = NewReloader(f.cfg, l)
+	assert.ErrorIs(t, err, ErrUnknownClientAuth)
+
+	f = newFixture(t, ClientAuthNone)
+	f.cfg.MinVersion = "1.0"
+	_, err = NewReloader(f.cfg, l)
+	assert.ErrorIs(t, err, ErrUnknownMinVersion)
+}
+
+func TestReloader_TLS(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	f := newFixture(t, ClientAuthNone)
+	r, err := NewReloader(f.cfg, l)
+	require.NoError(t, err)
+	srv := startServer(t, r)
+
+	resp, err := f.client(t, false).Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "server-1", resp.TLS.PeerCertificates[0].Subject.CommonName)
+}
+
+func TestReloader_MutualTLS(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+
+	t.Run("require", func(t *testing.T) {
+		f := newFixture(t, ClientAuthRequire)
+		r, err := NewReloader(f.cfg, l)
+		require.NoError(t, err)
+		srv := startServer(t, r)
+
+		_, err = f.client(t, false).Get(srv.URL)
+		assert.Error(t, err)
+
+		resp, err := f.client(t, true).Get(srv.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		f := newFixture(t, ClientAuthOptional)
+		r, err := NewReloader(f.cfg, l)
+		require.NoError(t, err)
+		srv := startServer(t, r)
+
+		resp, err := f.client(t, false).Get(srv.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		resp, err = f.client(t, true).Get(srv.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("untrusted client certificate", func(t *testing.T) {
+		f := newFixture(t, ClientAuthRequire)
+		r, err := NewReloader(f.cfg, l)
+		require.NoError(t, err)
+		srv := startServer(t, r)
+
+		other := &fixture{ca: newTestCA(t)}
+		client := other.client(t, true)
+		roots := x509.NewCertPool()
+		roots.AddCert(f.ca.cert)
+		client.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+
+		_, err = client.Get(srv.URL)
+		assert.Error(t, err)
+	})
+}
+
+func TestReloader_HotReload(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	f := newFixture(t, ClientAuthNone)
+	r, err := NewReloader(f.cfg, l)
+	require.NoError(t, err)
+	srv := startServer(t, r)
+
+	assert.False(t, r.changed())
+
+	certPEM, keyPEM := f.ca.issue(t, "server-2", 3, x509.ExtKeyUsageServerAuth)
+	writeFile(t, f.cfg.CertFile, certPEM)
+	writeFile(t, f.cfg.KeyFile, keyPEM)
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(f.cfg.CertFile, future, future))
+	require.NoError(t, os.Chtimes(f.cfg.KeyFile, future, future))
+
+	assert.True(t, r.changed())
+	r.reloadIfChanged()
+	assert.False(t, r.changed())
+
+	resp, err := f.client(t, false).Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "server-2", resp.TLS.PeerCertificates[0].Subject.CommonName)
+}
+
+func TestReloader_KeepsOldCertOnBrokenFiles(t *testing.T) {
+	l, _ := tu.NewMockLogger()
+	f := newFixture(t, ClientAuthNone)
+	r, err := NewReloader(f.cfg, l)
+	require.NoError(t, err)
+	srv := startServer(t, r)
+
+	writeFile(t, f.cfg.CertFile, []byte("garbage"))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(f.cfg.CertFile, future, future))
+	r.reloadIfChanged()
+
+	resp, err := f.client(t, false).Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "server-1", resp.TLS.PeerCertificates[0].Subject.CommonName)
+}
@@ -21,6 +21,158 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SetCondition int32
+
+const (
+	SetCondition_SET_CONDITION_NONE SetCondition = 0
+	// Set only if the key doesn't exist.
+	SetCondition_SET_CONDITION_NOT_EXISTS SetCondition = 1
+	// Set only if the key exists.
+	SetCondition_SET_CONDITION_EXISTS SetCondition = 2
+)
+
+// Enum value maps for SetCondition.
+var (
+	SetCondition_name = map[int32]string{
+		0: "SET_CONDITION_NONE",
+		1: "SET_CONDITION_NOT_EXISTS",
+		2: "SET_CONDITION_EXISTS",
+	}
+	SetCondition_value = map[string]int32{
+		"SET_CONDITION_NONE":       0,
+		"SET_CONDITION_NOT_EXISTS": 1,
+		"SET_CONDITION_EXISTS":     2,
+	}
+)
+
+func (x SetCondition) Enum() *SetCondition {
+	p := new(SetCondition)
+	*p = x
+	return p
+}
+
+func (x SetCondition) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SetCondition) Descriptor() protoreflect.EnumDescriptor {
+	return file_commands_proto_enumTypes[0].Descriptor()
+}
+
+func (SetCondition) Type() protoreflect.EnumType {
+	return &file_commands_proto_enumTypes[0]
+}
+
+func (x SetCondition) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SetCondition.Descriptor instead.
+func (SetCondition) EnumDescriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{0}
+}
+
+type PatchType int32
+
+const (
+	// RFC 6902 JSON Patch.
+	PatchType_PATCH_TYPE_JSON_PATCH PatchType = 0
+	// RFC 7386 JSON Merge Patch.
+	PatchType_PATCH_TYPE_MERGE_PATCH PatchType = 1
+)
+
+// Enum value maps for PatchType.
+var (
+	PatchType_name = map[int32]string{
+		0: "PATCH_TYPE_JSON_PATCH",
+		1: "PATCH_TYPE_MERGE_PATCH",
+	}
+	PatchType_value = map[string]int32{
+		"PATCH_TYPE_JSON_PATCH":  0,
+		"PATCH_TYPE_MERGE_PATCH": 1,
+	}
+)
+
+func (x PatchType) Enum() *PatchType {
+	p := new(PatchType)
+	*p = x
+	return p
+}
+
+func (x PatchType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PatchType) Descriptor() protoreflect.EnumDescriptor {
+	return file_commands_proto_enumTypes[1].Descriptor()
+}
+
+func (PatchType) Type() protoreflect.EnumType {
+	return &file_commands_proto_enumTypes[1]
+}
+
+func (x PatchType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PatchType.Descriptor instead.
+func (PatchType) EnumDescriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{1}
+}
+
+// ValueKind mirrors kinds of values in the store.
+type ValueKind int32
+
+const (
+	ValueKind_VALUE_KIND_STRING ValueKind = 0
+	ValueKind_VALUE_KIND_HASH   ValueKind = 1
+	ValueKind_VALUE_KIND_LIST   ValueKind = 2
+	ValueKind_VALUE_KIND_ZSET   ValueKind = 3
+)
+
+// Enum value maps for ValueKind.
+var (
+	ValueKind_name = map[int32]string{
+		0: "VALUE_KIND_STRING",
+		1: "VALUE_KIND_HASH",
+		2: "VALUE_KIND_LIST",
+		3: "VALUE_KIND_ZSET",
+	}
+	ValueKind_value = map[string]int32{
+		"VALUE_KIND_STRING": 0,
+		"VALUE_KIND_HASH":   1,
+		"VALUE_KIND_LIST":   2,
+		"VALUE_KIND_ZSET":   3,
+	}
+)
+
+func (x ValueKind) Enum() *ValueKind {
+	p := new(ValueKind)
+	*p = x
+	return p
+}
+
+func (x ValueKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ValueKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_commands_proto_enumTypes[2].Descriptor()
+}
+
+func (ValueKind) Type() protoreflect.EnumType {
+	return &file_commands_proto_enumTypes[2]
+}
+
+func (x ValueKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ValueKind.Descriptor instead.
+func (ValueKind) EnumDescriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{2}
+}
+
 type PutCommand struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -117,31 +269,42 @@ func (x *DeleteCommand) GetKey() string {
 	return ""
 }
 
-type Command struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Command:
-	//
-	//	*Command_Put
-	//	*Command_Delete
-	Command       isCommand_Command `protobuf_oneof:"command"`
+// SetCommand is a conditional put with an optional expiry.
+// Result data is "1" if the value was stored and "0" if the condition didn't hold.
+type SetCommand struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Key       string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value     string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Condition SetCondition           `protobuf:"varint,3,opt,name=condition,proto3,enum=fsm.v1.SetCondition" json:"condition,omitempty"`
+	// Expiry deadline in unix milliseconds, 0 means the key doesn't expire.
+	ExpiresAt int64 `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Time of the proposal in unix milliseconds, unset by handlers since Command.now is stamped on every proposal.
+	// Like the now of other commands, it's kept for log entries of older nodes and applied no earlier than
+	// the cluster time.
+	Now int64 `protobuf:"varint,5,opt,name=now,proto3" json:"now,omitempty"`
+	// Opaque client data stored with the value.
+	Flags uint32 `protobuf:"varint,6,opt,name=flags,proto3" json:"flags,omitempty"`
+	// If set, the key must exist with this version (memcached cas).
+	// Result error is ErrNoSuchKey or ErrVersionMismatch if it doesn't.
+	Version       uint64 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Command) Reset() {
-	*x = Command{}
+func (x *SetCommand) Reset() {
+	*x = SetCommand{}
 	mi := &file_commands_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Command) String() string {
+func (x *SetCommand) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Command) ProtoMessage() {}
+func (*SetCommand) ProtoMessage() {}
 
-func (x *Command) ProtoReflect() protoreflect.Message {
+func (x *SetCommand) ProtoReflect() protoreflect.Message {
 	mi := &file_commands_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -153,73 +316,85 @@ func (x *Command) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Command.ProtoReflect.Descriptor instead.
-func (*Command) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetCommand.ProtoReflect.Descriptor instead.
+func (*SetCommand) Descriptor() ([]byte, []int) {
 	return file_commands_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Command) GetCommand() isCommand_Command {
+func (x *SetCommand) GetKey() string {
 	if x != nil {
-		return x.Command
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-func (x *Command) GetPut() *PutCommand {
+func (x *SetCommand) GetValue() string {
 	if x != nil {
-		if x, ok := x.Command.(*Command_Put); ok {
-			return x.Put
-		}
+		return x.Value
 	}
-	return nil
+	return ""
 }
 
-func (x *Command) GetDelete() *DeleteCommand {
+func (x *SetCommand) GetCondition() SetCondition {
 	if x != nil {
-		if x, ok := x.Command.(*Command_Delete); ok {
-			return x.Delete
-		}
+		return x.Condition
 	}
-	return nil
+	return SetCondition_SET_CONDITION_NONE
 }
 
-type isCommand_Command interface {
-	isCommand_Command()
+func (x *SetCommand) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
 }
 
-type Command_Put struct {
-	Put *PutCommand `protobuf:"bytes,1,opt,name=put,proto3,oneof"`
+func (x *SetCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
 }
 
-type Command_Delete struct {
-	Delete *DeleteCommand `protobuf:"bytes,2,opt,name=delete,proto3,oneof"`
+func (x *SetCommand) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
 }
 
-func (*Command_Put) isCommand_Command() {}
-
-func (*Command_Delete) isCommand_Command() {}
+func (x *SetCommand) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
 
-type SnapshotState struct {
+// IncrCommand adds delta to an integer value. Missing key is treated as 0.
+// Result data is the new value in decimal.
+type IncrCommand struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Items         map[string]string      `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Delta         int64                  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SnapshotState) Reset() {
-	*x = SnapshotState{}
+func (x *IncrCommand) Reset() {
+	*x = IncrCommand{}
 	mi := &file_commands_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SnapshotState) String() string {
+func (x *IncrCommand) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SnapshotState) ProtoMessage() {}
+func (*IncrCommand) ProtoMessage() {}
 
-func (x *SnapshotState) ProtoReflect() protoreflect.Message {
+func (x *IncrCommand) ProtoReflect() protoreflect.Message {
 	mi := &file_commands_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -231,39 +406,3530 @@ func (x *SnapshotState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SnapshotState.ProtoReflect.Descriptor instead.
-func (*SnapshotState) Descriptor() ([]byte, []int) {
+// Deprecated: Use IncrCommand.ProtoReflect.Descriptor instead.
+func (*IncrCommand) Descriptor() ([]byte, []int) {
 	return file_commands_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *SnapshotState) GetItems() map[string]string {
+func (x *IncrCommand) GetKey() string {
 	if x != nil {
-		return x.Items
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-var File_commands_proto protoreflect.FileDescriptor
+func (x *IncrCommand) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
 
-const file_commands_proto_rawDesc = "" +
-	"\n" +
-	"\x0ecommands.proto\x12\x06fsm.v1\"4\n" +
-	"\n" +
-	"PutCommand\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"!\n" +
-	"\rDeleteCommand\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"m\n" +
-	"\aCommand\x12&\n" +
-	"\x03put\x18\x01 \x01(\v2\x12.fsm.v1.PutCommandH\x00R\x03put\x12/\n" +
-	"\x06delete\x18\x02 \x01(\v2\x15.fsm.v1.DeleteCommandH\x00R\x06deleteB\t\n" +
-	"\acommand\"\x81\x01\n" +
-	"\rSnapshotState\x126\n" +
-	"\x05items\x18\x01 \x03(\v2 .fsm.v1.SnapshotState.ItemsEntryR\x05items\x1a8\n" +
-	"\n" +
-	"ItemsEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B1Z/github.com/shrtyk/kv-store/proto/fsm/gen;fsm_v1b\x06proto3"
+func (x *IncrCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// CounterCommand changes an unsigned 64-bit value the way memcached incr and decr do:
+// missing key isn't created, incr wraps around and decr stops at zero.
+// Result data is the new value in decimal.
+type CounterCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Delta         uint64                 `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	Decr          bool                   `protobuf:"varint,3,opt,name=decr,proto3" json:"decr,omitempty"`
+	Now           int64                  `protobuf:"varint,4,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CounterCommand) Reset() {
+	*x = CounterCommand{}
+	mi := &file_commands_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CounterCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CounterCommand) ProtoMessage() {}
+
+func (x *CounterCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CounterCommand.ProtoReflect.Descriptor instead.
+func (*CounterCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CounterCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CounterCommand) GetDelta() uint64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *CounterCommand) GetDecr() bool {
+	if x != nil {
+		return x.Decr
+	}
+	return false
+}
+
+func (x *CounterCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// FlushCommand makes every key expire at expires_at. Deadline at or before now deletes all keys.
+type FlushCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExpiresAt     int64                  `protobuf:"varint,1,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Now           int64                  `protobuf:"varint,2,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushCommand) Reset() {
+	*x = FlushCommand{}
+	mi := &file_commands_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushCommand) ProtoMessage() {}
+
+func (x *FlushCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushCommand.ProtoReflect.Descriptor instead.
+func (*FlushCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FlushCommand) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *FlushCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// ExpireCommand sets expiry deadline of an existing key, 0 removes the expiry.
+// Result data is "1" if the key exists and "0" otherwise.
+type ExpireCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireCommand) Reset() {
+	*x = ExpireCommand{}
+	mi := &file_commands_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireCommand) ProtoMessage() {}
+
+func (x *ExpireCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireCommand.ProtoReflect.Descriptor instead.
+func (*ExpireCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ExpireCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ExpireCommand) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ExpireCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// ReapCommand deletes the keys which are expired at now and revokes the leases which are expired at now.
+type ReapCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Now           int64                  `protobuf:"varint,2,opt,name=now,proto3" json:"now,omitempty"`
+	Leases        []int64                `protobuf:"varint,3,rep,packed,name=leases,proto3" json:"leases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReapCommand) Reset() {
+	*x = ReapCommand{}
+	mi := &file_commands_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReapCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReapCommand) ProtoMessage() {}
+
+func (x *ReapCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReapCommand.ProtoReflect.Descriptor instead.
+func (*ReapCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReapCommand) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ReapCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+func (x *ReapCommand) GetLeases() []int64 {
+	if x != nil {
+		return x.Leases
+	}
+	return nil
+}
+
+// MSetCommand stores several keys without expiry in a single command.
+type MSetCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Puts          []*PutCommand          `protobuf:"bytes,1,rep,name=puts,proto3" json:"puts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MSetCommand) Reset() {
+	*x = MSetCommand{}
+	mi := &file_commands_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MSetCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSetCommand) ProtoMessage() {}
+
+func (x *MSetCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSetCommand.ProtoReflect.Descriptor instead.
+func (*MSetCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MSetCommand) GetPuts() []*PutCommand {
+	if x != nil {
+		return x.Puts
+	}
+	return nil
+}
+
+// DelCommand deletes several keys. Result data is a number of keys alive at now which were deleted.
+type DelCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Now           int64                  `protobuf:"varint,2,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelCommand) Reset() {
+	*x = DelCommand{}
+	mi := &file_commands_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelCommand) ProtoMessage() {}
+
+func (x *DelCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelCommand.ProtoReflect.Descriptor instead.
+func (*DelCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DelCommand) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *DelCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// HSetCommand sets fields of a hash. Result data is the number of added fields.
+type HSetCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HSetCommand) Reset() {
+	*x = HSetCommand{}
+	mi := &file_commands_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HSetCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HSetCommand) ProtoMessage() {}
+
+func (x *HSetCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HSetCommand.ProtoReflect.Descriptor instead.
+func (*HSetCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HSetCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HSetCommand) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *HSetCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// HDelCommand deletes fields of a hash. Result data is the number of deleted fields.
+type HDelCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        []string               `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HDelCommand) Reset() {
+	*x = HDelCommand{}
+	mi := &file_commands_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HDelCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HDelCommand) ProtoMessage() {}
+
+func (x *HDelCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HDelCommand.ProtoReflect.Descriptor instead.
+func (*HDelCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HDelCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HDelCommand) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *HDelCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// HIncrByCommand adds delta to the integer value of a hash field. Result data is the new value.
+type HIncrByCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Delta         int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Now           int64                  `protobuf:"varint,4,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HIncrByCommand) Reset() {
+	*x = HIncrByCommand{}
+	mi := &file_commands_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HIncrByCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HIncrByCommand) ProtoMessage() {}
+
+func (x *HIncrByCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HIncrByCommand.ProtoReflect.Descriptor instead.
+func (*HIncrByCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *HIncrByCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HIncrByCommand) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *HIncrByCommand) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *HIncrByCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// PushCommand adds values to the head (left) or the tail of a list one by one.
+// Result data is the new length of the list.
+type PushCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	Left          bool                   `protobuf:"varint,3,opt,name=left,proto3" json:"left,omitempty"`
+	Now           int64                  `protobuf:"varint,4,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushCommand) Reset() {
+	*x = PushCommand{}
+	mi := &file_commands_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushCommand) ProtoMessage() {}
+
+func (x *PushCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushCommand.ProtoReflect.Descriptor instead.
+func (*PushCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PushCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PushCommand) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *PushCommand) GetLeft() bool {
+	if x != nil {
+		return x.Left
+	}
+	return false
+}
+
+func (x *PushCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// PopCommand removes up to count values from the head (left) or the tail of a list.
+// Result data is a marshaled PopResult.
+type PopCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Left          bool                   `protobuf:"varint,3,opt,name=left,proto3" json:"left,omitempty"`
+	Now           int64                  `protobuf:"varint,4,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PopCommand) Reset() {
+	*x = PopCommand{}
+	mi := &file_commands_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PopCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PopCommand) ProtoMessage() {}
+
+func (x *PopCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PopCommand.ProtoReflect.Descriptor instead.
+func (*PopCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PopCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PopCommand) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PopCommand) GetLeft() bool {
+	if x != nil {
+		return x.Left
+	}
+	return false
+}
+
+func (x *PopCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+type PopResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PopResult) Reset() {
+	*x = PopResult{}
+	mi := &file_commands_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PopResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PopResult) ProtoMessage() {}
+
+func (x *PopResult) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PopResult.ProtoReflect.Descriptor instead.
+func (*PopResult) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PopResult) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type ScoredMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        string                 `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoredMember) Reset() {
+	*x = ScoredMember{}
+	mi := &file_commands_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoredMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoredMember) ProtoMessage() {}
+
+func (x *ScoredMember) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoredMember.ProtoReflect.Descriptor instead.
+func (*ScoredMember) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ScoredMember) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ScoredMember) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// ZAddCommand sets scores of members of a sorted set.
+// Result data is the number of added members.
+type ZAddCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []*ScoredMember        `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZAddCommand) Reset() {
+	*x = ZAddCommand{}
+	mi := &file_commands_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZAddCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZAddCommand) ProtoMessage() {}
+
+func (x *ZAddCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZAddCommand.ProtoReflect.Descriptor instead.
+func (*ZAddCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ZAddCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZAddCommand) GetMembers() []*ScoredMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ZAddCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// ZRemCommand removes members of a sorted set.
+// Result data is the number of removed members.
+type ZRemCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRemCommand) Reset() {
+	*x = ZRemCommand{}
+	mi := &file_commands_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRemCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRemCommand) ProtoMessage() {}
+
+func (x *ZRemCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRemCommand.ProtoReflect.Descriptor instead.
+func (*ZRemCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ZRemCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZRemCommand) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ZRemCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// ZIncrByCommand adds delta to the score of a member of a sorted set.
+// Result data is the new score.
+type ZIncrByCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Member        string                 `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
+	Delta         float64                `protobuf:"fixed64,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Now           int64                  `protobuf:"varint,4,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZIncrByCommand) Reset() {
+	*x = ZIncrByCommand{}
+	mi := &file_commands_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZIncrByCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZIncrByCommand) ProtoMessage() {}
+
+func (x *ZIncrByCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZIncrByCommand.ProtoReflect.Descriptor instead.
+func (*ZIncrByCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ZIncrByCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZIncrByCommand) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ZIncrByCommand) GetDelta() float64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *ZIncrByCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// ZPopMinCommand removes up to count members with the lowest scores from a sorted set.
+// Result data is a marshaled ZPopResult.
+type ZPopMinCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZPopMinCommand) Reset() {
+	*x = ZPopMinCommand{}
+	mi := &file_commands_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZPopMinCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZPopMinCommand) ProtoMessage() {}
+
+func (x *ZPopMinCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZPopMinCommand.ProtoReflect.Descriptor instead.
+func (*ZPopMinCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ZPopMinCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZPopMinCommand) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ZPopMinCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+type ZPopResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*ScoredMember        `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZPopResult) Reset() {
+	*x = ZPopResult{}
+	mi := &file_commands_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZPopResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZPopResult) ProtoMessage() {}
+
+func (x *ZPopResult) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZPopResult.ProtoReflect.Descriptor instead.
+func (*ZPopResult) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ZPopResult) GetMembers() []*ScoredMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+// JsonPatchCommand applies a patch to the JSON document stored at key, creating it if missing.
+// Result data is the patched document.
+type JsonPatchCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Patch         []byte                 `protobuf:"bytes,2,opt,name=patch,proto3" json:"patch,omitempty"`
+	Type          PatchType              `protobuf:"varint,3,opt,name=type,proto3,enum=fsm.v1.PatchType" json:"type,omitempty"`
+	Now           int64                  `protobuf:"varint,4,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonPatchCommand) Reset() {
+	*x = JsonPatchCommand{}
+	mi := &file_commands_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonPatchCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonPatchCommand) ProtoMessage() {}
+
+func (x *JsonPatchCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonPatchCommand.ProtoReflect.Descriptor instead.
+func (*JsonPatchCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *JsonPatchCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *JsonPatchCommand) GetPatch() []byte {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+func (x *JsonPatchCommand) GetType() PatchType {
+	if x != nil {
+		return x.Type
+	}
+	return PatchType_PATCH_TYPE_JSON_PATCH
+}
+
+func (x *JsonPatchCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// Lease is a replicated lease. Keys attached to it are deleted when it's revoked or expires.
+type Lease struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Id given when the lease was granted, the log index of the command unless it's taken by an earlier command.
+	Id    int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TtlMs int64 `protobuf:"varint,2,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	// Deadline in unix milliseconds, the time the leader proposed the last keepalive plus ttl.
+	ExpiresAt     int64    `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Keys          []string `protobuf:"bytes,4,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lease) Reset() {
+	*x = Lease{}
+	mi := &file_commands_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lease) ProtoMessage() {}
+
+func (x *Lease) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lease.ProtoReflect.Descriptor instead.
+func (*Lease) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Lease) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Lease) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *Lease) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *Lease) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// LeaseGrantCommand grants a lease expiring at now plus ttl. Result data is the lease id,
+// the log index of the command or the next unused id if commands batched in an entry grant several.
+type LeaseGrantCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TtlMs         int64                  `protobuf:"varint,1,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	Now           int64                  `protobuf:"varint,2,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseGrantCommand) Reset() {
+	*x = LeaseGrantCommand{}
+	mi := &file_commands_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseGrantCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseGrantCommand) ProtoMessage() {}
+
+func (x *LeaseGrantCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseGrantCommand.ProtoReflect.Descriptor instead.
+func (*LeaseGrantCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LeaseGrantCommand) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *LeaseGrantCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// LeaseKeepAliveCommand moves the deadline of a lease to now plus its ttl.
+// Result data is the new deadline.
+type LeaseKeepAliveCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Now           int64                  `protobuf:"varint,2,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseKeepAliveCommand) Reset() {
+	*x = LeaseKeepAliveCommand{}
+	mi := &file_commands_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseKeepAliveCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseKeepAliveCommand) ProtoMessage() {}
+
+func (x *LeaseKeepAliveCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseKeepAliveCommand.ProtoReflect.Descriptor instead.
+func (*LeaseKeepAliveCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LeaseKeepAliveCommand) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LeaseKeepAliveCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// LeaseRevokeCommand deletes a lease and the keys attached to it.
+// Result data is the number of deleted keys.
+type LeaseRevokeCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Now           int64                  `protobuf:"varint,2,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseRevokeCommand) Reset() {
+	*x = LeaseRevokeCommand{}
+	mi := &file_commands_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseRevokeCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseRevokeCommand) ProtoMessage() {}
+
+func (x *LeaseRevokeCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseRevokeCommand.ProtoReflect.Descriptor instead.
+func (*LeaseRevokeCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *LeaseRevokeCommand) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LeaseRevokeCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// LeaseAttachCommand attaches existing keys to a lease, detaching them from their previous leases.
+// Result data is the number of attached keys.
+type LeaseAttachCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Keys          []string               `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseAttachCommand) Reset() {
+	*x = LeaseAttachCommand{}
+	mi := &file_commands_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseAttachCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseAttachCommand) ProtoMessage() {}
+
+func (x *LeaseAttachCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseAttachCommand.ProtoReflect.Descriptor instead.
+func (*LeaseAttachCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LeaseAttachCommand) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LeaseAttachCommand) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *LeaseAttachCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// LockCommand acquires the lock at key for a lease. The lock is a string key attached to the lease
+// holding the fencing token, an id given like lease ids when the lock is acquired. Locks held by expired
+// leases are taken over. Result data is the fencing token.
+type LockCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Lease         int64                  `protobuf:"varint,2,opt,name=lease,proto3" json:"lease,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockCommand) Reset() {
+	*x = LockCommand{}
+	mi := &file_commands_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockCommand) ProtoMessage() {}
+
+func (x *LockCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockCommand.ProtoReflect.Descriptor instead.
+func (*LockCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LockCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LockCommand) GetLease() int64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+func (x *LockCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+// UnlockCommand releases the lock at key held by a lease.
+type UnlockCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Lease         int64                  `protobuf:"varint,2,opt,name=lease,proto3" json:"lease,omitempty"`
+	Now           int64                  `protobuf:"varint,3,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockCommand) Reset() {
+	*x = UnlockCommand{}
+	mi := &file_commands_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockCommand) ProtoMessage() {}
+
+func (x *UnlockCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockCommand.ProtoReflect.Descriptor instead.
+func (*UnlockCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UnlockCommand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *UnlockCommand) GetLease() int64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+func (x *UnlockCommand) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+type Member struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HttpAddr      string                 `protobuf:"bytes,2,opt,name=http_addr,json=httpAddr,proto3" json:"http_addr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Member) Reset() {
+	*x = Member{}
+	mi := &file_commands_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Member) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Member) ProtoMessage() {}
+
+func (x *Member) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Member.ProtoReflect.Descriptor instead.
+func (*Member) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Member) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Member) GetHttpAddr() string {
+	if x != nil {
+		return x.HttpAddr
+	}
+	return ""
+}
+
+// MembershipCommand replaces the replicated cluster membership.
+// Admin changes are proposed as MemberSetCommand and MemberRemoveCommand instead,
+// so it's kept for log entries of older nodes and for snapshots.
+type MembershipCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*Member              `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MembershipCommand) Reset() {
+	*x = MembershipCommand{}
+	mi := &file_commands_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MembershipCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MembershipCommand) ProtoMessage() {}
+
+func (x *MembershipCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MembershipCommand.ProtoReflect.Descriptor instead.
+func (*MembershipCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MembershipCommand) GetMembers() []*Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+// MemberSetCommand sets the address of a single member in the replicated membership.
+type MemberSetCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        *Member                `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemberSetCommand) Reset() {
+	*x = MemberSetCommand{}
+	mi := &file_commands_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemberSetCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberSetCommand) ProtoMessage() {}
+
+func (x *MemberSetCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberSetCommand.ProtoReflect.Descriptor instead.
+func (*MemberSetCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *MemberSetCommand) GetMember() *Member {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+// MemberRemoveCommand removes a single member from the replicated membership.
+type MemberRemoveCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemberRemoveCommand) Reset() {
+	*x = MemberRemoveCommand{}
+	mi := &file_commands_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemberRemoveCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberRemoveCommand) ProtoMessage() {}
+
+func (x *MemberRemoveCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberRemoveCommand.ProtoReflect.Descriptor instead.
+func (*MemberRemoveCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *MemberRemoveCommand) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Namespace is an isolated keyspace with its own limits. Zero limits fall back to the store config.
+type Namespace struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MaxKeySize    int32                  `protobuf:"varint,2,opt,name=max_key_size,json=maxKeySize,proto3" json:"max_key_size,omitempty"`
+	MaxValSize    int32                  `protobuf:"varint,3,opt,name=max_val_size,json=maxValSize,proto3" json:"max_val_size,omitempty"`
+	MaxKeys       int32                  `protobuf:"varint,4,opt,name=max_keys,json=maxKeys,proto3" json:"max_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Namespace) Reset() {
+	*x = Namespace{}
+	mi := &file_commands_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Namespace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Namespace) ProtoMessage() {}
+
+func (x *Namespace) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Namespace.ProtoReflect.Descriptor instead.
+func (*Namespace) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Namespace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Namespace) GetMaxKeySize() int32 {
+	if x != nil {
+		return x.MaxKeySize
+	}
+	return 0
+}
+
+func (x *Namespace) GetMaxValSize() int32 {
+	if x != nil {
+		return x.MaxValSize
+	}
+	return 0
+}
+
+func (x *Namespace) GetMaxKeys() int32 {
+	if x != nil {
+		return x.MaxKeys
+	}
+	return 0
+}
+
+// NamespaceCreateCommand creates an empty namespace.
+type NamespaceCreateCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     *Namespace             `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NamespaceCreateCommand) Reset() {
+	*x = NamespaceCreateCommand{}
+	mi := &file_commands_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NamespaceCreateCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NamespaceCreateCommand) ProtoMessage() {}
+
+func (x *NamespaceCreateCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NamespaceCreateCommand.ProtoReflect.Descriptor instead.
+func (*NamespaceCreateCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *NamespaceCreateCommand) GetNamespace() *Namespace {
+	if x != nil {
+		return x.Namespace
+	}
+	return nil
+}
+
+// NamespaceDeleteCommand deletes a namespace with all its keys.
+type NamespaceDeleteCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NamespaceDeleteCommand) Reset() {
+	*x = NamespaceDeleteCommand{}
+	mi := &file_commands_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NamespaceDeleteCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NamespaceDeleteCommand) ProtoMessage() {}
+
+func (x *NamespaceDeleteCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NamespaceDeleteCommand.ProtoReflect.Descriptor instead.
+func (*NamespaceDeleteCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *NamespaceDeleteCommand) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// BatchCommand holds several marshaled commands committed as a single log entry.
+type BatchCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commands      [][]byte               `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCommand) Reset() {
+	*x = BatchCommand{}
+	mi := &file_commands_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCommand) ProtoMessage() {}
+
+func (x *BatchCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCommand.ProtoReflect.Descriptor instead.
+func (*BatchCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *BatchCommand) GetCommands() [][]byte {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+type Command struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Command:
+	//
+	//	*Command_Put
+	//	*Command_Delete
+	//	*Command_Batch
+	//	*Command_Sealed
+	//	*Command_Membership
+	//	*Command_Set
+	//	*Command_Incr
+	//	*Command_Expire
+	//	*Command_Reap
+	//	*Command_Mset
+	//	*Command_Del
+	//	*Command_Counter
+	//	*Command_Flush
+	//	*Command_Hset
+	//	*Command_Hdel
+	//	*Command_Hincrby
+	//	*Command_Push
+	//	*Command_Pop
+	//	*Command_Zadd
+	//	*Command_Zrem
+	//	*Command_Zincrby
+	//	*Command_Zpopmin
+	//	*Command_JsonPatch
+	//	*Command_LeaseGrant
+	//	*Command_LeaseKeepAlive
+	//	*Command_LeaseRevoke
+	//	*Command_LeaseAttach
+	//	*Command_Lock
+	//	*Command_Unlock
+	//	*Command_Tick
+	//	*Command_NamespaceCreate
+	//	*Command_NamespaceDelete
+	//	*Command_CdcAck
+	//	*Command_CdcSinks
+	//	*Command_MemberSet
+	//	*Command_MemberRemove
+	Command isCommand_Command `protobuf_oneof:"command"`
+	// Leader's wall clock time in unix milliseconds when the command was proposed,
+	// 0 for commands proposed by older nodes. It advances the cluster time of replicas,
+	// which is the time commands are applied at, so commands don't need a time of their own.
+	Now int64 `protobuf:"varint,31,opt,name=now,proto3" json:"now,omitempty"`
+	// Namespace of the keys the command changes, empty for the default namespace.
+	// Leases and locks exist in the default namespace only.
+	Namespace string `protobuf:"bytes,34,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Client the command was proposed for, unset for commands the cluster proposes itself.
+	Origin        *Origin `protobuf:"bytes,37,opt,name=origin,proto3" json:"origin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Command) Reset() {
+	*x = Command{}
+	mi := &file_commands_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Command) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Command) ProtoMessage() {}
+
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Command) GetCommand() isCommand_Command {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *Command) GetPut() *PutCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Put); ok {
+			return x.Put
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetDelete() *DeleteCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Delete); ok {
+			return x.Delete
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetBatch() *BatchCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Batch); ok {
+			return x.Batch
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetSealed() []byte {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Sealed); ok {
+			return x.Sealed
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetMembership() *MembershipCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Membership); ok {
+			return x.Membership
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetSet() *SetCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Set); ok {
+			return x.Set
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetIncr() *IncrCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Incr); ok {
+			return x.Incr
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetExpire() *ExpireCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Expire); ok {
+			return x.Expire
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetReap() *ReapCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Reap); ok {
+			return x.Reap
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetMset() *MSetCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Mset); ok {
+			return x.Mset
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetDel() *DelCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Del); ok {
+			return x.Del
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetCounter() *CounterCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Counter); ok {
+			return x.Counter
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetFlush() *FlushCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Flush); ok {
+			return x.Flush
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetHset() *HSetCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Hset); ok {
+			return x.Hset
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetHdel() *HDelCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Hdel); ok {
+			return x.Hdel
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetHincrby() *HIncrByCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Hincrby); ok {
+			return x.Hincrby
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetPush() *PushCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Push); ok {
+			return x.Push
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetPop() *PopCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Pop); ok {
+			return x.Pop
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetZadd() *ZAddCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Zadd); ok {
+			return x.Zadd
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetZrem() *ZRemCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Zrem); ok {
+			return x.Zrem
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetZincrby() *ZIncrByCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Zincrby); ok {
+			return x.Zincrby
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetZpopmin() *ZPopMinCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Zpopmin); ok {
+			return x.Zpopmin
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetJsonPatch() *JsonPatchCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_JsonPatch); ok {
+			return x.JsonPatch
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetLeaseGrant() *LeaseGrantCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_LeaseGrant); ok {
+			return x.LeaseGrant
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetLeaseKeepAlive() *LeaseKeepAliveCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_LeaseKeepAlive); ok {
+			return x.LeaseKeepAlive
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetLeaseRevoke() *LeaseRevokeCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_LeaseRevoke); ok {
+			return x.LeaseRevoke
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetLeaseAttach() *LeaseAttachCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_LeaseAttach); ok {
+			return x.LeaseAttach
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetLock() *LockCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Lock); ok {
+			return x.Lock
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetUnlock() *UnlockCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Unlock); ok {
+			return x.Unlock
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetTick() *TickCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_Tick); ok {
+			return x.Tick
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetNamespaceCreate() *NamespaceCreateCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_NamespaceCreate); ok {
+			return x.NamespaceCreate
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetNamespaceDelete() *NamespaceDeleteCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_NamespaceDelete); ok {
+			return x.NamespaceDelete
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetCdcAck() *CdcAckCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_CdcAck); ok {
+			return x.CdcAck
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetCdcSinks() *CdcSinksCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_CdcSinks); ok {
+			return x.CdcSinks
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetMemberSet() *MemberSetCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_MemberSet); ok {
+			return x.MemberSet
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetMemberRemove() *MemberRemoveCommand {
+	if x != nil {
+		if x, ok := x.Command.(*Command_MemberRemove); ok {
+			return x.MemberRemove
+		}
+	}
+	return nil
+}
+
+func (x *Command) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+func (x *Command) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Command) GetOrigin() *Origin {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+type isCommand_Command interface {
+	isCommand_Command()
+}
+
+type Command_Put struct {
+	Put *PutCommand `protobuf:"bytes,1,opt,name=put,proto3,oneof"`
+}
+
+type Command_Delete struct {
+	Delete *DeleteCommand `protobuf:"bytes,2,opt,name=delete,proto3,oneof"`
+}
+
+type Command_Batch struct {
+	Batch *BatchCommand `protobuf:"bytes,3,opt,name=batch,proto3,oneof"`
+}
+
+type Command_Sealed struct {
+	// Sealed is a marshaled Command encrypted by the proposing node.
+	Sealed []byte `protobuf:"bytes,4,opt,name=sealed,proto3,oneof"`
+}
+
+type Command_Membership struct {
+	Membership *MembershipCommand `protobuf:"bytes,5,opt,name=membership,proto3,oneof"`
+}
+
+type Command_Set struct {
+	Set *SetCommand `protobuf:"bytes,6,opt,name=set,proto3,oneof"`
+}
+
+type Command_Incr struct {
+	Incr *IncrCommand `protobuf:"bytes,7,opt,name=incr,proto3,oneof"`
+}
+
+type Command_Expire struct {
+	Expire *ExpireCommand `protobuf:"bytes,8,opt,name=expire,proto3,oneof"`
+}
+
+type Command_Reap struct {
+	Reap *ReapCommand `protobuf:"bytes,9,opt,name=reap,proto3,oneof"`
+}
+
+type Command_Mset struct {
+	Mset *MSetCommand `protobuf:"bytes,10,opt,name=mset,proto3,oneof"`
+}
+
+type Command_Del struct {
+	Del *DelCommand `protobuf:"bytes,11,opt,name=del,proto3,oneof"`
+}
+
+type Command_Counter struct {
+	Counter *CounterCommand `protobuf:"bytes,12,opt,name=counter,proto3,oneof"`
+}
+
+type Command_Flush struct {
+	Flush *FlushCommand `protobuf:"bytes,13,opt,name=flush,proto3,oneof"`
+}
+
+type Command_Hset struct {
+	Hset *HSetCommand `protobuf:"bytes,14,opt,name=hset,proto3,oneof"`
+}
+
+type Command_Hdel struct {
+	Hdel *HDelCommand `protobuf:"bytes,15,opt,name=hdel,proto3,oneof"`
+}
+
+type Command_Hincrby struct {
+	Hincrby *HIncrByCommand `protobuf:"bytes,16,opt,name=hincrby,proto3,oneof"`
+}
+
+type Command_Push struct {
+	Push *PushCommand `protobuf:"bytes,17,opt,name=push,proto3,oneof"`
+}
+
+type Command_Pop struct {
+	Pop *PopCommand `protobuf:"bytes,18,opt,name=pop,proto3,oneof"`
+}
+
+type Command_Zadd struct {
+	Zadd *ZAddCommand `protobuf:"bytes,19,opt,name=zadd,proto3,oneof"`
+}
+
+type Command_Zrem struct {
+	Zrem *ZRemCommand `protobuf:"bytes,20,opt,name=zrem,proto3,oneof"`
+}
+
+type Command_Zincrby struct {
+	Zincrby *ZIncrByCommand `protobuf:"bytes,21,opt,name=zincrby,proto3,oneof"`
+}
+
+type Command_Zpopmin struct {
+	Zpopmin *ZPopMinCommand `protobuf:"bytes,22,opt,name=zpopmin,proto3,oneof"`
+}
+
+type Command_JsonPatch struct {
+	JsonPatch *JsonPatchCommand `protobuf:"bytes,23,opt,name=json_patch,json=jsonPatch,proto3,oneof"`
+}
+
+type Command_LeaseGrant struct {
+	LeaseGrant *LeaseGrantCommand `protobuf:"bytes,24,opt,name=lease_grant,json=leaseGrant,proto3,oneof"`
+}
+
+type Command_LeaseKeepAlive struct {
+	LeaseKeepAlive *LeaseKeepAliveCommand `protobuf:"bytes,25,opt,name=lease_keep_alive,json=leaseKeepAlive,proto3,oneof"`
+}
+
+type Command_LeaseRevoke struct {
+	LeaseRevoke *LeaseRevokeCommand `protobuf:"bytes,26,opt,name=lease_revoke,json=leaseRevoke,proto3,oneof"`
+}
+
+type Command_LeaseAttach struct {
+	LeaseAttach *LeaseAttachCommand `protobuf:"bytes,27,opt,name=lease_attach,json=leaseAttach,proto3,oneof"`
+}
+
+type Command_Lock struct {
+	Lock *LockCommand `protobuf:"bytes,28,opt,name=lock,proto3,oneof"`
+}
+
+type Command_Unlock struct {
+	Unlock *UnlockCommand `protobuf:"bytes,29,opt,name=unlock,proto3,oneof"`
+}
+
+type Command_Tick struct {
+	Tick *TickCommand `protobuf:"bytes,30,opt,name=tick,proto3,oneof"`
+}
+
+type Command_NamespaceCreate struct {
+	NamespaceCreate *NamespaceCreateCommand `protobuf:"bytes,32,opt,name=namespace_create,json=namespaceCreate,proto3,oneof"`
+}
+
+type Command_NamespaceDelete struct {
+	NamespaceDelete *NamespaceDeleteCommand `protobuf:"bytes,33,opt,name=namespace_delete,json=namespaceDelete,proto3,oneof"`
+}
+
+type Command_CdcAck struct {
+	CdcAck *CdcAckCommand `protobuf:"bytes,35,opt,name=cdc_ack,json=cdcAck,proto3,oneof"`
+}
+
+type Command_CdcSinks struct {
+	CdcSinks *CdcSinksCommand `protobuf:"bytes,36,opt,name=cdc_sinks,json=cdcSinks,proto3,oneof"`
+}
+
+type Command_MemberSet struct {
+	MemberSet *MemberSetCommand `protobuf:"bytes,38,opt,name=member_set,json=memberSet,proto3,oneof"`
+}
+
+type Command_MemberRemove struct {
+	MemberRemove *MemberRemoveCommand `protobuf:"bytes,39,opt,name=member_remove,json=memberRemove,proto3,oneof"`
+}
+
+func (*Command_Put) isCommand_Command() {}
+
+func (*Command_Delete) isCommand_Command() {}
+
+func (*Command_Batch) isCommand_Command() {}
+
+func (*Command_Sealed) isCommand_Command() {}
+
+func (*Command_Membership) isCommand_Command() {}
+
+func (*Command_Set) isCommand_Command() {}
+
+func (*Command_Incr) isCommand_Command() {}
+
+func (*Command_Expire) isCommand_Command() {}
+
+func (*Command_Reap) isCommand_Command() {}
+
+func (*Command_Mset) isCommand_Command() {}
+
+func (*Command_Del) isCommand_Command() {}
+
+func (*Command_Counter) isCommand_Command() {}
+
+func (*Command_Flush) isCommand_Command() {}
+
+func (*Command_Hset) isCommand_Command() {}
+
+func (*Command_Hdel) isCommand_Command() {}
+
+func (*Command_Hincrby) isCommand_Command() {}
+
+func (*Command_Push) isCommand_Command() {}
+
+func (*Command_Pop) isCommand_Command() {}
+
+func (*Command_Zadd) isCommand_Command() {}
+
+func (*Command_Zrem) isCommand_Command() {}
+
+func (*Command_Zincrby) isCommand_Command() {}
+
+func (*Command_Zpopmin) isCommand_Command() {}
+
+func (*Command_JsonPatch) isCommand_Command() {}
+
+func (*Command_LeaseGrant) isCommand_Command() {}
+
+func (*Command_LeaseKeepAlive) isCommand_Command() {}
+
+func (*Command_LeaseRevoke) isCommand_Command() {}
+
+func (*Command_LeaseAttach) isCommand_Command() {}
+
+func (*Command_Lock) isCommand_Command() {}
+
+func (*Command_Unlock) isCommand_Command() {}
+
+func (*Command_Tick) isCommand_Command() {}
+
+func (*Command_NamespaceCreate) isCommand_Command() {}
+
+func (*Command_NamespaceDelete) isCommand_Command() {}
+
+func (*Command_CdcAck) isCommand_Command() {}
+
+func (*Command_CdcSinks) isCommand_Command() {}
+
+func (*Command_MemberSet) isCommand_Command() {}
+
+func (*Command_MemberRemove) isCommand_Command() {}
+
+// Origin identifies the client request a command was proposed for, so committed changes can be audited.
+type Origin struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Authenticated identity if known and client ip otherwise.
+	Caller        string `protobuf:"bytes,1,opt,name=caller,proto3" json:"caller,omitempty"`
+	Ip            string `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	RequestId     string `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Origin) Reset() {
+	*x = Origin{}
+	mi := &file_commands_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Origin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Origin) ProtoMessage() {}
+
+func (x *Origin) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Origin.ProtoReflect.Descriptor instead.
+func (*Origin) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *Origin) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *Origin) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Origin) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// CdcCursor is the log index up to which changes were delivered to a change data capture sink.
+type CdcCursor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sink          string                 `protobuf:"bytes,1,opt,name=sink,proto3" json:"sink,omitempty"`
+	Index         int64                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CdcCursor) Reset() {
+	*x = CdcCursor{}
+	mi := &file_commands_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CdcCursor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CdcCursor) ProtoMessage() {}
+
+func (x *CdcCursor) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CdcCursor.ProtoReflect.Descriptor instead.
+func (*CdcCursor) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CdcCursor) GetSink() string {
+	if x != nil {
+		return x.Sink
+	}
+	return ""
+}
+
+func (x *CdcCursor) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+// CdcAckCommand is proposed by the leader once changes up to the index were delivered to the sink.
+type CdcAckCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        *CdcCursor             `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CdcAckCommand) Reset() {
+	*x = CdcAckCommand{}
+	mi := &file_commands_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CdcAckCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CdcAckCommand) ProtoMessage() {}
+
+func (x *CdcAckCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CdcAckCommand.ProtoReflect.Descriptor instead.
+func (*CdcAckCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CdcAckCommand) GetCursor() *CdcCursor {
+	if x != nil {
+		return x.Cursor
+	}
+	return nil
+}
+
+// CdcSinksCommand replaces the set of change data capture sinks. Added sinks get changes applied
+// after the command, cursors of removed sinks are dropped.
+type CdcSinksCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sinks         []string               `protobuf:"bytes,1,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CdcSinksCommand) Reset() {
+	*x = CdcSinksCommand{}
+	mi := &file_commands_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CdcSinksCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CdcSinksCommand) ProtoMessage() {}
+
+func (x *CdcSinksCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CdcSinksCommand.ProtoReflect.Descriptor instead.
+func (*CdcSinksCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CdcSinksCommand) GetSinks() []string {
+	if x != nil {
+		return x.Sinks
+	}
+	return nil
+}
+
+// CdcChange is an applied change kept until it's delivered to every change data capture sink.
+type CdcChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Index int64                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Position of the change among changes of the same log entry.
+	Seq int32 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Cluster time in unix milliseconds when the change was applied.
+	Time    int64    `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Command *Command `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
+	// Result data of commands whose result is the new value, e.g. incr.
+	Result []byte `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	// Keys deleted as a side effect of the command, e.g. keys of a revoked lease or expired keys.
+	Deleted       []string `protobuf:"bytes,6,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CdcChange) Reset() {
+	*x = CdcChange{}
+	mi := &file_commands_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CdcChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CdcChange) ProtoMessage() {}
+
+func (x *CdcChange) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CdcChange.ProtoReflect.Descriptor instead.
+func (*CdcChange) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CdcChange) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CdcChange) GetSeq() int32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *CdcChange) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *CdcChange) GetCommand() *Command {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *CdcChange) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CdcChange) GetDeleted() []string {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+// TickCommand is proposed by the leader while there are no other writes, so the cluster time keeps up
+// with the leader's clock. The time is in the command envelope.
+type TickCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TickCommand) Reset() {
+	*x = TickCommand{}
+	mi := &file_commands_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TickCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TickCommand) ProtoMessage() {}
+
+func (x *TickCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TickCommand.ProtoReflect.Descriptor instead.
+func (*TickCommand) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{44}
+}
+
+// SnapshotState is a legacy snapshot format holding all items in a single message.
+type SnapshotState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         map[string]string      `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotState) Reset() {
+	*x = SnapshotState{}
+	mi := &file_commands_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotState) ProtoMessage() {}
+
+func (x *SnapshotState) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotState.ProtoReflect.Descriptor instead.
+func (*SnapshotState) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SnapshotState) GetItems() map[string]string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type SnapshotEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Expiry deadline in unix milliseconds, 0 means the key doesn't expire.
+	ExpiresAt int64             `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Flags     uint32            `protobuf:"varint,4,opt,name=flags,proto3" json:"flags,omitempty"`
+	Version   uint64            `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	Kind      ValueKind         `protobuf:"varint,6,opt,name=kind,proto3,enum=fsm.v1.ValueKind" json:"kind,omitempty"`
+	Hash      map[string]string `protobuf:"bytes,7,rep,name=hash,proto3" json:"hash,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	List      []string          `protobuf:"bytes,8,rep,name=list,proto3" json:"list,omitempty"`
+	// Members of a sorted set in ascending order.
+	Zset          []*ScoredMember `protobuf:"bytes,9,rep,name=zset,proto3" json:"zset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotEntry) Reset() {
+	*x = SnapshotEntry{}
+	mi := &file_commands_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotEntry) ProtoMessage() {}
+
+func (x *SnapshotEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotEntry.ProtoReflect.Descriptor instead.
+func (*SnapshotEntry) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SnapshotEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SnapshotEntry) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *SnapshotEntry) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *SnapshotEntry) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+func (x *SnapshotEntry) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SnapshotEntry) GetKind() ValueKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ValueKind_VALUE_KIND_STRING
+}
+
+func (x *SnapshotEntry) GetHash() map[string]string {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *SnapshotEntry) GetList() []string {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *SnapshotEntry) GetZset() []*ScoredMember {
+	if x != nil {
+		return x.Zset
+	}
+	return nil
+}
+
+// SnapshotChunk is a part of a chunked snapshot holding up to a few thousand entries.
+// Replicated membership, if any, is stored in a separate chunk without entries.
+type SnapshotChunk struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Entries    []*SnapshotEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Membership *MembershipCommand     `protobuf:"bytes,2,opt,name=membership,proto3" json:"membership,omitempty"`
+	// Last version assigned by the store, stored in a separate chunk after entries.
+	LastVersion uint64 `protobuf:"varint,3,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
+	// Leases ordered by id, stored in a separate chunk after membership.
+	Leases []*Lease `protobuf:"bytes,4,rep,name=leases,proto3" json:"leases,omitempty"`
+	// Cluster time in unix milliseconds, stored in a separate chunk after leases.
+	ClusterTime int64 `protobuf:"varint,5,opt,name=cluster_time,json=clusterTime,proto3" json:"cluster_time,omitempty"`
+	// Namespaces ordered by name, stored in a separate chunk after the last lease id.
+	Namespaces []*Namespace `protobuf:"bytes,6,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	// Namespace of the entries, entries of the default namespace are stored first.
+	Namespace string `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Cursors of change data capture sinks ordered by sink, stored in a separate chunk after namespaces.
+	CdcCursors []*CdcCursor `protobuf:"bytes,8,rep,name=cdc_cursors,json=cdcCursors,proto3" json:"cdc_cursors,omitempty"`
+	// Changes not yet delivered to every change data capture sink ordered by index,
+	// stored in separate chunks after cdc cursors.
+	CdcChanges []*CdcChange `protobuf:"bytes,9,rep,name=cdc_changes,json=cdcChanges,proto3" json:"cdc_changes,omitempty"`
+	// Last id given to a lease or a fencing token, stored in a separate chunk after cluster time.
+	LastLeaseId   int64 `protobuf:"varint,10,opt,name=last_lease_id,json=lastLeaseId,proto3" json:"last_lease_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotChunk) Reset() {
+	*x = SnapshotChunk{}
+	mi := &file_commands_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotChunk) ProtoMessage() {}
+
+func (x *SnapshotChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_commands_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotChunk.ProtoReflect.Descriptor instead.
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return file_commands_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SnapshotChunk) GetEntries() []*SnapshotEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *SnapshotChunk) GetMembership() *MembershipCommand {
+	if x != nil {
+		return x.Membership
+	}
+	return nil
+}
+
+func (x *SnapshotChunk) GetLastVersion() uint64 {
+	if x != nil {
+		return x.LastVersion
+	}
+	return 0
+}
+
+func (x *SnapshotChunk) GetLeases() []*Lease {
+	if x != nil {
+		return x.Leases
+	}
+	return nil
+}
+
+func (x *SnapshotChunk) GetClusterTime() int64 {
+	if x != nil {
+		return x.ClusterTime
+	}
+	return 0
+}
+
+func (x *SnapshotChunk) GetNamespaces() []*Namespace {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *SnapshotChunk) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SnapshotChunk) GetCdcCursors() []*CdcCursor {
+	if x != nil {
+		return x.CdcCursors
+	}
+	return nil
+}
+
+func (x *SnapshotChunk) GetCdcChanges() []*CdcChange {
+	if x != nil {
+		return x.CdcChanges
+	}
+	return nil
+}
+
+func (x *SnapshotChunk) GetLastLeaseId() int64 {
+	if x != nil {
+		return x.LastLeaseId
+	}
+	return 0
+}
+
+var File_commands_proto protoreflect.FileDescriptor
+
+const file_commands_proto_rawDesc = "" +
+	"\n" +
+	"\x0ecommands.proto\x12\x06fsm.v1\"4\n" +
+	"\n" +
+	"PutCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"!\n" +
+	"\rDeleteCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"\xc9\x01\n" +
+	"\n" +
+	"SetCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x122\n" +
+	"\tcondition\x18\x03 \x01(\x0e2\x14.fsm.v1.SetConditionR\tcondition\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12\x10\n" +
+	"\x03now\x18\x05 \x01(\x03R\x03now\x12\x14\n" +
+	"\x05flags\x18\x06 \x01(\rR\x05flags\x12\x18\n" +
+	"\aversion\x18\a \x01(\x04R\aversion\"G\n" +
+	"\vIncrCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x03R\x05delta\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"^\n" +
+	"\x0eCounterCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x04R\x05delta\x12\x12\n" +
+	"\x04decr\x18\x03 \x01(\bR\x04decr\x12\x10\n" +
+	"\x03now\x18\x04 \x01(\x03R\x03now\"?\n" +
+	"\fFlushCommand\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x01 \x01(\x03R\texpiresAt\x12\x10\n" +
+	"\x03now\x18\x02 \x01(\x03R\x03now\"R\n" +
+	"\rExpireCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"K\n" +
+	"\vReapCommand\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\x12\x10\n" +
+	"\x03now\x18\x02 \x01(\x03R\x03now\x12\x16\n" +
+	"\x06leases\x18\x03 \x03(\x03R\x06leases\"5\n" +
+	"\vMSetCommand\x12&\n" +
+	"\x04puts\x18\x01 \x03(\v2\x12.fsm.v1.PutCommandR\x04puts\"2\n" +
+	"\n" +
+	"DelCommand\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\x12\x10\n" +
+	"\x03now\x18\x02 \x01(\x03R\x03now\"\xa5\x01\n" +
+	"\vHSetCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x127\n" +
+	"\x06fields\x18\x02 \x03(\v2\x1f.fsm.v1.HSetCommand.FieldsEntryR\x06fields\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"I\n" +
+	"\vHDelCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06fields\x18\x02 \x03(\tR\x06fields\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"`\n" +
+	"\x0eHIncrByCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x03R\x05delta\x12\x10\n" +
+	"\x03now\x18\x04 \x01(\x03R\x03now\"]\n" +
+	"\vPushCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\x12\x12\n" +
+	"\x04left\x18\x03 \x01(\bR\x04left\x12\x10\n" +
+	"\x03now\x18\x04 \x01(\x03R\x03now\"Z\n" +
+	"\n" +
+	"PopCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x12\n" +
+	"\x04left\x18\x03 \x01(\bR\x04left\x12\x10\n" +
+	"\x03now\x18\x04 \x01(\x03R\x03now\"#\n" +
+	"\tPopResult\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"<\n" +
+	"\fScoredMember\x12\x16\n" +
+	"\x06member\x18\x01 \x01(\tR\x06member\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"a\n" +
+	"\vZAddCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12.\n" +
+	"\amembers\x18\x02 \x03(\v2\x14.fsm.v1.ScoredMemberR\amembers\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"K\n" +
+	"\vZRemCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"b\n" +
+	"\x0eZIncrByCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06member\x18\x02 \x01(\tR\x06member\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x01R\x05delta\x12\x10\n" +
+	"\x03now\x18\x04 \x01(\x03R\x03now\"J\n" +
+	"\x0eZPopMinCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"<\n" +
+	"\n" +
+	"ZPopResult\x12.\n" +
+	"\amembers\x18\x01 \x03(\v2\x14.fsm.v1.ScoredMemberR\amembers\"s\n" +
+	"\x10JsonPatchCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05patch\x18\x02 \x01(\fR\x05patch\x12%\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x11.fsm.v1.PatchTypeR\x04type\x12\x10\n" +
+	"\x03now\x18\x04 \x01(\x03R\x03now\"a\n" +
+	"\x05Lease\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x15\n" +
+	"\x06ttl_ms\x18\x02 \x01(\x03R\x05ttlMs\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\x12\x12\n" +
+	"\x04keys\x18\x04 \x03(\tR\x04keys\"<\n" +
+	"\x11LeaseGrantCommand\x12\x15\n" +
+	"\x06ttl_ms\x18\x01 \x01(\x03R\x05ttlMs\x12\x10\n" +
+	"\x03now\x18\x02 \x01(\x03R\x03now\"9\n" +
+	"\x15LeaseKeepAliveCommand\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03now\x18\x02 \x01(\x03R\x03now\"6\n" +
+	"\x12LeaseRevokeCommand\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03now\x18\x02 \x01(\x03R\x03now\"J\n" +
+	"\x12LeaseAttachCommand\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04keys\x18\x02 \x03(\tR\x04keys\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"G\n" +
+	"\vLockCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05lease\x18\x02 \x01(\x03R\x05lease\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"I\n" +
+	"\rUnlockCommand\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05lease\x18\x02 \x01(\x03R\x05lease\x12\x10\n" +
+	"\x03now\x18\x03 \x01(\x03R\x03now\"5\n" +
+	"\x06Member\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1b\n" +
+	"\thttp_addr\x18\x02 \x01(\tR\bhttpAddr\"=\n" +
+	"\x11MembershipCommand\x12(\n" +
+	"\amembers\x18\x01 \x03(\v2\x0e.fsm.v1.MemberR\amembers\":\n" +
+	"\x10MemberSetCommand\x12&\n" +
+	"\x06member\x18\x01 \x01(\v2\x0e.fsm.v1.MemberR\x06member\"%\n" +
+	"\x13MemberRemoveCommand\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"~\n" +
+	"\tNamespace\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\fmax_key_size\x18\x02 \x01(\x05R\n" +
+	"maxKeySize\x12 \n" +
+	"\fmax_val_size\x18\x03 \x01(\x05R\n" +
+	"maxValSize\x12\x19\n" +
+	"\bmax_keys\x18\x04 \x01(\x05R\amaxKeys\"I\n" +
+	"\x16NamespaceCreateCommand\x12/\n" +
+	"\tnamespace\x18\x01 \x01(\v2\x11.fsm.v1.NamespaceR\tnamespace\",\n" +
+	"\x16NamespaceDeleteCommand\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"*\n" +
+	"\fBatchCommand\x12\x1a\n" +
+	"\bcommands\x18\x01 \x03(\fR\bcommands\"\x99\x0f\n" +
+	"\aCommand\x12&\n" +
+	"\x03put\x18\x01 \x01(\v2\x12.fsm.v1.PutCommandH\x00R\x03put\x12/\n" +
+	"\x06delete\x18\x02 \x01(\v2\x15.fsm.v1.DeleteCommandH\x00R\x06delete\x12,\n" +
+	"\x05batch\x18\x03 \x01(\v2\x14.fsm.v1.BatchCommandH\x00R\x05batch\x12\x18\n" +
+	"\x06sealed\x18\x04 \x01(\fH\x00R\x06sealed\x12;\n" +
+	"\n" +
+	"membership\x18\x05 \x01(\v2\x19.fsm.v1.MembershipCommandH\x00R\n" +
+	"membership\x12&\n" +
+	"\x03set\x18\x06 \x01(\v2\x12.fsm.v1.SetCommandH\x00R\x03set\x12)\n" +
+	"\x04incr\x18\a \x01(\v2\x13.fsm.v1.IncrCommandH\x00R\x04incr\x12/\n" +
+	"\x06expire\x18\b \x01(\v2\x15.fsm.v1.ExpireCommandH\x00R\x06expire\x12)\n" +
+	"\x04reap\x18\t \x01(\v2\x13.fsm.v1.ReapCommandH\x00R\x04reap\x12)\n" +
+	"\x04mset\x18\n" +
+	" \x01(\v2\x13.fsm.v1.MSetCommandH\x00R\x04mset\x12&\n" +
+	"\x03del\x18\v \x01(\v2\x12.fsm.v1.DelCommandH\x00R\x03del\x122\n" +
+	"\acounter\x18\f \x01(\v2\x16.fsm.v1.CounterCommandH\x00R\acounter\x12,\n" +
+	"\x05flush\x18\r \x01(\v2\x14.fsm.v1.FlushCommandH\x00R\x05flush\x12)\n" +
+	"\x04hset\x18\x0e \x01(\v2\x13.fsm.v1.HSetCommandH\x00R\x04hset\x12)\n" +
+	"\x04hdel\x18\x0f \x01(\v2\x13.fsm.v1.HDelCommandH\x00R\x04hdel\x122\n" +
+	"\ahincrby\x18\x10 \x01(\v2\x16.fsm.v1.HIncrByCommandH\x00R\ahincrby\x12)\n" +
+	"\x04push\x18\x11 \x01(\v2\x13.fsm.v1.PushCommandH\x00R\x04push\x12&\n" +
+	"\x03pop\x18\x12 \x01(\v2\x12.fsm.v1.PopCommandH\x00R\x03pop\x12)\n" +
+	"\x04zadd\x18\x13 \x01(\v2\x13.fsm.v1.ZAddCommandH\x00R\x04zadd\x12)\n" +
+	"\x04zrem\x18\x14 \x01(\v2\x13.fsm.v1.ZRemCommandH\x00R\x04zrem\x122\n" +
+	"\azincrby\x18\x15 \x01(\v2\x16.fsm.v1.ZIncrByCommandH\x00R\azincrby\x122\n" +
+	"\azpopmin\x18\x16 \x01(\v2\x16.fsm.v1.ZPopMinCommandH\x00R\azpopmin\x129\n" +
+	"\n" +
+	"json_patch\x18\x17 \x01(\v2\x18.fsm.v1.JsonPatchCommandH\x00R\tjsonPatch\x12<\n" +
+	"\vlease_grant\x18\x18 \x01(\v2\x19.fsm.v1.LeaseGrantCommandH\x00R\n" +
+	"leaseGrant\x12I\n" +
+	"\x10lease_keep_alive\x18\x19 \x01(\v2\x1d.fsm.v1.LeaseKeepAliveCommandH\x00R\x0eleaseKeepAlive\x12?\n" +
+	"\flease_revoke\x18\x1a \x01(\v2\x1a.fsm.v1.LeaseRevokeCommandH\x00R\vleaseRevoke\x12?\n" +
+	"\flease_attach\x18\x1b \x01(\v2\x1a.fsm.v1.LeaseAttachCommandH\x00R\vleaseAttach\x12)\n" +
+	"\x04lock\x18\x1c \x01(\v2\x13.fsm.v1.LockCommandH\x00R\x04lock\x12/\n" +
+	"\x06unlock\x18\x1d \x01(\v2\x15.fsm.v1.UnlockCommandH\x00R\x06unlock\x12)\n" +
+	"\x04tick\x18\x1e \x01(\v2\x13.fsm.v1.TickCommandH\x00R\x04tick\x12K\n" +
+	"\x10namespace_create\x18  \x01(\v2\x1e.fsm.v1.NamespaceCreateCommandH\x00R\x0fnamespaceCreate\x12K\n" +
+	"\x10namespace_delete\x18! \x01(\v2\x1e.fsm.v1.NamespaceDeleteCommandH\x00R\x0fnamespaceDelete\x120\n" +
+	"\acdc_ack\x18# \x01(\v2\x15.fsm.v1.CdcAckCommandH\x00R\x06cdcAck\x126\n" +
+	"\tcdc_sinks\x18$ \x01(\v2\x17.fsm.v1.CdcSinksCommandH\x00R\bcdcSinks\x129\n" +
+	"\n" +
+	"member_set\x18& \x01(\v2\x18.fsm.v1.MemberSetCommandH\x00R\tmemberSet\x12B\n" +
+	"\rmember_remove\x18' \x01(\v2\x1b.fsm.v1.MemberRemoveCommandH\x00R\fmemberRemove\x12\x10\n" +
+	"\x03now\x18\x1f \x01(\x03R\x03now\x12\x1c\n" +
+	"\tnamespace\x18\" \x01(\tR\tnamespace\x12&\n" +
+	"\x06origin\x18% \x01(\v2\x0e.fsm.v1.OriginR\x06originB\t\n" +
+	"\acommand\"O\n" +
+	"\x06Origin\x12\x16\n" +
+	"\x06caller\x18\x01 \x01(\tR\x06caller\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"5\n" +
+	"\tCdcCursor\x12\x12\n" +
+	"\x04sink\x18\x01 \x01(\tR\x04sink\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x03R\x05index\":\n" +
+	"\rCdcAckCommand\x12)\n" +
+	"\x06cursor\x18\x01 \x01(\v2\x11.fsm.v1.CdcCursorR\x06cursor\"'\n" +
+	"\x0fCdcSinksCommand\x12\x14\n" +
+	"\x05sinks\x18\x01 \x03(\tR\x05sinks\"\xa4\x01\n" +
+	"\tCdcChange\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x05R\x03seq\x12\x12\n" +
+	"\x04time\x18\x03 \x01(\x03R\x04time\x12)\n" +
+	"\acommand\x18\x04 \x01(\v2\x0f.fsm.v1.CommandR\acommand\x12\x16\n" +
+	"\x06result\x18\x05 \x01(\fR\x06result\x12\x18\n" +
+	"\adeleted\x18\x06 \x03(\tR\adeleted\"\r\n" +
+	"\vTickCommand\"\x81\x01\n" +
+	"\rSnapshotState\x126\n" +
+	"\x05items\x18\x01 \x03(\v2 .fsm.v1.SnapshotState.ItemsEntryR\x05items\x1a8\n" +
+	"\n" +
+	"ItemsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd9\x02\n" +
+	"\rSnapshotEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\x12\x14\n" +
+	"\x05flags\x18\x04 \x01(\rR\x05flags\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x04R\aversion\x12%\n" +
+	"\x04kind\x18\x06 \x01(\x0e2\x11.fsm.v1.ValueKindR\x04kind\x123\n" +
+	"\x04hash\x18\a \x03(\v2\x1f.fsm.v1.SnapshotEntry.HashEntryR\x04hash\x12\x12\n" +
+	"\x04list\x18\b \x03(\tR\x04list\x12(\n" +
+	"\x04zset\x18\t \x03(\v2\x14.fsm.v1.ScoredMemberR\x04zset\x1a7\n" +
+	"\tHashEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc5\x03\n" +
+	"\rSnapshotChunk\x12/\n" +
+	"\aentries\x18\x01 \x03(\v2\x15.fsm.v1.SnapshotEntryR\aentries\x129\n" +
+	"\n" +
+	"membership\x18\x02 \x01(\v2\x19.fsm.v1.MembershipCommandR\n" +
+	"membership\x12!\n" +
+	"\flast_version\x18\x03 \x01(\x04R\vlastVersion\x12%\n" +
+	"\x06leases\x18\x04 \x03(\v2\r.fsm.v1.LeaseR\x06leases\x12!\n" +
+	"\fcluster_time\x18\x05 \x01(\x03R\vclusterTime\x121\n" +
+	"\n" +
+	"namespaces\x18\x06 \x03(\v2\x11.fsm.v1.NamespaceR\n" +
+	"namespaces\x12\x1c\n" +
+	"\tnamespace\x18\a \x01(\tR\tnamespace\x122\n" +
+	"\vcdc_cursors\x18\b \x03(\v2\x11.fsm.v1.CdcCursorR\n" +
+	"cdcCursors\x122\n" +
+	"\vcdc_changes\x18\t \x03(\v2\x11.fsm.v1.CdcChangeR\n" +
+	"cdcChanges\x12\"\n" +
+	"\rlast_lease_id\x18\n" +
+	" \x01(\x03R\vlastLeaseId*^\n" +
+	"\fSetCondition\x12\x16\n" +
+	"\x12SET_CONDITION_NONE\x10\x00\x12\x1c\n" +
+	"\x18SET_CONDITION_NOT_EXISTS\x10\x01\x12\x18\n" +
+	"\x14SET_CONDITION_EXISTS\x10\x02*B\n" +
+	"\tPatchType\x12\x19\n" +
+	"\x15PATCH_TYPE_JSON_PATCH\x10\x00\x12\x1a\n" +
+	"\x16PATCH_TYPE_MERGE_PATCH\x10\x01*a\n" +
+	"\tValueKind\x12\x15\n" +
+	"\x11VALUE_KIND_STRING\x10\x00\x12\x13\n" +
+	"\x0fVALUE_KIND_HASH\x10\x01\x12\x13\n" +
+	"\x0fVALUE_KIND_LIST\x10\x02\x12\x13\n" +
+	"\x0fVALUE_KIND_ZSET\x10\x03B1Z/github.com/shrtyk/kv-store/proto/fsm/gen;fsm_v1b\x06proto3"
 
 var (
 	file_commands_proto_rawDescOnce sync.Once
@@ -277,23 +3943,127 @@ func file_commands_proto_rawDescGZIP() []byte {
 	return file_commands_proto_rawDescData
 }
 
-var file_commands_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_commands_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_commands_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_commands_proto_goTypes = []any{
-	(*PutCommand)(nil),    // 0: fsm.v1.PutCommand
-	(*DeleteCommand)(nil), // 1: fsm.v1.DeleteCommand
-	(*Command)(nil),       // 2: fsm.v1.Command
-	(*SnapshotState)(nil), // 3: fsm.v1.SnapshotState
-	nil,                   // 4: fsm.v1.SnapshotState.ItemsEntry
+	(SetCondition)(0),              // 0: fsm.v1.SetCondition
+	(PatchType)(0),                 // 1: fsm.v1.PatchType
+	(ValueKind)(0),                 // 2: fsm.v1.ValueKind
+	(*PutCommand)(nil),             // 3: fsm.v1.PutCommand
+	(*DeleteCommand)(nil),          // 4: fsm.v1.DeleteCommand
+	(*SetCommand)(nil),             // 5: fsm.v1.SetCommand
+	(*IncrCommand)(nil),            // 6: fsm.v1.IncrCommand
+	(*CounterCommand)(nil),         // 7: fsm.v1.CounterCommand
+	(*FlushCommand)(nil),           // 8: fsm.v1.FlushCommand
+	(*ExpireCommand)(nil),          // 9: fsm.v1.ExpireCommand
+	(*ReapCommand)(nil),            // 10: fsm.v1.ReapCommand
+	(*MSetCommand)(nil),            // 11: fsm.v1.MSetCommand
+	(*DelCommand)(nil),             // 12: fsm.v1.DelCommand
+	(*HSetCommand)(nil),            // 13: fsm.v1.HSetCommand
+	(*HDelCommand)(nil),            // 14: fsm.v1.HDelCommand
+	(*HIncrByCommand)(nil),         // 15: fsm.v1.HIncrByCommand
+	(*PushCommand)(nil),            // 16: fsm.v1.PushCommand
+	(*PopCommand)(nil),             // 17: fsm.v1.PopCommand
+	(*PopResult)(nil),              // 18: fsm.v1.PopResult
+	(*ScoredMember)(nil),           // 19: fsm.v1.ScoredMember
+	(*ZAddCommand)(nil),            // 20: fsm.v1.ZAddCommand
+	(*ZRemCommand)(nil),            // 21: fsm.v1.ZRemCommand
+	(*ZIncrByCommand)(nil),         // 22: fsm.v1.ZIncrByCommand
+	(*ZPopMinCommand)(nil),         // 23: fsm.v1.ZPopMinCommand
+	(*ZPopResult)(nil),             // 24: fsm.v1.ZPopResult
+	(*JsonPatchCommand)(nil),       // 25: fsm.v1.JsonPatchCommand
+	(*Lease)(nil),                  // 26: fsm.v1.Lease
+	(*LeaseGrantCommand)(nil),      // 27: fsm.v1.LeaseGrantCommand
+	(*LeaseKeepAliveCommand)(nil),  // 28: fsm.v1.LeaseKeepAliveCommand
+	(*LeaseRevokeCommand)(nil),     // 29: fsm.v1.LeaseRevokeCommand
+	(*LeaseAttachCommand)(nil),     // 30: fsm.v1.LeaseAttachCommand
+	(*LockCommand)(nil),            // 31: fsm.v1.LockCommand
+	(*UnlockCommand)(nil),          // 32: fsm.v1.UnlockCommand
+	(*Member)(nil),                 // 33: fsm.v1.Member
+	(*MembershipCommand)(nil),      // 34: fsm.v1.MembershipCommand
+	(*MemberSetCommand)(nil),       // 35: fsm.v1.MemberSetCommand
+	(*MemberRemoveCommand)(nil),    // 36: fsm.v1.MemberRemoveCommand
+	(*Namespace)(nil),              // 37: fsm.v1.Namespace
+	(*NamespaceCreateCommand)(nil), // 38: fsm.v1.NamespaceCreateCommand
+	(*NamespaceDeleteCommand)(nil), // 39: fsm.v1.NamespaceDeleteCommand
+	(*BatchCommand)(nil),           // 40: fsm.v1.BatchCommand
+	(*Command)(nil),                // 41: fsm.v1.Command
+	(*Origin)(nil),                 // 42: fsm.v1.Origin
+	(*CdcCursor)(nil),              // 43: fsm.v1.CdcCursor
+	(*CdcAckCommand)(nil),          // 44: fsm.v1.CdcAckCommand
+	(*CdcSinksCommand)(nil),        // 45: fsm.v1.CdcSinksCommand
+	(*CdcChange)(nil),              // 46: fsm.v1.CdcChange
+	(*TickCommand)(nil),            // 47: fsm.v1.TickCommand
+	(*SnapshotState)(nil),          // 48: fsm.v1.SnapshotState
+	(*SnapshotEntry)(nil),          // 49: fsm.v1.SnapshotEntry
+	(*SnapshotChunk)(nil),          // 50: fsm.v1.SnapshotChunk
+	nil,                            // 51: fsm.v1.HSetCommand.FieldsEntry
+	nil,                            // 52: fsm.v1.SnapshotState.ItemsEntry
+	nil,                            // 53: fsm.v1.SnapshotEntry.HashEntry
 }
 var file_commands_proto_depIdxs = []int32{
-	0, // 0: fsm.v1.Command.put:type_name -> fsm.v1.PutCommand
-	1, // 1: fsm.v1.Command.delete:type_name -> fsm.v1.DeleteCommand
-	4, // 2: fsm.v1.SnapshotState.items:type_name -> fsm.v1.SnapshotState.ItemsEntry
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	0,  // 0: fsm.v1.SetCommand.condition:type_name -> fsm.v1.SetCondition
+	3,  // 1: fsm.v1.MSetCommand.puts:type_name -> fsm.v1.PutCommand
+	51, // 2: fsm.v1.HSetCommand.fields:type_name -> fsm.v1.HSetCommand.FieldsEntry
+	19, // 3: fsm.v1.ZAddCommand.members:type_name -> fsm.v1.ScoredMember
+	19, // 4: fsm.v1.ZPopResult.members:type_name -> fsm.v1.ScoredMember
+	1,  // 5: fsm.v1.JsonPatchCommand.type:type_name -> fsm.v1.PatchType
+	33, // 6: fsm.v1.MembershipCommand.members:type_name -> fsm.v1.Member
+	33, // 7: fsm.v1.MemberSetCommand.member:type_name -> fsm.v1.Member
+	37, // 8: fsm.v1.NamespaceCreateCommand.namespace:type_name -> fsm.v1.Namespace
+	3,  // 9: fsm.v1.Command.put:type_name -> fsm.v1.PutCommand
+	4,  // 10: fsm.v1.Command.delete:type_name -> fsm.v1.DeleteCommand
+	40, // 11: fsm.v1.Command.batch:type_name -> fsm.v1.BatchCommand
+	34, // 12: fsm.v1.Command.membership:type_name -> fsm.v1.MembershipCommand
+	5,  // 13: fsm.v1.Command.set:type_name -> fsm.v1.SetCommand
+	6,  // 14: fsm.v1.Command.incr:type_name -> fsm.v1.IncrCommand
+	9,  // 15: fsm.v1.Command.expire:type_name -> fsm.v1.ExpireCommand
+	10, // 16: fsm.v1.Command.reap:type_name -> fsm.v1.ReapCommand
+	11, // 17: fsm.v1.Command.mset:type_name -> fsm.v1.MSetCommand
+	12, // 18: fsm.v1.Command.del:type_name -> fsm.v1.DelCommand
+	7,  // 19: fsm.v1.Command.counter:type_name -> fsm.v1.CounterCommand
+	8,  // 20: fsm.v1.Command.flush:type_name -> fsm.v1.FlushCommand
+	13, // 21: fsm.v1.Command.hset:type_name -> fsm.v1.HSetCommand
+	14, // 22: fsm.v1.Command.hdel:type_name -> fsm.v1.HDelCommand
+	15, // 23: fsm.v1.Command.hincrby:type_name -> fsm.v1.HIncrByCommand
+	16, // 24: fsm.v1.Command.push:type_name -> fsm.v1.PushCommand
+	17, // 25: fsm.v1.Command.pop:type_name -> fsm.v1.PopCommand
+	20, // 26: fsm.v1.Command.zadd:type_name -> fsm.v1.ZAddCommand
+	21, // 27: fsm.v1.Command.zrem:type_name -> fsm.v1.ZRemCommand
+	22, // 28: fsm.v1.Command.zincrby:type_name -> fsm.v1.ZIncrByCommand
+	23, // 29: fsm.v1.Command.zpopmin:type_name -> fsm.v1.ZPopMinCommand
+	25, // 30: fsm.v1.Command.json_patch:type_name -> fsm.v1.JsonPatchCommand
+	27, // 31: fsm.v1.Command.lease_grant:type_name -> fsm.v1.LeaseGrantCommand
+	28, // 32: fsm.v1.Command.lease_keep_alive:type_name -> fsm.v1.LeaseKeepAliveCommand
+	29, // 33: fsm.v1.Command.lease_revoke:type_name -> fsm.v1.LeaseRevokeCommand
+	30, // 34: fsm.v1.Command.lease_attach:type_name -> fsm.v1.LeaseAttachCommand
+	31, // 35: fsm.v1.Command.lock:type_name -> fsm.v1.LockCommand
+	32, // 36: fsm.v1.Command.unlock:type_name -> fsm.v1.UnlockCommand
+	47, // 37: fsm.v1.Command.tick:type_name -> fsm.v1.TickCommand
+	38, // 38: fsm.v1.Command.namespace_create:type_name -> fsm.v1.NamespaceCreateCommand
+	39, // 39: fsm.v1.Command.namespace_delete:type_name -> fsm.v1.NamespaceDeleteCommand
+	44, // 40: fsm.v1.Command.cdc_ack:type_name -> fsm.v1.CdcAckCommand
+	45, // 41: fsm.v1.Command.cdc_sinks:type_name -> fsm.v1.CdcSinksCommand
+	35, // 42: fsm.v1.Command.member_set:type_name -> fsm.v1.MemberSetCommand
+	36, // 43: fsm.v1.Command.member_remove:type_name -> fsm.v1.MemberRemoveCommand
+	42, // 44: fsm.v1.Command.origin:type_name -> fsm.v1.Origin
+	43, // 45: fsm.v1.CdcAckCommand.cursor:type_name -> fsm.v1.CdcCursor
+	41, // 46: fsm.v1.CdcChange.command:type_name -> fsm.v1.Command
+	52, // 47: fsm.v1.SnapshotState.items:type_name -> fsm.v1.SnapshotState.ItemsEntry
+	2,  // 48: fsm.v1.SnapshotEntry.kind:type_name -> fsm.v1.ValueKind
+	53, // 49: fsm.v1.SnapshotEntry.hash:type_name -> fsm.v1.SnapshotEntry.HashEntry
+	19, // 50: fsm.v1.SnapshotEntry.zset:type_name -> fsm.v1.ScoredMember
+	49, // 51: fsm.v1.SnapshotChunk.entries:type_name -> fsm.v1.SnapshotEntry
+	34, // 52: fsm.v1.SnapshotChunk.membership:type_name -> fsm.v1.MembershipCommand
+	26, // 53: fsm.v1.SnapshotChunk.leases:type_name -> fsm.v1.Lease
+	37, // 54: fsm.v1.SnapshotChunk.namespaces:type_name -> fsm.v1.Namespace
+	43, // 55: fsm.v1.SnapshotChunk.cdc_cursors:type_name -> fsm.v1.CdcCursor
+	46, // 56: fsm.v1.SnapshotChunk.cdc_changes:type_name -> fsm.v1.CdcChange
+	57, // [57:57] is the sub-list for method output_type
+	57, // [57:57] is the sub-list for method input_type
+	57, // [57:57] is the sub-list for extension type_name
+	57, // [57:57] is the sub-list for extension extendee
+	0,  // [0:57] is the sub-list for field type_name
 }
 
 func init() { file_commands_proto_init() }
@@ -301,22 +4071,57 @@ func file_commands_proto_init() {
 	if File_commands_proto != nil {
 		return
 	}
-	file_commands_proto_msgTypes[2].OneofWrappers = []any{
+	file_commands_proto_msgTypes[38].OneofWrappers = []any{
 		(*Command_Put)(nil),
 		(*Command_Delete)(nil),
+		(*Command_Batch)(nil),
+		(*Command_Sealed)(nil),
+		(*Command_Membership)(nil),
+		(*Command_Set)(nil),
+		(*Command_Incr)(nil),
+		(*Command_Expire)(nil),
+		(*Command_Reap)(nil),
+		(*Command_Mset)(nil),
+		(*Command_Del)(nil),
+		(*Command_Counter)(nil),
+		(*Command_Flush)(nil),
+		(*Command_Hset)(nil),
+		(*Command_Hdel)(nil),
+		(*Command_Hincrby)(nil),
+		(*Command_Push)(nil),
+		(*Command_Pop)(nil),
+		(*Command_Zadd)(nil),
+		(*Command_Zrem)(nil),
+		(*Command_Zincrby)(nil),
+		(*Command_Zpopmin)(nil),
+		(*Command_JsonPatch)(nil),
+		(*Command_LeaseGrant)(nil),
+		(*Command_LeaseKeepAlive)(nil),
+		(*Command_LeaseRevoke)(nil),
+		(*Command_LeaseAttach)(nil),
+		(*Command_Lock)(nil),
+		(*Command_Unlock)(nil),
+		(*Command_Tick)(nil),
+		(*Command_NamespaceCreate)(nil),
+		(*Command_NamespaceDelete)(nil),
+		(*Command_CdcAck)(nil),
+		(*Command_CdcSinks)(nil),
+		(*Command_MemberSet)(nil),
+		(*Command_MemberRemove)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_commands_proto_rawDesc), len(file_commands_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      3,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_commands_proto_goTypes,
 		DependencyIndexes: file_commands_proto_depIdxs,
+		EnumInfos:         file_commands_proto_enumTypes,
 		MessageInfos:      file_commands_proto_msgTypes,
 	}.Build()
 	File_commands_proto = out.File
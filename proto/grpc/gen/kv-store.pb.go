@@ -21,6 +21,54 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PatchType int32
+
+const (
+	// RFC 6902 JSON Patch.
+	PatchType_PATCH_TYPE_JSON_PATCH PatchType = 0
+	// RFC 7386 JSON Merge Patch.
+	PatchType_PATCH_TYPE_MERGE_PATCH PatchType = 1
+)
+
+// Enum value maps for PatchType.
+var (
+	PatchType_name = map[int32]string{
+		0: "PATCH_TYPE_JSON_PATCH",
+		1: "PATCH_TYPE_MERGE_PATCH",
+	}
+	PatchType_value = map[string]int32{
+		"PATCH_TYPE_JSON_PATCH":  0,
+		"PATCH_TYPE_MERGE_PATCH": 1,
+	}
+)
+
+func (x PatchType) Enum() *PatchType {
+	p := new(PatchType)
+	*p = x
+	return p
+}
+
+func (x PatchType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PatchType) Descriptor() protoreflect.EnumDescriptor {
+	return file_kv_store_proto_enumTypes[0].Descriptor()
+}
+
+func (PatchType) Type() protoreflect.EnumType {
+	return &file_kv_store_proto_enumTypes[0]
+}
+
+func (x PatchType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PatchType.Descriptor instead.
+func (PatchType) EnumDescriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{0}
+}
+
 type Entry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -73,9 +121,12 @@ func (x *Entry) GetValue() string {
 	return ""
 }
 
+// Requests addressing keys carry a namespace of the keys, empty for the default namespace.
+// Unknown namespaces are reported with NOT_FOUND.
 type GetReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -117,6 +168,13 @@ func (x *GetReq) GetKey() string {
 	return ""
 }
 
+func (x *GetReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 type GetResp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Entry         *Entry                 `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
@@ -164,6 +222,7 @@ func (x *GetResp) GetEntry() *Entry {
 type DeleteReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -205,6 +264,13 @@ func (x *DeleteReq) GetKey() string {
 	return ""
 }
 
+func (x *DeleteReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 type DeleteResp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -245,6 +311,7 @@ type PutReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -293,6 +360,13 @@ func (x *PutReq) GetValue() string {
 	return ""
 }
 
+func (x *PutReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 type PutResp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -329,30 +403,2824 @@ func (*PutResp) Descriptor() ([]byte, []int) {
 	return file_kv_store_proto_rawDescGZIP(), []int{6}
 }
 
-var File_kv_store_proto protoreflect.FileDescriptor
+type HSetReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_kv_store_proto_rawDesc = "" +
-	"\n" +
-	"\x0ekv-store.proto\x12\vkv_store_v1\"/\n" +
-	"\x05Entry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"\x1a\n" +
-	"\x06GetReq\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"3\n" +
-	"\aGetResp\x12(\n" +
-	"\x05entry\x18\x01 \x01(\v2\x12.kv_store_v1.EntryR\x05entry\"\x1d\n" +
-	"\tDeleteReq\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"\f\n" +
-	"\n" +
-	"DeleteResp\"0\n" +
-	"\x06PutReq\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"\t\n" +
-	"\aPutResp2\xa8\x01\n" +
-	"\aKVStore\x120\n" +
-	"\x03Get\x12\x13.kv_store_v1.GetReq\x1a\x14.kv_store_v1.GetResp\x120\n" +
-	"\x03Put\x12\x13.kv_store_v1.PutReq\x1a\x14.kv_store_v1.PutResp\x129\n" +
-	"\x06Delete\x12\x16.kv_store_v1.DeleteReq\x1a\x17.kv_store_v1.DeleteRespB2Z0github.com/shrtyk/kv-store/proto/gen;kv_store_v1b\x06proto3"
+func (x *HSetReq) Reset() {
+	*x = HSetReq{}
+	mi := &file_kv_store_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HSetReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HSetReq) ProtoMessage() {}
+
+func (x *HSetReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HSetReq.ProtoReflect.Descriptor instead.
+func (*HSetReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HSetReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HSetReq) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *HSetReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type HSetResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Added         int64                  `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HSetResp) Reset() {
+	*x = HSetResp{}
+	mi := &file_kv_store_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HSetResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HSetResp) ProtoMessage() {}
+
+func (x *HSetResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HSetResp.ProtoReflect.Descriptor instead.
+func (*HSetResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HSetResp) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+type HGetReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetReq) Reset() {
+	*x = HGetReq{}
+	mi := &file_kv_store_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetReq) ProtoMessage() {}
+
+func (x *HGetReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetReq.ProtoReflect.Descriptor instead.
+func (*HGetReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HGetReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HGetReq) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *HGetReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type HGetResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetResp) Reset() {
+	*x = HGetResp{}
+	mi := &file_kv_store_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetResp) ProtoMessage() {}
+
+func (x *HGetResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetResp.ProtoReflect.Descriptor instead.
+func (*HGetResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HGetResp) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type HDelReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        []string               `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HDelReq) Reset() {
+	*x = HDelReq{}
+	mi := &file_kv_store_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HDelReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HDelReq) ProtoMessage() {}
+
+func (x *HDelReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HDelReq.ProtoReflect.Descriptor instead.
+func (*HDelReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HDelReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HDelReq) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *HDelReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type HDelResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       int64                  `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HDelResp) Reset() {
+	*x = HDelResp{}
+	mi := &file_kv_store_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HDelResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HDelResp) ProtoMessage() {}
+
+func (x *HDelResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HDelResp.ProtoReflect.Descriptor instead.
+func (*HDelResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *HDelResp) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+type HGetAllReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetAllReq) Reset() {
+	*x = HGetAllReq{}
+	mi := &file_kv_store_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetAllReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetAllReq) ProtoMessage() {}
+
+func (x *HGetAllReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetAllReq.ProtoReflect.Descriptor instead.
+func (*HGetAllReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HGetAllReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HGetAllReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type HGetAllResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fields        map[string]string      `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetAllResp) Reset() {
+	*x = HGetAllResp{}
+	mi := &file_kv_store_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetAllResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetAllResp) ProtoMessage() {}
+
+func (x *HGetAllResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetAllResp.ProtoReflect.Descriptor instead.
+func (*HGetAllResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HGetAllResp) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type HIncrByReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Delta         int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Namespace     string                 `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HIncrByReq) Reset() {
+	*x = HIncrByReq{}
+	mi := &file_kv_store_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HIncrByReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HIncrByReq) ProtoMessage() {}
+
+func (x *HIncrByReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HIncrByReq.ProtoReflect.Descriptor instead.
+func (*HIncrByReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HIncrByReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HIncrByReq) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *HIncrByReq) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *HIncrByReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type HIncrByResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         int64                  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HIncrByResp) Reset() {
+	*x = HIncrByResp{}
+	mi := &file_kv_store_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HIncrByResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HIncrByResp) ProtoMessage() {}
+
+func (x *HIncrByResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HIncrByResp.ProtoReflect.Descriptor instead.
+func (*HIncrByResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HIncrByResp) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type PushReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushReq) Reset() {
+	*x = PushReq{}
+	mi := &file_kv_store_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushReq) ProtoMessage() {}
+
+func (x *PushReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushReq.ProtoReflect.Descriptor instead.
+func (*PushReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PushReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PushReq) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *PushReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type PushResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Length        int64                  `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushResp) Reset() {
+	*x = PushResp{}
+	mi := &file_kv_store_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushResp) ProtoMessage() {}
+
+func (x *PushResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushResp.ProtoReflect.Descriptor instead.
+func (*PushResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PushResp) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type PopReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Maximum number of values to pop, 1 if not set.
+	Count         int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PopReq) Reset() {
+	*x = PopReq{}
+	mi := &file_kv_store_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PopReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PopReq) ProtoMessage() {}
+
+func (x *PopReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PopReq.ProtoReflect.Descriptor instead.
+func (*PopReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PopReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PopReq) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PopReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type PopResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PopResp) Reset() {
+	*x = PopResp{}
+	mi := &file_kv_store_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PopResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PopResp) ProtoMessage() {}
+
+func (x *PopResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PopResp.ProtoReflect.Descriptor instead.
+func (*PopResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PopResp) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type BLPopReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// How long to wait for a value, capped by the store max_pop_wait setting.
+	TimeoutMs     int64  `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	Namespace     string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BLPopReq) Reset() {
+	*x = BLPopReq{}
+	mi := &file_kv_store_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BLPopReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BLPopReq) ProtoMessage() {}
+
+func (x *BLPopReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BLPopReq.ProtoReflect.Descriptor instead.
+func (*BLPopReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BLPopReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *BLPopReq) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *BLPopReq) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *BLPopReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type LRangeReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Start         int64                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	Stop          int64                  `protobuf:"varint,3,opt,name=stop,proto3" json:"stop,omitempty"`
+	Namespace     string                 `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LRangeReq) Reset() {
+	*x = LRangeReq{}
+	mi := &file_kv_store_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LRangeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LRangeReq) ProtoMessage() {}
+
+func (x *LRangeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LRangeReq.ProtoReflect.Descriptor instead.
+func (*LRangeReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *LRangeReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LRangeReq) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *LRangeReq) GetStop() int64 {
+	if x != nil {
+		return x.Stop
+	}
+	return 0
+}
+
+func (x *LRangeReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type LRangeResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LRangeResp) Reset() {
+	*x = LRangeResp{}
+	mi := &file_kv_store_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LRangeResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LRangeResp) ProtoMessage() {}
+
+func (x *LRangeResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LRangeResp.ProtoReflect.Descriptor instead.
+func (*LRangeResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LRangeResp) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type LLenReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LLenReq) Reset() {
+	*x = LLenReq{}
+	mi := &file_kv_store_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LLenReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LLenReq) ProtoMessage() {}
+
+func (x *LLenReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LLenReq.ProtoReflect.Descriptor instead.
+func (*LLenReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LLenReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LLenReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type LLenResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Length        int64                  `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LLenResp) Reset() {
+	*x = LLenResp{}
+	mi := &file_kv_store_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LLenResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LLenResp) ProtoMessage() {}
+
+func (x *LLenResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LLenResp.ProtoReflect.Descriptor instead.
+func (*LLenResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LLenResp) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type ScoredMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        string                 `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoredMember) Reset() {
+	*x = ScoredMember{}
+	mi := &file_kv_store_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoredMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoredMember) ProtoMessage() {}
+
+func (x *ScoredMember) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoredMember.ProtoReflect.Descriptor instead.
+func (*ScoredMember) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ScoredMember) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ScoredMember) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type ZAddReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []*ScoredMember        `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZAddReq) Reset() {
+	*x = ZAddReq{}
+	mi := &file_kv_store_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZAddReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZAddReq) ProtoMessage() {}
+
+func (x *ZAddReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZAddReq.ProtoReflect.Descriptor instead.
+func (*ZAddReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ZAddReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZAddReq) GetMembers() []*ScoredMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ZAddReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ZAddResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Added         int64                  `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZAddResp) Reset() {
+	*x = ZAddResp{}
+	mi := &file_kv_store_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZAddResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZAddResp) ProtoMessage() {}
+
+func (x *ZAddResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZAddResp.ProtoReflect.Descriptor instead.
+func (*ZAddResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ZAddResp) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+type ZRemReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRemReq) Reset() {
+	*x = ZRemReq{}
+	mi := &file_kv_store_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRemReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRemReq) ProtoMessage() {}
+
+func (x *ZRemReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRemReq.ProtoReflect.Descriptor instead.
+func (*ZRemReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ZRemReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZRemReq) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ZRemReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ZRemResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Removed       int64                  `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRemResp) Reset() {
+	*x = ZRemResp{}
+	mi := &file_kv_store_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRemResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRemResp) ProtoMessage() {}
+
+func (x *ZRemResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRemResp.ProtoReflect.Descriptor instead.
+func (*ZRemResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ZRemResp) GetRemoved() int64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+type ZScoreReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Member        string                 `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZScoreReq) Reset() {
+	*x = ZScoreReq{}
+	mi := &file_kv_store_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZScoreReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZScoreReq) ProtoMessage() {}
+
+func (x *ZScoreReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZScoreReq.ProtoReflect.Descriptor instead.
+func (*ZScoreReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ZScoreReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZScoreReq) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ZScoreReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ZScoreResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         float64                `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZScoreResp) Reset() {
+	*x = ZScoreResp{}
+	mi := &file_kv_store_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZScoreResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZScoreResp) ProtoMessage() {}
+
+func (x *ZScoreResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZScoreResp.ProtoReflect.Descriptor instead.
+func (*ZScoreResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ZScoreResp) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type ZRangeReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Start         int64                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	Stop          int64                  `protobuf:"varint,3,opt,name=stop,proto3" json:"stop,omitempty"`
+	Namespace     string                 `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRangeReq) Reset() {
+	*x = ZRangeReq{}
+	mi := &file_kv_store_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRangeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRangeReq) ProtoMessage() {}
+
+func (x *ZRangeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRangeReq.ProtoReflect.Descriptor instead.
+func (*ZRangeReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ZRangeReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZRangeReq) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ZRangeReq) GetStop() int64 {
+	if x != nil {
+		return x.Stop
+	}
+	return 0
+}
+
+func (x *ZRangeReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ZRangeResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*ScoredMember        `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRangeResp) Reset() {
+	*x = ZRangeResp{}
+	mi := &file_kv_store_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRangeResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRangeResp) ProtoMessage() {}
+
+func (x *ZRangeResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRangeResp.ProtoReflect.Descriptor instead.
+func (*ZRangeResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ZRangeResp) GetMembers() []*ScoredMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type ZRangeByScoreReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Min   float64                `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max   float64                `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	// Maximum number of members, unlimited if not set.
+	Limit         int64  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Namespace     string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRangeByScoreReq) Reset() {
+	*x = ZRangeByScoreReq{}
+	mi := &file_kv_store_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRangeByScoreReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRangeByScoreReq) ProtoMessage() {}
+
+func (x *ZRangeByScoreReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRangeByScoreReq.ProtoReflect.Descriptor instead.
+func (*ZRangeByScoreReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ZRangeByScoreReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZRangeByScoreReq) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ZRangeByScoreReq) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *ZRangeByScoreReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ZRangeByScoreReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ZIncrByReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Member        string                 `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
+	Delta         float64                `protobuf:"fixed64,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Namespace     string                 `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZIncrByReq) Reset() {
+	*x = ZIncrByReq{}
+	mi := &file_kv_store_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZIncrByReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZIncrByReq) ProtoMessage() {}
+
+func (x *ZIncrByReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZIncrByReq.ProtoReflect.Descriptor instead.
+func (*ZIncrByReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ZIncrByReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZIncrByReq) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ZIncrByReq) GetDelta() float64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *ZIncrByReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ZIncrByResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         float64                `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZIncrByResp) Reset() {
+	*x = ZIncrByResp{}
+	mi := &file_kv_store_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZIncrByResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZIncrByResp) ProtoMessage() {}
+
+func (x *ZIncrByResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZIncrByResp.ProtoReflect.Descriptor instead.
+func (*ZIncrByResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ZIncrByResp) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type ZPopMinReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Maximum number of members to pop, 1 if not set.
+	Count         int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZPopMinReq) Reset() {
+	*x = ZPopMinReq{}
+	mi := &file_kv_store_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZPopMinReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZPopMinReq) ProtoMessage() {}
+
+func (x *ZPopMinReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZPopMinReq.ProtoReflect.Descriptor instead.
+func (*ZPopMinReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ZPopMinReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZPopMinReq) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ZPopMinReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type JsonPatchReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Patch         []byte                 `protobuf:"bytes,2,opt,name=patch,proto3" json:"patch,omitempty"`
+	Type          PatchType              `protobuf:"varint,3,opt,name=type,proto3,enum=kv_store_v1.PatchType" json:"type,omitempty"`
+	Namespace     string                 `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonPatchReq) Reset() {
+	*x = JsonPatchReq{}
+	mi := &file_kv_store_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonPatchReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonPatchReq) ProtoMessage() {}
+
+func (x *JsonPatchReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonPatchReq.ProtoReflect.Descriptor instead.
+func (*JsonPatchReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *JsonPatchReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *JsonPatchReq) GetPatch() []byte {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+func (x *JsonPatchReq) GetType() PatchType {
+	if x != nil {
+		return x.Type
+	}
+	return PatchType_PATCH_TYPE_JSON_PATCH
+}
+
+func (x *JsonPatchReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type JsonPatchResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Document      []byte                 `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonPatchResp) Reset() {
+	*x = JsonPatchResp{}
+	mi := &file_kv_store_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonPatchResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonPatchResp) ProtoMessage() {}
+
+func (x *JsonPatchResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonPatchResp.ProtoReflect.Descriptor instead.
+func (*JsonPatchResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *JsonPatchResp) GetDocument() []byte {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+type JsonGetReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// JSON path like $.a.b[0], the whole document if not set.
+	Path          string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonGetReq) Reset() {
+	*x = JsonGetReq{}
+	mi := &file_kv_store_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonGetReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonGetReq) ProtoMessage() {}
+
+func (x *JsonGetReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonGetReq.ProtoReflect.Descriptor instead.
+func (*JsonGetReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *JsonGetReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *JsonGetReq) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *JsonGetReq) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type JsonGetResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Document      []byte                 `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonGetResp) Reset() {
+	*x = JsonGetResp{}
+	mi := &file_kv_store_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonGetResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonGetResp) ProtoMessage() {}
+
+func (x *JsonGetResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonGetResp.ProtoReflect.Descriptor instead.
+func (*JsonGetResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *JsonGetResp) GetDocument() []byte {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+type LeaseGrantReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TtlMs         int64                  `protobuf:"varint,1,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseGrantReq) Reset() {
+	*x = LeaseGrantReq{}
+	mi := &file_kv_store_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseGrantReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseGrantReq) ProtoMessage() {}
+
+func (x *LeaseGrantReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseGrantReq.ProtoReflect.Descriptor instead.
+func (*LeaseGrantReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LeaseGrantReq) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type LeaseGrantResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseGrantResp) Reset() {
+	*x = LeaseGrantResp{}
+	mi := &file_kv_store_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseGrantResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseGrantResp) ProtoMessage() {}
+
+func (x *LeaseGrantResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseGrantResp.ProtoReflect.Descriptor instead.
+func (*LeaseGrantResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *LeaseGrantResp) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type LeaseKeepAliveReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseKeepAliveReq) Reset() {
+	*x = LeaseKeepAliveReq{}
+	mi := &file_kv_store_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseKeepAliveReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseKeepAliveReq) ProtoMessage() {}
+
+func (x *LeaseKeepAliveReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseKeepAliveReq.ProtoReflect.Descriptor instead.
+func (*LeaseKeepAliveReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *LeaseKeepAliveReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type LeaseKeepAliveResp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// New deadline of the lease in unix milliseconds.
+	ExpiresAt     int64 `protobuf:"varint,1,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseKeepAliveResp) Reset() {
+	*x = LeaseKeepAliveResp{}
+	mi := &file_kv_store_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseKeepAliveResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseKeepAliveResp) ProtoMessage() {}
+
+func (x *LeaseKeepAliveResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseKeepAliveResp.ProtoReflect.Descriptor instead.
+func (*LeaseKeepAliveResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *LeaseKeepAliveResp) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type LeaseRevokeReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseRevokeReq) Reset() {
+	*x = LeaseRevokeReq{}
+	mi := &file_kv_store_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseRevokeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseRevokeReq) ProtoMessage() {}
+
+func (x *LeaseRevokeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseRevokeReq.ProtoReflect.Descriptor instead.
+func (*LeaseRevokeReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *LeaseRevokeReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type LeaseRevokeResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       int64                  `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseRevokeResp) Reset() {
+	*x = LeaseRevokeResp{}
+	mi := &file_kv_store_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseRevokeResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseRevokeResp) ProtoMessage() {}
+
+func (x *LeaseRevokeResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseRevokeResp.ProtoReflect.Descriptor instead.
+func (*LeaseRevokeResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *LeaseRevokeResp) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+type LeaseTimeToLiveReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseTimeToLiveReq) Reset() {
+	*x = LeaseTimeToLiveReq{}
+	mi := &file_kv_store_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseTimeToLiveReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseTimeToLiveReq) ProtoMessage() {}
+
+func (x *LeaseTimeToLiveReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseTimeToLiveReq.ProtoReflect.Descriptor instead.
+func (*LeaseTimeToLiveReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *LeaseTimeToLiveReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type LeaseTimeToLiveResp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	TtlMs int64                  `protobuf:"varint,1,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	// Deadline of the lease in unix milliseconds.
+	ExpiresAt     int64    `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Keys          []string `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseTimeToLiveResp) Reset() {
+	*x = LeaseTimeToLiveResp{}
+	mi := &file_kv_store_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseTimeToLiveResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseTimeToLiveResp) ProtoMessage() {}
+
+func (x *LeaseTimeToLiveResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseTimeToLiveResp.ProtoReflect.Descriptor instead.
+func (*LeaseTimeToLiveResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *LeaseTimeToLiveResp) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *LeaseTimeToLiveResp) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *LeaseTimeToLiveResp) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// Leases and locks exist in the default namespace only, so requests addressing keys of leases carry no namespace.
+type LeaseAttachReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseAttachReq) Reset() {
+	*x = LeaseAttachReq{}
+	mi := &file_kv_store_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseAttachReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseAttachReq) ProtoMessage() {}
+
+func (x *LeaseAttachReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseAttachReq.ProtoReflect.Descriptor instead.
+func (*LeaseAttachReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *LeaseAttachReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LeaseAttachReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type LeaseAttachResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseAttachResp) Reset() {
+	*x = LeaseAttachResp{}
+	mi := &file_kv_store_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseAttachResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseAttachResp) ProtoMessage() {}
+
+func (x *LeaseAttachResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseAttachResp.ProtoReflect.Descriptor instead.
+func (*LeaseAttachResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{52}
+}
+
+type LockReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Lease         int64                  `protobuf:"varint,2,opt,name=lease,proto3" json:"lease,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockReq) Reset() {
+	*x = LockReq{}
+	mi := &file_kv_store_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockReq) ProtoMessage() {}
+
+func (x *LockReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockReq.ProtoReflect.Descriptor instead.
+func (*LockReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *LockReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LockReq) GetLease() int64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+type LockResp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Fencing token, a unique id given when the lock was acquired. It grows with every new holder.
+	Token         int64 `protobuf:"varint,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockResp) Reset() {
+	*x = LockResp{}
+	mi := &file_kv_store_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockResp) ProtoMessage() {}
+
+func (x *LockResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockResp.ProtoReflect.Descriptor instead.
+func (*LockResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *LockResp) GetToken() int64 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+type UnlockReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Lease         int64                  `protobuf:"varint,2,opt,name=lease,proto3" json:"lease,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockReq) Reset() {
+	*x = UnlockReq{}
+	mi := &file_kv_store_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockReq) ProtoMessage() {}
+
+func (x *UnlockReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockReq.ProtoReflect.Descriptor instead.
+func (*UnlockReq) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *UnlockReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *UnlockReq) GetLease() int64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+type UnlockResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockResp) Reset() {
+	*x = UnlockResp{}
+	mi := &file_kv_store_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockResp) ProtoMessage() {}
+
+func (x *UnlockResp) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_store_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockResp.ProtoReflect.Descriptor instead.
+func (*UnlockResp) Descriptor() ([]byte, []int) {
+	return file_kv_store_proto_rawDescGZIP(), []int{56}
+}
+
+var File_kv_store_proto protoreflect.FileDescriptor
+
+const file_kv_store_proto_rawDesc = "" +
+	"\n" +
+	"\x0ekv-store.proto\x12\vkv_store_v1\"/\n" +
+	"\x05Entry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"8\n" +
+	"\x06GetReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"3\n" +
+	"\aGetResp\x12(\n" +
+	"\x05entry\x18\x01 \x01(\v2\x12.kv_store_v1.EntryR\x05entry\";\n" +
+	"\tDeleteReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"\f\n" +
+	"\n" +
+	"DeleteResp\"N\n" +
+	"\x06PutReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\t\n" +
+	"\aPutResp\"\xae\x01\n" +
+	"\aHSetReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x128\n" +
+	"\x06fields\x18\x02 \x03(\v2 .kv_store_v1.HSetReq.FieldsEntryR\x06fields\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\" \n" +
+	"\bHSetResp\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\x03R\x05added\"O\n" +
+	"\aHGetReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\" \n" +
+	"\bHGetResp\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\"Q\n" +
+	"\aHDelReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06fields\x18\x02 \x03(\tR\x06fields\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"$\n" +
+	"\bHDelResp\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x03R\adeleted\"<\n" +
+	"\n" +
+	"HGetAllReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"\x86\x01\n" +
+	"\vHGetAllResp\x12<\n" +
+	"\x06fields\x18\x01 \x03(\v2$.kv_store_v1.HGetAllResp.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"h\n" +
+	"\n" +
+	"HIncrByReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x03R\x05delta\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"#\n" +
+	"\vHIncrByResp\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x03R\x05value\"Q\n" +
+	"\aPushReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\"\n" +
+	"\bPushResp\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x03R\x06length\"N\n" +
+	"\x06PopReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"!\n" +
+	"\aPopResp\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"o\n" +
+	"\bBLPopReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x03 \x01(\x03R\ttimeoutMs\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"e\n" +
+	"\tLRangeReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x12\n" +
+	"\x04stop\x18\x03 \x01(\x03R\x04stop\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"$\n" +
+	"\n" +
+	"LRangeResp\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"9\n" +
+	"\aLLenReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"\"\n" +
+	"\bLLenResp\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x03R\x06length\"<\n" +
+	"\fScoredMember\x12\x16\n" +
+	"\x06member\x18\x01 \x01(\tR\x06member\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"n\n" +
+	"\aZAddReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x123\n" +
+	"\amembers\x18\x02 \x03(\v2\x19.kv_store_v1.ScoredMemberR\amembers\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\" \n" +
+	"\bZAddResp\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\x03R\x05added\"S\n" +
+	"\aZRemReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"$\n" +
+	"\bZRemResp\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\x03R\aremoved\"S\n" +
+	"\tZScoreReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06member\x18\x02 \x01(\tR\x06member\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\"\n" +
+	"\n" +
+	"ZScoreResp\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x01R\x05score\"e\n" +
+	"\tZRangeReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x12\n" +
+	"\x04stop\x18\x03 \x01(\x03R\x04stop\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"A\n" +
+	"\n" +
+	"ZRangeResp\x123\n" +
+	"\amembers\x18\x01 \x03(\v2\x19.kv_store_v1.ScoredMemberR\amembers\"|\n" +
+	"\x10ZRangeByScoreReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x10\n" +
+	"\x03min\x18\x02 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x01R\x03max\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x03R\x05limit\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\"j\n" +
+	"\n" +
+	"ZIncrByReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06member\x18\x02 \x01(\tR\x06member\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x01R\x05delta\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"#\n" +
+	"\vZIncrByResp\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x01R\x05score\"R\n" +
+	"\n" +
+	"ZPopMinReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\x80\x01\n" +
+	"\fJsonPatchReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05patch\x18\x02 \x01(\fR\x05patch\x12*\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x16.kv_store_v1.PatchTypeR\x04type\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"+\n" +
+	"\rJsonPatchResp\x12\x1a\n" +
+	"\bdocument\x18\x01 \x01(\fR\bdocument\"P\n" +
+	"\n" +
+	"JsonGetReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\")\n" +
+	"\vJsonGetResp\x12\x1a\n" +
+	"\bdocument\x18\x01 \x01(\fR\bdocument\"&\n" +
+	"\rLeaseGrantReq\x12\x15\n" +
+	"\x06ttl_ms\x18\x01 \x01(\x03R\x05ttlMs\" \n" +
+	"\x0eLeaseGrantResp\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"#\n" +
+	"\x11LeaseKeepAliveReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"3\n" +
+	"\x12LeaseKeepAliveResp\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x01 \x01(\x03R\texpiresAt\" \n" +
+	"\x0eLeaseRevokeReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"+\n" +
+	"\x0fLeaseRevokeResp\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x03R\adeleted\"$\n" +
+	"\x12LeaseTimeToLiveReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"_\n" +
+	"\x13LeaseTimeToLiveResp\x12\x15\n" +
+	"\x06ttl_ms\x18\x01 \x01(\x03R\x05ttlMs\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\x12\x12\n" +
+	"\x04keys\x18\x03 \x03(\tR\x04keys\"2\n" +
+	"\x0eLeaseAttachReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"\x11\n" +
+	"\x0fLeaseAttachResp\"1\n" +
+	"\aLockReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05lease\x18\x02 \x01(\x03R\x05lease\" \n" +
+	"\bLockResp\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\x03R\x05token\"3\n" +
+	"\tUnlockReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05lease\x18\x02 \x01(\x03R\x05lease\"\f\n" +
+	"\n" +
+	"UnlockResp*B\n" +
+	"\tPatchType\x12\x19\n" +
+	"\x15PATCH_TYPE_JSON_PATCH\x10\x00\x12\x1a\n" +
+	"\x16PATCH_TYPE_MERGE_PATCH\x10\x012\xd5\x0e\n" +
+	"\aKVStore\x120\n" +
+	"\x03Get\x12\x13.kv_store_v1.GetReq\x1a\x14.kv_store_v1.GetResp\x120\n" +
+	"\x03Put\x12\x13.kv_store_v1.PutReq\x1a\x14.kv_store_v1.PutResp\x129\n" +
+	"\x06Delete\x12\x16.kv_store_v1.DeleteReq\x1a\x17.kv_store_v1.DeleteResp\x123\n" +
+	"\x04HSet\x12\x14.kv_store_v1.HSetReq\x1a\x15.kv_store_v1.HSetResp\x123\n" +
+	"\x04HGet\x12\x14.kv_store_v1.HGetReq\x1a\x15.kv_store_v1.HGetResp\x123\n" +
+	"\x04HDel\x12\x14.kv_store_v1.HDelReq\x1a\x15.kv_store_v1.HDelResp\x12<\n" +
+	"\aHGetAll\x12\x17.kv_store_v1.HGetAllReq\x1a\x18.kv_store_v1.HGetAllResp\x12<\n" +
+	"\aHIncrBy\x12\x17.kv_store_v1.HIncrByReq\x1a\x18.kv_store_v1.HIncrByResp\x124\n" +
+	"\x05LPush\x12\x14.kv_store_v1.PushReq\x1a\x15.kv_store_v1.PushResp\x124\n" +
+	"\x05RPush\x12\x14.kv_store_v1.PushReq\x1a\x15.kv_store_v1.PushResp\x121\n" +
+	"\x04LPop\x12\x13.kv_store_v1.PopReq\x1a\x14.kv_store_v1.PopResp\x121\n" +
+	"\x04RPop\x12\x13.kv_store_v1.PopReq\x1a\x14.kv_store_v1.PopResp\x124\n" +
+	"\x05BLPop\x12\x15.kv_store_v1.BLPopReq\x1a\x14.kv_store_v1.PopResp\x129\n" +
+	"\x06LRange\x12\x16.kv_store_v1.LRangeReq\x1a\x17.kv_store_v1.LRangeResp\x123\n" +
+	"\x04LLen\x12\x14.kv_store_v1.LLenReq\x1a\x15.kv_store_v1.LLenResp\x123\n" +
+	"\x04ZAdd\x12\x14.kv_store_v1.ZAddReq\x1a\x15.kv_store_v1.ZAddResp\x123\n" +
+	"\x04ZRem\x12\x14.kv_store_v1.ZRemReq\x1a\x15.kv_store_v1.ZRemResp\x129\n" +
+	"\x06ZScore\x12\x16.kv_store_v1.ZScoreReq\x1a\x17.kv_store_v1.ZScoreResp\x129\n" +
+	"\x06ZRange\x12\x16.kv_store_v1.ZRangeReq\x1a\x17.kv_store_v1.ZRangeResp\x12G\n" +
+	"\rZRangeByScore\x12\x1d.kv_store_v1.ZRangeByScoreReq\x1a\x17.kv_store_v1.ZRangeResp\x12<\n" +
+	"\aZIncrBy\x12\x17.kv_store_v1.ZIncrByReq\x1a\x18.kv_store_v1.ZIncrByResp\x12;\n" +
+	"\aZPopMin\x12\x17.kv_store_v1.ZPopMinReq\x1a\x17.kv_store_v1.ZRangeResp\x12B\n" +
+	"\tJsonPatch\x12\x19.kv_store_v1.JsonPatchReq\x1a\x1a.kv_store_v1.JsonPatchResp\x12<\n" +
+	"\aJsonGet\x12\x17.kv_store_v1.JsonGetReq\x1a\x18.kv_store_v1.JsonGetResp\x12E\n" +
+	"\n" +
+	"LeaseGrant\x12\x1a.kv_store_v1.LeaseGrantReq\x1a\x1b.kv_store_v1.LeaseGrantResp\x12Q\n" +
+	"\x0eLeaseKeepAlive\x12\x1e.kv_store_v1.LeaseKeepAliveReq\x1a\x1f.kv_store_v1.LeaseKeepAliveResp\x12H\n" +
+	"\vLeaseRevoke\x12\x1b.kv_store_v1.LeaseRevokeReq\x1a\x1c.kv_store_v1.LeaseRevokeResp\x12T\n" +
+	"\x0fLeaseTimeToLive\x12\x1f.kv_store_v1.LeaseTimeToLiveReq\x1a .kv_store_v1.LeaseTimeToLiveResp\x12H\n" +
+	"\vLeaseAttach\x12\x1b.kv_store_v1.LeaseAttachReq\x1a\x1c.kv_store_v1.LeaseAttachResp\x123\n" +
+	"\x04Lock\x12\x14.kv_store_v1.LockReq\x1a\x15.kv_store_v1.LockResp\x129\n" +
+	"\x06Unlock\x12\x16.kv_store_v1.UnlockReq\x1a\x17.kv_store_v1.UnlockRespB2Z0github.com/shrtyk/kv-store/proto/gen;kv_store_v1b\x06proto3"
 
 var (
 	file_kv_store_proto_rawDescOnce sync.Once
@@ -366,29 +3234,144 @@ func file_kv_store_proto_rawDescGZIP() []byte {
 	return file_kv_store_proto_rawDescData
 }
 
-var file_kv_store_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_kv_store_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_kv_store_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
 var file_kv_store_proto_goTypes = []any{
-	(*Entry)(nil),      // 0: kv_store_v1.Entry
-	(*GetReq)(nil),     // 1: kv_store_v1.GetReq
-	(*GetResp)(nil),    // 2: kv_store_v1.GetResp
-	(*DeleteReq)(nil),  // 3: kv_store_v1.DeleteReq
-	(*DeleteResp)(nil), // 4: kv_store_v1.DeleteResp
-	(*PutReq)(nil),     // 5: kv_store_v1.PutReq
-	(*PutResp)(nil),    // 6: kv_store_v1.PutResp
+	(PatchType)(0),              // 0: kv_store_v1.PatchType
+	(*Entry)(nil),               // 1: kv_store_v1.Entry
+	(*GetReq)(nil),              // 2: kv_store_v1.GetReq
+	(*GetResp)(nil),             // 3: kv_store_v1.GetResp
+	(*DeleteReq)(nil),           // 4: kv_store_v1.DeleteReq
+	(*DeleteResp)(nil),          // 5: kv_store_v1.DeleteResp
+	(*PutReq)(nil),              // 6: kv_store_v1.PutReq
+	(*PutResp)(nil),             // 7: kv_store_v1.PutResp
+	(*HSetReq)(nil),             // 8: kv_store_v1.HSetReq
+	(*HSetResp)(nil),            // 9: kv_store_v1.HSetResp
+	(*HGetReq)(nil),             // 10: kv_store_v1.HGetReq
+	(*HGetResp)(nil),            // 11: kv_store_v1.HGetResp
+	(*HDelReq)(nil),             // 12: kv_store_v1.HDelReq
+	(*HDelResp)(nil),            // 13: kv_store_v1.HDelResp
+	(*HGetAllReq)(nil),          // 14: kv_store_v1.HGetAllReq
+	(*HGetAllResp)(nil),         // 15: kv_store_v1.HGetAllResp
+	(*HIncrByReq)(nil),          // 16: kv_store_v1.HIncrByReq
+	(*HIncrByResp)(nil),         // 17: kv_store_v1.HIncrByResp
+	(*PushReq)(nil),             // 18: kv_store_v1.PushReq
+	(*PushResp)(nil),            // 19: kv_store_v1.PushResp
+	(*PopReq)(nil),              // 20: kv_store_v1.PopReq
+	(*PopResp)(nil),             // 21: kv_store_v1.PopResp
+	(*BLPopReq)(nil),            // 22: kv_store_v1.BLPopReq
+	(*LRangeReq)(nil),           // 23: kv_store_v1.LRangeReq
+	(*LRangeResp)(nil),          // 24: kv_store_v1.LRangeResp
+	(*LLenReq)(nil),             // 25: kv_store_v1.LLenReq
+	(*LLenResp)(nil),            // 26: kv_store_v1.LLenResp
+	(*ScoredMember)(nil),        // 27: kv_store_v1.ScoredMember
+	(*ZAddReq)(nil),             // 28: kv_store_v1.ZAddReq
+	(*ZAddResp)(nil),            // 29: kv_store_v1.ZAddResp
+	(*ZRemReq)(nil),             // 30: kv_store_v1.ZRemReq
+	(*ZRemResp)(nil),            // 31: kv_store_v1.ZRemResp
+	(*ZScoreReq)(nil),           // 32: kv_store_v1.ZScoreReq
+	(*ZScoreResp)(nil),          // 33: kv_store_v1.ZScoreResp
+	(*ZRangeReq)(nil),           // 34: kv_store_v1.ZRangeReq
+	(*ZRangeResp)(nil),          // 35: kv_store_v1.ZRangeResp
+	(*ZRangeByScoreReq)(nil),    // 36: kv_store_v1.ZRangeByScoreReq
+	(*ZIncrByReq)(nil),          // 37: kv_store_v1.ZIncrByReq
+	(*ZIncrByResp)(nil),         // 38: kv_store_v1.ZIncrByResp
+	(*ZPopMinReq)(nil),          // 39: kv_store_v1.ZPopMinReq
+	(*JsonPatchReq)(nil),        // 40: kv_store_v1.JsonPatchReq
+	(*JsonPatchResp)(nil),       // 41: kv_store_v1.JsonPatchResp
+	(*JsonGetReq)(nil),          // 42: kv_store_v1.JsonGetReq
+	(*JsonGetResp)(nil),         // 43: kv_store_v1.JsonGetResp
+	(*LeaseGrantReq)(nil),       // 44: kv_store_v1.LeaseGrantReq
+	(*LeaseGrantResp)(nil),      // 45: kv_store_v1.LeaseGrantResp
+	(*LeaseKeepAliveReq)(nil),   // 46: kv_store_v1.LeaseKeepAliveReq
+	(*LeaseKeepAliveResp)(nil),  // 47: kv_store_v1.LeaseKeepAliveResp
+	(*LeaseRevokeReq)(nil),      // 48: kv_store_v1.LeaseRevokeReq
+	(*LeaseRevokeResp)(nil),     // 49: kv_store_v1.LeaseRevokeResp
+	(*LeaseTimeToLiveReq)(nil),  // 50: kv_store_v1.LeaseTimeToLiveReq
+	(*LeaseTimeToLiveResp)(nil), // 51: kv_store_v1.LeaseTimeToLiveResp
+	(*LeaseAttachReq)(nil),      // 52: kv_store_v1.LeaseAttachReq
+	(*LeaseAttachResp)(nil),     // 53: kv_store_v1.LeaseAttachResp
+	(*LockReq)(nil),             // 54: kv_store_v1.LockReq
+	(*LockResp)(nil),            // 55: kv_store_v1.LockResp
+	(*UnlockReq)(nil),           // 56: kv_store_v1.UnlockReq
+	(*UnlockResp)(nil),          // 57: kv_store_v1.UnlockResp
+	nil,                         // 58: kv_store_v1.HSetReq.FieldsEntry
+	nil,                         // 59: kv_store_v1.HGetAllResp.FieldsEntry
 }
 var file_kv_store_proto_depIdxs = []int32{
-	0, // 0: kv_store_v1.GetResp.entry:type_name -> kv_store_v1.Entry
-	1, // 1: kv_store_v1.KVStore.Get:input_type -> kv_store_v1.GetReq
-	5, // 2: kv_store_v1.KVStore.Put:input_type -> kv_store_v1.PutReq
-	3, // 3: kv_store_v1.KVStore.Delete:input_type -> kv_store_v1.DeleteReq
-	2, // 4: kv_store_v1.KVStore.Get:output_type -> kv_store_v1.GetResp
-	6, // 5: kv_store_v1.KVStore.Put:output_type -> kv_store_v1.PutResp
-	4, // 6: kv_store_v1.KVStore.Delete:output_type -> kv_store_v1.DeleteResp
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1,  // 0: kv_store_v1.GetResp.entry:type_name -> kv_store_v1.Entry
+	58, // 1: kv_store_v1.HSetReq.fields:type_name -> kv_store_v1.HSetReq.FieldsEntry
+	59, // 2: kv_store_v1.HGetAllResp.fields:type_name -> kv_store_v1.HGetAllResp.FieldsEntry
+	27, // 3: kv_store_v1.ZAddReq.members:type_name -> kv_store_v1.ScoredMember
+	27, // 4: kv_store_v1.ZRangeResp.members:type_name -> kv_store_v1.ScoredMember
+	0,  // 5: kv_store_v1.JsonPatchReq.type:type_name -> kv_store_v1.PatchType
+	2,  // 6: kv_store_v1.KVStore.Get:input_type -> kv_store_v1.GetReq
+	6,  // 7: kv_store_v1.KVStore.Put:input_type -> kv_store_v1.PutReq
+	4,  // 8: kv_store_v1.KVStore.Delete:input_type -> kv_store_v1.DeleteReq
+	8,  // 9: kv_store_v1.KVStore.HSet:input_type -> kv_store_v1.HSetReq
+	10, // 10: kv_store_v1.KVStore.HGet:input_type -> kv_store_v1.HGetReq
+	12, // 11: kv_store_v1.KVStore.HDel:input_type -> kv_store_v1.HDelReq
+	14, // 12: kv_store_v1.KVStore.HGetAll:input_type -> kv_store_v1.HGetAllReq
+	16, // 13: kv_store_v1.KVStore.HIncrBy:input_type -> kv_store_v1.HIncrByReq
+	18, // 14: kv_store_v1.KVStore.LPush:input_type -> kv_store_v1.PushReq
+	18, // 15: kv_store_v1.KVStore.RPush:input_type -> kv_store_v1.PushReq
+	20, // 16: kv_store_v1.KVStore.LPop:input_type -> kv_store_v1.PopReq
+	20, // 17: kv_store_v1.KVStore.RPop:input_type -> kv_store_v1.PopReq
+	22, // 18: kv_store_v1.KVStore.BLPop:input_type -> kv_store_v1.BLPopReq
+	23, // 19: kv_store_v1.KVStore.LRange:input_type -> kv_store_v1.LRangeReq
+	25, // 20: kv_store_v1.KVStore.LLen:input_type -> kv_store_v1.LLenReq
+	28, // 21: kv_store_v1.KVStore.ZAdd:input_type -> kv_store_v1.ZAddReq
+	30, // 22: kv_store_v1.KVStore.ZRem:input_type -> kv_store_v1.ZRemReq
+	32, // 23: kv_store_v1.KVStore.ZScore:input_type -> kv_store_v1.ZScoreReq
+	34, // 24: kv_store_v1.KVStore.ZRange:input_type -> kv_store_v1.ZRangeReq
+	36, // 25: kv_store_v1.KVStore.ZRangeByScore:input_type -> kv_store_v1.ZRangeByScoreReq
+	37, // 26: kv_store_v1.KVStore.ZIncrBy:input_type -> kv_store_v1.ZIncrByReq
+	39, // 27: kv_store_v1.KVStore.ZPopMin:input_type -> kv_store_v1.ZPopMinReq
+	40, // 28: kv_store_v1.KVStore.JsonPatch:input_type -> kv_store_v1.JsonPatchReq
+	42, // 29: kv_store_v1.KVStore.JsonGet:input_type -> kv_store_v1.JsonGetReq
+	44, // 30: kv_store_v1.KVStore.LeaseGrant:input_type -> kv_store_v1.LeaseGrantReq
+	46, // 31: kv_store_v1.KVStore.LeaseKeepAlive:input_type -> kv_store_v1.LeaseKeepAliveReq
+	48, // 32: kv_store_v1.KVStore.LeaseRevoke:input_type -> kv_store_v1.LeaseRevokeReq
+	50, // 33: kv_store_v1.KVStore.LeaseTimeToLive:input_type -> kv_store_v1.LeaseTimeToLiveReq
+	52, // 34: kv_store_v1.KVStore.LeaseAttach:input_type -> kv_store_v1.LeaseAttachReq
+	54, // 35: kv_store_v1.KVStore.Lock:input_type -> kv_store_v1.LockReq
+	56, // 36: kv_store_v1.KVStore.Unlock:input_type -> kv_store_v1.UnlockReq
+	3,  // 37: kv_store_v1.KVStore.Get:output_type -> kv_store_v1.GetResp
+	7,  // 38: kv_store_v1.KVStore.Put:output_type -> kv_store_v1.PutResp
+	5,  // 39: kv_store_v1.KVStore.Delete:output_type -> kv_store_v1.DeleteResp
+	9,  // 40: kv_store_v1.KVStore.HSet:output_type -> kv_store_v1.HSetResp
+	11, // 41: kv_store_v1.KVStore.HGet:output_type -> kv_store_v1.HGetResp
+	13, // 42: kv_store_v1.KVStore.HDel:output_type -> kv_store_v1.HDelResp
+	15, // 43: kv_store_v1.KVStore.HGetAll:output_type -> kv_store_v1.HGetAllResp
+	17, // 44: kv_store_v1.KVStore.HIncrBy:output_type -> kv_store_v1.HIncrByResp
+	19, // 45: kv_store_v1.KVStore.LPush:output_type -> kv_store_v1.PushResp
+	19, // 46: kv_store_v1.KVStore.RPush:output_type -> kv_store_v1.PushResp
+	21, // 47: kv_store_v1.KVStore.LPop:output_type -> kv_store_v1.PopResp
+	21, // 48: kv_store_v1.KVStore.RPop:output_type -> kv_store_v1.PopResp
+	21, // 49: kv_store_v1.KVStore.BLPop:output_type -> kv_store_v1.PopResp
+	24, // 50: kv_store_v1.KVStore.LRange:output_type -> kv_store_v1.LRangeResp
+	26, // 51: kv_store_v1.KVStore.LLen:output_type -> kv_store_v1.LLenResp
+	29, // 52: kv_store_v1.KVStore.ZAdd:output_type -> kv_store_v1.ZAddResp
+	31, // 53: kv_store_v1.KVStore.ZRem:output_type -> kv_store_v1.ZRemResp
+	33, // 54: kv_store_v1.KVStore.ZScore:output_type -> kv_store_v1.ZScoreResp
+	35, // 55: kv_store_v1.KVStore.ZRange:output_type -> kv_store_v1.ZRangeResp
+	35, // 56: kv_store_v1.KVStore.ZRangeByScore:output_type -> kv_store_v1.ZRangeResp
+	38, // 57: kv_store_v1.KVStore.ZIncrBy:output_type -> kv_store_v1.ZIncrByResp
+	35, // 58: kv_store_v1.KVStore.ZPopMin:output_type -> kv_store_v1.ZRangeResp
+	41, // 59: kv_store_v1.KVStore.JsonPatch:output_type -> kv_store_v1.JsonPatchResp
+	43, // 60: kv_store_v1.KVStore.JsonGet:output_type -> kv_store_v1.JsonGetResp
+	45, // 61: kv_store_v1.KVStore.LeaseGrant:output_type -> kv_store_v1.LeaseGrantResp
+	47, // 62: kv_store_v1.KVStore.LeaseKeepAlive:output_type -> kv_store_v1.LeaseKeepAliveResp
+	49, // 63: kv_store_v1.KVStore.LeaseRevoke:output_type -> kv_store_v1.LeaseRevokeResp
+	51, // 64: kv_store_v1.KVStore.LeaseTimeToLive:output_type -> kv_store_v1.LeaseTimeToLiveResp
+	53, // 65: kv_store_v1.KVStore.LeaseAttach:output_type -> kv_store_v1.LeaseAttachResp
+	55, // 66: kv_store_v1.KVStore.Lock:output_type -> kv_store_v1.LockResp
+	57, // 67: kv_store_v1.KVStore.Unlock:output_type -> kv_store_v1.UnlockResp
+	37, // [37:68] is the sub-list for method output_type
+	6,  // [6:37] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_kv_store_proto_init() }
@@ -401,13 +3384,14 @@ func file_kv_store_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kv_store_proto_rawDesc), len(file_kv_store_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      1,
+			NumMessages:   59,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_kv_store_proto_goTypes,
 		DependencyIndexes: file_kv_store_proto_depIdxs,
+		EnumInfos:         file_kv_store_proto_enumTypes,
 		MessageInfos:      file_kv_store_proto_msgTypes,
 	}.Build()
 	File_kv_store_proto = out.File
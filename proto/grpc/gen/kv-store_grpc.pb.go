@@ -19,9 +19,37 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	KVStore_Get_FullMethodName    = "/kv_store_v1.KVStore/Get"
-	KVStore_Put_FullMethodName    = "/kv_store_v1.KVStore/Put"
-	KVStore_Delete_FullMethodName = "/kv_store_v1.KVStore/Delete"
+	KVStore_Get_FullMethodName             = "/kv_store_v1.KVStore/Get"
+	KVStore_Put_FullMethodName             = "/kv_store_v1.KVStore/Put"
+	KVStore_Delete_FullMethodName          = "/kv_store_v1.KVStore/Delete"
+	KVStore_HSet_FullMethodName            = "/kv_store_v1.KVStore/HSet"
+	KVStore_HGet_FullMethodName            = "/kv_store_v1.KVStore/HGet"
+	KVStore_HDel_FullMethodName            = "/kv_store_v1.KVStore/HDel"
+	KVStore_HGetAll_FullMethodName         = "/kv_store_v1.KVStore/HGetAll"
+	KVStore_HIncrBy_FullMethodName         = "/kv_store_v1.KVStore/HIncrBy"
+	KVStore_LPush_FullMethodName           = "/kv_store_v1.KVStore/LPush"
+	KVStore_RPush_FullMethodName           = "/kv_store_v1.KVStore/RPush"
+	KVStore_LPop_FullMethodName            = "/kv_store_v1.KVStore/LPop"
+	KVStore_RPop_FullMethodName            = "/kv_store_v1.KVStore/RPop"
+	KVStore_BLPop_FullMethodName           = "/kv_store_v1.KVStore/BLPop"
+	KVStore_LRange_FullMethodName          = "/kv_store_v1.KVStore/LRange"
+	KVStore_LLen_FullMethodName            = "/kv_store_v1.KVStore/LLen"
+	KVStore_ZAdd_FullMethodName            = "/kv_store_v1.KVStore/ZAdd"
+	KVStore_ZRem_FullMethodName            = "/kv_store_v1.KVStore/ZRem"
+	KVStore_ZScore_FullMethodName          = "/kv_store_v1.KVStore/ZScore"
+	KVStore_ZRange_FullMethodName          = "/kv_store_v1.KVStore/ZRange"
+	KVStore_ZRangeByScore_FullMethodName   = "/kv_store_v1.KVStore/ZRangeByScore"
+	KVStore_ZIncrBy_FullMethodName         = "/kv_store_v1.KVStore/ZIncrBy"
+	KVStore_ZPopMin_FullMethodName         = "/kv_store_v1.KVStore/ZPopMin"
+	KVStore_JsonPatch_FullMethodName       = "/kv_store_v1.KVStore/JsonPatch"
+	KVStore_JsonGet_FullMethodName         = "/kv_store_v1.KVStore/JsonGet"
+	KVStore_LeaseGrant_FullMethodName      = "/kv_store_v1.KVStore/LeaseGrant"
+	KVStore_LeaseKeepAlive_FullMethodName  = "/kv_store_v1.KVStore/LeaseKeepAlive"
+	KVStore_LeaseRevoke_FullMethodName     = "/kv_store_v1.KVStore/LeaseRevoke"
+	KVStore_LeaseTimeToLive_FullMethodName = "/kv_store_v1.KVStore/LeaseTimeToLive"
+	KVStore_LeaseAttach_FullMethodName     = "/kv_store_v1.KVStore/LeaseAttach"
+	KVStore_Lock_FullMethodName            = "/kv_store_v1.KVStore/Lock"
+	KVStore_Unlock_FullMethodName          = "/kv_store_v1.KVStore/Unlock"
 )
 
 // KVStoreClient is the client API for KVStore service.
@@ -31,6 +59,34 @@ type KVStoreClient interface {
 	Get(ctx context.Context, in *GetReq, opts ...grpc.CallOption) (*GetResp, error)
 	Put(ctx context.Context, in *PutReq, opts ...grpc.CallOption) (*PutResp, error)
 	Delete(ctx context.Context, in *DeleteReq, opts ...grpc.CallOption) (*DeleteResp, error)
+	HSet(ctx context.Context, in *HSetReq, opts ...grpc.CallOption) (*HSetResp, error)
+	HGet(ctx context.Context, in *HGetReq, opts ...grpc.CallOption) (*HGetResp, error)
+	HDel(ctx context.Context, in *HDelReq, opts ...grpc.CallOption) (*HDelResp, error)
+	HGetAll(ctx context.Context, in *HGetAllReq, opts ...grpc.CallOption) (*HGetAllResp, error)
+	HIncrBy(ctx context.Context, in *HIncrByReq, opts ...grpc.CallOption) (*HIncrByResp, error)
+	LPush(ctx context.Context, in *PushReq, opts ...grpc.CallOption) (*PushResp, error)
+	RPush(ctx context.Context, in *PushReq, opts ...grpc.CallOption) (*PushResp, error)
+	LPop(ctx context.Context, in *PopReq, opts ...grpc.CallOption) (*PopResp, error)
+	RPop(ctx context.Context, in *PopReq, opts ...grpc.CallOption) (*PopResp, error)
+	BLPop(ctx context.Context, in *BLPopReq, opts ...grpc.CallOption) (*PopResp, error)
+	LRange(ctx context.Context, in *LRangeReq, opts ...grpc.CallOption) (*LRangeResp, error)
+	LLen(ctx context.Context, in *LLenReq, opts ...grpc.CallOption) (*LLenResp, error)
+	ZAdd(ctx context.Context, in *ZAddReq, opts ...grpc.CallOption) (*ZAddResp, error)
+	ZRem(ctx context.Context, in *ZRemReq, opts ...grpc.CallOption) (*ZRemResp, error)
+	ZScore(ctx context.Context, in *ZScoreReq, opts ...grpc.CallOption) (*ZScoreResp, error)
+	ZRange(ctx context.Context, in *ZRangeReq, opts ...grpc.CallOption) (*ZRangeResp, error)
+	ZRangeByScore(ctx context.Context, in *ZRangeByScoreReq, opts ...grpc.CallOption) (*ZRangeResp, error)
+	ZIncrBy(ctx context.Context, in *ZIncrByReq, opts ...grpc.CallOption) (*ZIncrByResp, error)
+	ZPopMin(ctx context.Context, in *ZPopMinReq, opts ...grpc.CallOption) (*ZRangeResp, error)
+	JsonPatch(ctx context.Context, in *JsonPatchReq, opts ...grpc.CallOption) (*JsonPatchResp, error)
+	JsonGet(ctx context.Context, in *JsonGetReq, opts ...grpc.CallOption) (*JsonGetResp, error)
+	LeaseGrant(ctx context.Context, in *LeaseGrantReq, opts ...grpc.CallOption) (*LeaseGrantResp, error)
+	LeaseKeepAlive(ctx context.Context, in *LeaseKeepAliveReq, opts ...grpc.CallOption) (*LeaseKeepAliveResp, error)
+	LeaseRevoke(ctx context.Context, in *LeaseRevokeReq, opts ...grpc.CallOption) (*LeaseRevokeResp, error)
+	LeaseTimeToLive(ctx context.Context, in *LeaseTimeToLiveReq, opts ...grpc.CallOption) (*LeaseTimeToLiveResp, error)
+	LeaseAttach(ctx context.Context, in *LeaseAttachReq, opts ...grpc.CallOption) (*LeaseAttachResp, error)
+	Lock(ctx context.Context, in *LockReq, opts ...grpc.CallOption) (*LockResp, error)
+	Unlock(ctx context.Context, in *UnlockReq, opts ...grpc.CallOption) (*UnlockResp, error)
 }
 
 type kVStoreClient struct {
@@ -71,6 +127,286 @@ func (c *kVStoreClient) Delete(ctx context.Context, in *DeleteReq, opts ...grpc.
 	return out, nil
 }
 
+func (c *kVStoreClient) HSet(ctx context.Context, in *HSetReq, opts ...grpc.CallOption) (*HSetResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HSetResp)
+	err := c.cc.Invoke(ctx, KVStore_HSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) HGet(ctx context.Context, in *HGetReq, opts ...grpc.CallOption) (*HGetResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HGetResp)
+	err := c.cc.Invoke(ctx, KVStore_HGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) HDel(ctx context.Context, in *HDelReq, opts ...grpc.CallOption) (*HDelResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HDelResp)
+	err := c.cc.Invoke(ctx, KVStore_HDel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) HGetAll(ctx context.Context, in *HGetAllReq, opts ...grpc.CallOption) (*HGetAllResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HGetAllResp)
+	err := c.cc.Invoke(ctx, KVStore_HGetAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) HIncrBy(ctx context.Context, in *HIncrByReq, opts ...grpc.CallOption) (*HIncrByResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HIncrByResp)
+	err := c.cc.Invoke(ctx, KVStore_HIncrBy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LPush(ctx context.Context, in *PushReq, opts ...grpc.CallOption) (*PushResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushResp)
+	err := c.cc.Invoke(ctx, KVStore_LPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) RPush(ctx context.Context, in *PushReq, opts ...grpc.CallOption) (*PushResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushResp)
+	err := c.cc.Invoke(ctx, KVStore_RPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LPop(ctx context.Context, in *PopReq, opts ...grpc.CallOption) (*PopResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PopResp)
+	err := c.cc.Invoke(ctx, KVStore_LPop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) RPop(ctx context.Context, in *PopReq, opts ...grpc.CallOption) (*PopResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PopResp)
+	err := c.cc.Invoke(ctx, KVStore_RPop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) BLPop(ctx context.Context, in *BLPopReq, opts ...grpc.CallOption) (*PopResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PopResp)
+	err := c.cc.Invoke(ctx, KVStore_BLPop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LRange(ctx context.Context, in *LRangeReq, opts ...grpc.CallOption) (*LRangeResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LRangeResp)
+	err := c.cc.Invoke(ctx, KVStore_LRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LLen(ctx context.Context, in *LLenReq, opts ...grpc.CallOption) (*LLenResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LLenResp)
+	err := c.cc.Invoke(ctx, KVStore_LLen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) ZAdd(ctx context.Context, in *ZAddReq, opts ...grpc.CallOption) (*ZAddResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZAddResp)
+	err := c.cc.Invoke(ctx, KVStore_ZAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) ZRem(ctx context.Context, in *ZRemReq, opts ...grpc.CallOption) (*ZRemResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRemResp)
+	err := c.cc.Invoke(ctx, KVStore_ZRem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) ZScore(ctx context.Context, in *ZScoreReq, opts ...grpc.CallOption) (*ZScoreResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZScoreResp)
+	err := c.cc.Invoke(ctx, KVStore_ZScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) ZRange(ctx context.Context, in *ZRangeReq, opts ...grpc.CallOption) (*ZRangeResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRangeResp)
+	err := c.cc.Invoke(ctx, KVStore_ZRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) ZRangeByScore(ctx context.Context, in *ZRangeByScoreReq, opts ...grpc.CallOption) (*ZRangeResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRangeResp)
+	err := c.cc.Invoke(ctx, KVStore_ZRangeByScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) ZIncrBy(ctx context.Context, in *ZIncrByReq, opts ...grpc.CallOption) (*ZIncrByResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZIncrByResp)
+	err := c.cc.Invoke(ctx, KVStore_ZIncrBy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) ZPopMin(ctx context.Context, in *ZPopMinReq, opts ...grpc.CallOption) (*ZRangeResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRangeResp)
+	err := c.cc.Invoke(ctx, KVStore_ZPopMin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) JsonPatch(ctx context.Context, in *JsonPatchReq, opts ...grpc.CallOption) (*JsonPatchResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JsonPatchResp)
+	err := c.cc.Invoke(ctx, KVStore_JsonPatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) JsonGet(ctx context.Context, in *JsonGetReq, opts ...grpc.CallOption) (*JsonGetResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JsonGetResp)
+	err := c.cc.Invoke(ctx, KVStore_JsonGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LeaseGrant(ctx context.Context, in *LeaseGrantReq, opts ...grpc.CallOption) (*LeaseGrantResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseGrantResp)
+	err := c.cc.Invoke(ctx, KVStore_LeaseGrant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LeaseKeepAlive(ctx context.Context, in *LeaseKeepAliveReq, opts ...grpc.CallOption) (*LeaseKeepAliveResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseKeepAliveResp)
+	err := c.cc.Invoke(ctx, KVStore_LeaseKeepAlive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LeaseRevoke(ctx context.Context, in *LeaseRevokeReq, opts ...grpc.CallOption) (*LeaseRevokeResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseRevokeResp)
+	err := c.cc.Invoke(ctx, KVStore_LeaseRevoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LeaseTimeToLive(ctx context.Context, in *LeaseTimeToLiveReq, opts ...grpc.CallOption) (*LeaseTimeToLiveResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseTimeToLiveResp)
+	err := c.cc.Invoke(ctx, KVStore_LeaseTimeToLive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) LeaseAttach(ctx context.Context, in *LeaseAttachReq, opts ...grpc.CallOption) (*LeaseAttachResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseAttachResp)
+	err := c.cc.Invoke(ctx, KVStore_LeaseAttach_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) Lock(ctx context.Context, in *LockReq, opts ...grpc.CallOption) (*LockResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LockResp)
+	err := c.cc.Invoke(ctx, KVStore_Lock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVStoreClient) Unlock(ctx context.Context, in *UnlockReq, opts ...grpc.CallOption) (*UnlockResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockResp)
+	err := c.cc.Invoke(ctx, KVStore_Unlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KVStoreServer is the server API for KVStore service.
 // All implementations must embed UnimplementedKVStoreServer
 // for forward compatibility.
@@ -78,6 +414,34 @@ type KVStoreServer interface {
 	Get(context.Context, *GetReq) (*GetResp, error)
 	Put(context.Context, *PutReq) (*PutResp, error)
 	Delete(context.Context, *DeleteReq) (*DeleteResp, error)
+	HSet(context.Context, *HSetReq) (*HSetResp, error)
+	HGet(context.Context, *HGetReq) (*HGetResp, error)
+	HDel(context.Context, *HDelReq) (*HDelResp, error)
+	HGetAll(context.Context, *HGetAllReq) (*HGetAllResp, error)
+	HIncrBy(context.Context, *HIncrByReq) (*HIncrByResp, error)
+	LPush(context.Context, *PushReq) (*PushResp, error)
+	RPush(context.Context, *PushReq) (*PushResp, error)
+	LPop(context.Context, *PopReq) (*PopResp, error)
+	RPop(context.Context, *PopReq) (*PopResp, error)
+	BLPop(context.Context, *BLPopReq) (*PopResp, error)
+	LRange(context.Context, *LRangeReq) (*LRangeResp, error)
+	LLen(context.Context, *LLenReq) (*LLenResp, error)
+	ZAdd(context.Context, *ZAddReq) (*ZAddResp, error)
+	ZRem(context.Context, *ZRemReq) (*ZRemResp, error)
+	ZScore(context.Context, *ZScoreReq) (*ZScoreResp, error)
+	ZRange(context.Context, *ZRangeReq) (*ZRangeResp, error)
+	ZRangeByScore(context.Context, *ZRangeByScoreReq) (*ZRangeResp, error)
+	ZIncrBy(context.Context, *ZIncrByReq) (*ZIncrByResp, error)
+	ZPopMin(context.Context, *ZPopMinReq) (*ZRangeResp, error)
+	JsonPatch(context.Context, *JsonPatchReq) (*JsonPatchResp, error)
+	JsonGet(context.Context, *JsonGetReq) (*JsonGetResp, error)
+	LeaseGrant(context.Context, *LeaseGrantReq) (*LeaseGrantResp, error)
+	LeaseKeepAlive(context.Context, *LeaseKeepAliveReq) (*LeaseKeepAliveResp, error)
+	LeaseRevoke(context.Context, *LeaseRevokeReq) (*LeaseRevokeResp, error)
+	LeaseTimeToLive(context.Context, *LeaseTimeToLiveReq) (*LeaseTimeToLiveResp, error)
+	LeaseAttach(context.Context, *LeaseAttachReq) (*LeaseAttachResp, error)
+	Lock(context.Context, *LockReq) (*LockResp, error)
+	Unlock(context.Context, *UnlockReq) (*UnlockResp, error)
 	mustEmbedUnimplementedKVStoreServer()
 }
 
@@ -97,6 +461,90 @@ func (UnimplementedKVStoreServer) Put(context.Context, *PutReq) (*PutResp, error
 func (UnimplementedKVStoreServer) Delete(context.Context, *DeleteReq) (*DeleteResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedKVStoreServer) HSet(context.Context, *HSetReq) (*HSetResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HSet not implemented")
+}
+func (UnimplementedKVStoreServer) HGet(context.Context, *HGetReq) (*HGetResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HGet not implemented")
+}
+func (UnimplementedKVStoreServer) HDel(context.Context, *HDelReq) (*HDelResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HDel not implemented")
+}
+func (UnimplementedKVStoreServer) HGetAll(context.Context, *HGetAllReq) (*HGetAllResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HGetAll not implemented")
+}
+func (UnimplementedKVStoreServer) HIncrBy(context.Context, *HIncrByReq) (*HIncrByResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HIncrBy not implemented")
+}
+func (UnimplementedKVStoreServer) LPush(context.Context, *PushReq) (*PushResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LPush not implemented")
+}
+func (UnimplementedKVStoreServer) RPush(context.Context, *PushReq) (*PushResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RPush not implemented")
+}
+func (UnimplementedKVStoreServer) LPop(context.Context, *PopReq) (*PopResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LPop not implemented")
+}
+func (UnimplementedKVStoreServer) RPop(context.Context, *PopReq) (*PopResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RPop not implemented")
+}
+func (UnimplementedKVStoreServer) BLPop(context.Context, *BLPopReq) (*PopResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BLPop not implemented")
+}
+func (UnimplementedKVStoreServer) LRange(context.Context, *LRangeReq) (*LRangeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LRange not implemented")
+}
+func (UnimplementedKVStoreServer) LLen(context.Context, *LLenReq) (*LLenResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LLen not implemented")
+}
+func (UnimplementedKVStoreServer) ZAdd(context.Context, *ZAddReq) (*ZAddResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZAdd not implemented")
+}
+func (UnimplementedKVStoreServer) ZRem(context.Context, *ZRemReq) (*ZRemResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZRem not implemented")
+}
+func (UnimplementedKVStoreServer) ZScore(context.Context, *ZScoreReq) (*ZScoreResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZScore not implemented")
+}
+func (UnimplementedKVStoreServer) ZRange(context.Context, *ZRangeReq) (*ZRangeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZRange not implemented")
+}
+func (UnimplementedKVStoreServer) ZRangeByScore(context.Context, *ZRangeByScoreReq) (*ZRangeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZRangeByScore not implemented")
+}
+func (UnimplementedKVStoreServer) ZIncrBy(context.Context, *ZIncrByReq) (*ZIncrByResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZIncrBy not implemented")
+}
+func (UnimplementedKVStoreServer) ZPopMin(context.Context, *ZPopMinReq) (*ZRangeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZPopMin not implemented")
+}
+func (UnimplementedKVStoreServer) JsonPatch(context.Context, *JsonPatchReq) (*JsonPatchResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JsonPatch not implemented")
+}
+func (UnimplementedKVStoreServer) JsonGet(context.Context, *JsonGetReq) (*JsonGetResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JsonGet not implemented")
+}
+func (UnimplementedKVStoreServer) LeaseGrant(context.Context, *LeaseGrantReq) (*LeaseGrantResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseGrant not implemented")
+}
+func (UnimplementedKVStoreServer) LeaseKeepAlive(context.Context, *LeaseKeepAliveReq) (*LeaseKeepAliveResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseKeepAlive not implemented")
+}
+func (UnimplementedKVStoreServer) LeaseRevoke(context.Context, *LeaseRevokeReq) (*LeaseRevokeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseRevoke not implemented")
+}
+func (UnimplementedKVStoreServer) LeaseTimeToLive(context.Context, *LeaseTimeToLiveReq) (*LeaseTimeToLiveResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseTimeToLive not implemented")
+}
+func (UnimplementedKVStoreServer) LeaseAttach(context.Context, *LeaseAttachReq) (*LeaseAttachResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseAttach not implemented")
+}
+func (UnimplementedKVStoreServer) Lock(context.Context, *LockReq) (*LockResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lock not implemented")
+}
+func (UnimplementedKVStoreServer) Unlock(context.Context, *UnlockReq) (*UnlockResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlock not implemented")
+}
 func (UnimplementedKVStoreServer) mustEmbedUnimplementedKVStoreServer() {}
 func (UnimplementedKVStoreServer) testEmbeddedByValue()                 {}
 
@@ -172,24 +620,640 @@ func _KVStore_Delete_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
-// KVStore_ServiceDesc is the grpc.ServiceDesc for KVStore service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var KVStore_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "kv_store_v1.KVStore",
-	HandlerType: (*KVStoreServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Get",
-			Handler:    _KVStore_Get_Handler,
-		},
-		{
-			MethodName: "Put",
-			Handler:    _KVStore_Put_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _KVStore_Delete_Handler,
+func _KVStore_HSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HSetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).HSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_HSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).HSet(ctx, req.(*HSetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_HGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HGetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).HGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_HGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).HGet(ctx, req.(*HGetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_HDel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HDelReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).HDel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_HDel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).HDel(ctx, req.(*HDelReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_HGetAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HGetAllReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).HGetAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_HGetAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).HGetAll(ctx, req.(*HGetAllReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_HIncrBy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HIncrByReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).HIncrBy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_HIncrBy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).HIncrBy(ctx, req.(*HIncrByReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LPush(ctx, req.(*PushReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_RPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).RPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_RPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).RPush(ctx, req.(*PushReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LPop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PopReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LPop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LPop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LPop(ctx, req.(*PopReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_RPop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PopReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).RPop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_RPop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).RPop(ctx, req.(*PopReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_BLPop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BLPopReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).BLPop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_BLPop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).BLPop(ctx, req.(*BLPopReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LRangeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LRange(ctx, req.(*LRangeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LLen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LLenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LLen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LLen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LLen(ctx, req.(*LLenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_ZAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZAddReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).ZAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_ZAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).ZAdd(ctx, req.(*ZAddReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_ZRem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZRemReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).ZRem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_ZRem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).ZRem(ctx, req.(*ZRemReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_ZScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZScoreReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).ZScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_ZScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).ZScore(ctx, req.(*ZScoreReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_ZRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZRangeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).ZRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_ZRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).ZRange(ctx, req.(*ZRangeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_ZRangeByScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZRangeByScoreReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).ZRangeByScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_ZRangeByScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).ZRangeByScore(ctx, req.(*ZRangeByScoreReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_ZIncrBy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZIncrByReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).ZIncrBy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_ZIncrBy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).ZIncrBy(ctx, req.(*ZIncrByReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_ZPopMin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZPopMinReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).ZPopMin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_ZPopMin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).ZPopMin(ctx, req.(*ZPopMinReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_JsonPatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JsonPatchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).JsonPatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_JsonPatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).JsonPatch(ctx, req.(*JsonPatchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_JsonGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JsonGetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).JsonGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_JsonGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).JsonGet(ctx, req.(*JsonGetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LeaseGrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseGrantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LeaseGrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LeaseGrant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LeaseGrant(ctx, req.(*LeaseGrantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LeaseKeepAlive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseKeepAliveReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LeaseKeepAlive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LeaseKeepAlive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LeaseKeepAlive(ctx, req.(*LeaseKeepAliveReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LeaseRevoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseRevokeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LeaseRevoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LeaseRevoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LeaseRevoke(ctx, req.(*LeaseRevokeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LeaseTimeToLive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseTimeToLiveReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LeaseTimeToLive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LeaseTimeToLive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LeaseTimeToLive(ctx, req.(*LeaseTimeToLiveReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_LeaseAttach_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseAttachReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).LeaseAttach(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_LeaseAttach_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).LeaseAttach(ctx, req.(*LeaseAttachReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).Lock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_Lock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).Lock(ctx, req.(*LockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVStore_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVStoreServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVStore_Unlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVStoreServer).Unlock(ctx, req.(*UnlockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KVStore_ServiceDesc is the grpc.ServiceDesc for KVStore service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KVStore_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kv_store_v1.KVStore",
+	HandlerType: (*KVStoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _KVStore_Get_Handler,
+		},
+		{
+			MethodName: "Put",
+			Handler:    _KVStore_Put_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _KVStore_Delete_Handler,
+		},
+		{
+			MethodName: "HSet",
+			Handler:    _KVStore_HSet_Handler,
+		},
+		{
+			MethodName: "HGet",
+			Handler:    _KVStore_HGet_Handler,
+		},
+		{
+			MethodName: "HDel",
+			Handler:    _KVStore_HDel_Handler,
+		},
+		{
+			MethodName: "HGetAll",
+			Handler:    _KVStore_HGetAll_Handler,
+		},
+		{
+			MethodName: "HIncrBy",
+			Handler:    _KVStore_HIncrBy_Handler,
+		},
+		{
+			MethodName: "LPush",
+			Handler:    _KVStore_LPush_Handler,
+		},
+		{
+			MethodName: "RPush",
+			Handler:    _KVStore_RPush_Handler,
+		},
+		{
+			MethodName: "LPop",
+			Handler:    _KVStore_LPop_Handler,
+		},
+		{
+			MethodName: "RPop",
+			Handler:    _KVStore_RPop_Handler,
+		},
+		{
+			MethodName: "BLPop",
+			Handler:    _KVStore_BLPop_Handler,
+		},
+		{
+			MethodName: "LRange",
+			Handler:    _KVStore_LRange_Handler,
+		},
+		{
+			MethodName: "LLen",
+			Handler:    _KVStore_LLen_Handler,
+		},
+		{
+			MethodName: "ZAdd",
+			Handler:    _KVStore_ZAdd_Handler,
+		},
+		{
+			MethodName: "ZRem",
+			Handler:    _KVStore_ZRem_Handler,
+		},
+		{
+			MethodName: "ZScore",
+			Handler:    _KVStore_ZScore_Handler,
+		},
+		{
+			MethodName: "ZRange",
+			Handler:    _KVStore_ZRange_Handler,
+		},
+		{
+			MethodName: "ZRangeByScore",
+			Handler:    _KVStore_ZRangeByScore_Handler,
+		},
+		{
+			MethodName: "ZIncrBy",
+			Handler:    _KVStore_ZIncrBy_Handler,
+		},
+		{
+			MethodName: "ZPopMin",
+			Handler:    _KVStore_ZPopMin_Handler,
+		},
+		{
+			MethodName: "JsonPatch",
+			Handler:    _KVStore_JsonPatch_Handler,
+		},
+		{
+			MethodName: "JsonGet",
+			Handler:    _KVStore_JsonGet_Handler,
+		},
+		{
+			MethodName: "LeaseGrant",
+			Handler:    _KVStore_LeaseGrant_Handler,
+		},
+		{
+			MethodName: "LeaseKeepAlive",
+			Handler:    _KVStore_LeaseKeepAlive_Handler,
+		},
+		{
+			MethodName: "LeaseRevoke",
+			Handler:    _KVStore_LeaseRevoke_Handler,
+		},
+		{
+			MethodName: "LeaseTimeToLive",
+			Handler:    _KVStore_LeaseTimeToLive_Handler,
+		},
+		{
+			MethodName: "LeaseAttach",
+			Handler:    _KVStore_LeaseAttach_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _KVStore_Lock_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _KVStore_Unlock_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},